@@ -195,6 +195,268 @@ type AIGatewayRouteSpec struct {
 	// +optional
 	// +kubebuilder:validation:MaxItems=36
 	LLMRequestCosts []LLMRequestCost `json:"llmRequestCosts,omitempty"`
+
+	// ResponseCache enables caching the responses of the backends in the gateway, so that the same requests are
+	// answered from the cache without calling the backends again.
+	//
+	// This applies to the chat completions, messages and embeddings requests whose model is matched exactly by
+	// the "x-ai-eg-model" header match of a rule. Streaming requests are answered from the cached non-streaming
+	// responses as well, with the stream synthesized from the response. Cached responses are kept in the memory of
+	// each external processor replica.
+	//
+	// +optional
+	ResponseCache *ResponseCache `json:"responseCache,omitempty"`
+
+	// PIIPolicy enables the detection of the personally identifiable information (PII) in the requests before they
+	// are sent to the backends.
+	//
+	// This applies to the chat completions, messages and responses requests whose model is matched exactly by the
+	// "x-ai-eg-model" header match of a rule. The texts of the messages are checked, and the requests with PII are
+	// either rejected, or sent with the PII replaced by stable placeholders such as "[EMAIL_1]".
+	//
+	// +optional
+	PIIPolicy *PIIPolicy `json:"piiPolicy,omitempty"`
+
+	// Guardrail enables checking the prompts, and optionally the completions, with an external guard service: an
+	// HTTP webhook, or a safety classifier model such as Llama Guard served by an AIServiceBackend.
+	//
+	// This applies to the chat completions, messages and responses requests whose model is matched exactly by the
+	// "x-ai-eg-model" header match of a rule. The prompts are checked before the requests are sent to the backends,
+	// after the PII is replaced if the PIIPolicy is set. Depending on the verdict, the requests are rejected with a
+	// 400 error, annotated in the dynamic metadata, or let through.
+	//
+	// +optional
+	Guardrail *Guardrail `json:"guardrail,omitempty"`
+}
+
+// ResponseCache configures the response cache of an AIGatewayRoute.
+type ResponseCache struct {
+	// TTL is how long a cached response is served. Defaults to 1h.
+	//
+	// +optional
+	// +kubebuilder:default="1h"
+	TTL *gwapiv1.Duration `json:"ttl,omitempty"`
+
+	// OwnerHeader is the request header identifying the caller, such as the API key. A cached response is only
+	// served to the requests with the same value of the header as the request it was cached for, so that the
+	// callers never receive the responses to each other's requests. The value is only kept hashed. The requests
+	// without the header share their cached responses. Defaults to "authorization".
+	//
+	// +optional
+	// +kubebuilder:default=authorization
+	// +kubebuilder:validation:MinLength=1
+	OwnerHeader *string `json:"ownerHeader,omitempty"`
+
+	// Semantic enables answering a request with the cached response of a semantically similar request when no
+	// response is cached for the request itself. The similarity is the cosine similarity of the embeddings of the
+	// texts of the requests.
+	//
+	// Only the requests made of text messages without tools are matched semantically.
+	//
+	// +optional
+	Semantic *SemanticResponseCache `json:"semantic,omitempty"`
+}
+
+// SemanticResponseCache configures the semantic matching of the response cache.
+type SemanticResponseCache struct {
+	// BackendRef is the AIServiceBackend serving the embedding model. The embeddings requests are sent to the
+	// backend directly from the external processor with the same schema and BackendSecurityPolicy as the requests
+	// routed to it.
+	BackendRef AIServiceBackendRef `json:"backendRef"`
+
+	// Model is the name of the embedding model.
+	//
+	// +kubebuilder:validation:MinLength=1
+	Model string `json:"model"`
+
+	// SimilarityThreshold is the minimum similarity between 0 and 1 for a cached response to answer a request.
+	// Defaults to "0.95".
+	//
+	// +optional
+	// +kubebuilder:default="0.95"
+	// +kubebuilder:validation:Pattern=`^(0(\.[0-9]+)?|1(\.0+)?)$`
+	SimilarityThreshold *string `json:"similarityThreshold,omitempty"`
+}
+
+// AIServiceBackendRef is a reference to an AIServiceBackend.
+type AIServiceBackendRef struct {
+	// Name is the name of the AIServiceBackend.
+	//
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the AIServiceBackend. Defaults to the namespace of the AIGatewayRoute.
+	//
+	// Like the backendRefs of the rules, a ReferenceGrant in the namespace is required to reference an
+	// AIServiceBackend in a different namespace.
+	//
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+}
+
+// PIIPolicy configures the detection of the PII in the requests of an AIGatewayRoute.
+//
+// +kubebuilder:validation:XValidation:rule="(has(self.entities) && size(self.entities) > 0) || (has(self.customPatterns) && size(self.customPatterns) > 0)", message="at least one entity or custom pattern is required"
+type PIIPolicy struct {
+	// Action is what is done to the requests with PII. Defaults to "Restore".
+	//
+	// * Block rejects the requests with a 400 error naming the kinds of the detected PII.
+	// * Mask replaces the PII with placeholders before the requests are sent to the backends.
+	// * Restore replaces the PII with placeholders the same as Mask, and replaces the placeholders in the responses,
+	//   including the streamed ones, with the original values.
+	//
+	// +optional
+	// +kubebuilder:default=Restore
+	Action PIIPolicyAction `json:"action,omitempty"`
+
+	// Entities is the list of the built-in kinds of PII to detect. Defaults to all of them.
+	//
+	// +optional
+	// +kubebuilder:default={Email,PhoneNumber,CreditCard,NationalID}
+	// +kubebuilder:validation:MaxItems=4
+	Entities []PIIEntity `json:"entities,omitempty"`
+
+	// CustomPatterns is the list of the custom kinds of PII detected with regular expressions.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxItems=16
+	CustomPatterns []PIICustomPattern `json:"customPatterns,omitempty"`
+}
+
+// PIIPolicyAction specifies what is done to the requests with PII.
+//
+// +kubebuilder:validation:Enum=Block;Mask;Restore
+type PIIPolicyAction string
+
+const (
+	// PIIPolicyActionBlock rejects the requests with PII.
+	PIIPolicyActionBlock PIIPolicyAction = "Block"
+	// PIIPolicyActionMask replaces the PII with placeholders.
+	PIIPolicyActionMask PIIPolicyAction = "Mask"
+	// PIIPolicyActionRestore replaces the PII with placeholders, and restores the original values in the responses.
+	PIIPolicyActionRestore PIIPolicyAction = "Restore"
+)
+
+// PIIEntity is a built-in kind of PII.
+//
+// +kubebuilder:validation:Enum=Email;PhoneNumber;CreditCard;NationalID
+type PIIEntity string
+
+const (
+	// PIIEntityEmail is an email address.
+	PIIEntityEmail PIIEntity = "Email"
+	// PIIEntityPhoneNumber is an international phone number starting with "+", or a North American phone number
+	// with separators such as "(415) 555-2671".
+	PIIEntityPhoneNumber PIIEntity = "PhoneNumber"
+	// PIIEntityCreditCard is a payment card number passing the Luhn check.
+	PIIEntityCreditCard PIIEntity = "CreditCard"
+	// PIIEntityNationalID is a US social security number.
+	PIIEntityNationalID PIIEntity = "NationalID"
+)
+
+// PIICustomPattern is a custom kind of PII detected with a regular expression.
+type PIICustomPattern struct {
+	// Name is the name of the kind of PII used in the placeholders, e.g. "EMPLOYEE_ID" for "[EMPLOYEE_ID_1]".
+	//
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[A-Z][A-Z0-9_]*$`
+	Name string `json:"name"`
+
+	// Regex is the RE2 regular expression matching the PII, e.g. "EMP-[0-9]{6}".
+	//
+	// +kubebuilder:validation:MinLength=1
+	Regex string `json:"regex"`
+}
+
+// Guardrail configures the external guard service of an AIGatewayRoute.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.webhook) != has(self.llm)", message="exactly one of webhook or llm must be set"
+type Guardrail struct {
+	// Webhook is the HTTP service returning the verdicts on the prompts and the completions.
+	//
+	// The service receives POST requests with the JSON body {"stage", "route", "model", "messages", "completion"},
+	// where the stage is "prompt" or "completion" and the messages are the {"role", "content"} texts of the prompt.
+	// It returns the JSON verdict {"action", "reason", "metadata"}, where the action is "pass", "block" or
+	// "annotate", the reason is returned to the clients of the blocked requests, and the reason and the metadata are
+	// added to the dynamic metadata of the annotated requests.
+	//
+	// +optional
+	Webhook *GuardrailWebhook `json:"webhook,omitempty"`
+
+	// LLM is the safety classifier model, such as Llama Guard, answering "safe", or "unsafe" followed by a line with
+	// the violated categories.
+	//
+	// +optional
+	LLM *GuardrailLLM `json:"llm,omitempty"`
+
+	// Completion enables checking the completions before they are returned to the clients. The completions are not
+	// checked if this is not set.
+	//
+	// +optional
+	Completion *GuardrailCompletion `json:"completion,omitempty"`
+
+	// Timeout is the timeout of each check. Defaults to 5s.
+	//
+	// +optional
+	// +kubebuilder:default="5s"
+	Timeout *gwapiv1.Duration `json:"timeout,omitempty"`
+
+	// FailOpen lets the requests through when the guard service fails or times out. By default, they are rejected.
+	//
+	// +optional
+	FailOpen bool `json:"failOpen,omitempty"`
+}
+
+// GuardrailWebhook configures the HTTP webhook of the guardrail.
+type GuardrailWebhook struct {
+	// URL is the URL the checks are posted to, e.g. "http://guard.default.svc.cluster.local:8080/check".
+	//
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+}
+
+// GuardrailLLM configures the safety classifier model of the guardrail.
+type GuardrailLLM struct {
+	// BackendRef is the AIServiceBackend serving the classifier model. The chat completions requests are sent to the
+	// backend directly from the external processor with the same schema and BackendSecurityPolicy as the requests
+	// routed to it.
+	BackendRef AIServiceBackendRef `json:"backendRef"`
+
+	// Model is the name of the classifier model, e.g. "meta-llama/Llama-Guard-3-8B".
+	//
+	// +kubebuilder:validation:MinLength=1
+	Model string `json:"model"`
+
+	// UnsafeAction is what is done to the requests classified as unsafe. Defaults to "Block".
+	//
+	// +optional
+	// +kubebuilder:default=Block
+	UnsafeAction GuardrailUnsafeAction `json:"unsafeAction,omitempty"`
+}
+
+// GuardrailUnsafeAction specifies what is done to the requests classified as unsafe.
+//
+// +kubebuilder:validation:Enum=Block;Annotate
+type GuardrailUnsafeAction string
+
+const (
+	// GuardrailUnsafeActionBlock rejects the requests.
+	GuardrailUnsafeActionBlock GuardrailUnsafeAction = "Block"
+	// GuardrailUnsafeActionAnnotate lets the requests through, and adds the violated categories to the dynamic
+	// metadata.
+	GuardrailUnsafeActionAnnotate GuardrailUnsafeAction = "Annotate"
+)
+
+// GuardrailCompletion configures the check of the completions.
+type GuardrailCompletion struct {
+	// StreamWindowSize is the number of bytes of the completion of a streaming response checked at once. The events
+	// of the stream are held back until the completion in them is checked, so the clients receive the stream in
+	// windows of this size. Zero holds the whole streaming response back until its end. Defaults to 512.
+	//
+	// +optional
+	// +kubebuilder:default=512
+	// +kubebuilder:validation:Minimum=0
+	StreamWindowSize *int32 `json:"streamWindowSize,omitempty"`
 }
 
 // AIGatewayRouteRule is a rule that defines the routing behavior of the AIGatewayRoute.
@@ -45,6 +45,8 @@ type AIServiceBackendList struct {
 }
 
 // AIServiceBackendSpec details the AIServiceBackend configuration.
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.batch) || self.schema.name != 'AWSBedrock' || has(self.batch.roleARN)", message="batch.roleARN is required for the AWSBedrock schema"
 type AIServiceBackendSpec struct {
 	// APISchema specifies the API schema of the output format of requests from
 	// Envoy that this AIServiceBackend can accept as incoming requests.
@@ -76,6 +78,18 @@ type AIServiceBackendSpec struct {
 	// +optional
 	BodyMutation *HTTPBodyMutation `json:"bodyMutation,omitempty"`
 
+	// Batch configures the batch jobs created on this backend through the /v1/batches endpoint.
+	//
+	// This is required to create batches on the AWSBedrock and GCPVertexAI backends, whose batch jobs read the input
+	// from and write the output to the storage of the provider. The storage locations and the service role of the jobs
+	// are only taken from here, and the requests setting them in the batch metadata are rejected.
+	//
+	// The batch inference jobs of AWS Bedrock are served by the control plane bedrock.<region>.amazonaws.com, so the
+	// backend of an AWSBedrock AIServiceBackend with batches must point to the control plane rather than the runtime.
+	//
+	// +optional
+	Batch *AIServiceBackendBatch `json:"batch,omitempty"`
+
 	// TODO: maybe add backend-level LLMRequestCost configuration that overrides the AIGatewayRoute-level LLMRequestCost.
 	// 	That may be useful for the backend that has a different cost calculation logic.
 }
@@ -127,3 +141,28 @@ type HTTPHeaderMutation struct {
 	// +kubebuilder:validation:MaxItems=16
 	Remove []string `json:"remove,omitempty"`
 }
+
+// AIServiceBackendBatch configures the batch jobs of an AIServiceBackend whose provider keeps the input and the output
+// of the batches in its own storage, i.e. S3 for AWS Bedrock and Cloud Storage for GCP Vertex AI.
+type AIServiceBackendBatch struct {
+	// InputURIPrefix is the storage URI under which the inputs of the batches must be, e.g. "s3://bucket/batch/input/"
+	// for AWS Bedrock and "gs://bucket/batch/input/" for GCP Vertex AI. The batches whose input_file_id is not under
+	// this prefix are rejected.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^(s3|gs)://.+`
+	InputURIPrefix string `json:"inputURIPrefix"`
+	// OutputURI is the storage URI where the provider writes the outputs of the batches, e.g.
+	// "s3://bucket/batch/output/" for AWS Bedrock and "gs://bucket/batch/output/" for GCP Vertex AI.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^(s3|gs)://.+`
+	OutputURI string `json:"outputURI"`
+	// RoleARN is the ARN of the service role that AWS Bedrock assumes to read the input and write the output of
+	// the batch inference jobs. The role of the backend credentials must be allowed to pass this role.
+	//
+	// This is required for the AWSBedrock schema, and ignored otherwise.
+	//
+	// +optional
+	RoleARN string `json:"roleARN,omitempty"`
+}
@@ -81,14 +81,16 @@ func TestAddKnownTypes(t *testing.T) {
 			"BackendSecurityPolicyList",
 			"MCPRoute",
 			"MCPRouteList",
+			"QuotaPolicy",
+			"QuotaPolicyList",
 		}
 
 		for _, typeName := range expectedTypes {
 			assert.Contains(t, types, typeName, "Type %s should be registered", typeName)
 		}
 
-		// Verify we have the expected number of types (8 custom types)
-		assert.GreaterOrEqual(t, len(types), 8, "Should have at least 8 registered types")
+		// Verify we have the expected number of types (10 custom types)
+		assert.GreaterOrEqual(t, len(types), 10, "Should have at least 10 registered types")
 	})
 
 	t.Run("AddKnownTypes can be called multiple times", func(t *testing.T) {
@@ -120,6 +122,7 @@ func TestAddToScheme(t *testing.T) {
 		assert.Contains(t, types, "AIServiceBackend")
 		assert.Contains(t, types, "BackendSecurityPolicy")
 		assert.Contains(t, types, "MCPRoute")
+		assert.Contains(t, types, "QuotaPolicy")
 	})
 }
 
@@ -15,7 +15,7 @@ package v1alpha1
 type VersionedAPISchema struct {
 	// Name is the name of the API schema of the AIGatewayRoute or AIServiceBackend.
 	//
-	// +kubebuilder:validation:Enum=OpenAI;Cohere;AWSBedrock;AzureOpenAI;GCPVertexAI;GCPAnthropic;Anthropic;AWSAnthropic
+	// +kubebuilder:validation:Enum=OpenAI;Cohere;AWSBedrock;AzureOpenAI;GCPVertexAI;GCPAnthropic;Anthropic;AWSAnthropic;GoogleAIStudio;AzureContentSafety
 	Name APISchema `json:"name"`
 
 	// Version is the version of the API schema.
@@ -85,6 +85,18 @@ const (
 	// https://aws.amazon.com/bedrock/anthropic/
 	// https://docs.claude.com/en/api/claude-on-amazon-bedrock
 	APISchemaAWSAnthropic APISchema = "AWSAnthropic"
+	// APISchemaGoogleAIStudio is the schema of the Gemini Developer API served by Google AI Studio.
+	// Unlike APISchemaGCPVertexAI, it does not require a GCP project nor a region, and it is authenticated with an
+	// API key, usually configured with a BackendSecurityPolicy of type GoogleAPIKey.
+	//
+	// https://ai.google.dev/api
+	APISchemaGoogleAIStudio APISchema = "GoogleAIStudio"
+	// APISchemaAzureContentSafety is the schema of Azure AI Content Safety, which only serves the moderation
+	// requests by translating them to the text:analyze API. The version is the api-version of the API, and
+	// defaults to "2024-09-01" when empty.
+	//
+	// https://learn.microsoft.com/en-us/azure/ai-services/content-safety/
+	APISchemaAzureContentSafety APISchema = "AzureContentSafety"
 )
 
 const (
@@ -94,6 +106,12 @@ const (
 )
 
 // LLMRequestCost configures each request cost.
+//
+// The token usage of a batch created through the /v1/batches endpoint is attributed to the first request
+// retrieving the batch once it is completed. Each batch is charged only once across all the external processors
+// sharing the quota store, so the deployments with multiple replicas must use a shared quota store such as Redis.
+// A batch never retrieved after its completion is not charged. The batches on the AWSBedrock and GCPVertexAI
+// backends are never charged since their batch jobs do not report the token usage.
 type LLMRequestCost struct {
 	// MetadataKey is the key of the metadata to store this cost of the request.
 	//
@@ -119,6 +137,18 @@ type LLMRequestCost struct {
 	//	* output_tokens: the number of output tokens. Type: unsigned integer.
 	//	* total_tokens: the total number of tokens. Type: unsigned integer.
 	//	* reasoning_tokens: the number of reasoning tokens. Type: unsigned integer.
+	//	* audio_duration_seconds: the duration of the input audio in seconds for the speech-to-text endpoints. Type: unsigned integer.
+	//	* request_headers: the headers of the request. Type: map of string to string.
+	//	* stream: whether the response is streamed. Type: boolean.
+	//	* operation: the operation name of the endpoint, e.g. "chat", "embeddings" or "image_generation". Type: string.
+	//	* response_model: the model that generated the response as reported by the backend. Type: string.
+	//	* image_count: the number of generated images for the image generation endpoint. Type: unsigned integer.
+	//	* audio_count: the number of generated audio outputs of the text-to-speech endpoint. Type: unsigned integer.
+	//	* tool_call_count: the number of tool calls in the chat completion response. Type: unsigned integer.
+	//	* cost: the cost of the request calculated from the pricing catalog of the GatewayConfig, or zero if the model has no price. Type: double.
+	//
+	// The expression can also call price(model, unit), which returns the price of one unit, e.g. "input" or "output",
+	// of the given model from the price table of the gateway and the backend as a double. It returns zero for unknown models and units.
 	//
 	// For example, the following expressions are valid:
 	//
@@ -127,6 +157,9 @@ type LLMRequestCost struct {
 	//	* "backend == 'bar.default' ?  (input_tokens - cached_input_tokens) + cached_input_tokens * 0.1 + cache_creation_input_tokens * 1.25 + output_tokens : total_tokens"
 	//	* "input_tokens + output_tokens + total_tokens"
 	//	* "input_tokens * output_tokens"
+	//	* "'x-tier' in request_headers && request_headers['x-tier'] == 'free' ? uint(0) : total_tokens"
+	//	* "uint(price(model, 'input') * double(input_tokens) + price(model, 'output') * double(output_tokens))"
+	//	* "uint(cost * 1000000.0)"
 	//
 	// +optional
 	CEL *string `json:"cel,omitempty"`
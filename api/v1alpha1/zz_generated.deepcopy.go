@@ -215,6 +215,21 @@ func (in *AIGatewayRouteSpec) DeepCopyInto(out *AIGatewayRouteSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ResponseCache != nil {
+		in, out := &in.ResponseCache, &out.ResponseCache
+		*out = new(ResponseCache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PIIPolicy != nil {
+		in, out := &in.PIIPolicy, &out.PIIPolicy
+		*out = new(PIIPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Guardrail != nil {
+		in, out := &in.Guardrail, &out.Guardrail
+		*out = new(Guardrail)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AIGatewayRouteSpec.
@@ -276,6 +291,21 @@ func (in *AIServiceBackend) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AIServiceBackendBatch) DeepCopyInto(out *AIServiceBackendBatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AIServiceBackendBatch.
+func (in *AIServiceBackendBatch) DeepCopy() *AIServiceBackendBatch {
+	if in == nil {
+		return nil
+	}
+	out := new(AIServiceBackendBatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AIServiceBackendList) DeepCopyInto(out *AIServiceBackendList) {
 	*out = *in
@@ -308,6 +338,26 @@ func (in *AIServiceBackendList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AIServiceBackendRef) DeepCopyInto(out *AIServiceBackendRef) {
+	*out = *in
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AIServiceBackendRef.
+func (in *AIServiceBackendRef) DeepCopy() *AIServiceBackendRef {
+	if in == nil {
+		return nil
+	}
+	out := new(AIServiceBackendRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AIServiceBackendSpec) DeepCopyInto(out *AIServiceBackendSpec) {
 	*out = *in
@@ -323,6 +373,11 @@ func (in *AIServiceBackendSpec) DeepCopyInto(out *AIServiceBackendSpec) {
 		*out = new(HTTPBodyMutation)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Batch != nil {
+		in, out := &in.Batch, &out.Batch
+		*out = new(AIServiceBackendBatch)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AIServiceBackendSpec.
@@ -571,6 +626,26 @@ func (in *BackendSecurityPolicyGCPCredentials) DeepCopy() *BackendSecurityPolicy
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendSecurityPolicyGoogleAPIKey) DeepCopyInto(out *BackendSecurityPolicyGoogleAPIKey) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.SecretObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendSecurityPolicyGoogleAPIKey.
+func (in *BackendSecurityPolicyGoogleAPIKey) DeepCopy() *BackendSecurityPolicyGoogleAPIKey {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendSecurityPolicyGoogleAPIKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackendSecurityPolicyList) DeepCopyInto(out *BackendSecurityPolicyList) {
 	*out = *in
@@ -657,6 +732,11 @@ func (in *BackendSecurityPolicySpec) DeepCopyInto(out *BackendSecurityPolicySpec
 		*out = new(BackendSecurityPolicyAnthropicAPIKey)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GoogleAPIKey != nil {
+		in, out := &in.GoogleAPIKey, &out.GoogleAPIKey
+		*out = new(BackendSecurityPolicyGoogleAPIKey)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendSecurityPolicySpec.
@@ -907,6 +987,92 @@ func (in *GatewayConfigStatus) DeepCopy() *GatewayConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Guardrail) DeepCopyInto(out *Guardrail) {
+	*out = *in
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(GuardrailWebhook)
+		**out = **in
+	}
+	if in.LLM != nil {
+		in, out := &in.LLM, &out.LLM
+		*out = new(GuardrailLLM)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Completion != nil {
+		in, out := &in.Completion, &out.Completion
+		*out = new(GuardrailCompletion)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Guardrail.
+func (in *Guardrail) DeepCopy() *Guardrail {
+	if in == nil {
+		return nil
+	}
+	out := new(Guardrail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuardrailCompletion) DeepCopyInto(out *GuardrailCompletion) {
+	*out = *in
+	if in.StreamWindowSize != nil {
+		in, out := &in.StreamWindowSize, &out.StreamWindowSize
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardrailCompletion.
+func (in *GuardrailCompletion) DeepCopy() *GuardrailCompletion {
+	if in == nil {
+		return nil
+	}
+	out := new(GuardrailCompletion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuardrailLLM) DeepCopyInto(out *GuardrailLLM) {
+	*out = *in
+	in.BackendRef.DeepCopyInto(&out.BackendRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardrailLLM.
+func (in *GuardrailLLM) DeepCopy() *GuardrailLLM {
+	if in == nil {
+		return nil
+	}
+	out := new(GuardrailLLM)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuardrailWebhook) DeepCopyInto(out *GuardrailWebhook) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardrailWebhook.
+func (in *GuardrailWebhook) DeepCopy() *GuardrailWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(GuardrailWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPBodyField) DeepCopyInto(out *HTTPBodyField) {
 	*out = *in
@@ -1483,6 +1649,46 @@ func (in *MCPToolFilter) DeepCopy() *MCPToolFilter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PIICustomPattern) DeepCopyInto(out *PIICustomPattern) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PIICustomPattern.
+func (in *PIICustomPattern) DeepCopy() *PIICustomPattern {
+	if in == nil {
+		return nil
+	}
+	out := new(PIICustomPattern)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PIIPolicy) DeepCopyInto(out *PIIPolicy) {
+	*out = *in
+	if in.Entities != nil {
+		in, out := &in.Entities, &out.Entities
+		*out = make([]PIIEntity, len(*in))
+		copy(*out, *in)
+	}
+	if in.CustomPatterns != nil {
+		in, out := &in.CustomPatterns, &out.CustomPatterns
+		*out = make([]PIICustomPattern, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PIIPolicy.
+func (in *PIIPolicy) DeepCopy() *PIIPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PIIPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PerModelQuota) DeepCopyInto(out *PerModelQuota) {
 	*out = *in
@@ -1724,6 +1930,57 @@ func (in *QuotaValue) DeepCopy() *QuotaValue {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResponseCache) DeepCopyInto(out *ResponseCache) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.OwnerHeader != nil {
+		in, out := &in.OwnerHeader, &out.OwnerHeader
+		*out = new(string)
+		**out = **in
+	}
+	if in.Semantic != nil {
+		in, out := &in.Semantic, &out.Semantic
+		*out = new(SemanticResponseCache)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResponseCache.
+func (in *ResponseCache) DeepCopy() *ResponseCache {
+	if in == nil {
+		return nil
+	}
+	out := new(ResponseCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SemanticResponseCache) DeepCopyInto(out *SemanticResponseCache) {
+	*out = *in
+	in.BackendRef.DeepCopyInto(&out.BackendRef)
+	if in.SimilarityThreshold != nil {
+		in, out := &in.SimilarityThreshold, &out.SimilarityThreshold
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SemanticResponseCache.
+func (in *SemanticResponseCache) DeepCopy() *SemanticResponseCache {
+	if in == nil {
+		return nil
+	}
+	out := new(SemanticResponseCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceQuotaDefinition) DeepCopyInto(out *ServiceQuotaDefinition) {
 	*out = *in
@@ -8,6 +8,7 @@ package v1beta1
 import (
 	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // GatewayConfig provides configuration for the AI Gateway external processor
@@ -80,6 +81,190 @@ type GatewayConfigSpec struct {
 	// +listType=map
 	// +listMapKey=metadataKey
 	GlobalLLMRequestCosts []LLMRequestCost `json:"globalLLMRequestCosts,omitempty"`
+
+	// QuotaStore configures where the external processor keeps the counters used to enforce QuotaPolicy.
+	//
+	// By default, the counters are kept in the memory of each external processor, which means that
+	// the quota is enforced per replica. Use the Redis type to share the counters across all the
+	// replicas of the Gateway.
+	//
+	// +optional
+	QuotaStore *QuotaStore `json:"quotaStore,omitempty"`
+
+	// Pricing is the price catalog of the models served through the Gateway.
+	//
+	// When the model of a request has a price, the external processor calculates the cost of the request
+	// from its token usage, reports it as the gen_ai.client.cost metric and sets it in the "cost" key of the
+	// dynamic metadata in the "io.envoy.ai_gateway" namespace. The cost is also available to the CEL
+	// expressions of GlobalLLMRequestCosts and LLMRequestCosts as the cost variable, e.g. to rate limit the
+	// spending with `uint(cost * 1000000.0)`. Their price(model, unit) function returns the price of one unit,
+	// e.g. one token. The prices with a backendRef take precedence over the ones without it for the requests
+	// sent to that AIServiceBackend.
+	//
+	// +optional
+	Pricing *GatewayConfigPricing `json:"pricing,omitempty"`
+}
+
+// GatewayConfigPricing is the price catalog of the models.
+type GatewayConfigPricing struct {
+	// Currency is the ISO 4217 code of the currency of the prices, reported as the
+	// aigw.cost.currency attribute of the gen_ai.client.cost metric.
+	//
+	// +optional
+	// +kubebuilder:default=USD
+	// +kubebuilder:validation:Pattern=`^[A-Z]{3}$`
+	Currency string `json:"currency,omitempty"`
+
+	// Models is the list of the prices of the models.
+	//
+	// A price with a backendRef takes precedence over the one without it for the requests to that backend.
+	// The price of the model reported in the response, e.g. "gpt-4o-2024-08-06", takes precedence over the
+	// one of the model of the request, e.g. "gpt-4o".
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=1024
+	Models []ModelPricing `json:"models"`
+}
+
+// ModelPricing is the price of each unit of a model in the currency of the catalog.
+//
+// Each price is a non-negative decimal number. The prices of the tokens are per one million tokens, which is how
+// the providers publish them, and the unset prices are zero unless stated otherwise.
+//
+// The input tokens that are read from or written to the cache are charged at the cachedInput and
+// cacheCreationInput prices, and the rest at the input price. Likewise, the reasoning tokens are
+// charged at the reasoning price and the rest of the output tokens at the output price.
+type ModelPricing struct {
+	// Model is the name of the model.
+	//
+	// +kubebuilder:validation:MinLength=1
+	Model string `json:"model"`
+
+	// BackendRef limits the price to the requests sent to the AIServiceBackend. When omitted, the price applies
+	// to the model served by any backend without a price of its own.
+	//
+	// +optional
+	BackendRef *ModelPricingBackendRef `json:"backendRef,omitempty"`
+
+	// Input is the price of one million input tokens.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	Input *string `json:"input,omitempty"`
+
+	// CachedInput is the price of one million input tokens read from the cache. Defaults to the input price.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	CachedInput *string `json:"cachedInput,omitempty"`
+
+	// CacheCreationInput is the price of one million input tokens written to the cache. Defaults to the input price.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	CacheCreationInput *string `json:"cacheCreationInput,omitempty"`
+
+	// Output is the price of one million output tokens.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	Output *string `json:"output,omitempty"`
+
+	// Reasoning is the price of one million reasoning tokens. Defaults to the output price.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	Reasoning *string `json:"reasoning,omitempty"`
+
+	// Image is the price of one generated image.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	Image *string `json:"image,omitempty"`
+
+	// Audio is the price of one second of the input audio of the speech-to-text endpoints.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	Audio *string `json:"audio,omitempty"`
+
+	// AudioOutput is the price of one generated audio output, e.g. a speech of the text-to-speech endpoints.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	AudioOutput *string `json:"audioOutput,omitempty"`
+}
+
+// ModelPricingBackendRef is a reference to an AIServiceBackend.
+type ModelPricingBackendRef struct {
+	// Name is the name of the AIServiceBackend.
+	//
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the AIServiceBackend. Defaults to the namespace of the GatewayConfig.
+	//
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+}
+
+// QuotaStoreType specifies the type of the quota counter store.
+//
+// +kubebuilder:validation:Enum=Memory;Redis
+type QuotaStoreType string
+
+const (
+	// QuotaStoreTypeMemory keeps the counters in the memory of each external processor.
+	QuotaStoreTypeMemory QuotaStoreType = "Memory"
+	// QuotaStoreTypeRedis keeps the counters in a server speaking the Redis serialization protocol (RESP),
+	// such as Redis or Valkey.
+	QuotaStoreTypeRedis QuotaStoreType = "Redis"
+)
+
+// QuotaStore configures the quota counter store.
+//
+// +kubebuilder:validation:XValidation:rule="self.type == 'Redis' ? has(self.redis) : !has(self.redis)", message="redis must be specified if and only if the type is Redis"
+type QuotaStore struct {
+	// Type is the type of the store.
+	//
+	// +kubebuilder:validation:Required
+	Type QuotaStoreType `json:"type"`
+
+	// Redis configures the Redis store. Required when the type is Redis.
+	//
+	// +optional
+	Redis *QuotaStoreRedis `json:"redis,omitempty"`
+}
+
+// QuotaStoreRedis configures the Redis quota counter store.
+//
+// +kubebuilder:validation:XValidation:rule="!self.url.matches('^rediss?://[^/?#@]*:[^/?#@]*@')", message="the password must be specified with passwordSecretRef rather than in the url"
+type QuotaStoreRedis struct {
+	// URL is the URL of the Redis server in the "redis://[username@]host[:port][/db]" format.
+	// Use the "rediss" scheme to connect over TLS. The username, if any, is used together with the password
+	// referenced by PasswordSecretRef to authenticate with the AUTH command, and the path selects the logical
+	// database, which defaults to 0. The URL must not contain the password.
+	//
+	// When Cluster is true, the URL points to one of the nodes of the cluster and the additional seed nodes
+	// can be listed with the "addr" query parameter, e.g. "redis://node-0:6379?addr=node-1:6379&addr=node-2:6379".
+	// The logical database cannot be selected in that case.
+	//
+	// +kubebuilder:validation:Pattern=`^rediss?://`
+	URL string `json:"url"`
+
+	// Cluster specifies whether the server is a Redis Cluster. When true, the external processor discovers the
+	// nodes of the cluster and follows its MOVED and ASK redirects.
+	//
+	// +optional
+	Cluster bool `json:"cluster,omitempty"`
+
+	// PasswordSecretRef is the reference to the secret containing the password used to authenticate with the
+	// Redis server. The secret defaults to the namespace of the GatewayConfig.
+	// ai-gateway must be given the permission to read this secret.
+	// The key of the secret should be "password".
+	//
+	// +optional
+	PasswordSecretRef *gwapiv1.SecretObjectReference `json:"passwordSecretRef,omitempty"`
 }
 
 // GatewayConfigExtProc holds runtime-specific configuration for the external processor.
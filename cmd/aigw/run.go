@@ -81,6 +81,9 @@ type runCmdContext struct {
 	fakeClientSet *fake.Clientset
 	// mcpSessionEncryptionIterations is the number of iterations for MCP session encryption key derivation.
 	mcpSessionEncryptionIterations int
+	// responseStorePath is the directory where the external processor keeps the responses of the OpenAI Responses API.
+	// Empty means the responses are not kept by the gateway.
+	responseStorePath string
 }
 
 // run starts the AI Gateway locally for a given configuration.
@@ -148,6 +151,7 @@ func run(ctx context.Context, c *cmdRun, o *runOpts, stdout, stderr io.Writer) e
 		adminPort:                      c.AdminPort,
 		extProcLauncher:                o.extProcLauncher,
 		mcpSessionEncryptionIterations: c.MCPSessionEncryptionIterations,
+		responseStorePath:              o.responseStorePath,
 	}
 	// If any of the configured MCP servers is using stdio, set up the streamable HTTP proxies for them
 	if err = proxyStdioMCPServers(ctx, debugLogger, c.mcpConfig); err != nil {
@@ -275,7 +279,7 @@ func recreateDir(path string) error {
 // writeEnvoyResourcesAndRunExtProc reads all resources from the given string, writes them to the output file, and runs
 // external processes for EnvoyExtensionPolicy resources.
 func (runCtx *runCmdContext) writeEnvoyResourcesAndRunExtProc(ctx context.Context, original string) (client.Client, <-chan error, int, error) {
-	aigwRoutes, mcpRoutes, aigwBackends, backendSecurityPolicies, backendTLSPolicies, gateways, secrets, _, err := collectObjects(original, runCtx.envoyGatewayResourcesOut, runCtx.stderrLogger)
+	aigwRoutes, mcpRoutes, aigwBackends, backendSecurityPolicies, quotaPolicies, backendTLSPolicies, gateways, secrets, _, err := collectObjects(original, runCtx.envoyGatewayResourcesOut, runCtx.stderrLogger)
 	if err != nil {
 		return nil, nil, 0, fmt.Errorf("error collecting: %w", err)
 	}
@@ -298,7 +302,7 @@ func (runCtx *runCmdContext) writeEnvoyResourcesAndRunExtProc(ctx context.Contex
 	}
 
 	var secretList *corev1.SecretList
-	fakeClient, _fakeClientSet, httpRoutes, eps, httpRouteFilters, backends, secretList, backendTrafficPolicies, securityPolicies, err := translateCustomResourceObjects(ctx, aigwRoutes, mcpRoutes, aigwBackends, backendSecurityPolicies, backendTLSPolicies, gateways, secrets, runCtx.stderrLogger)
+	fakeClient, _fakeClientSet, httpRoutes, eps, httpRouteFilters, backends, secretList, backendTrafficPolicies, securityPolicies, err := translateCustomResourceObjects(ctx, aigwRoutes, mcpRoutes, aigwBackends, backendSecurityPolicies, quotaPolicies, backendTLSPolicies, gateways, secrets, runCtx.stderrLogger)
 	if err != nil {
 		return nil, nil, 0, fmt.Errorf("error translating: %w", err)
 	}
@@ -363,6 +367,10 @@ func (runCtx *runCmdContext) mustStartExtProc(
 	ctx context.Context,
 	filterCfg *filterapi.Config,
 ) <-chan error {
+	if filterCfg.ResponseStore == nil && runCtx.responseStorePath != "" {
+		// Keep the responses on the local disk so that they survive the restarts of aigw.
+		filterCfg.ResponseStore = &filterapi.ResponseStore{Type: filterapi.ResponseStoreTypeFile, Path: runCtx.responseStorePath}
+	}
 	marshaled, err := yaml.Marshal(filterCfg)
 	if err != nil {
 		panic(fmt.Sprintf("BUG: failed to marshal filter config: %v", err))
@@ -118,6 +118,27 @@ func Test_mustStartExtProc(t *testing.T) {
 	require.ErrorIs(t, <-done, mockErr)
 }
 
+func Test_mustStartExtProc_responseStore(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "responses")
+	runCtx := &runCmdContext{
+		stderrLogger:      slog.New(slog.DiscardHandler),
+		stderr:            io.Discard,
+		tmpdir:            t.TempDir(),
+		adminPort:         1064,
+		responseStorePath: storePath,
+		extProcLauncher:   func(context.Context, []string, io.Writer) error { return errors.New("mock error") },
+	}
+
+	cfg := &filterapi.Config{Version: version.Parse()}
+	<-runCtx.mustStartExtProc(t.Context(), cfg)
+	require.Equal(t, &filterapi.ResponseStore{Type: filterapi.ResponseStoreTypeFile, Path: storePath}, cfg.ResponseStore)
+
+	// The explicitly configured store is preserved.
+	cfg = &filterapi.Config{Version: version.Parse(), ResponseStore: &filterapi.ResponseStore{Type: filterapi.ResponseStoreTypeMemory}}
+	<-runCtx.mustStartExtProc(t.Context(), cfg)
+	require.Equal(t, &filterapi.ResponseStore{Type: filterapi.ResponseStoreTypeMemory}, cfg.ResponseStore)
+}
+
 func Test_mustStartExtProc_defaultHeaderAttributes(t *testing.T) {
 	var capturedArgs []string
 	runCtx := &runCmdContext{
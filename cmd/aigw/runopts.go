@@ -47,6 +47,9 @@ type runOpts struct {
 	// Contains: filterapi.Config YAML for external processor
 	// Derived from: translating configPath (extracts filter config from aigw resources)
 	extprocConfigPath string
+	// responseStorePath is {StateHome}/responses
+	// Contains: the responses of the OpenAI Responses API kept by the external processor, shared across runs
+	responseStorePath string
 }
 
 // newRunOpts creates runOpts with all paths computed and creates directories
@@ -67,6 +70,7 @@ func newRunOpts(dirs *xdg.Directories, runID, configPath string, extProcLauncher
 	opts.egResourcesPath = filepath.Join(runDir, "envoy-ai-gateway-resources", "config.yaml")
 	opts.extprocConfigPath = filepath.Join(runDir, "extproc-config.yaml")
 	opts.extprocUDSPath = filepath.Join(dirs.RuntimeDir, runID, "uds.sock")
+	opts.responseStorePath = filepath.Join(dirs.StateHome, "responses")
 
 	// Create directories that aigw writes to
 	// runDir: for log, config, extproc-config (0o750 per XDG spec for StateHome)
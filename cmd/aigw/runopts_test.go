@@ -53,6 +53,7 @@ func TestNewRunOpts(t *testing.T) {
 			{"egResourcesPath", filepath.Join(expectedRunDir, "envoy-ai-gateway-resources", "config.yaml"), actual.egResourcesPath},
 			{"extprocConfigPath", filepath.Join(expectedRunDir, "extproc-config.yaml"), actual.extprocConfigPath},
 			{"extprocUDSPath", filepath.Join(dirs.RuntimeDir, runID, "uds.sock"), actual.extprocUDSPath},
+			{"responseStorePath", filepath.Join(dirs.StateHome, "responses"), actual.responseStorePath},
 		}
 
 		for _, p := range paths {
@@ -32,6 +32,7 @@ import (
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	kyaml "sigs.k8s.io/yaml"
 
+	aigv1a1 "github.com/envoyproxy/ai-gateway/api/v1alpha1"
 	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
 	"github.com/envoyproxy/ai-gateway/internal/controller"
 )
@@ -44,12 +45,12 @@ func translate(ctx context.Context, paths []string, output, stderr io.Writer) er
 	if err != nil {
 		return err
 	}
-	aigwRoutes, mcpRoutes, aigwBackends, backendSecurityPolicies, backendTLSConfigs, originalGateways, originalSecrets, _, err := collectObjects(yaml, output, stderrLogger)
+	aigwRoutes, mcpRoutes, aigwBackends, backendSecurityPolicies, quotaPolicies, backendTLSConfigs, originalGateways, originalSecrets, _, err := collectObjects(yaml, output, stderrLogger)
 	if err != nil {
 		return fmt.Errorf("error translating: %w", err)
 	}
 
-	_, _, httpRoutes, extensionPolicies, httpRouteFilter, backends, secrets, backendTrafficPolicies, securityPolicies, err := translateCustomResourceObjects(ctx, aigwRoutes, mcpRoutes, aigwBackends, backendSecurityPolicies, backendTLSConfigs, originalGateways, originalSecrets, stderrLogger)
+	_, _, httpRoutes, extensionPolicies, httpRouteFilter, backends, secrets, backendTrafficPolicies, securityPolicies, err := translateCustomResourceObjects(ctx, aigwRoutes, mcpRoutes, aigwBackends, backendSecurityPolicies, quotaPolicies, backendTLSConfigs, originalGateways, originalSecrets, stderrLogger)
 	if err != nil {
 		return fmt.Errorf("error emitting: %w", err)
 	}
@@ -107,7 +108,7 @@ func readYamlsAsString(paths []string) (string, error) {
 }
 
 // collectObjects reads the YAML input and collects target resources. Currently, this will collect
-// AIGatewayRoute, AIServiceBackend, BackendSecurityPolicy, and Secret resources. Other resources
+// AIGatewayRoute, AIServiceBackend, BackendSecurityPolicy, QuotaPolicy, and Secret resources. Other resources
 // will be written back to the output writer.
 //
 // If the resource is not an AI Gateway custom resource, it will be written back to the output writer.
@@ -116,6 +117,7 @@ func collectObjects(yamlInput string, out io.Writer, logger *slog.Logger) (
 	mcpRoutes []*aigv1b1.MCPRoute,
 	aigwBackends []*aigv1b1.AIServiceBackend,
 	backendSecurityPolicies []*aigv1b1.BackendSecurityPolicy,
+	quotaPolicies []*aigv1a1.QuotaPolicy,
 	backendTLSConfigs []*gwapiv1.BackendTLSPolicy,
 	gws []*gwapiv1.Gateway,
 	secrets []*corev1.Secret,
@@ -163,6 +165,8 @@ func collectObjects(yamlInput string, out io.Writer, logger *slog.Logger) (
 			mustExtractAndAppend(obj, &aigwBackends)
 		case "BackendSecurityPolicy":
 			mustExtractAndAppend(obj, &backendSecurityPolicies)
+		case "QuotaPolicy":
+			mustExtractAndAppend(obj, &quotaPolicies)
 		case "Secret":
 			mustExtractAndAppend(obj, &secrets)
 		case "Gateway":
@@ -195,6 +199,7 @@ func translateCustomResourceObjects(
 	mcpRoutes []*aigv1b1.MCPRoute,
 	aigwBackends []*aigv1b1.AIServiceBackend,
 	backendSecurityPolicies []*aigv1b1.BackendSecurityPolicy,
+	quotaPolicies []*aigv1a1.QuotaPolicy,
 	backendTLSPolicies []*gwapiv1.BackendTLSPolicy,
 	gws []*gwapiv1.Gateway,
 	usedDefinedSecrets []*corev1.Secret,
@@ -216,7 +221,8 @@ func translateCustomResourceObjects(
 		WithStatusSubresource(&aigv1b1.AIGatewayRoute{}).
 		WithStatusSubresource(&aigv1b1.MCPRoute{}).
 		WithStatusSubresource(&aigv1b1.AIServiceBackend{}).
-		WithStatusSubresource(&aigv1b1.BackendSecurityPolicy{})
+		WithStatusSubresource(&aigv1b1.BackendSecurityPolicy{}).
+		WithStatusSubresource(&aigv1a1.QuotaPolicy{})
 	_ = controller.ApplyIndexing(ctx, func(_ context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
 		builder = builder.WithIndex(obj, field, extractValue)
 		return nil
@@ -236,6 +242,8 @@ func translateCustomResourceObjects(
 
 	bspC := controller.NewBackendSecurityPolicyController(fakeClient, fakeClientSet, logr.FromSlogHandler(logger.Handler()),
 		make(chan event.GenericEvent), make(chan event.GenericEvent))
+	qpC := controller.NewQuotaPolicyController(fakeClient, logr.FromSlogHandler(logger.Handler()),
+		make(chan event.GenericEvent))
 	aisbC := controller.NewAIServiceBackendController(fakeClient, fakeClientSet, logr.FromSlogHandler(logger.Handler()),
 		make(chan event.GenericEvent))
 	airC := controller.NewAIGatewayRouteController(fakeClient, fakeClientSet, logr.FromSlogHandler(logger.Handler()),
@@ -257,6 +265,9 @@ func translateCustomResourceObjects(
 	for _, bsp := range backendSecurityPolicies {
 		mustCreateAndReconcile(ctx, fakeClient, bsp, bspC, logger)
 	}
+	for _, qp := range quotaPolicies {
+		mustCreateAndReconcile(ctx, fakeClient, qp, qpC, logger)
+	}
 	for _, backend := range aigwBackends {
 		mustCreateAndReconcile(ctx, fakeClient, backend, aisbC, logger)
 	}
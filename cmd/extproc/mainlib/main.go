@@ -34,6 +34,7 @@ import (
 	"github.com/envoyproxy/ai-gateway/internal/metrics"
 	"github.com/envoyproxy/ai-gateway/internal/requestheaderattrs"
 	"github.com/envoyproxy/ai-gateway/internal/tracing"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
 	"github.com/envoyproxy/ai-gateway/internal/version"
 )
 
@@ -120,7 +121,7 @@ func parseAndValidateFlags(args []string) (extProcFlags, error) {
 	fs.StringVar(&flags.endpointPrefixes,
 		"endpointPrefixes",
 		"",
-		"Comma-separated key-value pairs for endpoint prefixes. Format: openai:/,cohere:/cohere,anthropic:/anthropic.",
+		"Comma-separated key-value pairs for endpoint prefixes. Format: openai:/,cohere:/cohere,anthropic:/anthropic,gemini:/gemini,bedrock:/bedrock.",
 	)
 	fs.IntVar(&flags.maxRecvMsgSize,
 		"maxRecvMsgSize",
@@ -283,7 +284,15 @@ func Main(ctx context.Context, args []string, stderr io.Writer) (err error) {
 	imageGenerationMetricsFactory := metrics.NewMetricsFactory(meter, metricsRequestHeaderAttributes, metrics.GenAIOperationImageGeneration)
 	responsesMetricsFactory := metrics.NewMetricsFactory(meter, metricsRequestHeaderAttributes, metrics.GenAIOperationResponses)
 	speechMetricsFactory := metrics.NewMetricsFactory(meter, metricsRequestHeaderAttributes, metrics.GenAIOperationSpeech)
+	transcriptionMetricsFactory := metrics.NewMetricsFactory(meter, metricsRequestHeaderAttributes, metrics.GenAIOperationTranscription)
+	translationMetricsFactory := metrics.NewMetricsFactory(meter, metricsRequestHeaderAttributes, metrics.GenAIOperationTranslation)
 	rerankMetricsFactory := metrics.NewMetricsFactory(meter, metricsRequestHeaderAttributes, metrics.GenAIOperationRerank)
+	generateContentMetricsFactory := metrics.NewMetricsFactory(meter, metricsRequestHeaderAttributes, metrics.GenAIOperationGenerateContent)
+	converseMetricsFactory := metrics.NewMetricsFactory(meter, metricsRequestHeaderAttributes, metrics.GenAIOperationConverse)
+	filesMetricsFactory := metrics.NewMetricsFactory(meter, metricsRequestHeaderAttributes, metrics.GenAIOperationFiles)
+	batchMetricsFactory := metrics.NewMetricsFactory(meter, metricsRequestHeaderAttributes, metrics.GenAIOperationBatch)
+	moderationMetricsFactory := metrics.NewMetricsFactory(meter, metricsRequestHeaderAttributes, metrics.GenAIOperationModeration)
+	countTokensMetricsFactory := metrics.NewMetricsFactory(meter, metricsRequestHeaderAttributes, metrics.GenAIOperationCountTokens)
 	mcpMetrics := metrics.NewMCP(meter, metricsRequestHeaderAttributes)
 
 	extproc.LogRequestHeaderAttributes = logRequestHeaderAttributes
@@ -302,13 +311,38 @@ func Main(ctx context.Context, args []string, stderr io.Writer) (err error) {
 		responsesMetricsFactory, tracing.ResponsesTracer(), endpointspec.ResponsesEndpointSpec{}))
 	server.Register(path.Join(flags.rootPrefix, endpointPrefixes.OpenAI, "/v1/audio/speech"), extproc.NewFactory(
 		speechMetricsFactory, tracing.SpeechTracer(), endpointspec.SpeechEndpointSpec{}))
+	server.Register(path.Join(flags.rootPrefix, endpointPrefixes.OpenAI, "/v1/audio/transcriptions"), extproc.NewFactory(
+		transcriptionMetricsFactory, tracing.TranscriptionTracer(), endpointspec.AudioTranscriptionsEndpointSpec{}))
+	server.Register(path.Join(flags.rootPrefix, endpointPrefixes.OpenAI, "/v1/audio/translations"), extproc.NewFactory(
+		translationMetricsFactory, tracing.TranscriptionTracer(), endpointspec.AudioTranslationsEndpointSpec{}))
 	server.Register(path.Join(flags.rootPrefix, endpointPrefixes.OpenAI, "/v1/images/generations"), extproc.NewFactory(
 		imageGenerationMetricsFactory, tracing.ImageGenerationTracer(), endpointspec.ImageGenerationEndpointSpec{}))
+	server.Register(path.Join(flags.rootPrefix, endpointPrefixes.OpenAI, "/v1/moderations"), extproc.NewFactory(
+		moderationMetricsFactory, tracingapi.NoopModerationsTracer{}, endpointspec.ModerationsEndpointSpec{}))
 	server.Register(path.Join(flags.rootPrefix, endpointPrefixes.Cohere, "/v2/rerank"), extproc.NewFactory(
 		rerankMetricsFactory, tracing.RerankTracer(), endpointspec.RerankEndpointSpec{}))
 	server.Register(path.Join(flags.rootPrefix, endpointPrefixes.OpenAI, "/v1/models"), extproc.NewModelsProcessor)
+	server.RegisterPrefix(path.Join(flags.rootPrefix, endpointPrefixes.OpenAI, "/v1/responses")+"/", extproc.NewStoredResponsesProcessor)
 	server.Register(path.Join(flags.rootPrefix, endpointPrefixes.Anthropic, "/v1/messages"), extproc.NewFactory(
 		messagesMetricsFactory, tracing.MessageTracer(), endpointspec.MessagesEndpointSpec{}))
+	server.Register(path.Join(flags.rootPrefix, endpointPrefixes.Anthropic, "/v1/messages/count_tokens"), extproc.NewFactory(
+		countTokensMetricsFactory, tracingapi.NoopCountTokensTracer{}, endpointspec.CountTokensEndpointSpec{}))
+	// The Gemini API has the model and the method in the path, e.g. /v1beta/models/{model}:generateContent.
+	server.RegisterPrefix(path.Join(flags.rootPrefix, endpointPrefixes.Gemini, "/v1beta/models")+"/", extproc.NewFactory(
+		generateContentMetricsFactory, tracing.GenerateContentTracer(), endpointspec.GenerateContentEndpointSpec{}))
+	// The AWS Bedrock Converse API has the model in the path, e.g. /model/{modelId}/converse-stream.
+	server.RegisterPrefix(path.Join(flags.rootPrefix, endpointPrefixes.AWSBedrock, "/model")+"/", extproc.NewFactory(
+		converseMetricsFactory, tracing.ConverseTracer(), endpointspec.ConverseEndpointSpec{}))
+	// The files and the batches are created with a request body, and then accessed by the ID in the path,
+	// e.g. /v1/batches/{batch_id}/cancel, without a request body.
+	server.Register(path.Join(flags.rootPrefix, endpointPrefixes.OpenAI, "/v1/files"), extproc.NewFactory(
+		filesMetricsFactory, tracingapi.NoopFilesTracer{}, endpointspec.FilesEndpointSpec{}))
+	server.RegisterPrefix(path.Join(flags.rootPrefix, endpointPrefixes.OpenAI, "/v1/files")+"/", extproc.NewFactory(
+		filesMetricsFactory, tracingapi.NoopFilesTracer{}, endpointspec.FilesEndpointSpec{}))
+	server.Register(path.Join(flags.rootPrefix, endpointPrefixes.OpenAI, "/v1/batches"), extproc.NewFactory(
+		batchMetricsFactory, tracingapi.NoopBatchesTracer{}, endpointspec.BatchesEndpointSpec{}))
+	server.RegisterPrefix(path.Join(flags.rootPrefix, endpointPrefixes.OpenAI, "/v1/batches")+"/", extproc.NewFactory(
+		batchMetricsFactory, tracingapi.NoopBatchesTracer{}, endpointspec.BatchesEndpointSpec{}))
 
 	// Create and register gRPC server with ExternalProcessorServer (the service Envoy calls).
 	if err = filterapi.StartConfigWatcher(ctx, flags.configPath, server, l, time.Second*5); err != nil {
@@ -1761,3 +1761,11 @@ type ErrorResponseMessage struct {
 	Message string `json:"message"`
 	Type    string `json:"type"`
 }
+
+// CountTokensResponse represents the response of the /v1/messages/count_tokens endpoint. The request has the
+// same fields as [MessagesRequest] except max_tokens and stream.
+// https://platform.claude.com/docs/en/api/messages/count_tokens
+type CountTokensResponse struct {
+	// InputTokens is the total number of tokens across the messages, the system prompt and the tools.
+	InputTokens int `json:"input_tokens"`
+}
@@ -123,7 +123,14 @@ type GuardrailConfiguration struct {
 	Trace *string `json:"trace,omitempty"`
 }
 
+// ConverseInput is the request body of a call to Converse or ConverseStream.
+// https://docs.aws.amazon.com/bedrock/latest/APIReference/API_runtime_Converse.html
 type ConverseInput struct {
+	// ModelID is the model ID taken from the request path /model/{modelId}/converse. It is not part of the JSON body.
+	ModelID string `json:"-"`
+	// Stream is true when the request path is /model/{modelId}/converse-stream. It is not part of the JSON body.
+	Stream bool `json:"-"`
+
 	// Additional model parameters field paths to return in the response. Converse
 	// returns the requested fields as a JSON Pointer object in the additionalModelResponseFields
 	// field. The following is example JSON for additionalModelResponseFieldPaths.
@@ -473,6 +480,7 @@ type ConverseStreamEvent struct {
 	Usage             *TokenUsage                           `json:"usage,omitempty"`
 	Start             *ContentBlockStart                    `json:"start,omitempty"`
 	ServiceTier       *ServiceTier                          `json:"serviceTier,omitempty"`
+	Metrics           *ConverseMetrics                      `json:"metrics,omitempty"`
 }
 
 // ConverseStreamEventContentBlockDelta is defined in the AWS Bedrock API:
@@ -603,7 +611,7 @@ type CachePointBlock struct {
 	Type string `json:"type"`
 }
 
-// TitanEmbeddingRequest is the request body for the Amazon Titan Embed Text models
+// TitanEmbeddingRequest is the request body for the Amazon Titan Embed Text and Multimodal Embeddings models
 // via the AWS Bedrock InvokeModel API.
 //
 // v1 (amazon.titan-embed-text-v1): only InputText is supported.
@@ -625,6 +633,20 @@ type TitanEmbeddingRequest struct {
 	// EmbeddingTypes specifies the output embedding types.
 	// Accepted values: "float" (default), "binary". Only supported by v2.
 	EmbeddingTypes []string `json:"embeddingTypes,omitempty"`
+
+	// InputImage is the base64 encoded image to embed. Only supported by the Titan Multimodal Embeddings model
+	// (amazon.titan-embed-image-v1), which embeds the text and the image together.
+	// See https://docs.aws.amazon.com/bedrock/latest/userguide/model-parameters-titan-embed-mm.html
+	InputImage string `json:"inputImage,omitempty"`
+
+	// EmbeddingConfig is the configuration of the Titan Multimodal Embeddings model.
+	EmbeddingConfig *TitanEmbeddingConfig `json:"embeddingConfig,omitempty"`
+}
+
+// TitanEmbeddingConfig is the embeddingConfig of [TitanEmbeddingRequest].
+type TitanEmbeddingConfig struct {
+	// OutputEmbeddingLength is the number of dimensions of the embedding, one of 256, 384 or 1024 (default).
+	OutputEmbeddingLength int `json:"outputEmbeddingLength"`
 }
 
 // TitanEmbeddingResponse is the response body returned by the Amazon Titan Embed Text models.
@@ -637,3 +659,169 @@ type TitanEmbeddingResponse struct {
 	// InputTextTokenCount is the number of tokens in the input text.
 	InputTextTokenCount int `json:"inputTextTokenCount"`
 }
+
+// TitanImageGenerationRequest is the request body for the Amazon Titan Image Generator and Amazon Nova Canvas
+// models via the AWS Bedrock InvokeModel API. Only the TEXT_IMAGE task is used.
+//
+// See https://docs.aws.amazon.com/bedrock/latest/userguide/model-parameters-titan-image.html
+type TitanImageGenerationRequest struct {
+	// TaskType is the type of the image generation task, such as TEXT_IMAGE.
+	TaskType string `json:"taskType"`
+	// TextToImageParams is the parameters of the TEXT_IMAGE task.
+	TextToImageParams TitanTextToImageParams `json:"textToImageParams"`
+	// ImageGenerationConfig is the common configuration of the image generation.
+	ImageGenerationConfig *TitanImageGenerationConfig `json:"imageGenerationConfig,omitempty"`
+}
+
+// TitanTextToImageParams is the parameters of the TEXT_IMAGE task of [TitanImageGenerationRequest].
+type TitanTextToImageParams struct {
+	// Text is the text prompt to generate the image.
+	Text string `json:"text"`
+	// NegativeText is the text prompt to define what not to include in the image.
+	NegativeText string `json:"negativeText,omitempty"`
+}
+
+// TitanImageGenerationConfig is the configuration of [TitanImageGenerationRequest].
+type TitanImageGenerationConfig struct {
+	// NumberOfImages is the number of images to generate, from 1 to 5. Defaults to 1.
+	NumberOfImages int `json:"numberOfImages,omitempty"`
+	// Quality is either standard (default) or premium.
+	Quality string `json:"quality,omitempty"`
+	// Width is the width of the images in pixels.
+	Width int `json:"width,omitempty"`
+	// Height is the height of the images in pixels.
+	Height int `json:"height,omitempty"`
+}
+
+// TitanImageGenerationResponse is the response body of the Amazon Titan Image Generator and Amazon Nova Canvas
+// models.
+type TitanImageGenerationResponse struct {
+	// Images is the list of the base64 encoded PNG images.
+	Images []string `json:"images"`
+	// Error is the error message if the generation failed, for example by the content moderation.
+	Error string `json:"error,omitempty"`
+}
+
+// StabilityImageGenerationRequest is the request body for the Stability AI image models such as Stable Image
+// Core, Stable Image Ultra and Stable Diffusion 3.5 via the AWS Bedrock InvokeModel API.
+//
+// See https://docs.aws.amazon.com/bedrock/latest/userguide/model-parameters-diffusion-stable-image-core-text-image-request-response.html
+type StabilityImageGenerationRequest struct {
+	// Prompt is the text prompt to generate the image.
+	Prompt string `json:"prompt"`
+	// AspectRatio is the aspect ratio of the image, such as 1:1 (default) or 16:9.
+	AspectRatio string `json:"aspect_ratio,omitempty"`
+	// OutputFormat is the format of the image. One of png (default), jpeg or webp.
+	OutputFormat string `json:"output_format,omitempty"`
+	// NegativePrompt is the text prompt to define what not to include in the image.
+	NegativePrompt string `json:"negative_prompt,omitempty"`
+}
+
+// StabilityImageGenerationResponse is the response body of the Stability AI image models.
+type StabilityImageGenerationResponse struct {
+	// Images is the list of the base64 encoded images.
+	Images []string `json:"images"`
+	// FinishReasons is the reason for each image, which is null on success or the reason of the filtering.
+	FinishReasons []*string `json:"finish_reasons,omitempty"`
+	// Seeds is the seed used to generate each image.
+	Seeds []int64 `json:"seeds,omitempty"`
+}
+
+// RerankRequest is the request body of the Rerank API of the Amazon Bedrock Agents runtime.
+//
+// See https://docs.aws.amazon.com/bedrock/latest/APIReference/API_agent-runtime_Rerank.html
+type RerankRequest struct {
+	// Queries is the list of the queries to rerank the sources against. Only one query is supported.
+	Queries []RerankQuery `json:"queries"`
+	// Sources is the list of the documents to rerank.
+	Sources []RerankSource `json:"sources"`
+	// RerankingConfiguration is the configuration of the reranking model.
+	RerankingConfiguration RerankingConfiguration `json:"rerankingConfiguration"`
+}
+
+// RerankQuery is a query of [RerankRequest].
+type RerankQuery struct {
+	// Type is always TEXT.
+	Type      string         `json:"type"`
+	TextQuery RerankTextData `json:"textQuery"`
+}
+
+// RerankTextData is a text query or document of [RerankRequest].
+type RerankTextData struct {
+	Text string `json:"text"`
+}
+
+// RerankSource is a document of [RerankRequest].
+type RerankSource struct {
+	// Type is always INLINE.
+	Type                 string                     `json:"type"`
+	InlineDocumentSource RerankInlineDocumentSource `json:"inlineDocumentSource"`
+}
+
+// RerankInlineDocumentSource is the inline content of [RerankSource].
+type RerankInlineDocumentSource struct {
+	// Type is always TEXT.
+	Type         string         `json:"type"`
+	TextDocument RerankTextData `json:"textDocument"`
+}
+
+// RerankingConfiguration is the configuration of [RerankRequest].
+type RerankingConfiguration struct {
+	// Type is always BEDROCK_RERANKING_MODEL.
+	Type                          string                        `json:"type"`
+	BedrockRerankingConfiguration BedrockRerankingConfiguration `json:"bedrockRerankingConfiguration"`
+}
+
+// BedrockRerankingConfiguration is the configuration of the Bedrock reranking model.
+type BedrockRerankingConfiguration struct {
+	// NumberOfResults is the number of the results to return.
+	NumberOfResults *int `json:"numberOfResults,omitempty"`
+	// ModelConfiguration is the reranking model to use.
+	ModelConfiguration BedrockRerankingModelConfiguration `json:"modelConfiguration"`
+}
+
+// BedrockRerankingModelConfiguration is the reranking model of [BedrockRerankingConfiguration].
+type BedrockRerankingModelConfiguration struct {
+	// ModelArn is the ARN of the reranking model, e.g. arn:aws:bedrock:us-west-2::foundation-model/cohere.rerank-v3-5:0.
+	ModelArn string `json:"modelArn"`
+}
+
+// RerankResponse is the response body of the Rerank API of the Amazon Bedrock Agents runtime.
+type RerankResponse struct {
+	// Results is the list of the sources ordered by relevance.
+	Results []RerankResult `json:"results"`
+	// NextToken is set when there are more results to return.
+	NextToken *string `json:"nextToken,omitempty"`
+}
+
+// RerankResult is a result of [RerankResponse].
+type RerankResult struct {
+	// Index is the position of the source in the request.
+	Index int `json:"index"`
+	// RelevanceScore is the relevance score of the source.
+	RelevanceScore float64 `json:"relevanceScore"`
+}
+
+// CountTokensRequest is the request body of the CountTokens API of Amazon Bedrock.
+// https://docs.aws.amazon.com/bedrock/latest/APIReference/API_runtime_CountTokens.html
+type CountTokensRequest struct {
+	Input CountTokensInput `json:"input"`
+}
+
+// CountTokensInput is the input to count the tokens of, either in the InvokeModel or the Converse format.
+type CountTokensInput struct {
+	// InvokeModel is the request body of the InvokeModel API, e.g. an Anthropic Messages request.
+	InvokeModel *CountTokensInvokeModelInput `json:"invokeModel,omitempty"`
+}
+
+// CountTokensInvokeModelInput is the InvokeModel request of [CountTokensInput].
+type CountTokensInvokeModelInput struct {
+	// Body is the request body of the InvokeModel API, which is base64 encoded in JSON.
+	Body []byte `json:"body"`
+}
+
+// CountTokensResponse is the response body of the CountTokens API of Amazon Bedrock.
+type CountTokensResponse struct {
+	// InputTokens is the number of tokens the input would consume.
+	InputTokens int `json:"inputTokens"`
+}
@@ -0,0 +1,98 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package awsbedrock
+
+import "time"
+
+// ModelInvocationJobStatus is the status of a batch inference job.
+type ModelInvocationJobStatus string
+
+const (
+	ModelInvocationJobStatusSubmitted          ModelInvocationJobStatus = "Submitted"
+	ModelInvocationJobStatusValidating         ModelInvocationJobStatus = "Validating"
+	ModelInvocationJobStatusScheduled          ModelInvocationJobStatus = "Scheduled"
+	ModelInvocationJobStatusInProgress         ModelInvocationJobStatus = "InProgress"
+	ModelInvocationJobStatusCompleted          ModelInvocationJobStatus = "Completed"
+	ModelInvocationJobStatusPartiallyCompleted ModelInvocationJobStatus = "PartiallyCompleted"
+	ModelInvocationJobStatusFailed             ModelInvocationJobStatus = "Failed"
+	ModelInvocationJobStatusStopping           ModelInvocationJobStatus = "Stopping"
+	ModelInvocationJobStatusStopped            ModelInvocationJobStatus = "Stopped"
+	ModelInvocationJobStatusExpired            ModelInvocationJobStatus = "Expired"
+)
+
+// CreateModelInvocationJobInput is the request body of CreateModelInvocationJob, which creates a batch inference job.
+// https://docs.aws.amazon.com/bedrock/latest/APIReference/API_CreateModelInvocationJob.html
+type CreateModelInvocationJobInput struct {
+	// JobName is the name of the job.
+	JobName string `json:"jobName"`
+	// RoleArn is the ARN of the service role with the permissions to carry out and manage the job.
+	RoleArn string `json:"roleArn"`
+	// ModelID is the ID or the ARN of the model to use for the job.
+	ModelID string `json:"modelId"`
+	// InputDataConfig is the location of the input data.
+	InputDataConfig ModelInvocationJobInputDataConfig `json:"inputDataConfig"`
+	// OutputDataConfig is the location of the output data.
+	OutputDataConfig ModelInvocationJobOutputDataConfig `json:"outputDataConfig"`
+	// TimeoutDurationInHours is the number of hours after which the job is stopped if it has not completed.
+	TimeoutDurationInHours *int `json:"timeoutDurationInHours,omitempty"`
+}
+
+// ModelInvocationJobInputDataConfig is the location of the input data of a batch inference job.
+type ModelInvocationJobInputDataConfig struct {
+	S3InputDataConfig ModelInvocationJobS3InputDataConfig `json:"s3InputDataConfig"`
+}
+
+// ModelInvocationJobS3InputDataConfig is the S3 location of the input data of a batch inference job.
+type ModelInvocationJobS3InputDataConfig struct {
+	// S3URI is the S3 location of the input data, e.g. "s3://bucket/input.jsonl".
+	S3URI string `json:"s3Uri"`
+	// S3InputFormat is the format of the input data. Only "JSONL" is supported.
+	S3InputFormat string `json:"s3InputFormat,omitempty"`
+}
+
+// ModelInvocationJobOutputDataConfig is the location of the output data of a batch inference job.
+type ModelInvocationJobOutputDataConfig struct {
+	S3OutputDataConfig ModelInvocationJobS3OutputDataConfig `json:"s3OutputDataConfig"`
+}
+
+// ModelInvocationJobS3OutputDataConfig is the S3 location of the output data of a batch inference job.
+type ModelInvocationJobS3OutputDataConfig struct {
+	// S3URI is the S3 location where the output data is written, e.g. "s3://bucket/output/".
+	S3URI string `json:"s3Uri"`
+}
+
+// CreateModelInvocationJobOutput is the response body of CreateModelInvocationJob.
+type CreateModelInvocationJobOutput struct {
+	// JobArn is the ARN of the created job.
+	JobArn string `json:"jobArn"`
+}
+
+// GetModelInvocationJobOutput is the response body of GetModelInvocationJob.
+// https://docs.aws.amazon.com/bedrock/latest/APIReference/API_GetModelInvocationJob.html
+type GetModelInvocationJobOutput struct {
+	// JobArn is the ARN of the job.
+	JobArn string `json:"jobArn"`
+	// JobName is the name of the job.
+	JobName string `json:"jobName,omitempty"`
+	// ModelID is the ID or the ARN of the model used for the job.
+	ModelID string `json:"modelId"`
+	// Status is the status of the job.
+	Status ModelInvocationJobStatus `json:"status"`
+	// Message is the details about the status of the job, e.g. the reason of the failure.
+	Message string `json:"message,omitempty"`
+	// InputDataConfig is the location of the input data.
+	InputDataConfig ModelInvocationJobInputDataConfig `json:"inputDataConfig"`
+	// OutputDataConfig is the location of the output data.
+	OutputDataConfig ModelInvocationJobOutputDataConfig `json:"outputDataConfig"`
+	// SubmitTime is the time at which the job was submitted.
+	SubmitTime *time.Time `json:"submitTime,omitempty"`
+	// LastModifiedTime is the time at which the job was last modified.
+	LastModifiedTime *time.Time `json:"lastModifiedTime,omitempty"`
+	// EndTime is the time at which the job ended.
+	EndTime *time.Time `json:"endTime,omitempty"`
+	// JobExpirationTime is the time at which the job times out.
+	JobExpirationTime *time.Time `json:"jobExpirationTime,omitempty"`
+}
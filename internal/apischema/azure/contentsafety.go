@@ -0,0 +1,64 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package azure contains the API schemas of the Azure AI services other than Azure OpenAI, whose API is
+// compatible with OpenAI.
+package azure
+
+// ContentSafetyCategory is a harm category of Azure AI Content Safety.
+type ContentSafetyCategory string
+
+const (
+	ContentSafetyCategoryHate     ContentSafetyCategory = "Hate"
+	ContentSafetyCategorySelfHarm ContentSafetyCategory = "SelfHarm"
+	ContentSafetyCategorySexual   ContentSafetyCategory = "Sexual"
+	ContentSafetyCategoryViolence ContentSafetyCategory = "Violence"
+)
+
+const (
+	// ContentSafetyOutputTypeFourSeverityLevels returns the severities 0, 2, 4 and 6.
+	ContentSafetyOutputTypeFourSeverityLevels = "FourSeverityLevels"
+	// ContentSafetyMaxSeverity is the maximum severity of ContentSafetyOutputTypeFourSeverityLevels.
+	ContentSafetyMaxSeverity = 6
+)
+
+// AnalyzeTextRequest is the request body of the text:analyze API of Azure AI Content Safety.
+// https://learn.microsoft.com/en-us/rest/api/contentsafety/text-operations/analyze-text
+type AnalyzeTextRequest struct {
+	// Text is the text to analyze, up to 10k characters.
+	Text string `json:"text"`
+	// Categories is the list of the categories to analyze. All the categories are analyzed when empty.
+	Categories []ContentSafetyCategory `json:"categories,omitempty"`
+	// BlocklistNames is the list of the names of the blocklists to check.
+	BlocklistNames []string `json:"blocklistNames,omitempty"`
+	// HaltOnBlocklistHit stops the analysis when a blocklist is hit.
+	HaltOnBlocklistHit bool `json:"haltOnBlocklistHit,omitempty"`
+	// OutputType is the granularity of the severities, either ContentSafetyOutputTypeFourSeverityLevels or
+	// "EightSeverityLevels".
+	OutputType string `json:"outputType,omitempty"`
+}
+
+// AnalyzeTextResponse is the response body of the text:analyze API of Azure AI Content Safety.
+type AnalyzeTextResponse struct {
+	// BlocklistsMatch is the list of the blocklist items matched by the text.
+	BlocklistsMatch []ContentSafetyBlocklistMatch `json:"blocklistsMatch,omitempty"`
+	// CategoriesAnalysis is the list of the analysis results per category.
+	CategoriesAnalysis []ContentSafetyCategoryAnalysis `json:"categoriesAnalysis"`
+}
+
+// ContentSafetyBlocklistMatch is a blocklist item matched by the analyzed text.
+type ContentSafetyBlocklistMatch struct {
+	BlocklistName   string `json:"blocklistName"`
+	BlocklistItemID string `json:"blocklistItemId"`
+	BlocklistItem   string `json:"blocklistItemText"`
+}
+
+// ContentSafetyCategoryAnalysis is the analysis result of a category.
+type ContentSafetyCategoryAnalysis struct {
+	// Category is the analyzed category.
+	Category ContentSafetyCategory `json:"category"`
+	// Severity is the severity of the category, from 0 to ContentSafetyMaxSeverity.
+	Severity int `json:"severity"`
+}
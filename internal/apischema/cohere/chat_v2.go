@@ -0,0 +1,281 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package cohere
+
+// ChatV2Request represents the request body for Cohere Chat API v2.
+// Docs: https://docs.cohere.com/reference/chat
+type ChatV2Request struct {
+	// Model identifier to use, e.g. "command-a-03-2025".
+	Model string `json:"model"`
+	// Messages is the list of the chat messages in chronological order.
+	Messages []ChatV2Message `json:"messages"`
+	// Stream enables the streaming of the response as server-sent events.
+	Stream bool `json:"stream,omitempty"`
+	// Tools is the list of the tools available to the model.
+	Tools []ChatV2Tool `json:"tools,omitempty"`
+	// StrictTools forces the tool calls to follow the tool definitions strictly.
+	StrictTools *bool `json:"strict_tools,omitempty"`
+	// ResponseFormat forces the model to output the given format.
+	ResponseFormat *ChatV2ResponseFormat `json:"response_format,omitempty"`
+	// MaxTokens is the maximum number of output tokens.
+	MaxTokens *int64 `json:"max_tokens,omitempty"`
+	// StopSequences stops the generation when any of the sequences is generated.
+	StopSequences []string `json:"stop_sequences,omitempty"`
+	// Temperature controls the randomness of the generation.
+	Temperature *float64 `json:"temperature,omitempty"`
+	// Seed makes the sampling deterministic on a best-effort basis.
+	Seed *int `json:"seed,omitempty"`
+	// FrequencyPenalty reduces the repetitiveness of the tokens proportionally to their frequency.
+	FrequencyPenalty *float32 `json:"frequency_penalty,omitempty"`
+	// PresencePenalty reduces the repetitiveness of the tokens that already appeared.
+	PresencePenalty *float32 `json:"presence_penalty,omitempty"`
+	// K limits the sampling to the k most likely tokens.
+	K *int `json:"k,omitempty"`
+	// P limits the sampling to the most likely tokens whose total probability mass is p.
+	P *float64 `json:"p,omitempty"`
+	// Logprobs returns the log probabilities of the generated tokens when true.
+	Logprobs *bool `json:"logprobs,omitempty"`
+	// ToolChoice controls whether the model is forced to call tools. Either "REQUIRED" or "NONE".
+	// The model decides by itself when unset.
+	ToolChoice ChatV2ToolChoice `json:"tool_choice,omitempty"`
+	// Thinking configures the reasoning of the models that support it.
+	Thinking *ChatV2Thinking `json:"thinking,omitempty"`
+}
+
+// ChatV2Role is the role of a [ChatV2Message].
+type ChatV2Role string
+
+const (
+	ChatV2RoleSystem    ChatV2Role = "system"
+	ChatV2RoleUser      ChatV2Role = "user"
+	ChatV2RoleAssistant ChatV2Role = "assistant"
+	ChatV2RoleTool      ChatV2Role = "tool"
+)
+
+// ChatV2Message is a message of [ChatV2Request] and [ChatV2Response].
+// Cohere accepts either a string or a list of content items for the content, and the list is always used here.
+type ChatV2Message struct {
+	// Role is the role of the author of the message.
+	Role ChatV2Role `json:"role"`
+	// Content is the list of the content items of the message.
+	Content []ChatV2Content `json:"content,omitempty"`
+	// ToolCalls is the list of the tool calls of an assistant message.
+	ToolCalls []ChatV2ToolCall `json:"tool_calls,omitempty"`
+	// ToolPlan is the reasoning of the model before calling the tools, only in an assistant message.
+	ToolPlan string `json:"tool_plan,omitempty"`
+	// ToolCallID is the ID of the tool call the tool message is the result of.
+	ToolCallID string `json:"tool_call_id,omitempty"`
+	// Citations is the list of the citations of an assistant message in the response.
+	Citations []ChatV2Citation `json:"citations,omitempty"`
+}
+
+// ChatV2ContentType is the type of [ChatV2Content].
+type ChatV2ContentType string
+
+const (
+	ChatV2ContentTypeText     ChatV2ContentType = "text"
+	ChatV2ContentTypeImageURL ChatV2ContentType = "image_url"
+	ChatV2ContentTypeThinking ChatV2ContentType = "thinking"
+)
+
+// ChatV2Content is a content item of [ChatV2Message].
+type ChatV2Content struct {
+	// Type is the type of the content item.
+	Type ChatV2ContentType `json:"type"`
+	// Text is set when the Type is "text".
+	Text string `json:"text,omitempty"`
+	// ImageURL is set when the Type is "image_url".
+	ImageURL *ChatV2ImageURL `json:"image_url,omitempty"`
+	// Thinking is set when the Type is "thinking".
+	Thinking string `json:"thinking,omitempty"`
+}
+
+// ChatV2ImageURL is the image of a [ChatV2Content], either a URL or a base64 data URI.
+type ChatV2ImageURL struct {
+	// URL is the URL or the data URI of the image.
+	URL string `json:"url"`
+	// Detail is the level of detail of the image processing, one of "auto", "low" or "high".
+	Detail string `json:"detail,omitempty"`
+}
+
+// ChatV2Tool is a tool of [ChatV2Request]. Only function tools are supported.
+type ChatV2Tool struct {
+	// Type is always "function".
+	Type string `json:"type"`
+	// Function is the definition of the function.
+	Function ChatV2Function `json:"function"`
+}
+
+// ChatV2Function is the function definition of [ChatV2Tool].
+type ChatV2Function struct {
+	// Name is the name of the function.
+	Name string `json:"name"`
+	// Description is the description of the function.
+	Description string `json:"description,omitempty"`
+	// Parameters is the JSON schema of the parameters of the function.
+	Parameters any `json:"parameters,omitempty"`
+}
+
+// ChatV2ToolCall is a tool call of an assistant [ChatV2Message].
+type ChatV2ToolCall struct {
+	// ID is the ID of the tool call.
+	ID string `json:"id,omitempty"`
+	// Type is always "function".
+	Type string `json:"type,omitempty"`
+	// Function is the function called by the model.
+	Function ChatV2ToolCallFunction `json:"function"`
+}
+
+// ChatV2ToolCallFunction is the function of [ChatV2ToolCall].
+type ChatV2ToolCallFunction struct {
+	// Name is the name of the function.
+	Name string `json:"name,omitempty"`
+	// Arguments is the JSON encoded arguments of the function.
+	Arguments string `json:"arguments,omitempty"`
+}
+
+// ChatV2ToolChoice is the tool_choice of [ChatV2Request].
+type ChatV2ToolChoice string
+
+const (
+	ChatV2ToolChoiceRequired ChatV2ToolChoice = "REQUIRED"
+	ChatV2ToolChoiceNone     ChatV2ToolChoice = "NONE"
+)
+
+// ChatV2ResponseFormat is the response_format of [ChatV2Request].
+type ChatV2ResponseFormat struct {
+	// Type is either "text" or "json_object".
+	Type string `json:"type"`
+	// JSONSchema is the JSON schema the output must follow, only with the "json_object" type.
+	JSONSchema any `json:"json_schema,omitempty"`
+}
+
+// ChatV2Thinking is the thinking configuration of [ChatV2Request].
+type ChatV2Thinking struct {
+	// Type is either "enabled" or "disabled".
+	Type string `json:"type"`
+	// TokenBudget is the maximum number of tokens the model can use for thinking.
+	TokenBudget *int64 `json:"token_budget,omitempty"`
+}
+
+// ChatV2FinishReason is the reason why the model stopped generating.
+type ChatV2FinishReason string
+
+const (
+	ChatV2FinishReasonComplete     ChatV2FinishReason = "COMPLETE"
+	ChatV2FinishReasonStopSequence ChatV2FinishReason = "STOP_SEQUENCE"
+	ChatV2FinishReasonMaxTokens    ChatV2FinishReason = "MAX_TOKENS"
+	ChatV2FinishReasonToolCall     ChatV2FinishReason = "TOOL_CALL"
+	ChatV2FinishReasonError        ChatV2FinishReason = "ERROR"
+	ChatV2FinishReasonTimeout      ChatV2FinishReason = "TIMEOUT"
+)
+
+// ChatV2Response represents the response from Cohere Chat API v2.
+// Docs: https://docs.cohere.com/reference/chat#response
+type ChatV2Response struct {
+	// ID is the unique ID of the response.
+	ID string `json:"id"`
+	// FinishReason is the reason why the model stopped generating.
+	FinishReason ChatV2FinishReason `json:"finish_reason"`
+	// Message is the message generated by the model.
+	Message ChatV2Message `json:"message"`
+	// Usage is the token usage of the request.
+	Usage *ChatV2Usage `json:"usage,omitempty"`
+}
+
+// ChatV2Usage is the usage of [ChatV2Response]. The billed units and tokens are the same as in the rerank meta.
+type ChatV2Usage struct {
+	// BilledUnits reports the billed resource usage for this request.
+	BilledUnits *RerankV2BilledUnits `json:"billed_units,omitempty"`
+	// Tokens provides the token usage breakdown for the request/response.
+	Tokens *RerankV2Tokens `json:"tokens,omitempty"`
+	// CachedTokens is the number of prompt tokens that hit the inference cache.
+	CachedTokens *float64 `json:"cached_tokens,omitempty"`
+}
+
+// ChatV2Citation is a citation of the generated text.
+// Docs: https://docs.cohere.com/docs/citations
+type ChatV2Citation struct {
+	// Start is the start index of the cited text in the content.
+	Start int `json:"start"`
+	// End is the end index of the cited text in the content, exclusive.
+	End int `json:"end"`
+	// Text is the cited text.
+	Text string `json:"text"`
+	// Sources is the list of the sources supporting the cited text.
+	Sources []ChatV2Source `json:"sources,omitempty"`
+	// ContentIndex is the index of the content item the citation refers to.
+	ContentIndex int `json:"content_index,omitempty"`
+	// Type is where the citation is, e.g. "TEXT_CONTENT" or "PLAN".
+	Type string `json:"type,omitempty"`
+}
+
+// ChatV2Source is a source of [ChatV2Citation].
+type ChatV2Source struct {
+	// Type is either "tool" or "document".
+	Type string `json:"type"`
+	// ID is the ID of the tool call or of the document.
+	ID string `json:"id,omitempty"`
+	// ToolOutput is the output of the tool when the Type is "tool".
+	ToolOutput map[string]any `json:"tool_output,omitempty"`
+	// Document is the document when the Type is "document".
+	Document map[string]any `json:"document,omitempty"`
+}
+
+// ChatV2StreamEventType is the type of [ChatV2StreamEvent].
+type ChatV2StreamEventType string
+
+const (
+	ChatV2StreamEventTypeMessageStart  ChatV2StreamEventType = "message-start"
+	ChatV2StreamEventTypeContentStart  ChatV2StreamEventType = "content-start"
+	ChatV2StreamEventTypeContentDelta  ChatV2StreamEventType = "content-delta"
+	ChatV2StreamEventTypeContentEnd    ChatV2StreamEventType = "content-end"
+	ChatV2StreamEventTypeToolPlanDelta ChatV2StreamEventType = "tool-plan-delta"
+	ChatV2StreamEventTypeToolCallStart ChatV2StreamEventType = "tool-call-start"
+	ChatV2StreamEventTypeToolCallDelta ChatV2StreamEventType = "tool-call-delta"
+	ChatV2StreamEventTypeToolCallEnd   ChatV2StreamEventType = "tool-call-end"
+	ChatV2StreamEventTypeCitationStart ChatV2StreamEventType = "citation-start"
+	ChatV2StreamEventTypeCitationEnd   ChatV2StreamEventType = "citation-end"
+	ChatV2StreamEventTypeMessageEnd    ChatV2StreamEventType = "message-end"
+)
+
+// ChatV2StreamEvent is a server-sent event of the streaming Cohere Chat API v2.
+// Docs: https://docs.cohere.com/reference/chat-stream
+type ChatV2StreamEvent struct {
+	// Type is the type of the event.
+	Type ChatV2StreamEventType `json:"type"`
+	// ID is the ID of the response, only in the message-start event.
+	ID string `json:"id,omitempty"`
+	// Index is the index of the content item, tool call or citation the event refers to.
+	Index int `json:"index,omitempty"`
+	// Delta is the payload of the event.
+	Delta *ChatV2StreamDelta `json:"delta,omitempty"`
+}
+
+// ChatV2StreamDelta is the delta of [ChatV2StreamEvent].
+type ChatV2StreamDelta struct {
+	// Message is the partial message.
+	Message *ChatV2StreamDeltaMessage `json:"message,omitempty"`
+	// FinishReason is set in the message-end event.
+	FinishReason ChatV2FinishReason `json:"finish_reason,omitempty"`
+	// Usage is set in the message-end event.
+	Usage *ChatV2Usage `json:"usage,omitempty"`
+}
+
+// ChatV2StreamDeltaMessage is the partial message of [ChatV2StreamDelta].
+// Unlike [ChatV2Message], the content, tool calls and citations are single objects.
+type ChatV2StreamDeltaMessage struct {
+	// Role is set in the message-start event.
+	Role ChatV2Role `json:"role,omitempty"`
+	// Content is set in the content-start and content-delta events.
+	Content *ChatV2Content `json:"content,omitempty"`
+	// ToolPlan is set in the tool-plan-delta event.
+	ToolPlan string `json:"tool_plan,omitempty"`
+	// ToolCalls is set in the tool-call-start and tool-call-delta events.
+	ToolCalls *ChatV2ToolCall `json:"tool_calls,omitempty"`
+	// Citations is set in the citation-start event.
+	Citations *ChatV2Citation `json:"citations,omitempty"`
+}
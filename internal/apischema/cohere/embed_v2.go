@@ -0,0 +1,91 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package cohere
+
+// EmbedV2InputType is the input_type of [EmbedV2Request].
+type EmbedV2InputType string
+
+const (
+	EmbedV2InputTypeSearchDocument EmbedV2InputType = "search_document"
+	EmbedV2InputTypeSearchQuery    EmbedV2InputType = "search_query"
+	EmbedV2InputTypeClassification EmbedV2InputType = "classification"
+	EmbedV2InputTypeClustering     EmbedV2InputType = "clustering"
+	EmbedV2InputTypeImage          EmbedV2InputType = "image"
+)
+
+// EmbedV2EmbeddingType is an embedding type of [EmbedV2Request].
+type EmbedV2EmbeddingType string
+
+const (
+	EmbedV2EmbeddingTypeFloat   EmbedV2EmbeddingType = "float"
+	EmbedV2EmbeddingTypeInt8    EmbedV2EmbeddingType = "int8"
+	EmbedV2EmbeddingTypeUint8   EmbedV2EmbeddingType = "uint8"
+	EmbedV2EmbeddingTypeBinary  EmbedV2EmbeddingType = "binary"
+	EmbedV2EmbeddingTypeUbinary EmbedV2EmbeddingType = "ubinary"
+	EmbedV2EmbeddingTypeBase64  EmbedV2EmbeddingType = "base64"
+)
+
+// EmbedV2Request represents the request body for Cohere Embed API v2.
+// Docs: https://docs.cohere.com/reference/embed
+type EmbedV2Request struct {
+	// Model identifier to use, e.g. "embed-v4.0".
+	Model string `json:"model"`
+	// InputType is the type of the input, required by the embed v3 and newer models.
+	InputType EmbedV2InputType `json:"input_type"`
+	// Texts is the list of the texts to embed.
+	Texts []string `json:"texts,omitempty"`
+	// Inputs is the list of the multimodal inputs to embed, each of which is embedded into a single embedding.
+	// Only supported by the embed v4 and newer models. Mutually exclusive with Texts.
+	Inputs []EmbedV2Input `json:"inputs,omitempty"`
+	// EmbeddingTypes is the list of the embedding types to return. Defaults to float.
+	EmbeddingTypes []EmbedV2EmbeddingType `json:"embedding_types,omitempty"`
+	// OutputDimension is the number of dimensions of the embeddings, only supported by the embed v4 and newer models.
+	OutputDimension *int `json:"output_dimension,omitempty"`
+	// Truncate specifies how the inputs longer than the maximum token length are handled, one of "NONE", "START" or "END".
+	Truncate string `json:"truncate,omitempty"`
+}
+
+// EmbedV2Input is a multimodal input of [EmbedV2Request].
+type EmbedV2Input struct {
+	Content []EmbedV2Content `json:"content"`
+}
+
+// EmbedV2Content is a text or image content of [EmbedV2Input].
+type EmbedV2Content struct {
+	// Type is either "text" or "image_url".
+	Type     string               `json:"type"`
+	Text     string               `json:"text,omitempty"`
+	ImageURL *EmbedV2ContentImage `json:"image_url,omitempty"`
+}
+
+// EmbedV2ContentImage is the image of [EmbedV2Content].
+type EmbedV2ContentImage struct {
+	// URL is the data URL of the image.
+	URL string `json:"url"`
+}
+
+// EmbedV2Response represents the response from Cohere Embed API v2.
+// Docs: https://docs.cohere.com/reference/embed#response
+type EmbedV2Response struct {
+	// ID is the unique ID of the response.
+	ID string `json:"id"`
+	// Embeddings holds the embeddings of each requested type, in the same order as the inputs.
+	Embeddings EmbedV2Embeddings `json:"embeddings"`
+	// Texts is the list of the embedded texts.
+	Texts []string `json:"texts,omitempty"`
+	// Meta contains the metadata of the response, the same as in the rerank response.
+	Meta *RerankV2Meta `json:"meta,omitempty"`
+}
+
+// EmbedV2Embeddings holds the embeddings of [EmbedV2Response] by embedding type.
+type EmbedV2Embeddings struct {
+	Float   [][]float64 `json:"float,omitempty"`
+	Int8    [][]float64 `json:"int8,omitempty"`
+	Uint8   [][]float64 `json:"uint8,omitempty"`
+	Binary  [][]float64 `json:"binary,omitempty"`
+	Ubinary [][]float64 `json:"ubinary,omitempty"`
+	Base64  []string    `json:"base64,omitempty"`
+}
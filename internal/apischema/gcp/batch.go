@@ -0,0 +1,107 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package gcp
+
+import "time"
+
+// JobState is the state of a Vertex AI job.
+// https://cloud.google.com/vertex-ai/docs/reference/rest/v1/JobState
+type JobState string
+
+const (
+	JobStateQueued             JobState = "JOB_STATE_QUEUED"
+	JobStatePending            JobState = "JOB_STATE_PENDING"
+	JobStateRunning            JobState = "JOB_STATE_RUNNING"
+	JobStateSucceeded          JobState = "JOB_STATE_SUCCEEDED"
+	JobStateFailed             JobState = "JOB_STATE_FAILED"
+	JobStateCancelling         JobState = "JOB_STATE_CANCELLING"
+	JobStateCancelled          JobState = "JOB_STATE_CANCELLED"
+	JobStatePaused             JobState = "JOB_STATE_PAUSED"
+	JobStateExpired            JobState = "JOB_STATE_EXPIRED"
+	JobStateUpdating           JobState = "JOB_STATE_UPDATING"
+	JobStatePartiallySucceeded JobState = "JOB_STATE_PARTIALLY_SUCCEEDED"
+)
+
+// BatchPredictionJob is a Vertex AI batch prediction job, used as both the request and the response body.
+// https://cloud.google.com/vertex-ai/docs/reference/rest/v1/projects.locations.batchPredictionJobs
+type BatchPredictionJob struct {
+	// Name is the resource name of the job, e.g. "projects/{project}/locations/{location}/batchPredictionJobs/{id}".
+	Name string `json:"name,omitempty"`
+	// DisplayName is the user-defined name of the job.
+	DisplayName string `json:"displayName"`
+	// Model is the name of the model, e.g. "publishers/google/models/gemini-2.0-flash-001".
+	Model string `json:"model"`
+	// InputConfig is the location of the input instances.
+	InputConfig BatchPredictionJobInputConfig `json:"inputConfig"`
+	// OutputConfig is the location where the predictions are written.
+	OutputConfig BatchPredictionJobOutputConfig `json:"outputConfig"`
+	// Labels is the set of key-value pairs attached to the job.
+	Labels map[string]string `json:"labels,omitempty"`
+	// State is the state of the job. Output only.
+	State JobState `json:"state,omitempty"`
+	// Error is the error of the job when it failed. Output only.
+	Error *BatchPredictionJobError `json:"error,omitempty"`
+	// OutputInfo is the information about the output of the job. Output only.
+	OutputInfo *BatchPredictionJobOutputInfo `json:"outputInfo,omitempty"`
+	// CompletionStats is the statistics of the completed predictions. Output only.
+	CompletionStats *BatchPredictionJobCompletionStats `json:"completionStats,omitempty"`
+	// CreateTime is the time when the job was created. Output only.
+	CreateTime *time.Time `json:"createTime,omitempty"`
+	// StartTime is the time when the job entered the running state. Output only.
+	StartTime *time.Time `json:"startTime,omitempty"`
+	// EndTime is the time when the job entered a final state. Output only.
+	EndTime *time.Time `json:"endTime,omitempty"`
+}
+
+// BatchPredictionJobInputConfig is the input configuration of a [BatchPredictionJob].
+type BatchPredictionJobInputConfig struct {
+	// InstancesFormat is the format of the input instances, e.g. "jsonl".
+	InstancesFormat string `json:"instancesFormat"`
+	// GCSSource is the Cloud Storage location of the input instances.
+	GCSSource *GCSSource `json:"gcsSource,omitempty"`
+}
+
+// BatchPredictionJobOutputConfig is the output configuration of a [BatchPredictionJob].
+type BatchPredictionJobOutputConfig struct {
+	// PredictionsFormat is the format of the predictions, e.g. "jsonl".
+	PredictionsFormat string `json:"predictionsFormat"`
+	// GCSDestination is the Cloud Storage location where the predictions are written.
+	GCSDestination *GCSDestination `json:"gcsDestination,omitempty"`
+}
+
+// GCSSource is a Cloud Storage location of input data.
+type GCSSource struct {
+	// URIs is the list of the Cloud Storage URIs of the input files, e.g. "gs://bucket/input.jsonl".
+	URIs []string `json:"uris"`
+}
+
+// GCSDestination is a Cloud Storage location of output data.
+type GCSDestination struct {
+	// OutputURIPrefix is the Cloud Storage URI of the directory where the output is written.
+	OutputURIPrefix string `json:"outputUriPrefix"`
+}
+
+// BatchPredictionJobError is the error of a [BatchPredictionJob].
+type BatchPredictionJobError struct {
+	// Code is the status code.
+	Code int `json:"code,omitempty"`
+	// Message is the error message.
+	Message string `json:"message,omitempty"`
+}
+
+// BatchPredictionJobOutputInfo is the information about the output of a [BatchPredictionJob].
+type BatchPredictionJobOutputInfo struct {
+	// GCSOutputDirectory is the Cloud Storage directory where the predictions are written.
+	GCSOutputDirectory string `json:"gcsOutputDirectory,omitempty"`
+}
+
+// BatchPredictionJobCompletionStats is the statistics of the completed predictions of a [BatchPredictionJob].
+// The counts are int64 values, which are encoded as strings in JSON.
+type BatchPredictionJobCompletionStats struct {
+	SuccessfulCount int64 `json:"successfulCount,omitempty,string"`
+	FailedCount     int64 `json:"failedCount,omitempty,string"`
+	IncompleteCount int64 `json:"incompleteCount,omitempty,string"`
+}
@@ -9,6 +9,7 @@ import (
 	"google.golang.org/genai"
 
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/json"
 )
 
 type GenerateContentRequest struct {
@@ -39,6 +40,41 @@ type GenerateContentRequest struct {
 	//
 	// https://github.com/googleapis/go-genai/blob/6a8184fcaf8bf15f0c566616a7b356560309be9b/types.go#L1057
 	SafetySettings []*genai.SafetySetting `json:"safetySettings,omitempty"`
+
+	// Model is the model of the request. It is not part of the body since the Gemini API has the model in the
+	// request path, e.g. /v1beta/models/{model}:generateContent. It is set when the request is parsed from the path.
+	Model string `json:"-"`
+	// Stream is true when the request is to the streamGenerateContent method. Like Model, it is taken from the
+	// request path.
+	Stream bool `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+//
+// The Gemini API accepts both the snake_case and the lowerCamelCase field names, and the google-genai SDKs
+// send the latter, so the lowerCamelCase names of the fields tagged with snake_case are accepted as well.
+func (r *GenerateContentRequest) UnmarshalJSON(data []byte) error {
+	type alias GenerateContentRequest
+	var aux struct {
+		alias
+		CamelToolConfig        *genai.ToolConfig       `json:"toolConfig,omitempty"`
+		CamelGenerationConfig  *genai.GenerationConfig `json:"generationConfig,omitempty"`
+		CamelSystemInstruction *genai.Content          `json:"systemInstruction,omitempty"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*r = GenerateContentRequest(aux.alias)
+	if r.ToolConfig == nil {
+		r.ToolConfig = aux.CamelToolConfig
+	}
+	if r.GenerationConfig == nil {
+		r.GenerationConfig = aux.CamelGenerationConfig
+	}
+	if r.SystemInstruction == nil {
+		r.SystemInstruction = aux.CamelSystemInstruction
+	}
+	return nil
 }
 
 // https://docs.cloud.google.com/vertex-ai/generative-ai/docs/model-reference/text-embeddings-api#syntax
@@ -107,3 +143,159 @@ type Prediction struct {
 type PredictResponse struct {
 	Predictions []*Prediction `json:"predictions"`
 }
+
+// MultimodalEmbeddingPredictRequest is the request body of the predict method of the multimodalembedding models.
+// https://cloud.google.com/vertex-ai/generative-ai/docs/model-reference/multimodal-embeddings-api#request_body
+type MultimodalEmbeddingPredictRequest struct {
+	Instances  []*MultimodalEmbeddingInstance `json:"instances"`
+	Parameters *MultimodalEmbeddingParameters `json:"parameters,omitempty"`
+}
+
+// MultimodalEmbeddingInstance is an instance of [MultimodalEmbeddingPredictRequest]. The text and the image are
+// embedded separately into the same semantic space.
+type MultimodalEmbeddingInstance struct {
+	// Text is the text to embed.
+	Text string `json:"text,omitempty"`
+	// Image is the image to embed.
+	Image *MultimodalEmbeddingImage `json:"image,omitempty"`
+}
+
+// MultimodalEmbeddingImage is the image of [MultimodalEmbeddingInstance]. Either BytesBase64Encoded or GcsURI is set.
+type MultimodalEmbeddingImage struct {
+	BytesBase64Encoded string `json:"bytesBase64Encoded,omitempty"`
+	GcsURI             string `json:"gcsUri,omitempty"`
+	MimeType           string `json:"mimeType,omitempty"`
+}
+
+// MultimodalEmbeddingParameters is the configuration of [MultimodalEmbeddingPredictRequest].
+type MultimodalEmbeddingParameters struct {
+	// Dimension is the size of the embeddings, one of 128, 256, 512 or 1408 (default).
+	Dimension int `json:"dimension,omitempty"`
+}
+
+// MultimodalEmbeddingPredictResponse is the response body of the predict method of the multimodalembedding models.
+type MultimodalEmbeddingPredictResponse struct {
+	Predictions []*MultimodalEmbeddingPrediction `json:"predictions"`
+}
+
+// MultimodalEmbeddingPrediction is a prediction of [MultimodalEmbeddingPredictResponse], holding the embeddings
+// of the text and the image of the instance.
+type MultimodalEmbeddingPrediction struct {
+	TextEmbedding  []float64 `json:"textEmbedding,omitempty"`
+	ImageEmbedding []float64 `json:"imageEmbedding,omitempty"`
+}
+
+// ImagenPredictRequest is the request body of the predict method of the Imagen models.
+// https://cloud.google.com/vertex-ai/generative-ai/docs/model-reference/imagen-api#request_body
+type ImagenPredictRequest struct {
+	// Instances is the list of prompts. Imagen only accepts a single instance.
+	Instances []ImagenInstance `json:"instances"`
+	// Parameters is the image generation configuration.
+	Parameters ImagenParameters `json:"parameters"`
+}
+
+// ImagenInstance is an instance of [ImagenPredictRequest].
+type ImagenInstance struct {
+	// Prompt is the text prompt for the images.
+	Prompt string `json:"prompt"`
+}
+
+// ImagenParameters is the configuration of [ImagenPredictRequest].
+type ImagenParameters struct {
+	// SampleCount is the number of images to generate, from 1 to 4. Defaults to 4.
+	SampleCount int `json:"sampleCount,omitempty"`
+	// AspectRatio is the aspect ratio of the images. One of 1:1 (default), 3:4, 4:3, 9:16 or 16:9.
+	AspectRatio string `json:"aspectRatio,omitempty"`
+	// SampleImageSize is the resolution of the images. Either 1K (default) or 2K.
+	SampleImageSize string `json:"sampleImageSize,omitempty"`
+	// OutputOptions is the format of the images.
+	OutputOptions *ImagenOutputOptions `json:"outputOptions,omitempty"`
+}
+
+// ImagenOutputOptions is the format of the images generated by Imagen.
+type ImagenOutputOptions struct {
+	// MimeType is either image/png (default) or image/jpeg.
+	MimeType string `json:"mimeType,omitempty"`
+	// CompressionQuality is the quality of the image/jpeg images from 0 to 100. Defaults to 75.
+	CompressionQuality *int `json:"compressionQuality,omitempty"`
+}
+
+// ImagenPredictResponse is the response body of the predict method of the Imagen models.
+// https://cloud.google.com/vertex-ai/generative-ai/docs/model-reference/imagen-api#response_body
+type ImagenPredictResponse struct {
+	Predictions []ImagenPrediction `json:"predictions"`
+}
+
+// ImagenPrediction is a generated image of [ImagenPredictResponse].
+type ImagenPrediction struct {
+	// BytesBase64Encoded is the base64 encoded image. Empty if the image was filtered out.
+	BytesBase64Encoded string `json:"bytesBase64Encoded,omitempty"`
+	// MimeType is the type of the image.
+	MimeType string `json:"mimeType,omitempty"`
+	// Prompt is the enhanced prompt used to generate the image, if prompt enhancement is enabled.
+	Prompt string `json:"prompt,omitempty"`
+	// RaiFilteredReason is the reason why the image was filtered out by the responsible AI filters.
+	RaiFilteredReason string `json:"raiFilteredReason,omitempty"`
+}
+
+// RankRequest is the request body of the rank method of the Vertex AI ranking API.
+// https://cloud.google.com/generative-ai-app-builder/docs/reference/rest/v1/projects.locations.rankingConfigs/rank
+type RankRequest struct {
+	// Model is the ranking model, e.g. semantic-ranker-default-004. The default model is used when empty.
+	Model string `json:"model,omitempty"`
+	// Query is the query to rank the records against.
+	Query string `json:"query"`
+	// Records is the list of the records to rank.
+	Records []RankingRecord `json:"records"`
+	// TopN is the number of the results to return. All the records are returned when zero.
+	TopN int `json:"topN,omitempty"`
+	// IgnoreRecordDetailsInResponse returns only the record IDs and scores when true.
+	IgnoreRecordDetailsInResponse bool `json:"ignoreRecordDetailsInResponse,omitempty"`
+}
+
+// RankingRecord is a record of [RankRequest] and [RankResponse].
+type RankingRecord struct {
+	// ID is the unique ID of the record.
+	ID string `json:"id"`
+	// Title is the title of the record.
+	Title string `json:"title,omitempty"`
+	// Content is the content of the record.
+	Content string `json:"content,omitempty"`
+	// Score is the relevance score of the record, only set in the response.
+	Score float64 `json:"score,omitempty"`
+}
+
+// RankResponse is the response body of the rank method of the Vertex AI ranking API.
+type RankResponse struct {
+	// Records is the list of the records ordered by the score in descending order.
+	Records []RankingRecord `json:"records"`
+}
+
+// BatchEmbedContentsRequest is the request body of the batchEmbedContents method of the Gemini Developer API.
+// https://ai.google.dev/api/embeddings#method:-models.batchembedcontents
+type BatchEmbedContentsRequest struct {
+	// Requests is the list of the embedding requests, one per input text.
+	Requests []*EmbedContentRequest `json:"requests"`
+}
+
+// EmbedContentRequest is a request of [BatchEmbedContentsRequest].
+// https://ai.google.dev/api/embeddings#EmbedContentRequest
+type EmbedContentRequest struct {
+	// Model is the resource name of the model in the form of "models/{model}".
+	Model string `json:"model"`
+	// Content is the content to embed. Only the text parts are counted.
+	Content *genai.Content `json:"content"`
+	// TaskType is the type of the task the embeddings will be used for.
+	TaskType openai.EmbeddingTaskType `json:"taskType,omitempty"`
+	// Title is the title of the text. Only applicable when the TaskType is RETRIEVAL_DOCUMENT.
+	Title string `json:"title,omitempty"`
+	// OutputDimensionality is the size of the output embedding. The embedding is truncated when set.
+	OutputDimensionality int `json:"outputDimensionality,omitempty"`
+}
+
+// BatchEmbedContentsResponse is the response body of the batchEmbedContents method of the Gemini Developer API.
+// https://ai.google.dev/api/embeddings#response-body_1
+type BatchEmbedContentsResponse struct {
+	// Embeddings is the list of the embeddings in the same order as the requests.
+	Embeddings []*ContentEmbedding `json:"embeddings"`
+}
@@ -0,0 +1,233 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package openai
+
+const (
+	// FileIDPrefix is the prefix of the IDs of the files.
+	FileIDPrefix = "file-"
+	// BatchIDPrefix is the prefix of the IDs of the batches.
+	BatchIDPrefix = "batch_"
+)
+
+// FileOperation is the operation of a [FileRequest].
+type FileOperation string
+
+const (
+	// FileOperationUpload is POST /v1/files.
+	FileOperationUpload FileOperation = "upload"
+	// FileOperationRetrieve is GET /v1/files/{file_id}.
+	FileOperationRetrieve FileOperation = "retrieve"
+	// FileOperationContent is GET /v1/files/{file_id}/content.
+	FileOperationContent FileOperation = "content"
+	// FileOperationDelete is DELETE /v1/files/{file_id}.
+	FileOperationDelete FileOperation = "delete"
+)
+
+// FileRequest represents a request to the Files API. The upload is a multipart/form-data request with the file
+// and its purpose, while the other operations only have the file ID in the path.
+// Docs: https://platform.openai.com/docs/api-reference/files
+type FileRequest struct {
+	// Purpose is the intended purpose of the uploaded file, e.g. "batch".
+	Purpose string `json:"purpose,omitempty"`
+	// FileName is the file name of the uploaded file.
+	FileName string `json:"file_name,omitempty"`
+	// FileSize is the size of the uploaded file in bytes.
+	FileSize int `json:"file_size,omitempty"`
+	// Model is the model of the requests in the uploaded batch input file, or the model the file ID is scoped to.
+	Model string `json:"model,omitempty"`
+
+	// Operation is the operation of the request, derived from the method and the path.
+	Operation FileOperation `json:"-"`
+	// FileID is the ID of the file assigned by the backend, derived from the path. Empty for the upload.
+	FileID string `json:"-"`
+}
+
+// FileObject represents a file in the Files API.
+// Docs: https://platform.openai.com/docs/api-reference/files/object
+type FileObject struct {
+	// ID is the file identifier, which can be referenced in the API endpoints.
+	ID string `json:"id"`
+	// Object is the object type, which is always "file".
+	Object string `json:"object"`
+	// Bytes is the size of the file in bytes.
+	Bytes int64 `json:"bytes"`
+	// CreatedAt is the Unix timestamp (in seconds) for when the file was created.
+	CreatedAt int64 `json:"created_at"`
+	// ExpiresAt is the Unix timestamp (in seconds) for when the file will expire.
+	ExpiresAt *int64 `json:"expires_at,omitempty"`
+	// Filename is the name of the file.
+	Filename string `json:"filename"`
+	// Purpose is the intended purpose of the file, e.g. "batch" or "batch_output".
+	Purpose string `json:"purpose"`
+}
+
+// FileDeleted is the result of DELETE /v1/files/{file_id}.
+// Docs: https://platform.openai.com/docs/api-reference/files/delete
+type FileDeleted struct {
+	// ID is the ID of the deleted file.
+	ID string `json:"id"`
+	// Object is the object type, which is always "file".
+	Object string `json:"object"`
+	// Deleted is whether the file was deleted.
+	Deleted bool `json:"deleted"`
+}
+
+// BatchInputLine is a line of the JSONL batch input file.
+// Docs: https://platform.openai.com/docs/api-reference/batch/request-input
+type BatchInputLine struct {
+	// CustomID is a developer-provided per-request ID used to match the outputs to the inputs.
+	CustomID string `json:"custom_id"`
+	// Method is the HTTP method of the request, which is always "POST".
+	Method string `json:"method"`
+	// URL is the relative URL of the endpoint, e.g. "/v1/chat/completions".
+	URL string `json:"url"`
+	// Body is the request body. Only the model is needed by the gateway.
+	Body struct {
+		Model string `json:"model"`
+	} `json:"body"`
+}
+
+// BatchOperation is the operation of a [BatchRequest].
+type BatchOperation string
+
+const (
+	// BatchOperationCreate is POST /v1/batches.
+	BatchOperationCreate BatchOperation = "create"
+	// BatchOperationRetrieve is GET /v1/batches/{batch_id}.
+	BatchOperationRetrieve BatchOperation = "retrieve"
+	// BatchOperationCancel is POST /v1/batches/{batch_id}/cancel.
+	BatchOperationCancel BatchOperation = "cancel"
+)
+
+// BatchRequest represents a request to the Batch API. The creation has the body below, while the other
+// operations only have the batch ID in the path.
+// Docs: https://platform.openai.com/docs/api-reference/batch/create
+type BatchRequest struct {
+	// InputFileID is the ID of the uploaded JSONL file with the requests of the batch.
+	InputFileID string `json:"input_file_id,omitempty"`
+	// Endpoint is the endpoint to be used for all the requests in the batch, e.g. "/v1/chat/completions".
+	Endpoint string `json:"endpoint,omitempty"`
+	// CompletionWindow is the time frame within which the batch should be processed. Currently only "24h".
+	CompletionWindow string `json:"completion_window,omitempty"`
+	// Metadata is the set of key-value pairs attached to the batch.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Model is the model of the requests in the batch, derived from the input file ID or the batch ID.
+	Model string `json:"model,omitempty"`
+
+	// Operation is the operation of the request, derived from the method and the path.
+	Operation BatchOperation `json:"-"`
+	// BatchID is the ID of the batch assigned by the backend, derived from the path. Empty for the creation.
+	BatchID string `json:"-"`
+}
+
+// BatchStatus is the status of a [Batch].
+type BatchStatus string
+
+const (
+	BatchStatusValidating BatchStatus = "validating"
+	BatchStatusFailed     BatchStatus = "failed"
+	BatchStatusInProgress BatchStatus = "in_progress"
+	BatchStatusFinalizing BatchStatus = "finalizing"
+	BatchStatusCompleted  BatchStatus = "completed"
+	BatchStatusExpired    BatchStatus = "expired"
+	BatchStatusCancelling BatchStatus = "cancelling"
+	BatchStatusCancelled  BatchStatus = "cancelled"
+)
+
+// Batch represents a batch in the Batch API.
+// Docs: https://platform.openai.com/docs/api-reference/batch/object
+type Batch struct {
+	// ID is the batch identifier.
+	ID string `json:"id"`
+	// Object is the object type, which is always "batch".
+	Object string `json:"object"`
+	// Endpoint is the endpoint used by the batch.
+	Endpoint string `json:"endpoint"`
+	// Model is the model of the batch.
+	Model string `json:"model,omitempty"`
+	// Errors is the list of the errors of the batch, set when the batch failed.
+	Errors *BatchErrors `json:"errors,omitempty"`
+	// InputFileID is the ID of the input file for the batch.
+	InputFileID string `json:"input_file_id"`
+	// CompletionWindow is the time frame within which the batch should be processed.
+	CompletionWindow string `json:"completion_window"`
+	// Status is the current status of the batch.
+	Status BatchStatus `json:"status"`
+	// OutputFileID is the ID of the file containing the outputs of the successfully executed requests.
+	OutputFileID string `json:"output_file_id,omitempty"`
+	// ErrorFileID is the ID of the file containing the outputs of the requests with errors.
+	ErrorFileID string `json:"error_file_id,omitempty"`
+	// CreatedAt is the Unix timestamp (in seconds) for when the batch was created.
+	CreatedAt int64 `json:"created_at"`
+	// InProgressAt is the Unix timestamp (in seconds) for when the batch started processing.
+	InProgressAt *int64 `json:"in_progress_at,omitempty"`
+	// ExpiresAt is the Unix timestamp (in seconds) for when the batch will expire.
+	ExpiresAt *int64 `json:"expires_at,omitempty"`
+	// CompletedAt is the Unix timestamp (in seconds) for when the batch was completed.
+	CompletedAt *int64 `json:"completed_at,omitempty"`
+	// FailedAt is the Unix timestamp (in seconds) for when the batch failed.
+	FailedAt *int64 `json:"failed_at,omitempty"`
+	// CancelledAt is the Unix timestamp (in seconds) for when the batch was cancelled.
+	CancelledAt *int64 `json:"cancelled_at,omitempty"`
+	// RequestCounts is the request counts for the different statuses within the batch.
+	RequestCounts *BatchRequestCounts `json:"request_counts,omitempty"`
+	// Usage is the token usage of the batch, only set once the batch is completed.
+	Usage *BatchUsage `json:"usage,omitempty"`
+	// Metadata is the set of key-value pairs attached to the batch.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// BatchErrors is the list of the errors of a [Batch].
+type BatchErrors struct {
+	// Object is the object type, which is always "list".
+	Object string `json:"object"`
+	// Data is the list of the errors.
+	Data []BatchError `json:"data"`
+}
+
+// BatchError is an error of a [Batch].
+type BatchError struct {
+	// Code is an error code identifying the error type.
+	Code string `json:"code,omitempty"`
+	// Message is a human-readable message providing more details about the error.
+	Message string `json:"message"`
+}
+
+// BatchRequestCounts is the request counts of a [Batch].
+type BatchRequestCounts struct {
+	// Total is the total number of requests in the batch.
+	Total int `json:"total"`
+	// Completed is the number of requests that have been completed successfully.
+	Completed int `json:"completed"`
+	// Failed is the number of requests that have failed.
+	Failed int `json:"failed"`
+}
+
+// BatchUsage is the token usage of a [Batch].
+type BatchUsage struct {
+	// InputTokens is the number of input tokens of all the requests of the batch.
+	InputTokens int `json:"input_tokens"`
+	// InputTokensDetails is the breakdown of the input tokens.
+	InputTokensDetails *BatchInputTokensDetails `json:"input_tokens_details,omitempty"`
+	// OutputTokens is the number of output tokens of all the requests of the batch.
+	OutputTokens int `json:"output_tokens"`
+	// OutputTokensDetails is the breakdown of the output tokens.
+	OutputTokensDetails *BatchOutputTokensDetails `json:"output_tokens_details,omitempty"`
+	// TotalTokens is the total number of tokens of all the requests of the batch.
+	TotalTokens int `json:"total_tokens"`
+}
+
+// BatchInputTokensDetails is the breakdown of the input tokens of [BatchUsage].
+type BatchInputTokensDetails struct {
+	// CachedTokens is the number of tokens retrieved from the cache.
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// BatchOutputTokensDetails is the breakdown of the output tokens of [BatchUsage].
+type BatchOutputTokensDetails struct {
+	// ReasoningTokens is the number of reasoning tokens.
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
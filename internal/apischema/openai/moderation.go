@@ -0,0 +1,135 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package openai
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// ModerationModelOmniLatest is the default model of the moderations endpoint.
+const ModerationModelOmniLatest = "omni-moderation-latest"
+
+// Moderation categories of the OpenAI moderation models.
+// https://platform.openai.com/docs/guides/moderation#content-classifications
+const (
+	ModerationCategoryHarassment            = "harassment"
+	ModerationCategoryHarassmentThreatening = "harassment/threatening"
+	ModerationCategoryHate                  = "hate"
+	ModerationCategoryHateThreatening       = "hate/threatening"
+	ModerationCategoryIllicit               = "illicit"
+	ModerationCategoryIllicitViolent        = "illicit/violent"
+	ModerationCategorySelfHarm              = "self-harm"
+	ModerationCategorySelfHarmIntent        = "self-harm/intent"
+	ModerationCategorySelfHarmInstructions  = "self-harm/instructions"
+	ModerationCategorySexual                = "sexual"
+	ModerationCategorySexualMinors          = "sexual/minors"
+	ModerationCategoryViolence              = "violence"
+	ModerationCategoryViolenceGraphic       = "violence/graphic"
+)
+
+// ModerationCategories is the list of all the moderation categories in the order of the OpenAI documentation.
+var ModerationCategories = []string{
+	ModerationCategoryHarassment,
+	ModerationCategoryHarassmentThreatening,
+	ModerationCategoryHate,
+	ModerationCategoryHateThreatening,
+	ModerationCategoryIllicit,
+	ModerationCategoryIllicitViolent,
+	ModerationCategorySelfHarm,
+	ModerationCategorySelfHarmIntent,
+	ModerationCategorySelfHarmInstructions,
+	ModerationCategorySexual,
+	ModerationCategorySexualMinors,
+	ModerationCategoryViolence,
+	ModerationCategoryViolenceGraphic,
+}
+
+// IsModerationModel returns true if the model is one of the OpenAI moderation models.
+func IsModerationModel(model string) bool {
+	return strings.HasPrefix(model, "omni-moderation") || strings.HasPrefix(model, "text-moderation")
+}
+
+// ModerationRequest represents a request to the /v1/moderations endpoint.
+// https://platform.openai.com/docs/api-reference/moderations/create
+type ModerationRequest struct {
+	// Input is the input to classify, which is a string, an array of strings or an array of multi-modal inputs.
+	Input ModerationInput `json:"input"`
+	// Model is the moderation model to use. Defaults to ModerationModelOmniLatest.
+	Model string `json:"model,omitempty"`
+}
+
+// ModerationInput is the ModerationRequest.Input type. The Value is either a string, []string or
+// []ModerationMultiModalInput.
+type ModerationInput struct {
+	Value any
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (m *ModerationInput) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		m.Value = str
+		return nil
+	}
+	var strs []string
+	if err := json.Unmarshal(data, &strs); err == nil {
+		m.Value = strs
+		return nil
+	}
+	var parts []ModerationMultiModalInput
+	if err := json.Unmarshal(data, &parts); err == nil {
+		m.Value = parts
+		return nil
+	}
+	return errors.New("input must be a string, an array of strings or an array of multi-modal inputs")
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (m ModerationInput) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Value)
+}
+
+// ModerationMultiModalInput is a text or an image input of the moderation models that support multi-modal inputs.
+type ModerationMultiModalInput struct {
+	// Type is either "text" or "image_url".
+	Type string `json:"type"`
+	// Text is the text to classify. Set when the Type is "text".
+	Text string `json:"text,omitempty"`
+	// ImageURL is the image to classify. Set when the Type is "image_url".
+	ImageURL *ModerationImageURL `json:"image_url,omitempty"`
+}
+
+// ModerationImageURL is the URL or the base64 encoded data URL of an image to classify.
+type ModerationImageURL struct {
+	URL string `json:"url"`
+}
+
+// ModerationResponse represents the response of the /v1/moderations endpoint.
+// https://platform.openai.com/docs/api-reference/moderations/object
+type ModerationResponse struct {
+	// ID is the unique identifier of the moderation request.
+	ID string `json:"id"`
+	// Model is the model used to generate the moderation results.
+	Model string `json:"model"`
+	// Results is the list of the moderation results, one per input.
+	Results []ModerationResult `json:"results"`
+}
+
+// ModerationResult is the moderation result of an input.
+type ModerationResult struct {
+	// Flagged is true if the input is classified as violating any of the categories.
+	Flagged bool `json:"flagged"`
+	// Categories is the map of the categories to whether the input is classified as violating them.
+	Categories map[string]bool `json:"categories"`
+	// CategoryScores is the map of the categories to the scores between 0 and 1 predicted by the model.
+	CategoryScores map[string]float64 `json:"category_scores"`
+	// CategoryAppliedInputTypes is the map of the categories to the input types, "text" or "image", the score
+	// applies to. Only returned by the multi-modal moderation models.
+	CategoryAppliedInputTypes map[string][]string `json:"category_applied_input_types,omitempty"`
+}
@@ -311,10 +311,11 @@ func (c ContentUnion) MarshalJSON() ([]byte, error) {
 	return json.Marshal(c.Value)
 }
 
-// EmbeddingContent represents content that can be either a string or an array of strings.
-// This allows embedding inputs to specify multiple texts with a shared task_type.
+// EmbeddingContent represents content that can be either a string, an array of strings or an array of content parts.
+// This allows embedding inputs to specify multiple texts with a shared task_type, or a single multimodal input
+// made of text and image parts.
 type EmbeddingContent struct {
-	Value any // string or []string
+	Value any // string, []string or []EmbeddingContentPart
 }
 
 func (c *EmbeddingContent) UnmarshalJSON(data []byte) error {
@@ -330,7 +331,24 @@ func (c *EmbeddingContent) UnmarshalJSON(data []byte) error {
 		c.Value = strs
 		return nil
 	}
-	return fmt.Errorf("content must be string or array of strings")
+	// Try array of content parts
+	var parts []EmbeddingContentPart
+	if err := json.Unmarshal(data, &parts); err == nil {
+		for _, part := range parts {
+			switch part.Type {
+			case EmbeddingContentPartTypeText:
+			case EmbeddingContentPartTypeImageURL:
+				if part.ImageURL == nil || part.ImageURL.URL == "" {
+					return fmt.Errorf("image_url content part must have a url")
+				}
+			default:
+				return fmt.Errorf("unsupported content part type %q", part.Type)
+			}
+		}
+		c.Value = parts
+		return nil
+	}
+	return fmt.Errorf("content must be string, array of strings or array of content parts")
 }
 
 func (c EmbeddingContent) MarshalJSON() ([]byte, error) {
@@ -344,14 +362,33 @@ func (c EmbeddingContent) IsEmpty() bool {
 		return v == ""
 	case []string:
 		return len(v) == 0
+	case []EmbeddingContentPart:
+		return len(v) == 0
 	default:
 		return true
 	}
 }
 
+// EmbeddingContentPartType is the type of [EmbeddingContentPart].
+type EmbeddingContentPartType string
+
+const (
+	EmbeddingContentPartTypeText     EmbeddingContentPartType = "text"
+	EmbeddingContentPartTypeImageURL EmbeddingContentPartType = "image_url"
+)
+
+// EmbeddingContentPart is a part of a multimodal embedding input, in the same shape as the content parts of
+// the chat completion messages. The image is either a base64 encoded data URL or a URL of the provider storage,
+// e.g. gs:// for GCP Vertex AI.
+type EmbeddingContentPart struct {
+	Type     EmbeddingContentPartType                     `json:"type"`
+	Text     string                                       `json:"text,omitempty"`
+	ImageURL *ChatCompletionContentPartImageImageURLParam `json:"image_url,omitempty"`
+}
+
 // EmbeddingInputItem represents a single embedding input with optional metadata
 type EmbeddingInputItem struct {
-	Content  EmbeddingContent  `json:"content"`             // The actual text content (string or []string)
+	Content  EmbeddingContent  `json:"content"`             // The actual content (string, []string or []EmbeddingContentPart)
 	TaskType EmbeddingTaskType `json:"task_type,omitempty"` // Optional task type
 	Title    string            `json:"title,omitempty"`     // Optional title
 }
@@ -1636,6 +1673,9 @@ type EmbeddingRequest struct {
 
 	// GCPVertexAIEmbeddingVendorFields configures the GCP VertexAI specific fields for embedding during schema translation.
 	*GCPVertexAIEmbeddingVendorFields `json:",inline,omitempty"`
+
+	// CohereEmbeddingVendorFields configures the Cohere specific fields for embedding during schema translation.
+	*CohereEmbeddingVendorFields `json:",inline,omitempty"`
 }
 
 type EmbeddingTaskType string
@@ -1663,6 +1703,21 @@ type GCPVertexAIEmbeddingVendorFields struct {
 	TaskType EmbeddingTaskType `json:"task_type,omitempty"`
 }
 
+// CohereEmbeddingVendorFields contains Cohere vendor-specific fields for embeddings.
+// https://docs.cohere.com/reference/embed
+type CohereEmbeddingVendorFields struct {
+	// InputType is the type of the input, one of "search_document", "search_query", "classification", "clustering"
+	// or "image". When left blank, it is derived from the task_type, and defaults to "search_document".
+	InputType string `json:"input_type,omitempty"`
+
+	// EmbeddingType is the type of the returned embeddings, one of "float", "int8", "uint8", "binary" or "ubinary".
+	// The integer embeddings are returned as arrays of numbers. Defaults to "float".
+	EmbeddingType string `json:"embedding_type,omitempty"`
+
+	// Truncate specifies how the inputs longer than the maximum token length are handled, one of "NONE", "START" or "END".
+	Truncate string `json:"truncate,omitempty"`
+}
+
 // EmbeddingResponse represents a response from /v1/embeddings.
 // https://platform.openai.com/docs/api-reference/embeddings/object
 type EmbeddingResponse struct {
@@ -5840,6 +5895,17 @@ type ToolChoiceShell struct {
 	Type string `json:"type,omitzero"`
 }
 
+// ResponseDeleted is the result of DELETE /v1/responses/{response_id}.
+// Docs: https://platform.openai.com/docs/api-reference/responses/delete
+type ResponseDeleted struct {
+	// ID is the ID of the deleted response.
+	ID string `json:"id"`
+	// Object is the object type, which is always "response".
+	Object string `json:"object"`
+	// Deleted is whether the response was deleted.
+	Deleted bool `json:"deleted"`
+}
+
 // Response represents a response from the /v1/responses endpoint.
 // Docs: https://platform.openai.com/docs/api-reference/responses/object
 type Response struct {
@@ -8360,9 +8426,29 @@ type SpeechRequest struct {
 
 // SpeechStreamChunk for SSE streaming responses
 type SpeechStreamChunk struct {
-	Data []byte `json:"data"` // Audio data chunk
+	Data []byte `json:"data,omitempty"` // Audio data chunk
+	// Type is the type of the event, either speech.audio.delta or speech.audio.done.
+	Type string `json:"type,omitempty"`
+	// Audio is the base64-encoded audio chunk of a speech.audio.delta event.
+	Audio string `json:"audio,omitempty"`
+	// Usage is the token usage of the request, reported by the speech.audio.done event.
+	Usage *SpeechUsage `json:"usage,omitempty"`
+}
+
+// SpeechUsage is the token usage of the speech synthesis reported at the end of the SSE stream.
+// https://platform.openai.com/docs/api-reference/audio/speech-audio-done-event
+type SpeechUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
 }
 
+// Speech stream event type constants
+const (
+	SpeechStreamEventTypeAudioDelta = "speech.audio.delta"
+	SpeechStreamEventTypeAudioDone  = "speech.audio.done"
+)
+
 // Voice constants
 const (
 	SpeechVoiceAlloy   = "alloy"
@@ -8403,3 +8489,80 @@ const (
 	SpeechModelGPT4oMiniTTS         = "gpt-4o-mini-tts"
 	SpeechModelGPT4oMiniTTS20251215 = "gpt-4o-mini-tts-2025-12-15"
 )
+
+// TranscriptionRequest represents a request to the /v1/audio/transcriptions and /v1/audio/translations endpoints.
+//
+// The request is sent as multipart/form-data, and this only holds the form fields other than the audio file.
+// The translations endpoint accepts a subset of the fields.
+// https://platform.openai.com/docs/api-reference/audio/createTranscription
+type TranscriptionRequest struct {
+	// Model is the ID of the model to use, e.g. "whisper-1" or "gpt-4o-transcribe".
+	Model string `json:"model"`
+	// FileName is the file name of the uploaded audio file.
+	FileName string `json:"file_name,omitempty"`
+	// FileSize is the size of the uploaded audio file in bytes.
+	FileSize int `json:"file_size,omitempty"`
+	// Language is the language of the input audio in ISO-639-1 format.
+	Language *string `json:"language,omitempty"`
+	// Prompt is the optional text to guide the style of the model or continue a previous audio segment.
+	Prompt *string `json:"prompt,omitempty"`
+	// ResponseFormat is the format of the output: json, text, srt, verbose_json or vtt.
+	ResponseFormat *string `json:"response_format,omitempty"`
+	// Temperature is the sampling temperature between 0 and 1.
+	Temperature *float64 `json:"temperature,omitempty"`
+	// Stream indicates that the response is streamed with server-sent events.
+	Stream bool `json:"stream,omitempty"`
+}
+
+// TranscriptionResponse represents the JSON response of the /v1/audio/transcriptions and /v1/audio/translations
+// endpoints. Only the fields used by the gateway are defined.
+type TranscriptionResponse struct {
+	// Text is the transcribed or translated text.
+	Text string `json:"text"`
+	// Language is the language of the input audio. Only present in the verbose_json format.
+	Language string `json:"language,omitempty"`
+	// Duration is the duration of the input audio in seconds. Only present in the verbose_json format.
+	Duration *float64 `json:"duration,omitempty"`
+	// Usage is the usage statistics of the request.
+	Usage *TranscriptionUsage `json:"usage,omitempty"`
+}
+
+// TranscriptionUsage is the usage statistics of a transcription, which is either billed by the audio duration or
+// by the number of tokens depending on the model.
+type TranscriptionUsage struct {
+	// Type is either TranscriptionUsageTypeDuration or TranscriptionUsageTypeTokens.
+	Type string `json:"type"`
+	// Seconds is the duration of the input audio in seconds. Set when the Type is TranscriptionUsageTypeDuration.
+	Seconds float64 `json:"seconds,omitempty"`
+	// InputTokens is the number of input tokens. Set when the Type is TranscriptionUsageTypeTokens.
+	InputTokens int `json:"input_tokens,omitempty"`
+	// OutputTokens is the number of output tokens. Set when the Type is TranscriptionUsageTypeTokens.
+	OutputTokens int `json:"output_tokens,omitempty"`
+	// TotalTokens is the total number of tokens. Set when the Type is TranscriptionUsageTypeTokens.
+	TotalTokens int `json:"total_tokens,omitempty"`
+}
+
+// TranscriptionStreamEvent is an event of the streamed transcription response.
+// https://platform.openai.com/docs/api-reference/audio/transcript-text-delta-event
+type TranscriptionStreamEvent struct {
+	// Type is either TranscriptionStreamEventTypeDelta or TranscriptionStreamEventTypeDone.
+	Type string `json:"type"`
+	// Delta is the text delta. Set for TranscriptionStreamEventTypeDelta.
+	Delta string `json:"delta,omitempty"`
+	// Text is the complete transcription. Set for TranscriptionStreamEventTypeDone.
+	Text string `json:"text,omitempty"`
+	// Usage is the usage statistics of the request. Set for TranscriptionStreamEventTypeDone.
+	Usage *TranscriptionUsage `json:"usage,omitempty"`
+}
+
+// Transcription usage type constants
+const (
+	TranscriptionUsageTypeDuration = "duration"
+	TranscriptionUsageTypeTokens   = "tokens"
+)
+
+// Transcription stream event type constants
+const (
+	TranscriptionStreamEventTypeDelta = "transcript.text.delta"
+	TranscriptionStreamEventTypeDone  = "transcript.text.done"
+)
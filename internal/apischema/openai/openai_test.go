@@ -13730,3 +13730,41 @@ func TestResponseContentPartDoneEventPartUnionUnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestModerationInput(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		data     string
+		expected any
+	}{
+		{name: "string", data: `"I want to kill them."`, expected: "I want to kill them."},
+		{name: "strings", data: `["first","second"]`, expected: []string{"first", "second"}},
+		{
+			name: "multi-modal",
+			data: `[{"type":"text","text":"caption"},{"type":"image_url","image_url":{"url":"https://example.com/image.png"}}]`,
+			expected: []ModerationMultiModalInput{
+				{Type: "text", Text: "caption"},
+				{Type: "image_url", ImageURL: &ModerationImageURL{URL: "https://example.com/image.png"}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var input ModerationInput
+			require.NoError(t, json.Unmarshal([]byte(tc.data), &input))
+			require.Equal(t, tc.expected, input.Value)
+
+			data, err := json.Marshal(input)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.data, string(data))
+		})
+	}
+
+	var input ModerationInput
+	require.ErrorContains(t, json.Unmarshal([]byte(`123`), &input), "input must be a string, an array of strings or an array of multi-modal inputs")
+}
+
+func TestIsModerationModel(t *testing.T) {
+	require.True(t, IsModerationModel(ModerationModelOmniLatest))
+	require.True(t, IsModerationModel("text-moderation-stable"))
+	require.False(t, IsModerationModel("gpt-4o-mini"))
+}
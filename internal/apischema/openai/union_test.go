@@ -224,6 +224,16 @@ func TestUnmarshalJSONEmbeddingInput(t *testing.T) {
 				TaskType: "RETRIEVAL_QUERY",
 			},
 		},
+		{
+			name: "single EmbeddingInputItem object with content parts",
+			data: []byte(`{"content":[{"type":"text","text":"a cat"},{"type":"image_url","image_url":{"url":"data:image/png;base64,AAAA"}}]}`),
+			expected: EmbeddingInputItem{
+				Content: EmbeddingContent{Value: []EmbeddingContentPart{
+					{Type: EmbeddingContentPartTypeText, Text: "a cat"},
+					{Type: EmbeddingContentPartTypeImageURL, ImageURL: &ChatCompletionContentPartImageImageURLParam{URL: "data:image/png;base64,AAAA"}},
+				}},
+			},
+		},
 	}
 
 	for _, tc := range successCases {
@@ -266,6 +276,16 @@ func TestUnmarshalJSONEmbeddingInput_Errors(t *testing.T) {
 			data:        []byte(`[{"content":"valid"},{"content":""}]`),
 			expectedErr: "invalid input array element",
 		},
+		{
+			name:        "content part of unsupported type",
+			data:        []byte(`{"content":[{"type":"input_audio"}]}`),
+			expectedErr: "cannot unmarshal input as EmbeddingInputItem",
+		},
+		{
+			name:        "image content part without url",
+			data:        []byte(`{"content":[{"type":"image_url"}]}`),
+			expectedErr: "cannot unmarshal input as EmbeddingInputItem",
+		},
 		{
 			name:        "invalid type - null",
 			data:        []byte(`null`),
@@ -24,6 +24,12 @@ import (
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 )
 
+// awsBedrockRerankPath is the path of the Rerank API of the Amazon Bedrock Agents runtime.
+const awsBedrockRerankPath = "/rerank"
+
+// awsBedrockModelInvocationJobPath is the path prefix of the batch inference jobs of the Amazon Bedrock control plane.
+const awsBedrockModelInvocationJobPath = "/model-invocation-job"
+
 // awsHandler implements [Handler] for AWS Bedrock authz.
 type awsHandler struct {
 	credentialsProvider aws.CredentialsProvider
@@ -91,9 +97,24 @@ func (a *awsHandler) Do(ctx context.Context, requestHeaders map[string]string, m
 		body = mutatedBody
 	}
 
+	// The host is part of the signature. The Rerank API is served by the Agents runtime endpoint, and the batch
+	// inference jobs by the control plane, while the other APIs are served by the runtime endpoint.
+	endpoint := "bedrock-runtime"
+	switch {
+	case path == awsBedrockRerankPath:
+		endpoint = "bedrock-agent-runtime"
+	case strings.HasPrefix(path, awsBedrockModelInvocationJobPath):
+		// The runtime endpoint does not serve the batch inference jobs, so the backend must point to the control plane.
+		if authority := requestHeaders[":authority"]; strings.HasPrefix(authority, "bedrock-runtime.") {
+			return nil, fmt.Errorf("batch inference jobs are served by the AWS Bedrock control plane bedrock.%s.amazonaws.com, "+
+				"but the backend points to %s", a.region, authority)
+		}
+		endpoint = "bedrock"
+	}
+
 	payloadHash := sha256.Sum256(body)
 	req, err := http.NewRequest(method,
-		fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com%s", a.region, path),
+		fmt.Sprintf("https://%s.%s.amazonaws.com%s", endpoint, a.region, path),
 		bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("cannot create request: %w", err)
@@ -213,6 +213,28 @@ func TestAWSHandler_Do(t *testing.T) {
 		require.Contains(t, headers, "X-Amz-Date")
 	})
 
+	t.Run("batch inference jobs", func(t *testing.T) {
+		awsFileBody := "[default]\naws_access_key_id=test\naws_secret_access_key=secret\n"
+		handler, err := newAWSHandler(t.Context(), &filterapi.AWSAuth{
+			CredentialFileLiteral: awsFileBody,
+			Region:                "us-east-1",
+		})
+		require.NoError(t, err)
+
+		hdrs, err := handler.Do(t.Context(), map[string]string{
+			":method": "POST", ":path": "/model-invocation-job", ":authority": "bedrock.us-east-1.amazonaws.com",
+		}, []byte(`{"jobName": "batch"}`))
+		require.NoError(t, err)
+		require.Contains(t, stringPairsToMap(hdrs), "Authorization")
+
+		// The runtime endpoint does not serve the batch inference jobs.
+		_, err = handler.Do(t.Context(), map[string]string{
+			":method": "GET", ":path": "/model-invocation-job/abc", ":authority": "bedrock-runtime.us-east-1.amazonaws.com",
+		}, nil)
+		require.EqualError(t, err, "batch inference jobs are served by the AWS Bedrock control plane bedrock.us-east-1.amazonaws.com, "+
+			"but the backend points to bedrock-runtime.us-east-1.amazonaws.com")
+	})
+
 	t.Run("multiple regions", func(t *testing.T) {
 		awsFileBody := "[default]\naws_access_key_id=test\naws_secret_access_key=secret\n"
 		regions := []string{"us-east-1", "eu-west-1", "ap-southeast-1"}
@@ -0,0 +1,42 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package backendauth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+)
+
+func TestGoogleAPIKeyHandler(t *testing.T) {
+	t.Run("sets x-goog-api-key header", func(t *testing.T) {
+		handler, err := newGoogleAPIKeyHandler(&filterapi.GoogleAPIKeyAuth{Key: "test-google-key"})
+		require.NoError(t, err)
+
+		headers := make(map[string]string)
+		hdrs, err := handler.Do(t.Context(), headers, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, "test-google-key", headers["x-goog-api-key"])
+		require.Len(t, hdrs, 1)
+		require.Equal(t, "x-goog-api-key", hdrs[0][0])
+		require.Equal(t, "test-google-key", hdrs[0][1])
+	})
+
+	t.Run("trims whitespace", func(t *testing.T) {
+		handler, err := newGoogleAPIKeyHandler(&filterapi.GoogleAPIKeyAuth{Key: "  key-with-spaces\n"})
+		require.NoError(t, err)
+
+		headers := make(map[string]string)
+		hdrs, err := handler.Do(t.Context(), headers, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, "key-with-spaces", headers["x-goog-api-key"])
+		require.Equal(t, "key-with-spaces", hdrs[0][1])
+	})
+}
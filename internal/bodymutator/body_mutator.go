@@ -12,6 +12,8 @@ import (
 	"github.com/tidwall/sjson"
 
 	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/multipartform"
 )
 
 type BodyMutator struct {
@@ -20,6 +22,10 @@ type BodyMutator struct {
 
 	// bodyMutations is the body mutations to apply
 	bodyMutations *filterapi.HTTPBodyMutation
+
+	// multipartBoundary is the boundary of the multipart/form-data request body. When set, the mutations are
+	// applied to the form fields instead of JSON fields.
+	multipartBoundary string
 }
 
 func NewBodyMutator(bodyMutations *filterapi.HTTPBodyMutation, originalBody []byte) *BodyMutator {
@@ -29,6 +35,16 @@ func NewBodyMutator(bodyMutations *filterapi.HTTPBodyMutation, originalBody []by
 	}
 }
 
+// NewMultipartBodyMutator creates a BodyMutator for multipart/form-data request bodies with the given boundary.
+// The paths of the mutations are the form field names, and the JSON string values are set unquoted.
+func NewMultipartBodyMutator(bodyMutations *filterapi.HTTPBodyMutation, originalBody []byte, boundary string) *BodyMutator {
+	return &BodyMutator{
+		originalBody:      originalBody,
+		bodyMutations:     bodyMutations,
+		multipartBoundary: boundary,
+	}
+}
+
 // isJSONValue checks if a string represents a JSON value (not a plain string)
 func isJSONValue(value string) bool {
 	value = strings.TrimSpace(value)
@@ -78,6 +94,9 @@ func (b *BodyMutator) Mutate(requestBody []byte) ([]byte, error) {
 	if b.bodyMutations == nil {
 		return requestBody, nil
 	}
+	if b.multipartBoundary != "" {
+		return b.mutateForm(requestBody)
+	}
 
 	mutatedBody := requestBody
 	var err error
@@ -117,3 +136,34 @@ func (b *BodyMutator) Mutate(requestBody []byte) ([]byte, error) {
 
 	return mutatedBody, nil
 }
+
+// mutateForm mutates the form fields of the multipart/form-data request body. All the mutations are applied with
+// a single copy of the body so that the uploaded files are not copied more than once.
+func (b *BodyMutator) mutateForm(requestBody []byte) ([]byte, error) {
+	form, err := multipartform.Parse(requestBody, b.multipartBoundary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse multipart form: %w", err)
+	}
+	var remove []string
+	for _, fieldName := range b.bodyMutations.Remove {
+		if fieldName != "" {
+			remove = append(remove, fieldName)
+		}
+	}
+	var set []multipartform.Field
+	for _, field := range b.bodyMutations.Set {
+		if field.Path == "" {
+			continue
+		}
+		value := field.Value
+		// Form fields are plain text, so a JSON string such as "\"scale\"" is set as scale. The other JSON
+		// values such as numbers and booleans are already in their text form.
+		if trimmed := strings.TrimSpace(value); len(trimmed) >= 2 && strings.HasPrefix(trimmed, "\"") && strings.HasSuffix(trimmed, "\"") {
+			if err = json.Unmarshal([]byte(trimmed), &value); err != nil {
+				return nil, fmt.Errorf("failed to set field %s: %w", field.Path, err)
+			}
+		}
+		set = append(set, multipartform.Field{Name: field.Path, Value: value})
+	}
+	return form.Rewrite(set, remove), nil
+}
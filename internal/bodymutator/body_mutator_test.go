@@ -190,3 +190,29 @@ func TestBodyMutator_Mutate_InvalidJSONValue(t *testing.T) {
 	require.Equal(t, "not valid json but will be treated as string", result["service_tier"])
 	require.Equal(t, "valid", result["valid_field"])
 }
+
+func TestBodyMutator_Mutate_MultipartForm(t *testing.T) {
+	bodyMutations := &filterapi.HTTPBodyMutation{
+		Set: []filterapi.HTTPBodyField{
+			{Path: "model", Value: "\"gpt-4o-transcribe\""},
+			{Path: "temperature", Value: "0.2"},
+			{Path: "language", Value: "en"},
+		},
+		Remove: []string{"prompt"},
+	}
+
+	originalBody := []byte("--b\r\nContent-Disposition: form-data; name=\"model\"\r\n\r\nwhisper-1\r\n" +
+		"--b\r\nContent-Disposition: form-data; name=\"prompt\"\r\n\r\nsecret\r\n" +
+		"--b\r\nContent-Disposition: form-data; name=\"file\"; filename=\"a.mp3\"\r\n\r\naudio\r\n--b--\r\n")
+	mutator := NewMultipartBodyMutator(bodyMutations, originalBody, "b")
+
+	mutatedBody, err := mutator.Mutate(originalBody)
+	require.NoError(t, err)
+	require.Equal(t, "--b\r\nContent-Disposition: form-data; name=\"model\"\r\n\r\ngpt-4o-transcribe\r\n"+
+		"--b\r\nContent-Disposition: form-data; name=\"file\"; filename=\"a.mp3\"\r\n\r\naudio\r\n"+
+		"--b\r\nContent-Disposition: form-data; name=\"temperature\"\r\n\r\n0.2\r\n"+
+		"--b\r\nContent-Disposition: form-data; name=\"language\"\r\n\r\nen\r\n--b--\r\n", string(mutatedBody))
+
+	_, err = mutator.Mutate([]byte(`{"model": "gpt-4"}`))
+	require.ErrorContains(t, err, "failed to parse multipart form: missing multipart boundary")
+}
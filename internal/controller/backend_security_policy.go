@@ -103,7 +103,8 @@ func (c *BackendSecurityPolicyController) reconcile(ctx context.Context, bsp *ai
 	// Determine if credential rotation is needed
 	requiresRotation := bsp.Spec.Type != aigv1b1.BackendSecurityPolicyTypeAPIKey &&
 		bsp.Spec.Type != aigv1b1.BackendSecurityPolicyTypeAzureAPIKey &&
-		bsp.Spec.Type != aigv1b1.BackendSecurityPolicyTypeAnthropicAPIKey
+		bsp.Spec.Type != aigv1b1.BackendSecurityPolicyTypeAnthropicAPIKey &&
+		bsp.Spec.Type != aigv1b1.BackendSecurityPolicyTypeGoogleAPIKey
 
 	// Skip rotation for AWS when neither credentials file nor OIDC exchange is configured
 	// This allows IRSA/Pod Identity to work via the default credential chain
@@ -432,7 +433,8 @@ func getBSPGeneratedSecretName(bsp *aigv1b1.BackendSecurityPolicy) string {
 		}
 	case aigv1b1.BackendSecurityPolicyTypeAPIKey,
 		aigv1b1.BackendSecurityPolicyTypeAzureAPIKey,
-		aigv1b1.BackendSecurityPolicyTypeAnthropicAPIKey:
+		aigv1b1.BackendSecurityPolicyTypeAnthropicAPIKey,
+		aigv1b1.BackendSecurityPolicyTypeGoogleAPIKey:
 		return "" // APIKey does not require rotation.
 	default:
 		panic("BUG: unsupported backend security policy type: " + string(bsp.Spec.Type))
@@ -175,6 +175,12 @@ func StartControllers(ctx context.Context, mgr manager.Manager, config *rest.Con
 		return fmt.Errorf("failed to create controller for BackendSecurityPolicy: %w", err)
 	}
 
+	quotaPolicyC := NewQuotaPolicyController(c, logger.WithName("quota-policy"), aiServiceBackendEventChan)
+	if err = TypedControllerBuilderForCRD(mgr, &aigv1a1.QuotaPolicy{}).
+		Complete(quotaPolicyC); err != nil {
+		return fmt.Errorf("failed to create controller for QuotaPolicy: %w", err)
+	}
+
 	// Check if InferencePool CRD exists before creating the controller.
 	crdClient, err := apiextensionsclientset.NewForConfig(config)
 	if err != nil {
@@ -205,8 +211,9 @@ func StartControllers(ctx context.Context, mgr manager.Manager, config *rest.Con
 		}
 	}
 	mcpRouteEventChan := make(chan event.GenericEvent, 100)
+	gatewayConfigEventChan := make(chan event.GenericEvent, 100)
 	secretC := NewSecretController(c, kubernetes.NewForConfigOrDie(config), logger.
-		WithName("secret"), backendSecurityPolicyEventChan, mcpRouteEventChan)
+		WithName("secret"), backendSecurityPolicyEventChan, mcpRouteEventChan, gatewayConfigEventChan)
 	// Do not use TypedControllerBuilderForCRD for secret, as changing a secret content doesn't change the generation.
 	if err = ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Secret{}).
@@ -231,6 +238,10 @@ func StartControllers(ctx context.Context, mgr manager.Manager, config *rest.Con
 	// GatewayConfig controller for gateway-scoped configuration.
 	gatewayConfigC := NewGatewayConfigController(c, logger.WithName("gateway-config"), gatewayEventChan)
 	if err = TypedControllerBuilderForCRD(mgr, &aigv1b1.GatewayConfig{}).
+		WatchesRawSource(source.Channel(
+			gatewayConfigEventChan,
+			&handler.EnqueueRequestForObject{},
+		)).
 		Complete(gatewayConfigC); err != nil {
 		return fmt.Errorf("failed to create controller for GatewayConfig: %w", err)
 	}
@@ -298,12 +309,18 @@ const (
 	// k8sClientIndexSecretToReferencingMCPRoute is the index name that maps
 	// from a Secret to the MCPRoute that references it.
 	k8sClientIndexSecretToReferencingMCPRoute = "SecretToReferencingMCPRoute"
+	// k8sClientIndexSecretToReferencingGatewayConfig is the index name that maps
+	// from a Secret to the GatewayConfig that references it.
+	k8sClientIndexSecretToReferencingGatewayConfig = "SecretToReferencingGatewayConfig"
 	// k8sClientIndexBackendToReferencingAIGatewayRoute is the index name that maps from a Backend to the
 	// AIGatewayRoute that references it.
 	k8sClientIndexBackendToReferencingAIGatewayRoute = "BackendToReferencingAIGatewayRoute"
 	// k8sClientIndexAIServiceBackendToTargetingBackendSecurityPolicy is the index name that maps from an AIServiceBackend
 	// to the BackendSecurityPolicy whose targetRefs contains the AIServiceBackend.
 	k8sClientIndexAIServiceBackendToTargetingBackendSecurityPolicy = "AIServiceBackendToTargetingBackendSecurityPolicy"
+	// k8sClientIndexAIServiceBackendToTargetingQuotaPolicy is the index name that maps from an AIServiceBackend
+	// to the QuotaPolicy that targets it.
+	k8sClientIndexAIServiceBackendToTargetingQuotaPolicy = "AIServiceBackendToTargetingQuotaPolicy"
 	// k8sClientIndexGatewayToGatewayConfig maps from a GatewayConfig name to Gateways referencing it.
 	k8sClientIndexGatewayToGatewayConfig = "GatewayToGatewayConfig"
 
@@ -346,12 +363,22 @@ func ApplyIndexing(ctx context.Context, indexer func(ctx context.Context, obj cl
 	if err != nil {
 		return fmt.Errorf("failed to index field for BackendSecurityPolicy targetRefs: %w", err)
 	}
+	err = indexer(ctx, &aigv1a1.QuotaPolicy{},
+		k8sClientIndexAIServiceBackendToTargetingQuotaPolicy, quotaPolicyTargetRefsIndexFunc)
+	if err != nil {
+		return fmt.Errorf("failed to index field for QuotaPolicy targetRefs: %w", err)
+	}
 
 	err = indexer(ctx, &gwapiv1.Gateway{},
 		k8sClientIndexGatewayToGatewayConfig, gatewayToGatewayConfigIndexFunc)
 	if err != nil {
 		return fmt.Errorf("failed to create index from GatewayConfig to Gateway: %w", err)
 	}
+	err = indexer(ctx, &aigv1b1.GatewayConfig{},
+		k8sClientIndexSecretToReferencingGatewayConfig, gatewayConfigToReferencedSecret)
+	if err != nil {
+		return fmt.Errorf("failed to create index from Secret to GatewayConfig: %w", err)
+	}
 
 	err = indexer(ctx, &gwapiv1b1.ReferenceGrant{},
 		k8sClientIndexReferenceGrantToTargetKind, referenceGrantToTargetKindIndexFunc)
@@ -427,6 +454,15 @@ func gatewayToGatewayConfigIndexFunc(o client.Object) []string {
 	return []string{configName}
 }
 
+func gatewayConfigToReferencedSecret(o client.Object) []string {
+	gatewayConfig := o.(*aigv1b1.GatewayConfig)
+	store := gatewayConfig.Spec.QuotaStore
+	if store == nil || store.Redis == nil || store.Redis.PasswordSecretRef == nil {
+		return nil
+	}
+	return []string{getSecretNameAndNamespace(store.Redis.PasswordSecretRef, gatewayConfig.Namespace)}
+}
+
 func aiGatewayRouteToAttachedGatewayIndexFunc(o client.Object) []string {
 	aiGatewayRoute := o.(*aigv1b1.AIGatewayRoute)
 	var ret []string
@@ -480,6 +516,9 @@ func backendSecurityPolicyIndexFunc(o client.Object) []string {
 	case aigv1b1.BackendSecurityPolicyTypeAnthropicAPIKey:
 		apiKey := backendSecurityPolicy.Spec.AnthropicAPIKey
 		key = getSecretNameAndNamespace(apiKey.SecretRef, backendSecurityPolicy.Namespace)
+	case aigv1b1.BackendSecurityPolicyTypeGoogleAPIKey:
+		apiKey := backendSecurityPolicy.Spec.GoogleAPIKey
+		key = getSecretNameAndNamespace(apiKey.SecretRef, backendSecurityPolicy.Namespace)
 	case aigv1b1.BackendSecurityPolicyTypeAzureCredentials:
 		azureCreds := backendSecurityPolicy.Spec.AzureCredentials
 		if azureCreds.ClientSecretRef != nil {
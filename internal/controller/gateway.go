@@ -8,8 +8,12 @@ package controller
 import (
 	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,11 +32,13 @@ import (
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	"sigs.k8s.io/yaml"
 
+	aigv1a1 "github.com/envoyproxy/ai-gateway/api/v1alpha1"
 	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
 	"github.com/envoyproxy/ai-gateway/internal/controller/rotators"
 	"github.com/envoyproxy/ai-gateway/internal/filterapi"
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 	"github.com/envoyproxy/ai-gateway/internal/llmcostcel"
+	"github.com/envoyproxy/ai-gateway/internal/pii"
 	"github.com/envoyproxy/ai-gateway/internal/version"
 )
 
@@ -41,6 +47,8 @@ const (
 	FilterConfigKeyInSecret = "filter-config.yaml" //nolint: gosec
 	// defaultOwnedBy is the default value for the ModelsOwnedBy field in the filter config.
 	defaultOwnedBy = "Envoy AI Gateway"
+	// redisPasswordInSecret is the key to store the password of the Redis quota store.
+	redisPasswordInSecret = "password"
 )
 
 // NewGatewayController creates a new reconcile.TypedReconciler for gwapiv1.Gateway.
@@ -56,14 +64,15 @@ func NewGatewayController(
 		uf = uuid.NewString
 	}
 	return &GatewayController{
-		client:           client,
-		kube:             kube,
-		logger:           logger,
-		extProcImage:     extProcImage,
-		extProcLogLevel:  extProcLogLevel,
-		standAlone:       standAlone,
-		uuidFn:           uf,
-		extProcAsSideCar: extProcAsSideCar,
+		client:                  client,
+		kube:                    kube,
+		logger:                  logger,
+		extProcImage:            extProcImage,
+		extProcLogLevel:         extProcLogLevel,
+		standAlone:              standAlone,
+		uuidFn:                  uf,
+		extProcAsSideCar:        extProcAsSideCar,
+		referenceGrantValidator: newReferenceGrantValidator(client),
 	}
 }
 
@@ -80,6 +89,8 @@ type GatewayController struct {
 	// Whether to run the extProc container as a sidecar (true) as a normal container (false).
 	// This is essentially a workaround for old k8s versions, and we can remove this in the future.
 	extProcAsSideCar bool
+	// referenceGrantValidator validates cross-namespace references using ReferenceGrant.
+	referenceGrantValidator *referenceGrantValidator
 }
 
 // Reconcile implements the reconcile.Reconciler for gwapiv1.Gateway.
@@ -131,20 +142,30 @@ func (c *GatewayController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	uid := c.uuidFn()
 
-	// Fetch GatewayConfig to get global LLM request cost defaults.
+	// Fetch GatewayConfig to get global LLM request cost defaults, the quota store and the pricing catalog.
 	gwConfig, err := c.fetchGatewayConfig(ctx, gw)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	var defaultLLMCosts []aigv1b1.LLMRequestCost
+	var quotaStore *filterapi.QuotaStore
+	var prices *modelPrices
 	if gwConfig != nil {
 		defaultLLMCosts = gwConfig.Spec.GlobalLLMRequestCosts
+		quotaStore, err = c.quotaStoreToFilterAPI(ctx, gwConfig.Spec.QuotaStore, gwConfig.Namespace)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("invalid quota store of GatewayConfig %s: %w", gwConfig.Name, err)
+		}
+		prices, err = pricingToFilterAPI(gwConfig.Spec.Pricing, gwConfig.Namespace)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("invalid pricing of GatewayConfig %s: %w", gwConfig.Name, err)
+		}
 	}
 
 	// We need to create the filter config in Envoy Gateway system namespace because the sidecar extproc need
 	// to access it.
 	var hasEffectiveRoutes bool // indicates whether the filter config is effective (i.e., there is at least one active route).
-	hasEffectiveRoutes, err = c.reconcileFilterConfigSecret(ctx, FilterConfigSecretPerGatewayName(gw.Name, gw.Namespace), namespace, aiRoutes.Items, mcpRoutes.Items, uid, defaultLLMCosts)
+	hasEffectiveRoutes, err = c.reconcileFilterConfigSecret(ctx, FilterConfigSecretPerGatewayName(gw.Name, gw.Namespace), namespace, aiRoutes.Items, mcpRoutes.Items, uid, defaultLLMCosts, quotaStore, prices)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -188,6 +209,14 @@ func headerMutationToFilterAPI(m *aigv1b1.HTTPHeaderMutation) *filterapi.HTTPHea
 	return ret
 }
 
+// batchToFilterAPI converts an aigv1b1.AIServiceBackendBatch to filterapi.BackendBatch.
+func batchToFilterAPI(b *aigv1b1.AIServiceBackendBatch) *filterapi.BackendBatch {
+	if b == nil {
+		return nil
+	}
+	return &filterapi.BackendBatch{InputURIPrefix: b.InputURIPrefix, OutputURI: b.OutputURI, RoleARN: b.RoleARN}
+}
+
 // bodyMutationToFilterAPI converts an aigv1b1.HTTPBodyMutation to filterapi.HTTPBodyMutation.
 func bodyMutationToFilterAPI(m *aigv1b1.HTTPBodyMutation) *filterapi.HTTPBodyMutation {
 	if m == nil {
@@ -350,9 +379,15 @@ func (c *GatewayController) reconcileFilterConfigSecret(
 	mcpRoutes []aigv1b1.MCPRoute,
 	uuid string,
 	defaultLLMCosts []aigv1b1.LLMRequestCost,
+	quotaStore *filterapi.QuotaStore,
+	prices *modelPrices,
 ) (hasEffectiveRoute bool, _ error) {
 	// Precondition: aiGatewayRoutes is not empty as we early return if it is empty.
-	ec := &filterapi.Config{UUID: uuid, Version: version.Parse()}
+	ec := &filterapi.Config{UUID: uuid, Version: version.Parse(), QuotaStore: quotaStore}
+	if prices != nil {
+		ec.ModelPrices = prices.global
+		ec.Currency = prices.currency
+	}
 	var err error
 
 	// Process global LLM request costs from GatewayConfig.
@@ -378,6 +413,7 @@ func (c *GatewayController) reconcileFilterConfigSecret(
 		spec := aiGatewayRoute.Spec
 		routeBackendNamesSet := map[string]struct{}{}
 		routeBackendNames := []string{}
+		var routeModels []string
 		for ruleIndex := range spec.Rules {
 			rule := &spec.Rules[ruleIndex]
 			for _, m := range rule.Matches {
@@ -394,6 +430,7 @@ func (c *GatewayController) reconcileFilterConfigSecret(
 						CreatedAt: ptr.Deref[metav1.Time](rule.ModelsCreatedAt, aiGatewayRoute.CreationTimestamp).UTC(),
 						OwnedBy:   ptr.Deref(rule.ModelsOwnedBy, defaultOwnedBy),
 					})
+					routeModels = append(routeModels, h.Value)
 				}
 			}
 			for backendRefIndex := range rule.BackendRefs {
@@ -447,6 +484,9 @@ func (c *GatewayController) reconcileFilterConfigSecret(
 					b.BodyMutation = bodyMutationToFilterAPI(mergedBodyMutation)
 
 					b.Schema = schemaToFilterAPI(backendObj.Spec.APISchema)
+					b.Quota = c.quotaForBackend(ctx, backendObj)
+					b.ModelPrices = prices.forBackend(backendNamespace, backendRef.Name)
+					b.Batch = batchToFilterAPI(backendObj.Spec.Batch)
 				}
 
 				if bsp != nil {
@@ -481,6 +521,37 @@ func (c *GatewayController) reconcileFilterConfigSecret(
 				ec.LLMRequestCosts = append(ec.LLMRequestCosts, fc)
 			}
 		}
+		if spec.ResponseCache != nil && len(routeModels) > 0 {
+			rc, rcErr := c.responseCacheToFilterAPI(ctx, spec.ResponseCache, aiGatewayRoute.Namespace)
+			if rcErr != nil {
+				// The requests are still served without the cache.
+				c.logger.Error(rcErr, "failed to convert the response cache. Skipping the response cache of this route.",
+					"aigatewayroute", aiGatewayRoute.Name, "namespace", aiGatewayRoute.Namespace)
+			} else {
+				rc.RouteName, rc.Models = routeName, routeModels
+				ec.ResponseCaches = append(ec.ResponseCaches, *rc)
+			}
+		}
+		if spec.PIIPolicy != nil && len(routeModels) > 0 {
+			policy, pErr := piiPolicyToFilterAPI(spec.PIIPolicy)
+			if pErr != nil {
+				c.logger.Error(pErr, "failed to convert the PII policy. Skipping the PII policy of this route.",
+					"aigatewayroute", aiGatewayRoute.Name, "namespace", aiGatewayRoute.Namespace)
+			} else {
+				policy.RouteName, policy.Models = routeName, routeModels
+				ec.PIIPolicies = append(ec.PIIPolicies, *policy)
+			}
+		}
+		if spec.Guardrail != nil && len(routeModels) > 0 {
+			g, gErr := c.guardrailToFilterAPI(ctx, spec.Guardrail, aiGatewayRoute.Namespace)
+			if gErr != nil {
+				c.logger.Error(gErr, "failed to convert the guardrail. Skipping the guardrail of this route.",
+					"aigatewayroute", aiGatewayRoute.Name, "namespace", aiGatewayRoute.Namespace)
+			} else {
+				g.RouteName, g.Models = routeName, routeModels
+				ec.Guardrails = append(ec.Guardrails, *g)
+			}
+		}
 	}
 
 	// Configuration for MCP processor.
@@ -654,6 +725,13 @@ func (c *GatewayController) bspToFilterAPIBackendAuth(ctx context.Context, backe
 			return nil, fmt.Errorf("failed to get secret %s: %w", secretName, err)
 		}
 		return &filterapi.BackendAuth{AnthropicAPIKey: &filterapi.AnthropicAPIKeyAuth{Key: apiKey}}, nil
+	case aigv1b1.BackendSecurityPolicyTypeGoogleAPIKey:
+		secretName := string(backendSecurityPolicy.Spec.GoogleAPIKey.SecretRef.Name)
+		apiKey, err := c.getSecretData(ctx, namespace, secretName, apiKeyInSecret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %s: %w", secretName, err)
+		}
+		return &filterapi.BackendAuth{GoogleAPIKey: &filterapi.GoogleAPIKeyAuth{Key: apiKey}}, nil
 	case aigv1b1.BackendSecurityPolicyTypeAWSCredentials:
 		awsCred := backendSecurityPolicy.Spec.AWSCredentials
 
@@ -786,6 +864,304 @@ func (c *GatewayController) backendWithMaybeBSP(ctx context.Context, namespace,
 	return
 }
 
+// quotaStoreToFilterAPI converts the QuotaStore of the GatewayConfig in the given namespace to the
+// filterapi.QuotaStore. The password of the Redis store is read from its secret, which is fine since the filter
+// config is itself stored in a secret.
+func (c *GatewayController) quotaStoreToFilterAPI(ctx context.Context, store *aigv1b1.QuotaStore, namespace string) (*filterapi.QuotaStore, error) {
+	if store == nil {
+		return nil, nil
+	}
+	ret := &filterapi.QuotaStore{Type: filterapi.QuotaStoreType(store.Type)}
+	redis := store.Redis
+	if redis == nil {
+		return ret, nil
+	}
+	// The same check is done by the CRD validation, but the password must never end up in a plaintext field.
+	u, err := url.Parse(redis.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	if _, ok := u.User.Password(); ok {
+		return nil, errors.New("the Redis password must be specified with passwordSecretRef rather than in the URL")
+	}
+	ret.RedisURL, ret.RedisCluster = redis.URL, redis.Cluster
+	if ref := redis.PasswordSecretRef; ref != nil {
+		secretNamespace := namespace
+		if ref.Namespace != nil {
+			secretNamespace = string(*ref.Namespace)
+		}
+		ret.RedisPassword, err = c.getSecretData(ctx, secretNamespace, string(ref.Name), redisPasswordInSecret)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// responseCacheToFilterAPI converts the ResponseCache of an AIGatewayRoute in the given namespace to the
+// filterapi.ResponseCache without the route name and models. The embedding backend of the semantic lookup is
+// resolved to the URL of its Envoy Gateway Backend, since the external processor calls it directly.
+func (c *GatewayController) responseCacheToFilterAPI(ctx context.Context, cache *aigv1b1.ResponseCache, namespace string) (*filterapi.ResponseCache, error) {
+	ret := &filterapi.ResponseCache{TTL: time.Hour, OwnerHeader: ptr.Deref(cache.OwnerHeader, filterapi.DefaultResponseCacheOwnerHeader)}
+	if cache.TTL != nil {
+		ttl, err := time.ParseDuration(string(*cache.TTL))
+		if err != nil {
+			return nil, fmt.Errorf("invalid TTL %q: %w", *cache.TTL, err)
+		}
+		ret.TTL = ttl
+	}
+	semantic := cache.Semantic
+	if semantic == nil {
+		return ret, nil
+	}
+	threshold, err := strconv.ParseFloat(ptr.Deref(semantic.SimilarityThreshold, "0.95"), 64)
+	if err != nil || threshold < 0 || threshold > 1 {
+		return nil, fmt.Errorf("invalid similarity threshold %q", ptr.Deref(semantic.SimilarityThreshold, ""))
+	}
+
+	b, url, err := c.backendRefToFilterAPI(ctx, semantic.BackendRef, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the embedding backend: %w", err)
+	}
+	ret.Semantic = &filterapi.SemanticResponseCache{
+		Backend:             b,
+		URL:                 url,
+		Model:               semantic.Model,
+		SimilarityThreshold: threshold,
+	}
+	return ret, nil
+}
+
+// piiPolicyToFilterAPI converts the PIIPolicy of an AIGatewayRoute to the filterapi.PIIPolicy without the route name
+// and models. The custom patterns are compiled here as in the external processor, since an invalid pattern there
+// would reject the whole filter configuration.
+func piiPolicyToFilterAPI(policy *aigv1b1.PIIPolicy) (*filterapi.PIIPolicy, error) {
+	ret := &filterapi.PIIPolicy{Action: filterapi.PIIAction(cmp.Or(policy.Action, aigv1b1.PIIPolicyActionRestore))}
+	entities := make([]pii.Entity, 0, len(policy.Entities))
+	for _, e := range policy.Entities {
+		ret.Entities = append(ret.Entities, string(e))
+		entities = append(entities, pii.Entity(e))
+	}
+	patterns := make([]pii.Pattern, 0, len(policy.CustomPatterns))
+	for _, p := range policy.CustomPatterns {
+		ret.Patterns = append(ret.Patterns, filterapi.PIIPattern{Name: p.Name, Regex: p.Regex})
+		patterns = append(patterns, pii.Pattern{Name: p.Name, Regex: p.Regex})
+	}
+	if _, err := pii.NewDetector(entities, patterns); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// guardrailToFilterAPI converts the Guardrail of an AIGatewayRoute in the given namespace to the filterapi.Guardrail
+// without the route name and models. The backend of the classifier model is resolved the same way as the embedding
+// backend of the response cache.
+func (c *GatewayController) guardrailToFilterAPI(ctx context.Context, g *aigv1b1.Guardrail, namespace string) (*filterapi.Guardrail, error) {
+	ret := &filterapi.Guardrail{Timeout: 5 * time.Second, FailOpen: g.FailOpen}
+	if g.Timeout != nil {
+		timeout, err := time.ParseDuration(string(*g.Timeout))
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout %q: %w", *g.Timeout, err)
+		}
+		ret.Timeout = timeout
+	}
+	if g.Completion != nil {
+		ret.CheckCompletion = true
+		ret.StreamWindowSize = int(ptr.Deref(g.Completion.StreamWindowSize, 512))
+	}
+	switch {
+	case g.Webhook != nil:
+		ret.Webhook = &filterapi.GuardrailWebhook{URL: g.Webhook.URL}
+	case g.LLM != nil:
+		b, url, err := c.backendRefToFilterAPI(ctx, g.LLM.BackendRef, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the classifier backend: %w", err)
+		}
+		ret.LLM = &filterapi.GuardrailLLM{
+			Backend:      b,
+			URL:          url,
+			Model:        g.LLM.Model,
+			UnsafeAction: filterapi.GuardrailUnsafeAction(cmp.Or(g.LLM.UnsafeAction, aigv1b1.GuardrailUnsafeActionBlock)),
+		}
+	default:
+		return nil, errors.New("exactly one of webhook or llm must be set")
+	}
+	return ret, nil
+}
+
+// backendRefToFilterAPI resolves the AIServiceBackend referenced from an AIGatewayRoute in the given namespace to the
+// filterapi.Backend and the URL of its Envoy Gateway Backend, for the backends the external processor calls directly.
+// Like the backendRefs of the rules, a reference to another namespace requires a ReferenceGrant.
+func (c *GatewayController) backendRefToFilterAPI(ctx context.Context, ref aigv1b1.AIServiceBackendRef, namespace string) (filterapi.Backend, string, error) {
+	backendNamespace := ptr.Deref(ref.Namespace, namespace)
+	if err := c.referenceGrantValidator.validateAIServiceBackendReference(ctx, namespace, backendNamespace, ref.Name); err != nil {
+		return filterapi.Backend{}, "", err
+	}
+	backendObj, bsp, err := c.backendWithMaybeBSP(ctx, backendNamespace, ref.Name)
+	if err != nil {
+		return filterapi.Backend{}, "", fmt.Errorf("failed to get the backend %s: %w", ref.Name, err)
+	}
+	url, err := c.backendURL(ctx, backendObj)
+	if err != nil {
+		return filterapi.Backend{}, "", err
+	}
+	b := filterapi.Backend{
+		Name:   fmt.Sprintf("%s/%s", backendNamespace, backendObj.Name),
+		Schema: schemaToFilterAPI(backendObj.Spec.APISchema),
+	}
+	if bsp != nil {
+		if b.Auth, err = c.bspToFilterAPIBackendAuth(ctx, bsp); err != nil {
+			return filterapi.Backend{}, "", fmt.Errorf("failed to get the backend auth of the backend %s: %w", backendObj.Name, err)
+		}
+	}
+	return b, url, nil
+}
+
+// backendURL returns the base URL of the first endpoint of the Envoy Gateway Backend of the AIServiceBackend.
+// HTTPS is assumed when the Backend has TLS settings or the port is 443.
+func (c *GatewayController) backendURL(ctx context.Context, backend *aigv1b1.AIServiceBackend) (string, error) {
+	ref := backend.Spec.BackendRef
+	namespace := backend.Namespace
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	var egBackend egv1a1.Backend
+	if err := c.client.Get(ctx, client.ObjectKey{Name: string(ref.Name), Namespace: namespace}, &egBackend); err != nil {
+		return "", fmt.Errorf("failed to get the Backend %s of AIServiceBackend %s: %w", ref.Name, backend.Name, err)
+	}
+	for _, e := range egBackend.Spec.Endpoints {
+		switch {
+		case e.FQDN != nil:
+			return endpointURL(&egBackend, e.FQDN.Hostname, e.FQDN.Port), nil
+		case e.IP != nil:
+			return endpointURL(&egBackend, e.IP.Address, e.IP.Port), nil
+		}
+	}
+	return "", fmt.Errorf("the Backend %s of AIServiceBackend %s has no FQDN or IP endpoint", ref.Name, backend.Name)
+}
+
+// endpointURL returns the base URL of the endpoint of the Envoy Gateway Backend with the given host and port.
+func endpointURL(egBackend *egv1a1.Backend, host string, port int32) string {
+	scheme := "http"
+	if egBackend.Spec.TLS != nil || port == 443 {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, strconv.Itoa(int(port))))
+}
+
+// modelPrices is the pricing catalog of the GatewayConfig converted to the filterapi form.
+type modelPrices struct {
+	currency string
+	// global is the list of the prices without a backendRef.
+	global []filterapi.ModelPrice
+	// perBackend maps "namespace/name" of the AIServiceBackend to its prices.
+	perBackend map[string][]filterapi.ModelPrice
+}
+
+// forBackend returns the prices of the given AIServiceBackend. This is nil-safe.
+func (m *modelPrices) forBackend(namespace, name string) []filterapi.ModelPrice {
+	if m == nil {
+		return nil
+	}
+	return m.perBackend[namespace+"/"+name]
+}
+
+// pricingToFilterAPI converts the pricing catalog of the GatewayConfig in the given namespace to the per-unit
+// prices of the filterapi. Returns nil if the pricing is not configured.
+func pricingToFilterAPI(pricing *aigv1b1.GatewayConfigPricing, namespace string) (*modelPrices, error) {
+	if pricing == nil {
+		return nil, nil
+	}
+	ret := &modelPrices{currency: cmp.Or(pricing.Currency, "USD"), perBackend: map[string][]filterapi.ModelPrice{}}
+	for i := range pricing.Models {
+		m := &pricing.Models[i]
+		prices := make(map[string]float64)
+		for _, p := range []struct {
+			unit string
+			// perUnits is the number of the units the price is for.
+			perUnits float64
+			value    *string
+		}{
+			{llmcostcel.PriceUnitInput, 1e6, m.Input},
+			{llmcostcel.PriceUnitCachedInput, 1e6, m.CachedInput},
+			{llmcostcel.PriceUnitCacheCreationInput, 1e6, m.CacheCreationInput},
+			{llmcostcel.PriceUnitOutput, 1e6, m.Output},
+			{llmcostcel.PriceUnitReasoning, 1e6, m.Reasoning},
+			{llmcostcel.PriceUnitImage, 1, m.Image},
+			{llmcostcel.PriceUnitAudio, 1, m.Audio},
+			{llmcostcel.PriceUnitAudioOutput, 1, m.AudioOutput},
+		} {
+			if p.value == nil {
+				continue
+			}
+			v, err := strconv.ParseFloat(*p.value, 64)
+			if err != nil || v < 0 {
+				return nil, fmt.Errorf("invalid %s price %q of model %s", p.unit, *p.value, m.Model)
+			}
+			prices[p.unit] = v / p.perUnits
+		}
+		mp := filterapi.ModelPrice{Model: m.Model, Prices: prices}
+		if m.BackendRef == nil {
+			ret.global = append(ret.global, mp)
+			continue
+		}
+		key := ptr.Deref(m.BackendRef.Namespace, namespace) + "/" + m.BackendRef.Name
+		ret.perBackend[key] = append(ret.perBackend[key], mp)
+	}
+	return ret, nil
+}
+
+// quotaForBackend returns the quota of the QuotaPolicy targeting the given AIServiceBackend if it exists.
+//
+// When multiple QuotaPolicies target the same backend, the oldest one wins, which is consistent with
+// the conflict resolution of the Gateway API policy attachment. Failures are logged and the backend is
+// served without a quota so that a broken policy does not take the backend down.
+func (c *GatewayController) quotaForBackend(ctx context.Context, backend *aigv1b1.AIServiceBackend) *filterapi.BackendQuota {
+	var quotaPolicyList aigv1a1.QuotaPolicyList
+	key := fmt.Sprintf("%s.%s", backend.Name, backend.Namespace)
+	if err := c.client.List(ctx, &quotaPolicyList, client.InNamespace(backend.Namespace),
+		client.MatchingFields{k8sClientIndexAIServiceBackendToTargetingQuotaPolicy: key}); err != nil {
+		c.logger.Error(err, "failed to list QuotaPolicies for backend", "backend_name", backend.Name,
+			"backend_namespace", backend.Namespace)
+		return nil
+	}
+
+	var winner *aigv1a1.QuotaPolicy
+	for i := range quotaPolicyList.Items {
+		policy := &quotaPolicyList.Items[i]
+		targeted := false
+		for _, target := range policy.Spec.TargetRefs {
+			if string(target.Name) == backend.Name &&
+				target.Group == aiServiceBackendGroup &&
+				target.Kind == aiServiceBackendKind {
+				targeted = true
+				break
+			}
+		}
+		if !targeted || policy.DeletionTimestamp != nil {
+			continue
+		}
+		if winner == nil || policy.CreationTimestamp.Before(&winner.CreationTimestamp) ||
+			(policy.CreationTimestamp.Equal(&winner.CreationTimestamp) && policy.Name < winner.Name) {
+			winner = policy
+		}
+	}
+	if winner == nil {
+		return nil
+	}
+	if len(quotaPolicyList.Items) > 1 {
+		c.logger.Info("multiple QuotaPolicies found for backend, using the oldest one", "backend_name", backend.Name,
+			"backend_namespace", backend.Namespace, "quota_policy", winner.Name)
+	}
+	q, err := quotaPolicyToFilterAPI(winner, backend.Name)
+	if err != nil {
+		c.logger.Error(err, "invalid QuotaPolicy. Skipping the quota for this backend.", "backend_name", backend.Name,
+			"backend_namespace", backend.Namespace, "quota_policy", winner.Name)
+		return nil
+	}
+	return q
+}
+
 // getBSPForInferencePool retrieves the BackendSecurityPolicy for a given InferencePool if it exists.
 func (c *GatewayController) getBSPForInferencePool(ctx context.Context, namespace, name string) (*aigv1b1.BackendSecurityPolicy, error) {
 	var bspList aigv1b1.BackendSecurityPolicyList
@@ -0,0 +1,301 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	aigv1a1 "github.com/envoyproxy/ai-gateway/api/v1alpha1"
+	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/llmcostcel"
+)
+
+// QuotaPolicyController implements [reconcile.TypedReconciler] for [aigv1a1.QuotaPolicy].
+//
+// Exported for testing purposes.
+type QuotaPolicyController struct {
+	client                    client.Client
+	logger                    logr.Logger
+	aiServiceBackendEventChan chan event.GenericEvent
+}
+
+// NewQuotaPolicyController creates a new [reconcile.TypedReconciler] for [aigv1a1.QuotaPolicy].
+func NewQuotaPolicyController(client client.Client, logger logr.Logger, aiServiceBackendEventChan chan event.GenericEvent) *QuotaPolicyController {
+	return &QuotaPolicyController{
+		client:                    client,
+		logger:                    logger,
+		aiServiceBackendEventChan: aiServiceBackendEventChan,
+	}
+}
+
+// Reconcile implements the [reconcile.TypedReconciler] for [aigv1a1.QuotaPolicy].
+func (c *QuotaPolicyController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var quotaPolicy aigv1a1.QuotaPolicy
+	if err := c.client.Get(ctx, req.NamespacedName, &quotaPolicy); err != nil {
+		if apierrors.IsNotFound(err) {
+			c.logger.Info("Deleting QuotaPolicy", "namespace", req.Namespace, "name", req.Name)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	c.logger.Info("Reconciling QuotaPolicy", "namespace", req.Namespace, "name", req.Name)
+	if handleFinalizer(ctx, c.client, c.logger, &quotaPolicy, c.syncQuotaPolicy) { // Propagate the deletion all the way to relevant Gateways.
+		return ctrl.Result{}, nil
+	}
+	// The targeted backends are notified regardless of the validation result so that an invalid
+	// policy stops being enforced.
+	if err := c.syncQuotaPolicy(ctx, &quotaPolicy); err != nil {
+		c.logger.Error(err, "failed to sync QuotaPolicy", "namespace", req.Namespace, "name", req.Name)
+		c.updateQuotaPolicyStatus(ctx, &quotaPolicy, aigv1b1.ConditionTypeNotAccepted, err.Error())
+		return ctrl.Result{}, err
+	}
+	if err := validateQuotaPolicy(&quotaPolicy); err != nil {
+		c.updateQuotaPolicyStatus(ctx, &quotaPolicy, aigv1b1.ConditionTypeNotAccepted, err.Error())
+		return ctrl.Result{}, nil
+	}
+	c.updateQuotaPolicyStatus(ctx, &quotaPolicy, aigv1b1.ConditionTypeAccepted, "QuotaPolicy reconciled successfully")
+	return ctrl.Result{}, nil
+}
+
+// syncQuotaPolicy notifies the AIServiceBackends targeted by the QuotaPolicy.
+func (c *QuotaPolicyController) syncQuotaPolicy(ctx context.Context, quotaPolicy *aigv1a1.QuotaPolicy) error {
+	for _, targetRef := range quotaPolicy.Spec.TargetRefs {
+		if targetRef.Group != aiServiceBackendGroup || targetRef.Kind != aiServiceBackendKind {
+			continue
+		}
+		var aiBackend aigv1b1.AIServiceBackend
+		err := c.client.Get(ctx, client.ObjectKey{
+			Name:      string(targetRef.Name),
+			Namespace: quotaPolicy.Namespace, // targetRefs are local to the policy's namespace.
+		}, &aiBackend)
+		if err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to get targeted AIServiceBackend %s: %w", targetRef.Name, err)
+			}
+			c.logger.Info("Targeted AIServiceBackend not found", "name", string(targetRef.Name), "namespace", quotaPolicy.Namespace)
+			continue
+		}
+		c.logger.Info("Syncing AIServiceBackend", "namespace", aiBackend.Namespace, "name", aiBackend.Name)
+		c.aiServiceBackendEventChan <- event.GenericEvent{Object: &aiBackend}
+	}
+	return nil
+}
+
+// updateQuotaPolicyStatus updates the status of the QuotaPolicy.
+func (c *QuotaPolicyController) updateQuotaPolicyStatus(ctx context.Context, quotaPolicy *aigv1a1.QuotaPolicy, conditionType string, message string) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := c.client.Get(ctx, client.ObjectKey{Name: quotaPolicy.Name, Namespace: quotaPolicy.Namespace}, quotaPolicy); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		quotaPolicy.Status.Conditions = newConditions(conditionType, message)
+		return c.client.Status().Update(ctx, quotaPolicy)
+	})
+	if err != nil {
+		c.logger.Error(err, "failed to update QuotaPolicy status", "namespace", quotaPolicy.Namespace, "name", quotaPolicy.Name)
+	}
+}
+
+// validateQuotaPolicy validates the parts of the QuotaPolicy that cannot be validated by the CRD schema.
+func validateQuotaPolicy(quotaPolicy *aigv1a1.QuotaPolicy) error {
+	_, err := quotaPolicyToFilterAPI(quotaPolicy, "")
+	return err
+}
+
+// quotaPolicyToFilterAPI converts the QuotaPolicy to the filterapi.BackendQuota for the given AIServiceBackend name.
+func quotaPolicyToFilterAPI(quotaPolicy *aigv1a1.QuotaPolicy, backendName string) (*filterapi.BackendQuota, error) {
+	ret := &filterapi.BackendQuota{
+		Name: fmt.Sprintf("%s/%s/%s", quotaPolicy.Namespace, quotaPolicy.Name, backendName),
+	}
+	spec := &quotaPolicy.Spec
+
+	if spec.ServiceQuota.Quota.Duration != "" {
+		quota, err := quotaValueToFilterAPI(spec.ServiceQuota.Quota)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service quota: %w", err)
+		}
+		expr, err := quotaCostExpressionToFilterAPI(spec.ServiceQuota.CostExpression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service quota: %w", err)
+		}
+		ret.ServiceQuota = &filterapi.ServiceQuota{CostExpression: expr, Quota: quota}
+	}
+
+	seenModels := make(map[string]struct{}, len(spec.PerModelQuotas))
+	for i := range spec.PerModelQuotas {
+		pmq := &spec.PerModelQuotas[i]
+		modelName := ptr.Deref(pmq.ModelName, "")
+		if modelName == "" {
+			return nil, fmt.Errorf("perModelQuotas[%d]: modelName must be specified", i)
+		}
+		if _, ok := seenModels[modelName]; ok {
+			return nil, fmt.Errorf("perModelQuotas[%d]: duplicate quota for model %s", i, modelName)
+		}
+		seenModels[modelName] = struct{}{}
+
+		m, err := perModelQuotaToFilterAPI(modelName, &pmq.Quota)
+		if err != nil {
+			return nil, fmt.Errorf("perModelQuotas[%d]: %w", i, err)
+		}
+		ret.PerModelQuotas = append(ret.PerModelQuotas, m)
+	}
+	return ret, nil
+}
+
+// perModelQuotaToFilterAPI converts the QuotaDefinition of the given model to the filterapi.PerModelQuota.
+func perModelQuotaToFilterAPI(modelName string, def *aigv1a1.QuotaDefinition) (filterapi.PerModelQuota, error) {
+	ret := filterapi.PerModelQuota{ModelName: modelName}
+	var err error
+	if ret.CostExpression, err = quotaCostExpressionToFilterAPI(def.CostExpression); err != nil {
+		return ret, err
+	}
+	switch def.Mode {
+	case aigv1a1.QuoteBucketModeShared:
+		ret.Mode = filterapi.QuotaBucketModeShared
+	case aigv1a1.QuoteBucketModeExclusive, "":
+		ret.Mode = filterapi.QuotaBucketModeExclusive
+	default:
+		return ret, fmt.Errorf("unknown quota bucket mode %q", def.Mode)
+	}
+	if def.DefaultBucket.Duration != "" {
+		quota, err := quotaValueToFilterAPI(def.DefaultBucket)
+		if err != nil {
+			return ret, fmt.Errorf("invalid default bucket: %w", err)
+		}
+		ret.DefaultBucket = &quota
+	}
+	for j := range def.BucketRules {
+		rule := &def.BucketRules[j]
+		quota, err := quotaValueToFilterAPI(rule.Quota)
+		if err != nil {
+			return ret, fmt.Errorf("bucketRules[%d]: %w", j, err)
+		}
+		fr := filterapi.QuotaRule{Quota: quota, ShadowMode: ptr.Deref(rule.ShadowMode, false)}
+		for k := range rule.ClientSelectors {
+			sel, err := quotaClientSelectorToFilterAPI(&rule.ClientSelectors[k])
+			if err != nil {
+				return ret, fmt.Errorf("bucketRules[%d].clientSelectors[%d]: %w", j, k, err)
+			}
+			fr.ClientSelectors = append(fr.ClientSelectors, sel)
+		}
+		ret.BucketRules = append(ret.BucketRules, fr)
+	}
+	return ret, nil
+}
+
+// quotaClientSelectorToFilterAPI converts the client selector to the filterapi.QuotaClientSelector.
+// Only the header matches are supported since the quota is enforced by the external processor which
+// does not have access to the other attributes of the traffic flow.
+func quotaClientSelectorToFilterAPI(sel *egv1a1.RateLimitSelectCondition) (filterapi.QuotaClientSelector, error) {
+	var ret filterapi.QuotaClientSelector
+	switch {
+	case len(sel.Methods) > 0:
+		return ret, errors.New("methods selector is not supported")
+	case sel.Path != nil:
+		return ret, errors.New("path selector is not supported")
+	case sel.SourceCIDR != nil:
+		return ret, errors.New("sourceCIDR selector is not supported")
+	case len(sel.QueryParams) > 0:
+		return ret, errors.New("queryParams selector is not supported")
+	}
+	for _, h := range sel.Headers {
+		m := filterapi.QuotaHeaderMatch{
+			Name:   strings.ToLower(h.Name),
+			Value:  ptr.Deref(h.Value, ""),
+			Invert: ptr.Deref(h.Invert, false),
+		}
+		switch ptr.Deref(h.Type, egv1a1.HeaderMatchExact) {
+		case egv1a1.HeaderMatchExact:
+			m.Type = filterapi.QuotaHeaderMatchTypeExact
+		case egv1a1.HeaderMatchRegularExpression:
+			if _, err := regexp.Compile(m.Value); err != nil {
+				return ret, fmt.Errorf("invalid regular expression for header %s: %w", h.Name, err)
+			}
+			m.Type = filterapi.QuotaHeaderMatchTypeRegularExpression
+		case egv1a1.HeaderMatchDistinct:
+			if m.Invert {
+				return ret, fmt.Errorf("invert is not supported for the distinct header match %s", h.Name)
+			}
+			m.Type = filterapi.QuotaHeaderMatchTypeDistinct
+		default:
+			return ret, fmt.Errorf("unknown header match type %q for header %s", *h.Type, h.Name)
+		}
+		ret.Headers = append(ret.Headers, m)
+	}
+	return ret, nil
+}
+
+// quotaCostExpressionToFilterAPI validates the optional CEL cost expression.
+func quotaCostExpressionToFilterAPI(expr *string) (string, error) {
+	if expr == nil || *expr == "" {
+		return "", nil
+	}
+	if _, err := llmcostcel.NewProgram(*expr); err != nil {
+		return "", fmt.Errorf("invalid cost expression: %w", err)
+	}
+	return *expr, nil
+}
+
+// quotaValueToFilterAPI converts the QuotaValue to the filterapi.QuotaValue.
+func quotaValueToFilterAPI(v aigv1a1.QuotaValue) (filterapi.QuotaValue, error) {
+	d, err := parseQuotaDuration(v.Duration)
+	if err != nil {
+		return filterapi.QuotaValue{}, err
+	}
+	return filterapi.QuotaValue{Limit: uint64(v.Limit), Duration: d}, nil
+}
+
+// parseQuotaDuration parses the duration of the QuotaValue. The suffix is used to specify the unit
+// which is one of "s", "m" or "h". Seconds are assumed when the suffix is omitted.
+func parseQuotaDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, errors.New("duration must be specified")
+	}
+	unit := time.Second
+	num := s
+	switch s[len(s)-1] {
+	case 's':
+		num = s[:len(s)-1]
+	case 'm':
+		unit, num = time.Minute, s[:len(s)-1]
+	case 'h':
+		unit, num = time.Hour, s[:len(s)-1]
+	}
+	n, err := strconv.ParseUint(num, 10, 32)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid duration %q: must be a positive integer with an optional s, m or h suffix", s)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// quotaPolicyTargetRefsIndexFunc indexes the QuotaPolicy by the AIServiceBackends it targets.
+func quotaPolicyTargetRefsIndexFunc(o client.Object) []string {
+	quotaPolicy := o.(*aigv1a1.QuotaPolicy)
+	var ret []string
+	for _, targetRef := range quotaPolicy.Spec.TargetRefs {
+		ret = append(ret, fmt.Sprintf("%s.%s", targetRef.Name, quotaPolicy.Namespace))
+	}
+	return ret
+}
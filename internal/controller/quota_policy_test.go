@@ -0,0 +1,263 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	aigv1a1 "github.com/envoyproxy/ai-gateway/api/v1alpha1"
+	aigv1b1 "github.com/envoyproxy/ai-gateway/api/v1beta1"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	internaltesting "github.com/envoyproxy/ai-gateway/internal/testing"
+)
+
+func TestQuotaPolicyController_Reconcile(t *testing.T) {
+	aiServiceBackendEventCh := internaltesting.NewControllerEventChan[*aigv1b1.AIServiceBackend]()
+	fakeClient := requireNewFakeClientWithIndexes(t)
+	c := NewQuotaPolicyController(fakeClient, ctrl.Log, aiServiceBackendEventCh.Ch)
+	const namespace = "default"
+
+	asb := &aigv1b1.AIServiceBackend{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: namespace}}
+	require.NoError(t, fakeClient.Create(t.Context(), asb))
+
+	// Not found should be a no-op.
+	_, err := c.Reconcile(t.Context(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "nonexistent"}})
+	require.NoError(t, err)
+
+	targetRefs := []gwapiv1a2.LocalPolicyTargetReference{
+		{Kind: aiServiceBackendKind, Group: aiServiceBackendGroup, Name: gwapiv1.ObjectName(asb.Name)},
+		{Kind: aiServiceBackendKind, Group: aiServiceBackendGroup, Name: "missing"},
+	}
+	require.NoError(t, fakeClient.Create(t.Context(), &aigv1a1.QuotaPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "valid", Namespace: namespace},
+		Spec: aigv1a1.QuotaPolicySpec{
+			TargetRefs: targetRefs,
+			ServiceQuota: aigv1a1.ServiceQuotaDefinition{
+				Quota: aigv1a1.QuotaValue{Limit: 100, Duration: "1m"},
+			},
+		},
+	}))
+	_, err = c.Reconcile(t.Context(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "valid"}})
+	require.NoError(t, err)
+	items := aiServiceBackendEventCh.RequireItemsEventually(t, 1)
+	require.Equal(t, asb.Name, items[0].Name)
+
+	var qp aigv1a1.QuotaPolicy
+	require.NoError(t, fakeClient.Get(t.Context(), types.NamespacedName{Namespace: namespace, Name: "valid"}, &qp))
+	require.Len(t, qp.Status.Conditions, 1)
+	require.Equal(t, aigv1b1.ConditionTypeAccepted, qp.Status.Conditions[0].Type)
+	require.Contains(t, qp.Finalizers, aiGatewayControllerFinalizer)
+
+	// An invalid policy is not accepted, but the backends are still notified.
+	require.NoError(t, fakeClient.Create(t.Context(), &aigv1a1.QuotaPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: namespace},
+		Spec: aigv1a1.QuotaPolicySpec{
+			TargetRefs: targetRefs,
+			ServiceQuota: aigv1a1.ServiceQuotaDefinition{
+				Quota: aigv1a1.QuotaValue{Limit: 100, Duration: "1d"},
+			},
+		},
+	}))
+	_, err = c.Reconcile(t.Context(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "invalid"}})
+	require.NoError(t, err)
+	aiServiceBackendEventCh.RequireItemsEventually(t, 1)
+	require.NoError(t, fakeClient.Get(t.Context(), types.NamespacedName{Namespace: namespace, Name: "invalid"}, &qp))
+	require.Len(t, qp.Status.Conditions, 1)
+	require.Equal(t, aigv1b1.ConditionTypeNotAccepted, qp.Status.Conditions[0].Type)
+	require.Contains(t, qp.Status.Conditions[0].Message, `invalid duration "1d"`)
+
+	// Deletion propagates to the backends.
+	require.NoError(t, fakeClient.Delete(t.Context(), &aigv1a1.QuotaPolicy{ObjectMeta: metav1.ObjectMeta{Name: "valid", Namespace: namespace}}))
+	_, err = c.Reconcile(t.Context(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "valid"}})
+	require.NoError(t, err)
+	aiServiceBackendEventCh.RequireItemsEventually(t, 1)
+}
+
+func Test_quotaPolicyToFilterAPI(t *testing.T) {
+	policy := func(spec aigv1a1.QuotaPolicySpec) *aigv1a1.QuotaPolicy {
+		return &aigv1a1.QuotaPolicy{ObjectMeta: metav1.ObjectMeta{Name: "qp", Namespace: "ns"}, Spec: spec}
+	}
+	for _, tc := range []struct {
+		name   string
+		policy *aigv1a1.QuotaPolicy
+		exp    *filterapi.BackendQuota
+		expErr string
+	}{
+		{
+			name:   "empty",
+			policy: policy(aigv1a1.QuotaPolicySpec{}),
+			exp:    &filterapi.BackendQuota{Name: "ns/qp/backend"},
+		},
+		{
+			name: "service and per model quotas",
+			policy: policy(aigv1a1.QuotaPolicySpec{
+				ServiceQuota: aigv1a1.ServiceQuotaDefinition{
+					CostExpression: ptr.To("input_tokens + output_tokens"),
+					Quota:          aigv1a1.QuotaValue{Limit: 1000, Duration: "1h"},
+				},
+				PerModelQuotas: []aigv1a1.PerModelQuota{
+					{
+						ModelName: ptr.To("gpt-4o"),
+						Quota: aigv1a1.QuotaDefinition{
+							Mode:          aigv1a1.QuoteBucketModeShared,
+							DefaultBucket: aigv1a1.QuotaValue{Limit: 100, Duration: "60"},
+							BucketRules: []aigv1a1.QuotaRule{
+								{
+									ClientSelectors: []egv1a1.RateLimitSelectCondition{{
+										Headers: []egv1a1.HeaderMatch{
+											{Name: "X-Tenant", Value: ptr.To("a")},
+											{Name: "x-user", Type: ptr.To(egv1a1.HeaderMatchRegularExpression), Value: ptr.To("^u-.*$"), Invert: ptr.To(true)},
+											{Name: "x-session", Type: ptr.To(egv1a1.HeaderMatchDistinct)},
+										},
+									}},
+									Quota:      aigv1a1.QuotaValue{Limit: 10, Duration: "30s"},
+									ShadowMode: ptr.To(true),
+								},
+							},
+						},
+					},
+					{ModelName: ptr.To("gpt-4o-mini")},
+				},
+			}),
+			exp: &filterapi.BackendQuota{
+				Name: "ns/qp/backend",
+				ServiceQuota: &filterapi.ServiceQuota{
+					CostExpression: "input_tokens + output_tokens",
+					Quota:          filterapi.QuotaValue{Limit: 1000, Duration: time.Hour},
+				},
+				PerModelQuotas: []filterapi.PerModelQuota{
+					{
+						ModelName:     "gpt-4o",
+						Mode:          filterapi.QuotaBucketModeShared,
+						DefaultBucket: &filterapi.QuotaValue{Limit: 100, Duration: time.Minute},
+						BucketRules: []filterapi.QuotaRule{
+							{
+								ClientSelectors: []filterapi.QuotaClientSelector{{
+									Headers: []filterapi.QuotaHeaderMatch{
+										{Name: "x-tenant", Type: filterapi.QuotaHeaderMatchTypeExact, Value: "a"},
+										{Name: "x-user", Type: filterapi.QuotaHeaderMatchTypeRegularExpression, Value: "^u-.*$", Invert: true},
+										{Name: "x-session", Type: filterapi.QuotaHeaderMatchTypeDistinct},
+									},
+								}},
+								Quota:      filterapi.QuotaValue{Limit: 10, Duration: 30 * time.Second},
+								ShadowMode: true,
+							},
+						},
+					},
+					{ModelName: "gpt-4o-mini", Mode: filterapi.QuotaBucketModeExclusive},
+				},
+			},
+		},
+		{
+			name: "invalid service cost expression",
+			policy: policy(aigv1a1.QuotaPolicySpec{ServiceQuota: aigv1a1.ServiceQuotaDefinition{
+				CostExpression: ptr.To("invalid +"),
+				Quota:          aigv1a1.QuotaValue{Limit: 1, Duration: "1s"},
+			}}),
+			expErr: "invalid service quota: invalid cost expression",
+		},
+		{
+			name:   "missing model name",
+			policy: policy(aigv1a1.QuotaPolicySpec{PerModelQuotas: []aigv1a1.PerModelQuota{{}}}),
+			expErr: "perModelQuotas[0]: modelName must be specified",
+		},
+		{
+			name: "duplicate model name",
+			policy: policy(aigv1a1.QuotaPolicySpec{PerModelQuotas: []aigv1a1.PerModelQuota{
+				{ModelName: ptr.To("a")}, {ModelName: ptr.To("a")},
+			}}),
+			expErr: "perModelQuotas[1]: duplicate quota for model a",
+		},
+		{
+			name: "unsupported selector",
+			policy: policy(aigv1a1.QuotaPolicySpec{PerModelQuotas: []aigv1a1.PerModelQuota{{
+				ModelName: ptr.To("a"),
+				Quota: aigv1a1.QuotaDefinition{BucketRules: []aigv1a1.QuotaRule{{
+					ClientSelectors: []egv1a1.RateLimitSelectCondition{{SourceCIDR: &egv1a1.SourceMatch{Value: "10.0.0.0/8"}}},
+					Quota:           aigv1a1.QuotaValue{Limit: 1, Duration: "1s"},
+				}}},
+			}}}),
+			expErr: "perModelQuotas[0]: bucketRules[0].clientSelectors[0]: sourceCIDR selector is not supported",
+		},
+		{
+			name: "distinct with invert",
+			policy: policy(aigv1a1.QuotaPolicySpec{PerModelQuotas: []aigv1a1.PerModelQuota{{
+				ModelName: ptr.To("a"),
+				Quota: aigv1a1.QuotaDefinition{BucketRules: []aigv1a1.QuotaRule{{
+					ClientSelectors: []egv1a1.RateLimitSelectCondition{{Headers: []egv1a1.HeaderMatch{
+						{Name: "x", Type: ptr.To(egv1a1.HeaderMatchDistinct), Invert: ptr.To(true)},
+					}}},
+					Quota: aigv1a1.QuotaValue{Limit: 1, Duration: "1s"},
+				}}},
+			}}}),
+			expErr: "invert is not supported for the distinct header match x",
+		},
+		{
+			name: "invalid regular expression",
+			policy: policy(aigv1a1.QuotaPolicySpec{PerModelQuotas: []aigv1a1.PerModelQuota{{
+				ModelName: ptr.To("a"),
+				Quota: aigv1a1.QuotaDefinition{BucketRules: []aigv1a1.QuotaRule{{
+					ClientSelectors: []egv1a1.RateLimitSelectCondition{{Headers: []egv1a1.HeaderMatch{
+						{Name: "x", Type: ptr.To(egv1a1.HeaderMatchRegularExpression), Value: ptr.To("(")},
+					}}},
+					Quota: aigv1a1.QuotaValue{Limit: 1, Duration: "1s"},
+				}}},
+			}}}),
+			expErr: "invalid regular expression for header x",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := quotaPolicyToFilterAPI(tc.policy, "backend")
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.exp, actual)
+		})
+	}
+}
+
+func Test_parseQuotaDuration(t *testing.T) {
+	for _, tc := range []struct {
+		in  string
+		exp time.Duration
+	}{
+		{in: "10", exp: 10 * time.Second},
+		{in: "10s", exp: 10 * time.Second},
+		{in: "5m", exp: 5 * time.Minute},
+		{in: "2h", exp: 2 * time.Hour},
+	} {
+		d, err := parseQuotaDuration(tc.in)
+		require.NoError(t, err, tc.in)
+		require.Equal(t, tc.exp, d, tc.in)
+	}
+	for _, in := range []string{"", "0", "0s", "-1s", "1d", "1.5h", "h", "1ms"} {
+		_, err := parseQuotaDuration(in)
+		require.Error(t, err, in)
+	}
+}
+
+func Test_quotaPolicyTargetRefsIndexFunc(t *testing.T) {
+	keys := quotaPolicyTargetRefsIndexFunc(&aigv1a1.QuotaPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "qp", Namespace: "ns"},
+		Spec: aigv1a1.QuotaPolicySpec{TargetRefs: []gwapiv1a2.LocalPolicyTargetReference{
+			{Name: "a"}, {Name: "b"},
+		}},
+	})
+	require.Equal(t, []string{"a.ns", "b.ns"}, keys)
+}
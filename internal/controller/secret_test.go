@@ -27,8 +27,9 @@ import (
 func TestSecretController_Reconcile(t *testing.T) {
 	bspCh := internaltesting.NewControllerEventChan[*aigv1b1.BackendSecurityPolicy]()
 	mcpRouteCh := internaltesting.NewControllerEventChan[*aigv1b1.MCPRoute]()
+	gatewayConfigCh := internaltesting.NewControllerEventChan[*aigv1b1.GatewayConfig]()
 	fakeClient := requireNewFakeClientWithIndexes(t)
-	c := NewSecretController(fakeClient, fake2.NewClientset(), ctrl.Log, bspCh.Ch, mcpRouteCh.Ch)
+	c := NewSecretController(fakeClient, fake2.NewClientset(), ctrl.Log, bspCh.Ch, mcpRouteCh.Ch, gatewayConfigCh.Ch)
 
 	err := fakeClient.Create(t.Context(), &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{Name: "mysecret", Namespace: "default"},
@@ -73,6 +74,17 @@ func TestSecretController_Reconcile(t *testing.T) {
 	}
 	require.NoError(t, fakeClient.Create(t.Context(), mcp))
 
+	// Create a GatewayConfig that references the secret via the password of the Redis quota store.
+	gatewayConfig := &aigv1b1.GatewayConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+		Spec: aigv1b1.GatewayConfigSpec{
+			QuotaStore: &aigv1b1.QuotaStore{Type: aigv1b1.QuotaStoreTypeRedis, Redis: &aigv1b1.QuotaStoreRedis{
+				URL: "redis://redis:6379", PasswordSecretRef: &gwapiv1.SecretObjectReference{Name: "mysecret"},
+			}},
+		},
+	}
+	require.NoError(t, fakeClient.Create(t.Context(), gatewayConfig))
+
 	_, err = c.Reconcile(t.Context(), reconcile.Request{NamespacedName: types.NamespacedName{
 		Namespace: "default", Name: "mysecret",
 	}})
@@ -91,6 +103,9 @@ func TestSecretController_Reconcile(t *testing.T) {
 	mcpActual := mcpRouteCh.RequireItemsEventually(t, 1)
 	require.Equal(t, mcp, mcpActual[0])
 
+	gatewayConfigActual := gatewayConfigCh.RequireItemsEventually(t, 1)
+	require.Equal(t, gatewayConfig, gatewayConfigActual[0])
+
 	// Test the case where the Secret is being deleted.
 	err = fakeClient.Delete(t.Context(), &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{Name: "mysecret", Namespace: "default"},
@@ -8,16 +8,30 @@
 package endpointspec
 
 import (
+	"bytes"
+	"cmp"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
+	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
+	"google.golang.org/genai"
+	"k8s.io/utils/ptr"
 
 	"github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
 	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
 	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/guardrail"
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/multipartform"
 	"github.com/envoyproxy/ai-gateway/internal/redaction"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
 	"github.com/envoyproxy/ai-gateway/internal/translator"
@@ -76,6 +90,62 @@ type (
 		// * err: An error if redaction fails (implementation-specific).
 		RedactSensitiveInfoFromRequest(req *ReqT) (redactedReq *ReqT, err error)
 	}
+	// FormSpec is optionally implemented by the Spec of the endpoints accepting multipart/form-data requests.
+	//
+	// When the content-type of the request is multipart/form-data, the router processor parses the form
+	// once with the boundary of the header and calls ParseForm instead of [Spec.ParseBody]. The return values
+	// are the same as [Spec.ParseBody].
+	FormSpec[ReqT any] interface {
+		ParseForm(form *multipartform.Form, costConfigured bool) (originalModel internalapi.OriginalModel, req *ReqT, stream bool, mutatedBody []byte, err error)
+	}
+	// PathSpec is optionally implemented by the Spec of the endpoints whose model and streaming mode are part of
+	// the request path rather than the body, such as the Gemini API.
+	//
+	// The router processor calls ParsePath with the original :path header instead of [Spec.ParseBody]. The return
+	// values are the same as [Spec.ParseBody].
+	PathSpec[ReqT any] interface {
+		ParsePath(path string, body []byte, costConfigured bool) (originalModel internalapi.OriginalModel, req *ReqT, stream bool, mutatedBody []byte, err error)
+	}
+	// HeaderSpec is optionally implemented by the Spec of the endpoints whose requests may have no body, such as
+	// the retrieval of a resource by ID. Envoy does not send the request body phase for such requests, so they have
+	// to be routed at the request headers phase.
+	//
+	// The router processor calls ParseHeaders with the :method and the original :path headers before the body is
+	// received. When ok is true, the request is routed with the returned model and request, and the request body, if
+	// any, is ignored. Otherwise, the request is parsed from the body as usual.
+	HeaderSpec[ReqT any] interface {
+		ParseHeaders(method, path string) (originalModel internalapi.OriginalModel, req *ReqT, ok bool, err error)
+	}
+	// CacheSpec is optionally implemented by the Spec of the endpoints whose responses can be served from the
+	// response cache. The responses of the other endpoints are never cached.
+	//
+	// CacheText returns the text of the request compared by the semantic lookup, or an empty string if the request
+	// must only be matched exactly, such as when it has non-text content or tools. StreamFromCache converts the
+	// cached non-streaming response body to the body of the streaming response to the request.
+	CacheSpec[ReqT any] interface {
+		CacheText(req *ReqT) string
+		StreamFromCache(req *ReqT, body []byte) ([]byte, error)
+	}
+	// PIISpec is optionally implemented by the Spec of the endpoints whose requests are checked by the PII guardrail.
+	//
+	// RedactRequest returns the request body with the texts of the request, such as the contents of the messages,
+	// replaced by the return values of redact, or nil if no text is changed. StreamText identifies the text deltas
+	// in the events of the streaming responses where the placeholders are restored, as a pii.StreamTextFunc.
+	PIISpec interface {
+		RedactRequest(body []byte, redact func(string) string) ([]byte, error)
+		StreamText(data []byte) (key, path string, end bool)
+	}
+	// GuardrailSpec is optionally implemented by the Spec of the endpoints whose prompts and completions are checked
+	// by the external guardrail.
+	//
+	// PromptMessages returns the text messages of the request body, such as the system prompt and the contents of the
+	// messages, normalized to their roles and texts. CompletionText returns the text of the non-streaming response
+	// body. StreamText is the same as [PIISpec.StreamText].
+	GuardrailSpec interface {
+		PromptMessages(body []byte) []guardrail.Message
+		CompletionText(body []byte) string
+		StreamText(data []byte) (key, path string, end bool)
+	}
 	// ChatCompletionsEndpointSpec implements EndpointSpec for /v1/chat/completions.
 	ChatCompletionsEndpointSpec struct{}
 	// CompletionsEndpointSpec implements EndpointSpec for /v1/completions.
@@ -92,6 +162,24 @@ type (
 	RerankEndpointSpec struct{}
 	// SpeechEndpointSpec implements EndpointSpec for /v1/audio/speech.
 	SpeechEndpointSpec struct{}
+	// AudioTranscriptionsEndpointSpec implements EndpointSpec for /v1/audio/transcriptions.
+	AudioTranscriptionsEndpointSpec struct{}
+	// AudioTranslationsEndpointSpec implements EndpointSpec for /v1/audio/translations.
+	AudioTranslationsEndpointSpec struct{}
+	// GenerateContentEndpointSpec implements EndpointSpec for the Gemini /v1beta/models/{model}:generateContent
+	// and /v1beta/models/{model}:streamGenerateContent.
+	GenerateContentEndpointSpec struct{}
+	// ConverseEndpointSpec implements EndpointSpec for the AWS Bedrock /model/{modelId}/converse
+	// and /model/{modelId}/converse-stream.
+	ConverseEndpointSpec struct{}
+	// FilesEndpointSpec implements EndpointSpec for /v1/files and /v1/files/{file_id}.
+	FilesEndpointSpec struct{}
+	// BatchesEndpointSpec implements EndpointSpec for /v1/batches and /v1/batches/{batch_id}.
+	BatchesEndpointSpec struct{}
+	// ModerationsEndpointSpec implements EndpointSpec for /v1/moderations.
+	ModerationsEndpointSpec struct{}
+	// CountTokensEndpointSpec implements EndpointSpec for /v1/messages/count_tokens.
+	CountTokensEndpointSpec struct{}
 )
 
 // ParseBody implements [EndpointSpec.ParseBody].
@@ -137,13 +225,148 @@ func (ChatCompletionsEndpointSpec) GetTranslator(schema filterapi.VersionedAPISc
 		return translator.NewChatCompletionOpenAIToAzureOpenAITranslator(schema.Version, modelNameOverride), nil
 	case filterapi.APISchemaGCPVertexAI:
 		return translator.NewChatCompletionOpenAIToGCPVertexAITranslator(modelNameOverride), nil
+	case filterapi.APISchemaGoogleAIStudio:
+		return translator.NewChatCompletionOpenAIToGoogleAIStudioTranslator(modelNameOverride), nil
 	case filterapi.APISchemaGCPAnthropic:
 		return translator.NewChatCompletionOpenAIToGCPAnthropicTranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaAnthropic:
+		return translator.NewChatCompletionOpenAIToAnthropicTranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaCohere:
+		return translator.NewChatCompletionOpenAIToCohereTranslator(schema.Version, modelNameOverride), nil
 	default:
 		return nil, fmt.Errorf("unsupported API schema: backend=%s", schema)
 	}
 }
 
+// CacheText implements [CacheSpec.CacheText].
+//
+// The text is the content of the messages prefixed by their roles. The requests with tools, tool calls or
+// non-text content are only matched exactly.
+func (ChatCompletionsEndpointSpec) CacheText(req *openai.ChatCompletionRequest) string {
+	if len(req.Tools) > 0 {
+		return ""
+	}
+	var b strings.Builder
+	for i := range req.Messages {
+		role, text, ok := chatMessageText(&req.Messages[i])
+		if !ok {
+			return ""
+		}
+		b.WriteString(role)
+		b.WriteString(": ")
+		b.WriteString(text)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// StreamFromCache implements [CacheSpec.StreamFromCache].
+func (ChatCompletionsEndpointSpec) StreamFromCache(req *openai.ChatCompletionRequest, body []byte) ([]byte, error) {
+	return translator.ChatCompletionResponseToStream(body, req.StreamOptions != nil && req.StreamOptions.IncludeUsage)
+}
+
+// RedactRequest implements [PIISpec.RedactRequest].
+//
+// The texts are the contents of the messages.
+func (ChatCompletionsEndpointSpec) RedactRequest(body []byte, redact func(string) string) ([]byte, error) {
+	var paths []string
+	gjson.GetBytes(body, "messages").ForEach(func(i, msg gjson.Result) bool {
+		paths = appendContentTextPaths(paths, "messages."+i.String()+".content", msg.Get("content"))
+		return true
+	})
+	return redactTexts(body, paths, redact)
+}
+
+// StreamText implements [PIISpec.StreamText].
+//
+// The text is the content of the first choice of the chunk, which ends with the finish reason.
+func (ChatCompletionsEndpointSpec) StreamText(data []byte) (key, path string, end bool) {
+	choice := gjson.GetBytes(data, "choices.0")
+	if !choice.Exists() {
+		return "", "", false
+	}
+	if choice.Get("delta.content").Type == gjson.String {
+		path = "choices.0.delta.content"
+	}
+	return "choice:" + choice.Get("index").String(), path, choice.Get("finish_reason").Type == gjson.String
+}
+
+// PromptMessages implements [GuardrailSpec.PromptMessages].
+func (ChatCompletionsEndpointSpec) PromptMessages(body []byte) []guardrail.Message {
+	var messages []guardrail.Message
+	gjson.GetBytes(body, "messages").ForEach(func(_, msg gjson.Result) bool {
+		messages = appendMessage(messages, msg.Get("role").String(), msg.Get("content"))
+		return true
+	})
+	return messages
+}
+
+// CompletionText implements [GuardrailSpec.CompletionText].
+//
+// The text is the content of the message of the first choice.
+func (ChatCompletionsEndpointSpec) CompletionText(body []byte) string {
+	return gjson.GetBytes(body, "choices.0.message.content").String()
+}
+
+// chatMessageText returns the role and the text content of the message. ok is false if the message has tool
+// calls or non-text content.
+func chatMessageText(msg *openai.ChatCompletionMessageParamUnion) (role, text string, ok bool) {
+	switch {
+	case msg.OfUser != nil:
+		switch v := msg.OfUser.Content.Value.(type) {
+		case string:
+			return msg.OfUser.Role, v, true
+		case []openai.ChatCompletionContentPartUserUnionParam:
+			texts := make([]string, 0, len(v))
+			for _, part := range v {
+				if part.OfText == nil {
+					return "", "", false
+				}
+				texts = append(texts, part.OfText.Text)
+			}
+			return msg.OfUser.Role, strings.Join(texts, "\n"), true
+		}
+	case msg.OfAssistant != nil:
+		if len(msg.OfAssistant.ToolCalls) > 0 {
+			return "", "", false
+		}
+		switch v := msg.OfAssistant.Content.Value.(type) {
+		case nil:
+			return msg.OfAssistant.Role, "", true
+		case string:
+			return msg.OfAssistant.Role, v, true
+		case openai.ChatCompletionAssistantMessageParamContent:
+			return msg.OfAssistant.Role, ptr.Deref(v.Text, ptr.Deref(v.Refusal, "")), true
+		case []openai.ChatCompletionAssistantMessageParamContent:
+			texts := make([]string, 0, len(v))
+			for _, part := range v {
+				texts = append(texts, ptr.Deref(part.Text, ptr.Deref(part.Refusal, "")))
+			}
+			return msg.OfAssistant.Role, strings.Join(texts, "\n"), true
+		}
+	case msg.OfSystem != nil:
+		return msg.OfSystem.Role, contentUnionText(msg.OfSystem.Content), true
+	case msg.OfDeveloper != nil:
+		return msg.OfDeveloper.Role, contentUnionText(msg.OfDeveloper.Content), true
+	}
+	return "", "", false
+}
+
+// contentUnionText returns the text of the ContentUnion.
+func contentUnionText(content openai.ContentUnion) string {
+	switch v := content.Value.(type) {
+	case string:
+		return v
+	case []openai.ChatCompletionContentPartTextParam:
+		texts := make([]string, len(v))
+		for i, part := range v {
+			texts[i] = part.Text
+		}
+		return strings.Join(texts, "\n")
+	}
+	return ""
+}
+
 // RedactSensitiveInfoFromRequest implements [EndpointSpec.RedactSensitiveInfoFromRequest].
 func (ChatCompletionsEndpointSpec) RedactSensitiveInfoFromRequest(req *openai.ChatCompletionRequest) (redactedReq *openai.ChatCompletionRequest, err error) {
 	// Create a shallow copy of the request
@@ -248,13 +471,29 @@ func (EmbeddingsEndpointSpec) GetTranslator(schema filterapi.VersionedAPISchema,
 		return translator.NewEmbeddingOpenAIToAzureOpenAITranslator(schema.Version, modelNameOverride), nil
 	case filterapi.APISchemaGCPVertexAI:
 		return translator.NewEmbeddingOpenAIToGCPVertexAITranslator("", modelNameOverride), nil
+	case filterapi.APISchemaGoogleAIStudio:
+		return translator.NewEmbeddingOpenAIToGoogleAIStudioTranslator("", modelNameOverride), nil
 	case filterapi.APISchemaAWSBedrock:
 		return translator.NewEmbeddingOpenAIToAWSBedrockTranslator(modelNameOverride), nil
+	case filterapi.APISchemaCohere:
+		return translator.NewEmbeddingOpenAIToCohereTranslator(schema.Version, modelNameOverride), nil
 	default:
 		return nil, fmt.Errorf("unsupported API schema: backend=%s", schema)
 	}
 }
 
+// CacheText implements [CacheSpec.CacheText].
+//
+// The embeddings of similar inputs are not interchangeable, so the requests are only matched exactly.
+func (EmbeddingsEndpointSpec) CacheText(*openai.EmbeddingRequest) string { return "" }
+
+// StreamFromCache implements [CacheSpec.StreamFromCache].
+//
+// This is never called since the embedding requests are never streaming.
+func (EmbeddingsEndpointSpec) StreamFromCache(*openai.EmbeddingRequest, []byte) ([]byte, error) {
+	return nil, errors.New("embeddings responses cannot be streamed")
+}
+
 // RedactSensitiveInfoFromRequest implements [EndpointSpec.RedactSensitiveInfoFromRequest].
 func (EmbeddingsEndpointSpec) RedactSensitiveInfoFromRequest(req *openai.EmbeddingRequest) (redactedReq *openai.EmbeddingRequest, err error) {
 	// Placeholder if redaction is required in future
@@ -277,6 +516,12 @@ func (ImageGenerationEndpointSpec) GetTranslator(schema filterapi.VersionedAPISc
 	switch schema.Name {
 	case filterapi.APISchemaOpenAI:
 		return translator.NewImageGenerationOpenAIToOpenAITranslator(schema.OpenAIPrefix(), modelNameOverride), nil
+	case filterapi.APISchemaAzureOpenAI:
+		return translator.NewImageGenerationOpenAIToAzureOpenAITranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaGCPVertexAI:
+		return translator.NewImageGenerationOpenAIToGCPVertexAITranslator(modelNameOverride), nil
+	case filterapi.APISchemaAWSBedrock:
+		return translator.NewImageGenerationOpenAIToAWSBedrockTranslator(modelNameOverride), nil
 	default:
 		return nil, fmt.Errorf("unsupported API schema: backend=%s", schema)
 	}
@@ -305,11 +550,89 @@ func (ResponsesEndpointSpec) GetTranslator(schema filterapi.VersionedAPISchema,
 	switch schema.Name {
 	case filterapi.APISchemaOpenAI:
 		return translator.NewResponsesOpenAIToOpenAITranslator(schema.OpenAIPrefix(), modelNameOverride), nil
+	case filterapi.APISchemaAnthropic:
+		return translator.NewResponsesToAnthropicTranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaAWSBedrock:
+		return translator.NewResponsesToAWSBedrockTranslator(modelNameOverride), nil
+	case filterapi.APISchemaGCPVertexAI:
+		return translator.NewResponsesToGCPVertexAITranslator(modelNameOverride), nil
 	default:
 		return nil, fmt.Errorf("unsupported API schema: backend=%s", schema)
 	}
 }
 
+// RedactRequest implements [PIISpec.RedactRequest].
+//
+// The texts are the instructions and the contents of the input messages.
+func (ResponsesEndpointSpec) RedactRequest(body []byte, redact func(string) string) ([]byte, error) {
+	var paths []string
+	if gjson.GetBytes(body, "instructions").Type == gjson.String {
+		paths = append(paths, "instructions")
+	}
+	input := gjson.GetBytes(body, "input")
+	if input.Type == gjson.String {
+		paths = append(paths, "input")
+	}
+	input.ForEach(func(i, item gjson.Result) bool {
+		paths = appendContentTextPaths(paths, "input."+i.String()+".content", item.Get("content"))
+		return true
+	})
+	return redactTexts(body, paths, redact)
+}
+
+// StreamText implements [PIISpec.StreamText].
+//
+// The texts are the output texts of the content parts of the output items.
+func (ResponsesEndpointSpec) StreamText(data []byte) (key, path string, end bool) {
+	event := gjson.ParseBytes(data)
+	switch event.Get("type").String() {
+	case "response.output_text.delta":
+		path = "delta"
+	case "response.output_text.done":
+		end = true
+	default:
+		return "", "", false
+	}
+	return event.Get("item_id").String() + ":" + event.Get("content_index").String(), path, end
+}
+
+// PromptMessages implements [GuardrailSpec.PromptMessages].
+//
+// The instructions are the system message, and the outputs of the function calls are the tool messages.
+func (ResponsesEndpointSpec) PromptMessages(body []byte) []guardrail.Message {
+	messages := appendMessage(nil, openai.ChatMessageRoleSystem, gjson.GetBytes(body, "instructions"))
+	input := gjson.GetBytes(body, "input")
+	if input.Type == gjson.String {
+		return appendMessage(messages, openai.ChatMessageRoleUser, input)
+	}
+	input.ForEach(func(_, item gjson.Result) bool {
+		if item.Get("type").String() == "function_call_output" {
+			messages = appendMessage(messages, openai.ChatMessageRoleTool, item.Get("output"))
+		} else {
+			messages = appendMessage(messages, item.Get("role").String(), item.Get("content"))
+		}
+		return true
+	})
+	return messages
+}
+
+// CompletionText implements [GuardrailSpec.CompletionText].
+//
+// The text is the output texts of the output messages.
+func (ResponsesEndpointSpec) CompletionText(body []byte) string {
+	var texts []string
+	gjson.GetBytes(body, "output").ForEach(func(_, item gjson.Result) bool {
+		item.Get("content").ForEach(func(_, part gjson.Result) bool {
+			if part.Get("type").String() == "output_text" {
+				texts = append(texts, part.Get("text").String())
+			}
+			return true
+		})
+		return true
+	})
+	return strings.Join(texts, "\n")
+}
+
 // RedactSensitiveInfoFromRequest implements [EndpointSpec.RedactSensitiveInfoFromRequest].
 func (ResponsesEndpointSpec) RedactSensitiveInfoFromRequest(req *openai.ResponseRequest) (redactedReq *openai.ResponseRequest, err error) {
 	// Placeholder if redaction is required in future
@@ -337,7 +660,8 @@ func (MessagesEndpointSpec) ParseBody(
 
 // GetTranslator implements [EndpointSpec.GetTranslator].
 func (MessagesEndpointSpec) GetTranslator(schema filterapi.VersionedAPISchema, modelNameOverride string) (translator.AnthropicMessagesTranslator, error) {
-	// Messages processor only supports Anthropic-native translators.
+	// Messages processor supports Anthropic-native translators as well as the OpenAI, AWS Bedrock Converse and
+	// GCP Vertex AI Gemini translations.
 	switch schema.Name {
 	case filterapi.APISchemaGCPAnthropic:
 		return translator.NewAnthropicToGCPAnthropicTranslator(schema.Version, modelNameOverride), nil
@@ -347,9 +671,182 @@ func (MessagesEndpointSpec) GetTranslator(schema filterapi.VersionedAPISchema, m
 		return translator.NewAnthropicToAnthropicTranslator(schema.Version, modelNameOverride), nil
 	case filterapi.APISchemaOpenAI:
 		return translator.NewAnthropicToChatCompletionOpenAITranslator(schema.OpenAIPrefix(), modelNameOverride), nil
+	case filterapi.APISchemaAWSBedrock:
+		return translator.NewAnthropicToAWSBedrockTranslator(modelNameOverride), nil
+	case filterapi.APISchemaGCPVertexAI:
+		return translator.NewAnthropicToGCPVertexAITranslator(modelNameOverride), nil
+	default:
+		return nil, fmt.Errorf("/v1/messages endpoint only supports backends that return native Anthropic format (Anthropic, GCPAnthropic, AWSAnthropic). OpenAI, AWSBedrock and GCPVertexAI translation is also supported. Backend %s uses different model format", schema.Name)
+	}
+}
+
+// CacheText implements [CacheSpec.CacheText].
+//
+// The text is the system prompt followed by the content of the messages prefixed by their roles. The requests
+// with tools or non-text content blocks are only matched exactly.
+func (MessagesEndpointSpec) CacheText(req *anthropic.MessagesRequest) string {
+	if len(req.Tools) > 0 {
+		return ""
+	}
+	var b strings.Builder
+	if system := req.System; system != nil {
+		b.WriteString("system: ")
+		b.WriteString(system.Text)
+		for i := range system.Texts {
+			b.WriteString(system.Texts[i].Text)
+		}
+		b.WriteByte('\n')
+	}
+	for i := range req.Messages {
+		msg := &req.Messages[i]
+		b.WriteString(string(msg.Role))
+		b.WriteString(": ")
+		b.WriteString(msg.Content.Text)
+		for j := range msg.Content.Array {
+			block := &msg.Content.Array[j]
+			if block.Text == nil {
+				return ""
+			}
+			b.WriteString(block.Text.Text)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// StreamFromCache implements [CacheSpec.StreamFromCache].
+func (MessagesEndpointSpec) StreamFromCache(_ *anthropic.MessagesRequest, body []byte) ([]byte, error) {
+	return translator.MessagesResponseToStream(body)
+}
+
+// RedactRequest implements [PIISpec.RedactRequest].
+//
+// The texts are the system prompt and the text blocks of the messages, including the ones of the tool results.
+func (MessagesEndpointSpec) RedactRequest(body []byte, redact func(string) string) ([]byte, error) {
+	paths := appendContentTextPaths(nil, "system", gjson.GetBytes(body, "system"))
+	gjson.GetBytes(body, "messages").ForEach(func(i, msg gjson.Result) bool {
+		path := "messages." + i.String() + ".content"
+		content := msg.Get("content")
+		paths = appendContentTextPaths(paths, path, content)
+		content.ForEach(func(j, block gjson.Result) bool {
+			if block.Get("type").String() == "tool_result" {
+				paths = appendContentTextPaths(paths, path+"."+j.String()+".content", block.Get("content"))
+			}
+			return true
+		})
+		return true
+	})
+	return redactTexts(body, paths, redact)
+}
+
+// StreamText implements [PIISpec.StreamText].
+//
+// The texts are the text content blocks, which end with the content_block_stop events.
+func (MessagesEndpointSpec) StreamText(data []byte) (key, path string, end bool) {
+	event := gjson.ParseBytes(data)
+	switch event.Get("type").String() {
+	case "content_block_delta":
+		if event.Get("delta.type").String() != "text_delta" {
+			return "", "", false
+		}
+		path = "delta.text"
+	case "content_block_stop":
+		end = true
 	default:
-		return nil, fmt.Errorf("/v1/messages endpoint only supports backends that return native Anthropic format (Anthropic, GCPAnthropic, AWSAnthropic). OpenAI translation is also supported. Backend %s uses different model format", schema.Name)
+		return "", "", false
 	}
+	return "block:" + event.Get("index").String(), path, end
+}
+
+// PromptMessages implements [GuardrailSpec.PromptMessages].
+//
+// The contents of the tool results are the tool messages following the message they are in.
+func (MessagesEndpointSpec) PromptMessages(body []byte) []guardrail.Message {
+	messages := appendMessage(nil, openai.ChatMessageRoleSystem, gjson.GetBytes(body, "system"))
+	gjson.GetBytes(body, "messages").ForEach(func(_, msg gjson.Result) bool {
+		content := msg.Get("content")
+		messages = appendMessage(messages, msg.Get("role").String(), content)
+		content.ForEach(func(_, block gjson.Result) bool {
+			if block.Get("type").String() == "tool_result" {
+				messages = appendMessage(messages, openai.ChatMessageRoleTool, block.Get("content"))
+			}
+			return true
+		})
+		return true
+	})
+	return messages
+}
+
+// CompletionText implements [GuardrailSpec.CompletionText].
+//
+// The text is the text content blocks.
+func (MessagesEndpointSpec) CompletionText(body []byte) string {
+	var texts []string
+	gjson.GetBytes(body, "content").ForEach(func(_, block gjson.Result) bool {
+		if block.Get("type").String() == "text" {
+			texts = append(texts, block.Get("text").String())
+		}
+		return true
+	})
+	return strings.Join(texts, "\n")
+}
+
+// appendMessage appends the message of the role with the texts of the content, which is either a string or an array
+// of parts with the text in the "text" field. The message is not appended if the content has no text.
+func appendMessage(messages []guardrail.Message, role string, content gjson.Result) []guardrail.Message {
+	var text string
+	if content.Type == gjson.String {
+		text = content.String()
+	} else {
+		var texts []string
+		content.ForEach(func(_, part gjson.Result) bool {
+			if t := part.Get("text"); t.Type == gjson.String {
+				texts = append(texts, t.String())
+			}
+			return true
+		})
+		text = strings.Join(texts, "\n")
+	}
+	if text == "" {
+		return messages
+	}
+	return append(messages, guardrail.Message{Role: role, Content: text})
+}
+
+// appendContentTextPaths appends the paths of the texts of the content at the path, which is either a string or an
+// array of parts with the text in the "text" field.
+func appendContentTextPaths(paths []string, path string, content gjson.Result) []string {
+	if content.Type == gjson.String {
+		return append(paths, path)
+	}
+	content.ForEach(func(i, part gjson.Result) bool {
+		if part.Get("text").Type == gjson.String {
+			paths = append(paths, path+"."+i.String()+".text")
+		}
+		return true
+	})
+	return paths
+}
+
+// redactTexts returns the body with the strings at the paths replaced by the return values of redact, or nil if no
+// string is changed.
+func redactTexts(body []byte, paths []string, redact func(string) string) ([]byte, error) {
+	var redactedBody []byte
+	for _, path := range paths {
+		text := gjson.GetBytes(body, path).String()
+		redacted := redact(text)
+		if redacted == text {
+			continue
+		}
+		if redactedBody == nil {
+			redactedBody = bytes.Clone(body)
+		}
+		var err error
+		if redactedBody, err = sjson.SetBytes(redactedBody, path, redacted); err != nil {
+			return nil, fmt.Errorf("failed to redact %s: %w", path, err)
+		}
+	}
+	return redactedBody, nil
 }
 
 // RedactSensitiveInfoFromRequest implements [EndpointSpec.RedactSensitiveInfoFromRequest].
@@ -375,6 +872,12 @@ func (RerankEndpointSpec) GetTranslator(schema filterapi.VersionedAPISchema, mod
 	switch schema.Name {
 	case filterapi.APISchemaCohere:
 		return translator.NewRerankCohereToCohereTranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaOpenAI:
+		return translator.NewRerankCohereToOpenAITranslator(schema.OpenAIPrefix(), modelNameOverride), nil
+	case filterapi.APISchemaAWSBedrock:
+		return translator.NewRerankCohereToAWSBedrockTranslator(modelNameOverride), nil
+	case filterapi.APISchemaGCPVertexAI:
+		return translator.NewRerankCohereToGCPVertexAITranslator(modelNameOverride), nil
 	default:
 		return nil, fmt.Errorf("unsupported API schema: backend=%s", schema)
 	}
@@ -616,6 +1119,12 @@ func (SpeechEndpointSpec) GetTranslator(
 			schema.OpenAIPrefix(),
 			modelNameOverride,
 		), nil
+	case filterapi.APISchemaAzureOpenAI:
+		return translator.NewSpeechOpenAIToAzureOpenAITranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaGCPVertexAI:
+		return translator.NewSpeechOpenAIToGCPVertexAITranslator(modelNameOverride), nil
+	case filterapi.APISchemaGoogleAIStudio:
+		return translator.NewSpeechOpenAIToGoogleAIStudioTranslator(modelNameOverride), nil
 	default:
 		return nil, fmt.Errorf("unsupported API schema for speech: backend=%s", schema)
 	}
@@ -637,3 +1146,696 @@ func (SpeechEndpointSpec) RedactSensitiveInfoFromRequest(req *openai.SpeechReque
 
 	return &redacted, nil
 }
+
+// ParseBody implements [EndpointSpec.ParseBody].
+func (s AudioTranscriptionsEndpointSpec) ParseBody(
+	body []byte,
+	costConfigured bool,
+) (internalapi.OriginalModel, *openai.TranscriptionRequest, bool, []byte, error) {
+	form, err := multipartform.Parse(body, "")
+	if err != nil {
+		return "", nil, false, nil, fmt.Errorf("%w: failed to parse multipart form for /v1/audio/transcriptions: %w", internalapi.ErrMalformedRequest, err)
+	}
+	return s.ParseForm(form, costConfigured)
+}
+
+// ParseForm implements [FormSpec.ParseForm].
+func (AudioTranscriptionsEndpointSpec) ParseForm(
+	form *multipartform.Form,
+	_ bool,
+) (internalapi.OriginalModel, *openai.TranscriptionRequest, bool, []byte, error) {
+	req, err := parseTranscriptionForm(form)
+	if err != nil {
+		return "", nil, false, nil, fmt.Errorf("%w: failed to parse multipart form for /v1/audio/transcriptions: %w", internalapi.ErrMalformedRequest, err)
+	}
+	return req.Model, req, req.Stream, nil, nil
+}
+
+// GetTranslator implements [EndpointSpec.GetTranslator].
+func (AudioTranscriptionsEndpointSpec) GetTranslator(
+	schema filterapi.VersionedAPISchema,
+	modelNameOverride string,
+) (translator.OpenAITranscriptionTranslator, error) {
+	switch schema.Name {
+	case filterapi.APISchemaOpenAI:
+		return translator.NewAudioTranscriptionOpenAIToOpenAITranslator(schema.OpenAIPrefix(), modelNameOverride), nil
+	case filterapi.APISchemaAzureOpenAI:
+		return translator.NewAudioTranscriptionOpenAIToAzureOpenAITranslator(schema.Version, modelNameOverride), nil
+	default:
+		return nil, fmt.Errorf("unsupported API schema for audio transcriptions: backend=%s", schema)
+	}
+}
+
+// RedactSensitiveInfoFromRequest implements [EndpointSpec.RedactSensitiveInfoFromRequest].
+func (AudioTranscriptionsEndpointSpec) RedactSensitiveInfoFromRequest(req *openai.TranscriptionRequest) (*openai.TranscriptionRequest, error) {
+	return redactTranscriptionRequest(req), nil
+}
+
+// ParseBody implements [EndpointSpec.ParseBody].
+func (s AudioTranslationsEndpointSpec) ParseBody(
+	body []byte,
+	costConfigured bool,
+) (internalapi.OriginalModel, *openai.TranscriptionRequest, bool, []byte, error) {
+	form, err := multipartform.Parse(body, "")
+	if err != nil {
+		return "", nil, false, nil, fmt.Errorf("%w: failed to parse multipart form for /v1/audio/translations: %w", internalapi.ErrMalformedRequest, err)
+	}
+	return s.ParseForm(form, costConfigured)
+}
+
+// ParseForm implements [FormSpec.ParseForm].
+func (AudioTranslationsEndpointSpec) ParseForm(
+	form *multipartform.Form,
+	_ bool,
+) (internalapi.OriginalModel, *openai.TranscriptionRequest, bool, []byte, error) {
+	req, err := parseTranscriptionForm(form)
+	if err != nil {
+		return "", nil, false, nil, fmt.Errorf("%w: failed to parse multipart form for /v1/audio/translations: %w", internalapi.ErrMalformedRequest, err)
+	}
+	// The translations endpoint does not support streaming.
+	req.Stream = false
+	return req.Model, req, false, nil, nil
+}
+
+// GetTranslator implements [EndpointSpec.GetTranslator].
+func (AudioTranslationsEndpointSpec) GetTranslator(
+	schema filterapi.VersionedAPISchema,
+	modelNameOverride string,
+) (translator.OpenAITranscriptionTranslator, error) {
+	switch schema.Name {
+	case filterapi.APISchemaOpenAI:
+		return translator.NewAudioTranslationOpenAIToOpenAITranslator(schema.OpenAIPrefix(), modelNameOverride), nil
+	case filterapi.APISchemaAzureOpenAI:
+		return translator.NewAudioTranslationOpenAIToAzureOpenAITranslator(schema.Version, modelNameOverride), nil
+	default:
+		return nil, fmt.Errorf("unsupported API schema for audio translations: backend=%s", schema)
+	}
+}
+
+// RedactSensitiveInfoFromRequest implements [EndpointSpec.RedactSensitiveInfoFromRequest].
+func (AudioTranslationsEndpointSpec) RedactSensitiveInfoFromRequest(req *openai.TranscriptionRequest) (*openai.TranscriptionRequest, error) {
+	return redactTranscriptionRequest(req), nil
+}
+
+// parseTranscriptionForm parses the form fields of the multipart/form-data body of the speech-to-text endpoints.
+// The audio file is not copied, only its name and size are recorded.
+func parseTranscriptionForm(form *multipartform.Form) (*openai.TranscriptionRequest, error) {
+	var req openai.TranscriptionRequest
+	for _, part := range form.Parts() {
+		if part.IsFile() {
+			req.FileName, req.FileSize = part.FileName, part.Size()
+			continue
+		}
+		value := string(part.Content())
+		switch part.Name {
+		case "model":
+			req.Model = value
+		case "language":
+			req.Language = &value
+		case "prompt":
+			req.Prompt = &value
+		case "response_format":
+			req.ResponseFormat = &value
+		case "temperature":
+			temperature, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid temperature %q", value)
+			}
+			req.Temperature = &temperature
+		case "stream":
+			stream, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid stream %q", value)
+			}
+			req.Stream = stream
+		}
+	}
+	return &req, nil
+}
+
+// redactTranscriptionRequest creates a copy of the speech-to-text request with the prompt redacted.
+func redactTranscriptionRequest(req *openai.TranscriptionRequest) *openai.TranscriptionRequest {
+	redacted := *req
+	if req.Prompt != nil {
+		redactedPrompt := redaction.RedactString(*req.Prompt)
+		redacted.Prompt = &redactedPrompt
+	}
+	return &redacted
+}
+
+// ParseBody implements [EndpointSpec.ParseBody].
+//
+// The Gemini API has the model in the request path, so this always fails without the path. See [GenerateContentEndpointSpec.ParsePath].
+func (s GenerateContentEndpointSpec) ParseBody(
+	body []byte,
+	costConfigured bool,
+) (internalapi.OriginalModel, *gcp.GenerateContentRequest, bool, []byte, error) {
+	return s.ParsePath("", body, costConfigured)
+}
+
+// ParsePath implements [PathSpec.ParsePath].
+//
+// The path is of the form .../models/{model}:{method} where the method is either generateContent or
+// streamGenerateContent, optionally followed by a query string such as ?alt=sse.
+func (GenerateContentEndpointSpec) ParsePath(
+	path string,
+	body []byte,
+	_ bool,
+) (internalapi.OriginalModel, *gcp.GenerateContentRequest, bool, []byte, error) {
+	path, _, _ = strings.Cut(path, "?")
+	_, modelAndMethod, ok := strings.Cut(path, "/models/")
+	if !ok {
+		return "", nil, false, nil, fmt.Errorf("%w: missing model in the path %q", internalapi.ErrInvalidRequestBody, path)
+	}
+	model, method, _ := strings.Cut(modelAndMethod, ":")
+	if model == "" {
+		return "", nil, false, nil, fmt.Errorf("%w: missing model in the path %q", internalapi.ErrInvalidRequestBody, path)
+	}
+	var stream bool
+	switch method {
+	case "generateContent":
+	case "streamGenerateContent":
+		stream = true
+	default:
+		return "", nil, false, nil, fmt.Errorf("%w: unsupported method %q, only generateContent and streamGenerateContent are supported", internalapi.ErrInvalidRequestBody, method)
+	}
+
+	var req gcp.GenerateContentRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return "", nil, false, nil, fmt.Errorf("%w: failed to parse JSON for %s: %w", internalapi.ErrMalformedRequest, method, err)
+	}
+	req.Model, req.Stream = model, stream
+	return model, &req, stream, nil, nil
+}
+
+// GetTranslator implements [EndpointSpec.GetTranslator].
+func (GenerateContentEndpointSpec) GetTranslator(
+	schema filterapi.VersionedAPISchema,
+	modelNameOverride string,
+) (translator.GeminiGenerateContentTranslator, error) {
+	switch schema.Name {
+	case filterapi.APISchemaGCPVertexAI:
+		return translator.NewGenerateContentToGCPVertexAITranslator(modelNameOverride), nil
+	case filterapi.APISchemaGoogleAIStudio:
+		return translator.NewGenerateContentToGoogleAIStudioTranslator(modelNameOverride), nil
+	case filterapi.APISchemaOpenAI:
+		return translator.NewGenerateContentToOpenAITranslator(schema.OpenAIPrefix(), modelNameOverride), nil
+	case filterapi.APISchemaAnthropic:
+		return translator.NewGenerateContentToAnthropicTranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaAWSBedrock:
+		return translator.NewGenerateContentToAWSBedrockTranslator(modelNameOverride), nil
+	default:
+		return nil, fmt.Errorf("unsupported API schema for generate content: backend=%s", schema)
+	}
+}
+
+// RedactSensitiveInfoFromRequest implements [EndpointSpec.RedactSensitiveInfoFromRequest].
+func (GenerateContentEndpointSpec) RedactSensitiveInfoFromRequest(req *gcp.GenerateContentRequest) (*gcp.GenerateContentRequest, error) {
+	redacted := *req
+	if req.SystemInstruction != nil {
+		redacted.SystemInstruction = redactGenAIContent(req.SystemInstruction)
+	}
+	if len(req.Contents) > 0 {
+		redacted.Contents = make([]genai.Content, len(req.Contents))
+		for i := range req.Contents {
+			redacted.Contents[i] = *redactGenAIContent(&req.Contents[i])
+		}
+	}
+	return &redacted, nil
+}
+
+// redactGenAIContent creates a copy of the Gemini content with the text, inline data and function arguments and
+// responses of the parts redacted.
+func redactGenAIContent(content *genai.Content) *genai.Content {
+	redacted := *content
+	redacted.Parts = make([]*genai.Part, len(content.Parts))
+	for i, part := range content.Parts {
+		if part == nil {
+			continue
+		}
+		redactedPart := *part
+		if part.Text != "" {
+			redactedPart.Text = redaction.RedactString(part.Text)
+		}
+		if part.InlineData != nil {
+			redactedPart.InlineData = &genai.Blob{
+				MIMEType: part.InlineData.MIMEType,
+				Data:     []byte(redaction.RedactString(string(part.InlineData.Data))),
+			}
+		}
+		if part.FunctionCall != nil {
+			redactedPart.FunctionCall = &genai.FunctionCall{ID: part.FunctionCall.ID, Name: part.FunctionCall.Name}
+		}
+		if part.FunctionResponse != nil {
+			redactedPart.FunctionResponse = &genai.FunctionResponse{ID: part.FunctionResponse.ID, Name: part.FunctionResponse.Name}
+		}
+		redacted.Parts[i] = &redactedPart
+	}
+	return &redacted
+}
+
+// ParseBody implements [EndpointSpec.ParseBody].
+//
+// The Converse API has the model in the request path, so this always fails without the path. See [ConverseEndpointSpec.ParsePath].
+func (s ConverseEndpointSpec) ParseBody(
+	body []byte,
+	costConfigured bool,
+) (internalapi.OriginalModel, *awsbedrock.ConverseInput, bool, []byte, error) {
+	return s.ParsePath("", body, costConfigured)
+}
+
+// ParsePath implements [PathSpec.ParsePath].
+//
+// The path is of the form .../model/{modelId}/{method} where the method is either converse or converse-stream.
+// The model ID is URL encoded by the AWS SDKs since it can be an ARN containing slashes.
+func (ConverseEndpointSpec) ParsePath(
+	path string,
+	body []byte,
+	_ bool,
+) (internalapi.OriginalModel, *awsbedrock.ConverseInput, bool, []byte, error) {
+	path, _, _ = strings.Cut(path, "?")
+	_, modelAndMethod, ok := strings.Cut(path, "/model/")
+	if !ok {
+		return "", nil, false, nil, fmt.Errorf("%w: missing model in the path %q", internalapi.ErrInvalidRequestBody, path)
+	}
+	var method string
+	if i := strings.LastIndex(modelAndMethod, "/"); i != -1 {
+		modelAndMethod, method = modelAndMethod[:i], modelAndMethod[i+1:]
+	}
+	model, err := url.PathUnescape(modelAndMethod)
+	if err != nil || model == "" {
+		return "", nil, false, nil, fmt.Errorf("%w: missing model in the path %q", internalapi.ErrInvalidRequestBody, path)
+	}
+	var stream bool
+	switch method {
+	case "converse":
+	case "converse-stream":
+		stream = true
+	default:
+		return "", nil, false, nil, fmt.Errorf("%w: unsupported method %q, only converse and converse-stream are supported", internalapi.ErrInvalidRequestBody, method)
+	}
+
+	var req awsbedrock.ConverseInput
+	if err := json.Unmarshal(body, &req); err != nil {
+		return "", nil, false, nil, fmt.Errorf("%w: failed to parse JSON for %s: %w", internalapi.ErrMalformedRequest, method, err)
+	}
+	req.ModelID, req.Stream = model, stream
+	return model, &req, stream, nil, nil
+}
+
+// GetTranslator implements [EndpointSpec.GetTranslator].
+func (ConverseEndpointSpec) GetTranslator(
+	schema filterapi.VersionedAPISchema,
+	modelNameOverride string,
+) (translator.AWSBedrockConverseTranslator, error) {
+	switch schema.Name {
+	case filterapi.APISchemaAWSBedrock:
+		return translator.NewConverseToAWSBedrockTranslator(modelNameOverride), nil
+	case filterapi.APISchemaAnthropic:
+		return translator.NewConverseToAnthropicTranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaGCPAnthropic:
+		return translator.NewConverseToGCPAnthropicTranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaAWSAnthropic:
+		return translator.NewConverseToAWSAnthropicTranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaOpenAI:
+		return translator.NewConverseToOpenAITranslator(schema.OpenAIPrefix(), modelNameOverride), nil
+	case filterapi.APISchemaGCPVertexAI:
+		return translator.NewConverseToGCPVertexAITranslator(modelNameOverride), nil
+	default:
+		return nil, fmt.Errorf("unsupported API schema for converse: backend=%s", schema)
+	}
+}
+
+// RedactSensitiveInfoFromRequest implements [EndpointSpec.RedactSensitiveInfoFromRequest].
+func (ConverseEndpointSpec) RedactSensitiveInfoFromRequest(req *awsbedrock.ConverseInput) (*awsbedrock.ConverseInput, error) {
+	redacted := *req
+	if len(req.System) > 0 {
+		redacted.System = make([]*awsbedrock.SystemContentBlock, len(req.System))
+		for i, block := range req.System {
+			if block == nil || block.Text == nil {
+				redacted.System[i] = block
+				continue
+			}
+			redactedBlock := *block
+			redactedBlock.Text = ptr.To(redaction.RedactString(*block.Text))
+			redacted.System[i] = &redactedBlock
+		}
+	}
+	if len(req.Messages) > 0 {
+		redacted.Messages = make([]*awsbedrock.Message, len(req.Messages))
+		for i, msg := range req.Messages {
+			if msg == nil {
+				continue
+			}
+			redactedMsg := *msg
+			redactedMsg.Content = make([]*awsbedrock.ContentBlock, len(msg.Content))
+			for j, block := range msg.Content {
+				if block != nil {
+					redactedMsg.Content[j] = redactConverseContentBlock(block)
+				}
+			}
+			redacted.Messages[i] = &redactedMsg
+		}
+	}
+	return &redacted, nil
+}
+
+// redactConverseContentBlock creates a copy of the Converse content block with the text, the image and document
+// bytes and the tool inputs and results redacted.
+func redactConverseContentBlock(block *awsbedrock.ContentBlock) *awsbedrock.ContentBlock {
+	redacted := *block
+	if block.Text != nil {
+		redacted.Text = ptr.To(redaction.RedactString(*block.Text))
+	}
+	if block.Image != nil {
+		image := *block.Image
+		image.Source.Bytes = []byte(redaction.RedactString(string(block.Image.Source.Bytes)))
+		redacted.Image = &image
+	}
+	if block.Document != nil {
+		document := *block.Document
+		document.Source.Bytes = []byte(redaction.RedactString(string(block.Document.Source.Bytes)))
+		redacted.Document = &document
+	}
+	if block.ToolUse != nil {
+		redacted.ToolUse = &awsbedrock.ToolUseBlock{ToolUseID: block.ToolUse.ToolUseID, Name: block.ToolUse.Name}
+	}
+	if block.ToolResult != nil {
+		redacted.ToolResult = &awsbedrock.ToolResultBlock{ToolUseID: block.ToolResult.ToolUseID, Status: block.ToolResult.Status}
+	}
+	return &redacted
+}
+
+// ParseBody implements [EndpointSpec.ParseBody].
+func (s FilesEndpointSpec) ParseBody(
+	body []byte,
+	costConfigured bool,
+) (internalapi.OriginalModel, *openai.FileRequest, bool, []byte, error) {
+	form, err := multipartform.Parse(body, "")
+	if err != nil {
+		return "", nil, false, nil, fmt.Errorf("%w: failed to parse multipart form for /v1/files: %w", internalapi.ErrMalformedRequest, err)
+	}
+	return s.ParseForm(form, costConfigured)
+}
+
+// ParseForm implements [FormSpec.ParseForm].
+//
+// Only the batch input files are supported since the file has to be routed to a backend by the model of its requests.
+// All the requests in the file must have the same model.
+func (FilesEndpointSpec) ParseForm(
+	form *multipartform.Form,
+	_ bool,
+) (internalapi.OriginalModel, *openai.FileRequest, bool, []byte, error) {
+	purpose, _ := form.Value("purpose")
+	if purpose != "batch" {
+		return "", nil, false, nil, fmt.Errorf("%w: unsupported purpose %q, only batch files are supported", internalapi.ErrInvalidRequestBody, purpose)
+	}
+	file := form.Part("file")
+	if file == nil || !file.IsFile() {
+		return "", nil, false, nil, fmt.Errorf("%w: missing file in the multipart form for /v1/files", internalapi.ErrInvalidRequestBody)
+	}
+	model, err := batchInputModel(file.Content())
+	if err != nil {
+		return "", nil, false, nil, err
+	}
+	req := &openai.FileRequest{
+		Purpose:   purpose,
+		FileName:  file.FileName,
+		FileSize:  file.Size(),
+		Model:     model,
+		Operation: openai.FileOperationUpload,
+	}
+	return model, req, false, nil, nil
+}
+
+// batchInputModel returns the model of the requests in the JSONL batch input file.
+func batchInputModel(jsonl []byte) (string, error) {
+	var model string
+	for i, line := range bytes.Split(jsonl, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var input openai.BatchInputLine
+		if err := json.Unmarshal(line, &input); err != nil {
+			return "", fmt.Errorf("%w: failed to parse line %d of the batch input: %w", internalapi.ErrInvalidRequestBody, i+1, err)
+		}
+		switch {
+		case input.Body.Model == "":
+			return "", fmt.Errorf("%w: missing model in line %d of the batch input", internalapi.ErrInvalidRequestBody, i+1)
+		case model == "":
+			model = input.Body.Model
+		case model != input.Body.Model:
+			return "", fmt.Errorf("%w: all the requests of the batch input must have the same model, got %q and %q in line %d",
+				internalapi.ErrInvalidRequestBody, model, input.Body.Model, i+1)
+		}
+	}
+	if model == "" {
+		return "", fmt.Errorf("%w: empty batch input", internalapi.ErrInvalidRequestBody)
+	}
+	return model, nil
+}
+
+// ParseHeaders implements [HeaderSpec.ParseHeaders].
+//
+// The operations on a file are routed by the model encoded in the file ID, see [internalapi.EncodeModelScopedID].
+// The upload is parsed from the body, and listing the files is not supported since they are spread across backends.
+func (FilesEndpointSpec) ParseHeaders(method, path string) (internalapi.OriginalModel, *openai.FileRequest, bool, error) {
+	path, _, _ = strings.Cut(path, "?")
+	_, resource, _ := strings.Cut(path, "/v1/files")
+	resource = strings.TrimPrefix(resource, "/")
+	if resource == "" {
+		if method == http.MethodPost {
+			return "", nil, false, nil
+		}
+		return "", nil, false, fmt.Errorf("%w: listing the files is not supported", internalapi.ErrInvalidRequestBody)
+	}
+
+	id, subresource, _ := strings.Cut(resource, "/")
+	var operation openai.FileOperation
+	switch {
+	case method == http.MethodGet && subresource == "":
+		operation = openai.FileOperationRetrieve
+	case method == http.MethodGet && subresource == "content":
+		operation = openai.FileOperationContent
+	case method == http.MethodDelete && subresource == "":
+		operation = openai.FileOperationDelete
+	default:
+		return "", nil, false, fmt.Errorf("%w: unsupported operation %s %s", internalapi.ErrInvalidRequestBody, method, path)
+	}
+	model, fileID, ok := internalapi.DecodeModelScopedID(openai.FileIDPrefix, id)
+	if !ok {
+		return "", nil, false, fmt.Errorf("%w: unknown file ID %q", internalapi.ErrInvalidRequestBody, id)
+	}
+	return model, &openai.FileRequest{Model: model, Operation: operation, FileID: fileID}, true, nil
+}
+
+// GetTranslator implements [EndpointSpec.GetTranslator].
+func (FilesEndpointSpec) GetTranslator(schema filterapi.VersionedAPISchema, modelNameOverride string) (translator.OpenAIFilesTranslator, error) {
+	switch schema.Name {
+	case filterapi.APISchemaOpenAI:
+		return translator.NewFilesOpenAIToOpenAITranslator(schema.OpenAIPrefix(), modelNameOverride), nil
+	case filterapi.APISchemaAzureOpenAI:
+		return translator.NewFilesOpenAIToAzureOpenAITranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaAWSBedrock:
+		return translator.NewFilesStorageTranslator("AWS Bedrock", "S3"), nil
+	case filterapi.APISchemaGCPVertexAI:
+		return translator.NewFilesStorageTranslator("GCP Vertex AI", "Cloud Storage"), nil
+	default:
+		return nil, fmt.Errorf("unsupported API schema for files, only OpenAI and Azure OpenAI accept uploads: backend=%s", schema)
+	}
+}
+
+// RedactSensitiveInfoFromRequest implements [EndpointSpec.RedactSensitiveInfoFromRequest].
+//
+// The content of the file is not kept in the request, so there is nothing to redact.
+func (FilesEndpointSpec) RedactSensitiveInfoFromRequest(req *openai.FileRequest) (*openai.FileRequest, error) {
+	redacted := *req
+	return &redacted, nil
+}
+
+// ParseBody implements [EndpointSpec.ParseBody].
+//
+// The batch is routed by the model encoded in the input file ID when the file is uploaded through the gateway.
+// Otherwise, e.g. when the input is the storage URI of the provider, the model is taken from the batch metadata.
+func (BatchesEndpointSpec) ParseBody(
+	body []byte,
+	_ bool,
+) (internalapi.OriginalModel, *openai.BatchRequest, bool, []byte, error) {
+	var req openai.BatchRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return "", nil, false, nil, fmt.Errorf("%w: failed to parse JSON for /v1/batches: %w", internalapi.ErrMalformedRequest, err)
+	}
+	req.Operation = openai.BatchOperationCreate
+	if model, fileID, ok := internalapi.DecodeModelScopedID(openai.FileIDPrefix, req.InputFileID); ok {
+		req.Model, req.InputFileID = model, fileID
+	} else {
+		req.Model = req.Metadata[internalapi.BatchMetadataModel]
+	}
+	if req.Model == "" {
+		return "", nil, false, nil, fmt.Errorf("%w: unknown model of the batch, either upload the input file through /v1/files or set metadata.%s",
+			internalapi.ErrInvalidRequestBody, internalapi.BatchMetadataModel)
+	}
+	return req.Model, &req, false, nil, nil
+}
+
+// ParseHeaders implements [HeaderSpec.ParseHeaders].
+//
+// The operations on a batch are routed by the model encoded in the batch ID, see [internalapi.EncodeModelScopedID].
+// The creation is parsed from the body, and listing the batches is not supported since they are spread across backends.
+func (BatchesEndpointSpec) ParseHeaders(method, path string) (internalapi.OriginalModel, *openai.BatchRequest, bool, error) {
+	path, _, _ = strings.Cut(path, "?")
+	_, resource, _ := strings.Cut(path, "/v1/batches")
+	resource = strings.TrimPrefix(resource, "/")
+	if resource == "" {
+		if method == http.MethodPost {
+			return "", nil, false, nil
+		}
+		return "", nil, false, fmt.Errorf("%w: listing the batches is not supported", internalapi.ErrInvalidRequestBody)
+	}
+
+	id, subresource, _ := strings.Cut(resource, "/")
+	var operation openai.BatchOperation
+	switch {
+	case method == http.MethodGet && subresource == "":
+		operation = openai.BatchOperationRetrieve
+	case method == http.MethodPost && subresource == "cancel":
+		operation = openai.BatchOperationCancel
+	default:
+		return "", nil, false, fmt.Errorf("%w: unsupported operation %s %s", internalapi.ErrInvalidRequestBody, method, path)
+	}
+	model, batchID, ok := internalapi.DecodeModelScopedID(openai.BatchIDPrefix, id)
+	if !ok {
+		return "", nil, false, fmt.Errorf("%w: unknown batch ID %q", internalapi.ErrInvalidRequestBody, id)
+	}
+	return model, &openai.BatchRequest{Model: model, Operation: operation, BatchID: batchID}, true, nil
+}
+
+// GetTranslator implements [EndpointSpec.GetTranslator].
+func (BatchesEndpointSpec) GetTranslator(schema filterapi.VersionedAPISchema, modelNameOverride string) (translator.OpenAIBatchesTranslator, error) {
+	switch schema.Name {
+	case filterapi.APISchemaOpenAI:
+		return translator.NewBatchesOpenAIToOpenAITranslator(schema.OpenAIPrefix(), modelNameOverride), nil
+	case filterapi.APISchemaAzureOpenAI:
+		return translator.NewBatchesOpenAIToAzureOpenAITranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaAWSBedrock:
+		return translator.NewBatchesOpenAIToAWSBedrockTranslator(modelNameOverride), nil
+	case filterapi.APISchemaGCPVertexAI:
+		return translator.NewBatchesOpenAIToGCPVertexAITranslator(modelNameOverride), nil
+	default:
+		return nil, fmt.Errorf("unsupported API schema for batches: backend=%s", schema)
+	}
+}
+
+// RedactSensitiveInfoFromRequest implements [EndpointSpec.RedactSensitiveInfoFromRequest].
+//
+// The batch request only references the input file, so there is nothing to redact.
+func (BatchesEndpointSpec) RedactSensitiveInfoFromRequest(req *openai.BatchRequest) (*openai.BatchRequest, error) {
+	redacted := *req
+	return &redacted, nil
+}
+
+// ParseBody implements [EndpointSpec.ParseBody].
+func (ModerationsEndpointSpec) ParseBody(
+	body []byte,
+	_ bool,
+) (internalapi.OriginalModel, *openai.ModerationRequest, bool, []byte, error) {
+	var req openai.ModerationRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return "", nil, false, nil, fmt.Errorf("%w: failed to parse JSON for /v1/moderations: %w", internalapi.ErrMalformedRequest, err)
+	}
+	// The model is optional in the OpenAI API, so the default moderation model is used for the routing.
+	req.Model = cmp.Or(req.Model, openai.ModerationModelOmniLatest)
+	return req.Model, &req, false, nil, nil
+}
+
+// GetTranslator implements [EndpointSpec.GetTranslator].
+//
+// The OpenAI moderation models are served by the moderations endpoint of OpenAI, and Azure AI Content Safety by its
+// text:analyze API. Any other model of a chat backend is used as an LLM classifier.
+func (ModerationsEndpointSpec) GetTranslator(schema filterapi.VersionedAPISchema, modelNameOverride string) (translator.OpenAIModerationTranslator, error) {
+	switch schema.Name {
+	case filterapi.APISchemaAzureContentSafety:
+		return translator.NewModerationOpenAIToAzureContentSafetyTranslator(schema.Version, modelNameOverride), nil
+	case filterapi.APISchemaOpenAI, filterapi.APISchemaAzureOpenAI, filterapi.APISchemaAWSBedrock, filterapi.APISchemaAWSAnthropic,
+		filterapi.APISchemaGCPVertexAI, filterapi.APISchemaGoogleAIStudio, filterapi.APISchemaGCPAnthropic,
+		filterapi.APISchemaAnthropic, filterapi.APISchemaCohere:
+		chat, err := ChatCompletionsEndpointSpec{}.GetTranslator(schema, modelNameOverride)
+		if err != nil {
+			return nil, err
+		}
+		classifier := translator.NewModerationToChatCompletionTranslator(chat)
+		if schema.Name == filterapi.APISchemaOpenAI {
+			return translator.NewModerationOpenAIToOpenAITranslator(schema.OpenAIPrefix(), modelNameOverride, classifier), nil
+		}
+		return classifier, nil
+	default:
+		return nil, fmt.Errorf("unsupported API schema for moderations: backend=%s", schema)
+	}
+}
+
+// RedactSensitiveInfoFromRequest implements [EndpointSpec.RedactSensitiveInfoFromRequest].
+func (ModerationsEndpointSpec) RedactSensitiveInfoFromRequest(req *openai.ModerationRequest) (*openai.ModerationRequest, error) {
+	redacted := *req
+	switch v := req.Input.Value.(type) {
+	case string:
+		redacted.Input.Value = redaction.RedactString(v)
+	case []string:
+		texts := make([]string, len(v))
+		for i, text := range v {
+			texts[i] = redaction.RedactString(text)
+		}
+		redacted.Input.Value = texts
+	case []openai.ModerationMultiModalInput:
+		parts := make([]openai.ModerationMultiModalInput, len(v))
+		for i, part := range v {
+			parts[i] = part
+			if part.Text != "" {
+				parts[i].Text = redaction.RedactString(part.Text)
+			}
+			if part.ImageURL != nil {
+				parts[i].ImageURL = &openai.ModerationImageURL{URL: redaction.RedactString(part.ImageURL.URL)}
+			}
+		}
+		redacted.Input.Value = parts
+	}
+	return &redacted, nil
+}
+
+// ParseBody implements [EndpointSpec.ParseBody].
+//
+// The request has the same fields as the messages request except max_tokens and stream, so it is parsed as such.
+func (CountTokensEndpointSpec) ParseBody(
+	body []byte,
+	_ bool,
+) (internalapi.OriginalModel, *anthropic.MessagesRequest, bool, []byte, error) {
+	var req anthropic.MessagesRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return "", nil, false, nil, fmt.Errorf("%w: failed to parse JSON for /v1/messages/count_tokens: %w", internalapi.ErrMalformedRequest, err)
+	}
+	if req.Model == "" {
+		return "", nil, false, nil, fmt.Errorf("%w: model field is required", internalapi.ErrInvalidRequestBody)
+	}
+	return req.Model, &req, false, nil, nil
+}
+
+// GetTranslator implements [EndpointSpec.GetTranslator].
+//
+// The Anthropic backends count the tokens themselves. For the other backends, which have no token counting API for
+// the Anthropic format, the count is estimated in the gateway with the tokenizer of the model family.
+func (CountTokensEndpointSpec) GetTranslator(schema filterapi.VersionedAPISchema, modelNameOverride string) (translator.AnthropicCountTokensTranslator, error) {
+	switch schema.Name {
+	case filterapi.APISchemaAnthropic:
+		return translator.NewCountTokensAnthropicToAnthropicTranslator(modelNameOverride), nil
+	case filterapi.APISchemaGCPAnthropic:
+		return translator.NewCountTokensAnthropicToGCPAnthropicTranslator(modelNameOverride), nil
+	case filterapi.APISchemaAWSAnthropic:
+		return translator.NewCountTokensAnthropicToAWSAnthropicTranslator(schema.Version, modelNameOverride), nil
+	default:
+		return translator.NewCountTokensEstimationTranslator(modelNameOverride), nil
+	}
+}
+
+// RedactSensitiveInfoFromRequest implements [EndpointSpec.RedactSensitiveInfoFromRequest].
+func (CountTokensEndpointSpec) RedactSensitiveInfoFromRequest(req *anthropic.MessagesRequest) (*anthropic.MessagesRequest, error) {
+	return MessagesEndpointSpec{}.RedactSensitiveInfoFromRequest(req)
+}
@@ -6,16 +6,27 @@
 package endpointspec
 
 import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
 	"k8s.io/utils/ptr"
 
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
 	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
 	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/guardrail"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/multipartform"
 	"github.com/envoyproxy/ai-gateway/internal/redaction"
+	"github.com/envoyproxy/ai-gateway/internal/translator"
 )
 
 func TestChatCompletionsEndpointSpec_ParseBody(t *testing.T) {
@@ -84,7 +95,10 @@ func TestChatCompletionsEndpointSpec_GetTranslator(t *testing.T) {
 		{Name: filterapi.APISchemaAWSAnthropic},
 		{Name: filterapi.APISchemaAzureOpenAI, Version: "2024-02-01"},
 		{Name: filterapi.APISchemaGCPVertexAI},
+		{Name: filterapi.APISchemaGoogleAIStudio},
 		{Name: filterapi.APISchemaGCPAnthropic, Version: "2024-05-01"},
+		{Name: filterapi.APISchemaAnthropic},
+		{Name: filterapi.APISchemaCohere, Version: "v2"},
 	}
 
 	for _, schema := range supported {
@@ -164,7 +178,9 @@ func TestEmbeddingsEndpointSpec_GetTranslator(t *testing.T) {
 		{Name: filterapi.APISchemaOpenAI},
 		{Name: filterapi.APISchemaAzureOpenAI},
 		{Name: filterapi.APISchemaGCPVertexAI},
+		{Name: filterapi.APISchemaGoogleAIStudio},
 		{Name: filterapi.APISchemaAWSBedrock},
+		{Name: filterapi.APISchemaCohere, Version: "v2"},
 	}
 	for _, schema := range supported {
 		s := schema
@@ -177,7 +193,7 @@ func TestEmbeddingsEndpointSpec_GetTranslator(t *testing.T) {
 	}
 
 	t.Run("unsupported", func(t *testing.T) {
-		_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaCohere}, "override")
+		_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaAnthropic}, "override")
 		require.ErrorContains(t, err, "unsupported API schema")
 	})
 }
@@ -206,10 +222,17 @@ func TestImageGenerationEndpointSpec_ParseBody(t *testing.T) {
 func TestImageGenerationEndpointSpec_GetTranslator(t *testing.T) {
 	spec := ImageGenerationEndpointSpec{}
 
-	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI}, "override")
-	require.NoError(t, err)
+	for _, schema := range []filterapi.APISchemaName{
+		filterapi.APISchemaOpenAI,
+		filterapi.APISchemaAzureOpenAI,
+		filterapi.APISchemaAWSBedrock,
+		filterapi.APISchemaGCPVertexAI,
+	} {
+		_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: schema}, "override")
+		require.NoError(t, err, schema)
+	}
 
-	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaAzureOpenAI}, "override")
+	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaAnthropic}, "override")
 	require.ErrorContains(t, err, "unsupported API schema")
 }
 
@@ -249,6 +272,8 @@ func TestMessagesEndpointSpec_GetTranslator(t *testing.T) {
 		{Name: filterapi.APISchemaAWSAnthropic},
 		{Name: filterapi.APISchemaAnthropic},
 		{Name: filterapi.APISchemaOpenAI}, // This is for OpenAI-schema backends like vLLM that support the /v1/messages endpoint
+		{Name: filterapi.APISchemaAWSBedrock},
+		{Name: filterapi.APISchemaGCPVertexAI},
 	} {
 		translator, err := spec.GetTranslator(schema, "override")
 		require.NoError(t, err)
@@ -283,10 +308,17 @@ func TestRerankEndpointSpec_ParseBody(t *testing.T) {
 func TestRerankEndpointSpec_GetTranslator(t *testing.T) {
 	spec := RerankEndpointSpec{}
 
-	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaCohere}, "override")
-	require.NoError(t, err)
+	for _, name := range []filterapi.APISchemaName{
+		filterapi.APISchemaCohere,
+		filterapi.APISchemaOpenAI,
+		filterapi.APISchemaAWSBedrock,
+		filterapi.APISchemaGCPVertexAI,
+	} {
+		_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: name}, "override")
+		require.NoError(t, err, name)
+	}
 
-	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI}, "override")
+	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaAnthropic}, "override")
 	require.ErrorContains(t, err, "unsupported API schema")
 }
 
@@ -335,10 +367,17 @@ func TestResponsesEndpointSpec_ParseBody(t *testing.T) {
 func TestResponsesEndpointSpec_GetTranslator(t *testing.T) {
 	spec := ResponsesEndpointSpec{}
 
-	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI}, "override")
-	require.NoError(t, err)
+	for _, schema := range []filterapi.APISchemaName{
+		filterapi.APISchemaOpenAI,
+		filterapi.APISchemaAnthropic,
+		filterapi.APISchemaAWSBedrock,
+		filterapi.APISchemaGCPVertexAI,
+	} {
+		_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: schema}, "override")
+		require.NoError(t, err, schema)
+	}
 
-	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaAzureOpenAI}, "override")
+	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaAzureOpenAI}, "override")
 	require.ErrorContains(t, err, "unsupported API schema")
 }
 
@@ -935,7 +974,16 @@ func TestSpeechEndpointSpec_GetTranslator(t *testing.T) {
 	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI}, "override")
 	require.NoError(t, err)
 
-	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaAzureOpenAI}, "override")
+	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaAzureOpenAI, Version: "2025-03-01-preview"}, "override")
+	require.NoError(t, err)
+
+	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaGCPVertexAI}, "override")
+	require.NoError(t, err)
+
+	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaGoogleAIStudio}, "override")
+	require.NoError(t, err)
+
+	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaAWSBedrock}, "override")
 	require.ErrorContains(t, err, "unsupported API schema for speech")
 }
 
@@ -1014,3 +1062,932 @@ func TestSpeechEndpointSpec_RedactSensitiveInfoFromRequest(t *testing.T) {
 		require.Equal(t, "sse", *redacted.StreamFormat)
 	})
 }
+
+// audioForm returns a multipart/form-data body with the given fields and an audio file.
+func audioForm(t *testing.T, fields ...string) []byte {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for i := 0; i < len(fields); i += 2 {
+		require.NoError(t, w.WriteField(fields[i], fields[i+1]))
+	}
+	fw, err := w.CreateFormFile("file", "speech.mp3")
+	require.NoError(t, err)
+	_, err = fw.Write([]byte("audio data"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestAudioTranscriptionsEndpointSpec_ParseBody(t *testing.T) {
+	spec := AudioTranscriptionsEndpointSpec{}
+
+	t.Run("not multipart", func(t *testing.T) {
+		_, _, _, _, err := spec.ParseBody([]byte(`{"model":"whisper-1"}`), false)
+		require.ErrorContains(t, err, "failed to parse multipart form for /v1/audio/transcriptions: missing multipart boundary")
+	})
+
+	t.Run("invalid temperature", func(t *testing.T) {
+		_, _, _, _, err := spec.ParseBody(audioForm(t, "model", "whisper-1", "temperature", "hot"), false)
+		require.ErrorContains(t, err, `invalid temperature "hot"`)
+	})
+
+	t.Run("all fields", func(t *testing.T) {
+		body := audioForm(t,
+			"model", "gpt-4o-transcribe",
+			"language", "en",
+			"prompt", "Transcribe carefully",
+			"response_format", "json",
+			"temperature", "0.2",
+			"stream", "true",
+		)
+		model, parsed, stream, mutated, err := spec.ParseBody(body, false)
+		require.NoError(t, err)
+		require.Equal(t, "gpt-4o-transcribe", model)
+		require.True(t, stream)
+		require.Nil(t, mutated)
+		require.Equal(t, &openai.TranscriptionRequest{
+			Model:          "gpt-4o-transcribe",
+			FileName:       "speech.mp3",
+			FileSize:       len("audio data"),
+			Language:       ptr.To("en"),
+			Prompt:         ptr.To("Transcribe carefully"),
+			ResponseFormat: ptr.To("json"),
+			Temperature:    ptr.To(0.2),
+			Stream:         true,
+		}, parsed)
+	})
+}
+
+func TestAudioTranscriptionsEndpointSpec_ParseForm(t *testing.T) {
+	var spec FormSpec[openai.TranscriptionRequest] = AudioTranscriptionsEndpointSpec{}
+	body := audioForm(t, "model", "whisper-1", "stream", "true")
+	firstLine, _, _ := bytes.Cut(body, []byte("\r\n"))
+	// With the boundary of the content-type header, the form can be parsed even with a preamble.
+	form, err := multipartform.Parse(append([]byte("preamble\r\n"), body...), string(firstLine[2:]))
+	require.NoError(t, err)
+
+	model, parsed, stream, mutated, err := spec.ParseForm(form, false)
+	require.NoError(t, err)
+	require.Equal(t, "whisper-1", model)
+	require.True(t, stream)
+	require.Nil(t, mutated)
+	require.Equal(t, "speech.mp3", parsed.FileName)
+	require.Equal(t, len("audio data"), parsed.FileSize)
+}
+
+func TestAudioTranscriptionsEndpointSpec_GetTranslator(t *testing.T) {
+	spec := AudioTranscriptionsEndpointSpec{}
+
+	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI}, "override")
+	require.NoError(t, err)
+
+	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaAzureOpenAI, Version: "2025-03-01-preview"}, "override")
+	require.NoError(t, err)
+
+	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaAWSBedrock}, "override")
+	require.ErrorContains(t, err, "unsupported API schema for audio transcriptions")
+}
+
+func TestAudioTranslationsEndpointSpec_ParseBody(t *testing.T) {
+	spec := AudioTranslationsEndpointSpec{}
+
+	model, parsed, stream, mutated, err := spec.ParseBody(audioForm(t, "model", "whisper-1", "stream", "true"), false)
+	require.NoError(t, err)
+	require.Equal(t, "whisper-1", model)
+	require.False(t, stream, "translations do not support streaming")
+	require.False(t, parsed.Stream)
+	require.Nil(t, mutated)
+
+	_, _, _, _, err = spec.ParseBody([]byte("model=whisper-1"), false)
+	require.ErrorContains(t, err, "failed to parse multipart form for /v1/audio/translations")
+}
+
+func TestAudioTranslationsEndpointSpec_GetTranslator(t *testing.T) {
+	spec := AudioTranslationsEndpointSpec{}
+
+	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI}, "")
+	require.NoError(t, err)
+
+	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaAzureOpenAI, Version: "2025-03-01-preview"}, "")
+	require.NoError(t, err)
+
+	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaGCPVertexAI}, "")
+	require.ErrorContains(t, err, "unsupported API schema for audio translations")
+}
+
+func TestAudioTranscriptionsEndpointSpec_RedactSensitiveInfoFromRequest(t *testing.T) {
+	req := &openai.TranscriptionRequest{Model: "whisper-1", FileName: "speech.mp3", Prompt: ptr.To("secret context")}
+	redacted, err := AudioTranscriptionsEndpointSpec{}.RedactSensitiveInfoFromRequest(req)
+	require.NoError(t, err)
+	require.Contains(t, *redacted.Prompt, "[REDACTED LENGTH=")
+	require.Equal(t, "whisper-1", redacted.Model)
+	require.Equal(t, "speech.mp3", redacted.FileName)
+	// The original request must not be modified.
+	require.Equal(t, "secret context", *req.Prompt)
+}
+
+func TestGenerateContentEndpointSpec_ParsePath(t *testing.T) {
+	var spec PathSpec[gcp.GenerateContentRequest] = GenerateContentEndpointSpec{}
+	body := []byte(`{"contents":[{"role":"user","parts":[{"text":"Hello"}]}],"generationConfig":{"maxOutputTokens":100},"systemInstruction":{"parts":[{"text":"Be brief"}]}}`)
+
+	t.Run("generateContent", func(t *testing.T) {
+		model, parsed, stream, mutated, err := spec.ParsePath("/gemini/v1beta/models/gemini-2.5-flash:generateContent", body, false)
+		require.NoError(t, err)
+		require.Equal(t, "gemini-2.5-flash", model)
+		require.False(t, stream)
+		require.Nil(t, mutated)
+		require.Equal(t, "gemini-2.5-flash", parsed.Model)
+		require.False(t, parsed.Stream)
+		require.Len(t, parsed.Contents, 1)
+		require.Equal(t, "Hello", parsed.Contents[0].Parts[0].Text)
+		require.NotNil(t, parsed.GenerationConfig)
+		require.Equal(t, int32(100), parsed.GenerationConfig.MaxOutputTokens)
+		require.NotNil(t, parsed.SystemInstruction)
+		require.Equal(t, "Be brief", parsed.SystemInstruction.Parts[0].Text)
+	})
+
+	t.Run("streamGenerateContent", func(t *testing.T) {
+		model, parsed, stream, _, err := spec.ParsePath("/v1beta/models/gemini-2.5-pro:streamGenerateContent?alt=sse", body, false)
+		require.NoError(t, err)
+		require.Equal(t, "gemini-2.5-pro", model)
+		require.True(t, stream)
+		require.True(t, parsed.Stream)
+	})
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		body   []byte
+		expErr string
+	}{
+		{name: "no model", path: "/v1beta/generateContent", body: body, expErr: "missing model in the path"},
+		{name: "empty model", path: "/v1beta/models/:generateContent", body: body, expErr: "missing model in the path"},
+		{name: "unsupported method", path: "/v1beta/models/gemini-2.5-flash:countTokens", body: body, expErr: `unsupported method "countTokens"`},
+		{name: "invalid json", path: "/v1beta/models/gemini-2.5-flash:generateContent", body: []byte("not-json"), expErr: "malformed request"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, _, err := spec.ParsePath(tc.path, tc.body, false)
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+
+	t.Run("body only", func(t *testing.T) {
+		_, _, _, _, err := GenerateContentEndpointSpec{}.ParseBody(body, false)
+		require.ErrorContains(t, err, "missing model in the path")
+	})
+}
+
+func TestGenerateContentEndpointSpec_GetTranslator(t *testing.T) {
+	spec := GenerateContentEndpointSpec{}
+	for _, schema := range []filterapi.VersionedAPISchema{
+		{Name: filterapi.APISchemaGCPVertexAI},
+		{Name: filterapi.APISchemaGoogleAIStudio},
+		{Name: filterapi.APISchemaOpenAI, Prefix: "v1"},
+		{Name: filterapi.APISchemaAnthropic},
+		{Name: filterapi.APISchemaAWSBedrock},
+	} {
+		t.Run(string(schema.Name), func(t *testing.T) {
+			translator, err := spec.GetTranslator(schema, "override")
+			require.NoError(t, err)
+			require.NotNil(t, translator)
+		})
+	}
+
+	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaCohere}, "")
+	require.ErrorContains(t, err, "unsupported API schema for generate content")
+}
+
+func TestGenerateContentEndpointSpec_RedactSensitiveInfoFromRequest(t *testing.T) {
+	req := &gcp.GenerateContentRequest{
+		Model:             "gemini-2.5-flash",
+		SystemInstruction: &genai.Content{Parts: []*genai.Part{{Text: "system secret"}}},
+		Contents: []genai.Content{
+			{Role: genai.RoleUser, Parts: []*genai.Part{
+				{Text: "user secret"},
+				{InlineData: &genai.Blob{MIMEType: "image/png", Data: []byte("image bytes")}},
+			}},
+			{Role: genai.RoleModel, Parts: []*genai.Part{
+				{FunctionCall: &genai.FunctionCall{ID: "call_1", Name: "get_weather", Args: map[string]any{"city": "Paris"}}},
+			}},
+			{Role: genai.RoleUser, Parts: []*genai.Part{
+				{FunctionResponse: &genai.FunctionResponse{ID: "call_1", Name: "get_weather", Response: map[string]any{"output": "sunny"}}},
+			}},
+		},
+	}
+	redacted, err := GenerateContentEndpointSpec{}.RedactSensitiveInfoFromRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, "gemini-2.5-flash", redacted.Model)
+	require.Equal(t, redaction.RedactString("system secret"), redacted.SystemInstruction.Parts[0].Text)
+	require.Equal(t, redaction.RedactString("user secret"), redacted.Contents[0].Parts[0].Text)
+	require.Equal(t, "image/png", redacted.Contents[0].Parts[1].InlineData.MIMEType)
+	require.Equal(t, redaction.RedactString("image bytes"), string(redacted.Contents[0].Parts[1].InlineData.Data))
+	require.Equal(t, "get_weather", redacted.Contents[1].Parts[0].FunctionCall.Name)
+	require.Nil(t, redacted.Contents[1].Parts[0].FunctionCall.Args)
+	require.Nil(t, redacted.Contents[2].Parts[0].FunctionResponse.Response)
+
+	// The original request is not modified.
+	require.Equal(t, "user secret", req.Contents[0].Parts[0].Text)
+	require.Equal(t, "Paris", req.Contents[1].Parts[0].FunctionCall.Args["city"])
+}
+
+func TestConverseEndpointSpec_ParsePath(t *testing.T) {
+	var spec PathSpec[awsbedrock.ConverseInput] = ConverseEndpointSpec{}
+	body := []byte(`{"messages":[{"role":"user","content":[{"text":"Hello"}]}],"inferenceConfig":{"maxTokens":100},"system":[{"text":"Be brief"}]}`)
+
+	t.Run("converse", func(t *testing.T) {
+		model, parsed, stream, mutated, err := spec.ParsePath("/bedrock/model/anthropic.claude-sonnet-4-5/converse", body, false)
+		require.NoError(t, err)
+		require.Equal(t, "anthropic.claude-sonnet-4-5", model)
+		require.False(t, stream)
+		require.Nil(t, mutated)
+		require.Equal(t, "anthropic.claude-sonnet-4-5", parsed.ModelID)
+		require.False(t, parsed.Stream)
+		require.Len(t, parsed.Messages, 1)
+		require.Equal(t, "Hello", *parsed.Messages[0].Content[0].Text)
+		require.Equal(t, ptr.To[int64](100), parsed.InferenceConfig.MaxTokens)
+		require.Equal(t, "Be brief", *parsed.System[0].Text)
+	})
+
+	t.Run("converse-stream with ARN", func(t *testing.T) {
+		model, parsed, stream, _, err := spec.ParsePath(
+			"/model/arn%3Aaws%3Abedrock%3Aus-east-1%3A123456789012%3Ainference-profile%2Fus.anthropic.claude-sonnet-4-5/converse-stream", body, false)
+		require.NoError(t, err)
+		require.Equal(t, "arn:aws:bedrock:us-east-1:123456789012:inference-profile/us.anthropic.claude-sonnet-4-5", model)
+		require.True(t, stream)
+		require.True(t, parsed.Stream)
+	})
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		body   []byte
+		expErr string
+	}{
+		{name: "no model", path: "/bedrock/converse", body: body, expErr: "missing model in the path"},
+		{name: "empty model", path: "/model//converse", body: body, expErr: "missing model in the path"},
+		{name: "unsupported method", path: "/model/anthropic.claude-sonnet-4-5/invoke", body: body, expErr: `unsupported method "invoke"`},
+		{name: "invalid json", path: "/model/anthropic.claude-sonnet-4-5/converse", body: []byte("not-json"), expErr: "malformed request"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, _, err := spec.ParsePath(tc.path, tc.body, false)
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+
+	t.Run("body only", func(t *testing.T) {
+		_, _, _, _, err := ConverseEndpointSpec{}.ParseBody(body, false)
+		require.ErrorContains(t, err, "missing model in the path")
+	})
+}
+
+func TestConverseEndpointSpec_GetTranslator(t *testing.T) {
+	spec := ConverseEndpointSpec{}
+	for _, schema := range []filterapi.VersionedAPISchema{
+		{Name: filterapi.APISchemaAWSBedrock},
+		{Name: filterapi.APISchemaAnthropic},
+		{Name: filterapi.APISchemaGCPAnthropic},
+		{Name: filterapi.APISchemaAWSAnthropic},
+		{Name: filterapi.APISchemaOpenAI, Prefix: "v1"},
+		{Name: filterapi.APISchemaGCPVertexAI},
+	} {
+		t.Run(string(schema.Name), func(t *testing.T) {
+			translator, err := spec.GetTranslator(schema, "override")
+			require.NoError(t, err)
+			require.NotNil(t, translator)
+		})
+	}
+
+	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaCohere}, "")
+	require.ErrorContains(t, err, "unsupported API schema for converse")
+}
+
+func TestConverseEndpointSpec_RedactSensitiveInfoFromRequest(t *testing.T) {
+	req := &awsbedrock.ConverseInput{
+		ModelID: "anthropic.claude-sonnet-4-5",
+		System:  []*awsbedrock.SystemContentBlock{{Text: ptr.To("system secret")}},
+		Messages: []*awsbedrock.Message{
+			{Role: awsbedrock.ConversationRoleUser, Content: []*awsbedrock.ContentBlock{
+				{Text: ptr.To("user secret")},
+				{Image: &awsbedrock.ImageBlock{Format: "png", Source: awsbedrock.ImageSource{Bytes: []byte("image bytes")}}},
+			}},
+			{Role: awsbedrock.ConversationRoleAssistant, Content: []*awsbedrock.ContentBlock{
+				{ToolUse: &awsbedrock.ToolUseBlock{ToolUseID: "tooluse_1", Name: "get_weather", Input: map[string]any{"city": "Paris"}}},
+			}},
+			{Role: awsbedrock.ConversationRoleUser, Content: []*awsbedrock.ContentBlock{
+				{ToolResult: &awsbedrock.ToolResultBlock{ToolUseID: ptr.To("tooluse_1"), Content: []*awsbedrock.ToolResultContentBlock{{Text: ptr.To("sunny")}}}},
+			}},
+		},
+	}
+	redacted, err := ConverseEndpointSpec{}.RedactSensitiveInfoFromRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, "anthropic.claude-sonnet-4-5", redacted.ModelID)
+	require.Equal(t, redaction.RedactString("system secret"), *redacted.System[0].Text)
+	require.Equal(t, redaction.RedactString("user secret"), *redacted.Messages[0].Content[0].Text)
+	require.Equal(t, "png", redacted.Messages[0].Content[1].Image.Format)
+	require.Equal(t, redaction.RedactString("image bytes"), string(redacted.Messages[0].Content[1].Image.Source.Bytes))
+	require.Equal(t, "get_weather", redacted.Messages[1].Content[0].ToolUse.Name)
+	require.Nil(t, redacted.Messages[1].Content[0].ToolUse.Input)
+	require.Equal(t, ptr.To("tooluse_1"), redacted.Messages[2].Content[0].ToolResult.ToolUseID)
+	require.Nil(t, redacted.Messages[2].Content[0].ToolResult.Content)
+
+	// The original request is not modified.
+	require.Equal(t, "user secret", *req.Messages[0].Content[0].Text)
+	require.Equal(t, "image bytes", string(req.Messages[0].Content[1].Image.Source.Bytes))
+	require.Equal(t, "Paris", req.Messages[1].Content[0].ToolUse.Input["city"])
+}
+
+// batchInputForm returns a multipart/form-data body of a file upload with the given purpose and JSONL content.
+func batchInputForm(t *testing.T, purpose string, lines ...string) []byte {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	require.NoError(t, w.WriteField("purpose", purpose))
+	fw, err := w.CreateFormFile("file", "batch.jsonl")
+	require.NoError(t, err)
+	for _, line := range lines {
+		_, err = fw.Write([]byte(line + "\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestFilesEndpointSpec_ParseBody(t *testing.T) {
+	spec := FilesEndpointSpec{}
+	line := func(model string) string {
+		return `{"custom_id":"1","method":"POST","url":"/v1/chat/completions","body":{"model":"` + model + `","messages":[]}}`
+	}
+
+	t.Run("batch input", func(t *testing.T) {
+		body := batchInputForm(t, "batch", line("gpt-4o-mini"), line("gpt-4o-mini"))
+		model, parsed, stream, mutated, err := spec.ParseBody(body, false)
+		require.NoError(t, err)
+		require.Equal(t, "gpt-4o-mini", model)
+		require.False(t, stream)
+		require.Nil(t, mutated)
+		require.Equal(t, &openai.FileRequest{
+			Purpose:   "batch",
+			FileName:  "batch.jsonl",
+			FileSize:  2 * (len(line("gpt-4o-mini")) + 1),
+			Model:     "gpt-4o-mini",
+			Operation: openai.FileOperationUpload,
+		}, parsed)
+	})
+
+	for _, tc := range []struct {
+		name   string
+		body   []byte
+		expErr string
+	}{
+		{name: "not multipart", body: []byte(`{"purpose":"batch"}`), expErr: "failed to parse multipart form for /v1/files"},
+		{name: "unsupported purpose", body: batchInputForm(t, "fine-tune", line("gpt-4o-mini")), expErr: `unsupported purpose "fine-tune"`},
+		{name: "empty input", body: batchInputForm(t, "batch"), expErr: "empty batch input"},
+		{name: "invalid line", body: batchInputForm(t, "batch", "not-json"), expErr: "failed to parse line 1 of the batch input"},
+		{name: "missing model", body: batchInputForm(t, "batch", line("")), expErr: "missing model in line 1 of the batch input"},
+		{
+			name:   "mixed models",
+			body:   batchInputForm(t, "batch", line("gpt-4o-mini"), line("gpt-4o")),
+			expErr: `all the requests of the batch input must have the same model, got "gpt-4o-mini" and "gpt-4o" in line 2`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, _, err := spec.ParseBody(tc.body, false)
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestFilesEndpointSpec_ParseHeaders(t *testing.T) {
+	var spec HeaderSpec[openai.FileRequest] = FilesEndpointSpec{}
+	fileID := internalapi.EncodeModelScopedID(openai.FileIDPrefix, "gpt-4o-mini", "file-abc123")
+
+	t.Run("upload", func(t *testing.T) {
+		_, _, ok, err := spec.ParseHeaders(http.MethodPost, "/v1/files")
+		require.NoError(t, err)
+		require.False(t, ok, "the upload is parsed from the body")
+	})
+
+	for _, tc := range []struct {
+		name         string
+		method       string
+		path         string
+		expOperation openai.FileOperation
+	}{
+		{name: "retrieve", method: http.MethodGet, path: "/v1/files/" + fileID, expOperation: openai.FileOperationRetrieve},
+		{name: "content", method: http.MethodGet, path: "/openai/v1/files/" + fileID + "/content?x=y", expOperation: openai.FileOperationContent},
+		{name: "delete", method: http.MethodDelete, path: "/v1/files/" + fileID, expOperation: openai.FileOperationDelete},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			model, parsed, ok, err := spec.ParseHeaders(tc.method, tc.path)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, "gpt-4o-mini", model)
+			require.Equal(t, &openai.FileRequest{Model: "gpt-4o-mini", Operation: tc.expOperation, FileID: "file-abc123"}, parsed)
+		})
+	}
+
+	for _, tc := range []struct {
+		name   string
+		method string
+		path   string
+		expErr string
+	}{
+		{name: "list", method: http.MethodGet, path: "/v1/files?purpose=batch", expErr: "listing the files is not supported"},
+		{name: "unsupported operation", method: http.MethodPost, path: "/v1/files/" + fileID, expErr: "unsupported operation POST"},
+		{name: "unknown file ID", method: http.MethodGet, path: "/v1/files/file-abc123", expErr: `unknown file ID "file-abc123"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, err := spec.ParseHeaders(tc.method, tc.path)
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestFilesEndpointSpec_GetTranslator(t *testing.T) {
+	spec := FilesEndpointSpec{}
+
+	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI}, "override")
+	require.NoError(t, err)
+
+	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaAzureOpenAI, Version: "2025-03-01-preview"}, "override")
+	require.NoError(t, err)
+
+	// The providers whose batch input is in their own storage reject the requests on the files API.
+	for _, schema := range []filterapi.APISchemaName{filterapi.APISchemaAWSBedrock, filterapi.APISchemaGCPVertexAI} {
+		tr, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: schema}, "override")
+		require.NoError(t, err)
+		_, _, err = tr.RequestBody(nil, &openai.FileRequest{Operation: openai.FileOperationUpload}, false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+	}
+
+	_, err = spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaAnthropic}, "override")
+	require.ErrorContains(t, err, "unsupported API schema for files, only OpenAI and Azure OpenAI accept uploads")
+}
+
+func TestBatchesEndpointSpec_ParseBody(t *testing.T) {
+	spec := BatchesEndpointSpec{}
+
+	t.Run("uploaded input file", func(t *testing.T) {
+		fileID := internalapi.EncodeModelScopedID(openai.FileIDPrefix, "gpt-4o-mini", "file-abc123")
+		body := []byte(`{"input_file_id":"` + fileID + `","endpoint":"/v1/chat/completions","completion_window":"24h"}`)
+		model, parsed, stream, mutated, err := spec.ParseBody(body, false)
+		require.NoError(t, err)
+		require.Equal(t, "gpt-4o-mini", model)
+		require.False(t, stream)
+		require.Nil(t, mutated)
+		require.Equal(t, &openai.BatchRequest{
+			InputFileID:      "file-abc123",
+			Endpoint:         "/v1/chat/completions",
+			CompletionWindow: "24h",
+			Model:            "gpt-4o-mini",
+			Operation:        openai.BatchOperationCreate,
+		}, parsed)
+	})
+
+	t.Run("model in metadata", func(t *testing.T) {
+		body := []byte(`{"input_file_id":"s3://bucket/input.jsonl","endpoint":"/v1/chat/completions","completion_window":"24h",` +
+			`"metadata":{"model":"anthropic.claude-3-haiku-20240307-v1:0"}}`)
+		model, parsed, _, _, err := spec.ParseBody(body, false)
+		require.NoError(t, err)
+		require.Equal(t, "anthropic.claude-3-haiku-20240307-v1:0", model)
+		require.Equal(t, "s3://bucket/input.jsonl", parsed.InputFileID)
+	})
+
+	t.Run("unknown model", func(t *testing.T) {
+		_, _, _, _, err := spec.ParseBody([]byte(`{"input_file_id":"file-abc123"}`), false)
+		require.ErrorContains(t, err, "unknown model of the batch")
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, _, _, _, err := spec.ParseBody([]byte("not-json"), false)
+		require.ErrorContains(t, err, "malformed request")
+	})
+}
+
+func TestBatchesEndpointSpec_ParseHeaders(t *testing.T) {
+	var spec HeaderSpec[openai.BatchRequest] = BatchesEndpointSpec{}
+	batchID := internalapi.EncodeModelScopedID(openai.BatchIDPrefix, "gpt-4o-mini", "batch_abc123")
+
+	t.Run("create", func(t *testing.T) {
+		_, _, ok, err := spec.ParseHeaders(http.MethodPost, "/v1/batches")
+		require.NoError(t, err)
+		require.False(t, ok, "the creation is parsed from the body")
+	})
+
+	for _, tc := range []struct {
+		name         string
+		method       string
+		path         string
+		expOperation openai.BatchOperation
+	}{
+		{name: "retrieve", method: http.MethodGet, path: "/v1/batches/" + batchID, expOperation: openai.BatchOperationRetrieve},
+		{name: "cancel", method: http.MethodPost, path: "/v1/batches/" + batchID + "/cancel", expOperation: openai.BatchOperationCancel},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			model, parsed, ok, err := spec.ParseHeaders(tc.method, tc.path)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, "gpt-4o-mini", model)
+			require.Equal(t, &openai.BatchRequest{Model: "gpt-4o-mini", Operation: tc.expOperation, BatchID: "batch_abc123"}, parsed)
+		})
+	}
+
+	for _, tc := range []struct {
+		name   string
+		method string
+		path   string
+		expErr string
+	}{
+		{name: "list", method: http.MethodGet, path: "/v1/batches?limit=10", expErr: "listing the batches is not supported"},
+		{name: "unsupported operation", method: http.MethodDelete, path: "/v1/batches/" + batchID, expErr: "unsupported operation DELETE"},
+		{name: "unknown batch ID", method: http.MethodGet, path: "/v1/batches/batch_abc123", expErr: `unknown batch ID "batch_abc123"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, err := spec.ParseHeaders(tc.method, tc.path)
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestBatchesEndpointSpec_GetTranslator(t *testing.T) {
+	spec := BatchesEndpointSpec{}
+	for _, schema := range []filterapi.VersionedAPISchema{
+		{Name: filterapi.APISchemaOpenAI, Prefix: "v1"},
+		{Name: filterapi.APISchemaAzureOpenAI, Version: "2025-03-01-preview"},
+		{Name: filterapi.APISchemaAWSBedrock},
+		{Name: filterapi.APISchemaGCPVertexAI},
+	} {
+		t.Run(string(schema.Name), func(t *testing.T) {
+			translator, err := spec.GetTranslator(schema, "override")
+			require.NoError(t, err)
+			require.NotNil(t, translator)
+		})
+	}
+
+	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: filterapi.APISchemaCohere}, "")
+	require.ErrorContains(t, err, "unsupported API schema for batches")
+}
+
+func TestModerationsEndpointSpec_ParseBody(t *testing.T) {
+	spec := ModerationsEndpointSpec{}
+
+	t.Run("default model", func(t *testing.T) {
+		model, req, stream, mutated, err := spec.ParseBody([]byte(`{"input":"hello"}`), false)
+		require.NoError(t, err)
+		require.Equal(t, openai.ModerationModelOmniLatest, model)
+		require.Equal(t, &openai.ModerationRequest{Model: openai.ModerationModelOmniLatest, Input: openai.ModerationInput{Value: "hello"}}, req)
+		require.False(t, stream)
+		require.Nil(t, mutated)
+	})
+
+	t.Run("model", func(t *testing.T) {
+		model, req, _, _, err := spec.ParseBody([]byte(`{"model":"gpt-4o-mini","input":["a","b"]}`), false)
+		require.NoError(t, err)
+		require.Equal(t, "gpt-4o-mini", model)
+		require.Equal(t, []string{"a", "b"}, req.Input.Value)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, _, _, _, err := spec.ParseBody([]byte(`{"input":1}`), false)
+		require.ErrorIs(t, err, internalapi.ErrMalformedRequest)
+	})
+}
+
+func TestModerationsEndpointSpec_GetTranslator(t *testing.T) {
+	spec := ModerationsEndpointSpec{}
+	for _, schema := range []filterapi.VersionedAPISchema{
+		{Name: filterapi.APISchemaOpenAI, Prefix: "v1"},
+		{Name: filterapi.APISchemaAzureContentSafety},
+		{Name: filterapi.APISchemaAzureOpenAI, Version: "2024-10-21"},
+		{Name: filterapi.APISchemaAWSBedrock},
+		{Name: filterapi.APISchemaAWSAnthropic},
+		{Name: filterapi.APISchemaGCPVertexAI},
+		{Name: filterapi.APISchemaGoogleAIStudio},
+		{Name: filterapi.APISchemaGCPAnthropic},
+		{Name: filterapi.APISchemaAnthropic},
+		{Name: filterapi.APISchemaCohere},
+	} {
+		t.Run(string(schema.Name), func(t *testing.T) {
+			translator, err := spec.GetTranslator(schema, "override")
+			require.NoError(t, err)
+			require.NotNil(t, translator)
+		})
+	}
+
+	_, err := spec.GetTranslator(filterapi.VersionedAPISchema{Name: "Unknown"}, "")
+	require.ErrorContains(t, err, "unsupported API schema for moderations")
+}
+
+func TestModerationsEndpointSpec_RedactSensitiveInfoFromRequest(t *testing.T) {
+	spec := ModerationsEndpointSpec{}
+	for _, tc := range []struct {
+		name  string
+		input any
+	}{
+		{name: "string", input: "secret"},
+		{name: "strings", input: []string{"secret", "secret"}},
+		{name: "multi-modal", input: []openai.ModerationMultiModalInput{
+			{Type: "text", Text: "secret"},
+			{Type: "image_url", ImageURL: &openai.ModerationImageURL{URL: "data:image/png;base64,secret"}},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &openai.ModerationRequest{Model: "omni-moderation-latest", Input: openai.ModerationInput{Value: tc.input}}
+			redacted, err := spec.RedactSensitiveInfoFromRequest(req)
+			require.NoError(t, err)
+			require.Equal(t, tc.input, req.Input.Value, "original request must not be modified")
+			require.Equal(t, "omni-moderation-latest", redacted.Model)
+
+			b, err := json.Marshal(redacted)
+			require.NoError(t, err)
+			require.NotContains(t, string(b), "secret")
+			require.Contains(t, string(b), "[REDACTED")
+		})
+	}
+}
+
+func TestCountTokensEndpointSpec_ParseBody(t *testing.T) {
+	spec := CountTokensEndpointSpec{}
+
+	t.Run("valid", func(t *testing.T) {
+		model, req, stream, mutated, err := spec.ParseBody([]byte(`{"model":"claude-sonnet-4-5","messages":[{"role":"user","content":"hi"}]}`), false)
+		require.NoError(t, err)
+		require.Equal(t, "claude-sonnet-4-5", model)
+		require.Len(t, req.Messages, 1)
+		require.False(t, stream)
+		require.Nil(t, mutated)
+	})
+
+	t.Run("missing model", func(t *testing.T) {
+		_, _, _, _, err := spec.ParseBody([]byte(`{"messages":[]}`), false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, _, _, _, err := spec.ParseBody([]byte("{"), false)
+		require.ErrorIs(t, err, internalapi.ErrMalformedRequest)
+	})
+}
+
+func TestCountTokensEndpointSpec_GetTranslator(t *testing.T) {
+	spec := CountTokensEndpointSpec{}
+	for _, tc := range []struct {
+		schema   filterapi.VersionedAPISchema
+		expLocal bool
+	}{
+		{schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaAnthropic}},
+		{schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaGCPAnthropic}},
+		{schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaAWSAnthropic, Version: "bedrock-2023-05-31"}},
+		{schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI, Prefix: "v1"}, expLocal: true},
+		{schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaAWSBedrock}, expLocal: true},
+		{schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaGCPVertexAI}, expLocal: true},
+	} {
+		t.Run(string(tc.schema.Name), func(t *testing.T) {
+			tr, err := spec.GetTranslator(tc.schema, "")
+			require.NoError(t, err)
+			_, local := tr.(translator.LocalResponder)
+			require.Equal(t, tc.expLocal, local)
+		})
+	}
+}
+
+func TestChatCompletionsEndpointSpec_CacheText(t *testing.T) {
+	spec := ChatCompletionsEndpointSpec{}
+	for _, tc := range []struct {
+		name string
+		body string
+		exp  string
+	}{
+		{
+			name: "text",
+			body: `{"model":"gpt-4o","messages":[{"role":"system","content":"Be brief."},{"role":"user","content":[{"type":"text","text":"Hi"},{"type":"text","text":"there"}]},
+{"role":"assistant","content":"Hello!"},{"role":"user","content":"What is AI?"}]}`,
+			exp: "system: Be brief.\nuser: Hi\nthere\nassistant: Hello!\nuser: What is AI?\n",
+		},
+		{
+			name: "image",
+			body: `{"model":"gpt-4o","messages":[{"role":"user","content":[{"type":"text","text":"What is this?"},{"type":"image_url","image_url":{"url":"https://example.com/a.png"}}]}]}`,
+		},
+		{
+			name: "tools",
+			body: `{"model":"gpt-4o","messages":[{"role":"user","content":"Weather?"}],"tools":[{"type":"function","function":{"name":"get_weather"}}]}`,
+		},
+		{
+			name: "tool message",
+			body: `{"model":"gpt-4o","messages":[{"role":"user","content":"Weather?"},{"role":"tool","tool_call_id":"call_1","content":"sunny"}]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, req, _, _, err := spec.ParseBody([]byte(tc.body), false)
+			require.NoError(t, err)
+			require.Equal(t, tc.exp, spec.CacheText(req))
+		})
+	}
+}
+
+func TestChatCompletionsEndpointSpec_StreamFromCache(t *testing.T) {
+	spec := ChatCompletionsEndpointSpec{}
+	body := []byte(`{"id":"chatcmpl-1","object":"chat.completion","model":"gpt-4o","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"Hi"}}],
+"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`)
+
+	out, err := spec.StreamFromCache(&openai.ChatCompletionRequest{}, body)
+	require.NoError(t, err)
+	require.NotContains(t, string(out), `"usage"`)
+
+	out, err = spec.StreamFromCache(&openai.ChatCompletionRequest{StreamOptions: &openai.StreamOptions{IncludeUsage: true}}, body)
+	require.NoError(t, err)
+	require.Contains(t, string(out), `"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}`)
+}
+
+func TestMessagesEndpointSpec_CacheText(t *testing.T) {
+	spec := MessagesEndpointSpec{}
+	for _, tc := range []struct {
+		name string
+		body string
+		exp  string
+	}{
+		{
+			name: "text",
+			body: `{"model":"claude-sonnet-4-5","max_tokens":10,"system":"Be brief.","messages":[{"role":"user","content":"Hi"},
+{"role":"assistant","content":[{"type":"text","text":"Hello!"}]},{"role":"user","content":"What is AI?"}]}`,
+			exp: "system: Be brief.\nuser: Hi\nassistant: Hello!\nuser: What is AI?\n",
+		},
+		{
+			name: "image",
+			body: `{"model":"claude-sonnet-4-5","max_tokens":10,"messages":[{"role":"user","content":[{"type":"image","source":{"type":"url","url":"https://example.com/a.png"}}]}]}`,
+		},
+		{
+			name: "tools",
+			body: `{"model":"claude-sonnet-4-5","max_tokens":10,"messages":[{"role":"user","content":"Weather?"}],"tools":[{"name":"get_weather","input_schema":{"type":"object"}}]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, req, _, _, err := spec.ParseBody([]byte(tc.body), false)
+			require.NoError(t, err)
+			require.Equal(t, tc.exp, spec.CacheText(req))
+		})
+	}
+}
+
+func TestEmbeddingsEndpointSpec_CacheText(t *testing.T) {
+	spec := EmbeddingsEndpointSpec{}
+	require.Empty(t, spec.CacheText(&openai.EmbeddingRequest{Model: "text-embedding-3-small"}))
+	_, err := spec.StreamFromCache(&openai.EmbeddingRequest{}, nil)
+	require.Error(t, err)
+}
+
+func TestChatCompletionsEndpointSpec_RedactRequest(t *testing.T) {
+	body := []byte(`{"model":"gpt-4o","messages":[{"role":"system","content":"Be brief."},` +
+		`{"role":"user","content":[{"type":"text","text":"Mail a@b.io"},{"type":"image_url","image_url":{"url":"https://a@b.io"}}]},` +
+		`{"role":"assistant","content":"Sent to a@b.io."}]}`)
+	redact := func(s string) string { return strings.ReplaceAll(s, "a@b.io", "[EMAIL_1]") }
+
+	redacted, err := ChatCompletionsEndpointSpec{}.RedactRequest(body, redact)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"model":"gpt-4o","messages":[{"role":"system","content":"Be brief."},`+
+		`{"role":"user","content":[{"type":"text","text":"Mail [EMAIL_1]"},{"type":"image_url","image_url":{"url":"https://a@b.io"}}]},`+
+		`{"role":"assistant","content":"Sent to [EMAIL_1]."}]}`, string(redacted))
+
+	redacted, err = ChatCompletionsEndpointSpec{}.RedactRequest([]byte(`{"messages":[{"role":"user","content":"Hi"}]}`), redact)
+	require.NoError(t, err)
+	require.Nil(t, redacted)
+}
+
+func TestChatCompletionsEndpointSpec_StreamText(t *testing.T) {
+	for _, tc := range []struct {
+		data    string
+		expKey  string
+		expPath string
+		expEnd  bool
+	}{
+		{data: `{"choices":[{"index":0,"delta":{"content":"Hi"}}]}`, expKey: "choice:0", expPath: "choices.0.delta.content"},
+		{data: `{"choices":[{"index":1,"delta":{"role":"assistant"}}]}`, expKey: "choice:1"},
+		{data: `{"choices":[{"index":0,"delta":{"content":"."},"finish_reason":"stop"}]}`, expKey: "choice:0", expPath: "choices.0.delta.content", expEnd: true},
+		{data: `{"choices":[],"usage":{"total_tokens":3}}`},
+	} {
+		key, path, end := ChatCompletionsEndpointSpec{}.StreamText([]byte(tc.data))
+		require.Equal(t, tc.expKey, key, tc.data)
+		require.Equal(t, tc.expPath, path, tc.data)
+		require.Equal(t, tc.expEnd, end, tc.data)
+	}
+}
+
+func TestMessagesEndpointSpec_RedactRequest(t *testing.T) {
+	body := []byte(`{"model":"claude-sonnet-4-5","system":[{"type":"text","text":"User is a@b.io."}],"messages":[` +
+		`{"role":"user","content":"Mail a@b.io"},` +
+		`{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"lookup","input":{"q":"a@b.io"}}]},` +
+		`{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"Found a@b.io"},{"type":"text","text":"Thanks a@b.io"}]}]}`)
+	redact := func(s string) string { return strings.ReplaceAll(s, "a@b.io", "[EMAIL_1]") }
+
+	redacted, err := MessagesEndpointSpec{}.RedactRequest(body, redact)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"model":"claude-sonnet-4-5","system":[{"type":"text","text":"User is [EMAIL_1]."}],"messages":[`+
+		`{"role":"user","content":"Mail [EMAIL_1]"},`+
+		`{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"lookup","input":{"q":"a@b.io"}}]},`+
+		`{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"Found [EMAIL_1]"},{"type":"text","text":"Thanks [EMAIL_1]"}]}]}`,
+		string(redacted))
+}
+
+func TestMessagesEndpointSpec_StreamText(t *testing.T) {
+	for _, tc := range []struct {
+		data    string
+		expKey  string
+		expPath string
+		expEnd  bool
+	}{
+		{data: `{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Hi"}}`, expKey: "block:1", expPath: "delta.text"},
+		{data: `{"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"{"}}`},
+		{data: `{"type":"content_block_stop","index":1}`, expKey: "block:1", expEnd: true},
+		{data: `{"type":"message_stop"}`},
+	} {
+		key, path, end := MessagesEndpointSpec{}.StreamText([]byte(tc.data))
+		require.Equal(t, tc.expKey, key, tc.data)
+		require.Equal(t, tc.expPath, path, tc.data)
+		require.Equal(t, tc.expEnd, end, tc.data)
+	}
+}
+
+func TestResponsesEndpointSpec_RedactRequest(t *testing.T) {
+	redact := func(s string) string { return strings.ReplaceAll(s, "a@b.io", "[EMAIL_1]") }
+
+	redacted, err := ResponsesEndpointSpec{}.RedactRequest([]byte(`{"model":"gpt-4o","instructions":"User is a@b.io.","input":"Mail a@b.io"}`), redact)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"model":"gpt-4o","instructions":"User is [EMAIL_1].","input":"Mail [EMAIL_1]"}`, string(redacted))
+
+	redacted, err = ResponsesEndpointSpec{}.RedactRequest([]byte(`{"model":"gpt-4o","input":[`+
+		`{"role":"user","content":"Mail a@b.io"},`+
+		`{"type":"message","role":"user","content":[{"type":"input_text","text":"Thanks a@b.io"}]},`+
+		`{"type":"function_call_output","call_id":"c1","output":"a@b.io"}]}`), redact)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"model":"gpt-4o","input":[`+
+		`{"role":"user","content":"Mail [EMAIL_1]"},`+
+		`{"type":"message","role":"user","content":[{"type":"input_text","text":"Thanks [EMAIL_1]"}]},`+
+		`{"type":"function_call_output","call_id":"c1","output":"a@b.io"}]}`, string(redacted))
+}
+
+func TestResponsesEndpointSpec_StreamText(t *testing.T) {
+	for _, tc := range []struct {
+		data    string
+		expKey  string
+		expPath string
+		expEnd  bool
+	}{
+		{data: `{"type":"response.output_text.delta","item_id":"msg_1","content_index":0,"delta":"Hi"}`, expKey: "msg_1:0", expPath: "delta"},
+		{data: `{"type":"response.output_text.done","item_id":"msg_1","content_index":0,"text":"Hi"}`, expKey: "msg_1:0", expEnd: true},
+		{data: `{"type":"response.created","response":{}}`},
+	} {
+		key, path, end := ResponsesEndpointSpec{}.StreamText([]byte(tc.data))
+		require.Equal(t, tc.expKey, key, tc.data)
+		require.Equal(t, tc.expPath, path, tc.data)
+		require.Equal(t, tc.expEnd, end, tc.data)
+	}
+}
+
+func TestChatCompletionsEndpointSpec_PromptMessages(t *testing.T) {
+	messages := ChatCompletionsEndpointSpec{}.PromptMessages([]byte(`{"model":"gpt-4o","messages":[{"role":"system","content":"Be brief."},` +
+		`{"role":"user","content":[{"type":"text","text":"What is this?"},{"type":"image_url","image_url":{"url":"https://example.com/a.png"}},{"type":"text","text":"Answer in French."}]},` +
+		`{"role":"assistant","content":null,"tool_calls":[{"id":"c1","type":"function","function":{"name":"f","arguments":"{}"}}]},` +
+		`{"role":"tool","tool_call_id":"c1","content":"A cat."}]}`))
+	require.Equal(t, []guardrail.Message{
+		{Role: "system", Content: "Be brief."},
+		{Role: "user", Content: "What is this?\nAnswer in French."},
+		{Role: "tool", Content: "A cat."},
+	}, messages)
+
+	require.Equal(t, "Un chat.", ChatCompletionsEndpointSpec{}.CompletionText(
+		[]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"Un chat."}}]}`)))
+}
+
+func TestMessagesEndpointSpec_PromptMessages(t *testing.T) {
+	messages := MessagesEndpointSpec{}.PromptMessages([]byte(`{"model":"claude-sonnet-4-5","system":[{"type":"text","text":"Be brief."}],"messages":[` +
+		`{"role":"user","content":"Look it up."},` +
+		`{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"lookup","input":{}}]},` +
+		`{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":[{"type":"text","text":"Found."}]},{"type":"text","text":"Thanks"}]}]}`))
+	require.Equal(t, []guardrail.Message{
+		{Role: "system", Content: "Be brief."},
+		{Role: "user", Content: "Look it up."},
+		{Role: "user", Content: "Thanks"},
+		{Role: "tool", Content: "Found."},
+	}, messages)
+
+	require.Equal(t, "Hello\nBye", MessagesEndpointSpec{}.CompletionText([]byte(`{"content":[`+
+		`{"type":"text","text":"Hello"},{"type":"tool_use","id":"t2","name":"f","input":{}},{"type":"text","text":"Bye"}]}`)))
+}
+
+func TestResponsesEndpointSpec_PromptMessages(t *testing.T) {
+	require.Equal(t, []guardrail.Message{{Role: "system", Content: "Be brief."}, {Role: "user", Content: "Hi"}},
+		ResponsesEndpointSpec{}.PromptMessages([]byte(`{"model":"gpt-4o","instructions":"Be brief.","input":"Hi"}`)))
+
+	messages := ResponsesEndpointSpec{}.PromptMessages([]byte(`{"model":"gpt-4o","input":[` +
+		`{"role":"user","content":"Look it up."},` +
+		`{"type":"function_call","call_id":"c1","name":"lookup","arguments":"{}"},` +
+		`{"type":"function_call_output","call_id":"c1","output":"Found."},` +
+		`{"type":"message","role":"user","content":[{"type":"input_text","text":"Thanks"}]}]}`))
+	require.Equal(t, []guardrail.Message{
+		{Role: "user", Content: "Look it up."},
+		{Role: "tool", Content: "Found."},
+		{Role: "user", Content: "Thanks"},
+	}, messages)
+
+	require.Equal(t, "Hello", ResponsesEndpointSpec{}.CompletionText([]byte(`{"output":[`+
+		`{"type":"reasoning","summary":[]},{"type":"message","role":"assistant","content":[{"type":"output_text","text":"Hello"}]}]}`)))
+}
@@ -0,0 +1,171 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extproc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/endpointspec"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// backendHTTPClient is used to call the backends directly from the external processor.
+var backendHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// backendClient calls a backend directly from the external processor rather than through Envoy, such as to embed
+// the text of the request for the semantic lookup of the response cache, or to classify it with the model of the
+// guardrail. The request is translated to the schema of
+// the backend and authenticated the same as the requests routed to it.
+type backendClient struct {
+	// url is the base URL of the backend, e.g. "https://api.openai.com".
+	url     string
+	backend *filterapi.Backend
+	handler filterapi.BackendAuthHandler
+	client  *http.Client
+}
+
+// embed returns the embedding of the text with the embedding model.
+func (c *backendClient) embed(ctx context.Context, model, text string) ([]float32, error) {
+	tr, err := endpointspec.EmbeddingsEndpointSpec{}.GetTranslator(c.backend.Schema, c.backend.ModelNameOverride)
+	if err != nil {
+		return nil, err
+	}
+	req := &openai.EmbeddingRequest{Model: model, Input: openai.EmbeddingRequestInput{Value: text}}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embedding request: %w", err)
+	}
+	body, err = c.do(ctx, "/v1/embeddings", body, func(original []byte) ([]internalapi.Header, []byte, error) {
+		return tr.RequestBody(original, req, false)
+	}, func(headers map[string]string, respBody io.Reader) ([]byte, error) {
+		_, newBody, _, _, err := tr.ResponseBody(headers, respBody, true, nil)
+		return newBody, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	var resp openai.EmbeddingResponse
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal embedding response: %w", err)
+	}
+	if len(resp.Data) == 0 {
+		return nil, errors.New("no embedding in the response")
+	}
+	floats, ok := resp.Data[0].Embedding.Value.([]float64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected embedding format %T", resp.Data[0].Embedding.Value)
+	}
+	embedding := make([]float32, len(floats))
+	for i, f := range floats {
+		embedding[i] = float32(f)
+	}
+	return embedding, nil
+}
+
+// complete returns the text of the answer of the chat model to the messages, such as the verdict of a safety
+// classifier model.
+func (c *backendClient) complete(ctx context.Context, model string, messages []openai.ChatCompletionMessageParamUnion) (string, error) {
+	tr, err := endpointspec.ChatCompletionsEndpointSpec{}.GetTranslator(c.backend.Schema, c.backend.ModelNameOverride)
+	if err != nil {
+		return "", err
+	}
+	req := &openai.ChatCompletionRequest{Model: model, Messages: messages}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal chat completion request: %w", err)
+	}
+	body, err = c.do(ctx, "/v1/chat/completions", body, func(original []byte) ([]internalapi.Header, []byte, error) {
+		return tr.RequestBody(original, req, false)
+	}, func(headers map[string]string, respBody io.Reader) ([]byte, error) {
+		_, newBody, _, _, err := tr.ResponseBody(headers, respBody, true, nil)
+		return newBody, err
+	})
+	if err != nil {
+		return "", err
+	}
+	var resp openai.ChatCompletionResponse
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal chat completion response: %w", err)
+	}
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == nil {
+		return "", errors.New("no content in the response")
+	}
+	return *resp.Choices[0].Message.Content, nil
+}
+
+// do sends the request body to the backend and returns the response body. requestBody and responseBody translate
+// the bodies from and to the schema of the backend, and return nil bodies if they are not changed.
+func (c *backendClient) do(ctx context.Context, path string, body []byte,
+	requestBody func(original []byte) ([]internalapi.Header, []byte, error),
+	responseBody func(headers map[string]string, body io.Reader) ([]byte, error),
+) ([]byte, error) {
+	headers := map[string]string{":method": http.MethodPost, ":path": path, "content-type": "application/json"}
+	newHeaders, newBody, err := requestBody(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate the request: %w", err)
+	}
+	if newBody != nil {
+		body = newBody
+	}
+	for _, h := range newHeaders {
+		headers[h.Key()] = h.Value()
+	}
+	if c.handler != nil {
+		authHeaders, authErr := c.handler.Do(ctx, headers, body)
+		if authErr != nil {
+			return nil, fmt.Errorf("failed to authenticate the request: %w", authErr)
+		}
+		for _, h := range authHeaders {
+			headers[h.Key()] = h.Value()
+		}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, headers[":method"], strings.TrimSuffix(c.url, "/")+headers[":path"], bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the request: %w", err)
+	}
+	for k, v := range headers {
+		if !strings.HasPrefix(k, ":") && k != "content-length" {
+			httpReq.Header.Set(k, v)
+		}
+	}
+	httpResp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send the request: %w", err)
+	}
+	defer httpResp.Body.Close()
+	raw, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from the backend: %s", httpResp.StatusCode, raw)
+	}
+
+	respHeaders := map[string]string{":status": strconv.Itoa(httpResp.StatusCode)}
+	for k := range httpResp.Header {
+		respHeaders[strings.ToLower(k)] = httpResp.Header.Get(k)
+	}
+	newBody, err = responseBody(respHeaders, bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate the response: %w", err)
+	}
+	if newBody != nil {
+		return newBody, nil
+	}
+	return raw, nil
+}
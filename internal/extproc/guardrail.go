@@ -0,0 +1,201 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extproc
+
+import (
+	"context"
+	"log/slog"
+
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/endpointspec"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/guardrail"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+// checkPromptGuardrail checks the prompt of the request with the guardrail of the model, if any. It returns the
+// ImmediateResponse rejecting the request when the verdict blocks it, or the dynamic metadata annotating the request
+// when the verdict annotates it. When the completions are checked as well, the guardrail and the prompt are kept in
+// the router for the upstream filter.
+func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) checkPromptGuardrail(ctx context.Context, logger *slog.Logger,
+	originalModel internalapi.OriginalModel, requestBody []byte,
+) (*extprocv3.ProcessingResponse, *structpb.Struct) {
+	guardrailSpec, ok := any(r.eh).(endpointspec.GuardrailSpec)
+	if !ok {
+		return nil, nil
+	}
+	g := r.config.Guardrails[originalModel]
+	if g == nil {
+		return nil, nil
+	}
+
+	req := &guardrail.Request{
+		Stage:    guardrail.StagePrompt,
+		Route:    g.RouteName,
+		Model:    originalModel,
+		Messages: guardrailSpec.PromptMessages(requestBody),
+	}
+	verdict := checkGuardrail(ctx, logger, g, req)
+	if verdict.Action == guardrail.ActionBlock {
+		logger.Info("blocking request by guardrail", slog.String("route", g.RouteName), slog.String("reason", verdict.Reason))
+		return createUserFacingErrorResponse(400, "BadRequest", guardrailBlockedMessage("request", verdict)), nil
+	}
+	if g.CheckCompletion {
+		r.guardrail, r.guardrailPrompt = g, req.Messages
+	}
+	if verdict.Action == guardrail.ActionAnnotate {
+		return nil, guardrailMetadata(guardrail.StagePrompt, verdict)
+	}
+	return nil, nil
+}
+
+// checkCompletionGuardrail checks the completion of the response with the guardrail kept by the router. newBody is
+// the body from the translator, or nil if the translator does not change the decoded body.
+//
+// The non-streaming response is checked as a whole, and the ImmediateResponse rejecting it is returned when the
+// verdict blocks it. The events of the streaming response are held back in the window until the completion in them
+// is checked, and the body returned is the events released so far. Since the response headers have already been
+// sent, the streaming response blocked by the verdict ends with an error event instead.
+func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) checkCompletionGuardrail(ctx context.Context,
+	newBody, decodedBody []byte, endOfStream bool,
+) ([]byte, *extprocv3.ProcessingResponse, *structpb.Struct) {
+	g := u.parent.guardrail
+	body := newBody
+	if body == nil {
+		body = decodedBody
+	}
+	if u.guardrailWindow == nil {
+		guardrailSpec, _ := any(u.parent.eh).(endpointspec.GuardrailSpec)
+		if !endOfStream || guardrailSpec == nil {
+			return newBody, nil, nil
+		}
+		verdict := u.checkCompletion(ctx, g, guardrailSpec.CompletionText(body))
+		switch verdict.Action {
+		case guardrail.ActionBlock:
+			u.logger.Info("blocking response by guardrail", slog.String("route", g.RouteName), slog.String("reason", verdict.Reason))
+			return nil, createUserFacingErrorResponse(400, "BadRequest", guardrailBlockedMessage("response", verdict)), nil
+		case guardrail.ActionAnnotate:
+			return newBody, nil, guardrailMetadata(guardrail.StageCompletion, verdict)
+		}
+		return newBody, nil, nil
+	}
+
+	if u.guardrailBlocked {
+		// The rest of the blocked stream is dropped.
+		return []byte{}, nil, nil
+	}
+	var metadata *structpb.Struct
+	if u.guardrailWindow.Write(body, endOfStream) {
+		verdict := u.checkCompletion(ctx, g, u.guardrailWindow.Completion())
+		switch verdict.Action {
+		case guardrail.ActionBlock:
+			u.logger.Info("blocking streaming response by guardrail", slog.String("route", g.RouteName), slog.String("reason", verdict.Reason))
+			u.guardrailBlocked = true
+			return guardrailErrorEvent(verdict), nil, nil
+		case guardrail.ActionAnnotate:
+			metadata = guardrailMetadata(guardrail.StageCompletion, verdict)
+		}
+	}
+	return u.guardrailWindow.Release(), nil, metadata
+}
+
+// checkCompletion returns the verdict of the guardrail on the completion so far.
+func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) checkCompletion(ctx context.Context, g *filterapi.RuntimeGuardrail, completion string) *guardrail.Verdict {
+	return checkGuardrail(ctx, u.logger, g, &guardrail.Request{
+		Stage:      guardrail.StageCompletion,
+		Route:      g.RouteName,
+		Model:      u.parent.originalModel,
+		Messages:   u.parent.guardrailPrompt,
+		Completion: completion,
+	})
+}
+
+// checkGuardrail returns the verdict of the guard service of the guardrail on the request. The failures of the guard
+// service block the request unless the guardrail fails open.
+func checkGuardrail(ctx context.Context, logger *slog.Logger, g *filterapi.RuntimeGuardrail, req *guardrail.Request) *guardrail.Verdict {
+	if g.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.Timeout)
+		defer cancel()
+	}
+	var verdict *guardrail.Verdict
+	var err error
+	if g.Webhook != nil {
+		verdict, err = guardrail.CallWebhook(ctx, backendHTTPClient, g.Webhook.URL, req)
+	} else {
+		verdict, err = classifyGuardrail(ctx, g, req)
+	}
+	if err != nil {
+		logger.Warn("failed to check the guardrail", slog.String("route", g.RouteName),
+			slog.String("stage", string(req.Stage)), slog.String("error", err.Error()))
+		if g.FailOpen {
+			return &guardrail.Verdict{Action: guardrail.ActionPass}
+		}
+		return &guardrail.Verdict{Action: guardrail.ActionBlock, Reason: "guardrail unavailable"}
+	}
+	return verdict
+}
+
+// classifyGuardrail returns the verdict of the classifier model of the guardrail. The prompt is given as a single user
+// message, followed by the completion as the assistant message at the completion stage, which is the conversation
+// format the safety classifier models such as Llama Guard expect.
+func classifyGuardrail(ctx context.Context, g *filterapi.RuntimeGuardrail, req *guardrail.Request) (*guardrail.Verdict, error) {
+	messages := []openai.ChatCompletionMessageParamUnion{{OfUser: &openai.ChatCompletionUserMessageParam{
+		Role:    openai.ChatMessageRoleUser,
+		Content: openai.StringOrUserRoleContentUnion{Value: req.Transcript()},
+	}}}
+	if req.Stage == guardrail.StageCompletion {
+		messages = append(messages, openai.ChatCompletionMessageParamUnion{OfAssistant: &openai.ChatCompletionAssistantMessageParam{
+			Role:    openai.ChatMessageRoleAssistant,
+			Content: openai.StringOrAssistantRoleContentUnion{Value: req.Completion},
+		}})
+	}
+	client := &backendClient{url: g.LLM.URL, backend: &g.LLM.Backend, handler: g.LLMHandler, client: backendHTTPClient}
+	output, err := client.complete(ctx, g.LLM.Model, messages)
+	if err != nil {
+		return nil, err
+	}
+	unsafeAction := guardrail.ActionBlock
+	if g.LLM.UnsafeAction == filterapi.GuardrailUnsafeActionAnnotate {
+		unsafeAction = guardrail.ActionAnnotate
+	}
+	return guardrail.ParseClassifierOutput(output, unsafeAction)
+}
+
+// guardrailBlockedMessage returns the message of the error returned to the client when the request or the response,
+// as the subject says, is blocked by the verdict.
+func guardrailBlockedMessage(subject string, verdict *guardrail.Verdict) string {
+	message := subject + " blocked by guardrail"
+	if verdict.Reason != "" {
+		message += ": " + verdict.Reason
+	}
+	return message
+}
+
+// guardrailErrorEvent returns the server-sent event ending the streaming response blocked by the verdict.
+func guardrailErrorEvent(verdict *guardrail.Verdict) []byte {
+	data := formatUserFacingErrorJSON("BadRequest", 400, guardrailBlockedMessage("response", verdict))
+	return append(append([]byte("event: error\ndata: "), data...), "\n\n"...)
+}
+
+// guardrailMetadata returns the dynamic metadata annotating the request with the verdict at the stage. The reason is
+// set to "guardrail_<stage>_reason", and each entry of the metadata of the verdict to "guardrail_<stage>_<key>".
+func guardrailMetadata(stage guardrail.Stage, verdict *guardrail.Verdict) *structpb.Struct {
+	prefix := "guardrail_" + string(stage) + "_"
+	fields := map[string]*structpb.Value{prefix + "flagged": structpb.NewBoolValue(true)}
+	if verdict.Reason != "" {
+		fields[prefix+"reason"] = structpb.NewStringValue(verdict.Reason)
+	}
+	for k, v := range verdict.Metadata {
+		fields[prefix+k] = structpb.NewStringValue(v)
+	}
+	return &structpb.Struct{Fields: map[string]*structpb.Value{
+		internalapi.AIGatewayFilterMetadataNamespace: structpb.NewStructValue(&structpb.Struct{Fields: fields}),
+	}}
+}
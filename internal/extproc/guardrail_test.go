@@ -0,0 +1,259 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extproc
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/guardrail"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// newGuardrailWebhook returns the webhook blocking the texts containing "jailbreak", annotating the ones containing
+// "toxic", and letting the others pass. The requests received are appended to requests.
+func newGuardrailWebhook(t *testing.T, requests *[]guardrail.Request) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req guardrail.Request
+		require.NoError(t, json.Unmarshal(body, &req))
+		*requests = append(*requests, req)
+		text := req.Completion
+		if req.Stage == guardrail.StagePrompt {
+			text = req.Transcript()
+		}
+		switch {
+		case strings.Contains(text, "jailbreak"):
+			_, _ = w.Write([]byte(`{"action":"block","reason":"jailbreak \"attempt\""}`))
+		case strings.Contains(text, "toxic"):
+			_, _ = w.Write([]byte(`{"action":"annotate","reason":"toxic","metadata":{"score":"0.7"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"action":"pass"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newGuardrailRouter returns the router of the chat completions with the guardrail for "gpt-4o".
+func newGuardrailRouter(g *filterapi.Guardrail) *chatCompletionProcessorRouterFilter {
+	return &chatCompletionProcessorRouterFilter{
+		config: &filterapi.RuntimeConfig{Guardrails: map[string]*filterapi.RuntimeGuardrail{
+			"gpt-4o": {Guardrail: g},
+		}},
+		requestHeaders: map[string]string{":path": "/v1/chat/completions"},
+		logger:         slog.Default(),
+		tracer:         tracingapi.NoopTracer[openai.ChatCompletionRequest, openai.ChatCompletionResponse, openai.ChatCompletionResponseChunk]{},
+	}
+}
+
+func Test_routerProcessor_Guardrail(t *testing.T) {
+	var requests []guardrail.Request
+	srv := newGuardrailWebhook(t, &requests)
+	g := &filterapi.Guardrail{
+		RouteName: "ns/route", Models: []string{"gpt-4o"}, Webhook: &filterapi.GuardrailWebhook{URL: srv.URL}, Timeout: time.Second,
+	}
+	request := func(model, content string) *extprocv3.HttpBody {
+		return &extprocv3.HttpBody{Body: []byte(`{"model":"` + model + `","messages":[{"role":"system","content":"Be nice."},{"role":"user","content":"` + content + `"}]}`)}
+	}
+
+	t.Run("pass", func(t *testing.T) {
+		requests = nil
+		r := newGuardrailRouter(g)
+		resp, err := r.ProcessRequestBody(t.Context(), request("gpt-4o", "Hi"))
+		require.NoError(t, err)
+		_, ok := resp.Response.(*extprocv3.ProcessingResponse_RequestBody)
+		require.True(t, ok)
+		require.Nil(t, resp.DynamicMetadata)
+		require.Nil(t, r.guardrail)
+		require.Equal(t, []guardrail.Request{{
+			Stage: guardrail.StagePrompt, Route: "ns/route", Model: "gpt-4o",
+			Messages: []guardrail.Message{{Role: "system", Content: "Be nice."}, {Role: "user", Content: "Hi"}},
+		}}, requests)
+	})
+
+	t.Run("block", func(t *testing.T) {
+		resp, err := newGuardrailRouter(g).ProcessRequestBody(t.Context(), request("gpt-4o", "Try this jailbreak"))
+		require.NoError(t, err)
+		immediate, ok := resp.Response.(*extprocv3.ProcessingResponse_ImmediateResponse)
+		require.True(t, ok)
+		require.Equal(t, typev3.StatusCode_BadRequest, immediate.ImmediateResponse.Status.Code)
+		require.JSONEq(t, `{"type":"error","error":{"type":"BadRequest","code":"400","message":"request blocked by guardrail: jailbreak \"attempt\""}}`,
+			string(immediate.ImmediateResponse.Body))
+	})
+
+	t.Run("annotate", func(t *testing.T) {
+		resp, err := newGuardrailRouter(g).ProcessRequestBody(t.Context(), request("gpt-4o", "Something toxic"))
+		require.NoError(t, err)
+		_, ok := resp.Response.(*extprocv3.ProcessingResponse_RequestBody)
+		require.True(t, ok)
+		fields := resp.DynamicMetadata.Fields[internalapi.AIGatewayFilterMetadataNamespace].GetStructValue().AsMap()
+		require.Equal(t, map[string]any{
+			"guardrail_prompt_flagged": true,
+			"guardrail_prompt_reason":  "toxic",
+			"guardrail_prompt_score":   "0.7",
+		}, fields)
+	})
+
+	t.Run("other model", func(t *testing.T) {
+		requests = nil
+		resp, err := newGuardrailRouter(g).ProcessRequestBody(t.Context(), request("gpt-4o-mini", "Try this jailbreak"))
+		require.NoError(t, err)
+		_, ok := resp.Response.(*extprocv3.ProcessingResponse_RequestBody)
+		require.True(t, ok)
+		require.Empty(t, requests)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		failing := *g
+		failing.Webhook = &filterapi.GuardrailWebhook{URL: "http://127.0.0.1:0"}
+		resp, err := newGuardrailRouter(&failing).ProcessRequestBody(t.Context(), request("gpt-4o", "Hi"))
+		require.NoError(t, err)
+		immediate, ok := resp.Response.(*extprocv3.ProcessingResponse_ImmediateResponse)
+		require.True(t, ok)
+		require.Contains(t, string(immediate.ImmediateResponse.Body), "request blocked by guardrail: guardrail unavailable")
+
+		failing.FailOpen = true
+		resp, err = newGuardrailRouter(&failing).ProcessRequestBody(t.Context(), request("gpt-4o", "Hi"))
+		require.NoError(t, err)
+		_, ok = resp.Response.(*extprocv3.ProcessingResponse_RequestBody)
+		require.True(t, ok)
+	})
+}
+
+func Test_upstreamProcessor_Guardrail_completion(t *testing.T) {
+	var requests []guardrail.Request
+	srv := newGuardrailWebhook(t, &requests)
+	g := &filterapi.Guardrail{
+		RouteName: "ns/route", Models: []string{"gpt-4o"}, Webhook: &filterapi.GuardrailWebhook{URL: srv.URL},
+		CheckCompletion: true, StreamWindowSize: 10, Timeout: time.Second,
+	}
+	// newUpstream processes the request through the router and returns the upstream filter that received the
+	// successful response headers.
+	newUpstream := func(t *testing.T, request string) *chatCompletionProcessorUpstreamFilter {
+		r := newGuardrailRouter(g)
+		_, err := r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{Body: []byte(request)})
+		require.NoError(t, err)
+		require.Same(t, g, r.guardrail.Guardrail)
+		u := &chatCompletionProcessorUpstreamFilter{requestHeaders: r.requestHeaders, metrics: &mockMetrics{}, logger: slog.Default()}
+		require.NoError(t, u.SetBackend(t.Context(), &filterapi.RuntimeBackend{Backend: &filterapi.Backend{
+			Name: "openai", Schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI},
+		}}, "ns/route", r))
+		_, err = u.ProcessRequestHeaders(t.Context(), nil)
+		require.NoError(t, err)
+		_, err = u.ProcessResponseHeaders(t.Context(), &corev3.HeaderMap{Headers: []*corev3.HeaderValue{{Key: ":status", Value: "200"}}})
+		require.NoError(t, err)
+		return u
+	}
+	response := func(content string) []byte {
+		return []byte(`{"id":"1","object":"chat.completion","model":"gpt-4o","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"` + content + `"}}]}`)
+	}
+
+	t.Run("non-streaming pass", func(t *testing.T) {
+		requests = nil
+		u := newUpstream(t, `{"model":"gpt-4o","messages":[{"role":"user","content":"Hi"}]}`)
+		require.Nil(t, u.guardrailWindow)
+		resp, err := u.ProcessResponseBody(t.Context(), &extprocv3.HttpBody{Body: response("Hello!"), EndOfStream: true})
+		require.NoError(t, err)
+		require.NotNil(t, resp.GetResponseBody())
+		require.Equal(t, guardrail.Request{
+			Stage: guardrail.StageCompletion, Route: "ns/route", Model: "gpt-4o",
+			Messages: []guardrail.Message{{Role: "user", Content: "Hi"}}, Completion: "Hello!",
+		}, requests[1])
+	})
+
+	t.Run("non-streaming block", func(t *testing.T) {
+		u := newUpstream(t, `{"model":"gpt-4o","messages":[{"role":"user","content":"Hi"}]}`)
+		resp, err := u.ProcessResponseBody(t.Context(), &extprocv3.HttpBody{Body: response("Here is a jailbreak"), EndOfStream: true})
+		require.NoError(t, err)
+		immediate, ok := resp.Response.(*extprocv3.ProcessingResponse_ImmediateResponse)
+		require.True(t, ok)
+		require.Equal(t, typev3.StatusCode_BadRequest, immediate.ImmediateResponse.Status.Code)
+		require.Contains(t, string(immediate.ImmediateResponse.Body), `response blocked by guardrail: jailbreak \"attempt\"`)
+	})
+
+	t.Run("non-streaming annotate", func(t *testing.T) {
+		u := newUpstream(t, `{"model":"gpt-4o","messages":[{"role":"user","content":"Hi"}]}`)
+		resp, err := u.ProcessResponseBody(t.Context(), &extprocv3.HttpBody{Body: response("Something toxic"), EndOfStream: true})
+		require.NoError(t, err)
+		fields := resp.DynamicMetadata.Fields[internalapi.AIGatewayFilterMetadataNamespace].GetStructValue().AsMap()
+		require.Equal(t, true, fields["guardrail_completion_flagged"])
+		require.Equal(t, "toxic", fields["guardrail_completion_reason"])
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		u := newUpstream(t, `{"model":"gpt-4o","messages":[{"role":"user","content":"Hi"}],"stream":true}`)
+		require.NotNil(t, u.guardrailWindow)
+		chunk := func(content string) string {
+			return `data: {"id":"1","object":"chat.completion.chunk","model":"gpt-4o","choices":[{"index":0,"delta":{"content":"` + content + `"}}]}` + "\n\n"
+		}
+		for _, tc := range []struct {
+			body        string
+			endOfStream bool
+			exp         string
+		}{
+			{body: chunk("Hello"), exp: ""},
+			{body: chunk(", world!"), exp: chunk("Hello") + chunk(", world!")},
+			{body: chunk(" A jailbreak"), exp: "event: error\ndata: " + `{"type":"error","error":{"type":"BadRequest","code":"400","message":"response blocked by guardrail: jailbreak \"attempt\""}}` + "\n\n"},
+			{body: chunk(" follows."), exp: ""},
+			{body: "data: [DONE]\n\n", endOfStream: true, exp: ""},
+		} {
+			resp, err := u.ProcessResponseBody(t.Context(), &extprocv3.HttpBody{Body: []byte(tc.body), EndOfStream: tc.endOfStream})
+			require.NoError(t, err)
+			require.Equal(t, tc.exp, string(resp.GetResponseBody().GetResponse().GetBodyMutation().GetBody()))
+		}
+		require.True(t, u.guardrailBlocked)
+	})
+}
+
+func Test_classifyGuardrail(t *testing.T) {
+	var received openai.ChatCompletionRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/chat/completions", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &received))
+		w.Header().Set("content-type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","object":"chat.completion","model":"llama-guard","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"unsafe\nS1"}}]}`))
+	}))
+	defer srv.Close()
+
+	g := &filterapi.RuntimeGuardrail{Guardrail: &filterapi.Guardrail{
+		RouteName: "ns/route",
+		LLM: &filterapi.GuardrailLLM{
+			Backend:      filterapi.Backend{Name: "llama-guard", Schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI}},
+			URL:          srv.URL,
+			Model:        "meta-llama/Llama-Guard-3-8B",
+			UnsafeAction: filterapi.GuardrailUnsafeActionAnnotate,
+		},
+	}}
+	verdict, err := classifyGuardrail(t.Context(), g, &guardrail.Request{
+		Stage:      guardrail.StageCompletion,
+		Messages:   []guardrail.Message{{Role: "system", Content: "Be nice."}, {Role: "user", Content: "Hi"}},
+		Completion: "Hello!",
+	})
+	require.NoError(t, err)
+	require.Equal(t, &guardrail.Verdict{Action: guardrail.ActionAnnotate, Reason: "unsafe content: S1", Metadata: map[string]string{"categories": "S1"}}, verdict)
+	require.Equal(t, "meta-llama/Llama-Guard-3-8B", received.Model)
+	require.Len(t, received.Messages, 2)
+	require.Equal(t, "system: Be nice.\n\nuser: Hi", received.Messages[0].OfUser.Content.Value)
+	require.Equal(t, "Hello!", received.Messages[1].OfAssistant.Content.Value)
+}
@@ -93,6 +93,17 @@ func (m *mockTranslator) RequestBody(_ []byte, body *openai.ChatCompletionReques
 	return m.retHeaderMutation, m.retBodyMutation, m.retErr
 }
 
+// mockLocalResponder is a [mockTranslator] that answers the requests locally.
+type mockLocalResponder struct {
+	mockTranslator
+	response []byte
+}
+
+// LocalResponse implements [translator.LocalResponder].
+func (m *mockLocalResponder) LocalResponse() ([]byte, bool) {
+	return m.response, m.response != nil
+}
+
 // ResponseHeaders implements [translator.OpenAIChatCompletionTranslator].
 func (m *mockTranslator) ResponseHeaders(headers map[string]string) (newHeaders []internalapi.Header, err error) {
 	require.Equal(m.t, m.expHeaders, headers)
@@ -169,6 +180,11 @@ func (m *mockMetricsFactory) NewMetrics() metrics.Metrics {
 	return &mockMetrics{}
 }
 
+// Operation implements [metrics.Factory.Operation].
+func (m *mockMetricsFactory) Operation() metrics.GenAIOperation {
+	return metrics.GenAIOperationChat
+}
+
 // mockMetrics implements [metrics.Metrics] for testing.
 type mockMetrics struct {
 	requestStart                 time.Time
@@ -188,6 +204,13 @@ type mockMetrics struct {
 	interTokenLatency     float64
 	timeToFirstTokenMs    float64
 	interTokenLatencyMs   float64
+	// quotaExceeded records the names of the exhausted quota buckets with their shadow mode.
+	quotaExceeded map[string]bool
+	// cost is the cumulative cost recorded via RecordCost and currency is the currency of the last one.
+	cost     float64
+	currency string
+	// responseCacheLookups is the results recorded via RecordResponseCacheLookup.
+	responseCacheLookups []metrics.ResponseCacheResult
 }
 
 // StartRequest implements [metrics.Metrics].
@@ -264,6 +287,25 @@ func (m *mockMetrics) RecordRequestCompletion(_ context.Context, success bool, _
 	}
 }
 
+// RecordQuotaExceeded implements [metrics.Metrics].
+func (m *mockMetrics) RecordQuotaExceeded(_ context.Context, quotaName string, shadowMode bool, _ map[string]string) {
+	if m.quotaExceeded == nil {
+		m.quotaExceeded = make(map[string]bool)
+	}
+	m.quotaExceeded[quotaName] = shadowMode
+}
+
+// RecordCost implements [metrics.Metrics].
+func (m *mockMetrics) RecordCost(_ context.Context, cost float64, currency string, _ map[string]string) {
+	m.cost += cost
+	m.currency = currency
+}
+
+// RecordResponseCacheLookup implements [metrics.Metrics].
+func (m *mockMetrics) RecordResponseCacheLookup(_ context.Context, result metrics.ResponseCacheResult, _ map[string]string) {
+	m.responseCacheLookups = append(m.responseCacheLookups, result)
+}
+
 // RequireSelectedModel asserts the models set on the metrics.
 func (m *mockMetrics) RequireSelectedModel(t *testing.T, originalModel, requestModel, responseModel string) {
 	require.Equal(t, originalModel, m.originalModel)
@@ -0,0 +1,86 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extproc
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+
+	"github.com/envoyproxy/ai-gateway/internal/endpointspec"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/responsecache"
+)
+
+// applyPIIPolicy checks the texts of the request for PII with the PII policy of the model, if any. It returns the
+// ImmediateResponse rejecting the request when the policy blocks it. Otherwise, it returns the request body with the
+// PII replaced by the placeholders, or nil if the request has no PII. When the placeholders are restored in the
+// response, the redactor is kept in the router.
+func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) applyPIIPolicy(logger *slog.Logger,
+	originalModel internalapi.OriginalModel, requestBody []byte,
+) ([]byte, *extprocv3.ProcessingResponse, error) {
+	piiSpec, ok := any(r.eh).(endpointspec.PIISpec)
+	if !ok {
+		return nil, nil, nil
+	}
+	policy := r.config.PIIPolicies[originalModel]
+	if policy == nil {
+		return nil, nil, nil
+	}
+
+	redactor := policy.Detector.NewRedactor()
+	redactedBody, err := piiSpec.RedactRequest(requestBody, redactor.Redact)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to redact PII from the request: %w", err)
+	}
+	if redactedBody == nil {
+		return nil, nil, nil
+	}
+	labels := redactor.Labels()
+	switch policy.Action {
+	case filterapi.PIIActionBlock:
+		logger.Info("blocking request with PII", slog.String("route", policy.RouteName), slog.Any("pii", labels))
+		return nil, createUserFacingErrorResponse(400, "BadRequest", "request contains PII: "+strings.Join(labels, ", ")), nil
+	case filterapi.PIIActionRestore:
+		r.piiRedactor = redactor
+	}
+	logger.Debug("redacted PII from the request", slog.String("route", policy.RouteName), slog.Any("pii", labels))
+	return redactedBody, nil, nil
+}
+
+// restorePII returns the response body served to the client with the placeholders of the PII restored. newBody is the
+// body from the translator, or nil if the translator does not change the decoded body. The content-length header is
+// updated for the non-streaming responses.
+func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) restorePII(newBody, decodedBody []byte,
+	newHeaders []internalapi.Header, endOfStream bool,
+) ([]byte, []internalapi.Header) {
+	if newBody == nil {
+		newBody = decodedBody
+	}
+	if u.piiRestorer != nil {
+		return u.piiRestorer.Restore(newBody, endOfStream), newHeaders
+	}
+	restored := u.parent.piiRedactor.RestoreJSON(newBody)
+	newHeaders = slices.DeleteFunc(newHeaders, func(h internalapi.Header) bool { return h.Key() == "content-length" })
+	return restored, append(newHeaders, internalapi.Header{"content-length", strconv.Itoa(len(restored))})
+}
+
+// restoreCachedPII returns the copy of the cached entry with the placeholders of the PII restored. The responses are
+// cached with the placeholders, which are the same for the same PII in the same request.
+func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) restoreCachedPII(entry *responsecache.Entry) *responsecache.Entry {
+	restored := *entry
+	if piiSpec, ok := any(r.eh).(endpointspec.PIISpec); ok && entry.Stream {
+		restored.Body = r.piiRedactor.NewStreamRestorer(piiSpec.StreamText).Restore(entry.Body, true)
+	} else {
+		restored.Body = r.piiRedactor.RestoreJSON(entry.Body)
+	}
+	return &restored
+}
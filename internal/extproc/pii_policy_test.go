@@ -0,0 +1,156 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extproc
+
+import (
+	"log/slog"
+	"strconv"
+	"testing"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/pii"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// newPIIPolicyRouter returns the router of the chat completions with the PII policy of the action for "gpt-4o".
+func newPIIPolicyRouter(t *testing.T, action filterapi.PIIAction) *chatCompletionProcessorRouterFilter {
+	detector, err := pii.NewDetector([]pii.Entity{pii.EntityEmail, pii.EntityPhoneNumber}, nil)
+	require.NoError(t, err)
+	return &chatCompletionProcessorRouterFilter{
+		config: &filterapi.RuntimeConfig{PIIPolicies: map[string]*filterapi.RuntimePIIPolicy{
+			"gpt-4o": {PIIPolicy: &filterapi.PIIPolicy{RouteName: "ns/route", Models: []string{"gpt-4o"}, Action: action}, Detector: detector},
+		}},
+		requestHeaders: map[string]string{":path": "/v1/chat/completions"},
+		logger:         slog.Default(),
+		tracer:         tracingapi.NoopTracer[openai.ChatCompletionRequest, openai.ChatCompletionResponse, openai.ChatCompletionResponseChunk]{},
+	}
+}
+
+func Test_routerProcessor_PIIPolicy(t *testing.T) {
+	const request = `{"model":"gpt-4o","messages":[{"role":"user","content":"Mail a@b.io or call +1 415 555 2671."}]}`
+
+	t.Run("block", func(t *testing.T) {
+		r := newPIIPolicyRouter(t, filterapi.PIIActionBlock)
+		resp, err := r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{Body: []byte(request)})
+		require.NoError(t, err)
+		immediate, ok := resp.Response.(*extprocv3.ProcessingResponse_ImmediateResponse)
+		require.True(t, ok)
+		require.Equal(t, typev3.StatusCode_BadRequest, immediate.ImmediateResponse.Status.Code)
+		require.Contains(t, string(immediate.ImmediateResponse.Body), "request contains PII: EMAIL, PHONE_NUMBER")
+	})
+
+	t.Run("mask", func(t *testing.T) {
+		r := newPIIPolicyRouter(t, filterapi.PIIActionMask)
+		resp, err := r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{Body: []byte(request)})
+		require.NoError(t, err)
+		_, ok := resp.Response.(*extprocv3.ProcessingResponse_RequestBody)
+		require.True(t, ok)
+		require.JSONEq(t, `{"model":"gpt-4o","messages":[{"role":"user","content":"Mail [EMAIL_1] or call [PHONE_NUMBER_1]."}]}`,
+			string(r.originalRequestBodyRaw))
+		require.Equal(t, "Mail [EMAIL_1] or call [PHONE_NUMBER_1].", r.originalRequestBody.Messages[0].OfUser.Content.Value)
+		require.True(t, r.forceBodyMutation)
+		require.Nil(t, r.piiRedactor)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		r := newPIIPolicyRouter(t, filterapi.PIIActionRestore)
+		_, err := r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{Body: []byte(request)})
+		require.NoError(t, err)
+		require.Contains(t, string(r.originalRequestBodyRaw), "[EMAIL_1]")
+		require.NotNil(t, r.piiRedactor)
+	})
+
+	t.Run("no pii", func(t *testing.T) {
+		r := newPIIPolicyRouter(t, filterapi.PIIActionBlock)
+		body := []byte(`{"model":"gpt-4o","messages":[{"role":"user","content":"Hi"}]}`)
+		resp, err := r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{Body: body})
+		require.NoError(t, err)
+		_, ok := resp.Response.(*extprocv3.ProcessingResponse_RequestBody)
+		require.True(t, ok)
+		require.Equal(t, body, r.originalRequestBodyRaw)
+		require.False(t, r.forceBodyMutation)
+	})
+
+	t.Run("other model", func(t *testing.T) {
+		r := newPIIPolicyRouter(t, filterapi.PIIActionBlock)
+		body := []byte(`{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Mail a@b.io"}]}`)
+		resp, err := r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{Body: body})
+		require.NoError(t, err)
+		_, ok := resp.Response.(*extprocv3.ProcessingResponse_RequestBody)
+		require.True(t, ok)
+		require.Equal(t, body, r.originalRequestBodyRaw)
+	})
+}
+
+func Test_upstreamProcessor_PIIPolicy_restore(t *testing.T) {
+	// newUpstream processes the request with the PII through the router and returns the upstream filter that
+	// received the successful response headers.
+	newUpstream := func(t *testing.T, request string) *chatCompletionProcessorUpstreamFilter {
+		r := newPIIPolicyRouter(t, filterapi.PIIActionRestore)
+		_, err := r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{Body: []byte(request)})
+		require.NoError(t, err)
+		u := &chatCompletionProcessorUpstreamFilter{requestHeaders: r.requestHeaders, metrics: &mockMetrics{}, logger: slog.Default()}
+		require.NoError(t, u.SetBackend(t.Context(), &filterapi.RuntimeBackend{Backend: &filterapi.Backend{
+			Name: "openai", Schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI},
+		}}, "ns/route", r))
+		resp, err := u.ProcessRequestHeaders(t.Context(), nil)
+		require.NoError(t, err)
+		upstreamBody := resp.GetRequestHeaders().GetResponse().GetBodyMutation().GetBody()
+		require.Contains(t, string(upstreamBody), "[EMAIL_1]")
+		require.NotContains(t, string(upstreamBody), "a@b.io")
+		_, err = u.ProcessResponseHeaders(t.Context(), &corev3.HeaderMap{Headers: []*corev3.HeaderValue{{Key: ":status", Value: "200"}}})
+		require.NoError(t, err)
+		return u
+	}
+
+	t.Run("non-streaming", func(t *testing.T) {
+		u := newUpstream(t, `{"model":"gpt-4o","messages":[{"role":"user","content":"Mail a@b.io"}]}`)
+		require.Nil(t, u.piiRestorer)
+		resp, err := u.ProcessResponseBody(t.Context(), &extprocv3.HttpBody{
+			Body:        []byte(`{"id":"1","object":"chat.completion","model":"gpt-4o","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"Sent to [EMAIL_1]."}}]}`),
+			EndOfStream: true,
+		})
+		require.NoError(t, err)
+		common := resp.GetResponseBody().GetResponse()
+		restored := common.GetBodyMutation().GetBody()
+		require.Contains(t, string(restored), `"content":"Sent to a@b.io."`)
+		var contentLength string
+		for _, h := range common.GetHeaderMutation().GetSetHeaders() {
+			if h.Header.Key == "content-length" {
+				contentLength = string(h.Header.RawValue)
+			}
+		}
+		require.Equal(t, strconv.Itoa(len(restored)), contentLength)
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		u := newUpstream(t, `{"model":"gpt-4o","messages":[{"role":"user","content":"Mail a@b.io"}],"stream":true}`)
+		require.NotNil(t, u.piiRestorer)
+		chunk := func(content string) string {
+			return `data: {"id":"1","object":"chat.completion.chunk","model":"gpt-4o","choices":[{"index":0,"delta":{"content":"` + content + `"}}]}` + "\n\n"
+		}
+		for _, tc := range []struct {
+			body        string
+			endOfStream bool
+			exp         string
+		}{
+			{body: chunk("Sent to [EMA"), exp: chunk("Sent to ")},
+			{body: chunk("IL_1].")[:30], exp: ""},
+			{body: chunk("IL_1].")[30:], exp: chunk("a@b.io.")},
+			{body: "data: [DONE]\n\n", endOfStream: true, exp: "data: [DONE]\n\n"},
+		} {
+			resp, err := u.ProcessResponseBody(t.Context(), &extprocv3.HttpBody{Body: []byte(tc.body), EndOfStream: tc.endOfStream})
+			require.NoError(t, err)
+			require.Equal(t, tc.exp, string(resp.GetResponseBody().GetResponse().GetBodyMutation().GetBody()))
+		}
+	})
+}
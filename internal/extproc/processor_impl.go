@@ -14,6 +14,7 @@ import (
 	"io"
 	"log/slog"
 	"strconv"
+	"time"
 
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	extprocv3http "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_proc/v3"
@@ -26,11 +27,16 @@ import (
 	"github.com/envoyproxy/ai-gateway/internal/bodymutator"
 	"github.com/envoyproxy/ai-gateway/internal/endpointspec"
 	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/guardrail"
 	"github.com/envoyproxy/ai-gateway/internal/headermutator"
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 	"github.com/envoyproxy/ai-gateway/internal/json"
 	"github.com/envoyproxy/ai-gateway/internal/llmcostcel"
 	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/multipartform"
+	"github.com/envoyproxy/ai-gateway/internal/pii"
+	"github.com/envoyproxy/ai-gateway/internal/quota"
+	"github.com/envoyproxy/ai-gateway/internal/responsestore"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
 	"github.com/envoyproxy/ai-gateway/internal/translator"
 )
@@ -39,6 +45,14 @@ import (
 // This is configured at the startup of the extproc server.
 var LogRequestHeaderAttributes map[string]string
 
+const (
+	// usageClaimKeyPrefix is the prefix of the keys claimed in the quota store for the deduplicated token usage.
+	usageClaimKeyPrefix = "usage:"
+	// usageClaimTTL is how long the claims of the deduplicated token usage are kept. A batch is only charged again
+	// if it is retrieved after this long since its first charge.
+	usageClaimTTL = 400 * 24 * time.Hour
+)
+
 // NewFactory creates a ProcessorFactory with the given parameters.
 //
 // Type Parameters:
@@ -62,7 +76,7 @@ func NewFactory[ReqT any, RespT any, RespChunkT any, EndpointSpecT endpointspec.
 	return func(config *filterapi.RuntimeConfig, requestHeaders map[string]string, logger *slog.Logger, isUpstreamFilter bool, enableRedaction bool) (Processor, error) {
 		logger = logger.With("isUpstreamFilter", fmt.Sprintf("%v", isUpstreamFilter))
 		if !isUpstreamFilter {
-			return newRouterProcessor[ReqT, RespT, RespChunkT, EndpointSpecT](config, requestHeaders, logger, tracer, enableRedaction), nil
+			return newRouterProcessor[ReqT, RespT, RespChunkT, EndpointSpecT](config, requestHeaders, logger, tracer, enableRedaction, f.Operation(), f.NewMetrics()), nil
 		}
 		return newUpstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT](requestHeaders, f.NewMetrics(), logger), nil
 	}
@@ -92,6 +106,9 @@ type (
 		originalRequestBodyRaw []byte
 		originalModel          internalapi.OriginalModel
 		forceBodyMutation      bool
+		// multipartBoundary is the boundary of the multipart/form-data request body, or empty if the request body
+		// is not a form. This is used to apply the body mutations to the form fields instead of JSON fields.
+		multipartBoundary string
 		// tracer is the tracer used for requests.
 		tracer tracingapi.RequestTracer[ReqT, RespT, RespChunkT]
 		// span is the tracing span for this request, created in ProcessRequestBody.
@@ -102,6 +119,23 @@ type (
 		stream              bool
 		debugLogEnabled     bool
 		enableRedaction     bool
+		// operation is the operation name of the endpoint, available to the cost CEL expressions.
+		operation metrics.GenAIOperation
+		// routedOnHeaders is true when the request has been routed at the request headers phase.
+		// See [endpointspec.HeaderSpec].
+		routedOnHeaders bool
+		// responseCache is the state to save the response in the response cache. Nil if the response is not cached.
+		responseCache *responseCacheState
+		// piiRedactor replaced the PII of the request with the placeholders restored in the response. Nil if the
+		// placeholders are not restored.
+		piiRedactor *pii.Redactor
+		// guardrail checks the completions of the request. Nil if the completions are not checked.
+		guardrail *filterapi.RuntimeGuardrail
+		// guardrailPrompt is the prompt of the request sent along with the completions to the guardrail.
+		guardrailPrompt []guardrail.Message
+		// metrics records the metrics of the requests answered by the router, such as from the response cache.
+		// This may be nil.
+		metrics metrics.Metrics
 	}
 	// upstreamProcessor implements [Processor] for the upstream filter for the standard LLM endpoints.
 	//
@@ -122,6 +156,29 @@ type (
 		backendName        string
 		routeName          string
 		handler            filterapi.BackendAuthHandler
+		// quota is the quota configuration of the backend. Nil if the backend has no quota.
+		quota *filterapi.RuntimeBackendQuota
+		// prices is the price table of the backend. Nil if the backend has no prices.
+		prices llmcostcel.PriceTable
+		// globalRequestCosts and requestCosts are the request costs whose price function sees the prices of the backend.
+		// Only used when the backend has prices.
+		globalRequestCosts []filterapi.RuntimeGlobalRequestCost
+		requestCosts       []filterapi.RuntimeRequestCost
+		// quotaSelection is the set of quota buckets applicable to the request, selected at the request headers phase
+		// and charged at the end of the response. Nil if no quota applies to the request.
+		quotaSelection *quotaSelection
+		// responseRecorder captures the response to be saved in the response store. Nil if the response is not saved.
+		responseRecorder *responsestore.Recorder
+		// responseCacheBody accumulates the response to be saved in the response cache. Nil if the response is not cached.
+		responseCacheBody *bytes.Buffer
+		// piiRestorer restores the placeholders of the PII in the streaming response. Nil if the response is not
+		// streamed or the placeholders are not restored.
+		piiRestorer *pii.StreamRestorer
+		// guardrailWindow holds back the events of the streaming response until the guardrail checks them. Nil if the
+		// response is not streamed or the completions are not checked.
+		guardrailWindow *guardrail.StreamWindow
+		// guardrailBlocked is true once the streaming response has been blocked by the guardrail.
+		guardrailBlocked bool
 		// cost is the cost of the request that is accumulated during the processing of the response.
 		costs metrics.TokenUsage
 		// metrics tracking.
@@ -135,6 +192,8 @@ func newRouterProcessor[ReqT, RespT, RespChunkT any, EndpointSpecT endpointspec.
 	logger *slog.Logger,
 	tracer tracingapi.RequestTracer[ReqT, RespT, RespChunkT],
 	enableRedaction bool,
+	operation metrics.GenAIOperation,
+	m metrics.Metrics,
 ) *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT] {
 	debugLogEnabled := logger.Enabled(context.Background(), slog.LevelDebug)
 	return &routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]{
@@ -145,6 +204,8 @@ func newRouterProcessor[ReqT, RespT, RespChunkT any, EndpointSpecT endpointspec.
 		forceBodyMutation: false,
 		debugLogEnabled:   debugLogEnabled,
 		enableRedaction:   enableRedaction,
+		operation:         operation,
+		metrics:           m,
 	}
 }
 
@@ -183,9 +244,12 @@ func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRespons
 
 // formatUserFacingErrorJSON formats a user-facing error as a JSON response body.
 // Returns JSON in format: {"type":"error","error":{"type":"<errorType>","code":"<statusCode>","message":"<message>"}}
+//
+// The message may embed client or backend provided values such as the model name, so it is escaped.
 func formatUserFacingErrorJSON(errorType string, statusCode int, message string) []byte {
-	return fmt.Appendf(nil, `{"type":"error","error":{"type":"%s","code":"%d","message":"%s"}}`,
-		errorType, statusCode, message)
+	escaped, _ := json.Marshal(message)
+	return fmt.Appendf(nil, `{"type":"error","error":{"type":"%s","code":"%d","message":%s}}`,
+		errorType, statusCode, escaped)
 }
 
 // createUserFacingErrorResponse creates an ImmediateResponse for user-facing errors with JSON body.
@@ -207,10 +271,102 @@ func createUserFacingErrorResponse(statusCode int, errorType string, message str
 	}
 }
 
+// createLocalResponse creates an ImmediateResponse for the requests answered by the translator without calling
+// the backend, see [translator.LocalResponder].
+func createLocalResponse(body []byte) *extprocv3.ProcessingResponse {
+	headerMutation := &extprocv3.HeaderMutation{}
+	setHeader(headerMutation, "content-type", "application/json")
+	setHeader(headerMutation, "content-length", strconv.Itoa(len(body)))
+	return &extprocv3.ProcessingResponse{
+		Response: &extprocv3.ProcessingResponse_ImmediateResponse{
+			ImmediateResponse: &extprocv3.ImmediateResponse{
+				Status:  &typev3.HttpStatus{Code: typev3.StatusCode_OK},
+				Headers: headerMutation,
+				Body:    body,
+			},
+		},
+	}
+}
+
+// parseBody parses the request body with the endpoint spec. When the endpoint accepts multipart/form-data and the
+// content-type header says so, the form is parsed here once with the boundary of the header. When the endpoint
+// takes the model from the path, the original path is passed along with the body.
+func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) parseBody(body []byte, costConfigured bool) (
+	internalapi.OriginalModel, *ReqT, bool, []byte, error,
+) {
+	if pathSpec, ok := any(r.eh).(endpointspec.PathSpec[ReqT]); ok {
+		return pathSpec.ParsePath(r.requestHeaders[":path"], body, costConfigured)
+	}
+	formSpec, ok := any(r.eh).(endpointspec.FormSpec[ReqT])
+	if !ok {
+		return r.eh.ParseBody(body, costConfigured)
+	}
+	boundary, ok := multipartform.Boundary(r.requestHeaders["content-type"])
+	if !ok {
+		return r.eh.ParseBody(body, costConfigured)
+	}
+	form, err := multipartform.Parse(body, boundary)
+	if err != nil {
+		return "", nil, false, nil, fmt.Errorf("%w: failed to parse multipart form: %w", internalapi.ErrMalformedRequest, err)
+	}
+	r.multipartBoundary = boundary
+	return formSpec.ParseForm(form, costConfigured)
+}
+
+// ProcessRequestHeaders implements [Processor.ProcessRequestHeaders].
+//
+// The requests without a body, such as the retrieval of a resource by ID, are routed here when the endpoint
+// implements [endpointspec.HeaderSpec]. Otherwise, the request is routed at the request body phase.
+func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRequestHeaders(ctx context.Context, headerMap *corev3.HeaderMap) (*extprocv3.ProcessingResponse, error) {
+	headerSpec, ok := any(r.eh).(endpointspec.HeaderSpec[ReqT])
+	if !ok {
+		return r.passThroughProcessor.ProcessRequestHeaders(ctx, headerMap)
+	}
+	originalModel, body, ok, err := headerSpec.ParseHeaders(r.requestHeaders[":method"], r.requestHeaders[":path"])
+	if err != nil {
+		if userFacingErr := internalapi.GetUserFacingError(err); userFacingErr != nil {
+			r.logger.Error("returning user-facing error for malformed request", slog.String("error", err.Error()))
+			return createUserFacingErrorResponse(400, "BadRequest", userFacingErr.Error()), nil
+		}
+		return nil, fmt.Errorf("failed to parse request headers: %w", err)
+	}
+	if !ok {
+		return r.passThroughProcessor.ProcessRequestHeaders(ctx, headerMap)
+	}
+	r.routedOnHeaders = true
+	return &extprocv3.ProcessingResponse{
+		Response: &extprocv3.ProcessingResponse_RequestHeaders{
+			RequestHeaders: &extprocv3.HeadersResponse{
+				Response: &extprocv3.CommonResponse{
+					HeaderMutation:  r.routeRequest(ctx, originalModel, body, nil),
+					ClearRouteCache: true,
+				},
+			},
+		},
+	}, nil
+}
+
 // ProcessRequestBody implements [Processor.ProcessRequestBody].
 func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRequestBody(ctx context.Context, rawBody *extprocv3.HttpBody) (*extprocv3.ProcessingResponse, error) {
-	costConfigured := len(r.config.RequestCosts) > 0 || len(r.config.GlobalRequestCosts) > 0
-	originalModel, body, stream, mutatedOriginalBody, err := r.eh.ParseBody(rawBody.Body, costConfigured)
+	if r.routedOnHeaders {
+		// The request has already been routed at the request headers phase, and the body is not used.
+		return r.passThroughProcessor.ProcessRequestBody(ctx, rawBody)
+	}
+	// Quota enforcement needs the token usage as well, so it is treated the same as the request costs.
+	costConfigured := len(r.config.RequestCosts) > 0 || len(r.config.GlobalRequestCosts) > 0 || r.config.HasQuota
+	requestBody := rawBody.Body
+	var inputExpanded bool
+	if store := r.responseStore(); store != nil {
+		// Rebuild the full input when the request continues from a response kept by the gateway.
+		expanded, err := responsestore.ExpandInput(ctx, store, requestBody, r.responseOwner())
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand the input of the previous response: %w", err)
+		}
+		if expanded != nil {
+			requestBody, inputExpanded = expanded, true
+		}
+	}
+	originalModel, body, stream, mutatedOriginalBody, err := r.parseBody(requestBody, costConfigured)
 	if err != nil {
 		if userFacingErr := internalapi.GetUserFacingError(err); userFacingErr != nil {
 			// return to user as 400 -  e.g., "malformed request: failed to parse JSON for /v1/chat/completions"
@@ -226,6 +382,23 @@ func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRequest
 		logger = r.logger
 	}
 
+	redactedBody, resp, err := r.applyPIIPolicy(logger, originalModel, requestBody)
+	if err != nil || resp != nil {
+		return resp, err
+	}
+	if redactedBody != nil {
+		// The request is parsed again so that the PII is not sent anywhere from here, including the tracing spans.
+		requestBody = redactedBody
+		if originalModel, body, stream, mutatedOriginalBody, err = r.parseBody(requestBody, costConfigured); err != nil {
+			return nil, fmt.Errorf("failed to parse redacted request body: %w", err)
+		}
+	}
+
+	blocked, promptMetadata := r.checkPromptGuardrail(ctx, logger, originalModel, requestBody)
+	if blocked != nil {
+		return blocked, nil
+	}
+
 	// Only log parsed request body when redaction is enabled
 	if r.debugLogEnabled && r.enableRedaction {
 		if redactedBody, err := r.eh.RedactSensitiveInfoFromRequest(body); err != nil {
@@ -239,13 +412,36 @@ func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRequest
 		}
 	}
 
+	if resp := r.lookupResponseCache(ctx, logger, originalModel, body, requestBody, stream); resp != nil {
+		resp.DynamicMetadata = promptMetadata
+		return resp, nil
+	}
+
 	if mutatedOriginalBody != nil {
 		r.originalRequestBodyRaw = mutatedOriginalBody
 		r.forceBodyMutation = true
 	} else {
-		r.originalRequestBodyRaw = rawBody.Body
+		r.originalRequestBodyRaw = requestBody
+		r.forceBodyMutation = inputExpanded || redactedBody != nil
 	}
+	r.stream = stream
+
+	return &extprocv3.ProcessingResponse{
+		Response: &extprocv3.ProcessingResponse_RequestBody{
+			RequestBody: &extprocv3.BodyResponse{
+				Response: &extprocv3.CommonResponse{
+					HeaderMutation:  r.routeRequest(ctx, originalModel, body, requestBody),
+					ClearRouteCache: true,
+				},
+			},
+		},
+		DynamicMetadata: promptMetadata,
+	}, nil
+}
 
+// routeRequest records the parsed request and returns the header mutation that sets the original model of
+// the request to the request header `x-ai-eg-model`, which is used to route the request to the backend.
+func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) routeRequest(ctx context.Context, originalModel internalapi.OriginalModel, body *ReqT, requestBody []byte) *extprocv3.HeaderMutation {
 	r.requestHeaders[internalapi.ModelNameHeaderKeyDefault] = originalModel
 
 	var additionalHeaders []*corev3.HeaderValueOption
@@ -268,7 +464,6 @@ func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRequest
 	}
 	r.originalModel = originalModel
 	r.originalRequestBody = body
-	r.stream = stream
 
 	// Tracing may need to inject headers, so create a header mutation here.
 	headerMutation := &extprocv3.HeaderMutation{
@@ -279,19 +474,46 @@ func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRequest
 		r.requestHeaders,
 		&headerMutationCarrier{m: headerMutation},
 		body,
-		rawBody.Body,
+		requestBody,
 	)
+	return headerMutation
+}
 
-	return &extprocv3.ProcessingResponse{
-		Response: &extprocv3.ProcessingResponse_RequestBody{
-			RequestBody: &extprocv3.BodyResponse{
-				Response: &extprocv3.CommonResponse{
-					HeaderMutation:  headerMutation,
-					ClearRouteCache: true,
-				},
-			},
-		},
-	}, nil
+// responseStore returns the response store if the gateway keeps the responses of the endpoint, or nil otherwise.
+func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) responseStore() responsestore.Store {
+	if _, ok := any(r.eh).(endpointspec.ResponsesEndpointSpec); !ok {
+		return nil
+	}
+	return r.config.ResponseStore
+}
+
+// responseOwner returns the owner of the responses created by the request as identified by the configured header.
+func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) responseOwner() string {
+	return responsestore.Owner(r.requestHeaders[r.config.ResponseStoreOwnerHeader])
+}
+
+// quotaStore returns the store of the quota counters, or nil if there is none.
+func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) quotaStore() quota.Store {
+	if u.parent.config == nil {
+		return nil
+	}
+	return u.parent.config.QuotaStore
+}
+
+// claimUsage returns true if the token usage identified by key has not been recorded yet by any of the external
+// processors sharing the quota store. The usage is not recorded when the store fails, since recording it twice
+// is worse than missing it.
+func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) claimUsage(ctx context.Context, key string) bool {
+	store := u.quotaStore()
+	if store == nil {
+		return true
+	}
+	claimed, err := store.Claim(ctx, usageClaimKeyPrefix+key, usageClaimTTL)
+	if err != nil {
+		u.logger.Warn("failed to claim the token usage, not recording it", slog.String("key", key), slog.String("error", err.Error()))
+		return false
+	}
+	return claimed
 }
 
 func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) onRetry() bool {
@@ -319,6 +541,21 @@ func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessReque
 	reqModel := cmp.Or(u.requestHeaders[internalapi.ModelNameHeaderKeyDefault], u.parent.originalModel)
 	u.metrics.SetRequestModel(reqModel)
 
+	if q, store := u.quota, u.quotaStore(); q != nil && store != nil {
+		u.quotaSelection = selectQuotaBuckets(q, reqModel, u.requestHeaders)
+		if u.quotaSelection != nil {
+			exceeded, quotaErr := u.quotaSelection.exceeded(ctx, store, u.metrics, u.requestHeaders)
+			if quotaErr != nil {
+				// Fail open so that the quota store being unavailable does not take the backend down.
+				u.logger.Warn("failed to check quota, allowing the request", slog.String("error", quotaErr.Error()))
+			} else if exceeded {
+				u.logger.Info("rejecting request due to exceeded quota", slog.String("backend", u.backendName), slog.String("model", reqModel))
+				u.metrics.RecordRequestCompletion(ctx, false, u.requestHeaders)
+				return createUserFacingErrorResponse(429, "TooManyRequests", "quota exceeded for model "+reqModel), nil
+			}
+		}
+	}
+
 	// We force the body mutation in the following cases:
 	// * The request is a retry request because the body mutation might have happened the previous iteration.
 	// * The request is a streaming request, and the IncludeUsage option is set to false since we need to ensure that
@@ -335,6 +572,12 @@ func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessReque
 		}
 		return nil, fmt.Errorf("failed to transform request: %w", err)
 	}
+	if responder, ok := any(u.translator).(translator.LocalResponder); ok {
+		if body, ok := responder.LocalResponse(); ok {
+			u.metrics.RecordRequestCompletion(ctx, true, u.requestHeaders)
+			return createLocalResponse(body), nil
+		}
+	}
 
 	headerMutation, bodyMutation := mutationsFromTranslationResult(newHeaders, newBody)
 
@@ -490,15 +733,69 @@ func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRespo
 		}, nil
 	}
 
+	var decodedBody []byte
+	if u.responseCacheBody != nil || u.parent.piiRedactor != nil || u.parent.guardrail != nil {
+		// The decoded body is kept since the response is cached, checked and restored as served when the translator
+		// does not change it.
+		if decodedBody, err = io.ReadAll(decodingResult.reader); err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		decodingResult.reader = bytes.NewReader(decodedBody)
+	}
+
 	newHeaders, newBody, tokenUsage, responseModel, err := u.translator.ResponseBody(u.responseHeaders, decodingResult.reader, body.EndOfStream, u.parent.span)
 	if err != nil {
 		return nil, fmt.Errorf("failed to transform response: %w", err)
 	}
-	headerMutation, bodyMutation := mutationsFromTranslationResult(newHeaders, newBody)
+	servedBody := newBody
+	var completionMetadata *structpb.Struct
+	if u.parent.guardrail != nil {
+		var blocked *extprocv3.ProcessingResponse
+		servedBody, blocked, completionMetadata = u.checkCompletionGuardrail(ctx, newBody, decodedBody, body.EndOfStream)
+		if blocked != nil {
+			if u.parent.span != nil {
+				u.parent.span.EndSpanOnError(400, blocked.GetImmediateResponse().GetBody())
+			}
+			recordRequestCompletionErr = true
+			return blocked, nil
+		}
+	}
+	if u.parent.piiRedactor != nil {
+		servedBody, newHeaders = u.restorePII(servedBody, decodedBody, newHeaders, body.EndOfStream)
+	}
+	headerMutation, bodyMutation := mutationsFromTranslationResult(newHeaders, servedBody)
 
 	// Remove content-encoding header if original body encoded but was mutated in the processor.
 	headerMutation = removeContentEncodingIfNeeded(headerMutation, bodyMutation, decodingResult.isEncoded)
 
+	if u.responseRecorder != nil && !u.guardrailBlocked {
+		// The responses are only recorded for the translated backends, so the body is always the one from the translator.
+		u.responseRecorder.Write(newBody)
+		if body.EndOfStream {
+			if stored := u.responseRecorder.Response(); stored != nil {
+				// The response has already been served at this point, so failing to save only affects the subsequent requests.
+				if storeErr := u.parent.config.ResponseStore.Put(ctx, stored); storeErr != nil {
+					u.logger.Warn("failed to save the response", slog.String("id", stored.ID), slog.String("error", storeErr.Error()))
+				}
+			}
+		}
+	}
+
+	if u.responseCacheBody != nil && !u.guardrailBlocked {
+		if newBody != nil {
+			u.responseCacheBody.Write(newBody)
+		} else {
+			u.responseCacheBody.Write(decodedBody)
+		}
+		if body.EndOfStream {
+			// The response has already been served at this point, so failing to save only affects the subsequent requests.
+			if cacheErr := u.parent.responseCache.saveResponse(ctx, u.parent.config.ResponseCacheStore,
+				u.responseCacheBody.Bytes(), u.parent.stream); cacheErr != nil {
+				u.logger.Warn("failed to save the response in the response cache", slog.String("error", cacheErr.Error()))
+			}
+		}
+	}
+
 	resp := &extprocv3.ProcessingResponse{
 		Response: &extprocv3.ProcessingResponse_ResponseBody{
 			ResponseBody: &extprocv3.BodyResponse{
@@ -510,6 +807,13 @@ func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRespo
 		},
 	}
 
+	// The usage reported by several responses, e.g. every retrieval of a completed batch, is only recorded once.
+	if deduplicator, ok := any(u.translator).(translator.UsageDeduplicator); ok {
+		if key := deduplicator.UsageKey(); key != "" && !u.claimUsage(ctx, key) {
+			tokenUsage = metrics.TokenUsage{}
+		}
+	}
+
 	// Translator reports the latest cumulative token usage which we use to override existing costs.
 	u.costs.Override(tokenUsage)
 
@@ -530,17 +834,42 @@ func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) ProcessRespo
 		u.metrics.RecordTokenUsage(ctx, u.costs, u.requestHeaders)
 	}
 
-	if body.EndOfStream && (len(u.parent.config.GlobalRequestCosts) > 0 || len(u.parent.config.RequestCosts) > 0) {
-		metadata, err := buildDynamicMetadata(u.parent.config.GlobalRequestCosts, u.parent.config.RequestCosts, &u.costs, u.requestHeaders, u.backendName, u.routeName, responseModel)
-		if err != nil {
-			return nil, fmt.Errorf("failed to build dynamic metadata: %w", err)
+	if body.EndOfStream {
+		vars := u.costVariables(responseModel)
+		// The prices of the backend take precedence over the ones of the gateway.
+		cost, priced := u.prices.Cost(&vars)
+		if !priced {
+			cost, priced = u.parent.config.Prices.Cost(&vars)
 		}
-		if u.parent.stream {
-			// Adding token latency information to metadata.
-			u.mergeWithTokenLatencyMetadata(metadata)
+		if priced {
+			vars.Cost = cost
+			u.metrics.RecordCost(ctx, cost, u.parent.config.Currency, u.requestHeaders)
+		}
+
+		if u.quotaSelection != nil {
+			// The response has already been served at this point, so failing to charge only affects the subsequent requests.
+			if quotaErr := u.quotaSelection.charge(ctx, u.quotaStore(), &vars); quotaErr != nil {
+				u.logger.Warn("failed to charge quota", slog.String("error", quotaErr.Error()))
+			}
+		}
+
+		globalRequestCosts, requestCosts := u.parent.config.GlobalRequestCosts, u.parent.config.RequestCosts
+		if u.prices != nil {
+			globalRequestCosts, requestCosts = u.globalRequestCosts, u.requestCosts
+		}
+		if priced || len(globalRequestCosts) > 0 || len(requestCosts) > 0 {
+			metadata, err := buildDynamicMetadata(globalRequestCosts, requestCosts, &u.costs, &vars, priced)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build dynamic metadata: %w", err)
+			}
+			if u.parent.stream {
+				// Adding token latency information to metadata.
+				u.mergeWithTokenLatencyMetadata(metadata)
+			}
+			resp.DynamicMetadata = metadata
 		}
-		resp.DynamicMetadata = metadata
 	}
+	resp.DynamicMetadata = mergeDynamicMetadata(resp.DynamicMetadata, completionMetadata)
 
 	if body.EndOfStream && u.parent.span != nil {
 		u.parent.span.EndSpan()
@@ -588,19 +917,42 @@ func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) SetBackend(c
 	u.backendName = backend.Backend.Name
 	u.routeName = routeName
 	u.handler = backend.Handler
+	u.quota = backend.Quota
+	u.prices = backend.Prices
+	u.globalRequestCosts, u.requestCosts = backend.GlobalRequestCosts, backend.RequestCosts
 	u.headerMutator = headermutator.NewHeaderMutator(backend.Backend.HeaderMutation, rp.requestHeaders)
-	u.bodyMutator = bodymutator.NewBodyMutator(backend.Backend.BodyMutation, rp.originalRequestBodyRaw)
+	if rp.multipartBoundary != "" {
+		u.bodyMutator = bodymutator.NewMultipartBodyMutator(backend.Backend.BodyMutation, rp.originalRequestBodyRaw, rp.multipartBoundary)
+	} else {
+		u.bodyMutator = bodymutator.NewBodyMutator(backend.Backend.BodyMutation, rp.originalRequestBodyRaw)
+	}
 	// Header-derived labels/CEL must be able to see the overridden request model.
 	if u.modelNameOverride != "" {
 		u.requestHeaders[internalapi.ModelNameHeaderKeyDefault] = u.modelNameOverride
 	}
 	u.parent = rp // Set parent before GetTranslator so it can access rp.eh
+	// The backends without server-side state rely on the gateway to keep the responses.
+	if rp.responseStore() != nil && backend.Backend.Schema.Name != filterapi.APISchemaOpenAI {
+		u.responseRecorder = responsestore.NewRecorder(rp.originalRequestBodyRaw, rp.stream, rp.responseOwner())
+	}
+	if rp.responseCache != nil {
+		u.responseCacheBody = &bytes.Buffer{}
+	}
+	if piiSpec, ok := any(rp.eh).(endpointspec.PIISpec); ok && rp.piiRedactor != nil && rp.stream {
+		u.piiRestorer = rp.piiRedactor.NewStreamRestorer(piiSpec.StreamText)
+	}
+	if guardrailSpec, ok := any(rp.eh).(endpointspec.GuardrailSpec); ok && rp.guardrail != nil && rp.stream {
+		u.guardrailWindow = guardrail.NewStreamWindow(rp.guardrail.StreamWindowSize, guardrailSpec.StreamText)
+	}
 
 	u.translator, err = u.parent.eh.GetTranslator(backend.Backend.Schema, u.modelNameOverride)
 	if err != nil {
 		return fmt.Errorf("failed to create translator for backend %s: %w", backend.Backend.Name, err)
 	}
 	rp.upstreamFilter = u // Only assign after translator is confirmed valid
+	if configurer, ok := any(u.translator).(translator.BatchConfigurer); ok {
+		configurer.SetBatchConfig(backend.Backend.Batch)
+	}
 
 	switch redactor := u.translator.(type) {
 	case translator.ResponseRedactor:
@@ -703,8 +1055,39 @@ func mergeDynamicMetadata(base, extra *structpb.Struct) *structpb.Struct {
 	return base
 }
 
+// costVariables returns the variables of the cost expressions of the request and the response so far, shared by the
+// LLM request costs, the prices and the quotas so that they all see the same inputs.
+func (u *upstreamProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) costVariables(responseModel internalapi.ResponseModel) llmcostcel.Variables {
+	vars := costVariables(&u.costs)
+	vars.Model = u.requestHeaders[internalapi.ModelNameHeaderKeyDefault]
+	vars.Backend = u.backendName
+	vars.RouteName = u.routeName
+	vars.RequestHeaders = u.requestHeaders
+	vars.Stream = u.parent.stream
+	vars.Operation = string(u.parent.operation)
+	vars.ResponseModel = responseModel
+	return vars
+}
+
+// costVariables returns the variables of the cost CEL expressions populated with the given token usage.
+func costVariables(costs *metrics.TokenUsage) llmcostcel.Variables {
+	var vars llmcostcel.Variables
+	vars.InputTokens, _ = costs.InputTokens()
+	vars.CachedInputTokens, _ = costs.CachedInputTokens()
+	vars.CacheCreationInputTokens, _ = costs.CacheCreationInputTokens()
+	vars.OutputTokens, _ = costs.OutputTokens()
+	vars.TotalTokens, _ = costs.TotalTokens()
+	vars.ReasoningTokens, _ = costs.ReasoningTokens()
+	vars.AudioDurationSeconds, _ = costs.AudioDurationSeconds()
+	vars.ImageCount, _ = costs.ImageCount()
+	vars.AudioCount, _ = costs.AudioCount()
+	vars.ToolCallCount, _ = costs.ToolCallCount()
+	return vars
+}
+
 // evalCost is a helper function that computes the cost value based on the cost type and CEL program.
-func evalCost(costType filterapi.LLMRequestCostType, celProg cel.Program, costs *metrics.TokenUsage, requestHeaders map[string]string, backendName, routeName string) (uint64, error) {
+// The vars are only used by the CEL expression.
+func evalCost(costType filterapi.LLMRequestCostType, celProg cel.Program, costs *metrics.TokenUsage, vars *llmcostcel.Variables) (uint64, error) {
 	var cost uint64
 	switch costType {
 	case filterapi.LLMRequestCostTypeInputToken:
@@ -727,25 +1110,7 @@ func evalCost(costType filterapi.LLMRequestCostType, celProg cel.Program, costs
 		cost = uint64(v)
 	case filterapi.LLMRequestCostTypeCEL:
 		var err error
-
-		in, _ := costs.InputTokens()
-		cachedIn, _ := costs.CachedInputTokens()
-		cacheCreation, _ := costs.CacheCreationInputTokens()
-		out, _ := costs.OutputTokens()
-		total, _ := costs.TotalTokens()
-		reasoning, _ := costs.ReasoningTokens()
-		cost, err = llmcostcel.EvaluateProgram(
-			celProg,
-			requestHeaders[internalapi.ModelNameHeaderKeyDefault],
-			backendName,
-			routeName,
-			in,
-			cachedIn,
-			cacheCreation,
-			out,
-			total,
-			reasoning,
-		)
+		cost, err = llmcostcel.EvaluateProgram(celProg, *vars)
 		if err != nil {
 			return 0, fmt.Errorf("failed to evaluate CEL expression: %w", err)
 		}
@@ -756,13 +1121,13 @@ func evalCost(costType filterapi.LLMRequestCostType, celProg cel.Program, costs
 }
 
 // evalRuntimeGlobalRequestCost computes the cost value for a single global runtime cost rule.
-func evalRuntimeGlobalRequestCost(rc *filterapi.RuntimeGlobalRequestCost, costs *metrics.TokenUsage, requestHeaders map[string]string, backendName, routeName string) (uint64, error) {
-	return evalCost(rc.Type, rc.CELProg, costs, requestHeaders, backendName, routeName)
+func evalRuntimeGlobalRequestCost(rc *filterapi.RuntimeGlobalRequestCost, costs *metrics.TokenUsage, vars *llmcostcel.Variables) (uint64, error) {
+	return evalCost(rc.Type, rc.CELProg, costs, vars)
 }
 
 // evalRuntimeRequestCost computes the cost value for a single route-scoped runtime cost rule.
-func evalRuntimeRequestCost(rc *filterapi.RuntimeRequestCost, costs *metrics.TokenUsage, requestHeaders map[string]string, backendName, routeName string) (uint64, error) {
-	return evalCost(rc.Type, rc.CELProg, costs, requestHeaders, backendName, routeName)
+func evalRuntimeRequestCost(rc *filterapi.RuntimeRequestCost, costs *metrics.TokenUsage, vars *llmcostcel.Variables) (uint64, error) {
+	return evalCost(rc.Type, rc.CELProg, costs, vars)
 }
 
 // buildDynamicMetadata creates metadata for rate limiting and cost tracking.
@@ -770,10 +1135,19 @@ func evalRuntimeRequestCost(rc *filterapi.RuntimeRequestCost, costs *metrics.Tok
 // when the response is successfully completed. It is not called for failed requests or partial responses.
 // The metadata includes token usage costs and model information for downstream processing.
 //
-// Two-tier precedence: for each metadataKey, check route-scoped requestCosts first (matching RouteName == routeName).
+// Two-tier precedence: for each metadataKey, check route-scoped requestCosts first (matching RouteName == vars.RouteName).
 // If found, use it. Otherwise, fall back to globalRequestCosts. If neither exists, the key is not emitted.
-func buildDynamicMetadata(globalRequestCosts []filterapi.RuntimeGlobalRequestCost, requestCosts []filterapi.RuntimeRequestCost, costs *metrics.TokenUsage, requestHeaders map[string]string, backendName, routeName, responseModel string) (*structpb.Struct, error) {
-	metadata := make(map[string]*structpb.Value, len(requestCosts)+len(globalRequestCosts)+3)
+//
+// The vars carry the request information such as the model, backend and route names, and are also passed to the CEL expressions.
+// When priced is true, the model has a price in the pricing catalog and vars.Cost is emitted under the "cost" key.
+func buildDynamicMetadata(globalRequestCosts []filterapi.RuntimeGlobalRequestCost, requestCosts []filterapi.RuntimeRequestCost, costs *metrics.TokenUsage, vars *llmcostcel.Variables, priced bool) (*structpb.Struct, error) {
+	metadata := make(map[string]*structpb.Value, len(requestCosts)+len(globalRequestCosts)+5)
+
+	// The cost in the currency of the pricing catalog, e.g. for the rate limits of the spending.
+	// This is set first so that the costs configured with the same metadata key take precedence.
+	if priced {
+		metadata["cost"] = &structpb.Value{Kind: &structpb.Value_NumberValue{NumberValue: vars.Cost}}
+	}
 
 	// Track which metadata keys have been populated by route-scoped costs.
 	populatedKeys := make(map[string]struct{})
@@ -782,10 +1156,10 @@ func buildDynamicMetadata(globalRequestCosts []filterapi.RuntimeGlobalRequestCos
 	// Route-scoped costs must have a RouteName set (validated at runtime config creation).
 	for i := range requestCosts {
 		rc := &requestCosts[i]
-		if rc.RouteName != routeName {
+		if rc.RouteName != vars.RouteName {
 			continue
 		}
-		cost, err := evalRuntimeRequestCost(rc, costs, requestHeaders, backendName, routeName)
+		cost, err := evalRuntimeRequestCost(rc, costs, vars)
 		if err != nil {
 			return nil, err
 		}
@@ -799,7 +1173,7 @@ func buildDynamicMetadata(globalRequestCosts []filterapi.RuntimeGlobalRequestCos
 		if _, exists := populatedKeys[rc.MetadataKey]; exists {
 			continue // Route-scoped cost already set this key.
 		}
-		cost, err := evalRuntimeGlobalRequestCost(rc, costs, requestHeaders, backendName, routeName)
+		cost, err := evalRuntimeGlobalRequestCost(rc, costs, vars)
 		if err != nil {
 			return nil, err
 		}
@@ -808,19 +1182,18 @@ func buildDynamicMetadata(globalRequestCosts []filterapi.RuntimeGlobalRequestCos
 
 	// Add the actual request model that was used (after any backend overrides were applied).
 	// At this point, the header contains the final model that was sent to the upstream.
-	actualModel := requestHeaders[internalapi.ModelNameHeaderKeyDefault]
-	metadata["model_name_override"] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: actualModel}}
+	metadata["model_name_override"] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: vars.Model}}
 
-	if backendName != "" {
-		metadata["backend_name"] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: backendName}}
+	if vars.Backend != "" {
+		metadata["backend_name"] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: vars.Backend}}
 	}
-	if routeName != "" {
-		metadata["route_name"] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: routeName}}
+	if vars.RouteName != "" {
+		metadata["route_name"] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: vars.RouteName}}
 	}
 
-	// responseModel is the actual model that served the request.
-	if responseModel != "" {
-		metadata["response_model"] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: responseModel}}
+	// ResponseModel is the actual model that served the request.
+	if vars.ResponseModel != "" {
+		metadata["response_model"] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: vars.ResponseModel}}
 	}
 
 	if len(metadata) == 0 {
@@ -19,6 +19,7 @@ import (
 	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/google/cel-go/cel"
 	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
 	"go.opentelemetry.io/otel/propagation"
 	"google.golang.org/protobuf/types/known/structpb"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/envoyproxy/ai-gateway/internal/json"
 	"github.com/envoyproxy/ai-gateway/internal/llmcostcel"
 	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/responsestore"
 	"github.com/envoyproxy/ai-gateway/internal/testing/testotel"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
 )
@@ -70,6 +72,10 @@ func TestNewFactory(t *testing.T) {
 type (
 	chatCompletionProcessorRouterFilter   = routerProcessor[openai.ChatCompletionRequest, openai.ChatCompletionResponse, openai.ChatCompletionResponseChunk, endpointspec.ChatCompletionsEndpointSpec]
 	chatCompletionProcessorUpstreamFilter = upstreamProcessor[openai.ChatCompletionRequest, openai.ChatCompletionResponse, openai.ChatCompletionResponseChunk, endpointspec.ChatCompletionsEndpointSpec]
+	responsesProcessorRouterFilter        = routerProcessor[openai.ResponseRequest, openai.Response, openai.ResponseStreamEventUnion, endpointspec.ResponsesEndpointSpec]
+	responsesProcessorUpstreamFilter      = upstreamProcessor[openai.ResponseRequest, openai.Response, openai.ResponseStreamEventUnion, endpointspec.ResponsesEndpointSpec]
+	transcriptionProcessorRouterFilter    = routerProcessor[openai.TranscriptionRequest, openai.TranscriptionResponse, openai.TranscriptionStreamEvent, endpointspec.AudioTranscriptionsEndpointSpec]
+	batchesProcessorRouterFilter          = routerProcessor[openai.BatchRequest, openai.Batch, struct{}, endpointspec.BatchesEndpointSpec]
 )
 
 type mockTracer struct {
@@ -87,6 +93,44 @@ func (m *mockTracer) StartSpanAndInjectHeaders(_ context.Context, _ map[string]s
 	return nil
 }
 
+func Test_routerProcessor_parseBody_multipart(t *testing.T) {
+	// The preamble cannot be skipped without the boundary of the content-type header.
+	body := []byte("preamble\r\n--b\r\nContent-Disposition: form-data; name=\"model\"\r\n\r\nwhisper-1\r\n--b--\r\n")
+
+	t.Run("form", func(t *testing.T) {
+		p := &transcriptionProcessorRouterFilter{requestHeaders: map[string]string{"content-type": "multipart/form-data; boundary=b"}}
+		model, req, stream, mutated, err := p.parseBody(body, false)
+		require.NoError(t, err)
+		require.Equal(t, "whisper-1", model)
+		require.Equal(t, "whisper-1", req.Model)
+		require.False(t, stream)
+		require.Nil(t, mutated)
+		require.Equal(t, "b", p.multipartBoundary)
+	})
+
+	t.Run("invalid form", func(t *testing.T) {
+		p := &transcriptionProcessorRouterFilter{requestHeaders: map[string]string{"content-type": "multipart/form-data; boundary=c"}}
+		_, _, _, _, err := p.parseBody(body, false)
+		require.ErrorIs(t, err, internalapi.ErrMalformedRequest)
+		require.ErrorContains(t, err, "failed to parse multipart form: missing multipart boundary")
+		require.Empty(t, p.multipartBoundary)
+	})
+
+	t.Run("no content-type", func(t *testing.T) {
+		p := &transcriptionProcessorRouterFilter{requestHeaders: map[string]string{}}
+		_, _, _, _, err := p.parseBody(body, false)
+		require.ErrorContains(t, err, "failed to parse multipart form for /v1/audio/transcriptions: missing multipart boundary")
+		require.Empty(t, p.multipartBoundary)
+	})
+
+	t.Run("not a form spec", func(t *testing.T) {
+		p := &chatCompletionProcessorRouterFilter{requestHeaders: map[string]string{"content-type": "multipart/form-data; boundary=b"}}
+		_, _, _, _, err := p.parseBody(body, false)
+		require.ErrorContains(t, err, "failed to parse JSON for /v1/chat/completions")
+		require.Empty(t, p.multipartBoundary)
+	})
+}
+
 func Test_chatCompletionProcessorRouterFilter_ProcessRequestBody(t *testing.T) {
 	t.Run("body parser error", func(t *testing.T) {
 		p := &chatCompletionProcessorRouterFilter{
@@ -211,6 +255,63 @@ func Test_chatCompletionProcessorRouterFilter_ProcessRequestBody(t *testing.T) {
 	})
 }
 
+func Test_routerProcessor_ProcessRequestHeaders(t *testing.T) {
+	t.Run("not a header spec", func(t *testing.T) {
+		p := &chatCompletionProcessorRouterFilter{requestHeaders: map[string]string{":method": "GET", ":path": "/v1/chat/completions"}}
+		resp, err := p.ProcessRequestHeaders(t.Context(), nil)
+		require.NoError(t, err)
+		require.Nil(t, resp.GetRequestHeaders().GetResponse())
+		require.False(t, p.routedOnHeaders)
+	})
+
+	t.Run("routed on body", func(t *testing.T) {
+		p := &batchesProcessorRouterFilter{requestHeaders: map[string]string{":method": "POST", ":path": "/v1/batches"}}
+		resp, err := p.ProcessRequestHeaders(t.Context(), nil)
+		require.NoError(t, err)
+		require.Nil(t, resp.GetRequestHeaders().GetResponse())
+		require.False(t, p.routedOnHeaders)
+	})
+
+	t.Run("routed on headers", func(t *testing.T) {
+		batchID := internalapi.EncodeModelScopedID(openai.BatchIDPrefix, "gpt-4o-mini", "batch_abc123")
+		p := &batchesProcessorRouterFilter{
+			config:         &filterapi.RuntimeConfig{},
+			requestHeaders: map[string]string{":method": "GET", ":path": "/v1/batches/" + batchID},
+			logger:         slog.Default(),
+			tracer:         tracingapi.NoopBatchesTracer{},
+		}
+		resp, err := p.ProcessRequestHeaders(t.Context(), nil)
+		require.NoError(t, err)
+		require.True(t, p.routedOnHeaders)
+		require.Equal(t, "gpt-4o-mini", p.originalModel)
+		require.Equal(t, &openai.BatchRequest{Model: "gpt-4o-mini", Operation: openai.BatchOperationRetrieve, BatchID: "batch_abc123"}, p.originalRequestBody)
+
+		commonResp := resp.GetRequestHeaders().GetResponse()
+		require.True(t, commonResp.ClearRouteCache)
+		setHeaders := commonResp.GetHeaderMutation().SetHeaders
+		require.Equal(t, internalapi.ModelNameHeaderKeyDefault, setHeaders[0].Header.Key)
+		require.Equal(t, "gpt-4o-mini", string(setHeaders[0].Header.RawValue))
+
+		// The body, if any, is passed through since the request has already been routed.
+		resp, err = p.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{Body: []byte("nonjson")})
+		require.NoError(t, err)
+		require.Nil(t, resp.GetRequestBody().GetResponse())
+	})
+
+	t.Run("unknown batch ID", func(t *testing.T) {
+		p := &batchesProcessorRouterFilter{
+			requestHeaders: map[string]string{":method": "GET", ":path": "/v1/batches/batch_abc123"},
+			logger:         slog.Default(),
+		}
+		resp, err := p.ProcessRequestHeaders(t.Context(), nil)
+		require.NoError(t, err)
+		immediateResp, ok := resp.Response.(*extprocv3.ProcessingResponse_ImmediateResponse)
+		require.True(t, ok)
+		require.Equal(t, typev3.StatusCode(400), immediateResp.ImmediateResponse.Status.Code)
+		require.Contains(t, string(immediateResp.ImmediateResponse.Body), `unknown batch ID \"batch_abc123\"`)
+	})
+}
+
 func Test_chatCompletionProcessorUpstreamFilter_ProcessResponseHeaders(t *testing.T) {
 	t.Run("error translation", func(t *testing.T) {
 		mm := &mockMetrics{}
@@ -364,6 +465,94 @@ func Test_chatCompletionProcessorUpstreamFilter_ProcessResponseBody(t *testing.T
 		require.Equal(t, "some_model", md.Fields[internalapi.AIGatewayFilterMetadataNamespace].GetStructValue().Fields["response_model"].GetStringValue())
 	})
 
+	t.Run("priced", func(t *testing.T) {
+		inBody := &extprocv3.HttpBody{Body: []byte("some-body"), EndOfStream: true}
+		mm := &mockMetrics{}
+		mt := &mockTranslator{t: t, expResponseBody: inBody, retResponseModel: "gpt-4o-2024-08-06"}
+		mt.retUsedToken.SetInputTokens(1000)
+		mt.retUsedToken.SetOutputTokens(100)
+		p := &chatCompletionProcessorUpstreamFilter{
+			translator: mt,
+			metrics:    mm,
+			parent: &chatCompletionProcessorRouterFilter{
+				config: &filterapi.RuntimeConfig{
+					Prices: llmcostcel.PriceTable{
+						"gpt-4o": {llmcostcel.PriceUnitInput: 2e-6, llmcostcel.PriceUnitOutput: 8e-6},
+						"o3":     {llmcostcel.PriceUnitInput: 1},
+					},
+					Currency: "USD",
+				},
+			},
+			// The prices of the backend take precedence.
+			prices:          llmcostcel.PriceTable{"o3": {llmcostcel.PriceUnitInput: 1e-6}},
+			requestHeaders:  map[string]string{internalapi.ModelNameHeaderKeyDefault: "gpt-4o"},
+			responseHeaders: map[string]string{":status": "200"},
+		}
+		res, err := p.ProcessResponseBody(t.Context(), inBody)
+		require.NoError(t, err)
+		require.InDelta(t, 0.0028, mm.cost, 1e-12)
+		require.Equal(t, "USD", mm.currency)
+		require.InDelta(t, 0.0028, res.DynamicMetadata.Fields[internalapi.AIGatewayFilterMetadataNamespace].
+			GetStructValue().Fields["cost"].GetNumberValue(), 1e-12)
+
+		mm = &mockMetrics{}
+		p.metrics = mm
+		p.requestHeaders[internalapi.ModelNameHeaderKeyDefault] = "o3"
+		mt.retResponseModel = "o3"
+		res, err = p.ProcessResponseBody(t.Context(), inBody)
+		require.NoError(t, err)
+		require.InDelta(t, 0.001, mm.cost, 1e-12)
+		require.InDelta(t, 0.001, res.DynamicMetadata.Fields[internalapi.AIGatewayFilterMetadataNamespace].
+			GetStructValue().Fields["cost"].GetNumberValue(), 1e-12)
+
+		// No cost is recorded for the models without a price.
+		mm = &mockMetrics{}
+		p.metrics = mm
+		p.requestHeaders[internalapi.ModelNameHeaderKeyDefault] = "unknown"
+		mt.retResponseModel = "unknown"
+		res, err = p.ProcessResponseBody(t.Context(), inBody)
+		require.NoError(t, err)
+		require.Zero(t, mm.cost)
+		require.Nil(t, res.DynamicMetadata)
+	})
+
+	t.Run("request costs of the backend with prices", func(t *testing.T) {
+		inBody := &extprocv3.HttpBody{Body: []byte("some-body"), EndOfStream: true}
+		mt := &mockTranslator{t: t, expResponseBody: inBody, retResponseModel: "o3"}
+		mt.retUsedToken.SetInputTokens(10)
+		const expr = `uint(price(model, "input") * double(input_tokens))`
+		newCost := func(prices llmcostcel.PriceTable) filterapi.RuntimeGlobalRequestCost {
+			prog, err := llmcostcel.NewProgramWithPriceTable(expr, prices)
+			require.NoError(t, err)
+			return filterapi.RuntimeGlobalRequestCost{
+				GlobalLLMRequestCost: &filterapi.GlobalLLMRequestCost{MetadataKey: "price_cost", Type: filterapi.LLMRequestCostTypeCEL, CEL: expr},
+				CELProg:              prog,
+			}
+		}
+		gatewayPrices := llmcostcel.PriceTable{"o3": {llmcostcel.PriceUnitInput: 2}}
+		backendPrices := llmcostcel.PriceTable{"o3": {llmcostcel.PriceUnitInput: 3}}
+		p := &chatCompletionProcessorUpstreamFilter{
+			translator: mt,
+			metrics:    &mockMetrics{},
+			parent: &chatCompletionProcessorRouterFilter{
+				config: &filterapi.RuntimeConfig{
+					Prices:             gatewayPrices,
+					GlobalRequestCosts: []filterapi.RuntimeGlobalRequestCost{newCost(gatewayPrices)},
+				},
+			},
+			prices:             backendPrices,
+			globalRequestCosts: []filterapi.RuntimeGlobalRequestCost{newCost(gatewayPrices.WithOverrides(backendPrices))},
+			requestHeaders:     map[string]string{internalapi.ModelNameHeaderKeyDefault: "o3"},
+			responseHeaders:    map[string]string{":status": "200"},
+		}
+		res, err := p.ProcessResponseBody(t.Context(), inBody)
+		require.NoError(t, err)
+		// The price function sees the same price of the backend as the cost.
+		md := res.DynamicMetadata.Fields[internalapi.AIGatewayFilterMetadataNamespace].GetStructValue()
+		require.Equal(t, float64(30), md.Fields["price_cost"].GetNumberValue())
+		require.InDelta(t, 30, md.Fields["cost"].GetNumberValue(), 1e-12)
+	})
+
 	// Verify we record failure for non-2xx responses and do it exactly once (defer suppressed).
 	t.Run("non-2xx status failure once", func(t *testing.T) {
 		inBody := &extprocv3.HttpBody{Body: []byte("error-body"), EndOfStream: true}
@@ -581,6 +770,39 @@ func Test_chatCompletionProcessorUpstreamFilter_ProcessRequestHeaders(t *testing
 				require.Equal(t, "some-model", mm.originalModel)
 				require.Equal(t, "some-model", mm.requestModel)
 			})
+			t.Run("local response", func(t *testing.T) {
+				headers := map[string]string{":path": "/foo", internalapi.ModelNameHeaderKeyDefault: "some-model"}
+				someBody := bodyFromModel(t, "some-model", tc.stream, nil)
+				var body openai.ChatCompletionRequest
+				require.NoError(t, json.Unmarshal(someBody, &body))
+				tr := &mockLocalResponder{
+					mockTranslator: mockTranslator{t: t, expRequestBody: &body},
+					response:       []byte(`{"input_tokens":10}`),
+				}
+				mm := &mockMetrics{}
+				p := &chatCompletionProcessorUpstreamFilter{
+					parent: &chatCompletionProcessorRouterFilter{
+						config:                 &filterapi.RuntimeConfig{},
+						logger:                 slog.Default(),
+						originalRequestBodyRaw: someBody,
+						originalRequestBody:    &body,
+						originalModel:          "some-model",
+						stream:                 tc.stream,
+					},
+					requestHeaders: headers,
+					metrics:        mm,
+					translator:     tr,
+					logger:         slog.Default(),
+				}
+				resp, err := p.ProcessRequestHeaders(t.Context(), nil)
+				require.NoError(t, err)
+
+				immediateResp, ok := resp.Response.(*extprocv3.ProcessingResponse_ImmediateResponse)
+				require.True(t, ok, "Response should be an immediate response")
+				require.Equal(t, typev3.StatusCode_OK, immediateResp.ImmediateResponse.Status.Code)
+				require.JSONEq(t, `{"input_tokens":10}`, string(immediateResp.ImmediateResponse.Body))
+				mm.RequireRequestSuccess(t)
+			})
 			t.Run("auth handler error", func(t *testing.T) {
 				headers := map[string]string{":path": "/foo", internalapi.ModelNameHeaderKeyDefault: "some-model"}
 				someBody := bodyFromModel(t, "some-model", tc.stream, nil)
@@ -1480,7 +1702,12 @@ func TestBuildDynamicMetadata_routeScoped(t *testing.T) {
 			tu.SetInputTokens(tt.inputTokens)
 			tu.SetTotalTokens(tt.totalTokens)
 
-			md, err := buildDynamicMetadata(nil, tt.requestCosts, &tu, tt.requestHeaders, tt.backendName, tt.routeName, "")
+			vars := costVariables(&tu)
+			vars.Model = tt.requestHeaders[internalapi.ModelNameHeaderKeyDefault]
+			vars.Backend = tt.backendName
+			vars.RouteName = tt.routeName
+			vars.RequestHeaders = tt.requestHeaders
+			md, err := buildDynamicMetadata(nil, tt.requestCosts, &tu, &vars, false)
 			require.NoError(t, err)
 
 			ns := md.Fields[internalapi.AIGatewayFilterMetadataNamespace].GetStructValue().Fields
@@ -1649,7 +1876,12 @@ func TestBuildDynamicMetadata_GlobalAndRouteScoped(t *testing.T) {
 			tu.SetOutputTokens(tt.outputTokens)
 			tu.SetTotalTokens(tt.totalTokens)
 
-			md, err := buildDynamicMetadata(tt.globalCosts, tt.routeCosts, &tu, tt.requestHeaders, tt.backendName, tt.routeName, "")
+			vars := costVariables(&tu)
+			vars.Model = tt.requestHeaders[internalapi.ModelNameHeaderKeyDefault]
+			vars.Backend = tt.backendName
+			vars.RouteName = tt.routeName
+			vars.RequestHeaders = tt.requestHeaders
+			md, err := buildDynamicMetadata(tt.globalCosts, tt.routeCosts, &tu, &vars, false)
 			require.NoError(t, err)
 
 			ns := md.Fields[internalapi.AIGatewayFilterMetadataNamespace].GetStructValue().Fields
@@ -1664,6 +1896,58 @@ func TestBuildDynamicMetadata_GlobalAndRouteScoped(t *testing.T) {
 	}
 }
 
+func TestBuildDynamicMetadata_requestVariables(t *testing.T) {
+	var tu metrics.TokenUsage
+	tu.SetOutputTokens(10)
+	tu.SetToolCallCount(2)
+	prog := mustCompileCEL(t, "(stream && operation == 'chat' && response_model == 'gpt-4o-2024-08-06' ? output_tokens : uint(0)) + "+
+		"tool_call_count * uint(100) + ('x-tier' in request_headers ? uint(1000) : uint(0))")
+	vars := costVariables(&tu)
+	vars.Model = "gpt-4o"
+	vars.RequestHeaders = map[string]string{"x-tier": "premium"}
+	vars.Stream = true
+	vars.Operation = "chat"
+	vars.ResponseModel = "gpt-4o-2024-08-06"
+	md, err := buildDynamicMetadata([]filterapi.RuntimeGlobalRequestCost{
+		{GlobalLLMRequestCost: &filterapi.GlobalLLMRequestCost{MetadataKey: "cost", Type: filterapi.LLMRequestCostTypeCEL}, CELProg: prog},
+	}, nil, &tu, &vars, false)
+	require.NoError(t, err)
+	ns := md.Fields[internalapi.AIGatewayFilterMetadataNamespace].GetStructValue().Fields
+	require.Equal(t, float64(1210), ns["cost"].GetNumberValue())
+	require.Equal(t, "gpt-4o", ns["model_name_override"].GetStringValue())
+	require.Equal(t, "gpt-4o-2024-08-06", ns["response_model"].GetStringValue())
+}
+
+func TestBuildDynamicMetadata_priced(t *testing.T) {
+	var tu metrics.TokenUsage
+	tu.SetInputTokens(1000)
+	vars := costVariables(&tu)
+	vars.Model = "gpt-4o"
+	vars.Cost = 0.0025
+
+	md, err := buildDynamicMetadata([]filterapi.RuntimeGlobalRequestCost{
+		{GlobalLLMRequestCost: &filterapi.GlobalLLMRequestCost{MetadataKey: "micro_dollars", Type: filterapi.LLMRequestCostTypeCEL}, CELProg: mustCompileCEL(t, "uint(cost * 1000000.0)")},
+	}, nil, &tu, &vars, true)
+	require.NoError(t, err)
+	ns := md.Fields[internalapi.AIGatewayFilterMetadataNamespace].GetStructValue().Fields
+	require.Equal(t, 0.0025, ns["cost"].GetNumberValue())
+	require.Equal(t, float64(2500), ns["micro_dollars"].GetNumberValue())
+
+	// The costs configured with the same metadata key take precedence.
+	md, err = buildDynamicMetadata([]filterapi.RuntimeGlobalRequestCost{
+		{GlobalLLMRequestCost: &filterapi.GlobalLLMRequestCost{MetadataKey: "cost", Type: filterapi.LLMRequestCostTypeInputToken}},
+	}, nil, &tu, &vars, true)
+	require.NoError(t, err)
+	ns = md.Fields[internalapi.AIGatewayFilterMetadataNamespace].GetStructValue().Fields
+	require.Equal(t, float64(1000), ns["cost"].GetNumberValue())
+
+	// Not emitted if the model has no price.
+	md, err = buildDynamicMetadata(nil, nil, &tu, &vars, false)
+	require.NoError(t, err)
+	ns = md.Fields[internalapi.AIGatewayFilterMetadataNamespace].GetStructValue().Fields
+	require.NotContains(t, ns, "cost")
+}
+
 // mustCompileCEL is a test helper that compiles a CEL expression or fails the test.
 func mustCompileCEL(t *testing.T, expr string) cel.Program {
 	t.Helper()
@@ -1671,3 +1955,107 @@ func mustCompileCEL(t *testing.T, expr string) cel.Program {
 	require.NoError(t, err)
 	return prog
 }
+
+func Test_responsesProcessor_ResponseStore(t *testing.T) {
+	store := responsestore.NewMemoryStore()
+	require.NoError(t, store.Put(t.Context(), &responsestore.Response{
+		ID:    "resp_1",
+		Owner: responsestore.Owner("Bearer alice"),
+		Input: []byte(`[{"type":"message","role":"user","content":"Hi"}]`),
+		Body:  []byte(`{"id":"resp_1","status":"completed","output":[{"type":"message","id":"msg_1","role":"assistant","status":"completed","content":[{"type":"output_text","text":"Hello!","annotations":[]}]}]}`),
+	}))
+	newRouterAs := func(authorization string) *responsesProcessorRouterFilter {
+		return &responsesProcessorRouterFilter{
+			config:         &filterapi.RuntimeConfig{ResponseStore: store, ResponseStoreOwnerHeader: "authorization"},
+			requestHeaders: map[string]string{":path": "/v1/responses", "authorization": authorization},
+			logger:         slog.Default(),
+			tracer:         tracingapi.NoopTracer[openai.ResponseRequest, openai.Response, openai.ResponseStreamEventUnion]{},
+		}
+	}
+	newRouter := func() *responsesProcessorRouterFilter { return newRouterAs("Bearer alice") }
+	anthropicBackend := &filterapi.RuntimeBackend{Backend: &filterapi.Backend{
+		Name: "anthropic", Schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaAnthropic},
+	}}
+
+	t.Run("continue from stored response", func(t *testing.T) {
+		r := newRouter()
+		_, err := r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{
+			Body: []byte(`{"model":"claude","input":"How are you?","previous_response_id":"resp_1"}`),
+		})
+		require.NoError(t, err)
+		require.True(t, r.forceBodyMutation)
+		require.Empty(t, r.originalRequestBody.PreviousResponseID)
+		require.JSONEq(t, `{"model":"claude","input":[
+{"type":"message","role":"user","content":"Hi"},
+{"type":"message","id":"msg_1","role":"assistant","status":"completed","content":[{"type":"output_text","text":"Hello!","annotations":[]}]},
+{"type":"message","role":"user","content":"How are you?"}
+]}`, string(r.originalRequestBodyRaw))
+
+		u := &responsesProcessorUpstreamFilter{requestHeaders: r.requestHeaders, metrics: &mockMetrics{}, logger: slog.Default()}
+		require.NoError(t, u.SetBackend(t.Context(), anthropicBackend, "route", r))
+		require.NotNil(t, u.responseRecorder)
+		_, err = u.ProcessRequestHeaders(t.Context(), nil)
+		require.NoError(t, err)
+		_, err = u.ProcessResponseHeaders(t.Context(), &corev3.HeaderMap{Headers: []*corev3.HeaderValue{{Key: ":status", Value: "200"}}})
+		require.NoError(t, err)
+		_, err = u.ProcessResponseBody(t.Context(), &extprocv3.HttpBody{EndOfStream: true, Body: []byte(
+			`{"id":"msg_2","type":"message","role":"assistant","model":"claude","content":[{"type":"text","text":"Good."}],"stop_reason":"end_turn","usage":{"input_tokens":10,"output_tokens":2}}`,
+		)})
+		require.NoError(t, err)
+
+		stored, err := store.Get(t.Context(), "resp_2")
+		require.NoError(t, err)
+		require.JSONEq(t, `[
+{"type":"message","role":"user","content":"Hi"},
+{"type":"message","id":"msg_1","role":"assistant","status":"completed","content":[{"type":"output_text","text":"Hello!","annotations":[]}]},
+{"type":"message","role":"user","content":"How are you?"}
+]`, string(stored.Input))
+		require.Equal(t, "Good.", gjson.GetBytes(stored.Body, "output.0.content.0.text").String())
+		require.Equal(t, responsestore.Owner("Bearer alice"), stored.Owner)
+	})
+
+	t.Run("unknown previous response", func(t *testing.T) {
+		r := newRouter()
+		body := []byte(`{"model":"gpt","input":"Hi","previous_response_id":"resp_unknown"}`)
+		_, err := r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{Body: body})
+		require.NoError(t, err)
+		require.False(t, r.forceBodyMutation)
+		require.Equal(t, "resp_unknown", r.originalRequestBody.PreviousResponseID)
+		require.Equal(t, body, r.originalRequestBodyRaw)
+	})
+
+	t.Run("previous response of another caller", func(t *testing.T) {
+		r := newRouterAs("Bearer bob")
+		body := []byte(`{"model":"claude","input":"Hi","previous_response_id":"resp_1"}`)
+		_, err := r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{Body: body})
+		require.NoError(t, err)
+		require.False(t, r.forceBodyMutation)
+		require.Equal(t, "resp_1", r.originalRequestBody.PreviousResponseID)
+		require.Equal(t, body, r.originalRequestBodyRaw)
+	})
+
+	t.Run("not recorded", func(t *testing.T) {
+		r := newRouter()
+		_, err := r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{Body: []byte(`{"model":"claude","input":"Hi","store":false}`)})
+		require.NoError(t, err)
+		u := &responsesProcessorUpstreamFilter{requestHeaders: r.requestHeaders, metrics: &mockMetrics{}, logger: slog.Default()}
+		require.NoError(t, u.SetBackend(t.Context(), anthropicBackend, "route", r))
+		require.Nil(t, u.responseRecorder)
+
+		// OpenAI keeps the responses on its side.
+		r = newRouter()
+		_, err = r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{Body: []byte(`{"model":"gpt","input":"Hi"}`)})
+		require.NoError(t, err)
+		u = &responsesProcessorUpstreamFilter{requestHeaders: r.requestHeaders, metrics: &mockMetrics{}, logger: slog.Default()}
+		require.NoError(t, u.SetBackend(t.Context(), &filterapi.RuntimeBackend{Backend: &filterapi.Backend{
+			Name: "openai", Schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI},
+		}}, "route", r))
+		require.Nil(t, u.responseRecorder)
+	})
+}
+
+func Test_formatUserFacingErrorJSON(t *testing.T) {
+	body := formatUserFacingErrorJSON("TooManyRequests", 429, `quota exceeded for model some"model\`)
+	require.JSONEq(t, `{"type":"error","error":{"type":"TooManyRequests","code":"429","message":"quota exceeded for model some\"model\\"}}`,
+		string(body))
+}
@@ -0,0 +1,164 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extproc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/cel-go/cel"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/llmcostcel"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/quota"
+)
+
+// quotaBucket is a single quota bucket that the request is checked against and charged to.
+type quotaBucket struct {
+	// name is the low-cardinality name of the bucket used in metrics.
+	name string
+	// key identifies the counter of the bucket. This differs from the name when the bucket
+	// is split by the distinct values of the request headers.
+	key    string
+	limit  uint64
+	window time.Duration
+	shadow bool
+}
+
+// quotaSelection is the set of quota buckets applicable to a request.
+type quotaSelection struct {
+	buckets []quotaBucket
+	// exclusive is true when the request is denied only if all the enforced buckets are exhausted.
+	exclusive bool
+	// costProg is the CEL program to calculate the quota burndown of the request.
+	costProg cel.Program
+}
+
+// selectQuotaBuckets returns the quota buckets applicable to the request for the given model.
+// This returns nil if no quota applies to the request.
+func selectQuotaBuckets(q *filterapi.RuntimeBackendQuota, model string, headers map[string]string) *quotaSelection {
+	for i := range q.PerModelQuotas {
+		m := &q.PerModelQuotas[i]
+		if m.ModelName != model {
+			continue
+		}
+		sel := &quotaSelection{
+			exclusive: m.Mode != filterapi.QuotaBucketModeShared,
+			costProg:  q.PerModelCostProgs[i],
+		}
+		enforcedRuleMatched := false
+		for j := range m.BucketRules {
+			rule := &m.BucketRules[j]
+			distinct, ok := matchQuotaClientSelectors(q.Regexps, rule.ClientSelectors, headers)
+			if !ok {
+				continue
+			}
+			name := fmt.Sprintf("%s/%s/rule/%d", q.Name, model, j)
+			sel.buckets = append(sel.buckets, quotaBucket{
+				name:   name,
+				key:    name + distinct,
+				limit:  rule.Quota.Limit,
+				window: rule.Quota.Duration,
+				shadow: rule.ShadowMode,
+			})
+			enforcedRuleMatched = enforcedRuleMatched || !rule.ShadowMode
+		}
+		// In the exclusive mode, the default bucket only applies when no enforced rule matches so that
+		// the shadow rules never change the outcome of the request.
+		if d := m.DefaultBucket; d != nil && (!sel.exclusive || !enforcedRuleMatched) {
+			name := fmt.Sprintf("%s/%s/default", q.Name, model)
+			sel.buckets = append(sel.buckets, quotaBucket{name: name, key: name, limit: d.Limit, window: d.Duration})
+		}
+		if len(sel.buckets) == 0 {
+			return nil
+		}
+		return sel
+	}
+	if s := q.ServiceQuota; s != nil {
+		name := q.Name + "/service"
+		return &quotaSelection{
+			buckets:  []quotaBucket{{name: name, key: name, limit: s.Quota.Limit, window: s.Quota.Duration}},
+			costProg: q.ServiceCostProg,
+		}
+	}
+	return nil
+}
+
+// matchQuotaClientSelectors returns true if all the selectors match the request headers.
+// The returned string is the suffix of the counter key derived from the values of the distinct header matches.
+func matchQuotaClientSelectors(regexps map[string]*regexp.Regexp, selectors []filterapi.QuotaClientSelector, headers map[string]string) (string, bool) {
+	var distinct strings.Builder
+	for _, sel := range selectors {
+		for _, h := range sel.Headers {
+			v, present := headers[h.Name]
+			var matched bool
+			switch h.Type {
+			case filterapi.QuotaHeaderMatchTypeDistinct:
+				if !present {
+					return "", false
+				}
+				distinct.WriteString("/" + h.Name + "=" + strconv.Quote(v))
+				continue
+			case filterapi.QuotaHeaderMatchTypeRegularExpression:
+				re := regexps[h.Value]
+				matched = present && re != nil && re.MatchString(v)
+			default:
+				matched = present && v == h.Value
+			}
+			if matched == h.Invert {
+				return "", false
+			}
+		}
+	}
+	return distinct.String(), true
+}
+
+// exceeded checks the buckets against the store and returns true if the request must be denied.
+// Exhausted buckets are reported in the metrics regardless of whether they are enforced.
+func (s *quotaSelection) exceeded(ctx context.Context, store quota.Store, m metrics.Metrics, requestHeaders map[string]string) (bool, error) {
+	enforced, exhausted := 0, 0
+	for _, b := range s.buckets {
+		if !b.shadow {
+			enforced++
+		}
+		usage, err := store.Usage(ctx, b.key, b.window)
+		if err != nil {
+			return false, fmt.Errorf("failed to get the usage of quota bucket %s: %w", b.name, err)
+		}
+		if usage < b.limit {
+			continue
+		}
+		m.RecordQuotaExceeded(ctx, b.name, b.shadow, requestHeaders)
+		if !b.shadow {
+			exhausted++
+		}
+	}
+	if s.exclusive {
+		return enforced > 0 && exhausted == enforced, nil
+	}
+	return exhausted > 0, nil
+}
+
+// charge calculates the cost of the request from the cost variables and charges it to all the buckets.
+func (s *quotaSelection) charge(ctx context.Context, store quota.Store, vars *llmcostcel.Variables) error {
+	cost, err := llmcostcel.EvaluateProgram(s.costProg, *vars)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate quota cost: %w", err)
+	}
+	var errs []error
+	for _, b := range s.buckets {
+		if err = store.Charge(ctx, b.key, b.window, cost); err != nil {
+			errs = append(errs, fmt.Errorf("failed to charge quota bucket %s: %w", b.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,369 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extproc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/quota"
+)
+
+func newTestRuntimeBackendQuota(t *testing.T, q *filterapi.BackendQuota) *filterapi.RuntimeBackendQuota {
+	rc, err := filterapi.NewRuntimeConfig(t.Context(), &filterapi.Config{
+		Backends: []filterapi.Backend{{Name: "backend", Quota: q}},
+	}, func(context.Context, *filterapi.BackendAuth) (filterapi.BackendAuthHandler, error) { return nil, nil })
+	require.NoError(t, err)
+	require.True(t, rc.HasQuota)
+	return rc.Backends["backend"].Quota
+}
+
+func Test_selectQuotaBuckets(t *testing.T) {
+	q := newTestRuntimeBackendQuota(t, &filterapi.BackendQuota{
+		Name:         "ns/policy/ns/backend",
+		ServiceQuota: &filterapi.ServiceQuota{Quota: filterapi.QuotaValue{Limit: 1000, Duration: time.Hour}},
+		PerModelQuotas: []filterapi.PerModelQuota{
+			{
+				ModelName:     "exclusive",
+				Mode:          filterapi.QuotaBucketModeExclusive,
+				DefaultBucket: &filterapi.QuotaValue{Limit: 100, Duration: time.Minute},
+				BucketRules: []filterapi.QuotaRule{
+					{
+						ClientSelectors: []filterapi.QuotaClientSelector{{Headers: []filterapi.QuotaHeaderMatch{
+							{Name: "x-tenant", Type: filterapi.QuotaHeaderMatchTypeExact, Value: "gold"},
+						}}},
+						Quota: filterapi.QuotaValue{Limit: 500, Duration: time.Minute},
+					},
+					{
+						ClientSelectors: []filterapi.QuotaClientSelector{{Headers: []filterapi.QuotaHeaderMatch{
+							{Name: "x-user", Type: filterapi.QuotaHeaderMatchTypeDistinct},
+						}}},
+						Quota:      filterapi.QuotaValue{Limit: 10, Duration: time.Minute},
+						ShadowMode: true,
+					},
+				},
+			},
+			{
+				ModelName:     "shared",
+				Mode:          filterapi.QuotaBucketModeShared,
+				DefaultBucket: &filterapi.QuotaValue{Limit: 100, Duration: time.Minute},
+				BucketRules: []filterapi.QuotaRule{
+					{
+						ClientSelectors: []filterapi.QuotaClientSelector{{Headers: []filterapi.QuotaHeaderMatch{
+							{Name: "x-tenant", Type: filterapi.QuotaHeaderMatchTypeRegularExpression, Value: "^silver-.*"},
+						}}},
+						Quota: filterapi.QuotaValue{Limit: 50, Duration: time.Minute},
+					},
+				},
+			},
+			{ModelName: "no-buckets", Mode: filterapi.QuotaBucketModeShared},
+		},
+	})
+
+	for _, tc := range []struct {
+		name    string
+		model   string
+		headers map[string]string
+		expKeys []string
+	}{
+		{name: "service quota", model: "other", expKeys: []string{"ns/policy/ns/backend/service"}},
+		{name: "exclusive default", model: "exclusive", expKeys: []string{"ns/policy/ns/backend/exclusive/default"}},
+		{
+			name:    "exclusive rule",
+			model:   "exclusive",
+			headers: map[string]string{"x-tenant": "gold"},
+			expKeys: []string{"ns/policy/ns/backend/exclusive/rule/0"},
+		},
+		{
+			name:    "exclusive shadow rule does not replace the default",
+			model:   "exclusive",
+			headers: map[string]string{"x-user": "alice"},
+			expKeys: []string{`ns/policy/ns/backend/exclusive/rule/1/x-user="alice"`, "ns/policy/ns/backend/exclusive/default"},
+		},
+		{
+			name:    "shared",
+			model:   "shared",
+			headers: map[string]string{"x-tenant": "silver-1"},
+			expKeys: []string{"ns/policy/ns/backend/shared/rule/0", "ns/policy/ns/backend/shared/default"},
+		},
+		{
+			name:    "shared no rule match",
+			model:   "shared",
+			headers: map[string]string{"x-tenant": "gold"},
+			expKeys: []string{"ns/policy/ns/backend/shared/default"},
+		},
+		{name: "no buckets", model: "no-buckets"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sel := selectQuotaBuckets(q, tc.model, tc.headers)
+			if tc.expKeys == nil {
+				require.Nil(t, sel)
+				return
+			}
+			require.NotNil(t, sel)
+			require.NotNil(t, sel.costProg)
+			var keys []string
+			for _, b := range sel.buckets {
+				keys = append(keys, b.key)
+			}
+			require.Equal(t, tc.expKeys, keys)
+		})
+	}
+}
+
+func Test_matchQuotaClientSelectors(t *testing.T) {
+	q := newTestRuntimeBackendQuota(t, &filterapi.BackendQuota{
+		PerModelQuotas: []filterapi.PerModelQuota{{
+			ModelName: "m",
+			BucketRules: []filterapi.QuotaRule{{ClientSelectors: []filterapi.QuotaClientSelector{{Headers: []filterapi.QuotaHeaderMatch{
+				{Name: "x-a", Type: filterapi.QuotaHeaderMatchTypeRegularExpression, Value: "^a+$"},
+			}}}}},
+		}},
+	})
+	for _, tc := range []struct {
+		name        string
+		match       filterapi.QuotaHeaderMatch
+		headers     map[string]string
+		expDistinct string
+		expOK       bool
+	}{
+		{name: "exact", match: filterapi.QuotaHeaderMatch{Name: "x-a", Type: filterapi.QuotaHeaderMatchTypeExact, Value: "v"}, headers: map[string]string{"x-a": "v"}, expOK: true},
+		{name: "exact mismatch", match: filterapi.QuotaHeaderMatch{Name: "x-a", Type: filterapi.QuotaHeaderMatchTypeExact, Value: "v"}, headers: map[string]string{"x-a": "w"}},
+		{name: "exact absent", match: filterapi.QuotaHeaderMatch{Name: "x-a", Type: filterapi.QuotaHeaderMatchTypeExact, Value: "v"}},
+		{name: "exact inverted absent", match: filterapi.QuotaHeaderMatch{Name: "x-a", Type: filterapi.QuotaHeaderMatchTypeExact, Value: "v", Invert: true}, expOK: true},
+		{name: "exact inverted", match: filterapi.QuotaHeaderMatch{Name: "x-a", Type: filterapi.QuotaHeaderMatchTypeExact, Value: "v", Invert: true}, headers: map[string]string{"x-a": "v"}},
+		{name: "regex", match: filterapi.QuotaHeaderMatch{Name: "x-a", Type: filterapi.QuotaHeaderMatchTypeRegularExpression, Value: "^a+$"}, headers: map[string]string{"x-a": "aaa"}, expOK: true},
+		{name: "regex mismatch", match: filterapi.QuotaHeaderMatch{Name: "x-a", Type: filterapi.QuotaHeaderMatchTypeRegularExpression, Value: "^a+$"}, headers: map[string]string{"x-a": "b"}},
+		{name: "distinct", match: filterapi.QuotaHeaderMatch{Name: "x-a", Type: filterapi.QuotaHeaderMatchTypeDistinct}, headers: map[string]string{"x-a": "v"}, expDistinct: `/x-a="v"`, expOK: true},
+		{name: "distinct absent", match: filterapi.QuotaHeaderMatch{Name: "x-a", Type: filterapi.QuotaHeaderMatchTypeDistinct}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			distinct, ok := matchQuotaClientSelectors(q.Regexps, []filterapi.QuotaClientSelector{{Headers: []filterapi.QuotaHeaderMatch{tc.match}}}, tc.headers)
+			require.Equal(t, tc.expOK, ok)
+			require.Equal(t, tc.expDistinct, distinct)
+		})
+	}
+}
+
+func Test_quotaSelection_exceeded(t *testing.T) {
+	store := quota.NewMemoryStore()
+	require.NoError(t, store.Charge(t.Context(), "exhausted", time.Minute, 10))
+	require.NoError(t, store.Charge(t.Context(), "exhausted-shadow", time.Minute, 10))
+	exhausted := quotaBucket{name: "exhausted", key: "exhausted", limit: 5, window: time.Minute}
+	exhaustedShadow := quotaBucket{name: "exhausted-shadow", key: "exhausted-shadow", limit: 5, window: time.Minute, shadow: true}
+	available := quotaBucket{name: "available", key: "available", limit: 10, window: time.Minute}
+
+	for _, tc := range []struct {
+		name        string
+		sel         quotaSelection
+		expExceeded bool
+		expMetrics  map[string]bool
+	}{
+		{name: "exclusive all exhausted", sel: quotaSelection{exclusive: true, buckets: []quotaBucket{exhausted}}, expExceeded: true, expMetrics: map[string]bool{"exhausted": false}},
+		{name: "exclusive some available", sel: quotaSelection{exclusive: true, buckets: []quotaBucket{exhausted, available}}, expMetrics: map[string]bool{"exhausted": false}},
+		{name: "exclusive only shadow", sel: quotaSelection{exclusive: true, buckets: []quotaBucket{exhaustedShadow}}, expMetrics: map[string]bool{"exhausted-shadow": true}},
+		{name: "shared one exhausted", sel: quotaSelection{buckets: []quotaBucket{exhausted, available}}, expExceeded: true, expMetrics: map[string]bool{"exhausted": false}},
+		{name: "shared shadow exhausted", sel: quotaSelection{buckets: []quotaBucket{exhaustedShadow, available}}, expMetrics: map[string]bool{"exhausted-shadow": true}},
+		{name: "shared available", sel: quotaSelection{buckets: []quotaBucket{available}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mm := &mockMetrics{}
+			exceeded, err := tc.sel.exceeded(t.Context(), store, mm, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.expExceeded, exceeded)
+			require.Equal(t, tc.expMetrics, mm.quotaExceeded)
+		})
+	}
+
+	t.Run("store error", func(t *testing.T) {
+		sel := quotaSelection{buckets: []quotaBucket{available}}
+		_, err := sel.exceeded(t.Context(), errQuotaStore{}, &mockMetrics{}, nil)
+		require.ErrorContains(t, err, "failed to get the usage of quota bucket available: store unavailable")
+	})
+}
+
+// errQuotaStore is a [quota.Store] that always fails.
+type errQuotaStore struct{}
+
+func (errQuotaStore) Usage(context.Context, string, time.Duration) (uint64, error) {
+	return 0, errors.New("store unavailable")
+}
+
+func (errQuotaStore) Charge(context.Context, string, time.Duration, uint64) error {
+	return errors.New("store unavailable")
+}
+
+func (errQuotaStore) Claim(context.Context, string, time.Duration) (bool, error) {
+	return false, errors.New("store unavailable")
+}
+
+func (errQuotaStore) Close() error { return nil }
+
+func Test_chatCompletionProcessorUpstreamFilter_Quota(t *testing.T) {
+	q := newTestRuntimeBackendQuota(t, &filterapi.BackendQuota{
+		Name: "ns/policy/ns/backend",
+		ServiceQuota: &filterapi.ServiceQuota{
+			CostExpression: "input_tokens + output_tokens * 2u",
+			Quota:          filterapi.QuotaValue{Limit: 8, Duration: time.Hour},
+		},
+	})
+	store := quota.NewMemoryStore()
+	headers := map[string]string{":path": "/v1/chat/completions", internalapi.ModelNameHeaderKeyDefault: "some-model"}
+	var body openai.ChatCompletionRequest
+	raw := bodyFromModel(t, "some-model", false, nil)
+	require.NoError(t, json.Unmarshal(raw, &body))
+
+	newProcessor := func(mm *mockMetrics, mt *mockTranslator) *chatCompletionProcessorUpstreamFilter {
+		return &chatCompletionProcessorUpstreamFilter{
+			parent: &chatCompletionProcessorRouterFilter{
+				config:                 &filterapi.RuntimeConfig{QuotaStore: store},
+				logger:                 slog.Default(),
+				originalRequestBodyRaw: raw,
+				originalRequestBody:    &body,
+				originalModel:          "some-model",
+			},
+			requestHeaders:  headers,
+			responseHeaders: map[string]string{":status": "200"},
+			metrics:         mm,
+			translator:      mt,
+			logger:          slog.Default(),
+			backendName:     "backend",
+			quota:           q,
+		}
+	}
+
+	// The first request is allowed and charges 2 + 4 * 2 = 10 at the end of the response.
+	mm := &mockMetrics{}
+	mt := &mockTranslator{t: t, expRequestBody: &body}
+	mt.retUsedToken.SetInputTokens(2)
+	mt.retUsedToken.SetOutputTokens(4)
+	p := newProcessor(mm, mt)
+	resp, err := p.ProcessRequestHeaders(t.Context(), nil)
+	require.NoError(t, err)
+	require.IsType(t, &extprocv3.ProcessingResponse_RequestHeaders{}, resp.Response)
+	_, err = p.ProcessResponseBody(t.Context(), &extprocv3.HttpBody{EndOfStream: true})
+	require.NoError(t, err)
+	usage, err := store.Usage(t.Context(), "ns/policy/ns/backend/service", time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), usage)
+
+	// The second request is denied.
+	mm = &mockMetrics{}
+	p = newProcessor(mm, &mockTranslator{t: t, expRequestBody: &body})
+	resp, err = p.ProcessRequestHeaders(t.Context(), nil)
+	require.NoError(t, err)
+	immediateResp, ok := resp.Response.(*extprocv3.ProcessingResponse_ImmediateResponse)
+	require.True(t, ok)
+	require.Equal(t, typev3.StatusCode(429), immediateResp.ImmediateResponse.Status.Code)
+	require.JSONEq(t, `{"type":"error","error":{"type":"TooManyRequests","code":"429","message":"quota exceeded for model some-model"}}`,
+		string(immediateResp.ImmediateResponse.Body))
+	mm.RequireRequestFailure(t)
+	require.Equal(t, map[string]bool{"ns/policy/ns/backend/service": false}, mm.quotaExceeded)
+
+	// The request is allowed when the store is unavailable.
+	p = newProcessor(&mockMetrics{}, &mockTranslator{t: t, expRequestBody: &body})
+	p.parent.config = &filterapi.RuntimeConfig{QuotaStore: errQuotaStore{}}
+	resp, err = p.ProcessRequestHeaders(t.Context(), nil)
+	require.NoError(t, err)
+	require.IsType(t, &extprocv3.ProcessingResponse_RequestHeaders{}, resp.Response)
+}
+
+// mockUsageDeduplicator is a [mockTranslator] whose token usage is identified by a key.
+type mockUsageDeduplicator struct {
+	mockTranslator
+	usageKey string
+}
+
+// UsageKey implements [translator.UsageDeduplicator].
+func (m *mockUsageDeduplicator) UsageKey() string { return m.usageKey }
+
+func Test_chatCompletionProcessorUpstreamFilter_UsageDeduplicator(t *testing.T) {
+	store := quota.NewMemoryStore()
+	retrieve := func(store quota.Store) *mockMetrics {
+		mm := &mockMetrics{}
+		mt := &mockUsageDeduplicator{mockTranslator: mockTranslator{t: t}, usageKey: "batch_abc123"}
+		mt.retUsedToken.SetInputTokens(100)
+		mt.retUsedToken.SetOutputTokens(50)
+		p := &chatCompletionProcessorUpstreamFilter{
+			parent: &chatCompletionProcessorRouterFilter{
+				config: &filterapi.RuntimeConfig{QuotaStore: store},
+				logger: slog.Default(),
+			},
+			responseHeaders: map[string]string{":status": "200"},
+			metrics:         mm,
+			translator:      mt,
+			logger:          slog.Default(),
+		}
+		_, err := p.ProcessResponseBody(t.Context(), &extprocv3.HttpBody{EndOfStream: true})
+		require.NoError(t, err)
+		return mm
+	}
+
+	// Only the first retrieval of the completed batch records its usage.
+	mm := retrieve(store)
+	require.Equal(t, 100, mm.inputTokenCount)
+	require.Equal(t, 50, mm.outputTokenCount)
+	mm = retrieve(store)
+	require.Zero(t, mm.inputTokenCount)
+	require.Zero(t, mm.outputTokenCount)
+
+	// The usage is not recorded when the store is unavailable, since it may have been recorded already.
+	mm = retrieve(errQuotaStore{})
+	require.Zero(t, mm.inputTokenCount)
+}
+
+func Test_chatCompletionProcessorUpstreamFilter_Quota_requestVariables(t *testing.T) {
+	// The cost expression sees the same request variables as the LLM request costs.
+	q := newTestRuntimeBackendQuota(t, &filterapi.BackendQuota{
+		Name: "ns/policy/ns/backend",
+		ServiceQuota: &filterapi.ServiceQuota{
+			CostExpression: `"x-tier" in request_headers && request_headers["x-tier"] == "free" && !stream ? output_tokens * 2u : output_tokens`,
+			Quota:          filterapi.QuotaValue{Limit: 100, Duration: time.Hour},
+		},
+	})
+	store := quota.NewMemoryStore()
+	var body openai.ChatCompletionRequest
+	raw := bodyFromModel(t, "some-model", false, nil)
+	require.NoError(t, json.Unmarshal(raw, &body))
+	mt := &mockTranslator{t: t, expRequestBody: &body}
+	mt.retUsedToken.SetOutputTokens(4)
+	p := &chatCompletionProcessorUpstreamFilter{
+		parent: &chatCompletionProcessorRouterFilter{
+			config:                 &filterapi.RuntimeConfig{QuotaStore: store},
+			logger:                 slog.Default(),
+			originalRequestBodyRaw: raw,
+			originalRequestBody:    &body,
+			originalModel:          "some-model",
+		},
+		requestHeaders: map[string]string{
+			":path": "/v1/chat/completions", internalapi.ModelNameHeaderKeyDefault: "some-model", "x-tier": "free",
+		},
+		responseHeaders: map[string]string{":status": "200"},
+		metrics:         &mockMetrics{},
+		translator:      mt,
+		logger:          slog.Default(),
+		backendName:     "backend",
+		quota:           q,
+	}
+	_, err := p.ProcessRequestHeaders(t.Context(), nil)
+	require.NoError(t, err)
+	_, err = p.ProcessResponseBody(t.Context(), &extprocv3.HttpBody{EndOfStream: true})
+	require.NoError(t, err)
+	usage, err := store.Usage(t.Context(), "ns/policy/ns/backend/service", time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), usage)
+}
@@ -0,0 +1,147 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extproc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+
+	"github.com/envoyproxy/ai-gateway/internal/endpointspec"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/responsecache"
+)
+
+// responseCacheHeader is the response header set to the result of the lookup when the response is served from
+// the response cache.
+const responseCacheHeader = "x-ai-eg-response-cache"
+
+// responseCacheState is the state of the response cache for a request that missed the cache, used to save the
+// response of the backend at the end of the stream.
+type responseCacheState struct {
+	key       string
+	namespace string
+	// embedding is the embedding of the text of the request. Nil if the semantic lookup is not enabled.
+	embedding []float32
+	ttl       time.Duration
+}
+
+// lookupResponseCache returns the ImmediateResponse serving the cached response to the request, or nil if the
+// request has to be sent to the backend. In the latter case, the state to save the response is kept in the router.
+//
+// The failures of the cache are logged and treated as misses so that the cache never fails the request.
+func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) lookupResponseCache(ctx context.Context, logger *slog.Logger,
+	originalModel internalapi.OriginalModel, body *ReqT, requestBody []byte, stream bool,
+) *extprocv3.ProcessingResponse {
+	cacheSpec, ok := any(r.eh).(endpointspec.CacheSpec[ReqT])
+	store := r.config.ResponseCacheStore
+	if !ok || store == nil {
+		return nil
+	}
+	c := r.config.ResponseCaches[originalModel]
+	if c == nil {
+		return nil
+	}
+
+	namespace := responsecache.Namespace(c.RouteName, r.requestHeaders[c.OwnerHeaderKey], r.requestHeaders[":path"], originalModel)
+	key, err := responsecache.Key(namespace, requestBody)
+	if err != nil {
+		logger.Warn("failed to compute the response cache key", slog.String("error", err.Error()))
+		return nil
+	}
+	state := &responseCacheState{key: key, namespace: namespace, ttl: c.TTL}
+	r.responseCache = state
+
+	result := metrics.ResponseCacheExactHit
+	entry, err := store.Get(ctx, key)
+	if errors.Is(err, responsecache.ErrNotFound) && c.Semantic != nil {
+		if text := cacheSpec.CacheText(body); text != "" {
+			state.embedding, err = r.embedCacheText(ctx, c, text)
+			if err == nil {
+				result = metrics.ResponseCacheSemanticHit
+				entry, err = store.Nearest(ctx, namespace, state.embedding, c.Semantic.SimilarityThreshold)
+			}
+		}
+	}
+	if err != nil && !errors.Is(err, responsecache.ErrNotFound) {
+		logger.Warn("failed to look up the response cache", slog.String("error", err.Error()))
+	}
+
+	var resp *extprocv3.ProcessingResponse
+	if entry != nil {
+		if r.piiRedactor != nil {
+			entry = r.restoreCachedPII(entry)
+		}
+		resp = cachedResponse(cacheSpec, body, entry, stream, result)
+	}
+	if resp == nil {
+		result = metrics.ResponseCacheMiss
+	}
+	if r.metrics != nil {
+		r.metrics.SetOriginalModel(originalModel)
+		r.metrics.SetRequestModel(originalModel)
+		r.metrics.RecordResponseCacheLookup(ctx, result, r.requestHeaders)
+	}
+	return resp
+}
+
+// embedCacheText returns the embedding of the text of the request with the embedding backend of the semantic lookup.
+func (r *routerProcessor[ReqT, RespT, RespChunkT, EndpointSpecT]) embedCacheText(ctx context.Context, c *filterapi.RuntimeResponseCache, text string) ([]float32, error) {
+	client := &backendClient{url: c.Semantic.URL, backend: &c.Semantic.Backend, handler: c.SemanticHandler, client: backendHTTPClient}
+	return client.embed(ctx, c.Semantic.Model, text)
+}
+
+// cachedResponse returns the ImmediateResponse serving the cached entry, or nil if the entry cannot answer the
+// request. A cached streaming response only answers the streaming requests, while a cached non-streaming response
+// answers both with the stream synthesized from the response for the streaming requests.
+func cachedResponse[ReqT any](cacheSpec endpointspec.CacheSpec[ReqT], req *ReqT, entry *responsecache.Entry,
+	stream bool, result metrics.ResponseCacheResult,
+) *extprocv3.ProcessingResponse {
+	body, contentType := entry.Body, "application/json"
+	switch {
+	case entry.Stream && !stream:
+		return nil
+	case entry.Stream:
+		contentType = "text/event-stream"
+	case stream:
+		var err error
+		if body, err = cacheSpec.StreamFromCache(req, entry.Body); err != nil {
+			return nil
+		}
+		contentType = "text/event-stream"
+	}
+	headerMutation := &extprocv3.HeaderMutation{}
+	setHeader(headerMutation, "content-type", contentType)
+	setHeader(headerMutation, "content-length", strconv.Itoa(len(body)))
+	setHeader(headerMutation, responseCacheHeader, string(result))
+	return &extprocv3.ProcessingResponse{
+		Response: &extprocv3.ProcessingResponse_ImmediateResponse{
+			ImmediateResponse: &extprocv3.ImmediateResponse{
+				Status:  &typev3.HttpStatus{Code: typev3.StatusCode_OK},
+				Headers: headerMutation,
+				Body:    body,
+			},
+		},
+	}
+}
+
+// saveResponse saves the response of the backend in the response cache.
+func (s *responseCacheState) saveResponse(ctx context.Context, store responsecache.Store, body []byte, stream bool) error {
+	return store.Put(ctx, s.key, &responsecache.Entry{
+		Namespace: s.namespace,
+		Body:      body,
+		Stream:    stream,
+		Embedding: s.embedding,
+		ExpiresAt: time.Now().Add(s.ttl),
+	})
+}
@@ -0,0 +1,243 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extproc
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/endpointspec"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/responsecache"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// requireCachedResponse asserts that the response is served from the response cache with the result and body.
+func requireCachedResponse(t *testing.T, resp *extprocv3.ProcessingResponse, result metrics.ResponseCacheResult, contentType, body string) {
+	immediate, ok := resp.Response.(*extprocv3.ProcessingResponse_ImmediateResponse)
+	require.True(t, ok, "response should be served from the cache")
+	require.Equal(t, typev3.StatusCode_OK, immediate.ImmediateResponse.Status.Code)
+	require.Equal(t, body, string(immediate.ImmediateResponse.Body))
+	headers := map[string]string{}
+	for _, h := range immediate.ImmediateResponse.Headers.SetHeaders {
+		headers[h.Header.Key] = string(h.Header.RawValue)
+	}
+	require.Equal(t, contentType, headers["content-type"])
+	require.Equal(t, string(result), headers[responseCacheHeader])
+}
+
+func Test_routerProcessor_ResponseCache(t *testing.T) {
+	const (
+		request  = `{"model":"gpt-4o","messages":[{"role":"user","content":"Hi"}]}`
+		response = `{"id":"chatcmpl-1","object":"chat.completion","created":1700000000,"model":"gpt-4o","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"Hello!"}}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`
+	)
+	store := responsecache.NewMemoryStore()
+	config := &filterapi.RuntimeConfig{
+		ResponseCaches: map[string]*filterapi.RuntimeResponseCache{
+			"gpt-4o": {
+				ResponseCache:  &filterapi.ResponseCache{RouteName: "ns/route", Models: []string{"gpt-4o"}, TTL: time.Hour},
+				OwnerHeaderKey: filterapi.DefaultResponseCacheOwnerHeader,
+			},
+		},
+		ResponseCacheStore: store,
+	}
+	newRouter := func(mm *mockMetrics) *chatCompletionProcessorRouterFilter {
+		return &chatCompletionProcessorRouterFilter{
+			config:         config,
+			requestHeaders: map[string]string{":path": "/v1/chat/completions", "authorization": "Bearer a"},
+			logger:         slog.Default(),
+			tracer:         tracingapi.NoopTracer[openai.ChatCompletionRequest, openai.ChatCompletionResponse, openai.ChatCompletionResponseChunk]{},
+			metrics:        mm,
+		}
+	}
+
+	// The first request misses the cache and the response of the backend is saved.
+	mm := &mockMetrics{}
+	r := newRouter(mm)
+	resp, err := r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{Body: []byte(request)})
+	require.NoError(t, err)
+	_, ok := resp.Response.(*extprocv3.ProcessingResponse_RequestBody)
+	require.True(t, ok)
+	require.Equal(t, []metrics.ResponseCacheResult{metrics.ResponseCacheMiss}, mm.responseCacheLookups)
+	require.NotNil(t, r.responseCache)
+
+	u := &chatCompletionProcessorUpstreamFilter{requestHeaders: r.requestHeaders, metrics: &mockMetrics{}, logger: slog.Default()}
+	require.NoError(t, u.SetBackend(t.Context(), &filterapi.RuntimeBackend{Backend: &filterapi.Backend{
+		Name: "openai", Schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI},
+	}}, "ns/route", r))
+	require.NotNil(t, u.responseCacheBody)
+	_, err = u.ProcessRequestHeaders(t.Context(), nil)
+	require.NoError(t, err)
+	_, err = u.ProcessResponseHeaders(t.Context(), &corev3.HeaderMap{Headers: []*corev3.HeaderValue{{Key: ":status", Value: "200"}}})
+	require.NoError(t, err)
+	_, err = u.ProcessResponseBody(t.Context(), &extprocv3.HttpBody{Body: []byte(response[:10])})
+	require.NoError(t, err)
+	_, err = u.ProcessResponseBody(t.Context(), &extprocv3.HttpBody{Body: []byte(response[10:]), EndOfStream: true})
+	require.NoError(t, err)
+
+	t.Run("exact hit", func(t *testing.T) {
+		mm := &mockMetrics{}
+		resp, err := newRouter(mm).ProcessRequestBody(t.Context(), &extprocv3.HttpBody{
+			Body: []byte(`{"messages":[{"content":"Hi","role":"user"}],"model":"gpt-4o"}`),
+		})
+		require.NoError(t, err)
+		requireCachedResponse(t, resp, metrics.ResponseCacheExactHit, "application/json", response)
+		require.Equal(t, []metrics.ResponseCacheResult{metrics.ResponseCacheExactHit}, mm.responseCacheLookups)
+	})
+
+	t.Run("streaming request", func(t *testing.T) {
+		resp, err := newRouter(&mockMetrics{}).ProcessRequestBody(t.Context(), &extprocv3.HttpBody{
+			Body: []byte(`{"model":"gpt-4o","messages":[{"role":"user","content":"Hi"}],"stream":true}`),
+		})
+		require.NoError(t, err)
+		immediate, ok := resp.Response.(*extprocv3.ProcessingResponse_ImmediateResponse)
+		require.True(t, ok)
+		require.True(t, strings.HasSuffix(string(immediate.ImmediateResponse.Body), "data: [DONE]\n\n"))
+		require.Contains(t, string(immediate.ImmediateResponse.Body), `"content":"Hello!"`)
+	})
+
+	t.Run("other request", func(t *testing.T) {
+		mm := &mockMetrics{}
+		resp, err := newRouter(mm).ProcessRequestBody(t.Context(), &extprocv3.HttpBody{
+			Body: []byte(`{"model":"gpt-4o","messages":[{"role":"user","content":"Hello"}]}`),
+		})
+		require.NoError(t, err)
+		_, ok := resp.Response.(*extprocv3.ProcessingResponse_RequestBody)
+		require.True(t, ok)
+		require.Equal(t, []metrics.ResponseCacheResult{metrics.ResponseCacheMiss}, mm.responseCacheLookups)
+	})
+
+	t.Run("other owner", func(t *testing.T) {
+		mm := &mockMetrics{}
+		r := newRouter(mm)
+		r.requestHeaders["authorization"] = "Bearer b"
+		resp, err := r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{Body: []byte(request)})
+		require.NoError(t, err)
+		_, ok := resp.Response.(*extprocv3.ProcessingResponse_RequestBody)
+		require.True(t, ok)
+		require.Equal(t, []metrics.ResponseCacheResult{metrics.ResponseCacheMiss}, mm.responseCacheLookups)
+	})
+
+	t.Run("model without cache", func(t *testing.T) {
+		mm := &mockMetrics{}
+		r := newRouter(mm)
+		resp, err := r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{
+			Body: []byte(`{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Hi"}]}`),
+		})
+		require.NoError(t, err)
+		_, ok := resp.Response.(*extprocv3.ProcessingResponse_RequestBody)
+		require.True(t, ok)
+		require.Nil(t, r.responseCache)
+		require.Empty(t, mm.responseCacheLookups)
+	})
+}
+
+func Test_routerProcessor_ResponseCache_semantic(t *testing.T) {
+	var embedCalls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		embedCalls++
+		require.Equal(t, "/v1/embeddings", r.URL.Path)
+		w.Header().Set("content-type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list","model":"text-embedding-3-small","data":[{"object":"embedding","index":0,"embedding":[1,0.1]}],"usage":{"prompt_tokens":1,"total_tokens":1}}`))
+	}))
+	defer srv.Close()
+
+	const path = "/v1/chat/completions"
+	store := responsecache.NewMemoryStore()
+	require.NoError(t, store.Put(t.Context(), "other", &responsecache.Entry{
+		Namespace: responsecache.Namespace("ns/route", "", path, "gpt-4o"),
+		Body:      []byte(`{"id":"chatcmpl-1","object":"chat.completion","choices":[]}`),
+		Embedding: []float32{1, 0},
+		ExpiresAt: time.Now().Add(time.Hour),
+	}))
+	config := &filterapi.RuntimeConfig{
+		ResponseCaches: map[string]*filterapi.RuntimeResponseCache{
+			"gpt-4o": {ResponseCache: &filterapi.ResponseCache{
+				RouteName: "ns/route", Models: []string{"gpt-4o"}, TTL: time.Hour,
+				Semantic: &filterapi.SemanticResponseCache{
+					Backend:             filterapi.Backend{Name: "embedder", Schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI}},
+					URL:                 srv.URL,
+					Model:               "text-embedding-3-small",
+					SimilarityThreshold: 0.95,
+				},
+			}},
+		},
+		ResponseCacheStore: store,
+	}
+	newRouter := func(mm *mockMetrics) *chatCompletionProcessorRouterFilter {
+		return &chatCompletionProcessorRouterFilter{
+			config:         config,
+			requestHeaders: map[string]string{":path": path},
+			logger:         slog.Default(),
+			tracer:         tracingapi.NoopTracer[openai.ChatCompletionRequest, openai.ChatCompletionResponse, openai.ChatCompletionResponseChunk]{},
+			metrics:        mm,
+		}
+	}
+
+	t.Run("hit", func(t *testing.T) {
+		mm := &mockMetrics{}
+		resp, err := newRouter(mm).ProcessRequestBody(t.Context(), &extprocv3.HttpBody{
+			Body: []byte(`{"model":"gpt-4o","messages":[{"role":"user","content":"Hi there"}]}`),
+		})
+		require.NoError(t, err)
+		requireCachedResponse(t, resp, metrics.ResponseCacheSemanticHit, "application/json",
+			`{"id":"chatcmpl-1","object":"chat.completion","choices":[]}`)
+		require.Equal(t, 1, embedCalls)
+	})
+
+	t.Run("tools are not matched", func(t *testing.T) {
+		embedCalls = 0
+		mm := &mockMetrics{}
+		r := newRouter(mm)
+		resp, err := r.ProcessRequestBody(t.Context(), &extprocv3.HttpBody{
+			Body: []byte(`{"model":"gpt-4o","messages":[{"role":"user","content":"Hi there"}],"tools":[{"type":"function","function":{"name":"f"}}]}`),
+		})
+		require.NoError(t, err)
+		_, ok := resp.Response.(*extprocv3.ProcessingResponse_RequestBody)
+		require.True(t, ok)
+		require.Zero(t, embedCalls)
+		require.Nil(t, r.responseCache.embedding)
+		require.Equal(t, []metrics.ResponseCacheResult{metrics.ResponseCacheMiss}, mm.responseCacheLookups)
+	})
+
+	t.Run("embedding failure", func(t *testing.T) {
+		config.ResponseCaches["gpt-4o"].Semantic.URL = "http://127.0.0.1:0"
+		defer func() { config.ResponseCaches["gpt-4o"].Semantic.URL = srv.URL }()
+		mm := &mockMetrics{}
+		resp, err := newRouter(mm).ProcessRequestBody(t.Context(), &extprocv3.HttpBody{
+			Body: []byte(`{"model":"gpt-4o","messages":[{"role":"user","content":"Hi there"}]}`),
+		})
+		require.NoError(t, err)
+		_, ok := resp.Response.(*extprocv3.ProcessingResponse_RequestBody)
+		require.True(t, ok)
+		require.Equal(t, []metrics.ResponseCacheResult{metrics.ResponseCacheMiss}, mm.responseCacheLookups)
+	})
+}
+
+func Test_cachedResponse(t *testing.T) {
+	spec := endpointspec.ChatCompletionsEndpointSpec{}
+	req := &openai.ChatCompletionRequest{}
+
+	// A cached streaming response does not answer a non-streaming request.
+	require.Nil(t, cachedResponse(spec, req, &responsecache.Entry{Body: []byte("data: [DONE]\n\n"), Stream: true}, false, metrics.ResponseCacheExactHit))
+
+	resp := cachedResponse(spec, req, &responsecache.Entry{Body: []byte("data: [DONE]\n\n"), Stream: true}, true, metrics.ResponseCacheExactHit)
+	requireCachedResponse(t, resp, metrics.ResponseCacheExactHit, "text/event-stream", "data: [DONE]\n\n")
+
+	// An invalid cached response is treated as a miss for the streaming requests.
+	require.Nil(t, cachedResponse(spec, req, &responsecache.Entry{Body: []byte("{")}, true, metrics.ResponseCacheExactHit))
+}
@@ -0,0 +1,123 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extproc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/grpc/codes"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/responsestore"
+)
+
+// storedResponsesProcessor implements [Processor] for the `GET /v1/responses/{id}` and `DELETE /v1/responses/{id}`
+// endpoints. This processor returns an immediate response from the response store of the filter configuration.
+//
+// The responses of the backends that store the responses themselves, e.g. OpenAI, are not kept in the response store,
+// so the request is passed through to the backend when the response is not in the store. Otherwise, the rest of
+// the methods of the Processor are never called since it returns an immediate response after processing the headers.
+type storedResponsesProcessor struct {
+	passThroughProcessor
+	logger *slog.Logger
+	store  responsestore.Store
+	method string
+	id     string
+	// owner is the owner of the request as returned by [responsestore.Owner]. Only the responses of the same owner
+	// are visible.
+	owner string
+}
+
+var _ Processor = (*storedResponsesProcessor)(nil)
+
+// NewStoredResponsesProcessor creates a new processor that serves the responses kept in the response store.
+//
+// The request is passed through when the response store is not configured, or the request is not
+// the retrieval or deletion of a response, so that it can still be routed to the backend.
+func NewStoredResponsesProcessor(config *filterapi.RuntimeConfig, requestHeaders map[string]string, logger *slog.Logger, isUpstreamFilter bool, _ bool) (Processor, error) {
+	if isUpstreamFilter || config.ResponseStore == nil {
+		return passThroughProcessor{}, nil
+	}
+	method := requestHeaders[":method"]
+	if method != http.MethodGet && method != http.MethodDelete {
+		return passThroughProcessor{}, nil
+	}
+	path := requestHeaders[":path"]
+	if queryIndex := strings.Index(path, "?"); queryIndex != -1 {
+		path = path[:queryIndex]
+	}
+	_, id, _ := strings.Cut(path, "/responses/")
+	if id == "" || strings.Contains(id, "/") {
+		// Sub-resources such as input_items are not served by the gateway.
+		return passThroughProcessor{}, nil
+	}
+	owner := responsestore.Owner(requestHeaders[config.ResponseStoreOwnerHeader])
+	return &storedResponsesProcessor{logger: logger, store: config.ResponseStore, method: method, id: id, owner: owner}, nil
+}
+
+// ProcessRequestHeaders implements [Processor.ProcessRequestHeaders].
+func (s *storedResponsesProcessor) ProcessRequestHeaders(ctx context.Context, _ *corev3.HeaderMap) (*extprocv3.ProcessingResponse, error) {
+	var body []byte
+	var err error
+	if s.method == http.MethodDelete {
+		err = responsestore.DeleteOwned(ctx, s.store, s.id, s.owner)
+		if err == nil {
+			body, err = json.Marshal(openai.ResponseDeleted{ID: s.id, Object: "response", Deleted: true})
+		}
+	} else {
+		var resp *responsestore.Response
+		if resp, err = responsestore.GetOwned(ctx, s.store, s.id, s.owner); err == nil {
+			body = resp.Body
+		}
+	}
+	switch {
+	case errors.Is(err, responsestore.ErrNotFound):
+		// The response may have been created on a backend that stores the responses itself.
+		s.logger.Debug("response not found in the response store, passing through", slog.String("id", s.id))
+		return s.passThroughProcessor.ProcessRequestHeaders(ctx, nil)
+	case err != nil:
+		s.logger.Error("failed to access the response store", slog.String("id", s.id), slog.String("error", err.Error()))
+		return storedResponsesErrorResponse(http.StatusInternalServerError, "server_error", "failed to access the response store")
+	}
+	return immediateJSONResponse(http.StatusOK, body), nil
+}
+
+// storedResponsesErrorResponse returns an immediate response with the error in the OpenAI format.
+func storedResponsesErrorResponse(status int, errorType, message string) (*extprocv3.ProcessingResponse, error) {
+	body, err := json.Marshal(openai.Error{Type: "error", Error: openai.ErrorType{Type: errorType, Message: message}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	return immediateJSONResponse(status, body), nil
+}
+
+// immediateJSONResponse returns an immediate response with the given status and JSON body.
+func immediateJSONResponse(status int, body []byte) *extprocv3.ProcessingResponse {
+	headerMutation := &extprocv3.HeaderMutation{}
+	setHeader(headerMutation, "content-length", strconv.Itoa(len(body)))
+	setHeader(headerMutation, "content-type", "application/json")
+	return &extprocv3.ProcessingResponse{
+		Response: &extprocv3.ProcessingResponse_ImmediateResponse{
+			ImmediateResponse: &extprocv3.ImmediateResponse{
+				Status:     &typev3.HttpStatus{Code: typev3.StatusCode(status)}, // #nosec G115 - HTTP status codes are always in valid int32 range
+				Headers:    headerMutation,
+				Body:       body,
+				GrpcStatus: &extprocv3.GrpcStatus{Status: uint32(codes.OK)},
+			},
+		},
+	}
+}
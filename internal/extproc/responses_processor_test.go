@@ -0,0 +1,87 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package extproc
+
+import (
+	"log/slog"
+	"testing"
+
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/responsestore"
+)
+
+func TestNewStoredResponsesProcessor_PassThrough(t *testing.T) {
+	cfg := &filterapi.RuntimeConfig{ResponseStore: responsestore.NewMemoryStore()}
+	for _, tc := range []struct {
+		name     string
+		cfg      *filterapi.RuntimeConfig
+		headers  map[string]string
+		upstream bool
+	}{
+		{name: "upstream filter", cfg: cfg, headers: map[string]string{":method": "GET", ":path": "/v1/responses/resp_1"}, upstream: true},
+		{name: "no store", cfg: &filterapi.RuntimeConfig{}, headers: map[string]string{":method": "GET", ":path": "/v1/responses/resp_1"}},
+		{name: "post", cfg: cfg, headers: map[string]string{":method": "POST", ":path": "/v1/responses/resp_1/cancel"}},
+		{name: "sub-resource", cfg: cfg, headers: map[string]string{":method": "GET", ":path": "/v1/responses/resp_1/input_items"}},
+		{name: "no id", cfg: cfg, headers: map[string]string{":method": "GET", ":path": "/v1/responses/"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewStoredResponsesProcessor(tc.cfg, tc.headers, slog.Default(), tc.upstream, false)
+			require.NoError(t, err)
+			require.IsType(t, passThroughProcessor{}, p)
+		})
+	}
+}
+
+func TestStoredResponsesProcessor_ProcessRequestHeaders(t *testing.T) {
+	store := responsestore.NewMemoryStore()
+	cfg := &filterapi.RuntimeConfig{ResponseStore: store, ResponseStoreOwnerHeader: "authorization"}
+	require.NoError(t, store.Put(t.Context(), &responsestore.Response{
+		ID: "resp_1", Owner: responsestore.Owner("Bearer alice"), Input: []byte(`[]`),
+		Body: []byte(`{"id":"resp_1","object":"response","status":"completed"}`),
+	}))
+
+	doAs := func(authorization, method, path string) *extprocv3.ProcessingResponse {
+		requestHeaders := map[string]string{":method": method, ":path": path, "authorization": authorization}
+		p, err := NewStoredResponsesProcessor(cfg, requestHeaders, slog.Default(), false, false)
+		require.NoError(t, err)
+		res, err := p.ProcessRequestHeaders(t.Context(), nil)
+		require.NoError(t, err)
+		return res
+	}
+	do := func(method, path string) *extprocv3.ImmediateResponse {
+		ir, ok := doAs("Bearer alice", method, path).Response.(*extprocv3.ProcessingResponse_ImmediateResponse)
+		require.True(t, ok)
+		require.Equal(t, "application/json", headers(ir.ImmediateResponse.Headers.SetHeaders)["content-type"])
+		return ir.ImmediateResponse
+	}
+	// The requests on the responses that are not in the store are passed through to the backend, which may
+	// store the responses itself, e.g. OpenAI.
+	requirePassThrough := func(authorization, method, path string) {
+		_, ok := doAs(authorization, method, path).Response.(*extprocv3.ProcessingResponse_RequestHeaders)
+		require.True(t, ok)
+	}
+
+	// The responses of the other callers are neither visible nor deletable.
+	for _, method := range []string{"GET", "DELETE"} {
+		requirePassThrough("Bearer bob", method, "/v1/responses/resp_1")
+	}
+
+	resp := do("GET", "/v1/responses/resp_1?include=reasoning.encrypted_content")
+	require.Equal(t, typev3.StatusCode_OK, resp.Status.Code)
+	require.JSONEq(t, `{"id":"resp_1","object":"response","status":"completed"}`, string(resp.Body))
+
+	resp = do("DELETE", "/v1/responses/resp_1")
+	require.Equal(t, typev3.StatusCode_OK, resp.Status.Code)
+	require.JSONEq(t, `{"id":"resp_1","object":"response","deleted":true}`, string(resp.Body))
+
+	for _, method := range []string{"GET", "DELETE"} {
+		requirePassThrough("Bearer alice", method, "/v1/responses/resp_1")
+	}
+}
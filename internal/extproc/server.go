@@ -28,7 +28,10 @@ import (
 	"github.com/envoyproxy/ai-gateway/internal/backendauth"
 	"github.com/envoyproxy/ai-gateway/internal/filterapi"
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/quota"
 	"github.com/envoyproxy/ai-gateway/internal/redaction"
+	"github.com/envoyproxy/ai-gateway/internal/responsecache"
+	"github.com/envoyproxy/ai-gateway/internal/responsestore"
 )
 
 var (
@@ -58,9 +61,21 @@ type Server struct {
 	enableRedaction               bool
 	config                        *filterapi.RuntimeConfig
 	processorFactories            map[string]ProcessorFactory
+	prefixProcessorFactories      map[string]ProcessorFactory
 	routerProcessorsPerReqID      map[string]Processor
 	routerProcessorsPerReqIDMutex sync.RWMutex
 	uuidFn                        func() string
+	// quotaStore is shared across the configuration updates so that the quota counters are preserved.
+	// This is recreated only when quotaStoreConfig changes.
+	quotaStore       quota.Store
+	quotaStoreConfig filterapi.QuotaStore
+	// responseStore is shared across the configuration updates in the same way as quotaStore.
+	// This is nil when the response store is not configured.
+	responseStore       responsestore.Store
+	responseStoreConfig filterapi.ResponseStore
+	// responseCacheStore is shared across the configuration updates so that the cached responses are preserved.
+	// This is nil when no route has the response cache.
+	responseCacheStore responsecache.Store
 }
 
 // NewServer creates a new external processor server.
@@ -71,6 +86,7 @@ func NewServer(logger *slog.Logger, enableRedaction bool) (*Server, error) {
 		debugLogEnabled:          debugLogEnabled,
 		enableRedaction:          enableRedaction,
 		processorFactories:       make(map[string]ProcessorFactory),
+		prefixProcessorFactories: make(map[string]ProcessorFactory),
 		routerProcessorsPerReqID: make(map[string]Processor),
 		uuidFn:                   uuid.NewString,
 	}
@@ -83,20 +99,120 @@ func (s *Server) LoadConfig(ctx context.Context, config *filterapi.Config) error
 	if err != nil {
 		return fmt.Errorf("cannot create runtime filter config: %w", err)
 	}
+	if err = s.maybeUpdateQuotaStore(config.QuotaStore); err != nil {
+		return fmt.Errorf("cannot create quota store: %w", err)
+	}
+	newConfig.QuotaStore = s.quotaStore
+	if err = s.maybeUpdateResponseStore(config.ResponseStore); err != nil {
+		return fmt.Errorf("cannot create response store: %w", err)
+	}
+	newConfig.ResponseStore = s.responseStore
+	s.maybeUpdateResponseCacheStore(len(config.ResponseCaches) > 0)
+	newConfig.ResponseCacheStore = s.responseCacheStore
 	s.config = newConfig // This is racey, but we don't care.
 	return nil
 }
 
+// maybeUpdateQuotaStore creates the quota store if it has not been created yet or the configuration has changed.
+func (s *Server) maybeUpdateQuotaStore(config *filterapi.QuotaStore) error {
+	var storeConfig filterapi.QuotaStore
+	if config != nil {
+		storeConfig = *config
+	}
+	if storeConfig.Type == "" {
+		storeConfig.Type = filterapi.QuotaStoreTypeMemory
+	}
+	if s.quotaStore != nil && storeConfig == s.quotaStoreConfig {
+		return nil
+	}
+
+	var store quota.Store
+	switch storeConfig.Type {
+	case filterapi.QuotaStoreTypeMemory:
+		store = quota.NewMemoryStore()
+	case filterapi.QuotaStoreTypeRedis:
+		if storeConfig.RedisURL == "" {
+			return errors.New("redis URL must be specified for the Redis quota store")
+		}
+		var err error
+		if store, err = quota.NewRedisStore(storeConfig.RedisURL, storeConfig.RedisPassword, storeConfig.RedisCluster); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown quota store type %q", storeConfig.Type)
+	}
+	if s.quotaStore != nil {
+		// The in-flight requests may still use the previous store. Their quota checks fail open and their charges are
+		// dropped with a warning, which is acceptable since the store is only recreated when its configuration changes.
+		_ = s.quotaStore.Close()
+	}
+	s.quotaStore, s.quotaStoreConfig = store, storeConfig
+	return nil
+}
+
+// maybeUpdateResponseStore creates the response store if it has not been created yet or the configuration has changed.
+func (s *Server) maybeUpdateResponseStore(config *filterapi.ResponseStore) error {
+	var storeConfig filterapi.ResponseStore
+	if config != nil {
+		storeConfig = *config
+	}
+	if storeConfig == s.responseStoreConfig {
+		return nil
+	}
+
+	var store responsestore.Store
+	switch storeConfig.Type {
+	case "":
+		// The response store is disabled.
+	case filterapi.ResponseStoreTypeMemory:
+		store = responsestore.NewMemoryStore()
+	case filterapi.ResponseStoreTypeFile:
+		if storeConfig.Path == "" {
+			return errors.New("path must be specified for the File response store")
+		}
+		var err error
+		if store, err = responsestore.NewFileStore(storeConfig.Path); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown response store type %q", storeConfig.Type)
+	}
+	if s.responseStore != nil {
+		_ = s.responseStore.Close()
+	}
+	s.responseStore, s.responseStoreConfig = store, storeConfig
+	return nil
+}
+
+// maybeUpdateResponseCacheStore creates the response cache store when a route enables the response cache, and
+// drops it when none does.
+func (s *Server) maybeUpdateResponseCacheStore(enabled bool) {
+	switch {
+	case enabled && s.responseCacheStore == nil:
+		s.responseCacheStore = responsecache.NewMemoryStore()
+	case !enabled && s.responseCacheStore != nil:
+		_ = s.responseCacheStore.Close()
+		s.responseCacheStore = nil
+	}
+}
+
 // Register a new processor for the given request path.
 func (s *Server) Register(path string, newProcessor ProcessorFactory) {
 	s.logger.Info("Registering processor", slog.String("path", path))
 	s.processorFactories[path] = newProcessor
 }
 
+// RegisterPrefix registers a new processor for the request paths starting with the given prefix.
+// The processors registered with Register take precedence, and the longest prefix wins among the prefixes.
+func (s *Server) RegisterPrefix(prefix string, newProcessor ProcessorFactory) {
+	s.logger.Info("Registering processor", slog.String("prefix", prefix))
+	s.prefixProcessorFactories[prefix] = newProcessor
+}
+
 var errNoProcessor = errors.New("no processor registered for the given path")
 
 // processorForPath returns the processor for the given path.
-// Only exact path matching is supported currently.
+// The exact path match is tried first, and then the longest registered prefix.
 func (s *Server) processorForPath(requestHeaders map[string]string, isUpstreamFilter bool, logger *slog.Logger) (Processor, error) {
 	pathHeader := ":path"
 	if isUpstreamFilter {
@@ -110,6 +226,14 @@ func (s *Server) processorForPath(requestHeaders map[string]string, isUpstreamFi
 	}
 
 	newProcessor, ok := s.processorFactories[path]
+	if !ok {
+		var matched string
+		for prefix, f := range s.prefixProcessorFactories {
+			if strings.HasPrefix(path, prefix) && len(prefix) > len(matched) {
+				matched, newProcessor, ok = prefix, f, true
+			}
+		}
+	}
 	if !ok {
 		return nil, fmt.Errorf("%w: %s", errNoProcessor, path)
 	}
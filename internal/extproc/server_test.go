@@ -52,6 +52,105 @@ func TestServer_LoadConfig(t *testing.T) {
 	require.NotNil(t, s.config)
 }
 
+func TestServer_LoadConfig_QuotaStore(t *testing.T) {
+	s := &Server{}
+	require.NoError(t, s.LoadConfig(t.Context(), &filterapi.Config{}))
+	memory := s.quotaStore
+	require.NotNil(t, memory)
+	require.Equal(t, memory, s.config.QuotaStore)
+
+	// The store is preserved across the configuration updates.
+	require.NoError(t, s.LoadConfig(t.Context(), &filterapi.Config{
+		QuotaStore: &filterapi.QuotaStore{Type: filterapi.QuotaStoreTypeMemory},
+	}))
+	require.Same(t, memory, s.quotaStore)
+
+	// The store is recreated when the configuration changes.
+	require.NoError(t, s.LoadConfig(t.Context(), &filterapi.Config{
+		QuotaStore: &filterapi.QuotaStore{Type: filterapi.QuotaStoreTypeRedis, RedisURL: "redis://localhost:6379"},
+	}))
+	require.NotSame(t, memory, s.quotaStore)
+	require.Equal(t, s.quotaStore, s.config.QuotaStore)
+
+	err := s.LoadConfig(t.Context(), &filterapi.Config{
+		QuotaStore: &filterapi.QuotaStore{Type: filterapi.QuotaStoreTypeRedis},
+	})
+	require.ErrorContains(t, err, "cannot create quota store: redis URL must be specified for the Redis quota store")
+	err = s.LoadConfig(t.Context(), &filterapi.Config{
+		QuotaStore: &filterapi.QuotaStore{Type: filterapi.QuotaStoreTypeRedis, RedisURL: "localhost:6379"},
+	})
+	require.ErrorContains(t, err, "cannot create quota store: invalid Redis URL")
+	err = s.LoadConfig(t.Context(), &filterapi.Config{
+		QuotaStore: &filterapi.QuotaStore{Type: "Unknown"},
+	})
+	require.ErrorContains(t, err, `cannot create quota store: unknown quota store type "Unknown"`)
+}
+
+func TestServer_LoadConfig_ResponseCacheStore(t *testing.T) {
+	s := &Server{}
+	require.NoError(t, s.LoadConfig(t.Context(), &filterapi.Config{}))
+	require.Nil(t, s.responseCacheStore)
+	require.Nil(t, s.config.ResponseCacheStore)
+
+	config := &filterapi.Config{ResponseCaches: []filterapi.ResponseCache{
+		{RouteName: "ns/route", Models: []string{"gpt-4o"}, TTL: time.Hour},
+	}}
+	require.NoError(t, s.LoadConfig(t.Context(), config))
+	memory := s.responseCacheStore
+	require.NotNil(t, memory)
+	require.Equal(t, memory, s.config.ResponseCacheStore)
+
+	// The store is preserved across the configuration updates.
+	require.NoError(t, s.LoadConfig(t.Context(), config))
+	require.Same(t, memory, s.responseCacheStore)
+
+	// The store is dropped when no route has the response cache.
+	require.NoError(t, s.LoadConfig(t.Context(), &filterapi.Config{}))
+	require.Nil(t, s.responseCacheStore)
+	require.Nil(t, s.config.ResponseCacheStore)
+}
+
+func TestServer_LoadConfig_ResponseStore(t *testing.T) {
+	s := &Server{}
+	require.NoError(t, s.LoadConfig(t.Context(), &filterapi.Config{}))
+	require.Nil(t, s.responseStore)
+	require.Nil(t, s.config.ResponseStore)
+
+	require.NoError(t, s.LoadConfig(t.Context(), &filterapi.Config{
+		ResponseStore: &filterapi.ResponseStore{Type: filterapi.ResponseStoreTypeMemory},
+	}))
+	memory := s.responseStore
+	require.NotNil(t, memory)
+	require.Equal(t, memory, s.config.ResponseStore)
+
+	// The store is preserved across the configuration updates.
+	require.NoError(t, s.LoadConfig(t.Context(), &filterapi.Config{
+		ResponseStore: &filterapi.ResponseStore{Type: filterapi.ResponseStoreTypeMemory},
+	}))
+	require.Same(t, memory, s.responseStore)
+
+	// The store is recreated when the configuration changes.
+	require.NoError(t, s.LoadConfig(t.Context(), &filterapi.Config{
+		ResponseStore: &filterapi.ResponseStore{Type: filterapi.ResponseStoreTypeFile, Path: t.TempDir()},
+	}))
+	require.NotSame(t, memory, s.responseStore)
+	require.Equal(t, s.responseStore, s.config.ResponseStore)
+
+	// The store is dropped when the configuration is removed.
+	require.NoError(t, s.LoadConfig(t.Context(), &filterapi.Config{}))
+	require.Nil(t, s.responseStore)
+	require.Nil(t, s.config.ResponseStore)
+
+	err := s.LoadConfig(t.Context(), &filterapi.Config{
+		ResponseStore: &filterapi.ResponseStore{Type: filterapi.ResponseStoreTypeFile},
+	})
+	require.ErrorContains(t, err, "cannot create response store: path must be specified for the File response store")
+	err = s.LoadConfig(t.Context(), &filterapi.Config{
+		ResponseStore: &filterapi.ResponseStore{Type: "Unknown"},
+	})
+	require.ErrorContains(t, err, `cannot create response store: unknown response store type "Unknown"`)
+}
+
 func TestServer_Check(t *testing.T) {
 	s, _ := requireNewServerWithMockProcessor(t)
 
@@ -847,6 +946,43 @@ func Test_headersToMap(t *testing.T) {
 	require.Equal(t, map[string]string{"foo": "bar", "dog": "cat"}, m)
 }
 
+func TestServer_ProcessorForPath_Prefix(t *testing.T) {
+	s, err := NewServer(slog.Default(), false)
+	require.NoError(t, err)
+	s.config = &filterapi.RuntimeConfig{}
+
+	exact, prefix, longerPrefix := &mockProcessor{}, &mockProcessor{}, &mockProcessor{}
+	s.Register("/v1/responses", func(*filterapi.RuntimeConfig, map[string]string, *slog.Logger, bool, bool) (Processor, error) {
+		return exact, nil
+	})
+	s.RegisterPrefix("/v1/responses/", func(*filterapi.RuntimeConfig, map[string]string, *slog.Logger, bool, bool) (Processor, error) {
+		return prefix, nil
+	})
+	s.RegisterPrefix("/v1/responses/special/", func(*filterapi.RuntimeConfig, map[string]string, *slog.Logger, bool, bool) (Processor, error) {
+		return longerPrefix, nil
+	})
+
+	for _, tc := range []struct {
+		path string
+		exp  Processor
+	}{
+		{path: "/v1/responses", exp: exact},
+		{path: "/v1/responses?foo=bar", exp: exact},
+		{path: "/v1/responses/resp_1", exp: prefix},
+		{path: "/v1/responses/resp_1?include=foo", exp: prefix},
+		{path: "/v1/responses/special/resp_1", exp: longerPrefix},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			p, err := s.processorForPath(map[string]string{":path": tc.path}, false, slog.Default())
+			require.NoError(t, err)
+			require.Same(t, tc.exp, p)
+		})
+	}
+
+	_, err = s.processorForPath(map[string]string{":path": "/v1/responsesfoo"}, false, slog.Default())
+	require.ErrorIs(t, err, errNoProcessor)
+}
+
 func TestServer_ProcessorForPath_QueryParameterStripping(t *testing.T) {
 	s, err := NewServer(slog.Default(), false)
 	require.NoError(t, err)
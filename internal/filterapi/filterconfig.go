@@ -37,14 +37,203 @@ type Config struct {
 	// LLMRequestCost configures the cost of each LLM-related request. Optional. If this is provided, the filter will populate
 	// the "calculated" cost in the filter metadata at the end of the response body processing.
 	LLMRequestCosts []LLMRequestCost `json:"llmRequestCosts,omitempty"`
+	// ModelPrices is the price table looked up by the price(model, unit) function of the CEL expressions of
+	// GlobalLLMRequestCosts, LLMRequestCosts and the quotas. Optional. The price function returns zero if this is not set.
+	ModelPrices []ModelPrice `json:"modelPrices,omitempty"`
+	// Currency is the currency of the ModelPrices and the ModelPrices of the backends, reported as an attribute
+	// of the cost metric. Optional.
+	Currency string `json:"currency,omitempty"`
 	// Backends is the list of backends that this listener can route to.
 	Backends []Backend `json:"backends,omitempty"`
 	// Models is the list of models that this route is aware of. Used to populate the "/models" endpoint in OpenAI-compatible APIs.
 	Models []Model `json:"models,omitempty"`
 	// MCPConfig is the configuration for the MCPRoute implementations.
 	MCPConfig *MCPConfig `json:"mcpConfig,omitempty"`
+	// QuotaStore configures where the quota counters are kept. Optional. Defaults to the in-memory store.
+	QuotaStore *QuotaStore `json:"quotaStore,omitempty"`
+	// ResponseStore configures where the finished responses of the OpenAI Responses API are kept so that
+	// "previous_response_id" and the retrieval of the responses work for the backends without server-side state.
+	// Optional. The responses are not kept by the gateway if this is not set.
+	ResponseStore *ResponseStore `json:"responseStore,omitempty"`
+	// ResponseCaches is the list of the response caches of the AIGatewayRoutes. Optional.
+	ResponseCaches []ResponseCache `json:"responseCaches,omitempty"`
+	// PIIPolicies is the list of the PII policies of the AIGatewayRoutes. Optional.
+	PIIPolicies []PIIPolicy `json:"piiPolicies,omitempty"`
+	// Guardrails is the list of the guardrails of the AIGatewayRoutes. Optional.
+	Guardrails []Guardrail `json:"guardrails,omitempty"`
 }
 
+// ResponseCache corresponds to ResponseCache in api/v1beta1/ai_gateway_route.go.
+//
+// The router filter does not know the route before the request is routed, so the cache applies to the requests
+// for the models matched exactly by the rules of the route.
+type ResponseCache struct {
+	// RouteName is the name of the AIGatewayRoute in the "namespace/name" format.
+	RouteName string `json:"routeName"`
+	// Models is the list of the models matched exactly by the rules of the route.
+	Models []string `json:"models"`
+	// TTL is how long the responses are served from the cache.
+	TTL time.Duration `json:"ttl"`
+	// OwnerHeader is the request header identifying the caller, such as the API key. The cached responses are only
+	// served to the requests with the same value of the header. Defaults to DefaultResponseCacheOwnerHeader when empty.
+	OwnerHeader string `json:"ownerHeader,omitempty"`
+	// Semantic enables the lookup of the responses to the semantically similar requests. Optional.
+	Semantic *SemanticResponseCache `json:"semantic,omitempty"`
+}
+
+// DefaultResponseCacheOwnerHeader is the default value of ResponseCache.OwnerHeader.
+const DefaultResponseCacheOwnerHeader = "authorization"
+
+// SemanticResponseCache is the configuration of the semantic lookup of the response cache.
+type SemanticResponseCache struct {
+	// Backend is the backend serving the OpenAI-compatible embeddings API used to embed the text of the requests.
+	Backend Backend `json:"backend"`
+	// URL is the base URL of the backend, e.g. "https://api.openai.com".
+	URL string `json:"url"`
+	// Model is the embedding model.
+	Model string `json:"model"`
+	// SimilarityThreshold is the minimum cosine similarity of the embeddings of the requests for the cached
+	// response to be served.
+	SimilarityThreshold float64 `json:"similarityThreshold"`
+}
+
+// PIIPolicy corresponds to PIIPolicy in api/v1beta1/ai_gateway_route.go.
+//
+// The same as the ResponseCache, the policy applies to the requests for the models matched exactly by the rules of
+// the route.
+type PIIPolicy struct {
+	// RouteName is the name of the AIGatewayRoute in the "namespace/name" format.
+	RouteName string `json:"routeName"`
+	// Models is the list of the models matched exactly by the rules of the route.
+	Models []string `json:"models"`
+	// Action is what is done to the requests with PII.
+	Action PIIAction `json:"action"`
+	// Entities is the list of the built-in PII entities to detect, e.g. "Email".
+	Entities []string `json:"entities,omitempty"`
+	// Patterns is the list of the custom patterns to detect.
+	Patterns []PIIPattern `json:"patterns,omitempty"`
+}
+
+// PIIAction is what is done to the requests with PII.
+type PIIAction string
+
+const (
+	// PIIActionBlock rejects the requests with PII.
+	PIIActionBlock PIIAction = "Block"
+	// PIIActionMask replaces the PII with placeholders.
+	PIIActionMask PIIAction = "Mask"
+	// PIIActionRestore replaces the PII with placeholders, and restores the original values in the responses.
+	PIIActionRestore PIIAction = "Restore"
+)
+
+// PIIPattern is a custom kind of PII detected with a regular expression.
+type PIIPattern struct {
+	// Name is the name of the pattern used in the placeholders, e.g. "EMPLOYEE_ID".
+	Name string `json:"name"`
+	// Regex is the RE2 regular expression matching the PII.
+	Regex string `json:"regex"`
+}
+
+// Guardrail corresponds to Guardrail in api/v1beta1/ai_gateway_route.go.
+//
+// The same as the ResponseCache, the guardrail applies to the requests for the models matched exactly by the rules
+// of the route.
+type Guardrail struct {
+	// RouteName is the name of the AIGatewayRoute in the "namespace/name" format.
+	RouteName string `json:"routeName"`
+	// Models is the list of the models matched exactly by the rules of the route.
+	Models []string `json:"models"`
+	// Webhook is the HTTP guard service. Either this or LLM is set.
+	Webhook *GuardrailWebhook `json:"webhook,omitempty"`
+	// LLM is the safety classifier model. Either this or Webhook is set.
+	LLM *GuardrailLLM `json:"llm,omitempty"`
+	// CheckCompletion enables the check of the completions.
+	CheckCompletion bool `json:"checkCompletion,omitempty"`
+	// StreamWindowSize is the number of bytes of the completion of a streaming response checked at once. Zero holds
+	// the whole streaming response back until its end.
+	StreamWindowSize int `json:"streamWindowSize,omitempty"`
+	// Timeout is the timeout of each check.
+	Timeout time.Duration `json:"timeout"`
+	// FailOpen lets the requests through when the guard service fails.
+	FailOpen bool `json:"failOpen,omitempty"`
+}
+
+// GuardrailWebhook is the configuration of the HTTP guard service.
+type GuardrailWebhook struct {
+	// URL is the URL the checks are posted to.
+	URL string `json:"url"`
+}
+
+// GuardrailLLM is the configuration of the safety classifier model.
+type GuardrailLLM struct {
+	// Backend is the backend serving the classifier model with the OpenAI-compatible chat completions API.
+	Backend Backend `json:"backend"`
+	// URL is the base URL of the backend, e.g. "https://api.openai.com".
+	URL string `json:"url"`
+	// Model is the classifier model.
+	Model string `json:"model"`
+	// UnsafeAction is what is done to the requests classified as unsafe.
+	UnsafeAction GuardrailUnsafeAction `json:"unsafeAction"`
+}
+
+// GuardrailUnsafeAction is what is done to the requests classified as unsafe.
+type GuardrailUnsafeAction string
+
+const (
+	// GuardrailUnsafeActionBlock rejects the requests.
+	GuardrailUnsafeActionBlock GuardrailUnsafeAction = "Block"
+	// GuardrailUnsafeActionAnnotate lets the requests through, and annotates them in the dynamic metadata.
+	GuardrailUnsafeActionAnnotate GuardrailUnsafeAction = "Annotate"
+)
+
+// ResponseStore is the configuration of the store for the responses of the OpenAI Responses API.
+type ResponseStore struct {
+	// Type is the type of the store.
+	Type ResponseStoreType `json:"type"`
+	// Path is the directory where the responses are kept. Only used when the Type is ResponseStoreTypeFile.
+	Path string `json:"path,omitempty"`
+	// OwnerHeader is the request header identifying the caller that owns the responses, such as the API key.
+	// A response can only be retrieved, deleted or continued from by the requests with the same value of the header.
+	// Defaults to DefaultResponseStoreOwnerHeader when empty.
+	OwnerHeader string `json:"ownerHeader,omitempty"`
+}
+
+// DefaultResponseStoreOwnerHeader is the default value of ResponseStore.OwnerHeader.
+const DefaultResponseStoreOwnerHeader = "authorization"
+
+// ResponseStoreType is the type of the response store.
+type ResponseStoreType string
+
+const (
+	// ResponseStoreTypeMemory keeps the responses in the memory of the external processor.
+	ResponseStoreTypeMemory ResponseStoreType = "Memory"
+	// ResponseStoreTypeFile keeps the responses as files in a local directory.
+	ResponseStoreTypeFile ResponseStoreType = "File"
+)
+
+// QuotaStore corresponds to QuotaStore in api/v1beta1/gateway_config.go.
+type QuotaStore struct {
+	// Type is the type of the store.
+	Type QuotaStoreType `json:"type"`
+	// RedisURL is the "redis://" or "rediss://" URL of the Redis server. Only used when the Type is QuotaStoreTypeRedis.
+	RedisURL string `json:"redisURL,omitempty"`
+	// RedisCluster is true when the Redis server is a Redis Cluster.
+	RedisCluster bool `json:"redisCluster,omitempty"`
+	// RedisPassword is the password used to authenticate with the Redis server, resolved by the controller from
+	// the secret referenced by the GatewayConfig. The RedisURL never contains the password.
+	RedisPassword string `json:"redisPassword,omitempty"`
+}
+
+// QuotaStoreType is the type of the quota counter store.
+type QuotaStoreType string
+
+const (
+	// QuotaStoreTypeMemory keeps the counters in the memory of the external processor.
+	QuotaStoreTypeMemory QuotaStoreType = "Memory"
+	// QuotaStoreTypeRedis keeps the counters in a server speaking the Redis protocol.
+	QuotaStoreTypeRedis QuotaStoreType = "Redis"
+)
+
 // Model corresponds to the OpenAI model object in the OpenAI-compatible APIs
 // and is used to populate the "/models" endpoint in OpenAI-compatible APIs.
 type Model struct {
@@ -69,6 +258,18 @@ type GlobalLLMRequestCost struct {
 	CEL string `json:"cel,omitempty"`
 }
 
+// ModelPrice is the price of each unit of a model.
+//
+// The cost of each request to a model with a price is reported as a metric as well as in the "cost" key of the
+// dynamic metadata, and is available to the CEL expressions as the cost variable.
+type ModelPrice struct {
+	// Model is the name of the model.
+	Model string `json:"model"`
+	// Prices maps the unit, e.g. "input" or "output", to the price of one unit.
+	// See the PriceUnit constants of the llmcostcel package for the units used to calculate the cost.
+	Prices map[string]float64 `json:"prices"`
+}
+
 // LLMRequestCost specifies "where" the request cost is stored in the filter metadata as well as
 // "how" the cost is calculated. By default, the cost is retrieved from "output token" in the response body.
 //
@@ -134,7 +335,7 @@ const (
 	APISchemaCohere APISchemaName = "Cohere"
 	// APISchemaAWSBedrock represents the AWS Bedrock API schema.
 	// Used for models hosted on AWS Bedrock. Chat completions use the Converse API,
-	// while embeddings use the InvokeModel API.
+	// while embeddings use the InvokeModel API and rerank uses the Rerank API of the Agents runtime.
 	APISchemaAWSBedrock APISchemaName = "AWSBedrock"
 	// APISchemaAzureOpenAI represents the Azure OpenAI API schema.
 	APISchemaAzureOpenAI APISchemaName = "AzureOpenAI"
@@ -145,11 +346,18 @@ const (
 	// Used for Claude models hosted on Google Cloud Vertex AI.
 	APISchemaGCPAnthropic APISchemaName = "GCPAnthropic"
 	// APISchemaAnthropic represents the standard Anthropic API schema.
+	// Chat completions are translated to the Messages API at /v1/messages.
 	APISchemaAnthropic APISchemaName = "Anthropic"
 	// APISchemaAWSAnthropic represents the AWS Bedrock Anthropic API schema.
 	// Used for Claude models hosted on AWS Bedrock. Supports both OpenAI and Anthropic input formats
 	// depending on the endpoint path, similar to APISchemaGCPAnthropic.
 	APISchemaAWSAnthropic APISchemaName = "AWSAnthropic"
+	// APISchemaGoogleAIStudio represents the Gemini Developer API schema of Google AI Studio.
+	// Used for Gemini models served by generativelanguage.googleapis.com with an API key.
+	APISchemaGoogleAIStudio APISchemaName = "GoogleAIStudio"
+	// APISchemaAzureContentSafety represents the Azure AI Content Safety API schema.
+	// Used for the moderation requests with the text:analyze API.
+	APISchemaAzureContentSafety APISchemaName = "AzureContentSafety"
 )
 
 // RouteRuleName is the name of the route rule.
@@ -169,6 +377,23 @@ type Backend struct {
 	HeaderMutation *HTTPHeaderMutation `json:"httpHeaderMutation,omitempty"`
 	// Body mutations to be applied to the request before sending to the backend. Optional.
 	BodyMutation *HTTPBodyMutation `json:"httpBodyMutation,omitempty"`
+	// Quota is the token quota enforced on the requests sent to the backend. Optional.
+	Quota *BackendQuota `json:"quota,omitempty"`
+	// ModelPrices are the prices of the models served by this backend, which take precedence over the ModelPrices
+	// of the Config. Optional.
+	ModelPrices []ModelPrice `json:"modelPrices,omitempty"`
+	// Batch is the configuration of the batch jobs created on the backend. Optional.
+	Batch *BackendBatch `json:"batch,omitempty"`
+}
+
+// BackendBatch corresponds to AIServiceBackendBatch in api/v1beta1/ai_service_backend.go.
+type BackendBatch struct {
+	// InputURIPrefix is the storage URI under which the inputs of the batches must be.
+	InputURIPrefix string `json:"inputURIPrefix"`
+	// OutputURI is the storage URI where the provider writes the outputs of the batches.
+	OutputURI string `json:"outputURI"`
+	// RoleARN is the ARN of the service role of the AWS Bedrock batch inference jobs.
+	RoleARN string `json:"roleARN,omitempty"`
 }
 
 // BackendAuth corresponds partially to BackendSecurityPolicy in api/v1alpha1/api.go.
@@ -185,6 +410,8 @@ type BackendAuth struct {
 	AzureAuth *AzureAuth `json:"azure,omitempty"`
 	// GCPAuth specifies the location of GCP credential file.
 	GCPAuth *GCPAuth `json:"gcp,omitempty"`
+	// GoogleAPIKey is the Google AI Studio API key.
+	GoogleAPIKey *GoogleAPIKeyAuth `json:"googleAPIKey,omitempty"`
 }
 
 // AWSAuth defines the credentials needed to access AWS.
@@ -213,6 +440,12 @@ type AnthropicAPIKeyAuth struct {
 	Key string `json:"key"`
 }
 
+// GoogleAPIKeyAuth defines the Google AI Studio API key.
+type GoogleAPIKeyAuth struct {
+	// Key is the Google AI Studio API key as a literal string.
+	Key string `json:"key"`
+}
+
 // AzureAuth defines the file containing azure access token that will be mounted to the external proc.
 type AzureAuth struct {
 	// AccessToken is the access token as a literal string.
@@ -256,6 +489,9 @@ type HTTPHeader struct {
 }
 
 // HTTPBodyMutation defines the mutation of HTTP request body JSON fields that will be applied to the request
+//
+// For multipart/form-data request bodies, the fields are the form fields instead, and JSON string values are set
+// unquoted.
 type HTTPBodyMutation struct {
 	// Set overwrites/adds the request body with the given JSON field (name, value)
 	// before sending to the backend. Only top-level fields are currently supported.
@@ -276,6 +512,102 @@ type HTTPBodyField struct {
 	Value string `json:"value"`
 }
 
+// BackendQuota corresponds to QuotaPolicy in api/v1alpha1/quota_policy.go attached to the AIServiceBackend.
+type BackendQuota struct {
+	// Name uniquely identifies the quota counters. Backends sharing the same name share the counters,
+	// which is the case for the same AIServiceBackend referenced by multiple routes.
+	Name string `json:"name"`
+	// ServiceQuota is the quota applicable to all models served by the backend. Optional.
+	ServiceQuota *ServiceQuota `json:"serviceQuota,omitempty"`
+	// PerModelQuotas overrides the ServiceQuota for specific models.
+	PerModelQuotas []PerModelQuota `json:"perModelQuotas,omitempty"`
+}
+
+// ServiceQuota is the quota applicable to all models served by the backend.
+type ServiceQuota struct {
+	// CostExpression is the CEL expression to calculate the quota burndown of the request.
+	// When empty, the total tokens are used.
+	CostExpression string `json:"costExpression,omitempty"`
+	// Quota is the quota value.
+	Quota QuotaValue `json:"quota"`
+}
+
+// PerModelQuota is the quota for a specific model served by the backend.
+type PerModelQuota struct {
+	// ModelName is the name of the model this quota applies to.
+	ModelName string `json:"modelName"`
+	// CostExpression is the CEL expression to calculate the quota burndown of the request.
+	// When empty, the total tokens are used.
+	CostExpression string `json:"costExpression,omitempty"`
+	// Mode determines how the quota is charged to the DefaultBucket and the matching BucketRules.
+	Mode QuotaBucketMode `json:"mode"`
+	// DefaultBucket is the quota applicable to all requests for the model. Optional.
+	DefaultBucket *QuotaValue `json:"defaultBucket,omitempty"`
+	// BucketRules are the quotas applicable to the requests matching the client selectors.
+	BucketRules []QuotaRule `json:"bucketRules,omitempty"`
+}
+
+// QuotaBucketMode corresponds to QuotaBucketMode in api/v1alpha1/quota_policy.go.
+type QuotaBucketMode string
+
+const (
+	// QuotaBucketModeExclusive charges the matching bucket rules, or the default bucket if no rule matches.
+	// The request is denied only if all the matching buckets are out of quota.
+	QuotaBucketModeExclusive QuotaBucketMode = "Exclusive"
+	// QuotaBucketModeShared charges the matching bucket rules as well as the default bucket.
+	// The request is denied if any of the matching buckets is out of quota.
+	QuotaBucketModeShared QuotaBucketMode = "Shared"
+)
+
+// QuotaRule is a quota bucket applicable to the requests matching the client selectors.
+type QuotaRule struct {
+	// ClientSelectors is the list of conditions that all must hold for the rule to apply.
+	// When empty, the rule applies to all requests.
+	ClientSelectors []QuotaClientSelector `json:"clientSelectors,omitempty"`
+	// Quota is the quota value.
+	Quota QuotaValue `json:"quota"`
+	// ShadowMode indicates that the rule is only observed and never enforced.
+	ShadowMode bool `json:"shadowMode,omitempty"`
+}
+
+// QuotaClientSelector selects the requests by the request headers.
+type QuotaClientSelector struct {
+	// Headers is the list of header matches that all must hold for the selector to match.
+	Headers []QuotaHeaderMatch `json:"headers,omitempty"`
+}
+
+// QuotaHeaderMatch matches a single request header.
+type QuotaHeaderMatch struct {
+	// Name is the name of the header. This is always ensured to be lower-cased like Envoy does internally.
+	Name string `json:"name"`
+	// Type is the type of the match.
+	Type QuotaHeaderMatchType `json:"type"`
+	// Value is the value to match. Not used when the Type is QuotaHeaderMatchTypeDistinct.
+	Value string `json:"value,omitempty"`
+	// Invert inverts the result of the match.
+	Invert bool `json:"invert,omitempty"`
+}
+
+// QuotaHeaderMatchType specifies how the header value is matched.
+type QuotaHeaderMatchType string
+
+const (
+	// QuotaHeaderMatchTypeExact matches the exact value of the header.
+	QuotaHeaderMatchTypeExact QuotaHeaderMatchType = "Exact"
+	// QuotaHeaderMatchTypeRegularExpression matches the value of the header against the RE2 regular expression.
+	QuotaHeaderMatchTypeRegularExpression QuotaHeaderMatchType = "RegularExpression"
+	// QuotaHeaderMatchTypeDistinct matches any value of the header, and each distinct value gets its own bucket.
+	QuotaHeaderMatchTypeDistinct QuotaHeaderMatchType = "Distinct"
+)
+
+// QuotaValue is the limit allotted for a sliding time window.
+type QuotaValue struct {
+	// Limit is the limit allotted for the window.
+	Limit uint64 `json:"limit"`
+	// Duration is the length of the sliding window.
+	Duration time.Duration `json:"duration"`
+}
+
 // UnmarshalConfigYaml reads the file at the given path and unmarshals it into a Config struct.
 func UnmarshalConfigYaml(path string) (*Config, error) {
 	raw, err := os.ReadFile(path)
@@ -6,15 +6,25 @@
 package filterapi
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/google/cel-go/cel"
 
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 	"github.com/envoyproxy/ai-gateway/internal/llmcostcel"
+	"github.com/envoyproxy/ai-gateway/internal/pii"
+	"github.com/envoyproxy/ai-gateway/internal/quota"
+	"github.com/envoyproxy/ai-gateway/internal/responsecache"
+	"github.com/envoyproxy/ai-gateway/internal/responsestore"
 )
 
+// defaultQuotaCostExpression is the CEL expression used when the quota does not specify one.
+const defaultQuotaCostExpression = "total_tokens"
+
 // BackendAuthHandler is the interface that deals with the backend auth for a specific backend.
 type BackendAuthHandler interface {
 	// Do performs the backend auth, and make changes to the request headers passed in as `requestHeaders`.
@@ -39,6 +49,55 @@ type RuntimeConfig struct {
 	DeclaredModels []Model
 	// Backends is the map of backends by name.
 	Backends map[string]*RuntimeBackend
+	// HasQuota is true if any of the backends has a quota configured.
+	HasQuota bool
+	// Prices is the price table of the models, used to calculate the cost of the requests to the backends
+	// without their own price for the model.
+	Prices llmcostcel.PriceTable
+	// Currency is the currency of the prices.
+	Currency string
+	// QuotaStore holds the quota counters. This is owned by the server and outlives the configuration
+	// so that the counters are preserved across configuration updates.
+	QuotaStore quota.Store
+	// ResponseStore holds the responses of the OpenAI Responses API. Nil if the gateway does not keep the responses.
+	// This is owned by the server and outlives the configuration, the same as QuotaStore.
+	ResponseStore responsestore.Store
+	// ResponseStoreOwnerHeader is the request header identifying the owner of the stored responses.
+	// See ResponseStore.OwnerHeader.
+	ResponseStoreOwnerHeader string
+	// ResponseCaches is the map of the response caches by the model they apply to.
+	ResponseCaches map[string]*RuntimeResponseCache
+	// ResponseCacheStore holds the cached responses. Nil if no route has a response cache.
+	// This is owned by the server and outlives the configuration, the same as QuotaStore.
+	ResponseCacheStore responsecache.Store
+	// PIIPolicies is the map of the PII policies by the model they apply to.
+	PIIPolicies map[string]*RuntimePIIPolicy
+	// Guardrails is the map of the guardrails by the model they apply to.
+	Guardrails map[string]*RuntimeGuardrail
+}
+
+// RuntimeGuardrail is the Guardrail with the auth handler of the classifier backend.
+type RuntimeGuardrail struct {
+	*Guardrail
+	// LLMHandler is the auth handler of the backend of the classifier model. Nil if the guardrail has no classifier
+	// or the backend has no auth.
+	LLMHandler BackendAuthHandler
+}
+
+// RuntimePIIPolicy is the PIIPolicy with its compiled detector.
+type RuntimePIIPolicy struct {
+	*PIIPolicy
+	// Detector detects the PII of the policy.
+	Detector *pii.Detector
+}
+
+// RuntimeResponseCache is the ResponseCache with the auth handler of the semantic lookup backend.
+type RuntimeResponseCache struct {
+	*ResponseCache
+	// OwnerHeaderKey is the lower-cased OwnerHeader, or DefaultResponseCacheOwnerHeader when it is empty.
+	OwnerHeaderKey string
+	// SemanticHandler is the auth handler of the backend of the semantic lookup. Nil if the backend has no auth.
+	SemanticHandler BackendAuthHandler
 }
 
 // RuntimeBackend is a filter backend with its auth handler that is derived from the filterapi.Backend configuration.
@@ -47,6 +106,26 @@ type RuntimeBackend struct {
 	Backend *Backend
 	// Handler is the backend auth handler.
 	Handler BackendAuthHandler
+	// Quota is the compiled quota configuration. Nil if the backend has no quota.
+	Quota *RuntimeBackendQuota
+	// Prices is the price table of the models served by the backend. Nil if the backend has no prices.
+	Prices llmcostcel.PriceTable
+	// GlobalRequestCosts and RequestCosts are the request costs of the gateway compiled with the price table of the
+	// gateway overridden by Prices, so that the price function of their CEL expressions sees the prices of the backend.
+	// Nil if the backend has no prices, in which case the ones of the RuntimeConfig are used.
+	GlobalRequestCosts []RuntimeGlobalRequestCost
+	RequestCosts       []RuntimeRequestCost
+}
+
+// RuntimeBackendQuota is the BackendQuota with the compiled CEL programs and regular expressions.
+type RuntimeBackendQuota struct {
+	*BackendQuota
+	// ServiceCostProg is the compiled cost expression of the ServiceQuota. Nil if there's no ServiceQuota.
+	ServiceCostProg cel.Program
+	// PerModelCostProgs are the compiled cost expressions of the PerModelQuotas, indexed in the same order.
+	PerModelCostProgs []cel.Program
+	// Regexps are the compiled regular expressions of the header matches keyed by the pattern.
+	Regexps map[string]*regexp.Regexp
 }
 
 // RuntimeGlobalRequestCost is the configuration for gateway-level default request costs.
@@ -65,7 +144,10 @@ type RuntimeRequestCost struct {
 
 // NewRuntimeConfig creates a new runtime filter configuration from the given filterapi.Config and a function to create backend auth handlers.
 func NewRuntimeConfig(ctx context.Context, config *Config, fn NewBackendAuthHandlerFunc) (*RuntimeConfig, error) {
+	prices := newPriceTable(config.ModelPrices)
+
 	backends := make(map[string]*RuntimeBackend, len(config.Backends))
+	hasQuota := false
 	for i := range config.Backends {
 		b := &config.Backends[i]
 		var h BackendAuthHandler
@@ -77,51 +159,223 @@ func NewRuntimeConfig(ctx context.Context, config *Config, fn NewBackendAuthHand
 			}
 		}
 
-		backends[b.Name] = &RuntimeBackend{Backend: b, Handler: h}
+		rb := &RuntimeBackend{Backend: b, Handler: h}
+		// The prices of the backend take precedence over the ones of the gateway in its CEL expressions.
+		backendPrices := prices
+		if len(b.ModelPrices) > 0 {
+			rb.Prices = newPriceTable(b.ModelPrices)
+			backendPrices = prices.WithOverrides(rb.Prices)
+			var err error
+			if rb.GlobalRequestCosts, err = newRuntimeGlobalRequestCosts(config.GlobalLLMRequestCosts, backendPrices); err != nil {
+				return nil, fmt.Errorf("cannot create request costs for backend %s: %w", b.Name, err)
+			}
+			if rb.RequestCosts, err = newRuntimeRequestCosts(config.LLMRequestCosts, backendPrices); err != nil {
+				return nil, fmt.Errorf("cannot create request costs for backend %s: %w", b.Name, err)
+			}
+		}
+		if b.Quota != nil {
+			var err error
+			rb.Quota, err = newRuntimeBackendQuota(b.Quota, backendPrices)
+			if err != nil {
+				return nil, fmt.Errorf("cannot create quota for backend %s: %w", b.Name, err)
+			}
+			hasQuota = true
+		}
+		backends[b.Name] = rb
+	}
+
+	globalCosts, err := newRuntimeGlobalRequestCosts(config.GlobalLLMRequestCosts, prices)
+	if err != nil {
+		return nil, err
+	}
+	costs, err := newRuntimeRequestCosts(config.LLMRequestCosts, prices)
+	if err != nil {
+		return nil, err
+	}
+
+	// Index the response caches by the models. A model matched by multiple routes uses the cache of the first route.
+	var responseCaches map[string]*RuntimeResponseCache
+	for i := range config.ResponseCaches {
+		c := &config.ResponseCaches[i]
+		rc := &RuntimeResponseCache{ResponseCache: c, OwnerHeaderKey: DefaultResponseCacheOwnerHeader}
+		if c.OwnerHeader != "" {
+			rc.OwnerHeaderKey = strings.ToLower(c.OwnerHeader)
+		}
+		if c.Semantic != nil && c.Semantic.Backend.Auth != nil {
+			var err error
+			rc.SemanticHandler, err = fn(ctx, c.Semantic.Backend.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("cannot create backend auth handler for the response cache of route %s: %w", c.RouteName, err)
+			}
+		}
+		if responseCaches == nil {
+			responseCaches = make(map[string]*RuntimeResponseCache)
+		}
+		for _, model := range c.Models {
+			if _, ok := responseCaches[model]; !ok {
+				responseCaches[model] = rc
+			}
+		}
+	}
+
+	// Index the PII policies by the models, the same as the response caches.
+	var piiPolicies map[string]*RuntimePIIPolicy
+	for i := range config.PIIPolicies {
+		p := &config.PIIPolicies[i]
+		entities := make([]pii.Entity, len(p.Entities))
+		for j, e := range p.Entities {
+			entities[j] = pii.Entity(e)
+		}
+		patterns := make([]pii.Pattern, len(p.Patterns))
+		for j, pattern := range p.Patterns {
+			patterns[j] = pii.Pattern{Name: pattern.Name, Regex: pattern.Regex}
+		}
+		detector, err := pii.NewDetector(entities, patterns)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create PII detector for route %s: %w", p.RouteName, err)
+		}
+		if piiPolicies == nil {
+			piiPolicies = make(map[string]*RuntimePIIPolicy)
+		}
+		rp := &RuntimePIIPolicy{PIIPolicy: p, Detector: detector}
+		for _, model := range p.Models {
+			if _, ok := piiPolicies[model]; !ok {
+				piiPolicies[model] = rp
+			}
+		}
+	}
+
+	// Index the guardrails by the models, the same as the response caches.
+	var guardrails map[string]*RuntimeGuardrail
+	for i := range config.Guardrails {
+		g := &config.Guardrails[i]
+		rg := &RuntimeGuardrail{Guardrail: g}
+		if g.LLM != nil && g.LLM.Backend.Auth != nil {
+			var err error
+			rg.LLMHandler, err = fn(ctx, g.LLM.Backend.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("cannot create backend auth handler for the guardrail of route %s: %w", g.RouteName, err)
+			}
+		}
+		if guardrails == nil {
+			guardrails = make(map[string]*RuntimeGuardrail)
+		}
+		for _, model := range g.Models {
+			if _, ok := guardrails[model]; !ok {
+				guardrails[model] = rg
+			}
+		}
+	}
+
+	responseStoreOwnerHeader := DefaultResponseStoreOwnerHeader
+	if config.ResponseStore != nil && config.ResponseStore.OwnerHeader != "" {
+		responseStoreOwnerHeader = strings.ToLower(config.ResponseStore.OwnerHeader)
 	}
 
-	// Compile CEL programs for GlobalLLMRequestCosts (gateway-level defaults).
-	globalCosts := make([]RuntimeGlobalRequestCost, 0, len(config.GlobalLLMRequestCosts))
-	for i := range config.GlobalLLMRequestCosts {
-		c := &config.GlobalLLMRequestCosts[i]
+	return &RuntimeConfig{
+		UUID:                     config.UUID,
+		Backends:                 backends,
+		GlobalRequestCosts:       globalCosts,
+		RequestCosts:             costs,
+		DeclaredModels:           config.Models,
+		HasQuota:                 hasQuota,
+		Prices:                   prices,
+		Currency:                 config.Currency,
+		ResponseCaches:           responseCaches,
+		PIIPolicies:              piiPolicies,
+		Guardrails:               guardrails,
+		ResponseStoreOwnerHeader: responseStoreOwnerHeader,
+	}, nil
+}
+
+// newRuntimeGlobalRequestCosts compiles the CEL programs of the GlobalLLMRequestCosts (gateway-level defaults)
+// whose price function looks up the given table.
+func newRuntimeGlobalRequestCosts(globalCosts []GlobalLLMRequestCost, prices llmcostcel.PriceTable) ([]RuntimeGlobalRequestCost, error) {
+	ret := make([]RuntimeGlobalRequestCost, 0, len(globalCosts))
+	for i := range globalCosts {
+		c := &globalCosts[i]
 		var prog cel.Program
 		if c.CEL != "" {
 			var err error
-			prog, err = llmcostcel.NewProgram(c.CEL)
+			prog, err = llmcostcel.NewProgramWithPriceTable(c.CEL, prices)
 			if err != nil {
 				return nil, fmt.Errorf("cannot create CEL program for global cost: %w", err)
 			}
 		}
-		globalCosts = append(globalCosts, RuntimeGlobalRequestCost{
+		ret = append(ret, RuntimeGlobalRequestCost{
 			GlobalLLMRequestCost: c,
 			CELProg:              prog,
 		})
 	}
+	return ret, nil
+}
 
-	// Compile CEL programs for LLMRequestCosts (route-scoped).
-	// Validate that all route-scoped costs have a non-empty RouteName.
-	costs := make([]RuntimeRequestCost, 0, len(config.LLMRequestCosts))
-	for i := range config.LLMRequestCosts {
-		c := &config.LLMRequestCosts[i]
+// newRuntimeRequestCosts compiles the CEL programs of the LLMRequestCosts (route-scoped) whose price function
+// looks up the given table. All route-scoped costs must have a non-empty RouteName.
+func newRuntimeRequestCosts(costs []LLMRequestCost, prices llmcostcel.PriceTable) ([]RuntimeRequestCost, error) {
+	ret := make([]RuntimeRequestCost, 0, len(costs))
+	for i := range costs {
+		c := &costs[i]
 		if c.RouteName == "" {
 			return nil, fmt.Errorf("route-scoped LLMRequestCost with metadataKey=%q must have non-empty RouteName", c.MetadataKey)
 		}
 		var prog cel.Program
 		if c.CEL != "" {
 			var err error
-			prog, err = llmcostcel.NewProgram(c.CEL)
+			prog, err = llmcostcel.NewProgramWithPriceTable(c.CEL, prices)
 			if err != nil {
 				return nil, fmt.Errorf("cannot create CEL program for cost: %w", err)
 			}
 		}
-		costs = append(costs, RuntimeRequestCost{LLMRequestCost: c, CELProg: prog})
+		ret = append(ret, RuntimeRequestCost{LLMRequestCost: c, CELProg: prog})
 	}
+	return ret, nil
+}
 
-	return &RuntimeConfig{
-		UUID:               config.UUID,
-		Backends:           backends,
-		GlobalRequestCosts: globalCosts,
-		RequestCosts:       costs,
-		DeclaredModels:     config.Models,
-	}, nil
+// newPriceTable creates the price table of the given model prices.
+func newPriceTable(modelPrices []ModelPrice) llmcostcel.PriceTable {
+	prices := make(llmcostcel.PriceTable, len(modelPrices))
+	for _, p := range modelPrices {
+		prices[p.Model] = p.Prices
+	}
+	return prices
+}
+
+// newRuntimeBackendQuota compiles the CEL programs and the regular expressions of the given BackendQuota.
+func newRuntimeBackendQuota(q *BackendQuota, prices llmcostcel.PriceTable) (*RuntimeBackendQuota, error) {
+	rq := &RuntimeBackendQuota{BackendQuota: q, Regexps: map[string]*regexp.Regexp{}}
+	if q.ServiceQuota != nil {
+		prog, err := llmcostcel.NewProgramWithPriceTable(cmp.Or(q.ServiceQuota.CostExpression, defaultQuotaCostExpression), prices)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create CEL program for service quota: %w", err)
+		}
+		rq.ServiceCostProg = prog
+	}
+	rq.PerModelCostProgs = make([]cel.Program, len(q.PerModelQuotas))
+	for i := range q.PerModelQuotas {
+		m := &q.PerModelQuotas[i]
+		prog, err := llmcostcel.NewProgramWithPriceTable(cmp.Or(m.CostExpression, defaultQuotaCostExpression), prices)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create CEL program for model %s quota: %w", m.ModelName, err)
+		}
+		rq.PerModelCostProgs[i] = prog
+		for _, rule := range m.BucketRules {
+			for _, sel := range rule.ClientSelectors {
+				for _, h := range sel.Headers {
+					if h.Type != QuotaHeaderMatchTypeRegularExpression {
+						continue
+					}
+					if _, ok := rq.Regexps[h.Value]; ok {
+						continue
+					}
+					re, err := regexp.Compile(h.Value)
+					if err != nil {
+						return nil, fmt.Errorf("cannot compile regular expression for header %s: %w", h.Name, err)
+					}
+					rq.Regexps[h.Value] = re
+				}
+			}
+		}
+	}
+	return rq, nil
 }
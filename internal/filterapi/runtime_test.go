@@ -12,6 +12,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 	"github.com/envoyproxy/ai-gateway/internal/llmcostcel"
 )
 
@@ -59,12 +60,60 @@ func TestServer_LoadConfig(t *testing.T) {
 		require.Equal(t, "1 + 1", rc.RequestCosts[1].CEL)
 		prog := rc.RequestCosts[1].CELProg
 		require.NotNil(t, prog)
-		val, err := llmcostcel.EvaluateProgram(prog, "", "", "", 1, 1, 1, 1, 1, 0)
+		val, err := llmcostcel.EvaluateProgram(prog, llmcostcel.Variables{InputTokens: 1, CachedInputTokens: 1, CacheCreationInputTokens: 1, OutputTokens: 1, TotalTokens: 1})
 		require.NoError(t, err)
 		require.Equal(t, uint64(2), val)
 		require.Equal(t, config.Models, rc.DeclaredModels)
 	})
 
+	t.Run("with model prices", func(t *testing.T) {
+		config := &Config{
+			ModelPrices: []ModelPrice{{Model: "gpt-4o", Prices: map[string]float64{"input": 2, "output": 8}}},
+			Currency:    "EUR",
+			Backends: []Backend{
+				{Name: "no-prices"},
+				{Name: "prices", ModelPrices: []ModelPrice{{Model: "gpt-4o", Prices: map[string]float64{"input": 1}}}},
+			},
+			GlobalLLMRequestCosts: []GlobalLLMRequestCost{
+				{MetadataKey: "cost", Type: LLMRequestCostTypeCEL, CEL: "uint(price(model, 'input') * double(input_tokens) + price(model, 'output') * double(output_tokens))"},
+			},
+		}
+		rc, err := NewRuntimeConfig(t.Context(), config, func(context.Context, *BackendAuth) (BackendAuthHandler, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.Len(t, rc.GlobalRequestCosts, 1)
+		v, err := llmcostcel.EvaluateProgram(rc.GlobalRequestCosts[0].CELProg, llmcostcel.Variables{Model: "gpt-4o", InputTokens: 10, OutputTokens: 5})
+		require.NoError(t, err)
+		require.Equal(t, uint64(60), v)
+
+		require.Equal(t, llmcostcel.PriceTable{"gpt-4o": {"input": 2, "output": 8}}, rc.Prices)
+		require.Equal(t, "EUR", rc.Currency)
+		require.Nil(t, rc.Backends["no-prices"].Prices)
+		require.Nil(t, rc.Backends["no-prices"].GlobalRequestCosts)
+		require.Equal(t, llmcostcel.PriceTable{"gpt-4o": {"input": 1}}, rc.Backends["prices"].Prices)
+
+		// The price function of the costs of the backend sees the prices of the backend, where the model has no
+		// output price.
+		require.Len(t, rc.Backends["prices"].GlobalRequestCosts, 1)
+		v, err = llmcostcel.EvaluateProgram(rc.Backends["prices"].GlobalRequestCosts[0].CELProg, llmcostcel.Variables{Model: "gpt-4o", InputTokens: 10, OutputTokens: 5})
+		require.NoError(t, err)
+		require.Equal(t, uint64(10), v)
+	})
+
+	t.Run("response store owner header", func(t *testing.T) {
+		noAuth := func(context.Context, *BackendAuth) (BackendAuthHandler, error) { return nil, nil }
+		rc, err := NewRuntimeConfig(t.Context(), &Config{}, noAuth)
+		require.NoError(t, err)
+		require.Equal(t, DefaultResponseStoreOwnerHeader, rc.ResponseStoreOwnerHeader)
+
+		rc, err = NewRuntimeConfig(t.Context(), &Config{
+			ResponseStore: &ResponseStore{Type: ResponseStoreTypeMemory, OwnerHeader: "X-Tenant-ID"},
+		}, noAuth)
+		require.NoError(t, err)
+		require.Equal(t, "x-tenant-id", rc.ResponseStoreOwnerHeader)
+	})
+
 	t.Run("with global costs", func(t *testing.T) {
 		config := &Config{
 			GlobalLLMRequestCosts: []GlobalLLMRequestCost{
@@ -131,4 +180,166 @@ func TestServer_LoadConfig(t *testing.T) {
 		require.Contains(t, err.Error(), "must have non-empty RouteName")
 		require.Contains(t, err.Error(), "missing_route")
 	})
+
+	t.Run("with quota", func(t *testing.T) {
+		config := &Config{
+			Backends: []Backend{
+				{Name: "no-quota"},
+				{Name: "quota", Quota: &BackendQuota{
+					Name:         "ns/policy/ns/backend",
+					ServiceQuota: &ServiceQuota{Quota: QuotaValue{Limit: 100, Duration: time.Minute}},
+					PerModelQuotas: []PerModelQuota{{
+						ModelName:      "m",
+						CostExpression: "input_tokens + output_tokens",
+						Mode:           QuotaBucketModeShared,
+						BucketRules: []QuotaRule{{ClientSelectors: []QuotaClientSelector{{Headers: []QuotaHeaderMatch{
+							{Name: "x-user", Type: QuotaHeaderMatchTypeRegularExpression, Value: "^a.*"},
+							{Name: "x-tenant", Type: QuotaHeaderMatchTypeDistinct},
+						}}}}},
+					}},
+				}},
+			},
+		}
+		rc, err := NewRuntimeConfig(t.Context(), config, func(_ context.Context, _ *BackendAuth) (BackendAuthHandler, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.True(t, rc.HasQuota)
+		require.Nil(t, rc.Backends["no-quota"].Quota)
+		q := rc.Backends["quota"].Quota
+		require.NotNil(t, q)
+		require.Equal(t, "ns/policy/ns/backend", q.Name)
+
+		// The default cost expression is the total tokens.
+		v, err := llmcostcel.EvaluateProgram(q.ServiceCostProg, llmcostcel.Variables{InputTokens: 1, OutputTokens: 2, TotalTokens: 10})
+		require.NoError(t, err)
+		require.Equal(t, uint64(10), v)
+		require.Len(t, q.PerModelCostProgs, 1)
+		v, err = llmcostcel.EvaluateProgram(q.PerModelCostProgs[0], llmcostcel.Variables{InputTokens: 1, OutputTokens: 2, TotalTokens: 10})
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), v)
+		require.Len(t, q.Regexps, 1)
+		require.True(t, q.Regexps["^a.*"].MatchString("abc"))
+	})
+
+	t.Run("error - invalid quota", func(t *testing.T) {
+		for _, tc := range []struct {
+			name   string
+			quota  *BackendQuota
+			expErr string
+		}{
+			{
+				name:   "service cost expression",
+				quota:  &BackendQuota{ServiceQuota: &ServiceQuota{CostExpression: "bad syntax @@"}},
+				expErr: "cannot create CEL program for service quota",
+			},
+			{
+				name:   "model cost expression",
+				quota:  &BackendQuota{PerModelQuotas: []PerModelQuota{{ModelName: "m", CostExpression: "bad syntax @@"}}},
+				expErr: "cannot create CEL program for model m quota",
+			},
+			{
+				name: "regular expression",
+				quota: &BackendQuota{PerModelQuotas: []PerModelQuota{{ModelName: "m", BucketRules: []QuotaRule{{
+					ClientSelectors: []QuotaClientSelector{{Headers: []QuotaHeaderMatch{{Name: "x-user", Type: QuotaHeaderMatchTypeRegularExpression, Value: "("}}}},
+				}}}}},
+				expErr: "cannot compile regular expression for header x-user",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := NewRuntimeConfig(t.Context(), &Config{Backends: []Backend{{Name: "b", Quota: tc.quota}}},
+					func(_ context.Context, _ *BackendAuth) (BackendAuthHandler, error) { return nil, nil })
+				require.ErrorContains(t, err, "cannot create quota for backend b")
+				require.ErrorContains(t, err, tc.expErr)
+			})
+		}
+	})
+
+	t.Run("with response caches", func(t *testing.T) {
+		config := &Config{
+			ResponseCaches: []ResponseCache{
+				{RouteName: "ns/route1", Models: []string{"gpt-4o", "gpt-4o-mini"}, TTL: time.Hour},
+				{RouteName: "ns/route2", Models: []string{"gpt-4o", "claude"}, TTL: time.Minute, OwnerHeader: "X-API-Key", Semantic: &SemanticResponseCache{
+					Backend:             Backend{Name: "embeddings", Auth: &BackendAuth{APIKey: &APIKeyAuth{Key: "dummy"}}},
+					URL:                 "https://api.openai.com",
+					Model:               "text-embedding-3-small",
+					SimilarityThreshold: 0.95,
+				}},
+			},
+		}
+		handler := &dummyBackendAuthHandler{}
+		rc, err := NewRuntimeConfig(t.Context(), config, func(_ context.Context, b *BackendAuth) (BackendAuthHandler, error) {
+			require.Equal(t, "dummy", b.APIKey.Key)
+			return handler, nil
+		})
+		require.NoError(t, err)
+		require.Len(t, rc.ResponseCaches, 3)
+		// The model matched by both routes uses the cache of the first one.
+		require.Equal(t, "ns/route1", rc.ResponseCaches["gpt-4o"].RouteName)
+		require.Nil(t, rc.ResponseCaches["gpt-4o"].SemanticHandler)
+		require.Equal(t, DefaultResponseCacheOwnerHeader, rc.ResponseCaches["gpt-4o"].OwnerHeaderKey)
+		require.Same(t, rc.ResponseCaches["gpt-4o"], rc.ResponseCaches["gpt-4o-mini"])
+		require.Equal(t, "ns/route2", rc.ResponseCaches["claude"].RouteName)
+		require.Same(t, handler, rc.ResponseCaches["claude"].SemanticHandler)
+		require.Equal(t, "x-api-key", rc.ResponseCaches["claude"].OwnerHeaderKey)
+
+		rc, err = NewRuntimeConfig(t.Context(), &Config{}, nil)
+		require.NoError(t, err)
+		require.Nil(t, rc.ResponseCaches)
+	})
+
+	t.Run("with PII policies", func(t *testing.T) {
+		config := &Config{
+			PIIPolicies: []PIIPolicy{
+				{RouteName: "ns/route1", Models: []string{"gpt-4o"}, Action: PIIActionBlock, Entities: []string{"Email"}},
+				{RouteName: "ns/route2", Models: []string{"gpt-4o", "claude"}, Action: PIIActionRestore, Patterns: []PIIPattern{
+					{Name: "EMPLOYEE_ID", Regex: `EMP-\d{6}`},
+				}},
+			},
+		}
+		rc, err := NewRuntimeConfig(t.Context(), config, nil)
+		require.NoError(t, err)
+		require.Len(t, rc.PIIPolicies, 2)
+		// The model matched by both routes uses the policy of the first one.
+		require.Equal(t, "ns/route1", rc.PIIPolicies["gpt-4o"].RouteName)
+		require.Equal(t, PIIActionRestore, rc.PIIPolicies["claude"].Action)
+		require.Equal(t, "Employee [EMPLOYEE_ID_1]", rc.PIIPolicies["claude"].Detector.NewRedactor().Redact("Employee EMP-000123"))
+
+		_, err = NewRuntimeConfig(t.Context(), &Config{PIIPolicies: []PIIPolicy{
+			{RouteName: "ns/route1", Models: []string{"gpt-4o"}, Action: PIIActionMask, Entities: []string{"Passport"}},
+		}}, nil)
+		require.ErrorContains(t, err, `cannot create PII detector for route ns/route1: unknown entity "Passport"`)
+	})
+
+	t.Run("with guardrails", func(t *testing.T) {
+		config := &Config{
+			Guardrails: []Guardrail{
+				{RouteName: "ns/route1", Models: []string{"gpt-4o"}, Webhook: &GuardrailWebhook{URL: "http://guard:8080/check"}, Timeout: time.Second},
+				{RouteName: "ns/route2", Models: []string{"gpt-4o", "claude"}, Timeout: time.Second, LLM: &GuardrailLLM{
+					Backend:      Backend{Name: "llama-guard", Auth: &BackendAuth{APIKey: &APIKeyAuth{Key: "dummy"}}},
+					URL:          "http://vllm:8000",
+					Model:        "meta-llama/Llama-Guard-3-8B",
+					UnsafeAction: GuardrailUnsafeActionBlock,
+				}},
+			},
+		}
+		handler := &dummyBackendAuthHandler{}
+		rc, err := NewRuntimeConfig(t.Context(), config, func(_ context.Context, b *BackendAuth) (BackendAuthHandler, error) {
+			require.Equal(t, "dummy", b.APIKey.Key)
+			return handler, nil
+		})
+		require.NoError(t, err)
+		require.Len(t, rc.Guardrails, 2)
+		// The model matched by both routes uses the guardrail of the first one.
+		require.Equal(t, "ns/route1", rc.Guardrails["gpt-4o"].RouteName)
+		require.Nil(t, rc.Guardrails["gpt-4o"].LLMHandler)
+		require.Equal(t, "ns/route2", rc.Guardrails["claude"].RouteName)
+		require.Same(t, handler, rc.Guardrails["claude"].LLMHandler)
+	})
+}
+
+type dummyBackendAuthHandler struct{}
+
+func (*dummyBackendAuthHandler) Do(context.Context, map[string]string, []byte) ([]internalapi.Header, error) {
+	return nil, nil
 }
@@ -0,0 +1,146 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package guardrail defines the protocol of the external guard services checking the prompts and the completions,
+// either an HTTP webhook or a safety classifier model such as Llama Guard.
+package guardrail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// Stage is the stage of the request that is checked.
+type Stage string
+
+const (
+	// StagePrompt is the check of the prompt before the request is sent to the backend.
+	StagePrompt Stage = "prompt"
+	// StageCompletion is the check of the completion before it is returned to the client.
+	StageCompletion Stage = "completion"
+)
+
+// Message is a message of the prompt normalized to its role and text.
+type Message struct {
+	// Role is the role of the author of the message, e.g. "system", "user", "assistant" or "tool".
+	Role string `json:"role"`
+	// Content is the text of the message.
+	Content string `json:"content"`
+}
+
+// Request is the request sent to the guard service.
+type Request struct {
+	// Stage is the stage of the check.
+	Stage Stage `json:"stage"`
+	// Route is the name of the AIGatewayRoute in the "namespace/name" format.
+	Route string `json:"route"`
+	// Model is the model of the request.
+	Model string `json:"model"`
+	// Messages is the prompt.
+	Messages []Message `json:"messages"`
+	// Completion is the text of the completion so far. Only set at the completion stage.
+	Completion string `json:"completion,omitempty"`
+}
+
+// Action is what is done to the request according to the verdict of the guard service.
+type Action string
+
+const (
+	// ActionPass lets the request pass.
+	ActionPass Action = "pass"
+	// ActionBlock rejects the request.
+	ActionBlock Action = "block"
+	// ActionAnnotate lets the request pass, and annotates it with the reason and the metadata of the verdict in the
+	// dynamic metadata.
+	ActionAnnotate Action = "annotate"
+)
+
+// Verdict is the response of the guard service.
+type Verdict struct {
+	// Action is what is done to the request.
+	Action Action `json:"action"`
+	// Reason is the reason of the verdict, returned to the client when the request is blocked.
+	Reason string `json:"reason,omitempty"`
+	// Metadata is the additional metadata of the verdict, added to the dynamic metadata when the request is annotated.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// CallWebhook sends the request to the webhook at the URL, and returns its verdict.
+func CallWebhook(ctx context.Context, client *http.Client, url string, req *Request) (*Verdict, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the guardrail request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the request: %w", err)
+	}
+	httpReq.Header.Set("content-type", "application/json")
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send the request: %w", err)
+	}
+	defer httpResp.Body.Close()
+	raw, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from the webhook: %s", httpResp.StatusCode, raw)
+	}
+	var verdict Verdict
+	if err = json.Unmarshal(raw, &verdict); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the verdict: %w", err)
+	}
+	switch verdict.Action {
+	case ActionPass, ActionBlock, ActionAnnotate:
+		return &verdict, nil
+	default:
+		return nil, fmt.Errorf("unknown action %q in the verdict", verdict.Action)
+	}
+}
+
+// Transcript returns the messages of the request as a single text, one "role: content" paragraph per message.
+// This is the prompt given to the safety classifier models, which only take the alternating user and assistant
+// messages.
+func (r *Request) Transcript() string {
+	var b strings.Builder
+	for i, m := range r.Messages {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(m.Role)
+		b.WriteString(": ")
+		b.WriteString(m.Content)
+	}
+	return b.String()
+}
+
+// ParseClassifierOutput returns the verdict of the output of a safety classifier model in the format of Llama Guard:
+// "safe", or "unsafe" followed by a line with the violated categories, e.g. "unsafe\nS1,S10". The unsafe texts get
+// the unsafeAction with the categories as the reason.
+func ParseClassifierOutput(output string, unsafeAction Action) (*Verdict, error) {
+	verdict, categories, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	switch strings.ToLower(strings.TrimSpace(verdict)) {
+	case "safe":
+		return &Verdict{Action: ActionPass}, nil
+	case "unsafe":
+		categories = strings.TrimSpace(categories)
+		v := &Verdict{Action: unsafeAction, Reason: "unsafe content"}
+		if categories != "" {
+			v.Reason += ": " + categories
+			v.Metadata = map[string]string{"categories": categories}
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unexpected output of the classifier: %q", output)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package guardrail
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallWebhook(t *testing.T) {
+	req := &Request{
+		Stage:    StagePrompt,
+		Route:    "ns/route",
+		Model:    "gpt-4o",
+		Messages: []Message{{Role: "user", Content: "Hi"}},
+	}
+	for _, tc := range []struct {
+		name       string
+		status     int
+		response   string
+		expVerdict *Verdict
+		expErr     string
+	}{
+		{name: "pass", status: 200, response: `{"action":"pass"}`, expVerdict: &Verdict{Action: ActionPass}},
+		{
+			name:       "block",
+			status:     200,
+			response:   `{"action":"block","reason":"jailbreak"}`,
+			expVerdict: &Verdict{Action: ActionBlock, Reason: "jailbreak"},
+		},
+		{
+			name:       "annotate",
+			status:     200,
+			response:   `{"action":"annotate","reason":"toxic","metadata":{"score":"0.7"}}`,
+			expVerdict: &Verdict{Action: ActionAnnotate, Reason: "toxic", Metadata: map[string]string{"score": "0.7"}},
+		},
+		{name: "unknown action", status: 200, response: `{"action":"allow"}`, expErr: `unknown action "allow" in the verdict`},
+		{name: "invalid json", status: 200, response: `{`, expErr: "failed to unmarshal the verdict"},
+		{name: "error status", status: 503, response: "unavailable", expErr: "unexpected status 503 from the webhook: unavailable"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.JSONEq(t, `{"stage":"prompt","route":"ns/route","model":"gpt-4o","messages":[{"role":"user","content":"Hi"}]}`, string(body))
+				require.Equal(t, "application/json", r.Header.Get("content-type"))
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer srv.Close()
+
+			verdict, err := CallWebhook(t.Context(), srv.Client(), srv.URL, req)
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expVerdict, verdict)
+		})
+	}
+}
+
+func TestRequest_Transcript(t *testing.T) {
+	req := &Request{Messages: []Message{{Role: "system", Content: "Be nice."}, {Role: "user", Content: "Hi"}}}
+	require.Equal(t, "system: Be nice.\n\nuser: Hi", req.Transcript())
+}
+
+func TestParseClassifierOutput(t *testing.T) {
+	for _, tc := range []struct {
+		output     string
+		expVerdict *Verdict
+		expErr     string
+	}{
+		{output: "safe", expVerdict: &Verdict{Action: ActionPass}},
+		{output: "\n\nsafe\n", expVerdict: &Verdict{Action: ActionPass}},
+		{
+			output:     "unsafe\nS1,S10",
+			expVerdict: &Verdict{Action: ActionBlock, Reason: "unsafe content: S1,S10", Metadata: map[string]string{"categories": "S1,S10"}},
+		},
+		{output: "Unsafe", expVerdict: &Verdict{Action: ActionBlock, Reason: "unsafe content"}},
+		{output: "I cannot help with that.", expErr: `unexpected output of the classifier: "I cannot help with that."`},
+	} {
+		t.Run(tc.output, func(t *testing.T) {
+			verdict, err := ParseClassifierOutput(tc.output, ActionBlock)
+			if tc.expErr != "" {
+				require.EqualError(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expVerdict, verdict)
+		})
+	}
+}
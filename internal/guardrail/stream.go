@@ -0,0 +1,109 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package guardrail
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// StreamWindow holds back the server-sent events of a streaming response until the completion in them is checked.
+//
+// The events are released in windows: the completion is checked whenever it has grown by the size of the window
+// since the previous check, and the events held back until then are released if it passes. This keeps the response
+// streamed while the client never receives a text that has not been checked.
+type StreamWindow struct {
+	// text identifies the text deltas in the data of the events, the same as pii.StreamTextFunc.
+	text func(data []byte) (key, path string, end bool)
+	// size is the number of bytes of the completion checked at once. Zero holds the whole response back.
+	size int
+	// buf is the incomplete event at the end of the previous chunk.
+	buf []byte
+	// held is the complete events held back.
+	held []byte
+	// completion is the text of the completion so far.
+	completion strings.Builder
+	// checked is the length of the completion already checked.
+	checked int
+	// done is true once the end of the stream has been received.
+	done bool
+}
+
+// NewStreamWindow creates a StreamWindow of the size, in bytes of the completion. text identifies the text deltas in
+// the data of the events.
+func NewStreamWindow(size int, text func(data []byte) (key, path string, end bool)) *StreamWindow {
+	return &StreamWindow{text: text, size: size}
+}
+
+// Write adds the chunk of the response. It returns true if the completion has to be checked before the held back
+// events are released, that is when it has grown by the size of the window or the stream has ended.
+func (w *StreamWindow) Write(chunk []byte, endOfStream bool) bool {
+	w.buf = append(w.buf, chunk...)
+	for {
+		i := bytes.Index(w.buf, []byte("\n\n"))
+		if i < 0 {
+			break
+		}
+		w.addEvent(w.buf[:i+2])
+		w.buf = w.buf[i+2:]
+	}
+	if endOfStream {
+		if len(w.buf) > 0 {
+			w.addEvent(w.buf)
+			w.buf = nil
+		}
+		w.done = true
+	}
+	unchecked := w.completion.Len() - w.checked
+	return unchecked > 0 && (w.done || (w.size > 0 && unchecked >= w.size))
+}
+
+// Completion returns the completion so far to be checked, and marks it as checked.
+func (w *StreamWindow) Completion() string {
+	w.checked = w.completion.Len()
+	return w.completion.String()
+}
+
+// Release returns the held back events if their completion has been checked, or the empty body otherwise.
+func (w *StreamWindow) Release() []byte {
+	if w.completion.Len() > w.checked {
+		return []byte{}
+	}
+	out := w.held
+	w.held = nil
+	if out == nil {
+		out = []byte{}
+	}
+	return out
+}
+
+// addEvent holds back the event, and appends its text delta to the completion.
+func (w *StreamWindow) addEvent(event []byte) {
+	w.held = append(w.held, event...)
+	data := eventData(event)
+	if data == nil {
+		return
+	}
+	if bytes.Equal(data, []byte("[DONE]")) {
+		w.done = true
+		return
+	}
+	if _, path, _ := w.text(data); path != "" {
+		w.completion.WriteString(gjson.GetBytes(data, path).String())
+	}
+}
+
+// eventData returns the data of the first "data:" line of the event, or nil if there is none.
+func eventData(event []byte) []byte {
+	for line := range bytes.Lines(event) {
+		if data, ok := bytes.CutPrefix(bytes.TrimRight(line, "\r\n"), []byte("data:")); ok {
+			return bytes.TrimPrefix(data, []byte(" "))
+		}
+	}
+	return nil
+}
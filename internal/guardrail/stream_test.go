@@ -0,0 +1,75 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package guardrail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+// chatStreamText identifies the text deltas of the chat completion chunks.
+func chatStreamText(data []byte) (key, path string, end bool) {
+	choice := gjson.GetBytes(data, "choices.0")
+	if !choice.Exists() {
+		return "", "", false
+	}
+	if choice.Get("delta.content").Type == gjson.String {
+		path = "choices.0.delta.content"
+	}
+	return choice.Get("index").String(), path, choice.Get("finish_reason").Type == gjson.String
+}
+
+func TestStreamWindow(t *testing.T) {
+	chunk := func(content string) string {
+		return `data: {"choices":[{"index":0,"delta":{"content":"` + content + `"}}]}` + "\n\n"
+	}
+	const done = "data: [DONE]\n\n"
+
+	t.Run("window", func(t *testing.T) {
+		w := NewStreamWindow(10, chatStreamText)
+		require.False(t, w.Write([]byte(chunk("Hello")), false))
+		require.Empty(t, w.Release())
+		require.True(t, w.Write([]byte(chunk(", world!")), false))
+		require.Equal(t, "Hello, world!", w.Completion())
+		require.Equal(t, chunk("Hello")+chunk(", world!"), string(w.Release()))
+
+		require.False(t, w.Write([]byte(chunk(" Bye")), false))
+		require.Empty(t, w.Release())
+		require.True(t, w.Write([]byte(done), false))
+		require.Equal(t, "Hello, world! Bye", w.Completion())
+		require.Equal(t, chunk(" Bye")+done, string(w.Release()))
+		require.False(t, w.Write(nil, true))
+		require.Empty(t, w.Release())
+	})
+
+	t.Run("whole response", func(t *testing.T) {
+		w := NewStreamWindow(0, chatStreamText)
+		require.False(t, w.Write([]byte(chunk("Hello, world! This is long enough.")), false))
+		require.Empty(t, w.Release())
+		require.True(t, w.Write([]byte(chunk("!")), true))
+		require.Equal(t, "Hello, world! This is long enough.!", w.Completion())
+		require.Equal(t, chunk("Hello, world! This is long enough.")+chunk("!"), string(w.Release()))
+	})
+
+	t.Run("events without text", func(t *testing.T) {
+		w := NewStreamWindow(10, chatStreamText)
+		role := `data: {"choices":[{"index":0,"delta":{"role":"assistant"}}]}` + "\n\n"
+		require.False(t, w.Write([]byte(role), false))
+		require.Equal(t, role, string(w.Release()))
+	})
+
+	t.Run("split event", func(t *testing.T) {
+		w := NewStreamWindow(5, chatStreamText)
+		event := chunk("Hello, world!")
+		require.False(t, w.Write([]byte(event[:20]), false))
+		require.Empty(t, w.Release())
+		require.True(t, w.Write([]byte(event[20:]), false))
+		require.Equal(t, "Hello, world!", w.Completion())
+		require.Equal(t, event, string(w.Release()))
+	})
+}
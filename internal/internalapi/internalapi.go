@@ -8,6 +8,7 @@
 package internalapi
 
 import (
+	"encoding/base64"
 	"fmt"
 	"maps"
 	"slices"
@@ -181,6 +182,10 @@ type EndpointPrefixes struct {
 	Cohere string
 	// Anthropic defaults to "/anthropic"
 	Anthropic string
+	// Gemini defaults to "/gemini"
+	Gemini string
+	// AWSBedrock defaults to "/bedrock"
+	AWSBedrock string
 }
 
 // ParseEndpointPrefixes parses a comma-separated list of key:value pairs to populate EndpointPrefixes.
@@ -189,17 +194,21 @@ type EndpointPrefixes struct {
 //   - openai
 //   - cohere
 //   - anthropic
+//   - gemini
+//   - bedrock
 //
 // Format example:
 //
-//	"openai:/,cohere:/cohere,anthropic:/anthropic"
+//	"openai:/,cohere:/cohere,anthropic:/anthropic,gemini:/gemini,bedrock:/bedrock"
 //
 // Unknown keys cause an error; values must be non-empty.
 func ParseEndpointPrefixes(s string) (EndpointPrefixes, error) {
 	out := EndpointPrefixes{
-		OpenAI:    "/",
-		Cohere:    "/cohere",
-		Anthropic: "/anthropic",
+		OpenAI:     "/",
+		Cohere:     "/cohere",
+		Anthropic:  "/anthropic",
+		Gemini:     "/gemini",
+		AWSBedrock: "/bedrock",
 	}
 	if s == "" {
 		return out, nil
@@ -227,8 +236,12 @@ func ParseEndpointPrefixes(s string) (EndpointPrefixes, error) {
 			out.Cohere = value
 		case "anthropic":
 			out.Anthropic = value
+		case "gemini":
+			out.Gemini = value
+		case "bedrock":
+			out.AWSBedrock = value
 		default:
-			return EndpointPrefixes{}, fmt.Errorf("unknown endpointPrefixes key %q at position %d (allowed: openai, cohere, anthropic)", key, i+1)
+			return EndpointPrefixes{}, fmt.Errorf("unknown endpointPrefixes key %q at position %d (allowed: openai, cohere, anthropic, gemini, bedrock)", key, i+1)
 		}
 	}
 	return out, nil
@@ -350,3 +363,42 @@ type ResponseModel = string
 // Aliased from aigv1b1.AIGatewayFilterMetadataNamespace to avoid making ExtProc directly depend
 // on the control plane API which is not a concern of ExtProc.
 const AIGatewayFilterMetadataNamespace = aigv1b1.AIGatewayFilterMetadataNamespace
+
+const (
+	// BatchMetadataModel is the key of the batch metadata with the model of the batch. This is used for routing when
+	// the input file is not uploaded through the gateway, e.g. the input is already in the storage of the provider.
+	BatchMetadataModel = "model"
+	// BatchMetadataOutputURI is the key of the batch metadata with the storage URI where the provider writes
+	// the output of the batch. This is rejected for the providers keeping the batches in their own storage since
+	// the output location is configured on the backend.
+	BatchMetadataOutputURI = "output_uri"
+	// BatchMetadataRoleARN is the key of the batch metadata with the ARN of the service role used by AWS Bedrock to
+	// access the input and output of the batch. This is rejected since the service role is configured on the backend.
+	BatchMetadataRoleARN = "role_arn"
+)
+
+// EncodeModelScopedID returns the ID of a resource created by a backend, such as a file or a batch, with the model
+// of the request encoded in it. This allows the subsequent requests on the resource, which have no body, to be
+// routed to the same backend by the model. The ID is of the form {prefix}{base64url(model + "\x00" + id)}, so
+// that the prefix of the IDs of the OpenAI API is kept, e.g. "file-" or "batch_".
+func EncodeModelScopedID(prefix, model, id string) string {
+	return prefix + base64.RawURLEncoding.EncodeToString([]byte(model+"\x00"+id))
+}
+
+// DecodeModelScopedID returns the model and the backend ID of the ID encoded by [EncodeModelScopedID],
+// or false if the ID is not a model scoped ID with the given prefix.
+func DecodeModelScopedID(prefix, scopedID string) (model, id string, ok bool) {
+	encoded, ok := strings.CutPrefix(scopedID, prefix)
+	if !ok {
+		return "", "", false
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", false
+	}
+	model, id, ok = strings.Cut(string(decoded), "\x00")
+	if !ok || model == "" || id == "" {
+		return "", "", false
+	}
+	return model, id, true
+}
@@ -6,6 +6,7 @@
 package internalapi
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,12 +14,14 @@ import (
 )
 
 func TestParseEndpointPrefixes_Success(t *testing.T) {
-	in := "openai:/foo,cohere:/1/2/3,anthropic:/cat"
+	in := "openai:/foo,cohere:/1/2/3,anthropic:/cat,gemini:/dog,bedrock:/rock"
 	ep, err := ParseEndpointPrefixes(in)
 	require.NoError(t, err)
 	require.Equal(t, "/foo", ep.OpenAI)
 	require.Equal(t, "/1/2/3", ep.Cohere)
 	require.Equal(t, "/cat", ep.Anthropic)
+	require.Equal(t, "/dog", ep.Gemini)
+	require.Equal(t, "/rock", ep.AWSBedrock)
 }
 
 func TestParseEndpointPrefixes_EmptyInput(t *testing.T) {
@@ -27,6 +30,8 @@ func TestParseEndpointPrefixes_EmptyInput(t *testing.T) {
 	require.Equal(t, "/", ep.OpenAI)
 	require.Equal(t, "/cohere", ep.Cohere)
 	require.Equal(t, "/anthropic", ep.Anthropic)
+	require.Equal(t, "/gemini", ep.Gemini)
+	require.Equal(t, "/bedrock", ep.AWSBedrock)
 }
 
 func TestParseEndpointPrefixes_UnknownKey(t *testing.T) {
@@ -265,3 +270,29 @@ func TestFormatRequestHeaderAttributeMapping(t *testing.T) {
 		})
 	}
 }
+
+func TestModelScopedID(t *testing.T) {
+	scoped := EncodeModelScopedID("batch_", "gpt-4o-mini", "batch_abc123")
+	require.True(t, strings.HasPrefix(scoped, "batch_"))
+	model, id, ok := DecodeModelScopedID("batch_", scoped)
+	require.True(t, ok)
+	require.Equal(t, "gpt-4o-mini", model)
+	require.Equal(t, "batch_abc123", id)
+
+	// The backend IDs can contain any character, such as the ARNs of AWS Bedrock.
+	arn := "arn:aws:bedrock:us-east-1:123456789012:model-invocation-job/abc"
+	model, id, ok = DecodeModelScopedID("batch_", EncodeModelScopedID("batch_", "anthropic.claude-3-haiku", arn))
+	require.True(t, ok)
+	require.Equal(t, "anthropic.claude-3-haiku", model)
+	require.Equal(t, arn, id)
+
+	for _, invalid := range []string{
+		"file-" + scoped[len("batch_"):], // Different prefix.
+		"batch_abc123",                   // Backend ID.
+		"batch_!!!",                      // Invalid base64.
+		"batch_" + "Z3B0LTRv",            // No separator.
+	} {
+		_, _, ok = DecodeModelScopedID("batch_", invalid)
+		require.False(t, ok, invalid)
+	}
+}
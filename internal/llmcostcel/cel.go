@@ -13,6 +13,8 @@ import (
 	"fmt"
 
 	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
 )
 
 const (
@@ -25,13 +27,145 @@ const (
 	celOutputTokensKey             = "output_tokens"
 	celTotalTokensKey              = "total_tokens"
 	celReasoningTokensKey          = "reasoning_tokens"
+	celAudioDurationSecondsKey     = "audio_duration_seconds"
+	celRequestHeadersKey           = "request_headers"
+	celStreamKey                   = "stream"
+	celOperationKey                = "operation"
+	celResponseModelKey            = "response_model"
+	celImageCountKey               = "image_count"
+	celAudioCountKey               = "audio_count"
+	celToolCallCountKey            = "tool_call_count"
+	celCostKey                     = "cost"
+
+	celPriceFunction = "price"
+)
+
+// The units of the PriceTable that are used by [PriceTable.Cost].
+const (
+	// PriceUnitInput is the unit of the input tokens that are neither read from nor written to the cache.
+	PriceUnitInput = "input"
+	// PriceUnitCachedInput is the unit of the input tokens read from the cache. Defaults to the input price.
+	PriceUnitCachedInput = "cached_input"
+	// PriceUnitCacheCreationInput is the unit of the input tokens written to the cache. Defaults to the input price.
+	PriceUnitCacheCreationInput = "cache_creation_input"
+	// PriceUnitOutput is the unit of the output tokens that are not reasoning tokens.
+	PriceUnitOutput = "output"
+	// PriceUnitReasoning is the unit of the reasoning tokens. Defaults to the output price.
+	PriceUnitReasoning = "reasoning"
+	// PriceUnitImage is the unit of the generated images.
+	PriceUnitImage = "image"
+	// PriceUnitAudio is the unit of the seconds of the input audio.
+	PriceUnitAudio = "audio"
+	// PriceUnitAudioOutput is the unit of the generated audio outputs, e.g. a speech.
+	PriceUnitAudioOutput = "audio_output"
 )
 
+// Variables are the values of the variables available to the CEL expressions.
+type Variables struct {
+	// Model is the model name of the request, after the overrides are applied.
+	Model string
+	// Backend is the name of the backend that served the request.
+	Backend string
+	// RouteName is the name of the route that the request matched.
+	RouteName string
+	// InputTokens is the number of input tokens.
+	InputTokens uint32
+	// CachedInputTokens is the number of input tokens read from the cache.
+	CachedInputTokens uint32
+	// CacheCreationInputTokens is the number of input tokens written to the cache.
+	CacheCreationInputTokens uint32
+	// OutputTokens is the number of output tokens.
+	OutputTokens uint32
+	// TotalTokens is the number of total tokens.
+	TotalTokens uint32
+	// ReasoningTokens is the number of reasoning tokens.
+	ReasoningTokens uint32
+	// AudioDurationSeconds is the duration of the input audio in seconds.
+	AudioDurationSeconds uint32
+	// RequestHeaders are the headers of the request. Nil is treated as empty.
+	RequestHeaders map[string]string
+	// Stream is true if the response is streamed.
+	Stream bool
+	// Operation is the name of the operation, i.e. the endpoint, e.g. "chat" or "embeddings".
+	Operation string
+	// ResponseModel is the model that generated the response as reported by the backend.
+	ResponseModel string
+	// ImageCount is the number of generated images.
+	ImageCount uint32
+	// AudioCount is the number of generated audio outputs.
+	AudioCount uint32
+	// ToolCallCount is the number of tool calls in the response.
+	ToolCallCount uint32
+	// Cost is the cost of the request calculated from the price table of the gateway. Zero if the model has no price.
+	Cost float64
+}
+
+// PriceTable is the price of each unit of each model returned by the price(model, unit) function of the CEL
+// expressions. The outer key is the model name, and the inner key is the unit, e.g. "input" or "output".
+type PriceTable map[string]map[string]float64
+
+// Cost returns the cost of the request with the given variables and whether the model has a price in the table.
+// The price of the response model takes precedence over the one of the request model since it is usually more specific,
+// e.g. "gpt-4o-2024-08-06" instead of "gpt-4o".
+//
+// The input tokens include the cached and the cache creation input tokens, and the output tokens include the
+// reasoning tokens, so each of them is charged once at the price of its own unit, falling back to the input or
+// output price respectively.
+func (p PriceTable) Cost(vars *Variables) (cost float64, ok bool) {
+	prices, ok := p[vars.ResponseModel]
+	if !ok {
+		if prices, ok = p[vars.Model]; !ok {
+			return 0, false
+		}
+	}
+	priceOr := func(unit, fallback string) float64 {
+		if v, ok := prices[unit]; ok {
+			return v
+		}
+		return prices[fallback]
+	}
+
+	cached, cacheCreation := float64(vars.CachedInputTokens), float64(vars.CacheCreationInputTokens)
+	input := max(float64(vars.InputTokens)-cached-cacheCreation, 0)
+	reasoning := float64(vars.ReasoningTokens)
+	output := max(float64(vars.OutputTokens)-reasoning, 0)
+	cost = input*prices[PriceUnitInput] +
+		cached*priceOr(PriceUnitCachedInput, PriceUnitInput) +
+		cacheCreation*priceOr(PriceUnitCacheCreationInput, PriceUnitInput) +
+		output*prices[PriceUnitOutput] +
+		reasoning*priceOr(PriceUnitReasoning, PriceUnitOutput) +
+		float64(vars.ImageCount)*prices[PriceUnitImage] +
+		float64(vars.AudioDurationSeconds)*prices[PriceUnitAudio] +
+		float64(vars.AudioCount)*prices[PriceUnitAudioOutput]
+	return cost, true
+}
+
+// WithOverrides returns a new price table with the models of overrides replacing the ones of this table, e.g. the
+// prices of a backend taking precedence over the ones of the gateway. This returns the table itself if there are no
+// overrides.
+func (p PriceTable) WithOverrides(overrides PriceTable) PriceTable {
+	if len(overrides) == 0 {
+		return p
+	}
+	merged := make(PriceTable, len(p)+len(overrides))
+	for model, prices := range p {
+		merged[model] = prices
+	}
+	for model, prices := range overrides {
+		merged[model] = prices
+	}
+	return merged
+}
+
 var env *cel.Env
 
 func init() {
-	var err error
-	env, err = cel.NewEnv(
+	env = newEnv(nil)
+}
+
+// newEnv creates a new CEL environment whose price function looks up the given table.
+func newEnv(prices PriceTable) *cel.Env {
+	e, err := cel.NewEnv(
 		cel.Variable(celModelNameKey, cel.StringType),
 		cel.Variable(celBackendKey, cel.StringType),
 		cel.Variable(celRouteNameKey, cel.StringType),
@@ -41,26 +175,54 @@ func init() {
 		cel.Variable(celOutputTokensKey, cel.UintType),
 		cel.Variable(celTotalTokensKey, cel.UintType),
 		cel.Variable(celReasoningTokensKey, cel.UintType),
+		cel.Variable(celAudioDurationSecondsKey, cel.UintType),
+		cel.Variable(celRequestHeadersKey, cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable(celStreamKey, cel.BoolType),
+		cel.Variable(celOperationKey, cel.StringType),
+		cel.Variable(celResponseModelKey, cel.StringType),
+		cel.Variable(celImageCountKey, cel.UintType),
+		cel.Variable(celAudioCountKey, cel.UintType),
+		cel.Variable(celToolCallCountKey, cel.UintType),
+		cel.Variable(celCostKey, cel.DoubleType),
+		cel.Function(celPriceFunction,
+			cel.Overload("price_string_string", []*cel.Type{cel.StringType, cel.StringType}, cel.DoubleType,
+				cel.BinaryBinding(func(model, unit ref.Val) ref.Val {
+					// Unknown models and units are free so that a single expression can be used for all models.
+					return types.Double(prices[string(model.(types.String))][string(unit.(types.String))])
+				}),
+			),
+		),
 	)
 	if err != nil {
 		panic(fmt.Sprintf("cannot create CEL environment: %v", err))
 	}
+	return e
 }
 
 // NewProgram creates a new CEL program from the given expression.
 func NewProgram(expr string) (prog cel.Program, err error) {
-	ast, issues := env.Compile(expr)
+	return newProgram(env, expr)
+}
+
+// NewProgramWithPriceTable creates a new CEL program from the given expression whose price function
+// looks up the given table.
+func NewProgramWithPriceTable(expr string, prices PriceTable) (prog cel.Program, err error) {
+	return newProgram(newEnv(prices), expr)
+}
+
+func newProgram(e *cel.Env, expr string) (prog cel.Program, err error) {
+	ast, issues := e.Compile(expr)
 	if issues != nil && issues.Err() != nil {
 		err = issues.Err()
 		return nil, fmt.Errorf("cannot compile CEL expression: %w", err)
 	}
-	prog, err = env.Program(ast)
+	prog, err = e.Program(ast)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create CEL program: %w", err)
 	}
 
 	// Sanity check by evaluating the expression with some dummy values.
-	_, err = EvaluateProgram(prog, "dummy", "dummy", "dummy", 0, 0, 0, 0, 0, 0)
+	_, err = EvaluateProgram(prog, Variables{Model: "dummy", Backend: "dummy", RouteName: "dummy", Operation: "dummy", ResponseModel: "dummy"})
 	if err != nil {
 		return nil, fmt.Errorf("failed to evaluate CEL expression: %w", err)
 	}
@@ -68,17 +230,30 @@ func NewProgram(expr string) (prog cel.Program, err error) {
 }
 
 // EvaluateProgram evaluates the given CEL program with the given variables.
-func EvaluateProgram(prog cel.Program, modelName, backend, routeName string, inputTokens, cachedInputTokens, cacheCreationInputTokens, outputTokens, totalTokens, reasoningTokens uint32) (uint64, error) {
+func EvaluateProgram(prog cel.Program, vars Variables) (uint64, error) {
+	requestHeaders := vars.RequestHeaders
+	if requestHeaders == nil {
+		requestHeaders = map[string]string{}
+	}
 	out, _, err := prog.Eval(map[string]any{
-		celModelNameKey:                modelName,
-		celBackendKey:                  backend,
-		celRouteNameKey:                routeName,
-		celInputTokensKey:              inputTokens,
-		celCachedInputTokensKey:        cachedInputTokens,
-		celCacheCreationInputTokensKey: cacheCreationInputTokens,
-		celOutputTokensKey:             outputTokens,
-		celTotalTokensKey:              totalTokens,
-		celReasoningTokensKey:          reasoningTokens,
+		celModelNameKey:                vars.Model,
+		celBackendKey:                  vars.Backend,
+		celRouteNameKey:                vars.RouteName,
+		celInputTokensKey:              vars.InputTokens,
+		celCachedInputTokensKey:        vars.CachedInputTokens,
+		celCacheCreationInputTokensKey: vars.CacheCreationInputTokens,
+		celOutputTokensKey:             vars.OutputTokens,
+		celTotalTokensKey:              vars.TotalTokens,
+		celReasoningTokensKey:          vars.ReasoningTokens,
+		celAudioDurationSecondsKey:     vars.AudioDurationSeconds,
+		celRequestHeadersKey:           requestHeaders,
+		celStreamKey:                   vars.Stream,
+		celOperationKey:                vars.Operation,
+		celResponseModelKey:            vars.ResponseModel,
+		celImageCountKey:               vars.ImageCount,
+		celAudioCountKey:               vars.AudioCount,
+		celToolCallCountKey:            vars.ToolCallCount,
+		celCostKey:                     vars.Cost,
 	})
 	if err != nil || out == nil {
 		return 0, fmt.Errorf("failed to evaluate CEL expression: %w", err)
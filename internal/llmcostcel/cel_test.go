@@ -28,11 +28,11 @@ func TestNewProgram(t *testing.T) {
 	t.Run("variables", func(t *testing.T) {
 		prog, err := NewProgram("model == 'cool_model' ?  (input_tokens - cached_input_tokens - cache_creation_input_tokens) * output_tokens  : total_tokens")
 		require.NoError(t, err)
-		v, err := EvaluateProgram(prog, "cool_model", "cool_backend", "cool_route", 200, 100, 1, 2, 3, 0)
+		v, err := EvaluateProgram(prog, Variables{Model: "cool_model", Backend: "cool_backend", RouteName: "cool_route", InputTokens: 200, CachedInputTokens: 100, CacheCreationInputTokens: 1, OutputTokens: 2, TotalTokens: 3})
 		require.NoError(t, err)
 		require.Equal(t, uint64(198), v)
 
-		v, err = EvaluateProgram(prog, "not_cool_model", "cool_backend", "cool_route", 200, 100, 1, 2, 3, 0)
+		v, err = EvaluateProgram(prog, Variables{Model: "not_cool_model", Backend: "cool_backend", RouteName: "cool_route", InputTokens: 200, CachedInputTokens: 100, CacheCreationInputTokens: 1, OutputTokens: 2, TotalTokens: 3})
 		require.NoError(t, err)
 		require.Equal(t, uint64(3), v)
 	})
@@ -59,22 +59,80 @@ func TestEvaluateProgram(t *testing.T) {
 	t.Run("signed integer negative", func(t *testing.T) {
 		prog, err := NewProgram("int(input_tokens) - int(output_tokens)")
 		require.NoError(t, err)
-		_, err = EvaluateProgram(prog, "cool_model", "cool_backend", "cool_route", 100, 0, 0, 2000, 3, 0)
+		_, err = EvaluateProgram(prog, Variables{Model: "cool_model", Backend: "cool_backend", RouteName: "cool_route", InputTokens: 100, OutputTokens: 2000, TotalTokens: 3})
 		require.ErrorContains(t, err, "CEL expression result is negative (-1900)")
 	})
 	t.Run("unsigned integer overflow", func(t *testing.T) {
 		prog, err := NewProgram("input_tokens - output_tokens")
 		require.NoError(t, err)
-		_, err = EvaluateProgram(prog, "cool_model", "cool_backend", "cool_route", 100, 0, 0, 2000, 3, 0)
+		_, err = EvaluateProgram(prog, Variables{Model: "cool_model", Backend: "cool_backend", RouteName: "cool_route", InputTokens: 100, OutputTokens: 2000, TotalTokens: 3})
 		require.ErrorContains(t, err, "failed to evaluate CEL expression: unsigned integer overflow")
 	})
 	t.Run("reasoning_tokens variable", func(t *testing.T) {
 		prog, err := NewProgram("output_tokens + reasoning_tokens")
 		require.NoError(t, err)
-		v, err := EvaluateProgram(prog, "cool_model", "cool_backend", "cool_route", 0, 0, 0, 100, 0, 50)
+		v, err := EvaluateProgram(prog, Variables{Model: "cool_model", Backend: "cool_backend", RouteName: "cool_route", OutputTokens: 100, ReasoningTokens: 50})
 		require.NoError(t, err)
 		require.Equal(t, uint64(150), v)
 	})
+	t.Run("audio_duration_seconds variable", func(t *testing.T) {
+		prog, err := NewProgram("model == 'whisper-1' ? audio_duration_seconds * uint(100) : total_tokens")
+		require.NoError(t, err)
+		v, err := EvaluateProgram(prog, Variables{Model: "whisper-1", Backend: "cool_backend", RouteName: "cool_route", AudioDurationSeconds: 62})
+		require.NoError(t, err)
+		require.Equal(t, uint64(6200), v)
+	})
+	t.Run("request variables", func(t *testing.T) {
+		prog, err := NewProgram(`(stream ? uint(10) : uint(0)) + (operation == 'image_generation' ? image_count * uint(100) : uint(0)) +
+			tool_call_count + audio_count * uint(1000) + ('x-tier' in request_headers && request_headers['x-tier'] == 'premium' ? uint(2) : uint(1)) +
+			(response_model == 'gpt-4o-2024-08-06' ? uint(10000) : uint(0))`)
+		require.NoError(t, err)
+		v, err := EvaluateProgram(prog, Variables{
+			RequestHeaders: map[string]string{"x-tier": "premium"},
+			Stream:         true,
+			Operation:      "image_generation",
+			ResponseModel:  "gpt-4o-2024-08-06",
+			ImageCount:     2,
+			AudioCount:     1,
+			ToolCallCount:  3,
+		})
+		require.NoError(t, err)
+		require.Equal(t, uint64(10+200+3+1000+2+10000), v)
+
+		v, err = EvaluateProgram(prog, Variables{})
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), v)
+	})
+	t.Run("price function", func(t *testing.T) {
+		prices := PriceTable{
+			"gpt-4o": {"input": 2.5, "output": 10},
+		}
+		expr := "uint(price(model, 'input') * double(input_tokens) + price(model, 'output') * double(output_tokens))"
+		prog, err := NewProgramWithPriceTable(expr, prices)
+		require.NoError(t, err)
+		v, err := EvaluateProgram(prog, Variables{Model: "gpt-4o", InputTokens: 100, OutputTokens: 10})
+		require.NoError(t, err)
+		require.Equal(t, uint64(350), v)
+
+		// Unknown models are free.
+		v, err = EvaluateProgram(prog, Variables{Model: "unknown", InputTokens: 100, OutputTokens: 10})
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), v)
+
+		// The price function is available without the price table, e.g. for the validation in the controller.
+		prog, err = NewProgram(expr)
+		require.NoError(t, err)
+		v, err = EvaluateProgram(prog, Variables{Model: "gpt-4o", InputTokens: 100, OutputTokens: 10})
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), v)
+	})
+	t.Run("cost variable", func(t *testing.T) {
+		prog, err := NewProgram("uint(cost * 1000000.0)")
+		require.NoError(t, err)
+		v, err := EvaluateProgram(prog, Variables{Cost: 0.0125})
+		require.NoError(t, err)
+		require.Equal(t, uint64(12500), v)
+	})
 	t.Run("ensure concurrency safety", func(t *testing.T) {
 		prog, err := NewProgram("model == 'cool_model' ?  input_tokens * output_tokens : total_tokens")
 		require.NoError(t, err)
@@ -83,7 +141,7 @@ func TestEvaluateProgram(t *testing.T) {
 		synctest.Test(t, func(t *testing.T) {
 			for range 100 {
 				go func() {
-					v, err := EvaluateProgram(prog, "cool_model", "cool_backend", "cool_route", 100, 0, 0, 2, 3, 0)
+					v, err := EvaluateProgram(prog, Variables{Model: "cool_model", Backend: "cool_backend", RouteName: "cool_route", InputTokens: 100, OutputTokens: 2, TotalTokens: 3})
 					require.NoError(t, err)
 					require.Equal(t, uint64(200), v)
 				}()
@@ -91,3 +149,101 @@ func TestEvaluateProgram(t *testing.T) {
 		}) // synctest.Test waits for all goroutines to complete.
 	})
 }
+
+func TestPriceTable_Cost(t *testing.T) {
+	prices := PriceTable{
+		"gpt-4o": {
+			PriceUnitInput:       2.5e-6,
+			PriceUnitCachedInput: 1.25e-6,
+			PriceUnitOutput:      10e-6,
+		},
+		"gpt-4o-2024-08-06": {PriceUnitInput: 1e-6, PriceUnitOutput: 1e-6},
+		"o3": {
+			PriceUnitInput:              2e-6,
+			PriceUnitCacheCreationInput: 4e-6,
+			PriceUnitOutput:             8e-6,
+			PriceUnitReasoning:          16e-6,
+		},
+		"gpt-image-1": {PriceUnitImage: 0.04},
+		"whisper-1":   {PriceUnitAudio: 0.0001},
+		"tts-1":       {PriceUnitAudioOutput: 0.015},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		vars    Variables
+		expCost float64
+		expOk   bool
+	}{
+		{
+			name:    "unknown model",
+			vars:    Variables{Model: "unknown", InputTokens: 100},
+			expCost: 0,
+			expOk:   false,
+		},
+		{
+			name: "cached input falls back to the input price",
+			vars: Variables{Model: "gpt-4o", InputTokens: 1000, CachedInputTokens: 400, CacheCreationInputTokens: 100, OutputTokens: 50},
+			// 500 uncached input tokens, 400 cached input tokens and 100 cache creation tokens at the input price.
+			expCost: 500*2.5e-6 + 400*1.25e-6 + 100*2.5e-6 + 50*10e-6,
+			expOk:   true,
+		},
+		{
+			name:    "response model takes precedence",
+			vars:    Variables{Model: "gpt-4o", ResponseModel: "gpt-4o-2024-08-06", InputTokens: 1000, OutputTokens: 50},
+			expCost: 1000*1e-6 + 50*1e-6,
+			expOk:   true,
+		},
+		{
+			name:    "unknown response model falls back to the request model",
+			vars:    Variables{Model: "o3", ResponseModel: "o3-2025-04-16", InputTokens: 100, CacheCreationInputTokens: 10, OutputTokens: 300, ReasoningTokens: 200},
+			expCost: 90*2e-6 + 10*4e-6 + 100*8e-6 + 200*16e-6,
+			expOk:   true,
+		},
+		{
+			name:    "images",
+			vars:    Variables{Model: "gpt-image-1", ImageCount: 3},
+			expCost: 3 * 0.04,
+			expOk:   true,
+		},
+		{
+			name:    "audio",
+			vars:    Variables{Model: "whisper-1", AudioDurationSeconds: 60},
+			expCost: 60 * 0.0001,
+			expOk:   true,
+		},
+		{
+			name:    "audio output",
+			vars:    Variables{Model: "tts-1", AudioCount: 2},
+			expCost: 2 * 0.015,
+			expOk:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cost, ok := prices.Cost(&tc.vars)
+			require.Equal(t, tc.expOk, ok)
+			require.InDelta(t, tc.expCost, cost, 1e-12)
+		})
+	}
+}
+
+func TestPriceTable_WithOverrides(t *testing.T) {
+	prices := PriceTable{"gpt-4o": {PriceUnitInput: 2.5e-6, PriceUnitOutput: 10e-6}, "o3": {PriceUnitInput: 2e-6}}
+	require.Equal(t, prices, prices.WithOverrides(nil))
+
+	merged := prices.WithOverrides(PriceTable{"gpt-4o": {PriceUnitInput: 2}, "llama": {PriceUnitOutput: 1e-7}})
+	require.Equal(t, PriceTable{
+		"gpt-4o": {PriceUnitInput: 2},
+		"o3":     {PriceUnitInput: 2e-6},
+		"llama":  {PriceUnitOutput: 1e-7},
+	}, merged)
+	// The original table is left untouched.
+	require.Equal(t, 2.5e-6, prices["gpt-4o"][PriceUnitInput])
+
+	// The price function of the CEL expressions sees the merged table.
+	prog, err := NewProgramWithPriceTable(`uint(price(model, "input") * double(input_tokens))`, merged)
+	require.NoError(t, err)
+	v, err := EvaluateProgram(prog, Variables{Model: "gpt-4o", InputTokens: 10})
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), v)
+}
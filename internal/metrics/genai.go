@@ -31,7 +31,15 @@ const (
 	GenAIOperationImageGeneration GenAIOperation = "image_generation"
 	GenAIOperationResponses       GenAIOperation = "responses"
 	GenAIOperationSpeech          GenAIOperation = "speech"
+	GenAIOperationTranscription   GenAIOperation = "transcription"
+	GenAIOperationTranslation     GenAIOperation = "translation"
 	GenAIOperationRerank          GenAIOperation = "rerank"
+	GenAIOperationGenerateContent GenAIOperation = "generate_content"
+	GenAIOperationConverse        GenAIOperation = "converse"
+	GenAIOperationFiles           GenAIOperation = "files"
+	GenAIOperationBatch           GenAIOperation = "batch"
+	GenAIOperationModeration      GenAIOperation = "moderation"
+	GenAIOperationCountTokens     GenAIOperation = "count_tokens"
 
 	// Provider names according to the Semantic Conventions for Generative AI Metrics.
 	// See: https://opentelemetry.io/docs/specs/semconv/attributes-registry/gen-ai/
@@ -54,6 +62,33 @@ const (
 	genaiTokenTypeCacheCreationInput = "cache_creation_input"
 	genaiTokenTypeReasoning          = "reasoning"
 	genaiErrorTypeFallback           = "_OTHER"
+
+	// The quota metrics are not part of the Semantic Conventions, hence the "aigw" prefix.
+
+	aigwMetricQuotaExceeded      = "aigw.quota.exceeded"
+	aigwAttributeQuotaName       = "aigw.quota.name"
+	aigwAttributeQuotaShadowMode = "aigw.quota.shadow_mode"
+
+	// The cost metric is not part of the Semantic Conventions either, but it is named after
+	// gen_ai.client.token.usage since the cost is derived from it.
+
+	genaiMetricClientCost     = "gen_ai.client.cost"
+	aigwAttributeCostCurrency = "aigw.cost.currency"
+
+	aigwMetricResponseCacheLookups   = "aigw.response_cache.lookups"
+	aigwAttributeResponseCacheResult = "aigw.response_cache.result"
+)
+
+// ResponseCacheResult is the result of the lookup of the response cache.
+type ResponseCacheResult string
+
+const (
+	// ResponseCacheMiss is the result of the lookup that found no cached response.
+	ResponseCacheMiss ResponseCacheResult = "miss"
+	// ResponseCacheExactHit is the result of the lookup that found the response to the same request.
+	ResponseCacheExactHit ResponseCacheResult = "exact_hit"
+	// ResponseCacheSemanticHit is the result of the lookup that found the response to a semantically similar request.
+	ResponseCacheSemanticHit ResponseCacheResult = "semantic_hit"
 )
 
 // GenAIOperation represents the type of generative AI operation i.e. the endpoint being called.
@@ -80,6 +115,13 @@ type genAI struct {
 	// Calculated by: (request_duration - time_to_first_token) / (output_tokens - 1)
 	// See: https://opentelemetry.io/docs/specs/semconv/gen-ai/gen-ai-metrics/#metric-gen_aiservertime_per_output_token
 	outputTokenLatency metric.Float64Histogram
+	// quotaExceeded is the number of requests that found a quota bucket exhausted, including the ones
+	// in shadow mode which are not enforced.
+	quotaExceeded metric.Float64Counter
+	// cost is the total cost of the requests calculated from the pricing catalog of the gateway.
+	cost metric.Float64Counter
+	// responseCacheLookups is the number of the lookups of the response cache by their result.
+	responseCacheLookups metric.Float64Counter
 }
 
 // newGenAI creates a new genAI metrics instance.
@@ -109,5 +151,20 @@ func newGenAI(meter metric.Meter) *genAI {
 			metric.WithUnit("s"),
 			metric.WithExplicitBucketBoundaries(0.01, 0.025, 0.05, 0.075, 0.1, 0.15, 0.2, 0.3, 0.4, 0.5, 0.75, 1.0, 2.5),
 		),
+		quotaExceeded: mustRegisterCounter(meter,
+			aigwMetricQuotaExceeded,
+			metric.WithDescription("Number of requests that exceeded a quota bucket, including the ones in shadow mode."),
+			metric.WithUnit("{request}"),
+		),
+		cost: mustRegisterCounter(meter,
+			genaiMetricClientCost,
+			metric.WithDescription("Cost of the requests in the currency of the pricing catalog."),
+			metric.WithUnit("{currency}"),
+		),
+		responseCacheLookups: mustRegisterCounter(meter,
+			aigwMetricResponseCacheLookups,
+			metric.WithDescription("Number of the lookups of the response cache by their result."),
+			metric.WithUnit("{request}"),
+		),
 	}
 }
@@ -115,6 +115,13 @@ type Metrics interface {
 	//
 	// Depending on the endpoint, some token types are not available and should be passed as OptUint32None.
 	RecordTokenUsage(ctx context.Context, usage TokenUsage, requestHeaders map[string]string)
+	// RecordQuotaExceeded records that the request found the quota bucket exhausted.
+	// The shadowMode indicates that the bucket is only observed and the request is not denied by it.
+	RecordQuotaExceeded(ctx context.Context, quotaName string, shadowMode bool, requestHeaders map[string]string)
+	// RecordCost records the cost of the request calculated from the pricing catalog in the given currency.
+	RecordCost(ctx context.Context, cost float64, currency string, requestHeaders map[string]string)
+	// RecordResponseCacheLookup records the result of the lookup of the response cache for the request.
+	RecordResponseCacheLookup(ctx context.Context, result ResponseCacheResult, requestHeaders map[string]string)
 
 	// Streaming-specific metrics methods, not used by all implementations.
 
@@ -130,6 +137,8 @@ type Metrics interface {
 type Factory interface {
 	// NewMetrics creates a new Metrics instance for the specified operation name.
 	NewMetrics() Metrics
+	// Operation returns the operation name of the Metrics instances created by this factory.
+	Operation() GenAIOperation
 }
 
 // NewMetricsFactory returns a Factory to create a new Metrics instance.
@@ -153,8 +162,17 @@ type TokenUsage struct {
 	cacheCreationInputTokens uint32
 	// ReasoningTokens is the number of reasoning tokens consumed.
 	reasoningTokens uint32
+	// AudioDurationSeconds is the duration of the input audio in seconds, used by the speech-to-text endpoints.
+	audioDurationSeconds uint32
+	// imageCount is the number of images generated, used by the image generation endpoints.
+	imageCount uint32
+	// audioCount is the number of audio outputs generated, e.g. the audio messages of the chat completions.
+	audioCount uint32
+	// toolCallCount is the number of tool calls in the response.
+	toolCallCount uint32
 
 	inputTokenSet, outputTokenSet, totalTokenSet, cachedInputTokenSet, cacheCreationInputTokenSet, reasoningTokenSet bool
+	audioDurationSecondsSet, imageCountSet, audioCountSet, toolCallCountSet                                          bool
 }
 
 // InputTokens returns the number of input tokens and whether it was set.
@@ -223,6 +241,50 @@ func (u *TokenUsage) SetReasoningTokens(tokens uint32) {
 	u.reasoningTokenSet = true
 }
 
+// AudioDurationSeconds returns the duration of the input audio in seconds and whether it was set.
+func (u *TokenUsage) AudioDurationSeconds() (uint32, bool) {
+	return u.audioDurationSeconds, u.audioDurationSecondsSet
+}
+
+// SetAudioDurationSeconds sets the duration of the input audio in seconds and marks the field as set.
+func (u *TokenUsage) SetAudioDurationSeconds(seconds uint32) {
+	u.audioDurationSeconds = seconds
+	u.audioDurationSecondsSet = true
+}
+
+// ImageCount returns the number of generated images and whether it was set.
+func (u *TokenUsage) ImageCount() (uint32, bool) {
+	return u.imageCount, u.imageCountSet
+}
+
+// SetImageCount sets the number of generated images and marks the field as set.
+func (u *TokenUsage) SetImageCount(count uint32) {
+	u.imageCount = count
+	u.imageCountSet = true
+}
+
+// AudioCount returns the number of generated audio outputs and whether it was set.
+func (u *TokenUsage) AudioCount() (uint32, bool) {
+	return u.audioCount, u.audioCountSet
+}
+
+// SetAudioCount sets the number of generated audio outputs and marks the field as set.
+func (u *TokenUsage) SetAudioCount(count uint32) {
+	u.audioCount = count
+	u.audioCountSet = true
+}
+
+// ToolCallCount returns the number of tool calls in the response and whether it was set.
+func (u *TokenUsage) ToolCallCount() (uint32, bool) {
+	return u.toolCallCount, u.toolCallCountSet
+}
+
+// SetToolCallCount sets the number of tool calls in the response and marks the field as set.
+func (u *TokenUsage) SetToolCallCount(count uint32) {
+	u.toolCallCount = count
+	u.toolCallCountSet = true
+}
+
 // AddInputTokens increments the recorded input tokens and marks the field as set.
 func (u *TokenUsage) AddInputTokens(tokens uint32) {
 	u.inputTokenSet = true
@@ -280,6 +342,22 @@ func (u *TokenUsage) Override(other TokenUsage) {
 		u.reasoningTokens = other.reasoningTokens
 		u.reasoningTokenSet = true
 	}
+	if other.audioDurationSecondsSet {
+		u.audioDurationSeconds = other.audioDurationSeconds
+		u.audioDurationSecondsSet = true
+	}
+	if other.imageCountSet {
+		u.imageCount = other.imageCount
+		u.imageCountSet = true
+	}
+	if other.audioCountSet {
+		u.audioCount = other.audioCount
+		u.audioCountSet = true
+	}
+	if other.toolCallCountSet {
+		u.toolCallCount = other.toolCallCount
+		u.toolCallCountSet = true
+	}
 }
 
 // ExtractTokenUsageFromExplicitCaching extracts the correct token usage from upstream Anthropic or AWS Bedrock token usage response.
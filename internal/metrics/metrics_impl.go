@@ -36,6 +36,11 @@ func (f *metricsImplFactory) NewMetrics() Metrics {
 	}
 }
 
+// Operation implements [Factory.Operation].
+func (f *metricsImplFactory) Operation() GenAIOperation {
+	return GenAIOperation(f.operation)
+}
+
 // metricsImpl provides shared functionality for AI Gateway metrics implementations.
 //
 // This implements the Metrics interface.
@@ -180,6 +185,36 @@ func (b *metricsImpl) RecordTokenUsage(ctx context.Context, usage TokenUsage, re
 	}
 }
 
+// RecordQuotaExceeded implements [Metrics.RecordQuotaExceeded].
+func (b *metricsImpl) RecordQuotaExceeded(ctx context.Context, quotaName string, shadowMode bool, requestHeaders map[string]string) {
+	attrs := b.buildBaseAttributes(requestHeaders)
+	b.metrics.quotaExceeded.Add(ctx, 1,
+		metric.WithAttributeSet(attrs),
+		metric.WithAttributes(
+			attribute.Key(aigwAttributeQuotaName).String(quotaName),
+			attribute.Key(aigwAttributeQuotaShadowMode).Bool(shadowMode),
+		),
+	)
+}
+
+// RecordCost implements [Metrics.RecordCost].
+func (b *metricsImpl) RecordCost(ctx context.Context, cost float64, currency string, requestHeaders map[string]string) {
+	attrs := b.buildBaseAttributes(requestHeaders)
+	b.metrics.cost.Add(ctx, cost,
+		metric.WithAttributeSet(attrs),
+		metric.WithAttributes(attribute.Key(aigwAttributeCostCurrency).String(currency)),
+	)
+}
+
+// RecordResponseCacheLookup implements [Metrics.RecordResponseCacheLookup].
+func (b *metricsImpl) RecordResponseCacheLookup(ctx context.Context, result ResponseCacheResult, requestHeaders map[string]string) {
+	attrs := b.buildBaseAttributes(requestHeaders)
+	b.metrics.responseCacheLookups.Add(ctx, 1,
+		metric.WithAttributeSet(attrs),
+		metric.WithAttributes(attribute.Key(aigwAttributeResponseCacheResult).String(string(result))),
+	)
+}
+
 // GetTimeToFirstTokenMs implements [Metrics.GetTimeToFirstTokenMs].
 func (b *metricsImpl) GetTimeToFirstTokenMs() float64 {
 	return float64(b.timeToFirstToken.Milliseconds())
@@ -190,6 +190,96 @@ func testRecordRequestCompletion(t *testing.T) {
 	assert.Equal(t, 2*10*time.Millisecond.Seconds(), sum)
 }
 
+func TestRecordQuotaExceeded(t *testing.T) {
+	t.Parallel()
+	var (
+		mr    = metric.NewManualReader()
+		meter = metric.NewMeterProvider(metric.WithReader(mr)).Meter("test")
+		pm    = NewMetricsFactory(meter, nil, GenAIOperationChat).NewMetrics().(*metricsImpl)
+		attrs = []attribute.KeyValue{
+			attribute.Key(genaiAttributeOperationName).String(string(GenAIOperationChat)),
+			attribute.Key(genaiAttributeProviderName).String(genaiProviderOpenAI),
+			attribute.Key(genaiAttributeOriginalModel).String("test-model"),
+			attribute.Key(genaiAttributeRequestModel).String("test-model"),
+			attribute.Key(genaiAttributeResponseModel).String("unknown"),
+		}
+	)
+
+	pm.SetOriginalModel("test-model")
+	pm.SetRequestModel("test-model")
+	pm.SetBackend(&filterapi.Backend{Schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI}})
+	pm.RecordQuotaExceeded(t.Context(), "ns/quota/service", false, nil)
+	pm.RecordQuotaExceeded(t.Context(), "ns/quota/service", false, nil)
+	pm.RecordQuotaExceeded(t.Context(), "ns/quota/rule/0", true, nil)
+
+	require.Equal(t, float64(2), testotel.GetCounterValue(t, mr, aigwMetricQuotaExceeded, attribute.NewSet(append(attrs,
+		attribute.Key(aigwAttributeQuotaName).String("ns/quota/service"),
+		attribute.Key(aigwAttributeQuotaShadowMode).Bool(false),
+	)...)))
+	require.Equal(t, float64(1), testotel.GetCounterValue(t, mr, aigwMetricQuotaExceeded, attribute.NewSet(append(attrs,
+		attribute.Key(aigwAttributeQuotaName).String("ns/quota/rule/0"),
+		attribute.Key(aigwAttributeQuotaShadowMode).Bool(true),
+	)...)))
+}
+
+func TestRecordCost(t *testing.T) {
+	t.Parallel()
+	var (
+		mr    = metric.NewManualReader()
+		meter = metric.NewMeterProvider(metric.WithReader(mr)).Meter("test")
+		pm    = NewMetricsFactory(meter, nil, GenAIOperationChat).NewMetrics().(*metricsImpl)
+		attrs = []attribute.KeyValue{
+			attribute.Key(genaiAttributeOperationName).String(string(GenAIOperationChat)),
+			attribute.Key(genaiAttributeProviderName).String(genaiProviderOpenAI),
+			attribute.Key(genaiAttributeOriginalModel).String("test-model"),
+			attribute.Key(genaiAttributeRequestModel).String("test-model"),
+			attribute.Key(genaiAttributeResponseModel).String("test-model-2025"),
+		}
+	)
+
+	pm.SetOriginalModel("test-model")
+	pm.SetRequestModel("test-model")
+	pm.SetResponseModel("test-model-2025")
+	pm.SetBackend(&filterapi.Backend{Schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI}})
+	pm.RecordCost(t.Context(), 0.25, "USD", nil)
+	pm.RecordCost(t.Context(), 0.5, "USD", nil)
+	pm.RecordCost(t.Context(), 2, "EUR", nil)
+
+	require.Equal(t, 0.75, testotel.GetCounterValue(t, mr, genaiMetricClientCost, attribute.NewSet(append(attrs,
+		attribute.Key(aigwAttributeCostCurrency).String("USD"))...)))
+	require.Equal(t, float64(2), testotel.GetCounterValue(t, mr, genaiMetricClientCost, attribute.NewSet(append(attrs,
+		attribute.Key(aigwAttributeCostCurrency).String("EUR"))...)))
+}
+
+func TestRecordResponseCacheLookup(t *testing.T) {
+	t.Parallel()
+	var (
+		mr    = metric.NewManualReader()
+		meter = metric.NewMeterProvider(metric.WithReader(mr)).Meter("test")
+		pm    = NewMetricsFactory(meter, nil, GenAIOperationChat).NewMetrics().(*metricsImpl)
+		attrs = []attribute.KeyValue{
+			attribute.Key(genaiAttributeOperationName).String(string(GenAIOperationChat)),
+			attribute.Key(genaiAttributeProviderName).String(genaiProviderOpenAI),
+			attribute.Key(genaiAttributeOriginalModel).String("test-model"),
+			attribute.Key(genaiAttributeRequestModel).String("test-model"),
+			attribute.Key(genaiAttributeResponseModel).String("unknown"),
+		}
+	)
+
+	pm.SetOriginalModel("test-model")
+	pm.SetRequestModel("test-model")
+	pm.SetBackend(&filterapi.Backend{Schema: filterapi.VersionedAPISchema{Name: filterapi.APISchemaOpenAI}})
+	pm.RecordResponseCacheLookup(t.Context(), ResponseCacheMiss, nil)
+	pm.RecordResponseCacheLookup(t.Context(), ResponseCacheExactHit, nil)
+	pm.RecordResponseCacheLookup(t.Context(), ResponseCacheExactHit, nil)
+	pm.RecordResponseCacheLookup(t.Context(), ResponseCacheSemanticHit, nil)
+
+	for result, exp := range map[ResponseCacheResult]float64{ResponseCacheMiss: 1, ResponseCacheExactHit: 2, ResponseCacheSemanticHit: 1} {
+		require.Equal(t, exp, testotel.GetCounterValue(t, mr, aigwMetricResponseCacheLookups, attribute.NewSet(append(attrs,
+			attribute.Key(aigwAttributeResponseCacheResult).String(string(result)))...)))
+	}
+}
+
 func TestGetTimeToFirstTokenMsAndGetInterTokenLatencyMs(t *testing.T) {
 	t.Parallel()
 	c := metricsImpl{timeToFirstToken: 1 * time.Second, interTokenLatencySec: 2}
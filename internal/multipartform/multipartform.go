@@ -0,0 +1,244 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package multipartform parses and rewrites multipart/form-data request bodies, such as the ones of the audio
+// transcription, image edit and file upload endpoints.
+//
+// Unlike mime/multipart, the parts are not copied out of the body: a [Form] only records where each part is in
+// the buffered body, so that the form fields can be read and rewritten without holding the uploaded files in
+// memory twice.
+package multipartform
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"slices"
+	"strings"
+)
+
+// MediaType is the media type of the multipart/form-data request bodies.
+const MediaType = "multipart/form-data"
+
+// Form is a parsed multipart/form-data body.
+type Form struct {
+	body     []byte
+	boundary string
+	parts    []Part
+	// preambleEnd is the offset of the first delimiter in the body.
+	preambleEnd int
+	// closingStart is the offset of the closing delimiter in the body.
+	closingStart int
+}
+
+// Part is a part of a [Form].
+type Part struct {
+	// Name is the name of the form field.
+	Name string
+	// FileName is the file name of the uploaded file, or empty if the part is not a file.
+	FileName string
+	// ContentType is the content type of the part, or empty if not specified.
+	ContentType string
+
+	// start is the offset of the part headers in the body, which is right after the delimiter.
+	start int
+	// content is the content of the part. This is a sub-slice of the body.
+	content []byte
+	// end is the offset of the delimiter following the part in the body.
+	end int
+}
+
+// Content returns the content of the part. The returned slice shares the memory with the body and must not
+// be modified.
+func (p *Part) Content() []byte { return p.content }
+
+// Size returns the size of the content of the part in bytes.
+func (p *Part) Size() int { return len(p.content) }
+
+// IsFile returns true if the part is an uploaded file.
+func (p *Part) IsFile() bool { return p.FileName != "" }
+
+// Boundary returns the boundary of the given content-type header value, or false if the content type is not
+// multipart/form-data.
+func Boundary(contentType string) (string, bool) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != MediaType || params["boundary"] == "" {
+		return "", false
+	}
+	return params["boundary"], true
+}
+
+// Parse parses the multipart/form-data body with the given boundary. When the boundary is empty, it is taken from
+// the first line of the body, which is useful when the content-type header is not available.
+func Parse(body []byte, boundary string) (*Form, error) {
+	if boundary == "" {
+		firstLine, _, _ := bytes.Cut(body, []byte("\r\n"))
+		b, ok := bytes.CutPrefix(firstLine, []byte("--"))
+		if !ok || len(b) == 0 {
+			return nil, errors.New("missing multipart boundary")
+		}
+		boundary = string(b)
+	}
+	dashBoundary := "--" + boundary
+	// Skip the preamble, which is usually empty.
+	var offset int
+	if bytes.HasPrefix(body, []byte(dashBoundary)) {
+		offset = len(dashBoundary)
+	} else if i := bytes.Index(body, []byte("\r\n"+dashBoundary)); i >= 0 {
+		offset = i + 2 + len(dashBoundary)
+	} else {
+		return nil, errors.New("missing multipart boundary")
+	}
+	delimiter := []byte("\r\n" + dashBoundary)
+
+	form := &Form{body: body, boundary: boundary, preambleEnd: offset - len(dashBoundary)}
+	// offset is right after a delimiter, where either the headers of the next part or "--" for the end follows.
+	for !bytes.HasPrefix(body[offset:], []byte("--")) {
+		headerLen := bytes.Index(body[offset:], []byte("\r\n\r\n"))
+		if headerLen < 0 {
+			return nil, errors.New("malformed multipart part headers")
+		}
+		contentStart := offset + headerLen + 4
+		contentLen := bytes.Index(body[contentStart:], delimiter)
+		if contentLen < 0 {
+			return nil, errors.New("missing multipart closing boundary")
+		}
+		part := Part{start: offset, content: body[contentStart : contentStart+contentLen], end: contentStart + contentLen}
+		if err := parsePartHeaders(&part, body[offset:contentStart]); err != nil {
+			return nil, err
+		}
+		form.parts = append(form.parts, part)
+		offset = part.end + len(delimiter)
+	}
+	form.closingStart = offset - len(dashBoundary)
+	return form, nil
+}
+
+// parsePartHeaders sets the name, file name and content type of the part from the raw part headers.
+func parsePartHeaders(part *Part, headers []byte) error {
+	for line := range bytes.SplitSeq(headers, []byte("\r\n")) {
+		key, value, ok := bytes.Cut(line, []byte(":"))
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(string(bytes.TrimSpace(key))) {
+		case "content-disposition":
+			_, params, err := mime.ParseMediaType(string(value))
+			if err != nil {
+				return fmt.Errorf("invalid part content-disposition: %w", err)
+			}
+			part.Name, part.FileName = params["name"], params["filename"]
+		case "content-type":
+			part.ContentType = string(bytes.TrimSpace(value))
+		}
+	}
+	return nil
+}
+
+// Boundary returns the boundary of the form.
+func (f *Form) Boundary() string { return f.boundary }
+
+// Parts returns the parts of the form in the order of the body.
+func (f *Form) Parts() []Part { return f.parts }
+
+// Part returns the first part with the given name, or nil if it does not exist.
+func (f *Form) Part(name string) *Part {
+	for i := range f.parts {
+		if f.parts[i].Name == name {
+			return &f.parts[i]
+		}
+	}
+	return nil
+}
+
+// Value returns the value of the form field with the given name, or false if it does not exist or is a file.
+func (f *Form) Value(name string) (string, bool) {
+	part := f.Part(name)
+	if part == nil || part.IsFile() {
+		return "", false
+	}
+	return string(part.content), true
+}
+
+// Field is a form field to be set by [Form.Rewrite].
+type Field struct {
+	// Name is the name of the form field.
+	Name string
+	// Value is the value of the form field.
+	Value string
+}
+
+// Rewrite returns a new body where the fields in set replace the values of the existing fields, or are appended
+// to the form if they do not exist, and the parts named in remove are removed. The body is copied only once
+// regardless of the number of changes, and the other parts including files are kept as is. Setting the value of
+// a file part is ignored.
+func (f *Form) Rewrite(set []Field, remove []string) []byte {
+	newBody := make([]byte, 0, len(f.body))
+	newBody = append(newBody, f.body[:f.preambleEnd]...)
+	first := true
+	writeDelimiter := func() {
+		if !first {
+			newBody = append(newBody, "\r\n"...)
+		}
+		newBody = append(newBody, "--"+f.boundary...)
+		first = false
+	}
+
+	replaced := make(map[string]bool, len(set))
+	for i := range f.parts {
+		part := &f.parts[i]
+		if slices.Contains(remove, part.Name) {
+			continue
+		}
+		writeDelimiter()
+		value, ok := lookupField(set, part.Name)
+		switch {
+		case ok && part.IsFile():
+			// Files cannot be replaced with a value, so keep the part and do not append the field either.
+			newBody = append(newBody, f.body[part.start:part.end]...)
+			replaced[part.Name] = true
+		case ok && !replaced[part.Name]:
+			contentStart := part.end - len(part.content)
+			newBody = append(newBody, f.body[part.start:contentStart]...)
+			newBody = append(newBody, value...)
+			replaced[part.Name] = true
+		default:
+			newBody = append(newBody, f.body[part.start:part.end]...)
+		}
+	}
+	for _, field := range set {
+		if replaced[field.Name] || slices.Contains(remove, field.Name) {
+			continue
+		}
+		writeDelimiter()
+		newBody = append(newBody, fmt.Sprintf("\r\nContent-Disposition: form-data; name=%q\r\n\r\n", field.Name)...)
+		newBody = append(newBody, field.Value...)
+		replaced[field.Name] = true
+	}
+	if !first {
+		newBody = append(newBody, "\r\n"...)
+	}
+	return append(newBody, f.body[f.closingStart:]...)
+}
+
+// SetField returns a copy of the multipart/form-data body where the value of the named form field is replaced,
+// or the field is appended if it does not exist. The boundary is taken from the body.
+func SetField(body []byte, name, value string) ([]byte, error) {
+	form, err := Parse(body, "")
+	if err != nil {
+		return nil, err
+	}
+	return form.Rewrite([]Field{{Name: name, Value: value}}, nil), nil
+}
+
+func lookupField(fields []Field, name string) (string, bool) {
+	for _, field := range fields {
+		if field.Name == name {
+			return field.Value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,249 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package multipartform
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestForm returns a multipart/form-data body with the given fields followed by a file part.
+func newTestForm(t *testing.T, fields [][2]string, file []byte) (body []byte, boundary string) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, field := range fields {
+		require.NoError(t, w.WriteField(field[0], field[1]))
+	}
+	if file != nil {
+		fw, err := w.CreateFormFile("file", "speech.mp3")
+		require.NoError(t, err)
+		_, err = fw.Write(file)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes(), w.Boundary()
+}
+
+// readForm reads the body with mime/multipart and returns the fields and the files.
+func readForm(t *testing.T, body []byte, boundary string) (fields [][2]string, files map[string][]byte) {
+	files = map[string][]byte{}
+	r := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		p, err := r.NextPart()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(p)
+		require.NoError(t, err)
+		if p.FileName() != "" {
+			files[p.FormName()] = content
+		} else {
+			fields = append(fields, [2]string{p.FormName(), string(content)})
+		}
+	}
+}
+
+func TestBoundary(t *testing.T) {
+	for _, tc := range []struct {
+		contentType string
+		boundary    string
+		ok          bool
+	}{
+		{contentType: "multipart/form-data; boundary=abc", boundary: "abc", ok: true},
+		{contentType: `multipart/form-data; boundary="a b"`, boundary: "a b", ok: true},
+		{contentType: "multipart/form-data"},
+		{contentType: "multipart/mixed; boundary=abc"},
+		{contentType: "application/json"},
+		{contentType: ""},
+	} {
+		t.Run(tc.contentType, func(t *testing.T) {
+			boundary, ok := Boundary(tc.contentType)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.boundary, boundary)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	file := []byte("fake audio\r\n--not-a-delimiter")
+	body, boundary := newTestForm(t, [][2]string{{"model", "whisper-1"}, {"language", "en"}}, file)
+
+	for _, b := range []string{boundary, ""} {
+		form, err := Parse(body, b)
+		require.NoError(t, err)
+		require.Equal(t, boundary, form.Boundary())
+		require.Len(t, form.Parts(), 3)
+
+		model, ok := form.Value("model")
+		require.True(t, ok)
+		require.Equal(t, "whisper-1", model)
+		language, ok := form.Value("language")
+		require.True(t, ok)
+		require.Equal(t, "en", language)
+		_, ok = form.Value("prompt")
+		require.False(t, ok)
+		_, ok = form.Value("file")
+		require.False(t, ok)
+
+		part := form.Part("file")
+		require.NotNil(t, part)
+		require.True(t, part.IsFile())
+		require.Equal(t, "speech.mp3", part.FileName)
+		require.Equal(t, "application/octet-stream", part.ContentType)
+		require.Equal(t, file, part.Content())
+		require.Equal(t, len(file), part.Size())
+		require.Nil(t, form.Part("prompt"))
+	}
+}
+
+func TestParse_Preamble(t *testing.T) {
+	body := []byte("preamble\r\n--b\r\nContent-Disposition: form-data; name=\"model\"\r\n\r\nwhisper-1\r\n--b--\r\n")
+	form, err := Parse(body, "b")
+	require.NoError(t, err)
+	model, ok := form.Value("model")
+	require.True(t, ok)
+	require.Equal(t, "whisper-1", model)
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		body     string
+		boundary string
+		expErr   string
+	}{
+		{name: "empty", body: "", expErr: "missing multipart boundary"},
+		{name: "json", body: `{"model":"whisper-1"}`, expErr: "missing multipart boundary"},
+		{name: "wrong boundary", body: "--b\r\n\r\n--b--", boundary: "c", expErr: "missing multipart boundary"},
+		{name: "no headers end", body: "--b\r\nContent-Disposition: form-data; name=\"model\"", expErr: "malformed multipart part headers"},
+		{name: "no closing", body: "--b\r\nContent-Disposition: form-data; name=\"model\"\r\n\r\nwhisper-1", expErr: "missing multipart closing boundary"},
+		{name: "invalid disposition", body: "--b\r\nContent-Disposition: ;\r\n\r\nx\r\n--b--", expErr: "invalid part content-disposition: mime: no media type"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.body), tc.boundary)
+			require.EqualError(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestForm_Rewrite(t *testing.T) {
+	file := []byte("fake audio")
+	for _, tc := range []struct {
+		name      string
+		fields    [][2]string
+		file      []byte
+		set       []Field
+		remove    []string
+		expFields [][2]string
+		expFile   bool
+	}{
+		{
+			name:      "no changes",
+			fields:    [][2]string{{"model", "whisper-1"}},
+			file:      file,
+			expFields: [][2]string{{"model", "whisper-1"}},
+			expFile:   true,
+		},
+		{
+			name:      "replace",
+			fields:    [][2]string{{"model", "whisper-1"}, {"language", "en"}},
+			file:      file,
+			set:       []Field{{Name: "model", Value: "gpt-4o-transcribe"}},
+			expFields: [][2]string{{"model", "gpt-4o-transcribe"}, {"language", "en"}},
+			expFile:   true,
+		},
+		{
+			name:      "append",
+			fields:    [][2]string{{"model", "whisper-1"}},
+			file:      file,
+			set:       []Field{{Name: "temperature", Value: "0.2"}},
+			expFields: [][2]string{{"model", "whisper-1"}, {"temperature", "0.2"}},
+			expFile:   true,
+		},
+		{
+			name:      "remove first",
+			fields:    [][2]string{{"model", "whisper-1"}, {"prompt", "secret"}},
+			file:      file,
+			remove:    []string{"model"},
+			expFields: [][2]string{{"prompt", "secret"}},
+			expFile:   true,
+		},
+		{
+			name:      "remove all fields",
+			fields:    [][2]string{{"model", "whisper-1"}, {"prompt", "secret"}},
+			remove:    []string{"model", "prompt"},
+			expFields: nil,
+		},
+		{
+			name:      "remove file",
+			fields:    [][2]string{{"model", "whisper-1"}},
+			file:      file,
+			remove:    []string{"file"},
+			expFields: [][2]string{{"model", "whisper-1"}},
+		},
+		{
+			name:      "set and remove",
+			fields:    [][2]string{{"model", "whisper-1"}, {"prompt", "secret"}},
+			file:      file,
+			set:       []Field{{Name: "model", Value: "m"}, {Name: "prompt", Value: "ignored"}, {Name: "language", Value: "fr"}},
+			remove:    []string{"prompt"},
+			expFields: [][2]string{{"model", "m"}, {"language", "fr"}},
+			expFile:   true,
+		},
+		{
+			name:      "set file is ignored",
+			fields:    [][2]string{{"model", "whisper-1"}},
+			file:      file,
+			set:       []Field{{Name: "file", Value: "x"}},
+			expFields: [][2]string{{"model", "whisper-1"}},
+			expFile:   true,
+		},
+		{
+			name:      "append to empty form",
+			set:       []Field{{Name: "model", Value: "whisper-1"}},
+			expFields: [][2]string{{"model", "whisper-1"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			body, boundary := newTestForm(t, tc.fields, tc.file)
+			form, err := Parse(body, boundary)
+			require.NoError(t, err)
+
+			newBody := form.Rewrite(tc.set, tc.remove)
+			if tc.set == nil && tc.remove == nil {
+				require.Equal(t, body, newBody)
+			}
+			fields, files := readForm(t, newBody, boundary)
+			require.Equal(t, tc.expFields, fields)
+			if tc.expFile {
+				require.Equal(t, map[string][]byte{"file": file}, files)
+			} else {
+				require.Empty(t, files)
+			}
+
+			// The rewritten body must be parsable again.
+			_, err = Parse(newBody, boundary)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSetField(t *testing.T) {
+	body, boundary := newTestForm(t, [][2]string{{"model", "whisper-1"}}, []byte("fake audio"))
+	newBody, err := SetField(body, "model", "override")
+	require.NoError(t, err)
+	fields, _ := readForm(t, newBody, boundary)
+	require.Equal(t, [][2]string{{"model", "override"}}, fields)
+
+	_, err = SetField([]byte(`{"model":"whisper-1"}`), "model", "override")
+	require.EqualError(t, err, "missing multipart boundary")
+}
@@ -0,0 +1,323 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package pii detects the personally identifiable information (PII) in the texts of the requests, and replaces it
+// with stable placeholders that can be restored in the responses.
+package pii
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// Entity is a kind of PII detected by the built-in detectors.
+type Entity string
+
+const (
+	// EntityEmail is an email address, e.g. "jane@example.com".
+	EntityEmail Entity = "Email"
+	// EntityPhoneNumber is an international phone number starting with "+", or a North American phone number
+	// with separators, e.g. "+33 6 12 34 56 78" or "(415) 555-2671".
+	EntityPhoneNumber Entity = "PhoneNumber"
+	// EntityCreditCard is a payment card number of 13 to 19 digits passing the Luhn check, optionally grouped
+	// with spaces or dashes.
+	EntityCreditCard Entity = "CreditCard"
+	// EntityNationalID is a US social security number, e.g. "123-45-6789".
+	EntityNationalID Entity = "NationalID"
+)
+
+// Pattern is a custom kind of PII detected with a regular expression.
+type Pattern struct {
+	// Name is the name of the pattern used in the placeholders, e.g. "EMPLOYEE_ID" for "[EMPLOYEE_ID_1]".
+	Name string
+	// Regex is the RE2 regular expression matching the PII.
+	Regex string
+}
+
+// patternNameRegex is the format of the names of the custom patterns.
+var patternNameRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// detector detects a kind of PII.
+type detector struct {
+	// label is the label of the placeholders, e.g. "EMAIL".
+	label string
+	re    *regexp.Regexp
+	// valid reports whether the match is the PII. Nil if all the matches are.
+	valid func(text string, start, end int) bool
+}
+
+// builtinDetectors is the detectors of the built-in entities.
+var builtinDetectors = map[Entity]detector{
+	EntityEmail: {
+		label: "EMAIL",
+		re:    regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+	},
+	EntityPhoneNumber: {
+		label: "PHONE_NUMBER",
+		re: regexp.MustCompile(`\+\d{1,3}[ .\-]?(?:\(\d{1,4}\)[ .\-]?)?\d{1,4}(?:[ .\-]?\d{2,4}){1,4}|` +
+			`\(\d{3}\)[ .\-]?\d{3}[ .\-]\d{4}|\d{3}[.\-]\d{3}[.\-]\d{4}`),
+		valid: func(text string, start, end int) bool {
+			n := countDigits(text[start:end])
+			return n >= 7 && n <= 15 && isolated(text, start, end)
+		},
+	},
+	EntityCreditCard: {
+		label: "CREDIT_CARD",
+		re:    regexp.MustCompile(`\d(?:[ \-]?\d){12,18}`),
+		valid: func(text string, start, end int) bool {
+			return isolated(text, start, end) && luhn(text[start:end])
+		},
+	},
+	EntityNationalID: {
+		label: "NATIONAL_ID",
+		re:    regexp.MustCompile(`\d{3}-\d{2}-\d{4}`),
+		valid: func(text string, start, end int) bool {
+			area, group, serial := text[start:start+3], text[start+4:start+6], text[start+7:end]
+			return isolated(text, start, end) && area != "000" && area != "666" && area[0] != '9' &&
+				group != "00" && serial != "0000"
+		},
+	},
+}
+
+// builtinOrder is the order in which the overlapping matches of the built-in entities are preferred.
+var builtinOrder = []Entity{EntityCreditCard, EntityNationalID, EntityEmail, EntityPhoneNumber}
+
+// Detector detects the PII in the texts. This is safe for concurrent use.
+type Detector struct {
+	// detectors is the list of the detectors by priority. The custom patterns come first.
+	detectors []detector
+}
+
+// NewDetector creates a Detector of the given built-in entities and custom patterns.
+func NewDetector(entities []Entity, patterns []Pattern) (*Detector, error) {
+	if len(entities) == 0 && len(patterns) == 0 {
+		return nil, errors.New("no entity or pattern to detect")
+	}
+	d := &Detector{}
+	for _, p := range patterns {
+		if !patternNameRegex.MatchString(p.Name) {
+			return nil, fmt.Errorf("invalid pattern name %q: must match %s", p.Name, patternNameRegex)
+		}
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex of pattern %s: %w", p.Name, err)
+		}
+		d.detectors = append(d.detectors, detector{label: p.Name, re: re})
+	}
+	for _, e := range entities {
+		if _, ok := builtinDetectors[e]; !ok {
+			return nil, fmt.Errorf("unknown entity %q", e)
+		}
+	}
+	for _, e := range builtinOrder {
+		if slices.Contains(entities, e) {
+			d.detectors = append(d.detectors, builtinDetectors[e])
+		}
+	}
+	return d, nil
+}
+
+// span is a match of a detector in a text.
+type span struct {
+	start, end int
+	label      string
+}
+
+// find returns the non-overlapping matches in the text sorted by their position. Among the overlapping matches,
+// the one starting first is preferred, then the one of the detector with the highest priority.
+func (d *Detector) find(text string) []span {
+	var spans []span
+	for _, det := range d.detectors {
+		for _, loc := range det.re.FindAllStringIndex(text, -1) {
+			if loc[0] == loc[1] || (det.valid != nil && !det.valid(text, loc[0], loc[1])) {
+				continue
+			}
+			overlaps := func(s span) bool { return loc[0] < s.end && s.start < loc[1] }
+			if slices.ContainsFunc(spans, func(s span) bool { return overlaps(s) && s.start <= loc[0] }) {
+				continue
+			}
+			// The overlapping matches starting later are replaced by this one.
+			spans = slices.DeleteFunc(spans, overlaps)
+			spans = append(spans, span{start: loc[0], end: loc[1], label: det.label})
+		}
+	}
+	slices.SortFunc(spans, func(a, b span) int { return cmp.Compare(a.start, b.start) })
+	return spans
+}
+
+// Redactor replaces the PII in the texts of a request with placeholders and restores them in the response.
+// The same value is always replaced with the same placeholder, e.g. "[EMAIL_1]", numbered per label in the order of
+// detection. A Redactor is used for a single request and is not safe for concurrent use.
+type Redactor struct {
+	detector *Detector
+	// placeholders is the map of the placeholders by the original values.
+	placeholders map[string]string
+	// originals is the map of the original values by the placeholders.
+	originals map[string]string
+	counts    map[string]int
+	// labels is the list of the labels of the detected PII in the order of detection.
+	labels []string
+	// restorer and jsonRestorer are built lazily and reset when a placeholder is added.
+	restorer, jsonRestorer *strings.Replacer
+}
+
+// NewRedactor creates a Redactor for a request.
+func (d *Detector) NewRedactor() *Redactor {
+	return &Redactor{
+		detector:     d,
+		placeholders: make(map[string]string),
+		originals:    make(map[string]string),
+		counts:       make(map[string]int),
+	}
+}
+
+// Redact returns the text with the PII replaced by the placeholders.
+func (r *Redactor) Redact(text string) string {
+	spans := r.detector.find(text)
+	if len(spans) == 0 {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, s := range spans {
+		b.WriteString(text[last:s.start])
+		b.WriteString(r.placeholder(s.label, text[s.start:s.end]))
+		last = s.end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// placeholder returns the placeholder of the original value, adding it if this is the first occurrence.
+func (r *Redactor) placeholder(label, original string) string {
+	if p, ok := r.placeholders[original]; ok {
+		return p
+	}
+	if r.counts[label] == 0 {
+		r.labels = append(r.labels, label)
+	}
+	r.counts[label]++
+	p := "[" + label + "_" + strconv.Itoa(r.counts[label]) + "]"
+	r.placeholders[original] = p
+	r.originals[p] = original
+	r.restorer, r.jsonRestorer = nil, nil
+	return p
+}
+
+// Labels returns the labels of the detected PII in the order of detection, e.g. ["EMAIL", "PHONE_NUMBER"].
+func (r *Redactor) Labels() []string {
+	return r.labels
+}
+
+// Restore returns the text with the placeholders replaced by the original values.
+func (r *Redactor) Restore(text string) string {
+	if len(r.originals) == 0 || !strings.Contains(text, "[") {
+		return text
+	}
+	if r.restorer == nil {
+		oldnew := make([]string, 0, 2*len(r.originals))
+		for p, original := range r.originals {
+			oldnew = append(oldnew, p, original)
+		}
+		r.restorer = strings.NewReplacer(oldnew...)
+	}
+	return r.restorer.Replace(text)
+}
+
+// RestoreJSON returns the JSON body with the placeholders in the strings replaced by the original values escaped
+// for JSON strings.
+func (r *Redactor) RestoreJSON(body []byte) []byte {
+	if len(r.originals) == 0 || !bytes.Contains(body, []byte("[")) {
+		return body
+	}
+	if r.jsonRestorer == nil {
+		oldnew := make([]string, 0, 2*len(r.originals))
+		for p, original := range r.originals {
+			oldnew = append(oldnew, p, jsonEscape(original))
+		}
+		r.jsonRestorer = strings.NewReplacer(oldnew...)
+	}
+	return []byte(r.jsonRestorer.Replace(string(body)))
+}
+
+// splitPartial splits the text before the trailing partial placeholder, if any, e.g. "Mail [EMA" is split into
+// "Mail " and "[EMA".
+func (r *Redactor) splitPartial(text string) (head, partial string) {
+	i := strings.LastIndexByte(text, '[')
+	if i < 0 {
+		return text, ""
+	}
+	suffix := text[i:]
+	for p := range r.originals {
+		if len(suffix) < len(p) && strings.HasPrefix(p, suffix) {
+			return text[:i], suffix
+		}
+	}
+	return text, ""
+}
+
+// jsonEscape returns the string escaped for the inside of a JSON string.
+func jsonEscape(s string) string {
+	b, err := json.Marshal(s)
+	if err != nil || len(b) < 2 {
+		return s
+	}
+	return string(b[1 : len(b)-1])
+}
+
+// isolated reports whether the match is not part of a longer word or number.
+func isolated(text string, start, end int) bool {
+	if r, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && isWordRune(r) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && isWordRune(r) {
+		return false
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func countDigits(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+// luhn reports whether the digits of the number pass the Luhn check.
+func luhn(number string) bool {
+	sum, double := 0, false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
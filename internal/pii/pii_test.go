@@ -0,0 +1,148 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package pii
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var allEntities = []Entity{EntityEmail, EntityPhoneNumber, EntityCreditCard, EntityNationalID}
+
+func TestNewDetector(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		entities []Entity
+		patterns []Pattern
+		expErr   string
+	}{
+		{name: "nothing to detect", expErr: "no entity or pattern to detect"},
+		{name: "unknown entity", entities: []Entity{"Passport"}, expErr: `unknown entity "Passport"`},
+		{name: "invalid name", patterns: []Pattern{{Name: "employee", Regex: `E\d+`}}, expErr: `invalid pattern name "employee"`},
+		{name: "invalid regex", patterns: []Pattern{{Name: "EMPLOYEE", Regex: `E(\d+`}}, expErr: "invalid regex of pattern EMPLOYEE"},
+		{name: "valid", entities: allEntities, patterns: []Pattern{{Name: "EMPLOYEE_ID", Regex: `EMP-\d{6}`}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := NewDetector(tc.entities, tc.patterns)
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, d)
+		})
+	}
+}
+
+func TestRedactor_Redact(t *testing.T) {
+	d, err := NewDetector(allEntities, []Pattern{{Name: "EMPLOYEE_ID", Regex: `EMP-\d{6}`}})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name      string
+		text      string
+		exp       string
+		expLabels []string
+	}{
+		{name: "no pii", text: "What is the capital of France?", exp: "What is the capital of France?"},
+		{
+			name:      "email",
+			text:      "Contact jane.doe+ai@example.co.uk please.",
+			exp:       "Contact [EMAIL_1] please.",
+			expLabels: []string{"EMAIL"},
+		},
+		{
+			name:      "phone numbers",
+			text:      "Call +33 6 12 34 56 78 or (415) 555-2671 or 415.555.2672.",
+			exp:       "Call [PHONE_NUMBER_1] or [PHONE_NUMBER_2] or [PHONE_NUMBER_3].",
+			expLabels: []string{"PHONE_NUMBER"},
+		},
+		{
+			name: "not phone numbers",
+			text: "Released on 2024-01-15, build 12345678, version 415-555-26710.",
+			exp:  "Released on 2024-01-15, build 12345678, version 415-555-26710.",
+		},
+		{
+			name:      "credit cards",
+			text:      "Cards 4111 1111 1111 1111 and 5500-0000-0000-0004, not 4111 1111 1111 1112.",
+			exp:       "Cards [CREDIT_CARD_1] and [CREDIT_CARD_2], not 4111 1111 1111 1112.",
+			expLabels: []string{"CREDIT_CARD"},
+		},
+		{
+			name:      "national ids",
+			text:      "SSN 123-45-6789, not 000-12-3456 or 123-45-67890.",
+			exp:       "SSN [NATIONAL_ID_1], not 000-12-3456 or 123-45-67890.",
+			expLabels: []string{"NATIONAL_ID"},
+		},
+		{
+			name:      "custom pattern",
+			text:      "Employee EMP-001234 wrote to a@b.io.",
+			exp:       "Employee [EMPLOYEE_ID_1] wrote to [EMAIL_1].",
+			expLabels: []string{"EMPLOYEE_ID", "EMAIL"},
+		},
+		{
+			name:      "repeated value",
+			text:      "a@b.io, c@d.io, a@b.io",
+			exp:       "[EMAIL_1], [EMAIL_2], [EMAIL_1]",
+			expLabels: []string{"EMAIL"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := d.NewRedactor()
+			redacted := r.Redact(tc.text)
+			require.Equal(t, tc.exp, redacted)
+			require.Equal(t, tc.expLabels, r.Labels())
+			require.Equal(t, tc.text, r.Restore(redacted))
+		})
+	}
+}
+
+func TestRedactor_Redact_stable(t *testing.T) {
+	d, err := NewDetector([]Entity{EntityEmail}, nil)
+	require.NoError(t, err)
+	r := d.NewRedactor()
+	require.Equal(t, "From [EMAIL_1]", r.Redact("From a@b.io"))
+	require.Equal(t, "To [EMAIL_2], cc [EMAIL_1]", r.Redact("To c@d.io, cc a@b.io"))
+}
+
+func TestRedactor_RestoreJSON(t *testing.T) {
+	d, err := NewDetector(nil, []Pattern{{Name: "SECRET", Regex: `secret "[a-z]+"`}})
+	require.NoError(t, err)
+	r := d.NewRedactor()
+	require.Equal(t, "The [SECRET_1].", r.Redact(`The secret "abc".`))
+
+	require.JSONEq(t, `{"content":"The secret \"abc\"."}`,
+		string(r.RestoreJSON([]byte(`{"content":"The [SECRET_1]."}`))))
+	require.Equal(t, `{"content":"[SECRET_2]"}`, string(r.RestoreJSON([]byte(`{"content":"[SECRET_2]"}`))))
+}
+
+func TestRedactor_splitPartial(t *testing.T) {
+	d, err := NewDetector([]Entity{EntityEmail}, nil)
+	require.NoError(t, err)
+	r := d.NewRedactor()
+	r.Redact("a@b.io")
+
+	for _, tc := range []struct {
+		text, expHead, expPartial string
+	}{
+		{text: "Mail [EMA", expHead: "Mail ", expPartial: "[EMA"},
+		{text: "Mail [", expHead: "Mail ", expPartial: "["},
+		{text: "Mail [EMAIL_1]", expHead: "Mail [EMAIL_1]"},
+		{text: "Mail [PHONE", expHead: "Mail [PHONE"},
+		{text: "Mail", expHead: "Mail"},
+	} {
+		head, partial := r.splitPartial(tc.text)
+		require.Equal(t, tc.expHead, head, tc.text)
+		require.Equal(t, tc.expPartial, partial, tc.text)
+	}
+}
+
+func TestLuhn(t *testing.T) {
+	require.True(t, luhn("4111111111111111"))
+	require.True(t, luhn("4111-1111-1111-1111"))
+	require.False(t, luhn("4111111111111112"))
+}
@@ -0,0 +1,162 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package pii
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// StreamTextFunc identifies the text delta in the data of an event of a streaming response. key identifies the
+// text the delta belongs to, such as the index of the choice, and is empty if the event is not about a text. path is
+// the gjson path of the delta in the data, or empty if the event has no delta. end is true if the event ends the text.
+type StreamTextFunc func(data []byte) (key, path string, end bool)
+
+// StreamRestorer restores the placeholders in the server-sent events of a streaming response.
+//
+// A placeholder may be split across the deltas of a text, e.g. "[EMA" and "IL_1]", so the trailing partial
+// placeholder of a delta is held back and prepended to the next delta of the same text. The held back text is
+// emitted in an event copied from the last delta of the text when the text ends without another delta.
+type StreamRestorer struct {
+	r    *Redactor
+	text StreamTextFunc
+	// buf is the incomplete event at the end of the previous chunk.
+	buf []byte
+	// pending is the held back text by the keys of the texts.
+	pending map[string]string
+	// last is the last delta event by the keys of the texts, used to emit the held back text.
+	last map[string]deltaEvent
+}
+
+// deltaEvent is an event with a text delta.
+type deltaEvent struct {
+	event []byte
+	path  string
+}
+
+// NewStreamRestorer creates a StreamRestorer of the placeholders of the Redactor.
+func (r *Redactor) NewStreamRestorer(text StreamTextFunc) *StreamRestorer {
+	return &StreamRestorer{r: r, text: text, pending: make(map[string]string), last: make(map[string]deltaEvent)}
+}
+
+// Restore returns the restored complete events of the chunk. The incomplete event at the end of the chunk is kept
+// until the next chunk, unless this is the end of the stream.
+func (s *StreamRestorer) Restore(chunk []byte, endOfStream bool) []byte {
+	s.buf = append(s.buf, chunk...)
+	var out []byte
+	for {
+		i := bytes.Index(s.buf, []byte("\n\n"))
+		if i < 0 {
+			break
+		}
+		out = s.restoreEvent(out, s.buf[:i+2])
+		s.buf = s.buf[i+2:]
+	}
+	if endOfStream {
+		if len(s.buf) > 0 {
+			out = s.restoreEvent(out, s.buf)
+			s.buf = nil
+		}
+		out = s.flushAll(out)
+	}
+	if out == nil {
+		// The body is replaced with the empty body while the event is incomplete.
+		out = []byte{}
+	}
+	return out
+}
+
+// restoreEvent appends the restored event to out.
+func (s *StreamRestorer) restoreEvent(out, event []byte) []byte {
+	event = s.r.RestoreJSON(event)
+	start, end := dataRange(event)
+	if start < 0 {
+		return append(out, event...)
+	}
+	data := event[start:end]
+	if bytes.Equal(data, []byte("[DONE]")) {
+		return append(s.flushAll(out), event...)
+	}
+	key, path, textEnd := s.text(data)
+	if key == "" {
+		return append(out, event...)
+	}
+	if path == "" {
+		if textEnd {
+			out = s.flush(out, key)
+		}
+		return append(out, event...)
+	}
+
+	text := s.r.Restore(s.pending[key] + gjson.GetBytes(data, path).String())
+	var partial string
+	if !textEnd {
+		text, partial = s.r.splitPartial(text)
+	}
+	if partial != "" {
+		s.pending[key] = partial
+	} else {
+		delete(s.pending, key)
+	}
+	if newData, err := sjson.SetBytes(slices.Clone(data), path, text); err == nil {
+		event = slices.Concat(event[:start], newData, event[end:])
+	}
+	s.last[key] = deltaEvent{event: event, path: path}
+	return append(out, event...)
+}
+
+// flush appends the event with the held back text of the key, if any, to out.
+func (s *StreamRestorer) flush(out []byte, key string) []byte {
+	partial, ok := s.pending[key]
+	if !ok {
+		return out
+	}
+	delete(s.pending, key)
+	last := s.last[key]
+	start, end := dataRange(last.event)
+	newData, err := sjson.SetBytes(slices.Clone(last.event[start:end]), last.path, partial)
+	if err != nil {
+		return out
+	}
+	return append(out, slices.Concat(last.event[:start], newData, last.event[end:])...)
+}
+
+// flushAll appends the events with all the held back texts to out.
+func (s *StreamRestorer) flushAll(out []byte) []byte {
+	keys := make([]string, 0, len(s.pending))
+	for key := range s.pending {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		out = s.flush(out, key)
+	}
+	return out
+}
+
+// dataRange returns the range of the data of the first "data:" line of the event, or -1 if there is none.
+func dataRange(event []byte) (start, end int) {
+	for i := 0; i < len(event); {
+		lineEnd := bytes.IndexByte(event[i:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(event)
+		} else {
+			lineEnd += i
+		}
+		if line := event[i:lineEnd]; bytes.HasPrefix(line, []byte("data:")) {
+			start = i + len("data:")
+			if start < lineEnd && event[start] == ' ' {
+				start++
+			}
+			return start, lineEnd
+		}
+		i = lineEnd + 1
+	}
+	return -1, -1
+}
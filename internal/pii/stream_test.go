@@ -0,0 +1,82 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package pii
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+// chatStreamText is the StreamTextFunc of the chat completion chunks.
+func chatStreamText(data []byte) (key, path string, end bool) {
+	choice := gjson.GetBytes(data, "choices.0")
+	if !choice.Exists() {
+		return "", "", false
+	}
+	if choice.Get("delta.content").Type == gjson.String {
+		path = "choices.0.delta.content"
+	}
+	return choice.Get("index").String(), path, choice.Get("finish_reason").Type == gjson.String
+}
+
+func TestStreamRestorer(t *testing.T) {
+	d, err := NewDetector([]Entity{EntityEmail}, nil)
+	require.NoError(t, err)
+	r := d.NewRedactor()
+	require.Equal(t, "[EMAIL_1] and [EMAIL_2]", r.Redact("a@b.io and c@d.io"))
+
+	chunk := func(content string) string {
+		return `data: {"choices":[{"index":0,"delta":{"content":"` + content + `"}}]}` + "\n\n"
+	}
+
+	t.Run("split placeholder", func(t *testing.T) {
+		s := r.NewStreamRestorer(chatStreamText)
+		require.Equal(t, chunk("Write to "), string(s.Restore([]byte(chunk("Write to [EMA")), false)))
+		require.Equal(t, chunk("a@b.io and [EMAIL_3]"), string(s.Restore([]byte(chunk("IL_1] and [EMAIL_3]")), false)))
+		require.Equal(t, chunk("c@d.io"), string(s.Restore([]byte(chunk("[EMAIL_2]")), false)))
+		require.Equal(t, "data: [DONE]\n\n", string(s.Restore([]byte("data: [DONE]\n\n"), true)))
+	})
+
+	t.Run("split event", func(t *testing.T) {
+		s := r.NewStreamRestorer(chatStreamText)
+		event := chunk("Hi [EMAIL_1]")
+		require.Empty(t, s.Restore([]byte(event[:20]), false))
+		require.Equal(t, chunk("Hi a@b.io"), string(s.Restore([]byte(event[20:]), false)))
+	})
+
+	t.Run("partial at end of text", func(t *testing.T) {
+		s := r.NewStreamRestorer(chatStreamText)
+		require.Equal(t, chunk("Array "), string(s.Restore([]byte(chunk("Array [")), false)))
+		finish := `data: {"choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}` + "\n\n"
+		require.Equal(t, chunk("[")+finish, string(s.Restore([]byte(finish), false)))
+	})
+
+	t.Run("partial at end of stream", func(t *testing.T) {
+		s := r.NewStreamRestorer(chatStreamText)
+		require.Equal(t, chunk("Array "), string(s.Restore([]byte(chunk("Array [E")), false)))
+		require.Equal(t, chunk("[E")+"data: [DONE]\n\n", string(s.Restore([]byte("data: [DONE]\n\n"), true)))
+	})
+
+	t.Run("anthropic events", func(t *testing.T) {
+		s := r.NewStreamRestorer(func(data []byte) (key, path string, end bool) {
+			switch gjson.GetBytes(data, "type").String() {
+			case "content_block_delta":
+				return gjson.GetBytes(data, "index").String(), "delta.text", false
+			case "content_block_stop":
+				return gjson.GetBytes(data, "index").String(), "", true
+			}
+			return "", "", false
+		})
+		delta := func(text string) string {
+			return "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"" + text + "\"}}\n\n"
+		}
+		stop := "event: content_block_stop\ndata: {\"type\":\"content_block_stop\",\"index\":0}\n\n"
+		require.Equal(t, delta("Hi "), string(s.Restore([]byte(delta("Hi [EMAIL_")), false)))
+		require.Equal(t, delta("[EMAIL_")+stop, string(s.Restore([]byte(stop), true)))
+	})
+}
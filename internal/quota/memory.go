@@ -0,0 +1,144 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package quota
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the number of charges after which stale counters are garbage collected.
+const sweepInterval = 1024
+
+// memoryStore is the in-process [Store]. The counters are not shared across processes, so this is only suitable
+// when a single external processor serves the traffic, such as in the standalone mode.
+type memoryStore struct {
+	mu       sync.Mutex
+	counters map[string]*counter
+	// claims is the map of the claimed keys to their expiration time.
+	claims  map[string]time.Time
+	charges int
+	// now is the clock used by the store. Overridable for testing.
+	now func() time.Time
+}
+
+// counter is the state of a single bucket.
+type counter struct {
+	window   time.Duration
+	start    time.Time
+	current  uint64
+	previous uint64
+}
+
+// NewMemoryStore creates a new in-memory [Store].
+func NewMemoryStore() Store {
+	return newMemoryStore()
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{counters: make(map[string]*counter), claims: make(map[string]time.Time), now: time.Now}
+}
+
+// Usage implements [Store.Usage].
+func (l *memoryStore) Usage(_ context.Context, key string, window time.Duration) (uint64, error) {
+	if window <= 0 {
+		return 0, nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c, ok := l.counters[key]
+	if !ok {
+		return 0, nil
+	}
+	now := l.now()
+	c.rotate(now, window)
+	return c.usage(now), nil
+}
+
+// Charge implements [Store.Charge].
+func (l *memoryStore) Charge(_ context.Context, key string, window time.Duration, cost uint64) error {
+	if window <= 0 || cost == 0 {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	c, ok := l.counters[key]
+	if !ok {
+		c = &counter{window: window, start: now.Truncate(window)}
+		l.counters[key] = c
+	}
+	c.rotate(now, window)
+	c.current += cost
+
+	l.charges++
+	if l.charges >= sweepInterval {
+		l.charges = 0
+		l.sweep(now)
+	}
+	return nil
+}
+
+// Claim implements [Store.Claim].
+func (l *memoryStore) Claim(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if expiry, ok := l.claims[key]; ok && now.Before(expiry) {
+		return false, nil
+	}
+	l.claims[key] = now.Add(ttl)
+
+	l.charges++
+	if l.charges >= sweepInterval {
+		l.charges = 0
+		l.sweep(now)
+	}
+	return true, nil
+}
+
+// Close implements [Store.Close].
+func (l *memoryStore) Close() error { return nil }
+
+// sweep removes the counters whose windows have fully elapsed and the expired claims. This must be called with
+// the lock held.
+func (l *memoryStore) sweep(now time.Time) {
+	for key, c := range l.counters {
+		if now.Sub(c.start) >= 2*c.window {
+			delete(l.counters, key)
+		}
+	}
+	for key, expiry := range l.claims {
+		if !now.Before(expiry) {
+			delete(l.claims, key)
+		}
+	}
+}
+
+// rotate moves the counter to the fixed window containing now.
+func (c *counter) rotate(now time.Time, window time.Duration) {
+	if c.window != window {
+		// The duration of the bucket has been reconfigured, so the previous counts are meaningless.
+		*c = counter{window: window, start: now.Truncate(window)}
+		return
+	}
+	start := now.Truncate(window)
+	switch {
+	case start.Equal(c.start):
+	case start.Equal(c.start.Add(window)):
+		c.previous, c.current = c.current, 0
+		c.start = start
+	default:
+		c.previous, c.current = 0, 0
+		c.start = start
+	}
+}
+
+// usage returns the sliding window estimate at now. The counter must have been rotated to now.
+func (c *counter) usage(now time.Time) uint64 {
+	return slidingWindowUsage(c.current, c.previous, now.Sub(c.start), c.window)
+}
@@ -0,0 +1,99 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Unix(999_960, 0) // Aligned to a minute boundary.
+	l := newMemoryStore()
+	l.now = func() time.Time { return now }
+
+	require.Zero(t, requireUsage(t, l, "a", time.Minute))
+	require.NoError(t, l.Charge(t.Context(), "a", time.Minute, 100))
+	require.NoError(t, l.Charge(t.Context(), "a", time.Minute, 20))
+	require.Equal(t, uint64(120), requireUsage(t, l, "a", time.Minute))
+	require.Zero(t, requireUsage(t, l, "b", time.Minute))
+
+	// Halfway through the next window, half of the previous window still counts.
+	now = now.Add(90 * time.Second)
+	require.Equal(t, uint64(60), requireUsage(t, l, "a", time.Minute))
+	require.NoError(t, l.Charge(t.Context(), "a", time.Minute, 10))
+	require.Equal(t, uint64(70), requireUsage(t, l, "a", time.Minute))
+
+	// Two windows later, everything has expired.
+	now = now.Add(2 * time.Minute)
+	require.Zero(t, requireUsage(t, l, "a", time.Minute))
+}
+
+func TestMemoryStore_ZeroValues(t *testing.T) {
+	l := newMemoryStore()
+	require.NoError(t, l.Charge(t.Context(), "a", 0, 100))
+	require.NoError(t, l.Charge(t.Context(), "a", time.Minute, 0))
+	require.Zero(t, requireUsage(t, l, "a", time.Minute))
+	require.Zero(t, requireUsage(t, l, "a", 0))
+	require.Empty(t, l.counters)
+}
+
+func TestMemoryStore_WindowChanged(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	l := newMemoryStore()
+	l.now = func() time.Time { return now }
+	require.NoError(t, l.Charge(t.Context(), "a", time.Minute, 100))
+	require.Zero(t, requireUsage(t, l, "a", time.Hour))
+	require.NoError(t, l.Charge(t.Context(), "a", time.Hour, 5))
+	require.Equal(t, uint64(5), requireUsage(t, l, "a", time.Hour))
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	l := newMemoryStore()
+	l.now = func() time.Time { return now }
+	require.NoError(t, l.Charge(t.Context(), "stale", time.Second, 1))
+	now = now.Add(time.Hour)
+	for range sweepInterval {
+		require.NoError(t, l.Charge(t.Context(), "fresh", time.Minute, 1))
+	}
+	require.Len(t, l.counters, 1)
+	require.Contains(t, l.counters, "fresh")
+}
+
+func TestMemoryStore_Claim(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	l := newMemoryStore()
+	l.now = func() time.Time { return now }
+	require.True(t, requireClaim(t, l, "a", time.Hour))
+	require.False(t, requireClaim(t, l, "a", time.Hour))
+	require.True(t, requireClaim(t, l, "b", time.Hour))
+
+	// The claim can be made again once it has expired, and the expired claims are swept.
+	now = now.Add(time.Hour)
+	require.True(t, requireClaim(t, l, "a", time.Hour))
+	for i := range sweepInterval {
+		require.NoError(t, l.Charge(t.Context(), "counter", time.Minute, uint64(i+1)))
+	}
+	require.Len(t, l.claims, 1)
+	require.Contains(t, l.claims, "a")
+}
+
+func requireClaim(t *testing.T, s Store, key string, ttl time.Duration) bool {
+	t.Helper()
+	claimed, err := s.Claim(t.Context(), key, ttl)
+	require.NoError(t, err)
+	return claimed
+}
+
+func requireUsage(t *testing.T, s Store, key string, window time.Duration) uint64 {
+	t.Helper()
+	usage, err := s.Usage(t.Context(), key, window)
+	require.NoError(t, err)
+	return usage
+}
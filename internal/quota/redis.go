@@ -0,0 +1,149 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix is the prefix of all the keys written by the Redis store.
+const redisKeyPrefix = "aigw:quota:"
+
+// redisStore is the [Store] backed by a server speaking the Redis serialization protocol (RESP), such as
+// Redis or Valkey. The counters are shared by all the external processors pointing to the same server.
+//
+// Each fixed window of a bucket is a separate key expiring after two windows, so the server does not
+// need any scripting support. The key of a bucket is wrapped in a hash tag so that the current and the
+// previous windows are stored in the same slot when the server is a cluster.
+type redisStore struct {
+	client redis.UniversalClient
+
+	// now is the clock used by the store. Overridable for testing.
+	now func() time.Time
+}
+
+// NewRedisStore creates a new [Store] backed by the Redis server at the given URL.
+//
+// The URL has the form "redis://[username@]host[:port][/db]", or "rediss://..." to connect over TLS, and accepts
+// the query parameters documented by [redis.ParseURL]. When cluster is true, the URL points to one of the nodes of
+// a Redis Cluster and the additional seed nodes are given with the "addr" query parameter as documented by
+// [redis.ParseClusterURL]; the client then follows the MOVED and ASK redirects of the cluster.
+//
+// The password is given separately since it comes from a secret, and the URL is rejected if it embeds one.
+//
+// The connections are established lazily, so this only fails if the URL is invalid.
+func NewRedisStore(rawURL, password string, cluster bool) (Store, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	if _, ok := u.User.Password(); ok {
+		return nil, errors.New("invalid Redis URL: the password must not be embedded in the URL")
+	}
+	// The URL parsers of the client take a lone username as the password, so the credentials are set explicitly.
+	username := u.User.Username()
+
+	var client redis.UniversalClient
+	if cluster {
+		opts, err := redis.ParseClusterURL(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Redis cluster URL: %w", err)
+		}
+		opts.Username, opts.Password = username, password
+		client = redis.NewClusterClient(opts)
+	} else {
+		opts, err := redis.ParseURL(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Redis URL: %w", err)
+		}
+		opts.Username, opts.Password = username, password
+		client = redis.NewClient(opts)
+	}
+	return newRedisStore(client), nil
+}
+
+func newRedisStore(client redis.UniversalClient) *redisStore {
+	return &redisStore{client: client, now: time.Now}
+}
+
+// Usage implements [Store.Usage].
+func (s *redisStore) Usage(ctx context.Context, key string, window time.Duration) (uint64, error) {
+	if window <= 0 {
+		return 0, nil
+	}
+	current, previous, elapsed := s.keys(key, window, s.now())
+	values, err := s.client.MGet(ctx, current, previous).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the counters: %w", err)
+	}
+	var counts [2]uint64
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+		case string:
+			if counts[i], err = strconv.ParseUint(v, 10, 64); err != nil {
+				return 0, fmt.Errorf("invalid counter value %q: %w", v, err)
+			}
+		default:
+			return 0, fmt.Errorf("unexpected counter value %v", v)
+		}
+	}
+	return slidingWindowUsage(counts[0], counts[1], elapsed, window), nil
+}
+
+// Charge implements [Store.Charge].
+func (s *redisStore) Charge(ctx context.Context, key string, window time.Duration, cost uint64) error {
+	if window <= 0 || cost == 0 {
+		return nil
+	}
+	if cost > math.MaxInt64 {
+		return errors.New("cost overflows the counter")
+	}
+	current, _, _ := s.keys(key, window, s.now())
+	_, err := s.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		p.IncrBy(ctx, current, int64(cost))
+		p.PExpire(ctx, current, 2*window)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to charge the counter: %w", err)
+	}
+	return nil
+}
+
+// Claim implements [Store.Claim].
+func (s *redisStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	claimed, err := s.client.SetNX(ctx, redisKeyPrefix+"claim:{"+key+"}", 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim the key: %w", err)
+	}
+	return claimed, nil
+}
+
+// Close implements [Store.Close].
+func (s *redisStore) Close() error {
+	return s.client.Close()
+}
+
+// keys returns the keys of the current and the previous fixed windows of the bucket as well as the time
+// elapsed since the start of the current fixed window.
+//
+// The window is part of the key so that reconfiguring the duration of a bucket starts from zero.
+func (s *redisStore) keys(key string, window time.Duration, now time.Time) (current, previous string, elapsed time.Duration) {
+	start := now.Truncate(window)
+	base := redisKeyPrefix + "{" + key + "}:" + strconv.FormatInt(window.Milliseconds(), 10) + ":"
+	current = base + strconv.FormatInt(start.UnixMilli(), 10)
+	previous = base + strconv.FormatInt(start.Add(-window).UnixMilli(), 10)
+	return current, previous, now.Sub(start)
+}
@@ -0,0 +1,44 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package quota implements the counter stores used by the external processor to enforce QuotaPolicy.
+//
+// All stores implement the same approximate sliding window: each bucket tracks the usage of the current and
+// the previous fixed window aligned to the Unix epoch, and the usage within the sliding window ending now is
+// estimated by weighting the previous window with the fraction of it that still overlaps the sliding window.
+// This is the same approximation used by most rate limiting implementations, and it only requires two integers
+// per bucket.
+package quota
+
+import (
+	"context"
+	"time"
+)
+
+// Store keeps the quota counters keyed by the bucket.
+//
+// The window of a bucket is passed on every call rather than configured upfront so that the store does not need
+// to be aware of the QuotaPolicy configuration. Implementations must be safe for concurrent use.
+type Store interface {
+	// Usage returns the estimated usage of the bucket identified by key within the sliding window ending now.
+	Usage(ctx context.Context, key string, window time.Duration) (uint64, error)
+	// Charge adds cost to the bucket identified by key.
+	Charge(ctx context.Context, key string, window time.Duration, cost uint64) error
+	// Claim records key for the ttl and returns true if it was not already recorded. This is used to do something
+	// only once across all the external processors sharing the store, e.g. charging the usage of a batch.
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Close releases the resources held by the store.
+	Close() error
+}
+
+// slidingWindowUsage returns the sliding window estimate given the usage of the current and the previous fixed
+// windows, and the time elapsed since the start of the current fixed window.
+func slidingWindowUsage(current, previous uint64, elapsed, window time.Duration) uint64 {
+	if elapsed >= window {
+		return current
+	}
+	weight := 1 - float64(elapsed)/float64(window)
+	return current + uint64(float64(previous)*weight)
+}
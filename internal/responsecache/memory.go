@@ -0,0 +1,88 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package responsecache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultMemoryStoreCapacity is the maximum number of responses kept by the in-memory store.
+const defaultMemoryStoreCapacity = 10_000
+
+// memoryStore is the in-process [Store]. The responses are neither shared across processes nor persisted.
+//
+// The oldest responses are evicted once the number of responses exceeds the capacity. The semantic lookup
+// scans all the entries of the namespace, which is fine for the capacity of the store.
+type memoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*Entry
+	// order is the keys in the insertion order, used for the eviction.
+	order    []string
+	capacity int
+	now      func() time.Time
+}
+
+// NewMemoryStore creates a new in-memory [Store].
+func NewMemoryStore() Store {
+	return newMemoryStore(defaultMemoryStoreCapacity, time.Now)
+}
+
+func newMemoryStore(capacity int, now func() time.Time) *memoryStore {
+	return &memoryStore{entries: make(map[string]*Entry), capacity: capacity, now: now}
+}
+
+// Get implements [Store.Get].
+func (m *memoryStore) Get(_ context.Context, key string) (*Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok || !m.now().Before(e.ExpiresAt) {
+		return nil, ErrNotFound
+	}
+	return e, nil
+}
+
+// Put implements [Store.Put].
+func (m *memoryStore) Put(_ context.Context, key string, entry *Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.entries[key]; !ok {
+		m.order = append(m.order, key)
+	}
+	m.entries[key] = entry
+	for len(m.entries) > m.capacity {
+		var oldest string
+		oldest, m.order = m.order[0], m.order[1:]
+		delete(m.entries, oldest)
+	}
+	return nil
+}
+
+// Nearest implements [Store.Nearest].
+func (m *memoryStore) Nearest(_ context.Context, namespace string, embedding []float32, threshold float64) (*Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	var nearest *Entry
+	best := threshold
+	for _, e := range m.entries {
+		if e.Namespace != namespace || e.Embedding == nil || !now.Before(e.ExpiresAt) {
+			continue
+		}
+		if s := Similarity(embedding, e.Embedding); s >= best {
+			nearest, best = e, s
+		}
+	}
+	if nearest == nil {
+		return nil, ErrNotFound
+	}
+	return nearest, nil
+}
+
+// Close implements [Store.Close].
+func (m *memoryStore) Close() error { return nil }
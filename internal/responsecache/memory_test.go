@@ -0,0 +1,83 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package responsecache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_GetPut(t *testing.T) {
+	now := time.Unix(1000, 0)
+	m := newMemoryStore(10, func() time.Time { return now })
+
+	_, err := m.Get(t.Context(), "k")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, m.Put(t.Context(), "k", &Entry{Body: []byte(`{"id":"1"}`), ExpiresAt: now.Add(time.Minute)}))
+	got, err := m.Get(t.Context(), "k")
+	require.NoError(t, err)
+	require.Equal(t, `{"id":"1"}`, string(got.Body))
+
+	// Put replaces the existing entry.
+	require.NoError(t, m.Put(t.Context(), "k", &Entry{Body: []byte(`{"id":"2"}`), ExpiresAt: now.Add(time.Minute)}))
+	got, err = m.Get(t.Context(), "k")
+	require.NoError(t, err)
+	require.Equal(t, `{"id":"2"}`, string(got.Body))
+	require.Equal(t, []string{"k"}, m.order)
+
+	// The expired entries are not returned.
+	now = now.Add(time.Minute)
+	_, err = m.Get(t.Context(), "k")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.NoError(t, m.Close())
+}
+
+func TestMemoryStore_Eviction(t *testing.T) {
+	m := newMemoryStore(2, time.Now)
+	expiresAt := time.Now().Add(time.Hour)
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, m.Put(t.Context(), key, &Entry{ExpiresAt: expiresAt}))
+	}
+	_, err := m.Get(t.Context(), "a")
+	require.ErrorIs(t, err, ErrNotFound)
+	for _, key := range []string{"b", "c"} {
+		_, err = m.Get(t.Context(), key)
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{"b", "c"}, m.order)
+}
+
+func TestMemoryStore_Nearest(t *testing.T) {
+	now := time.Unix(1000, 0)
+	m := newMemoryStore(10, func() time.Time { return now })
+	expiresAt := now.Add(time.Minute)
+	require.NoError(t, m.Put(t.Context(), "a", &Entry{Namespace: "ns", Body: []byte("a"), Embedding: []float32{1, 0}, ExpiresAt: expiresAt}))
+	require.NoError(t, m.Put(t.Context(), "b", &Entry{Namespace: "ns", Body: []byte("b"), Embedding: []float32{0.9, 0.1}, ExpiresAt: expiresAt}))
+	require.NoError(t, m.Put(t.Context(), "c", &Entry{Namespace: "other", Body: []byte("c"), Embedding: []float32{1, 0}, ExpiresAt: expiresAt}))
+	require.NoError(t, m.Put(t.Context(), "d", &Entry{Namespace: "ns", Body: []byte("d"), ExpiresAt: expiresAt}))
+
+	got, err := m.Nearest(t.Context(), "ns", []float32{1, 0.05}, 0.9)
+	require.NoError(t, err)
+	require.Equal(t, "a", string(got.Body))
+
+	got, err = m.Nearest(t.Context(), "ns", []float32{0.85, 0.15}, 0.9)
+	require.NoError(t, err)
+	require.Equal(t, "b", string(got.Body))
+
+	_, err = m.Nearest(t.Context(), "ns", []float32{0, 1}, 0.9)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = m.Nearest(t.Context(), "missing", []float32{1, 0}, 0.5)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// The expired entries are not returned.
+	now = expiresAt
+	_, err = m.Nearest(t.Context(), "ns", []float32{1, 0}, 0.5)
+	require.ErrorIs(t, err, ErrNotFound)
+}
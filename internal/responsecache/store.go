@@ -0,0 +1,139 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package responsecache implements the stores used by the external processor to cache the responses of the
+// LLM requests at the gateway.
+//
+// A response is cached under the key derived from the normalized request body, so that the same request is
+// answered without calling the backend until the entry expires. Optionally, the embedding of the text of the
+// request is kept with the entry so that the semantically similar requests are answered from the cache as well.
+package responsecache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// ErrNotFound is returned when no cached response matches the request.
+var ErrNotFound = errors.New("cached response not found")
+
+// Entry is a cached response.
+type Entry struct {
+	// Namespace groups the entries compared by the semantic lookup, i.e. the ones of the same route, endpoint
+	// and model.
+	Namespace string
+	// Body is the response body as returned to the client.
+	Body []byte
+	// Stream is true if the Body is the server-sent events of a streaming response.
+	Stream bool
+	// Embedding is the embedding of the text of the request. Nil if the semantic lookup is not enabled.
+	Embedding []float32
+	// ExpiresAt is the time when the entry expires.
+	ExpiresAt time.Time
+}
+
+// Store keeps the cached responses keyed by [Key]. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the unexpired entry identified by key, or ErrNotFound if it does not exist.
+	Get(ctx context.Context, key string) (*Entry, error)
+	// Put saves the entry, replacing the existing one with the same key if any.
+	Put(ctx context.Context, key string, entry *Entry) error
+	// Nearest returns the unexpired entry in the namespace whose embedding is the most similar to the given one,
+	// or ErrNotFound if the cosine similarity of none of them is at least the threshold.
+	Nearest(ctx context.Context, namespace string, embedding []float32, threshold float64) (*Entry, error)
+	// Close releases the resources held by the store.
+	Close() error
+}
+
+// streamFields are the fields of the request body that only select how the response is delivered. They are
+// ignored by the key so that the streaming and the non-streaming requests share the cached responses.
+var streamFields = []string{"stream", "stream_options"}
+
+// Key returns the key of the response to the request body in the namespace. The body is normalized so that
+// the order of the fields and the formatting do not matter.
+func Key(namespace string, body []byte) (string, error) {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "", fmt.Errorf("failed to parse the request body: %w", err)
+	}
+	if m, ok := v.(map[string]any); ok {
+		for _, f := range streamFields {
+			delete(m, f)
+		}
+	}
+	h := sha256.New()
+	h.Write([]byte(namespace))
+	h.Write([]byte{0})
+	writeCanonical(h, v)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeCanonical writes the JSON value to the hash with the object keys sorted.
+func writeCanonical(h hash.Hash, v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		h.Write([]byte{'{'})
+		for _, k := range keys {
+			writeCanonical(h, k)
+			h.Write([]byte{':'})
+			writeCanonical(h, v[k])
+		}
+		h.Write([]byte{'}'})
+	case []any:
+		h.Write([]byte{'['})
+		for _, e := range v {
+			writeCanonical(h, e)
+			h.Write([]byte{','})
+		}
+		h.Write([]byte{']'})
+	default:
+		b, _ := json.Marshal(v)
+		h.Write(b)
+	}
+}
+
+// Similarity returns the cosine similarity of the two embeddings, or zero if their dimensions differ.
+func Similarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		na += x * x
+		nb += y * y
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// Namespace returns the namespace of the entries of the requests of the owner to the model at the path of the route.
+// The owner is the value of the owner header of the requests, which is only kept hashed.
+func Namespace(routeName, owner, path, model string) string {
+	h := sha256.New()
+	for _, s := range []string{routeName, owner, path, model} {
+		_ = binary.Write(h, binary.LittleEndian, uint32(len(s))) //nolint:gosec
+		h.Write([]byte(s))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
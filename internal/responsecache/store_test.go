@@ -0,0 +1,61 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package responsecache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	key, err := Key("ns", []byte(`{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}],"temperature":0.5}`))
+	require.NoError(t, err)
+	require.Len(t, key, 64)
+
+	for _, body := range []string{
+		// Field order and formatting.
+		`{ "temperature": 0.5, "messages": [{"content": "hi", "role": "user"}], "model": "gpt-4o" }`,
+		// Streaming.
+		`{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}],"temperature":0.5,"stream":true,"stream_options":{"include_usage":true}}`,
+	} {
+		got, err := Key("ns", []byte(body))
+		require.NoError(t, err)
+		require.Equal(t, key, got, body)
+	}
+
+	for _, tc := range []struct{ namespace, body string }{
+		{"other", `{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}],"temperature":0.5}`},
+		{"ns", `{"model":"gpt-4o","messages":[{"role":"user","content":"hello"}],"temperature":0.5}`},
+		{"ns", `{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}],"temperature":0.7}`},
+		{"ns", `{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}],"temperature":"0.5"}`},
+	} {
+		got, err := Key(tc.namespace, []byte(tc.body))
+		require.NoError(t, err)
+		require.NotEqual(t, key, got, tc.body)
+	}
+
+	_, err = Key("ns", []byte(`{`))
+	require.ErrorContains(t, err, "failed to parse the request body")
+}
+
+func TestSimilarity(t *testing.T) {
+	require.InDelta(t, 1.0, Similarity([]float32{1, 2}, []float32{2, 4}), 1e-9)
+	require.InDelta(t, 0.0, Similarity([]float32{1, 0}, []float32{0, 1}), 1e-9)
+	require.InDelta(t, -1.0, Similarity([]float32{1, 0}, []float32{-1, 0}), 1e-9)
+	require.Zero(t, Similarity([]float32{1, 0}, []float32{1}))
+	require.Zero(t, Similarity([]float32{0, 0}, []float32{1, 0}))
+	require.Zero(t, Similarity(nil, nil))
+}
+
+func TestNamespace(t *testing.T) {
+	ns := Namespace("default/route", "Bearer a", "/v1/chat/completions", "gpt-4o")
+	require.Equal(t, ns, Namespace("default/route", "Bearer a", "/v1/chat/completions", "gpt-4o"))
+	require.NotEqual(t, ns, Namespace("default/route", "Bearer a", "/v1/chat/completions", "gpt-4o-mini"))
+	require.NotEqual(t, ns, Namespace("default/route", "Bearer b", "/v1/chat/completions", "gpt-4o"))
+	require.NotEqual(t, ns, Namespace("other/route", "Bearer a", "/v1/chat/completions", "gpt-4o"))
+	require.NotEqual(t, ns, Namespace("default/route/v1", "Bearer a", "/chat/completions", "gpt-4o"))
+}
@@ -0,0 +1,198 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package responsestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// fileStore is the [Store] that keeps each response as a JSON file in a directory, so that the responses survive
+// the restarts of the external processor. This is meant for the standalone mode where a single process serves
+// the traffic.
+//
+// As with the in-memory store, the oldest responses are evicted once the number of responses exceeds the capacity
+// so that the directory does not grow without bound. The responses already in the directory when the store is
+// created are counted in the order of their modification time.
+type fileStore struct {
+	dir string
+
+	mu sync.Mutex
+	// ids is the set of the IDs of the stored responses.
+	ids map[string]struct{}
+	// order is the IDs in the insertion order, used for the eviction.
+	order    []string
+	capacity int
+}
+
+// NewFileStore creates a new [Store] that keeps the responses in the given directory. The directory is created
+// if it does not exist.
+func NewFileStore(dir string) (Store, error) {
+	return newFileStore(dir, defaultMemoryStoreCapacity)
+}
+
+func newFileStore(dir string, capacity int) (*fileStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create response store directory %s: %w", dir, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response store directory %s: %w", dir, err)
+	}
+	f := &fileStore{dir: dir, ids: make(map[string]struct{}), capacity: capacity}
+	type stored struct {
+		id      string
+		modTime int64
+	}
+	var existing []stored
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		if _, ok = f.path(id); !ok {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		existing = append(existing, stored{id: id, modTime: info.ModTime().UnixNano()})
+	}
+	slices.SortStableFunc(existing, func(a, b stored) int {
+		switch {
+		case a.modTime < b.modTime:
+			return -1
+		case a.modTime > b.modTime:
+			return 1
+		}
+		return strings.Compare(a.id, b.id)
+	})
+	for _, s := range existing {
+		f.ids[s.id] = struct{}{}
+		f.order = append(f.order, s.id)
+	}
+	if err = f.evict(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Get implements [Store.Get].
+func (f *fileStore) Get(_ context.Context, id string) (*Response, error) {
+	path, ok := f.path(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read response %s: %w", id, err)
+	}
+	var resp Response
+	if err = json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response %s: %w", id, err)
+	}
+	return &resp, nil
+}
+
+// Put implements [Store.Put].
+func (f *fileStore) Put(_ context.Context, resp *Response) error {
+	path, ok := f.path(resp.ID)
+	if !ok {
+		return fmt.Errorf("invalid response ID %q", resp.ID)
+	}
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response %s: %w", resp.ID, err)
+	}
+	// Write to a temporary file and rename it so that concurrent readers never observe a partially written file.
+	tmp, err := os.CreateTemp(f.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err = tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write response %s: %w", resp.ID, err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write response %s: %w", resp.ID, err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write response %s: %w", resp.ID, err)
+	}
+	if _, ok = f.ids[resp.ID]; !ok {
+		f.ids[resp.ID] = struct{}{}
+		f.order = append(f.order, resp.ID)
+	}
+	return f.evict()
+}
+
+// Delete implements [Store.Delete].
+func (f *fileStore) Delete(_ context.Context, id string) error {
+	path, ok := f.path(id)
+	if !ok {
+		return ErrNotFound
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	err := os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to delete response %s: %w", id, err)
+	}
+	delete(f.ids, id)
+	f.order = slices.DeleteFunc(f.order, func(o string) bool { return o == id })
+	return nil
+}
+
+// Close implements [Store.Close].
+func (f *fileStore) Close() error { return nil }
+
+// evict removes the oldest responses while the number of responses exceeds the capacity. The caller must hold
+// the lock except during the construction.
+func (f *fileStore) evict() error {
+	for len(f.order) > f.capacity {
+		var oldest string
+		oldest, f.order = f.order[0], f.order[1:]
+		delete(f.ids, oldest)
+		path, _ := f.path(oldest)
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to evict response %s: %w", oldest, err)
+		}
+	}
+	return nil
+}
+
+// path returns the path of the file for the response ID. This returns false if the ID cannot be safely used as
+// a file name, which never happens for the IDs generated by the backends.
+func (f *fileStore) path(id string) (string, bool) {
+	if id == "" || len(id) > 256 {
+		return "", false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '-':
+		default:
+			return "", false
+		}
+	}
+	return filepath.Join(f.dir, id+".json"), true
+}
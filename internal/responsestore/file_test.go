@@ -0,0 +1,107 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package responsestore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	s, err := NewFileStore(filepath.Join(t.TempDir(), "responses"))
+	require.NoError(t, err)
+	testStore(t, s)
+}
+
+func TestFileStore_Persistence(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, s.Put(t.Context(), &Response{ID: "resp_1", Input: []byte(`[]`), Body: []byte(`{"id":"resp_1"}`)}))
+	require.NoError(t, s.Close())
+
+	// The responses are visible to the store created later on the same directory.
+	s, err = NewFileStore(dir)
+	require.NoError(t, err)
+	got, err := s.Get(t.Context(), "resp_1")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"resp_1"}`, string(got.Body))
+
+	// No temporary files are left behind.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "resp_1.json", entries[0].Name())
+}
+
+func TestFileStore_InvalidID(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileStore(filepath.Join(dir, "responses"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret.json"), []byte(`{}`), 0o600))
+
+	for _, id := range []string{"", "../secret", "a/b", "resp.1"} {
+		t.Run(id, func(t *testing.T) {
+			_, err := s.Get(t.Context(), id)
+			require.ErrorIs(t, err, ErrNotFound)
+			require.ErrorIs(t, s.Delete(t.Context(), id), ErrNotFound)
+			require.ErrorContains(t, s.Put(t.Context(), &Response{ID: id}), "invalid response ID")
+		})
+	}
+	require.FileExists(t, filepath.Join(dir, "secret.json"))
+}
+
+func TestFileStore_CorruptedFile(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "resp_1.json"), []byte(`{`), 0o600))
+	_, err = s.Get(t.Context(), "resp_1")
+	require.ErrorContains(t, err, "failed to unmarshal response resp_1")
+}
+
+func TestFileStore_Eviction(t *testing.T) {
+	dir := t.TempDir()
+	// The responses already in the directory are counted in the order of their modification time.
+	for i, id := range []string{"b", "a"} {
+		path := filepath.Join(dir, id+".json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"id":"`+id+`"}`), 0o600))
+		modTime := time.Unix(int64(i), 0)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(`x`), 0o600))
+
+	f, err := newFileStore(dir, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "a"}, f.order)
+
+	require.NoError(t, f.Put(t.Context(), &Response{ID: "c"}))
+	_, err = f.Get(t.Context(), "b")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.NoFileExists(t, filepath.Join(dir, "b.json"))
+	for _, id := range []string{"a", "c"} {
+		_, err = f.Get(t.Context(), id)
+		require.NoError(t, err)
+	}
+
+	// The deleted responses are not counted towards the capacity.
+	require.NoError(t, f.Delete(t.Context(), "a"))
+	require.NoError(t, f.Put(t.Context(), &Response{ID: "d"}))
+	require.Equal(t, []string{"c", "d"}, f.order)
+	require.FileExists(t, filepath.Join(dir, "notes.txt"))
+
+	// The existing responses over the capacity are evicted when the store is created.
+	f, err = newFileStore(dir, 1)
+	require.NoError(t, err)
+	require.Len(t, f.order, 1)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
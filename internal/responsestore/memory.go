@@ -0,0 +1,78 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package responsestore
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// defaultMemoryStoreCapacity is the maximum number of responses kept by the in-memory store.
+const defaultMemoryStoreCapacity = 10_000
+
+// memoryStore is the in-process [Store]. The responses are neither shared across processes nor persisted, so
+// this is only suitable when a single external processor serves the traffic, such as in the standalone mode.
+//
+// The oldest responses are evicted once the number of responses exceeds the capacity.
+type memoryStore struct {
+	mu        sync.Mutex
+	responses map[string]*Response
+	// order is the IDs in the insertion order, used for the eviction.
+	order    []string
+	capacity int
+}
+
+// NewMemoryStore creates a new in-memory [Store].
+func NewMemoryStore() Store {
+	return newMemoryStore(defaultMemoryStoreCapacity)
+}
+
+func newMemoryStore(capacity int) *memoryStore {
+	return &memoryStore{responses: make(map[string]*Response), capacity: capacity}
+}
+
+// Get implements [Store.Get].
+func (m *memoryStore) Get(_ context.Context, id string) (*Response, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	resp, ok := m.responses[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return resp, nil
+}
+
+// Put implements [Store.Put].
+func (m *memoryStore) Put(_ context.Context, resp *Response) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.responses[resp.ID]; !ok {
+		m.order = append(m.order, resp.ID)
+	}
+	m.responses[resp.ID] = resp
+	for len(m.responses) > m.capacity {
+		var oldest string
+		oldest, m.order = m.order[0], m.order[1:]
+		delete(m.responses, oldest)
+	}
+	return nil
+}
+
+// Delete implements [Store.Delete].
+func (m *memoryStore) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.responses[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.responses, id)
+	m.order = slices.DeleteFunc(m.order, func(o string) bool { return o == id })
+	return nil
+}
+
+// Close implements [Store.Close].
+func (m *memoryStore) Close() error { return nil }
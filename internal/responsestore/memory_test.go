@@ -0,0 +1,63 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package responsestore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestMemoryStore_Eviction(t *testing.T) {
+	m := newMemoryStore(2)
+	for _, id := range []string{"a", "b", "c"} {
+		require.NoError(t, m.Put(t.Context(), &Response{ID: id}))
+	}
+	_, err := m.Get(t.Context(), "a")
+	require.ErrorIs(t, err, ErrNotFound)
+	for _, id := range []string{"b", "c"} {
+		_, err = m.Get(t.Context(), id)
+		require.NoError(t, err)
+	}
+
+	// The deleted responses are not counted towards the capacity.
+	require.NoError(t, m.Delete(t.Context(), "b"))
+	require.NoError(t, m.Put(t.Context(), &Response{ID: "d"}))
+	require.Equal(t, []string{"c", "d"}, m.order)
+	require.Len(t, m.responses, 2)
+}
+
+// testStore runs the tests common to all the Store implementations.
+func testStore(t *testing.T, s Store) {
+	t.Helper()
+	defer func() { require.NoError(t, s.Close()) }()
+
+	_, err := s.Get(t.Context(), "resp_1")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, s.Delete(t.Context(), "resp_1"), ErrNotFound)
+
+	resp := &Response{ID: "resp_1", Input: []byte(`[{"role":"user","content":"hi"}]`), Body: []byte(`{"id":"resp_1"}`)}
+	require.NoError(t, s.Put(t.Context(), resp))
+	got, err := s.Get(t.Context(), "resp_1")
+	require.NoError(t, err)
+	require.Equal(t, "resp_1", got.ID)
+	require.JSONEq(t, string(resp.Input), string(got.Input))
+	require.JSONEq(t, string(resp.Body), string(got.Body))
+
+	// Put replaces the existing response.
+	require.NoError(t, s.Put(t.Context(), &Response{ID: "resp_1", Input: []byte(`[]`), Body: []byte(`{"id":"resp_1","status":"completed"}`)}))
+	got, err = s.Get(t.Context(), "resp_1")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"resp_1","status":"completed"}`, string(got.Body))
+
+	require.NoError(t, s.Delete(t.Context(), "resp_1"))
+	_, err = s.Get(t.Context(), "resp_1")
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,143 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package responsestore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// ExpandInput rebuilds the Responses API request body that continues from a stored response.
+//
+// When "previous_response_id" refers to a response of the owner in the store, the returned body has the input
+// items of the stored response, followed by its output items and the input items of the request, and
+// "previous_response_id" removed. This returns nil if the request does not continue from a stored response of
+// the owner, in which case the request is left to the backend as is.
+func ExpandInput(ctx context.Context, store Store, body []byte, owner string) ([]byte, error) {
+	previousID := gjson.GetBytes(body, "previous_response_id").String()
+	if previousID == "" {
+		return nil, nil
+	}
+	previous, err := GetOwned(ctx, store, previousID, owner)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get previous response %s: %w", previousID, err)
+	}
+
+	var items []string
+	items = appendItems(items, gjson.ParseBytes(previous.Input))
+	items = appendItems(items, gjson.GetBytes(previous.Body, "output"))
+	items = appendItems(items, gjson.GetBytes(body, "input"))
+	expanded, err := sjson.SetRawBytes(body, "input", joinItems(items))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set the input: %w", err)
+	}
+	expanded, err = sjson.DeleteBytes(expanded, "previous_response_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete previous_response_id: %w", err)
+	}
+	return expanded, nil
+}
+
+// appendItems appends the raw JSON of the input items in v to items. A string input is the shorthand of a single
+// user message.
+func appendItems(items []string, v gjson.Result) []string {
+	switch {
+	case v.Type == gjson.String:
+		item, _ := sjson.SetRaw(`{"type":"message","role":"user"}`, "content", v.Raw)
+		return append(items, item)
+	case v.IsArray():
+		for _, item := range v.Array() {
+			items = append(items, item.Raw)
+		}
+	}
+	return items
+}
+
+// joinItems returns the JSON array of the raw JSON items.
+func joinItems(items []string) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, item := range items {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(item)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes()
+}
+
+// Recorder captures the response returned to the client for a Responses API request so that it can be saved
+// in the store once finished.
+type Recorder struct {
+	input  []byte
+	owner  string
+	stream bool
+	// buf is the whole body for non-streaming responses, or the incomplete line for streaming responses.
+	buf []byte
+	// final is the final response object found in the event stream.
+	final []byte
+}
+
+// NewRecorder creates a [Recorder] for the given request body, which must be the one after [ExpandInput], and
+// the owner of the request. This returns nil if the request opted out of the storage with "store": false.
+func NewRecorder(requestBody []byte, stream bool, owner string) *Recorder {
+	if s := gjson.GetBytes(requestBody, "store"); s.Exists() && !s.Bool() {
+		return nil
+	}
+	return &Recorder{
+		input:  joinItems(appendItems(nil, gjson.GetBytes(requestBody, "input"))),
+		owner:  owner,
+		stream: stream,
+	}
+}
+
+// Write appends a chunk of the response body returned to the client.
+func (r *Recorder) Write(chunk []byte) {
+	r.buf = append(r.buf, chunk...)
+	if !r.stream {
+		return
+	}
+	for {
+		i := bytes.IndexByte(r.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimSpace(r.buf[:i])
+		r.buf = r.buf[i+1:]
+		data, ok := bytes.CutPrefix(line, []byte("data:"))
+		if !ok {
+			continue
+		}
+		switch event := gjson.ParseBytes(bytes.TrimSpace(data)); event.Get("type").String() {
+		case "response.completed", "response.incomplete":
+			r.final = []byte(event.Get("response").Raw)
+		}
+	}
+	// Drop the consumed bytes so that the buffer does not keep the whole stream alive.
+	r.buf = bytes.Clone(r.buf)
+}
+
+// Response returns the response to be saved, or nil if the response did not finish successfully.
+func (r *Recorder) Response() *Response {
+	body := r.final
+	if !r.stream {
+		body = r.buf
+	}
+	id := gjson.GetBytes(body, "id").String()
+	if status := gjson.GetBytes(body, "status").String(); id == "" || (status != "completed" && status != "incomplete") {
+		return nil
+	}
+	return &Response{ID: id, Owner: r.owner, CreatedAt: time.Now(), Input: r.input, Body: body}
+}
@@ -0,0 +1,145 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package responsestore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandInput(t *testing.T) {
+	s := NewMemoryStore()
+	require.NoError(t, s.Put(t.Context(), &Response{
+		ID:    "resp_1",
+		Owner: "owner",
+		Input: []byte(`[{"type":"message","role":"user","content":"What is the capital of France?"}]`),
+		Body:  []byte(`{"id":"resp_1","status":"completed","output":[{"type":"message","id":"msg_1","role":"assistant","content":[{"type":"output_text","text":"Paris."}]}]}`),
+	}))
+
+	for _, tc := range []struct {
+		name  string
+		body  string
+		owner string
+		exp   string
+	}{
+		{
+			name: "no previous response",
+			body: `{"model":"m","input":"hi"}`,
+		},
+		{
+			name: "unknown previous response",
+			body: `{"model":"m","input":"hi","previous_response_id":"resp_unknown"}`,
+		},
+		{
+			name:  "previous response of another owner",
+			body:  `{"model":"m","input":"hi","previous_response_id":"resp_1"}`,
+			owner: "another",
+		},
+		{
+			name:  "string input",
+			owner: "owner",
+			body:  `{"model":"m","input":"And Germany?","previous_response_id":"resp_1","stream":true}`,
+			exp: `{"model":"m","input":[
+{"type":"message","role":"user","content":"What is the capital of France?"},
+{"type":"message","id":"msg_1","role":"assistant","content":[{"type":"output_text","text":"Paris."}]},
+{"type":"message","role":"user","content":"And Germany?"}
+],"stream":true}`,
+		},
+		{
+			name:  "array input",
+			owner: "owner",
+			body:  `{"previous_response_id":"resp_1","model":"m","input":[{"type":"function_call_output","call_id":"c","output":"ok"}]}`,
+			exp: `{"model":"m","input":[
+{"type":"message","role":"user","content":"What is the capital of France?"},
+{"type":"message","id":"msg_1","role":"assistant","content":[{"type":"output_text","text":"Paris."}]},
+{"type":"function_call_output","call_id":"c","output":"ok"}
+]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ExpandInput(t.Context(), s, []byte(tc.body), tc.owner)
+			require.NoError(t, err)
+			if tc.exp == "" {
+				require.Nil(t, got)
+				return
+			}
+			require.JSONEq(t, tc.exp, string(got))
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	t.Run("store disabled", func(t *testing.T) {
+		require.Nil(t, NewRecorder([]byte(`{"input":"hi","store":false}`), false, ""))
+	})
+
+	t.Run("non-streaming", func(t *testing.T) {
+		r := NewRecorder([]byte(`{"input":"hi","store":true}`), false, "owner")
+		require.NotNil(t, r)
+		r.Write([]byte(`{"id":"resp_1","status":`))
+		r.Write([]byte(`"completed","output":[]}`))
+		resp := r.Response()
+		require.NotNil(t, resp)
+		require.Equal(t, "resp_1", resp.ID)
+		require.Equal(t, "owner", resp.Owner)
+		require.JSONEq(t, `[{"type":"message","role":"user","content":"hi"}]`, string(resp.Input))
+		require.JSONEq(t, `{"id":"resp_1","status":"completed","output":[]}`, string(resp.Body))
+	})
+
+	t.Run("non-streaming failed", func(t *testing.T) {
+		r := NewRecorder([]byte(`{"input":"hi"}`), false, "")
+		r.Write([]byte(`{"id":"resp_1","status":"failed"}`))
+		require.Nil(t, r.Response())
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		r := NewRecorder([]byte(`{"input":[{"role":"user","content":"hi"}]}`), true, "")
+		r.Write([]byte("event: response.created\ndata: {\"type\":\"response.created\",\"response\":{\"id\":\"resp_1\",\"status\":\"in_progress\"}}\n\n"))
+		require.Nil(t, r.Response())
+		r.Write([]byte("event: response.completed\ndata: {\"type\":\"response.completed\",\"response\":"))
+		r.Write([]byte("{\"id\":\"resp_1\",\"status\":\"completed\",\"output\":[]}}\n\n"))
+		resp := r.Response()
+		require.NotNil(t, resp)
+		require.Equal(t, "resp_1", resp.ID)
+		require.JSONEq(t, `[{"role":"user","content":"hi"}]`, string(resp.Input))
+		require.JSONEq(t, `{"id":"resp_1","status":"completed","output":[]}`, string(resp.Body))
+		require.Empty(t, r.buf)
+	})
+
+	t.Run("streaming failed", func(t *testing.T) {
+		r := NewRecorder([]byte(`{"input":"hi"}`), true, "")
+		r.Write([]byte("event: response.failed\ndata: {\"type\":\"response.failed\",\"response\":{\"id\":\"resp_1\",\"status\":\"failed\"}}\n\n"))
+		require.Nil(t, r.Response())
+	})
+}
+
+func TestOwner(t *testing.T) {
+	require.Empty(t, Owner(""))
+	owner := Owner("Bearer sk-1")
+	require.Len(t, owner, 64)
+	require.NotContains(t, owner, "sk-1")
+	require.Equal(t, owner, Owner("Bearer sk-1"))
+	require.NotEqual(t, owner, Owner("Bearer sk-2"))
+}
+
+func TestGetOwned_DeleteOwned(t *testing.T) {
+	s := NewMemoryStore()
+	require.NoError(t, s.Put(t.Context(), &Response{ID: "resp_1", Owner: "owner"}))
+
+	_, err := GetOwned(t.Context(), s, "resp_1", "another")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, DeleteOwned(t.Context(), s, "resp_1", "another"), ErrNotFound)
+	_, err = GetOwned(t.Context(), s, "resp_unknown", "owner")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	resp, err := GetOwned(t.Context(), s, "resp_1", "owner")
+	require.NoError(t, err)
+	require.Equal(t, "resp_1", resp.ID)
+	require.NoError(t, DeleteOwned(t.Context(), s, "resp_1", "owner"))
+	_, err = s.Get(t.Context(), "resp_1")
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,89 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package responsestore implements the stores used by the external processor to keep the finished responses of
+// the OpenAI Responses API.
+//
+// Backends other than OpenAI have no server-side conversation state, so "previous_response_id" and the retrieval
+// of stored responses cannot work against them. Instead, the external processor saves the input and the output
+// items of each finished response keyed by the response ID, and rebuilds the full input of a subsequent request
+// that continues from it before the request is translated for the backend.
+//
+// Each response is owned by the caller that created it, identified by a request header such as the API key, and
+// is only visible to the requests of the same caller.
+package responsestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// ErrNotFound is returned when the requested response does not exist in the store.
+var ErrNotFound = errors.New("response not found")
+
+// Response is a finished response kept in the store.
+type Response struct {
+	// ID is the ID of the response, i.e. the "id" field of the response object.
+	ID string `json:"id"`
+	// Owner identifies the caller that created the response as returned by [Owner].
+	Owner string `json:"owner,omitempty"`
+	// CreatedAt is the time when the response was stored.
+	CreatedAt time.Time `json:"created_at"`
+	// Input is the JSON array of the input items the response was generated from. This includes the items
+	// carried over from the previous responses when the request continued from one.
+	Input json.RawMessage `json:"input"`
+	// Body is the JSON of the response object as returned to the client.
+	Body json.RawMessage `json:"body"`
+}
+
+// Store keeps the finished responses keyed by the response ID. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the response identified by id, or ErrNotFound if it does not exist.
+	Get(ctx context.Context, id string) (*Response, error)
+	// Put saves the response, replacing the existing one with the same ID if any.
+	Put(ctx context.Context, resp *Response) error
+	// Delete removes the response identified by id, or returns ErrNotFound if it does not exist.
+	Delete(ctx context.Context, id string) error
+	// Close releases the resources held by the store.
+	Close() error
+}
+
+// Owner returns the owner key of the responses created by the requests with the given value of the owner header.
+// The value is hashed since it is typically a credential such as the API key, which must not be written to the
+// store. Requests without the header share the empty owner.
+func Owner(headerValue string) string {
+	if headerValue == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(headerValue))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetOwned returns the response identified by id if it belongs to the owner. The responses of the other owners
+// are reported as ErrNotFound so that their existence is not disclosed.
+func GetOwned(ctx context.Context, store Store, id, owner string) (*Response, error) {
+	resp, err := store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Owner != owner {
+		return nil, ErrNotFound
+	}
+	return resp, nil
+}
+
+// DeleteOwned removes the response identified by id if it belongs to the owner, with the same semantics as
+// [GetOwned] otherwise.
+func DeleteOwned(ctx context.Context, store Store, id, owner string) error {
+	if _, err := GetOwned(ctx, store, id, owner); err != nil {
+		return err
+	}
+	return store.Delete(ctx, id)
+}
@@ -0,0 +1,149 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package tokenizer estimates the number of tokens of texts for the model families whose providers do not
+// expose a token counting API.
+//
+// The estimation pre-tokenizes the text into words, numbers, punctuation and CJK characters like the BPE and
+// SentencePiece tokenizers do, and then applies the average token lengths of the vocabulary of each family.
+// It is typically within 10-20% of the exact count for natural language and code, which is enough for the
+// context window management of the clients, but it must not be used for billing.
+package tokenizer
+
+import (
+	"math"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// Tokenizer estimates the number of tokens of texts for a model family.
+type Tokenizer struct {
+	// Family is the name of the model family.
+	Family string
+	// lettersPerToken is the average number of letters of a word per token.
+	lettersPerToken float64
+	// digitsPerToken is the maximum number of digits merged into a token.
+	digitsPerToken int
+	// tokensPerCJKChar is the average number of tokens per Chinese, Japanese or Korean character.
+	tokensPerCJKChar float64
+}
+
+var (
+	// o200k is the tokenizer of the OpenAI models since GPT-4o.
+	o200k = &Tokenizer{Family: "o200k", lettersPerToken: 6, digitsPerToken: 3, tokensPerCJKChar: 0.8}
+	// cl100k is the tokenizer of GPT-4 and GPT-3.5.
+	cl100k = &Tokenizer{Family: "cl100k", lettersPerToken: 5.5, digitsPerToken: 3, tokensPerCJKChar: 1.2}
+	// claude is the tokenizer of the Anthropic Claude models.
+	claude = &Tokenizer{Family: "claude", lettersPerToken: 5, digitsPerToken: 3, tokensPerCJKChar: 1}
+	// gemini is the SentencePiece tokenizer of the Google Gemini and Gemma models, which splits the digits.
+	gemini = &Tokenizer{Family: "gemini", lettersPerToken: 6, digitsPerToken: 1, tokensPerCJKChar: 0.7}
+	// llama is the tokenizer of the Meta Llama 3 models.
+	llama = &Tokenizer{Family: "llama", lettersPerToken: 5.5, digitsPerToken: 3, tokensPerCJKChar: 1}
+	// mistral is the SentencePiece tokenizer of the Mistral models, which splits the digits.
+	mistral = &Tokenizer{Family: "mistral", lettersPerToken: 4.5, digitsPerToken: 1, tokensPerCJKChar: 1.3}
+	// Default is the tokenizer used for the models of an unknown family.
+	Default = &Tokenizer{Family: "default", lettersPerToken: 5, digitsPerToken: 3, tokensPerCJKChar: 1}
+)
+
+// families maps the keywords found in the model names to the tokenizers, in the order of precedence.
+var families = []struct {
+	keywords  []string
+	tokenizer *Tokenizer
+}{
+	{keywords: []string{"claude"}, tokenizer: claude},
+	{keywords: []string{"gemini", "gemma"}, tokenizer: gemini},
+	{keywords: []string{"llama"}, tokenizer: llama},
+	{keywords: []string{"mistral", "mixtral", "codestral", "pixtral", "ministral"}, tokenizer: mistral},
+	{keywords: []string{"gpt-4o", "gpt-4.1", "gpt-4.5", "gpt-5", "gpt-oss", "chatgpt-4o"}, tokenizer: o200k},
+	{keywords: []string{"gpt-4", "gpt-3.5", "text-embedding"}, tokenizer: cl100k},
+}
+
+// ForModel returns the tokenizer of the family of the model, or [Default] when the family is unknown.
+//
+// The model may be prefixed by the provider like the AWS Bedrock model IDs, e.g. "anthropic.claude-sonnet-4",
+// or be a resource name like the GCP Vertex AI ones, e.g. "publishers/google/models/gemini-2.5-pro".
+func ForModel(model string) *Tokenizer {
+	model = strings.ToLower(path.Base(model))
+	for _, f := range families {
+		for _, keyword := range f.keywords {
+			if strings.Contains(model, keyword) {
+				return f.tokenizer
+			}
+		}
+	}
+	// The OpenAI reasoning models, e.g. o1, o3-mini and o4-mini, use the o200k tokenizer.
+	if len(model) >= 2 && model[0] == 'o' && model[1] >= '1' && model[1] <= '9' {
+		return o200k
+	}
+	return Default
+}
+
+// CountTokens returns the estimated number of tokens of the text.
+func (t *Tokenizer) CountTokens(text string) int {
+	var (
+		tokens float64
+		// run is the number of the runes of the current run of the same class.
+		run      int
+		runClass = classNone
+	)
+	flush := func() {
+		switch runClass {
+		case classLetter:
+			tokens += math.Ceil(float64(run) / t.lettersPerToken)
+		case classDigit:
+			tokens += math.Ceil(float64(run) / float64(t.digitsPerToken))
+		case classCJK:
+			tokens += math.Ceil(float64(run) * t.tokensPerCJKChar)
+		case classPunct:
+			// The common sequences of punctuation such as `{"`, `":` or `//` are merged in all the vocabularies.
+			tokens += math.Ceil(float64(run) / 2)
+		case classNewline:
+			tokens++
+		}
+		run, runClass = 0, classNone
+	}
+	for _, r := range text {
+		c := classify(r)
+		if c != runClass {
+			flush()
+			runClass = c
+		}
+		run++
+	}
+	flush()
+	return int(tokens)
+}
+
+// class is the class of a rune for the pre-tokenization.
+type class int
+
+const (
+	classNone class = iota
+	// classSpace is the spaces, which are merged into the following word by all the tokenizers.
+	classSpace
+	classNewline
+	classLetter
+	classDigit
+	classCJK
+	classPunct
+)
+
+func classify(r rune) class {
+	switch {
+	case r == '\n' || r == '\r':
+		return classNewline
+	case unicode.IsSpace(r):
+		return classSpace
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+		return classCJK
+	case unicode.IsLetter(r) || unicode.IsMark(r):
+		return classLetter
+	case unicode.IsDigit(r):
+		return classDigit
+	default:
+		return classPunct
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package tokenizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestForModel(t *testing.T) {
+	for model, exp := range map[string]*Tokenizer{
+		"claude-sonnet-4-5":                          claude,
+		"anthropic.claude-3-5-sonnet-20241022-v2:0":  claude,
+		"us.anthropic.claude-3-7-sonnet-20250219-v1": claude,
+		"claude-3-5-sonnet-v2@20241022":              claude,
+		"publishers/google/models/gemini-2.5-pro":    gemini,
+		"models/gemma-3-27b-it":                      gemini,
+		"meta.llama3-70b-instruct-v1:0":              llama,
+		"Meta-Llama-3.1-8B-Instruct":                 llama,
+		"mistral-large-latest":                       mistral,
+		"gpt-4o-mini":                                o200k,
+		"gpt-5":                                      o200k,
+		"o3-mini":                                    o200k,
+		"gpt-4-turbo":                                cl100k,
+		"gpt-3.5-turbo":                              cl100k,
+		"some-model":                                 Default,
+		"":                                           Default,
+		"omni-moderation-latest":                     Default,
+	} {
+		require.Equal(t, exp, ForModel(model), model)
+	}
+}
+
+func TestTokenizer_CountTokens(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		tokenizer *Tokenizer
+		text      string
+		exp       int
+	}{
+		{name: "empty", tokenizer: Default, text: "", exp: 0},
+		{name: "words", tokenizer: Default, text: "Hello, world!", exp: 4},
+		{name: "long word", tokenizer: claude, text: "internationalization", exp: 4},
+		{name: "long word with larger vocabulary", tokenizer: o200k, text: "internationalization", exp: 4},
+		{name: "digits merged", tokenizer: o200k, text: "1234567", exp: 3},
+		{name: "digits split", tokenizer: gemini, text: "1234567", exp: 7},
+		{name: "newlines", tokenizer: Default, text: "a\n\nb\nc", exp: 5},
+		{name: "punctuation", tokenizer: Default, text: `{"a":1}`, exp: 5},
+		{name: "cjk", tokenizer: cl100k, text: "你好世界", exp: 5},
+		{name: "cjk with larger vocabulary", tokenizer: gemini, text: "你好世界", exp: 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.exp, tc.tokenizer.CountTokens(tc.text))
+		})
+	}
+
+	t.Run("sentence", func(t *testing.T) {
+		// The exact count is 10 with both o200k and cl100k.
+		count := o200k.CountTokens("The quick brown fox jumps over the lazy dog.")
+		require.InDelta(t, 10, count, 2)
+	})
+}
@@ -0,0 +1,271 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package awsbedrock provides OpenInference semantic conventions hooks for
+// AWS Bedrock instrumentation used by the ExtProc router filter.
+package awsbedrock
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// ConverseRecorder implements recorders for OpenInference AWS Bedrock Converse spans.
+type ConverseRecorder struct {
+	traceConfig *openinference.TraceConfig
+}
+
+// NewConverseRecorderFromEnv creates an tracingapi.ConverseRecorder
+// from environment variables using the OpenInference configuration specification.
+//
+// See: https://github.com/Arize-ai/openinference/blob/main/spec/configuration.md
+func NewConverseRecorderFromEnv() tracingapi.ConverseRecorder {
+	return NewConverseRecorder(nil)
+}
+
+// NewConverseRecorder creates a tracingapi.ConverseRecorder with the
+// given config using the OpenInference configuration specification.
+//
+// Parameters:
+//   - config: configuration for redaction. Defaults to NewTraceConfigFromEnv().
+//
+// See: https://github.com/Arize-ai/openinference/blob/main/spec/configuration.md
+func NewConverseRecorder(config *openinference.TraceConfig) tracingapi.ConverseRecorder {
+	if config == nil {
+		config = openinference.NewTraceConfigFromEnv()
+	}
+	return &ConverseRecorder{traceConfig: config}
+}
+
+// startOpts sets trace.SpanKindInternal as that's the span kind used in
+// OpenInference.
+var startOpts = []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindInternal)}
+
+// StartParams implements the same method as defined in tracingapi.ConverseRecorder.
+func (r *ConverseRecorder) StartParams(*awsbedrock.ConverseInput, []byte) (spanName string, opts []trace.SpanStartOption) {
+	return "Converse", startOpts
+}
+
+// RecordRequest implements the same method as defined in tracingapi.ConverseRecorder.
+func (r *ConverseRecorder) RecordRequest(span trace.Span, req *awsbedrock.ConverseInput, body []byte) {
+	span.SetAttributes(buildRequestAttributes(req, string(body), r.traceConfig)...)
+}
+
+// RecordResponseChunks implements the same method as defined in tracingapi.ConverseRecorder.
+func (r *ConverseRecorder) RecordResponseChunks(span trace.Span, events []*awsbedrock.ConverseStreamEvent) {
+	if len(events) > 0 {
+		span.AddEvent("First Token Stream Event")
+	}
+	r.RecordResponse(span, mergeEvents(events))
+}
+
+// RecordResponseOnError implements the same method as defined in tracingapi.ConverseRecorder.
+func (r *ConverseRecorder) RecordResponseOnError(span trace.Span, statusCode int, body []byte) {
+	openinference.RecordResponseError(span, statusCode, string(body))
+}
+
+// RecordResponse implements the same method as defined in tracingapi.ConverseRecorder.
+func (r *ConverseRecorder) RecordResponse(span trace.Span, resp *awsbedrock.ConverseResponse) {
+	attrs := buildResponseAttributes(resp, r.traceConfig)
+
+	bodyString := openinference.RedactedValue
+	if !r.traceConfig.HideOutputs {
+		marshaled, err := json.Marshal(resp)
+		if err == nil {
+			bodyString = string(marshaled)
+		}
+	}
+	attrs = append(attrs, attribute.String(openinference.OutputValue, bodyString))
+	span.SetAttributes(attrs...)
+	span.SetStatus(codes.Ok, "")
+}
+
+// buildRequestAttributes builds OpenInference attributes from the request.
+func buildRequestAttributes(req *awsbedrock.ConverseInput, body string, config *openinference.TraceConfig) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String(openinference.SpanKind, openinference.SpanKindLLM),
+		attribute.String(openinference.LLMSystem, openinference.LLMSystemAWSBedrock),
+		attribute.String(openinference.LLMModelName, req.ModelID),
+	}
+
+	if config.HideInputs {
+		attrs = append(attrs, attribute.String(openinference.InputValue, openinference.RedactedValue))
+	} else {
+		attrs = append(attrs,
+			attribute.String(openinference.InputValue, body),
+			attribute.String(openinference.InputMimeType, openinference.MimeTypeJSON),
+		)
+	}
+
+	if !config.HideLLMInvocationParameters && req.InferenceConfig != nil {
+		if invocationParamsJSON, err := json.Marshal(req.InferenceConfig); err == nil {
+			attrs = append(attrs, attribute.String(openinference.LLMInvocationParameters, string(invocationParamsJSON)))
+		}
+	}
+
+	if !config.HideInputs && !config.HideInputMessages {
+		for i, msg := range req.Messages {
+			if msg == nil {
+				continue
+			}
+			attrs = append(attrs, attribute.String(openinference.InputMessageAttribute(i, openinference.MessageRole), msg.Role))
+			for j, block := range msg.Content {
+				if block == nil || block.Text == nil {
+					continue
+				}
+				text := *block.Text
+				if config.HideInputText {
+					text = openinference.RedactedValue
+				}
+				attrs = append(attrs,
+					attribute.String(openinference.InputMessageContentAttribute(i, j, "text"), text),
+					attribute.String(openinference.InputMessageContentAttribute(i, j, "type"), "text"),
+				)
+			}
+		}
+	}
+
+	if req.ToolConfig != nil {
+		for i, tool := range req.ToolConfig.Tools {
+			if tool == nil || tool.ToolSpec == nil {
+				continue
+			}
+			if toolJSON, err := json.Marshal(tool.ToolSpec); err == nil {
+				attrs = append(attrs, attribute.String(openinference.InputToolsAttribute(i), string(toolJSON)))
+			}
+		}
+	}
+	return attrs
+}
+
+// buildResponseAttributes builds OpenInference attributes from the response.
+func buildResponseAttributes(resp *awsbedrock.ConverseResponse, config *openinference.TraceConfig) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if !config.HideOutputs {
+		attrs = append(attrs, attribute.String(openinference.OutputMimeType, openinference.MimeTypeJSON))
+	}
+
+	if !config.HideOutputs && !config.HideOutputMessages && resp.Output != nil {
+		msg := &resp.Output.Message
+		attrs = append(attrs, attribute.String(openinference.OutputMessageAttribute(0, openinference.MessageRole), msg.Role))
+		var text strings.Builder
+		var toolCallIndex int
+		for _, block := range msg.Content {
+			switch {
+			case block == nil:
+			case block.ToolUse != nil:
+				input, _ := json.Marshal(block.ToolUse.Input)
+				attrs = append(attrs,
+					attribute.String(openinference.OutputMessageToolCallAttribute(0, toolCallIndex, openinference.ToolCallID), block.ToolUse.ToolUseID),
+					attribute.String(openinference.OutputMessageToolCallAttribute(0, toolCallIndex, openinference.ToolCallFunctionName), block.ToolUse.Name),
+					attribute.String(openinference.OutputMessageToolCallAttribute(0, toolCallIndex, openinference.ToolCallFunctionArguments), string(input)),
+				)
+				toolCallIndex++
+			case block.Text != nil:
+				text.WriteString(*block.Text)
+			}
+		}
+		if text.Len() > 0 {
+			content := text.String()
+			if config.HideOutputText {
+				content = openinference.RedactedValue
+			}
+			attrs = append(attrs, attribute.String(openinference.OutputMessageAttribute(0, openinference.MessageContent), content))
+		}
+	}
+
+	// Token counts are considered metadata and are still included even when output content is hidden.
+	// Bedrock excludes the cached tokens from the input tokens, so they are added back to the prompt count.
+	if u := resp.Usage; u != nil {
+		prompt := u.InputTokens
+		if u.CacheReadInputTokens != nil {
+			prompt += *u.CacheReadInputTokens
+		}
+		if u.CacheWriteInputTokens != nil {
+			prompt += *u.CacheWriteInputTokens
+		}
+		attrs = append(attrs,
+			attribute.Int(openinference.LLMTokenCountPrompt, int(prompt)),
+			attribute.Int(openinference.LLMTokenCountCompletion, int(u.OutputTokens)),
+			attribute.Int(openinference.LLMTokenCountTotal, int(u.TotalTokens)),
+		)
+		if u.CacheReadInputTokens != nil && *u.CacheReadInputTokens > 0 {
+			attrs = append(attrs, attribute.Int(openinference.LLMTokenCountPromptCacheHit, int(*u.CacheReadInputTokens)))
+		}
+		if u.CacheWriteInputTokens != nil && *u.CacheWriteInputTokens > 0 {
+			attrs = append(attrs, attribute.Int(openinference.LLMTokenCountPromptCacheWrite, int(*u.CacheWriteInputTokens)))
+		}
+	}
+	return attrs
+}
+
+// mergeEvents merges the streamed events into a single response. The text and the tool use input deltas are
+// concatenated per content block, while the stop reason comes from messageStop and the usage and metrics
+// from the final metadata event.
+func mergeEvents(events []*awsbedrock.ConverseStreamEvent) *awsbedrock.ConverseResponse {
+	resp := &awsbedrock.ConverseResponse{
+		Output: &awsbedrock.ConverseOutput{Message: awsbedrock.Message{Role: awsbedrock.ConversationRoleAssistant}},
+	}
+	type mergedBlock struct {
+		text, input strings.Builder
+		toolUse     *awsbedrock.ToolUseBlockStart
+	}
+	var blocks []*mergedBlock
+	block := func(index int) *mergedBlock {
+		for len(blocks) <= index {
+			blocks = append(blocks, &mergedBlock{})
+		}
+		return blocks[index]
+	}
+	for _, event := range events {
+		if event == nil {
+			continue
+		}
+		switch awsbedrock.ConverseStreamEventType(event.EventType) {
+		case awsbedrock.ConverseStreamEventTypeContentBlockStart:
+			if event.Start != nil && event.Start.ToolUse != nil {
+				block(event.ContentBlockIndex).toolUse = event.Start.ToolUse
+			}
+		case awsbedrock.ConverseStreamEventTypeContentBlockDelta:
+			if event.Delta == nil {
+				continue
+			}
+			b := block(event.ContentBlockIndex)
+			if event.Delta.Text != nil {
+				b.text.WriteString(*event.Delta.Text)
+			}
+			if event.Delta.ToolUse != nil {
+				b.input.WriteString(event.Delta.ToolUse.Input)
+			}
+		case awsbedrock.ConverseStreamEventTypeMessageStop:
+			resp.StopReason = event.StopReason
+		case awsbedrock.ConverseStreamEventTypeMetadata:
+			resp.Usage = event.Usage
+			resp.Metrics = event.Metrics
+		}
+	}
+	for _, b := range blocks {
+		switch {
+		case b.toolUse != nil:
+			input := map[string]any{}
+			_ = json.Unmarshal([]byte(b.input.String()), &input)
+			resp.Output.Message.Content = append(resp.Output.Message.Content, &awsbedrock.ContentBlock{ToolUse: &awsbedrock.ToolUseBlock{
+				Name: b.toolUse.Name, ToolUseID: b.toolUse.ToolUseID, Input: input,
+			}})
+		case b.text.Len() > 0:
+			text := b.text.String()
+			resp.Output.Message.Content = append(resp.Output.Message.Content, &awsbedrock.ContentBlock{Text: &text})
+		}
+	}
+	return resp
+}
@@ -0,0 +1,179 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package awsbedrock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/testing/testotel"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference"
+)
+
+var (
+	testReq = &awsbedrock.ConverseInput{
+		ModelID: "anthropic.claude-sonnet-4-5",
+		Messages: []*awsbedrock.Message{
+			{Role: awsbedrock.ConversationRoleUser, Content: []*awsbedrock.ContentBlock{{Text: ptr.To("Hello!")}}},
+		},
+	}
+	testReqBody = []byte(`{"messages":[{"role":"user","content":[{"text":"Hello!"}]}]}`)
+
+	testResp = &awsbedrock.ConverseResponse{
+		Output: &awsbedrock.ConverseOutput{Message: awsbedrock.Message{
+			Role: awsbedrock.ConversationRoleAssistant,
+			Content: []*awsbedrock.ContentBlock{
+				{Text: ptr.To("Hi there!")},
+				{ToolUse: &awsbedrock.ToolUseBlock{ToolUseID: "tooluse_1", Name: "get_weather", Input: map[string]any{"city": "Paris"}}},
+			},
+		}},
+		StopReason: ptr.To(awsbedrock.StopReasonToolUse),
+		Usage: &awsbedrock.TokenUsage{
+			InputTokens:          10,
+			OutputTokens:         5,
+			TotalTokens:          19,
+			CacheReadInputTokens: ptr.To[int64](4),
+		},
+	}
+)
+
+func TestConverseRecorder_StartParams(t *testing.T) {
+	recorder := NewConverseRecorderFromEnv()
+	spanName, opts := recorder.StartParams(testReq, testReqBody)
+	actualSpan := testotel.RecordNewSpan(t, spanName, opts...)
+
+	require.Equal(t, "Converse", actualSpan.Name)
+	require.Equal(t, oteltrace.SpanKindInternal, actualSpan.SpanKind)
+}
+
+func TestConverseRecorder_RecordRequest(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   *openinference.TraceConfig
+		expected []attribute.KeyValue
+	}{
+		{
+			name:   "default",
+			config: &openinference.TraceConfig{},
+			expected: []attribute.KeyValue{
+				attribute.String(openinference.SpanKind, openinference.SpanKindLLM),
+				attribute.String(openinference.LLMSystem, openinference.LLMSystemAWSBedrock),
+				attribute.String(openinference.LLMModelName, "anthropic.claude-sonnet-4-5"),
+				attribute.String(openinference.InputValue, string(testReqBody)),
+				attribute.String(openinference.InputMimeType, openinference.MimeTypeJSON),
+				attribute.String(openinference.InputMessageAttribute(0, openinference.MessageRole), awsbedrock.ConversationRoleUser),
+				attribute.String(openinference.InputMessageContentAttribute(0, 0, "text"), "Hello!"),
+				attribute.String(openinference.InputMessageContentAttribute(0, 0, "type"), "text"),
+			},
+		},
+		{
+			name:   "hide inputs",
+			config: &openinference.TraceConfig{HideInputs: true},
+			expected: []attribute.KeyValue{
+				attribute.String(openinference.SpanKind, openinference.SpanKindLLM),
+				attribute.String(openinference.LLMSystem, openinference.LLMSystemAWSBedrock),
+				attribute.String(openinference.LLMModelName, "anthropic.claude-sonnet-4-5"),
+				attribute.String(openinference.InputValue, openinference.RedactedValue),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := NewConverseRecorder(tc.config)
+			actualSpan := testotel.RecordWithSpan(t, func(span oteltrace.Span) bool {
+				recorder.RecordRequest(span, testReq, testReqBody)
+				return false
+			})
+			openinference.RequireAttributesEqual(t, tc.expected, actualSpan.Attributes)
+		})
+	}
+}
+
+func TestConverseRecorder_RecordResponse(t *testing.T) {
+	respBody, err := json.Marshal(testResp)
+	require.NoError(t, err)
+
+	recorder := NewConverseRecorder(&openinference.TraceConfig{})
+	actualSpan := testotel.RecordWithSpan(t, func(span oteltrace.Span) bool {
+		recorder.RecordResponse(span, testResp)
+		return false
+	})
+
+	expected := []attribute.KeyValue{
+		attribute.String(openinference.OutputMimeType, openinference.MimeTypeJSON),
+		attribute.String(openinference.OutputMessageAttribute(0, openinference.MessageRole), awsbedrock.ConversationRoleAssistant),
+		attribute.String(openinference.OutputMessageToolCallAttribute(0, 0, openinference.ToolCallID), "tooluse_1"),
+		attribute.String(openinference.OutputMessageToolCallAttribute(0, 0, openinference.ToolCallFunctionName), "get_weather"),
+		attribute.String(openinference.OutputMessageToolCallAttribute(0, 0, openinference.ToolCallFunctionArguments), `{"city":"Paris"}`),
+		attribute.String(openinference.OutputMessageAttribute(0, openinference.MessageContent), "Hi there!"),
+		attribute.Int(openinference.LLMTokenCountPrompt, 14),
+		attribute.Int(openinference.LLMTokenCountCompletion, 5),
+		attribute.Int(openinference.LLMTokenCountTotal, 19),
+		attribute.Int(openinference.LLMTokenCountPromptCacheHit, 4),
+		attribute.String(openinference.OutputValue, string(respBody)),
+	}
+	openinference.RequireAttributesEqual(t, expected, actualSpan.Attributes)
+	require.Equal(t, codes.Ok, actualSpan.Status.Code)
+}
+
+func TestConverseRecorder_RecordResponseChunks(t *testing.T) {
+	events := []*awsbedrock.ConverseStreamEvent{
+		{EventType: "messageStart", Role: ptr.To(awsbedrock.ConversationRoleAssistant)},
+		{EventType: "contentBlockDelta", Delta: &awsbedrock.ConverseStreamEventContentBlockDelta{Text: ptr.To("Hi ")}},
+		{EventType: "contentBlockDelta", Delta: &awsbedrock.ConverseStreamEventContentBlockDelta{Text: ptr.To("there!")}},
+		{EventType: "contentBlockStop"},
+		{EventType: "contentBlockStart", ContentBlockIndex: 1, Start: &awsbedrock.ContentBlockStart{
+			ToolUse: &awsbedrock.ToolUseBlockStart{ToolUseID: "tooluse_1", Name: "get_weather"},
+		}},
+		{EventType: "contentBlockDelta", ContentBlockIndex: 1, Delta: &awsbedrock.ConverseStreamEventContentBlockDelta{
+			ToolUse: &awsbedrock.ToolUseBlockDelta{Input: `{"city":`},
+		}},
+		{EventType: "contentBlockDelta", ContentBlockIndex: 1, Delta: &awsbedrock.ConverseStreamEventContentBlockDelta{
+			ToolUse: &awsbedrock.ToolUseBlockDelta{Input: `"Paris"}`},
+		}},
+		{EventType: "contentBlockStop", ContentBlockIndex: 1},
+		{EventType: "messageStop", StopReason: ptr.To(awsbedrock.StopReasonToolUse)},
+		{EventType: "metadata", Usage: &awsbedrock.TokenUsage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15}},
+	}
+
+	recorder := NewConverseRecorder(&openinference.TraceConfig{HideOutputs: true})
+	actualSpan := testotel.RecordWithSpan(t, func(span oteltrace.Span) bool {
+		recorder.RecordResponseChunks(span, events)
+		return false
+	})
+
+	expected := []attribute.KeyValue{
+		attribute.Int(openinference.LLMTokenCountPrompt, 10),
+		attribute.Int(openinference.LLMTokenCountCompletion, 5),
+		attribute.Int(openinference.LLMTokenCountTotal, 15),
+		attribute.String(openinference.OutputValue, openinference.RedactedValue),
+	}
+	openinference.RequireAttributesEqual(t, expected, actualSpan.Attributes)
+	require.Len(t, actualSpan.Events, 1)
+
+	merged := mergeEvents(events)
+	require.Equal(t, awsbedrock.StopReasonToolUse, *merged.StopReason)
+	require.Len(t, merged.Output.Message.Content, 2)
+	require.Equal(t, "Hi there!", *merged.Output.Message.Content[0].Text)
+	require.Equal(t, map[string]any{"city": "Paris"}, merged.Output.Message.Content[1].ToolUse.Input)
+}
+
+func TestConverseRecorder_RecordResponseOnError(t *testing.T) {
+	recorder := NewConverseRecorderFromEnv()
+	actualSpan := testotel.RecordWithSpan(t, func(span oteltrace.Span) bool {
+		recorder.RecordResponseOnError(span, 400, []byte(`{"message":"bad request"}`))
+		return false
+	})
+	require.Equal(t, codes.Error, actualSpan.Status.Code)
+	require.Len(t, actualSpan.Events, 1)
+	require.Equal(t, "exception", actualSpan.Events[0].Name)
+}
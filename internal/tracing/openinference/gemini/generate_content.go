@@ -0,0 +1,239 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+// Package gemini provides OpenInference semantic conventions hooks for
+// Gemini instrumentation used by the ExtProc router filter.
+package gemini
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genai"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// GenerateContentRecorder implements recorders for OpenInference Gemini generateContent spans.
+type GenerateContentRecorder struct {
+	traceConfig *openinference.TraceConfig
+}
+
+// NewGenerateContentRecorderFromEnv creates an tracingapi.GenerateContentRecorder
+// from environment variables using the OpenInference configuration specification.
+//
+// See: https://github.com/Arize-ai/openinference/blob/main/spec/configuration.md
+func NewGenerateContentRecorderFromEnv() tracingapi.GenerateContentRecorder {
+	return NewGenerateContentRecorder(nil)
+}
+
+// NewGenerateContentRecorder creates a tracingapi.GenerateContentRecorder with the
+// given config using the OpenInference configuration specification.
+//
+// Parameters:
+//   - config: configuration for redaction. Defaults to NewTraceConfigFromEnv().
+//
+// See: https://github.com/Arize-ai/openinference/blob/main/spec/configuration.md
+func NewGenerateContentRecorder(config *openinference.TraceConfig) tracingapi.GenerateContentRecorder {
+	if config == nil {
+		config = openinference.NewTraceConfigFromEnv()
+	}
+	return &GenerateContentRecorder{traceConfig: config}
+}
+
+// startOpts sets trace.SpanKindInternal as that's the span kind used in
+// OpenInference.
+var startOpts = []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindInternal)}
+
+// StartParams implements the same method as defined in tracingapi.GenerateContentRecorder.
+func (r *GenerateContentRecorder) StartParams(*gcp.GenerateContentRequest, []byte) (spanName string, opts []trace.SpanStartOption) {
+	return "GenerateContent", startOpts
+}
+
+// RecordRequest implements the same method as defined in tracingapi.GenerateContentRecorder.
+func (r *GenerateContentRecorder) RecordRequest(span trace.Span, req *gcp.GenerateContentRequest, body []byte) {
+	span.SetAttributes(buildRequestAttributes(req, string(body), r.traceConfig)...)
+}
+
+// RecordResponseChunks implements the same method as defined in tracingapi.GenerateContentRecorder.
+func (r *GenerateContentRecorder) RecordResponseChunks(span trace.Span, chunks []*genai.GenerateContentResponse) {
+	if len(chunks) > 0 {
+		span.AddEvent("First Token Stream Event")
+	}
+	r.RecordResponse(span, mergeChunks(chunks))
+}
+
+// RecordResponseOnError implements the same method as defined in tracingapi.GenerateContentRecorder.
+func (r *GenerateContentRecorder) RecordResponseOnError(span trace.Span, statusCode int, body []byte) {
+	openinference.RecordResponseError(span, statusCode, string(body))
+}
+
+// RecordResponse implements the same method as defined in tracingapi.GenerateContentRecorder.
+func (r *GenerateContentRecorder) RecordResponse(span trace.Span, resp *genai.GenerateContentResponse) {
+	attrs := buildResponseAttributes(resp, r.traceConfig)
+
+	bodyString := openinference.RedactedValue
+	if !r.traceConfig.HideOutputs {
+		marshaled, err := json.Marshal(resp)
+		if err == nil {
+			bodyString = string(marshaled)
+		}
+	}
+	attrs = append(attrs, attribute.String(openinference.OutputValue, bodyString))
+	span.SetAttributes(attrs...)
+	span.SetStatus(codes.Ok, "")
+}
+
+// buildRequestAttributes builds OpenInference attributes from the request.
+func buildRequestAttributes(req *gcp.GenerateContentRequest, body string, config *openinference.TraceConfig) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String(openinference.SpanKind, openinference.SpanKindLLM),
+		attribute.String(openinference.LLMSystem, openinference.LLMSystemVertexAI),
+		attribute.String(openinference.LLMModelName, req.Model),
+	}
+
+	if config.HideInputs {
+		attrs = append(attrs, attribute.String(openinference.InputValue, openinference.RedactedValue))
+	} else {
+		attrs = append(attrs,
+			attribute.String(openinference.InputValue, body),
+			attribute.String(openinference.InputMimeType, openinference.MimeTypeJSON),
+		)
+	}
+
+	if !config.HideLLMInvocationParameters && req.GenerationConfig != nil {
+		if invocationParamsJSON, err := json.Marshal(req.GenerationConfig); err == nil {
+			attrs = append(attrs, attribute.String(openinference.LLMInvocationParameters, string(invocationParamsJSON)))
+		}
+	}
+
+	if !config.HideInputs && !config.HideInputMessages {
+		for i, content := range req.Contents {
+			attrs = append(attrs, attribute.String(openinference.InputMessageAttribute(i, openinference.MessageRole), content.Role))
+			for j, part := range content.Parts {
+				if part == nil || part.Text == "" {
+					continue
+				}
+				text := part.Text
+				if config.HideInputText {
+					text = openinference.RedactedValue
+				}
+				attrs = append(attrs,
+					attribute.String(openinference.InputMessageContentAttribute(i, j, "text"), text),
+					attribute.String(openinference.InputMessageContentAttribute(i, j, "type"), "text"),
+				)
+			}
+		}
+	}
+
+	for i, tool := range req.Tools {
+		if toolJSON, err := json.Marshal(tool); err == nil {
+			attrs = append(attrs, attribute.String(openinference.InputToolsAttribute(i), string(toolJSON)))
+		}
+	}
+	return attrs
+}
+
+// buildResponseAttributes builds OpenInference attributes from the response.
+func buildResponseAttributes(resp *genai.GenerateContentResponse, config *openinference.TraceConfig) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if resp.ModelVersion != "" {
+		attrs = append(attrs, attribute.String(openinference.LLMModelName, resp.ModelVersion))
+	}
+
+	if !config.HideOutputs {
+		attrs = append(attrs, attribute.String(openinference.OutputMimeType, openinference.MimeTypeJSON))
+	}
+
+	if !config.HideOutputs && !config.HideOutputMessages {
+		for i, candidate := range resp.Candidates {
+			if candidate == nil || candidate.Content == nil {
+				continue
+			}
+			attrs = append(attrs, attribute.String(openinference.OutputMessageAttribute(i, openinference.MessageRole), candidate.Content.Role))
+			var text string
+			var toolCallIndex int
+			for _, part := range candidate.Content.Parts {
+				switch {
+				case part == nil:
+				case part.FunctionCall != nil:
+					args, _ := json.Marshal(part.FunctionCall.Args)
+					attrs = append(attrs,
+						attribute.String(openinference.OutputMessageToolCallAttribute(i, toolCallIndex, openinference.ToolCallID), part.FunctionCall.ID),
+						attribute.String(openinference.OutputMessageToolCallAttribute(i, toolCallIndex, openinference.ToolCallFunctionName), part.FunctionCall.Name),
+						attribute.String(openinference.OutputMessageToolCallAttribute(i, toolCallIndex, openinference.ToolCallFunctionArguments), string(args)),
+					)
+					toolCallIndex++
+				case !part.Thought:
+					text += part.Text
+				}
+			}
+			if text != "" {
+				if config.HideOutputText {
+					text = openinference.RedactedValue
+				}
+				attrs = append(attrs, attribute.String(openinference.OutputMessageAttribute(i, openinference.MessageContent), text))
+			}
+		}
+	}
+
+	// Token counts are considered metadata and are still included even when output content is hidden.
+	if u := resp.UsageMetadata; u != nil {
+		attrs = append(attrs,
+			attribute.Int(openinference.LLMTokenCountPrompt, int(u.PromptTokenCount)),
+			attribute.Int(openinference.LLMTokenCountCompletion, int(u.CandidatesTokenCount+u.ThoughtsTokenCount)),
+			attribute.Int(openinference.LLMTokenCountTotal, int(u.TotalTokenCount)),
+		)
+		if u.CachedContentTokenCount > 0 {
+			attrs = append(attrs, attribute.Int(openinference.LLMTokenCountPromptCacheHit, int(u.CachedContentTokenCount)))
+		}
+		if u.ThoughtsTokenCount > 0 {
+			attrs = append(attrs, attribute.Int(openinference.LLMTokenCountCompletionReasoning, int(u.ThoughtsTokenCount)))
+		}
+	}
+	return attrs
+}
+
+// mergeChunks merges the streamed chunks into a single response. The parts of the candidates are concatenated,
+// and the last non-empty usage, model version and finish reason win, since each chunk carries the cumulative usage.
+func mergeChunks(chunks []*genai.GenerateContentResponse) *genai.GenerateContentResponse {
+	resp := &genai.GenerateContentResponse{}
+	candidates := map[int32]*genai.Candidate{}
+	for _, chunk := range chunks {
+		if chunk == nil {
+			continue
+		}
+		if chunk.ResponseID != "" {
+			resp.ResponseID = chunk.ResponseID
+		}
+		if chunk.ModelVersion != "" {
+			resp.ModelVersion = chunk.ModelVersion
+		}
+		if chunk.UsageMetadata != nil {
+			resp.UsageMetadata = chunk.UsageMetadata
+		}
+		for _, c := range chunk.Candidates {
+			if c == nil {
+				continue
+			}
+			merged, ok := candidates[c.Index]
+			if !ok {
+				merged = &genai.Candidate{Index: c.Index, Content: &genai.Content{Role: genai.RoleModel}}
+				candidates[c.Index] = merged
+				resp.Candidates = append(resp.Candidates, merged)
+			}
+			if c.FinishReason != "" {
+				merged.FinishReason = c.FinishReason
+			}
+			if c.Content != nil {
+				merged.Content.Parts = append(merged.Content.Parts, c.Content.Parts...)
+			}
+		}
+	}
+	return resp
+}
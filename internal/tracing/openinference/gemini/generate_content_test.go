@@ -0,0 +1,169 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package gemini
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/genai"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/testing/testotel"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference"
+)
+
+var (
+	testReq = &gcp.GenerateContentRequest{
+		Model: "gemini-2.5-flash",
+		Contents: []genai.Content{
+			{Role: genai.RoleUser, Parts: []*genai.Part{{Text: "Hello!"}}},
+		},
+	}
+	testReqBody = []byte(`{"contents":[{"role":"user","parts":[{"text":"Hello!"}]}]}`)
+
+	testResp = &genai.GenerateContentResponse{
+		ModelVersion: "gemini-2.5-flash-001",
+		Candidates: []*genai.Candidate{{
+			Content:      &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{Text: "Hi there!"}}},
+			FinishReason: genai.FinishReasonStop,
+		}},
+		UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+			PromptTokenCount:        10,
+			CandidatesTokenCount:    5,
+			ThoughtsTokenCount:      2,
+			CachedContentTokenCount: 4,
+			TotalTokenCount:         17,
+		},
+	}
+)
+
+func TestGenerateContentRecorder_StartParams(t *testing.T) {
+	recorder := NewGenerateContentRecorderFromEnv()
+	spanName, opts := recorder.StartParams(testReq, testReqBody)
+	actualSpan := testotel.RecordNewSpan(t, spanName, opts...)
+
+	require.Equal(t, "GenerateContent", actualSpan.Name)
+	require.Equal(t, oteltrace.SpanKindInternal, actualSpan.SpanKind)
+}
+
+func TestGenerateContentRecorder_RecordRequest(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   *openinference.TraceConfig
+		expected []attribute.KeyValue
+	}{
+		{
+			name:   "default",
+			config: &openinference.TraceConfig{},
+			expected: []attribute.KeyValue{
+				attribute.String(openinference.SpanKind, openinference.SpanKindLLM),
+				attribute.String(openinference.LLMSystem, openinference.LLMSystemVertexAI),
+				attribute.String(openinference.LLMModelName, "gemini-2.5-flash"),
+				attribute.String(openinference.InputValue, string(testReqBody)),
+				attribute.String(openinference.InputMimeType, openinference.MimeTypeJSON),
+				attribute.String(openinference.InputMessageAttribute(0, openinference.MessageRole), genai.RoleUser),
+				attribute.String(openinference.InputMessageContentAttribute(0, 0, "text"), "Hello!"),
+				attribute.String(openinference.InputMessageContentAttribute(0, 0, "type"), "text"),
+			},
+		},
+		{
+			name:   "hide inputs",
+			config: &openinference.TraceConfig{HideInputs: true},
+			expected: []attribute.KeyValue{
+				attribute.String(openinference.SpanKind, openinference.SpanKindLLM),
+				attribute.String(openinference.LLMSystem, openinference.LLMSystemVertexAI),
+				attribute.String(openinference.LLMModelName, "gemini-2.5-flash"),
+				attribute.String(openinference.InputValue, openinference.RedactedValue),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := NewGenerateContentRecorder(tc.config)
+			actualSpan := testotel.RecordWithSpan(t, func(span oteltrace.Span) bool {
+				recorder.RecordRequest(span, testReq, testReqBody)
+				return false
+			})
+			openinference.RequireAttributesEqual(t, tc.expected, actualSpan.Attributes)
+		})
+	}
+}
+
+func TestGenerateContentRecorder_RecordResponse(t *testing.T) {
+	respBody, err := json.Marshal(testResp)
+	require.NoError(t, err)
+
+	recorder := NewGenerateContentRecorder(&openinference.TraceConfig{})
+	actualSpan := testotel.RecordWithSpan(t, func(span oteltrace.Span) bool {
+		recorder.RecordResponse(span, testResp)
+		return false
+	})
+
+	expected := []attribute.KeyValue{
+		attribute.String(openinference.LLMModelName, "gemini-2.5-flash-001"),
+		attribute.String(openinference.OutputMimeType, openinference.MimeTypeJSON),
+		attribute.String(openinference.OutputMessageAttribute(0, openinference.MessageRole), genai.RoleModel),
+		attribute.String(openinference.OutputMessageAttribute(0, openinference.MessageContent), "Hi there!"),
+		attribute.Int(openinference.LLMTokenCountPrompt, 10),
+		attribute.Int(openinference.LLMTokenCountCompletion, 7),
+		attribute.Int(openinference.LLMTokenCountTotal, 17),
+		attribute.Int(openinference.LLMTokenCountPromptCacheHit, 4),
+		attribute.Int(openinference.LLMTokenCountCompletionReasoning, 2),
+		attribute.String(openinference.OutputValue, string(respBody)),
+	}
+	openinference.RequireAttributesEqual(t, expected, actualSpan.Attributes)
+	require.Equal(t, codes.Ok, actualSpan.Status.Code)
+}
+
+func TestGenerateContentRecorder_RecordResponseChunks(t *testing.T) {
+	chunks := []*genai.GenerateContentResponse{
+		{
+			ModelVersion: "gemini-2.5-flash-001",
+			Candidates:   []*genai.Candidate{{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{Text: "Hi "}}}}},
+		},
+		{
+			ModelVersion:  "gemini-2.5-flash-001",
+			Candidates:    []*genai.Candidate{{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{Text: "there!"}}}, FinishReason: genai.FinishReasonStop}},
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 10, CandidatesTokenCount: 5, TotalTokenCount: 15},
+		},
+	}
+
+	recorder := NewGenerateContentRecorder(&openinference.TraceConfig{HideOutputs: true})
+	actualSpan := testotel.RecordWithSpan(t, func(span oteltrace.Span) bool {
+		recorder.RecordResponseChunks(span, chunks)
+		return false
+	})
+
+	expected := []attribute.KeyValue{
+		attribute.String(openinference.LLMModelName, "gemini-2.5-flash-001"),
+		attribute.Int(openinference.LLMTokenCountPrompt, 10),
+		attribute.Int(openinference.LLMTokenCountCompletion, 5),
+		attribute.Int(openinference.LLMTokenCountTotal, 15),
+		attribute.String(openinference.OutputValue, openinference.RedactedValue),
+	}
+	openinference.RequireAttributesEqual(t, expected, actualSpan.Attributes)
+	require.Len(t, actualSpan.Events, 1)
+
+	merged := mergeChunks(chunks)
+	require.Len(t, merged.Candidates, 1)
+	require.Equal(t, genai.FinishReasonStop, merged.Candidates[0].FinishReason)
+	require.Len(t, merged.Candidates[0].Content.Parts, 2)
+}
+
+func TestGenerateContentRecorder_RecordResponseOnError(t *testing.T) {
+	recorder := NewGenerateContentRecorderFromEnv()
+	actualSpan := testotel.RecordWithSpan(t, func(span oteltrace.Span) bool {
+		recorder.RecordResponseOnError(span, 400, []byte(`{"error":{"code":400,"message":"bad request","status":"INVALID_ARGUMENT"}}`))
+		return false
+	})
+	require.Equal(t, codes.Error, actualSpan.Status.Code)
+	require.Len(t, actualSpan.Events, 1)
+	require.Equal(t, "exception", actualSpan.Events[0].Name)
+}
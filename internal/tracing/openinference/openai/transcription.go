@@ -0,0 +1,120 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package openai
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// TranscriptionRecorder implements recorders for OpenInference speech-to-text spans.
+type TranscriptionRecorder struct {
+	// Embedding NoopChunkRecorder since the streamed text deltas are not recorded individually.
+	tracingapi.NoopChunkRecorder[openai.TranscriptionStreamEvent]
+	traceConfig *openinference.TraceConfig
+}
+
+// NewTranscriptionRecorderFromEnv creates a tracingapi.TranscriptionRecorder
+// from environment variables using the OpenInference configuration specification.
+func NewTranscriptionRecorderFromEnv() tracingapi.TranscriptionRecorder {
+	return NewTranscriptionRecorder(nil)
+}
+
+// NewTranscriptionRecorder creates a tracingapi.TranscriptionRecorder with the
+// given config using the OpenInference configuration specification.
+//
+// Parameters:
+//   - config: configuration for redaction. Defaults to NewTraceConfigFromEnv().
+func NewTranscriptionRecorder(config *openinference.TraceConfig) tracingapi.TranscriptionRecorder {
+	if config == nil {
+		config = openinference.NewTraceConfigFromEnv()
+	}
+	return &TranscriptionRecorder{traceConfig: config}
+}
+
+// transcriptionStartOpts sets trace.SpanKindInternal as that's the span kind used in
+// OpenInference.
+var transcriptionStartOpts = []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindInternal)}
+
+// StartParams implements the same method as defined in tracingapi.TranscriptionRecorder.
+func (r *TranscriptionRecorder) StartParams(*openai.TranscriptionRequest, []byte) (spanName string, opts []trace.SpanStartOption) {
+	return "AudioTranscription", transcriptionStartOpts
+}
+
+// RecordRequest implements the same method as defined in tracingapi.TranscriptionRecorder.
+//
+// The raw body is not recorded since it is the multipart form including the audio file.
+func (r *TranscriptionRecorder) RecordRequest(span trace.Span, req *openai.TranscriptionRequest, _ []byte) {
+	span.SetAttributes(buildTranscriptionRequestAttributes(req, r.traceConfig)...)
+}
+
+// RecordResponse implements the same method as defined in tracingapi.TranscriptionRecorder.
+func (r *TranscriptionRecorder) RecordResponse(span trace.Span, resp *openai.TranscriptionResponse) {
+	var attrs []attribute.KeyValue
+	if resp != nil {
+		if !r.traceConfig.HideOutputs {
+			attrs = append(attrs, attribute.String(openinference.OutputValue, resp.Text))
+		}
+		if resp.Usage != nil && resp.Usage.Type == openai.TranscriptionUsageTypeTokens {
+			attrs = append(attrs,
+				attribute.Int(openinference.LLMTokenCountPrompt, resp.Usage.InputTokens),
+				attribute.Int(openinference.LLMTokenCountCompletion, resp.Usage.OutputTokens),
+				attribute.Int(openinference.LLMTokenCountTotal, resp.Usage.TotalTokens),
+			)
+		}
+	}
+	span.SetAttributes(attrs...)
+	span.SetStatus(codes.Ok, "")
+}
+
+// RecordResponseOnError implements the same method as defined in tracingapi.TranscriptionRecorder.
+func (r *TranscriptionRecorder) RecordResponseOnError(span trace.Span, statusCode int, body []byte) {
+	openinference.RecordResponseError(span, statusCode, string(body))
+}
+
+// buildTranscriptionRequestAttributes builds OpenInference attributes from the transcription request.
+func buildTranscriptionRequestAttributes(req *openai.TranscriptionRequest, config *openinference.TraceConfig) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String(openinference.SpanKind, openinference.SpanKindLLM),
+		attribute.String(openinference.LLMSystem, openinference.LLMSystemOpenAI),
+	}
+
+	if req.Model != "" {
+		attrs = append(attrs, attribute.String(openinference.LLMModelName, req.Model))
+	}
+
+	if config.HideInputs {
+		attrs = append(attrs, attribute.String(openinference.InputValue, openinference.RedactedValue))
+	} else {
+		// The audio itself is not recorded, only the file metadata and the prompt.
+		inputJSON, err := json.Marshal(map[string]any{
+			"file_name": req.FileName,
+			"file_size": req.FileSize,
+			"prompt":    req.Prompt,
+		})
+		if err == nil {
+			attrs = append(attrs,
+				attribute.String(openinference.InputValue, string(inputJSON)),
+				attribute.String(openinference.InputMimeType, openinference.MimeTypeJSON),
+			)
+		}
+	}
+
+	if !config.HideLLMInvocationParameters {
+		params, err := json.Marshal(req)
+		if err == nil {
+			attrs = append(attrs, attribute.String(openinference.LLMInvocationParameters, string(params)))
+		}
+	}
+
+	return attrs
+}
@@ -0,0 +1,154 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package openai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/testing/testotel"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference"
+)
+
+var basicTranscriptionReq = &openai.TranscriptionRequest{
+	Model:    "whisper-1",
+	FileName: "speech.mp3",
+	FileSize: 1024,
+	Language: ptr("en"),
+	Prompt:   ptr("Meeting notes"),
+}
+
+func TestTranscriptionRecorder_StartParams(t *testing.T) {
+	recorder := NewTranscriptionRecorderFromEnv()
+
+	spanName, opts := recorder.StartParams(basicTranscriptionReq, nil)
+	actualSpan := testotel.RecordNewSpan(t, spanName, opts...)
+
+	require.Equal(t, "AudioTranscription", actualSpan.Name)
+	require.Equal(t, oteltrace.SpanKindInternal, actualSpan.SpanKind)
+}
+
+func TestTranscriptionRecorder_RecordRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        *openinference.TraceConfig
+		expectedAttrs []attribute.KeyValue
+	}{
+		{
+			name:   "basic request",
+			config: &openinference.TraceConfig{},
+			expectedAttrs: []attribute.KeyValue{
+				attribute.String(openinference.SpanKind, openinference.SpanKindLLM),
+				attribute.String(openinference.LLMSystem, openinference.LLMSystemOpenAI),
+				attribute.String(openinference.LLMModelName, "whisper-1"),
+				attribute.String(openinference.InputValue, `{"file_name":"speech.mp3","file_size":1024,"prompt":"Meeting notes"}`),
+				attribute.String(openinference.InputMimeType, openinference.MimeTypeJSON),
+				attribute.String(openinference.LLMInvocationParameters, `{"model":"whisper-1","file_name":"speech.mp3","file_size":1024,"language":"en","prompt":"Meeting notes"}`),
+			},
+		},
+		{
+			name:   "hidden inputs and invocation parameters",
+			config: &openinference.TraceConfig{HideInputs: true, HideLLMInvocationParameters: true},
+			expectedAttrs: []attribute.KeyValue{
+				attribute.String(openinference.SpanKind, openinference.SpanKindLLM),
+				attribute.String(openinference.LLMSystem, openinference.LLMSystemOpenAI),
+				attribute.String(openinference.LLMModelName, "whisper-1"),
+				attribute.String(openinference.InputValue, openinference.RedactedValue),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := NewTranscriptionRecorder(tt.config)
+
+			// The multipart body must never be recorded since it contains the audio.
+			actualSpan := testotel.RecordWithSpan(t, func(span oteltrace.Span) bool {
+				recorder.RecordRequest(span, basicTranscriptionReq, []byte("--boundary\r\naudio"))
+				return false
+			})
+
+			openinference.RequireAttributesEqual(t, tt.expectedAttrs, actualSpan.Attributes)
+		})
+	}
+}
+
+func TestTranscriptionRecorder_RecordResponse(t *testing.T) {
+	tests := []struct {
+		name          string
+		resp          *openai.TranscriptionResponse
+		config        *openinference.TraceConfig
+		expectedAttrs []attribute.KeyValue
+	}{
+		{
+			name:   "duration usage",
+			resp:   &openai.TranscriptionResponse{Text: "hello", Usage: &openai.TranscriptionUsage{Type: "duration", Seconds: 3}},
+			config: &openinference.TraceConfig{},
+			expectedAttrs: []attribute.KeyValue{
+				attribute.String(openinference.OutputValue, "hello"),
+			},
+		},
+		{
+			name: "tokens usage",
+			resp: &openai.TranscriptionResponse{Text: "hello", Usage: &openai.TranscriptionUsage{
+				Type: "tokens", InputTokens: 14, OutputTokens: 2, TotalTokens: 16,
+			}},
+			config: &openinference.TraceConfig{},
+			expectedAttrs: []attribute.KeyValue{
+				attribute.String(openinference.OutputValue, "hello"),
+				attribute.Int(openinference.LLMTokenCountPrompt, 14),
+				attribute.Int(openinference.LLMTokenCountCompletion, 2),
+				attribute.Int(openinference.LLMTokenCountTotal, 16),
+			},
+		},
+		{
+			name:          "hidden outputs",
+			resp:          &openai.TranscriptionResponse{Text: "hello"},
+			config:        &openinference.TraceConfig{HideOutputs: true},
+			expectedAttrs: []attribute.KeyValue{},
+		},
+		{
+			name:          "nil response",
+			config:        &openinference.TraceConfig{},
+			expectedAttrs: []attribute.KeyValue{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := NewTranscriptionRecorder(tt.config)
+
+			actualSpan := testotel.RecordWithSpan(t, func(span oteltrace.Span) bool {
+				recorder.RecordResponse(span, tt.resp)
+				return false
+			})
+
+			openinference.RequireAttributesEqual(t, tt.expectedAttrs, actualSpan.Attributes)
+			require.Equal(t, trace.Status{Code: codes.Ok, Description: ""}, actualSpan.Status)
+		})
+	}
+}
+
+func TestTranscriptionRecorder_RecordResponseOnError(t *testing.T) {
+	recorder := NewTranscriptionRecorderFromEnv()
+
+	actualSpan := testotel.RecordWithSpan(t, func(span oteltrace.Span) bool {
+		recorder.RecordResponseOnError(span, 400, []byte(`{"error":{"message":"Invalid file format."}}`))
+		return false
+	})
+
+	require.Equal(t, trace.Status{
+		Code:        codes.Error,
+		Description: "Error code: 400 - {\"error\":{\"message\":\"Invalid file format.\"}}",
+	}, actualSpan.Status)
+	require.Len(t, actualSpan.Events, 1)
+}
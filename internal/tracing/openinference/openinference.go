@@ -48,6 +48,10 @@ const (
 	LLMSystemCohere = "cohere"
 	// LLMSystemAnthropic for Anthropic systems.
 	LLMSystemAnthropic = "anthropic"
+	// LLMSystemVertexAI for Google Vertex AI and Gemini systems.
+	LLMSystemVertexAI = "vertexai"
+	// LLMSystemAWSBedrock for AWS Bedrock systems.
+	LLMSystemAWSBedrock = "aws_bedrock"
 )
 
 // Input/Output constants.
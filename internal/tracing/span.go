@@ -7,8 +7,10 @@ package tracing
 
 import (
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genai"
 
 	anthropicschema "github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
 	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
@@ -52,6 +54,9 @@ type (
 	imageGenerationSpan = span[openai.ImageGenerationResponse, struct{}]
 	responsesSpan       = span[openai.Response, openai.ResponseStreamEventUnion]
 	speechSpan          = span[[]byte, openai.SpeechStreamChunk]
+	transcriptionSpan   = span[openai.TranscriptionResponse, openai.TranscriptionStreamEvent]
 	rerankSpan          = span[cohereschema.RerankV2Response, struct{}]
+	generateContentSpan = span[genai.GenerateContentResponse, genai.GenerateContentResponse]
+	converseSpan        = span[awsbedrock.ConverseResponse, awsbedrock.ConverseStreamEvent]
 	messageSpan         = span[anthropicschema.MessagesResponse, anthropicschema.MessagesStreamChunk]
 )
@@ -12,8 +12,11 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/genai"
 
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
 	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
 )
@@ -37,7 +40,10 @@ var (
 	_ tracingapi.ImageGenerationTracer = (*imageGenerationTracer)(nil)
 	_ tracingapi.ResponsesTracer       = (*responsesTracer)(nil)
 	_ tracingapi.SpeechTracer          = (*speechTracer)(nil)
+	_ tracingapi.TranscriptionTracer   = (*transcriptionTracer)(nil)
 	_ tracingapi.RerankTracer          = (*rerankTracer)(nil)
+	_ tracingapi.GenerateContentTracer = (*generateContentTracer)(nil)
+	_ tracingapi.ConverseTracer        = (*converseTracer)(nil)
 )
 
 type (
@@ -47,7 +53,10 @@ type (
 	imageGenerationTracer = requestTracerImpl[openai.ImageGenerationRequest, openai.ImageGenerationResponse, struct{}]
 	responsesTracer       = requestTracerImpl[openai.ResponseRequest, openai.Response, openai.ResponseStreamEventUnion]
 	speechTracer          = requestTracerImpl[openai.SpeechRequest, []byte, openai.SpeechStreamChunk]
+	transcriptionTracer   = requestTracerImpl[openai.TranscriptionRequest, openai.TranscriptionResponse, openai.TranscriptionStreamEvent]
 	rerankTracer          = requestTracerImpl[cohereschema.RerankV2Request, cohereschema.RerankV2Response, struct{}]
+	generateContentTracer = requestTracerImpl[gcp.GenerateContentRequest, genai.GenerateContentResponse, genai.GenerateContentResponse]
+	converseTracer        = requestTracerImpl[awsbedrock.ConverseInput, awsbedrock.ConverseResponse, awsbedrock.ConverseStreamEvent]
 )
 
 func newRequestTracer[ReqT any, RespT any, RespChunkT any](
@@ -176,6 +185,18 @@ func newSpeechTracer(tracer trace.Tracer, propagator propagation.TextMapPropagat
 	)
 }
 
+func newTranscriptionTracer(tracer trace.Tracer, propagator propagation.TextMapPropagator, recorder tracingapi.TranscriptionRecorder, headerAttributes map[string]string) tracingapi.TranscriptionTracer {
+	return newRequestTracer(
+		tracer,
+		propagator,
+		recorder,
+		headerAttributes,
+		func(span trace.Span, recorder tracingapi.TranscriptionRecorder) tracingapi.TranscriptionSpan {
+			return &transcriptionSpan{span: span, recorder: recorder}
+		},
+	)
+}
+
 func newRerankTracer(tracer trace.Tracer, propagator propagation.TextMapPropagator, recorder tracingapi.RerankRecorder, headerAttributes map[string]string) tracingapi.RerankTracer {
 	return newRequestTracer(
 		tracer,
@@ -188,6 +209,30 @@ func newRerankTracer(tracer trace.Tracer, propagator propagation.TextMapPropagat
 	)
 }
 
+func newGenerateContentTracer(tracer trace.Tracer, propagator propagation.TextMapPropagator, recorder tracingapi.GenerateContentRecorder, headerAttributes map[string]string) tracingapi.GenerateContentTracer {
+	return newRequestTracer(
+		tracer,
+		propagator,
+		recorder,
+		headerAttributes,
+		func(span trace.Span, recorder tracingapi.GenerateContentRecorder) tracingapi.GenerateContentSpan {
+			return &generateContentSpan{span: span, recorder: recorder}
+		},
+	)
+}
+
+func newConverseTracer(tracer trace.Tracer, propagator propagation.TextMapPropagator, recorder tracingapi.ConverseRecorder, headerAttributes map[string]string) tracingapi.ConverseTracer {
+	return newRequestTracer(
+		tracer,
+		propagator,
+		recorder,
+		headerAttributes,
+		func(span trace.Span, recorder tracingapi.ConverseRecorder) tracingapi.ConverseSpan {
+			return &converseSpan{span: span, recorder: recorder}
+		},
+	)
+}
+
 func newMessageTracer(tracer trace.Tracer, propagator propagation.TextMapPropagator, recorder tracingapi.MessageRecorder, headerAttributes map[string]string) tracingapi.MessageTracer {
 	return newRequestTracer(
 		tracer,
@@ -20,7 +20,9 @@ import (
 
 	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference/awsbedrock"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference/gemini"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/openinference/openai"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
 )
@@ -34,7 +36,10 @@ type tracingImpl struct {
 	embeddingsTracer      tracingapi.EmbeddingsTracer
 	responsesTracer       tracingapi.ResponsesTracer
 	speechTracer          tracingapi.SpeechTracer
+	transcriptionTracer   tracingapi.TranscriptionTracer
 	rerankTracer          tracingapi.RerankTracer
+	generateContentTracer tracingapi.GenerateContentTracer
+	converseTracer        tracingapi.ConverseTracer
 	messageTracer         tracingapi.MessageTracer
 	mcpTracer             tracingapi.MCPTracer
 	// shutdown is nil when we didn't create tp.
@@ -71,11 +76,26 @@ func (t *tracingImpl) SpeechTracer() tracingapi.SpeechTracer {
 	return t.speechTracer
 }
 
+// TranscriptionTracer implements the same method as documented on tracingapi.Tracing.
+func (t *tracingImpl) TranscriptionTracer() tracingapi.TranscriptionTracer {
+	return t.transcriptionTracer
+}
+
 // RerankTracer implements the same method as documented on tracingapi.Tracing.
 func (t *tracingImpl) RerankTracer() tracingapi.RerankTracer {
 	return t.rerankTracer
 }
 
+// GenerateContentTracer implements the same method as documented on tracingapi.Tracing.
+func (t *tracingImpl) GenerateContentTracer() tracingapi.GenerateContentTracer {
+	return t.generateContentTracer
+}
+
+// ConverseTracer implements the same method as documented on tracingapi.Tracing.
+func (t *tracingImpl) ConverseTracer() tracingapi.ConverseTracer {
+	return t.converseTracer
+}
+
 // MCPTracer implements the same method as documented on tracingapi.Tracing.
 func (t *tracingImpl) MCPTracer() tracingapi.MCPTracer {
 	return t.mcpTracer
@@ -205,7 +225,10 @@ func NewTracingFromEnv(ctx context.Context, stdout io.Writer, headerAttributeMap
 	embeddingsRecorder := openai.NewEmbeddingsRecorderFromEnv()
 	responsesRecorder := openai.NewResponsesRecorderFromEnv()
 	speechRecorder := openai.NewSpeechRecorderFromEnv()
+	transcriptionRecorder := openai.NewTranscriptionRecorderFromEnv()
 	rerankRecorder := cohere.NewRerankRecorderFromEnv()
+	generateContentRecorder := gemini.NewGenerateContentRecorderFromEnv()
+	converseRecorder := awsbedrock.NewConverseRecorderFromEnv()
 	messageRecorder := anthropic.NewMessageRecorderFromEnv()
 
 	tracer := tp.Tracer("envoyproxy/ai-gateway")
@@ -245,12 +268,30 @@ func NewTracingFromEnv(ctx context.Context, stdout io.Writer, headerAttributeMap
 			speechRecorder,
 			headerAttrs,
 		),
+		transcriptionTracer: newTranscriptionTracer(
+			tracer,
+			propagator,
+			transcriptionRecorder,
+			headerAttrs,
+		),
 		rerankTracer: newRerankTracer(
 			tracer,
 			propagator,
 			rerankRecorder,
 			headerAttrs,
 		),
+		generateContentTracer: newGenerateContentTracer(
+			tracer,
+			propagator,
+			generateContentRecorder,
+			headerAttrs,
+		),
+		converseTracer: newConverseTracer(
+			tracer,
+			propagator,
+			converseRecorder,
+			headerAttrs,
+		),
 		messageTracer: newMessageTracer(
 			tracer,
 			propagator,
@@ -12,9 +12,12 @@ import (
 
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genai"
 
 	anthropicschema "github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
 )
 
@@ -34,8 +37,15 @@ type (
 		ResponsesTracer() ResponsesTracer
 		// SpeechTracer creates spans for OpenAI speech synthesis requests on /v1/audio/speech endpoint.
 		SpeechTracer() SpeechTracer
+		// TranscriptionTracer creates spans for OpenAI speech-to-text requests on /v1/audio/transcriptions and
+		// /v1/audio/translations endpoints.
+		TranscriptionTracer() TranscriptionTracer
 		// RerankTracer creates spans for rerank requests.
 		RerankTracer() RerankTracer
+		// GenerateContentTracer creates spans for Gemini generateContent and streamGenerateContent requests.
+		GenerateContentTracer() GenerateContentTracer
+		// ConverseTracer creates spans for AWS Bedrock Converse and ConverseStream requests.
+		ConverseTracer() ConverseTracer
 		// MessageTracer creates spans for Anthropic messages requests.
 		MessageTracer() MessageTracer
 		// MCPTracer creates spans for MCP requests.
@@ -69,8 +79,14 @@ type (
 	ResponsesTracer = RequestTracer[openai.ResponseRequest, openai.Response, openai.ResponseStreamEventUnion]
 	// SpeechTracer creates spans for OpenAI speech synthesis requests.
 	SpeechTracer = RequestTracer[openai.SpeechRequest, []byte, openai.SpeechStreamChunk]
+	// TranscriptionTracer creates spans for OpenAI speech-to-text requests.
+	TranscriptionTracer = RequestTracer[openai.TranscriptionRequest, openai.TranscriptionResponse, openai.TranscriptionStreamEvent]
 	// RerankTracer creates spans for rerank requests.
 	RerankTracer = RequestTracer[cohere.RerankV2Request, cohere.RerankV2Response, struct{}]
+	// GenerateContentTracer creates spans for Gemini generateContent requests.
+	GenerateContentTracer = RequestTracer[gcp.GenerateContentRequest, genai.GenerateContentResponse, genai.GenerateContentResponse]
+	// ConverseTracer creates spans for AWS Bedrock Converse requests.
+	ConverseTracer = RequestTracer[awsbedrock.ConverseInput, awsbedrock.ConverseResponse, awsbedrock.ConverseStreamEvent]
 	// MessageTracer creates spans for Anthropic messages requests.
 	MessageTracer = RequestTracer[anthropicschema.MessagesRequest, anthropicschema.MessagesResponse, anthropicschema.MessagesStreamChunk]
 )
@@ -100,10 +116,24 @@ type (
 	ResponsesSpan = Span[openai.Response, openai.ResponseStreamEventUnion]
 	// SpeechSpan represents an OpenAI speech synthesis request span.
 	SpeechSpan = Span[[]byte, openai.SpeechStreamChunk]
+	// TranscriptionSpan represents an OpenAI speech-to-text request span.
+	TranscriptionSpan = Span[openai.TranscriptionResponse, openai.TranscriptionStreamEvent]
 	// RerankSpan represents a rerank request span.
 	RerankSpan = Span[cohere.RerankV2Response, struct{}]
+	// GenerateContentSpan represents a Gemini generateContent request span.
+	GenerateContentSpan = Span[genai.GenerateContentResponse, genai.GenerateContentResponse]
+	// ConverseSpan represents an AWS Bedrock Converse request span.
+	ConverseSpan = Span[awsbedrock.ConverseResponse, awsbedrock.ConverseStreamEvent]
 	// MessageSpan represents an Anthropic messages request span.
 	MessageSpan = Span[anthropicschema.MessagesResponse, anthropicschema.MessagesStreamChunk]
+	// FilesSpan represents an OpenAI files request span. The chunk type is unused and therefore set to struct{}.
+	FilesSpan = Span[openai.FileObject, struct{}]
+	// BatchesSpan represents an OpenAI batches request span. The chunk type is unused and therefore set to struct{}.
+	BatchesSpan = Span[openai.Batch, struct{}]
+	// ModerationsSpan represents an OpenAI moderations request span. The chunk type is unused and therefore set to struct{}.
+	ModerationsSpan = Span[openai.ModerationResponse, struct{}]
+	// CountTokensSpan represents an Anthropic count tokens request span. The chunk type is unused and therefore set to struct{}.
+	CountTokensSpan = Span[anthropicschema.CountTokensResponse, struct{}]
 )
 
 type (
@@ -145,8 +175,14 @@ type (
 	ResponsesRecorder = SpanRecorder[openai.ResponseRequest, openai.Response, openai.ResponseStreamEventUnion]
 	// SpeechRecorder records attributes to a span according to a semantic convention.
 	SpeechRecorder = SpanRecorder[openai.SpeechRequest, []byte, openai.SpeechStreamChunk]
+	// TranscriptionRecorder records attributes to a span according to a semantic convention.
+	TranscriptionRecorder = SpanRecorder[openai.TranscriptionRequest, openai.TranscriptionResponse, openai.TranscriptionStreamEvent]
 	// RerankRecorder records attributes to a span according to a semantic convention.
 	RerankRecorder = SpanRecorder[cohere.RerankV2Request, cohere.RerankV2Response, struct{}]
+	// GenerateContentRecorder records attributes to a span according to a semantic convention.
+	GenerateContentRecorder = SpanRecorder[gcp.GenerateContentRequest, genai.GenerateContentResponse, genai.GenerateContentResponse]
+	// ConverseRecorder records attributes to a span according to a semantic convention.
+	ConverseRecorder = SpanRecorder[awsbedrock.ConverseInput, awsbedrock.ConverseResponse, awsbedrock.ConverseStreamEvent]
 	// MessageRecorder records attributes to a span according to a semantic convention.
 	MessageRecorder = SpanRecorder[anthropicschema.MessagesRequest, anthropicschema.MessagesResponse, anthropicschema.MessagesStreamChunk]
 )
@@ -194,11 +230,26 @@ func (NoopTracing) SpeechTracer() SpeechTracer {
 	return NoopSpeechTracer{}
 }
 
+// TranscriptionTracer implements Tracing.TranscriptionTracer.
+func (NoopTracing) TranscriptionTracer() TranscriptionTracer {
+	return NoopTranscriptionTracer{}
+}
+
 // RerankTracer implements Tracing.RerankTracer.
 func (NoopTracing) RerankTracer() RerankTracer {
 	return NoopRerankTracer{}
 }
 
+// GenerateContentTracer implements Tracing.GenerateContentTracer.
+func (NoopTracing) GenerateContentTracer() GenerateContentTracer {
+	return NoopGenerateContentTracer{}
+}
+
+// ConverseTracer implements Tracing.ConverseTracer.
+func (NoopTracing) ConverseTracer() ConverseTracer {
+	return NoopConverseTracer{}
+}
+
 func (NoopTracing) MessageTracer() MessageTracer {
 	return NoopMessageTracer{}
 }
@@ -223,10 +274,24 @@ type (
 	NoopResponsesTracer = NoopTracer[openai.ResponseRequest, openai.Response, openai.ResponseStreamEventUnion]
 	// NoopSpeechTracer implements SpeechTracer.
 	NoopSpeechTracer = NoopTracer[openai.SpeechRequest, []byte, openai.SpeechStreamChunk]
+	// NoopTranscriptionTracer implements TranscriptionTracer.
+	NoopTranscriptionTracer = NoopTracer[openai.TranscriptionRequest, openai.TranscriptionResponse, openai.TranscriptionStreamEvent]
 	// NoopRerankTracer implements RerankTracer.
 	NoopRerankTracer = NoopTracer[cohere.RerankV2Request, cohere.RerankV2Response, struct{}]
+	// NoopGenerateContentTracer implements GenerateContentTracer.
+	NoopGenerateContentTracer = NoopTracer[gcp.GenerateContentRequest, genai.GenerateContentResponse, genai.GenerateContentResponse]
+	// NoopConverseTracer implements ConverseTracer.
+	NoopConverseTracer = NoopTracer[awsbedrock.ConverseInput, awsbedrock.ConverseResponse, awsbedrock.ConverseStreamEvent]
 	// NoopMessageTracer implements MessageTracer.
 	NoopMessageTracer = NoopTracer[anthropicschema.MessagesRequest, anthropicschema.MessagesResponse, anthropicschema.MessagesStreamChunk]
+	// NoopFilesTracer is the tracer of the OpenAI files requests, which are not traced.
+	NoopFilesTracer = NoopTracer[openai.FileRequest, openai.FileObject, struct{}]
+	// NoopBatchesTracer is the tracer of the OpenAI batches requests, which are not traced.
+	NoopBatchesTracer = NoopTracer[openai.BatchRequest, openai.Batch, struct{}]
+	// NoopModerationsTracer is the tracer of the OpenAI moderations requests, which are not traced.
+	NoopModerationsTracer = NoopTracer[openai.ModerationRequest, openai.ModerationResponse, struct{}]
+	// NoopCountTokensTracer is the tracer of the Anthropic count tokens requests, which are not traced.
+	NoopCountTokensTracer = NoopTracer[anthropicschema.MessagesRequest, anthropicschema.CountTokensResponse, struct{}]
 )
 
 // StartSpanAndInjectHeaders implements RequestTracer.StartSpanAndInjectHeaders.
@@ -0,0 +1,707 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"cmp"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewAnthropicToAWSBedrockTranslator implements [Factory] for Anthropic Messages to AWS Bedrock Converse translation.
+// Unlike [NewAnthropicToAWSAnthropicTranslator], this uses the model-agnostic Converse API, so any model
+// available on Bedrock can be served through the Anthropic Messages endpoint:
+// https://docs.aws.amazon.com/bedrock/latest/APIReference/API_runtime_Converse.html
+func NewAnthropicToAWSBedrockTranslator(modelNameOverride internalapi.ModelNameOverride) AnthropicMessagesTranslator {
+	return &anthropicToAWSBedrockTranslator{modelNameOverride: modelNameOverride}
+}
+
+// anthropicToAWSBedrockTranslator translates Anthropic Messages API requests to AWS Bedrock Converse API.
+type anthropicToAWSBedrockTranslator struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+	stream            bool
+	// responseID is taken from the x-amzn-requestid header since Converse responses do not carry an ID.
+	responseID   string
+	bufferedBody []byte
+	events       []awsbedrock.ConverseStreamEvent
+	streamState  *awsBedrockStreamToAnthropicState
+	// Redaction configuration for debug logging
+	debugLogEnabled bool
+	enableRedaction bool
+	logger          *slog.Logger
+}
+
+// RequestBody implements [AnthropicMessagesTranslator.RequestBody].
+func (a *anthropicToAWSBedrockTranslator) RequestBody(_ []byte, body *anthropic.MessagesRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	a.stream = body.Stream
+	a.requestModel = cmp.Or(a.modelNameOverride, body.Model)
+
+	pathTemplate := "/model/%s/converse"
+	if a.stream {
+		pathTemplate = "/model/%s/converse-stream"
+		a.streamState = &awsBedrockStreamToAnthropicState{anthropicSSEWriter: anthropicSSEWriter{blockIndex: -1}}
+	}
+
+	bedrockReq, err := anthropicToBedrockConverseInput(body, a.requestModel)
+	if err != nil {
+		return nil, nil, err
+	}
+	newBody, err = json.Marshal(bedrockReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		// URL encode the model name for the path to handle ARNs with special characters.
+		{pathHeaderName, fmt.Sprintf(pathTemplate, url.PathEscape(a.requestModel))},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// anthropicToBedrockConverseInput converts an Anthropic Messages request to a Bedrock Converse request for the model.
+func anthropicToBedrockConverseInput(body *anthropic.MessagesRequest, model string) (*awsbedrock.ConverseInput, error) {
+	bedrockReq := &awsbedrock.ConverseInput{
+		InferenceConfig: &awsbedrock.InferenceConfiguration{
+			Temperature:   body.Temperature,
+			TopP:          body.TopP,
+			StopSequences: body.StopSequences,
+		},
+	}
+	if body.MaxTokens > 0 {
+		bedrockReq.InferenceConfig.MaxTokens = ptr.To(int64(body.MaxTokens))
+	}
+
+	// Parameters without a Converse equivalent are passed through to the model as-is. Only the Claude models
+	// understand them, while the other models such as Nova, Llama and Mistral reject unknown fields.
+	additional := map[string]any{}
+	if isBedrockClaudeModel(model) {
+		if body.TopK != nil {
+			additional["top_k"] = *body.TopK
+		}
+		if t := body.Thinking; t != nil {
+			switch {
+			case t.Enabled != nil:
+				additional["thinking"] = map[string]any{"type": "enabled", "budget_tokens": t.Enabled.BudgetTokens}
+			case t.Disabled != nil:
+				additional["thinking"] = map[string]any{"type": "disabled"}
+			case t.Adaptive != nil:
+				additional["thinking"] = map[string]any{"type": "adaptive"}
+			}
+		}
+	}
+	if len(additional) > 0 {
+		bedrockReq.AdditionalModelRequestFields = additional
+	}
+
+	if s := body.System; s != nil {
+		if s.Text != "" {
+			bedrockReq.System = append(bedrockReq.System, &awsbedrock.SystemContentBlock{Text: ptr.To(s.Text)})
+		}
+		for i := range s.Texts {
+			text := &s.Texts[i]
+			bedrockReq.System = append(bedrockReq.System, &awsbedrock.SystemContentBlock{Text: ptr.To(text.Text)})
+			if cp := anthropicCachePoint(text.CacheControl); cp != nil {
+				bedrockReq.System = append(bedrockReq.System, &awsbedrock.SystemContentBlock{CachePoint: cp})
+			}
+		}
+	}
+
+	bedrockReq.Messages = make([]*awsbedrock.Message, 0, len(body.Messages))
+	for i := range body.Messages {
+		msg, err := anthropicMessageToBedrockMessage(&body.Messages[i])
+		if err != nil {
+			return nil, err
+		}
+		bedrockReq.Messages = append(bedrockReq.Messages, msg)
+	}
+
+	if len(body.Tools) > 0 {
+		toolConfig, err := anthropicToolsToBedrockToolConfiguration(body.Tools, body.ToolChoice)
+		if err != nil {
+			return nil, err
+		}
+		bedrockReq.ToolConfig = toolConfig
+	}
+	return bedrockReq, nil
+}
+
+// isBedrockClaudeModel returns true if the Bedrock model ID, inference profile ID or ARN is of an Anthropic Claude model.
+func isBedrockClaudeModel(model string) bool {
+	return strings.Contains(model, "anthropic") && strings.Contains(model, "claude")
+}
+
+// anthropicCachePoint returns a Bedrock cache point block if the cache control is set, otherwise nil.
+func anthropicCachePoint(cc *anthropic.CacheControl) *awsbedrock.CachePointBlock {
+	if cc == nil || cc.Ephemeral == nil {
+		return nil
+	}
+	return &awsbedrock.CachePointBlock{Type: "default"}
+}
+
+// anthropicMessageToBedrockMessage converts a single Anthropic message to a Bedrock message.
+func anthropicMessageToBedrockMessage(msg *anthropic.MessageParam) (*awsbedrock.Message, error) {
+	var role string
+	switch msg.Role {
+	case anthropic.MessageRoleUser:
+		role = awsbedrock.ConversationRoleUser
+	case anthropic.MessageRoleAssistant:
+		role = awsbedrock.ConversationRoleAssistant
+	default:
+		return nil, fmt.Errorf("%w: unsupported message role %q", internalapi.ErrInvalidRequestBody, msg.Role)
+	}
+	out := &awsbedrock.Message{Role: role}
+	if msg.Content.Text != "" {
+		out.Content = []*awsbedrock.ContentBlock{{Text: ptr.To(msg.Content.Text)}}
+		return out, nil
+	}
+
+	out.Content = make([]*awsbedrock.ContentBlock, 0, len(msg.Content.Array))
+	for i := range msg.Content.Array {
+		block := &msg.Content.Array[i]
+		var (
+			converted *awsbedrock.ContentBlock
+			cc        *anthropic.CacheControl
+		)
+		switch {
+		case block.Text != nil:
+			converted = &awsbedrock.ContentBlock{Text: ptr.To(block.Text.Text)}
+			cc = block.Text.CacheControl
+		case block.Image != nil:
+			image, err := anthropicImageToBedrockImage(&block.Image.Source)
+			if err != nil {
+				return nil, err
+			}
+			converted = &awsbedrock.ContentBlock{Image: image}
+			cc = block.Image.CacheControl
+		case block.Document != nil:
+			document, err := anthropicDocumentToBedrockDocument(block.Document)
+			if err != nil {
+				return nil, err
+			}
+			converted = &awsbedrock.ContentBlock{Document: document}
+			cc = block.Document.CacheControl
+		case block.Thinking != nil:
+			converted = &awsbedrock.ContentBlock{ReasoningContent: &awsbedrock.ReasoningContentBlock{
+				ReasoningText: &awsbedrock.ReasoningTextBlock{Text: block.Thinking.Thinking, Signature: block.Thinking.Signature},
+			}}
+		case block.RedactedThinking != nil:
+			data, err := base64.StdEncoding.DecodeString(block.RedactedThinking.Data)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid redacted thinking data", internalapi.ErrInvalidRequestBody)
+			}
+			converted = &awsbedrock.ContentBlock{ReasoningContent: &awsbedrock.ReasoningContentBlock{RedactedContent: data}}
+		case block.ToolUse != nil:
+			input := block.ToolUse.Input
+			if input == nil {
+				input = map[string]any{}
+			}
+			converted = &awsbedrock.ContentBlock{ToolUse: &awsbedrock.ToolUseBlock{
+				Name:      block.ToolUse.Name,
+				Input:     input,
+				ToolUseID: block.ToolUse.ID,
+			}}
+			cc = block.ToolUse.CacheControl
+		case block.ToolResult != nil:
+			toolResult, err := anthropicToolResultToBedrockToolResult(block.ToolResult)
+			if err != nil {
+				return nil, err
+			}
+			converted = &awsbedrock.ContentBlock{ToolResult: toolResult}
+			cc = block.ToolResult.CacheControl
+		default:
+			// Server tools such as web search are executed by Anthropic and have no Converse equivalent.
+			return nil, fmt.Errorf("%w: unsupported content block in %s message", internalapi.ErrInvalidRequestBody, msg.Role)
+		}
+		out.Content = append(out.Content, converted)
+		if cp := anthropicCachePoint(cc); cp != nil {
+			out.Content = append(out.Content, &awsbedrock.ContentBlock{CachePoint: cp})
+		}
+	}
+	return out, nil
+}
+
+// anthropicImageToBedrockImage converts an Anthropic image source to a Bedrock image block.
+// Bedrock only accepts inline image bytes, so URL sources are rejected.
+func anthropicImageToBedrockImage(src *anthropic.ImageSource) (*awsbedrock.ImageBlock, error) {
+	if src.Base64 == nil {
+		return nil, fmt.Errorf("%w: only base64 image sources are supported", internalapi.ErrInvalidRequestBody)
+	}
+	var format string
+	switch src.Base64.MediaType {
+	case mimeTypeImagePNG:
+		format = "png"
+	case mimeTypeImageJPEG:
+		format = "jpeg"
+	case mimeTypeImageGIF:
+		format = "gif"
+	case mimeTypeImageWEBP:
+		format = "webp"
+	default:
+		return nil, fmt.Errorf("%w: unsupported image format %s", internalapi.ErrInvalidRequestBody, src.Base64.MediaType)
+	}
+	data, err := base64.StdEncoding.DecodeString(src.Base64.Data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid base64 image data", internalapi.ErrInvalidRequestBody)
+	}
+	return &awsbedrock.ImageBlock{Format: format, Source: awsbedrock.ImageSource{Bytes: data}}, nil
+}
+
+// anthropicDocumentToBedrockDocument converts an Anthropic document block to a Bedrock document block.
+// Bedrock only accepts inline document bytes, so URL and content block sources are rejected.
+func anthropicDocumentToBedrockDocument(doc *anthropic.DocumentBlockParam) (*awsbedrock.DocumentBlock, error) {
+	// Bedrock requires a document name, and it must be unique within the request.
+	name := cmp.Or(doc.Title, "document")
+	switch {
+	case doc.Source.Base64PDF != nil:
+		data, err := base64.StdEncoding.DecodeString(doc.Source.Base64PDF.Data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid base64 document data", internalapi.ErrInvalidRequestBody)
+		}
+		return &awsbedrock.DocumentBlock{Format: "pdf", Name: name, Source: awsbedrock.DocumentSource{Bytes: data}}, nil
+	case doc.Source.PlainText != nil:
+		return &awsbedrock.DocumentBlock{
+			Format: "txt", Name: name, Source: awsbedrock.DocumentSource{Bytes: []byte(doc.Source.PlainText.Data)},
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: only base64 PDF and plain text document sources are supported", internalapi.ErrInvalidRequestBody)
+	}
+}
+
+// anthropicToolResultToBedrockToolResult converts an Anthropic tool_result block to a Bedrock tool result block.
+func anthropicToolResultToBedrockToolResult(result *anthropic.ToolResultBlockParam) (*awsbedrock.ToolResultBlock, error) {
+	content := make([]*awsbedrock.ToolResultContentBlock, 0)
+	if c := result.Content; c != nil {
+		if c.Text != "" {
+			content = append(content, &awsbedrock.ToolResultContentBlock{Text: ptr.To(c.Text)})
+		}
+		for i := range c.Array {
+			item := &c.Array[i]
+			switch {
+			case item.Text != nil:
+				content = append(content, &awsbedrock.ToolResultContentBlock{Text: ptr.To(item.Text.Text)})
+			case item.Image != nil:
+				image, err := anthropicImageToBedrockImage(&item.Image.Source)
+				if err != nil {
+					return nil, err
+				}
+				content = append(content, &awsbedrock.ToolResultContentBlock{Image: image})
+			case item.Document != nil:
+				document, err := anthropicDocumentToBedrockDocument(item.Document)
+				if err != nil {
+					return nil, err
+				}
+				content = append(content, &awsbedrock.ToolResultContentBlock{Document: document})
+			default:
+				return nil, fmt.Errorf("%w: unsupported tool_result content", internalapi.ErrInvalidRequestBody)
+			}
+		}
+	}
+	status := "success"
+	if result.IsError {
+		status = "error"
+	}
+	return &awsbedrock.ToolResultBlock{Content: content, Status: &status, ToolUseID: ptr.To(result.ToolUseID)}, nil
+}
+
+// anthropicToolsToBedrockToolConfiguration converts Anthropic tools and tool choice to a Bedrock tool configuration.
+// Only custom tools are supported since the Anthropic-defined tools are not available through Converse.
+func anthropicToolsToBedrockToolConfiguration(tools []anthropic.ToolUnion, toolChoice *anthropic.ToolChoice) (*awsbedrock.ToolConfiguration, error) {
+	config := &awsbedrock.ToolConfiguration{Tools: make([]*awsbedrock.Tool, 0, len(tools))}
+	for i := range tools {
+		tool := tools[i].Tool
+		if tool == nil {
+			return nil, fmt.Errorf("%w: only custom tools are supported", internalapi.ErrInvalidRequestBody)
+		}
+		var desc *string
+		if tool.Description != "" {
+			desc = ptr.To(tool.Description)
+		}
+		config.Tools = append(config.Tools, &awsbedrock.Tool{
+			ToolSpec: &awsbedrock.ToolSpecification{
+				Name:        ptr.To(tool.Name),
+				Description: desc,
+				InputSchema: &awsbedrock.ToolInputSchema{JSON: tool.InputSchema},
+			},
+			CachePoint: anthropicCachePoint(tool.CacheControl),
+		})
+	}
+	if toolChoice != nil {
+		switch {
+		case toolChoice.Auto != nil:
+			config.ToolChoice = &awsbedrock.ToolChoice{Auto: &awsbedrock.AutoToolChoice{}}
+		case toolChoice.Any != nil:
+			config.ToolChoice = &awsbedrock.ToolChoice{Any: &awsbedrock.AnyToolChoice{}}
+		case toolChoice.Tool != nil:
+			config.ToolChoice = &awsbedrock.ToolChoice{Tool: &awsbedrock.SpecificToolChoice{Name: ptr.To(toolChoice.Tool.Name)}}
+		}
+		// Converse has no equivalent of "none". The tools are still sent since Bedrock rejects
+		// tool_use and tool_result blocks in the history when no tool configuration is present.
+	}
+	return config, nil
+}
+
+// ResponseHeaders implements [AnthropicMessagesTranslator.ResponseHeaders].
+func (a *anthropicToAWSBedrockTranslator) ResponseHeaders(headers map[string]string) (
+	newHeaders []internalapi.Header, err error,
+) {
+	if a.stream && headers[contentTypeHeaderName] == "application/vnd.amazon.eventstream" {
+		// The AWS eventstream is converted to Anthropic server-sent events.
+		newHeaders = []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}
+	}
+	a.responseID = headers["x-amzn-requestid"]
+	return
+}
+
+// ResponseBody implements [AnthropicMessagesTranslator.ResponseBody].
+// The Converse response does not contain the model, so the request model is returned as the response model.
+func (a *anthropicToAWSBedrockTranslator) ResponseBody(_ map[string]string, body io.Reader, endOfStream bool, span tracingapi.MessageSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel string, err error,
+) {
+	responseModel = a.requestModel
+	if a.stream {
+		return a.responseBodyStreaming(body, endOfStream)
+	}
+
+	var bedrockResp awsbedrock.ConverseResponse
+	if err = json.NewDecoder(body).Decode(&bedrockResp); err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+
+	anthropicResp := &anthropic.MessagesResponse{
+		ID:         a.responseID,
+		Type:       "message",
+		Role:       "assistant",
+		Model:      a.requestModel,
+		Content:    make([]anthropic.MessagesContentBlock, 0),
+		StopReason: ptr.To(bedrockStopReasonToAnthropic(bedrockResp.StopReason)),
+		Usage:      &anthropic.Usage{},
+	}
+	if u := bedrockResp.Usage; u != nil {
+		tokenUsage = metrics.ExtractTokenUsageFromExplicitCaching(u.InputTokens, u.OutputTokens,
+			u.CacheReadInputTokens, u.CacheWriteInputTokens)
+		anthropicResp.Usage = bedrockUsageToAnthropic(u)
+	}
+	if bedrockResp.Output != nil {
+		for _, output := range bedrockResp.Output.Message.Content {
+			// The Converse content block is a union, so only one of the members is set.
+			switch {
+			case output.Text != nil:
+				anthropicResp.Content = append(anthropicResp.Content, anthropic.MessagesContentBlock{
+					Text: &anthropic.TextBlock{Type: "text", Text: *output.Text},
+				})
+			case output.ToolUse != nil:
+				anthropicResp.Content = append(anthropicResp.Content, anthropic.MessagesContentBlock{
+					Tool: &anthropic.ToolUseBlock{
+						Type:  "tool_use",
+						ID:    output.ToolUse.ToolUseID,
+						Name:  output.ToolUse.Name,
+						Input: output.ToolUse.Input,
+					},
+				})
+			case output.ReasoningContent != nil:
+				if rt := output.ReasoningContent.ReasoningText; rt != nil {
+					anthropicResp.Content = append(anthropicResp.Content, anthropic.MessagesContentBlock{
+						Thinking: &anthropic.ThinkingBlock{Type: "thinking", Thinking: rt.Text, Signature: rt.Signature},
+					})
+				} else if rc := output.ReasoningContent.RedactedContent; len(rc) > 0 {
+					anthropicResp.Content = append(anthropicResp.Content, anthropic.MessagesContentBlock{
+						RedactedThinking: &anthropic.RedactedThinkingBlock{
+							Type: "redacted_thinking",
+							Data: base64.StdEncoding.EncodeToString(rc),
+						},
+					})
+				}
+			}
+		}
+	}
+
+	// Redact and log response when enabled
+	if a.debugLogEnabled && a.enableRedaction && a.logger != nil {
+		redactedResp := a.RedactAnthropicBody(anthropicResp)
+		if jsonBody, marshalErr := json.Marshal(redactedResp); marshalErr == nil {
+			a.logger.Debug("response body processing", slog.Any("response", string(jsonBody)))
+		}
+	}
+
+	if span != nil {
+		span.RecordResponse(anthropicResp)
+	}
+	newBody, err = json.Marshal(anthropicResp)
+	if err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// responseBodyStreaming converts the Converse eventstream to Anthropic SSE events.
+func (a *anthropicToAWSBedrockTranslator) responseBodyStreaming(body io.Reader, endOfStream bool) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel string, err error,
+) {
+	responseModel = a.requestModel
+	if a.streamState == nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("stream state not initialized")
+	}
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to read body: %w", err)
+	}
+	a.bufferedBody = append(a.bufferedBody, buf...)
+	a.bufferedBody, a.events = decodeConverseStreamEvents(a.bufferedBody, a.events)
+
+	s := a.streamState
+	s.messageID = cmp.Or(s.messageID, a.responseID)
+	s.model = a.requestModel
+
+	// Always return a non-nil body so that the raw eventstream bytes are never passed through.
+	newBody = make([]byte, 0)
+	for i := range a.events {
+		if err = s.handleEvent(&a.events[i], &newBody); err != nil {
+			return nil, nil, tokenUsage, responseModel, err
+		}
+	}
+	if endOfStream {
+		if err = s.emitClosingEvents(&newBody); err != nil {
+			return nil, nil, tokenUsage, responseModel, err
+		}
+	}
+	tokenUsage = s.tokenUsage
+	return
+}
+
+// ResponseError implements [AnthropicMessagesTranslator.ResponseError].
+// The Bedrock exception type is stored in the "x-amzn-errortype" header, and it is mapped to the Anthropic error
+// type by the HTTP status code since the Bedrock exception names do not match the Anthropic error types.
+func (a *anthropicToAWSBedrockTranslator) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	var message string
+	if strings.Contains(respHeaders[contentTypeHeaderName], jsonContentType) {
+		var bedrockError awsbedrock.BedrockException
+		if err = json.NewDecoder(body).Decode(&bedrockError); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal error body: %w", err)
+		}
+		message = bedrockError.Message
+		if errType := respHeaders[awsErrorTypeHeaderName]; errType != "" {
+			message = errType + ": " + message
+		}
+	} else {
+		var buf []byte
+		if buf, err = io.ReadAll(body); err != nil {
+			return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+		}
+		message = string(buf)
+	}
+	anthropicError := anthropic.ErrorResponse{
+		Type: "error",
+		Error: anthropic.ErrorResponseMessage{
+			Type:    anthropicErrorTypeForStatus(respHeaders[statusHeaderName]),
+			Message: message,
+		},
+	}
+	newBody, err = json.Marshal(anthropicError)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal error body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{contentTypeHeaderName, jsonContentType},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// anthropicErrorTypeForStatus returns the Anthropic error type corresponding to the HTTP status code.
+// https://platform.claude.com/docs/en/api/errors#http-errors
+func anthropicErrorTypeForStatus(statusCode string) string {
+	switch statusCode {
+	case "400":
+		return "invalid_request_error"
+	case "401":
+		return "authentication_error"
+	case "403":
+		return "permission_error"
+	case "404":
+		return "not_found_error"
+	case "413":
+		return "request_too_large"
+	case "429":
+		return "rate_limit_error"
+	case "503":
+		return "service_unavailable_error"
+	case "529":
+		return "overloaded_error"
+	default:
+		return "api_error"
+	}
+}
+
+// SetRedactionConfig implements [AnthropicResponseRedactor.SetRedactionConfig].
+func (a *anthropicToAWSBedrockTranslator) SetRedactionConfig(debugLogEnabled, enableRedaction bool, logger *slog.Logger) {
+	a.debugLogEnabled = debugLogEnabled
+	a.enableRedaction = enableRedaction
+	a.logger = logger
+}
+
+// RedactAnthropicBody implements [AnthropicResponseRedactor.RedactAnthropicBody].
+// Creates a redacted copy of the Anthropic response for safe logging without modifying the original.
+func (a *anthropicToAWSBedrockTranslator) RedactAnthropicBody(resp *anthropic.MessagesResponse) *anthropic.MessagesResponse {
+	if resp == nil {
+		return nil
+	}
+	redacted := *resp
+	if len(resp.Content) > 0 {
+		redacted.Content = make([]anthropic.MessagesContentBlock, len(resp.Content))
+		for i := range resp.Content {
+			redacted.Content[i] = redactAnthropicContent(&resp.Content[i])
+		}
+	}
+	return &redacted
+}
+
+// bedrockStopReasonToAnthropic converts the Bedrock stop reason to the Anthropic stop reason.
+func bedrockStopReasonToAnthropic(stopReason *string) anthropic.StopReason {
+	if stopReason == nil {
+		return anthropic.StopReasonEndTurn
+	}
+	switch *stopReason {
+	case awsbedrock.StopReasonToolUse:
+		return anthropic.StopReasonToolUse
+	case awsbedrock.StopReasonMaxTokens:
+		return anthropic.StopReasonMaxTokens
+	case awsbedrock.StopReasonStopSequence:
+		return anthropic.StopReasonStopSequence
+	case awsbedrock.StopReasonGuardrailIntervened, awsbedrock.StopReasonContentFiltered:
+		return anthropic.StopReasonRefusal
+	default:
+		return anthropic.StopReasonEndTurn
+	}
+}
+
+// bedrockUsageToAnthropic converts the Bedrock token usage to the Anthropic usage.
+// Both exclude the cached tokens from the input tokens.
+func bedrockUsageToAnthropic(u *awsbedrock.TokenUsage) *anthropic.Usage {
+	usage := &anthropic.Usage{InputTokens: float64(u.InputTokens), OutputTokens: float64(u.OutputTokens)}
+	if u.CacheReadInputTokens != nil {
+		usage.CacheReadInputTokens = float64(*u.CacheReadInputTokens)
+	}
+	if u.CacheWriteInputTokens != nil {
+		usage.CacheCreationInputTokens = float64(*u.CacheWriteInputTokens)
+	}
+	return usage
+}
+
+// awsBedrockStreamToAnthropicState tracks the state for converting Converse stream events to Anthropic SSE events.
+//
+// Converse content blocks map one-to-one to Anthropic content blocks, so the Converse content block index is
+// used as the Anthropic index. Converse only announces tool use blocks with contentBlockStart, so the start
+// of text and reasoning blocks is synthesized from their first delta.
+type awsBedrockStreamToAnthropicState struct {
+	anthropicSSEWriter
+	tokenUsage metrics.TokenUsage
+}
+
+// handleEvent converts a single Converse stream event to Anthropic SSE events.
+func (s *awsBedrockStreamToAnthropicState) handleEvent(event *awsbedrock.ConverseStreamEvent, out *[]byte) error {
+	if s.closingEmitted {
+		return nil
+	}
+	if !s.messageStarted {
+		if err := s.emitMessageStart(out); err != nil {
+			return err
+		}
+	}
+	switch awsbedrock.ConverseStreamEventType(event.EventType) {
+	case awsbedrock.ConverseStreamEventTypeContentBlockStart:
+		if event.Start != nil && event.Start.ToolUse != nil {
+			return s.startBlock(event.ContentBlockIndex, sseToolBlock{
+				Type:  "tool_use",
+				ID:    event.Start.ToolUse.ToolUseID,
+				Name:  event.Start.ToolUse.Name,
+				Input: map[string]any{},
+			}, out)
+		}
+	case awsbedrock.ConverseStreamEventTypeContentBlockDelta:
+		return s.handleDelta(event.ContentBlockIndex, event.Delta, out)
+	case awsbedrock.ConverseStreamEventTypeContentBlockStop:
+		return s.stopBlock(out)
+	case awsbedrock.ConverseStreamEventTypeMessageStop:
+		s.stopReason = bedrockStopReasonToAnthropic(event.StopReason)
+	case awsbedrock.ConverseStreamEventTypeMetadata:
+		// The metadata event carrying the usage is the last event of the stream.
+		if u := event.Usage; u != nil {
+			s.usage = bedrockUsageToAnthropic(u)
+			s.tokenUsage = metrics.ExtractTokenUsageFromExplicitCaching(u.InputTokens, u.OutputTokens,
+				u.CacheReadInputTokens, u.CacheWriteInputTokens)
+		}
+		return s.emitClosingEvents(out)
+	}
+	return nil
+}
+
+// handleDelta emits the content_block_delta event, preceded by content_block_start if the block is not open yet.
+func (s *awsBedrockStreamToAnthropicState) handleDelta(index int, delta *awsbedrock.ConverseStreamEventContentBlockDelta, out *[]byte) error {
+	if delta == nil {
+		return nil
+	}
+	var payload any
+	switch {
+	case delta.Text != nil:
+		if s.blockIndex != index {
+			if err := s.startBlock(index, sseTextBlock{Type: "text", Text: ""}, out); err != nil {
+				return err
+			}
+		}
+		payload = sseContentBlockDeltaText{
+			Type: "content_block_delta", Index: index, Delta: sseTextDelta{Type: "text_delta", Text: *delta.Text},
+		}
+	case delta.ToolUse != nil:
+		payload = sseContentBlockDeltaTool{
+			Type: "content_block_delta", Index: index, Delta: sseInputJSONDelta{Type: "input_json_delta", PartialJSON: delta.ToolUse.Input},
+		}
+	case delta.ReasoningContent != nil:
+		rc := delta.ReasoningContent
+		if len(rc.RedactedContent) > 0 {
+			// Anthropic delivers redacted thinking in full with content_block_start, without any delta.
+			return s.startBlock(index, sseRedactedThinkingBlock{
+				Type: "redacted_thinking", Data: base64.StdEncoding.EncodeToString(rc.RedactedContent),
+			}, out)
+		}
+		if s.blockIndex != index {
+			if err := s.startBlock(index, sseThinkingBlock{Type: "thinking", Thinking: "", Signature: ""}, out); err != nil {
+				return err
+			}
+		}
+		if rc.Signature != "" {
+			payload = sseContentBlockDeltaSignature{
+				Type: "content_block_delta", Index: index, Delta: sseSignatureDelta{Type: "signature_delta", Signature: rc.Signature},
+			}
+		} else {
+			payload = sseContentBlockDeltaThinking{
+				Type: "content_block_delta", Index: index, Delta: sseThinkingDelta{Type: "thinking_delta", Thinking: rc.Text},
+			}
+		}
+	default:
+		return nil
+	}
+	return s.emitDelta(payload, out)
+}
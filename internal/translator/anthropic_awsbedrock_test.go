@@ -0,0 +1,369 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws/protocol/eventstream"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestAnthropicToAWSBedrockTranslator_RequestBody(t *testing.T) {
+	req := &anthropic.MessagesRequest{
+		Model:         "anthropic.claude-sonnet-4",
+		MaxTokens:     2048,
+		Temperature:   ptr.To(0.5),
+		TopK:          ptr.To(10),
+		StopSequences: []string{"STOP"},
+		System:        &anthropic.SystemPrompt{Texts: []anthropic.TextBlockParam{{Type: "text", Text: "be nice", CacheControl: &anthropic.CacheControl{Ephemeral: &anthropic.CacheControlEphemeral{Type: "ephemeral"}}}}},
+		Thinking:      &anthropic.Thinking{Enabled: &anthropic.ThinkingEnabled{Type: "enabled", BudgetTokens: 1024}},
+		Messages: []anthropic.MessageParam{
+			{Role: anthropic.MessageRoleUser, Content: anthropic.MessageContent{Text: "what is cos(7)?"}},
+			{Role: anthropic.MessageRoleAssistant, Content: anthropic.MessageContent{Array: []anthropic.ContentBlockParam{
+				{Thinking: &anthropic.ThinkingBlockParam{Type: "thinking", Thinking: "use the tool", Signature: "sig"}},
+				{ToolUse: &anthropic.ToolUseBlockParam{Type: "tool_use", ID: "tool-1", Name: "cosine", Input: map[string]any{"x": 7}}},
+			}}},
+			{Role: anthropic.MessageRoleUser, Content: anthropic.MessageContent{Array: []anthropic.ContentBlockParam{
+				{ToolResult: &anthropic.ToolResultBlockParam{Type: "tool_result", ToolUseID: "tool-1", Content: &anthropic.ToolResultContent{Text: "0.75"}}},
+				{Image: &anthropic.ImageBlockParam{Type: "image", Source: anthropic.ImageSource{Base64: &anthropic.Base64ImageSource{Type: "base64", MediaType: "image/png", Data: "aGk="}}}},
+			}}},
+		},
+		Tools: []anthropic.ToolUnion{{Tool: &anthropic.Tool{
+			Type: "custom", Name: "cosine", Description: "cosine of x",
+			InputSchema: anthropic.ToolInputSchema{Type: "object", Properties: map[string]any{"x": map[string]any{"type": "number"}}, Required: []string{"x"}},
+		}}},
+		ToolChoice: &anthropic.ToolChoice{Tool: &anthropic.ToolChoiceTool{Type: "tool", Name: "cosine"}},
+	}
+
+	t.Run("non-streaming", func(t *testing.T) {
+		tr := NewAnthropicToAWSBedrockTranslator("")
+		headers, body, err := tr.RequestBody(nil, req, false)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{
+			{pathHeaderName, "/model/anthropic.claude-sonnet-4/converse"},
+			{contentLengthHeaderName, strconv.Itoa(len(body))},
+		}, headers)
+		require.JSONEq(t, `{
+			"additionalModelRequestFields": {"thinking": {"type": "enabled", "budget_tokens": 1024}, "top_k": 10},
+			"inferenceConfig": {"maxTokens": 2048, "stopSequences": ["STOP"], "temperature": 0.5},
+			"system": [{"text": "be nice"}, {"cachePoint": {"type": "default"}}],
+			"messages": [
+				{"role": "user", "content": [{"text": "what is cos(7)?"}]},
+				{"role": "assistant", "content": [
+					{"reasoningContent": {"reasoningText": {"text": "use the tool", "signature": "sig"}}},
+					{"toolUse": {"name": "cosine", "input": {"x": 7}, "toolUseId": "tool-1"}}
+				]},
+				{"role": "user", "content": [
+					{"toolResult": {"content": [{"text": "0.75"}], "status": "success", "toolUseId": "tool-1"}},
+					{"image": {"format": "png", "source": {"bytes": "aGk="}}}
+				]}
+			],
+			"toolConfig": {
+				"toolChoice": {"tool": {"name": "cosine"}},
+				"tools": [{"toolSpec": {"description": "cosine of x", "name": "cosine", "inputSchema": {"json": {"type": "object", "properties": {"x": {"type": "number"}}, "required": ["x"]}}}}]
+			}
+		}`, string(body))
+	})
+
+	t.Run("streaming with model override", func(t *testing.T) {
+		tr := NewAnthropicToAWSBedrockTranslator("arn:aws:bedrock:us-east-1:123:inference-profile/x")
+		streamReq := *req
+		streamReq.Stream = true
+		headers, _, err := tr.RequestBody(nil, &streamReq, false)
+		require.NoError(t, err)
+		require.Equal(t, "/model/arn:aws:bedrock:us-east-1:123:inference-profile%2Fx/converse-stream", headers[0].Value())
+	})
+
+	t.Run("non-claude model", func(t *testing.T) {
+		// The Claude specific parameters are not sent to the other models, which reject unknown fields.
+		tr := NewAnthropicToAWSBedrockTranslator("amazon.nova-pro-v1:0")
+		_, body, err := tr.RequestBody(nil, req, false)
+		require.NoError(t, err)
+		require.NotContains(t, string(body), "additionalModelRequestFields")
+		require.Contains(t, string(body), `"maxTokens":2048`)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			name   string
+			block  anthropic.ContentBlockParam
+			tools  []anthropic.ToolUnion
+			expErr string
+		}{
+			{
+				name:   "image url",
+				block:  anthropic.ContentBlockParam{Image: &anthropic.ImageBlockParam{Source: anthropic.ImageSource{URL: &anthropic.URLImageSource{URL: "https://example.com/a.png"}}}},
+				expErr: "only base64 image sources are supported",
+			},
+			{
+				name:   "image format",
+				block:  anthropic.ContentBlockParam{Image: &anthropic.ImageBlockParam{Source: anthropic.ImageSource{Base64: &anthropic.Base64ImageSource{MediaType: "image/bmp", Data: "aGk="}}}},
+				expErr: "unsupported image format image/bmp",
+			},
+			{
+				name:   "server tool use",
+				block:  anthropic.ContentBlockParam{ServerToolUse: &anthropic.ServerToolUseBlockParam{Name: "web_search"}},
+				expErr: "unsupported content block in user message",
+			},
+			{
+				name:   "non custom tool",
+				block:  anthropic.ContentBlockParam{Text: &anthropic.TextBlockParam{Text: "hi"}},
+				tools:  []anthropic.ToolUnion{{BashTool: &anthropic.BashTool{Name: "bash"}}},
+				expErr: "only custom tools are supported",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				_, _, err := NewAnthropicToAWSBedrockTranslator("").RequestBody(nil, &anthropic.MessagesRequest{
+					Model:    "m",
+					Messages: []anthropic.MessageParam{{Role: anthropic.MessageRoleUser, Content: anthropic.MessageContent{Array: []anthropic.ContentBlockParam{tc.block}}}},
+					Tools:    tc.tools,
+				}, false)
+				require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+				require.ErrorContains(t, err, tc.expErr)
+			})
+		}
+	})
+}
+
+func TestAnthropicToAWSBedrockTranslator_ResponseBody_NonStreaming(t *testing.T) {
+	tr := NewAnthropicToAWSBedrockTranslator("")
+	_, _, err := tr.RequestBody(nil, &anthropic.MessagesRequest{
+		Model:    "anthropic.claude-sonnet-4",
+		Messages: []anthropic.MessageParam{{Role: anthropic.MessageRoleUser, Content: anthropic.MessageContent{Text: "hi"}}},
+	}, false)
+	require.NoError(t, err)
+	headers, err := tr.ResponseHeaders(map[string]string{"x-amzn-requestid": "req-123"})
+	require.NoError(t, err)
+	require.Nil(t, headers)
+
+	resp := awsbedrock.ConverseResponse{
+		Output: &awsbedrock.ConverseOutput{Message: awsbedrock.Message{Role: "assistant", Content: []*awsbedrock.ContentBlock{
+			{ReasoningContent: &awsbedrock.ReasoningContentBlock{ReasoningText: &awsbedrock.ReasoningTextBlock{Text: "hmm", Signature: "sig"}}},
+			{ReasoningContent: &awsbedrock.ReasoningContentBlock{RedactedContent: []byte("secret")}},
+			{Text: ptr.To("let me check")},
+			{ToolUse: &awsbedrock.ToolUseBlock{Name: "cosine", ToolUseID: "tool-1", Input: map[string]any{"x": float64(7)}}},
+		}}},
+		StopReason: ptr.To(awsbedrock.StopReasonToolUse),
+		Usage:      &awsbedrock.TokenUsage{InputTokens: 10, OutputTokens: 20, TotalTokens: 35, CacheReadInputTokens: ptr.To(int64(5))},
+	}
+	body, err := json.Marshal(resp)
+	require.NoError(t, err)
+
+	_, newBody, tokenUsage, responseModel, err := tr.ResponseBody(nil, bytes.NewReader(body), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, "anthropic.claude-sonnet-4", responseModel)
+	inputTokens, _ := tokenUsage.InputTokens()
+	cachedTokens, _ := tokenUsage.CachedInputTokens()
+	outputTokens, _ := tokenUsage.OutputTokens()
+	require.Equal(t, uint32(15), inputTokens)
+	require.Equal(t, uint32(5), cachedTokens)
+	require.Equal(t, uint32(20), outputTokens)
+	require.JSONEq(t, `{
+		"id": "req-123",
+		"type": "message",
+		"role": "assistant",
+		"model": "anthropic.claude-sonnet-4",
+		"content": [
+			{"type": "thinking", "thinking": "hmm", "signature": "sig"},
+			{"type": "redacted_thinking", "data": "`+base64.StdEncoding.EncodeToString([]byte("secret"))+`"},
+			{"type": "text", "text": "let me check"},
+			{"type": "tool_use", "id": "tool-1", "name": "cosine", "input": {"x": 7}}
+		],
+		"stop_reason": "tool_use",
+		"usage": {"input_tokens": 10, "output_tokens": 20, "cache_read_input_tokens": 5, "cache_creation_input_tokens": 0}
+	}`, string(newBody))
+
+	t.Run("invalid body", func(t *testing.T) {
+		_, _, _, _, err := tr.ResponseBody(nil, strings.NewReader("{"), true, nil)
+		require.ErrorContains(t, err, "failed to unmarshal body")
+	})
+}
+
+func TestAnthropicToAWSBedrockTranslator_ResponseBody_Streaming(t *testing.T) {
+	newStreamingTranslator := func(t *testing.T) AnthropicMessagesTranslator {
+		tr := NewAnthropicToAWSBedrockTranslator("")
+		_, _, err := tr.RequestBody(nil, &anthropic.MessagesRequest{
+			Model:    "anthropic.claude-sonnet-4",
+			Stream:   true,
+			Messages: []anthropic.MessageParam{{Role: anthropic.MessageRoleUser, Content: anthropic.MessageContent{Text: "hi"}}},
+		}, false)
+		require.NoError(t, err)
+		headers, err := tr.ResponseHeaders(map[string]string{
+			"content-type":     "application/vnd.amazon.eventstream",
+			"x-amzn-requestid": "req-123",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}, headers)
+		return tr
+	}
+
+	t.Run("text and tool use", func(t *testing.T) {
+		tr := newStreamingTranslator(t)
+		raw, err := base64.StdEncoding.DecodeString(base64RealStreamingEvents)
+		require.NoError(t, err)
+
+		// Feed the eventstream in small chunks to exercise the buffering of partial messages.
+		var out []byte
+		for i := 0; i < len(raw); i += 100 {
+			chunk := raw[i:min(i+100, len(raw))]
+			_, body, _, _, err := tr.ResponseBody(nil, bytes.NewReader(chunk), false, nil)
+			require.NoError(t, err)
+			require.NotNil(t, body)
+			out = append(out, body...)
+		}
+		_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, bytes.NewReader(nil), true, nil)
+		require.NoError(t, err)
+		require.Empty(t, body)
+		require.Equal(t, "anthropic.claude-sonnet-4", responseModel)
+		inputTokens, _ := tokenUsage.InputTokens()
+		outputTokens, _ := tokenUsage.OutputTokens()
+		require.Equal(t, uint32(386), inputTokens)
+		require.Equal(t, uint32(75), outputTokens)
+
+		events := parseSSEEventsFromBytes(out)
+		var types []string
+		for _, e := range events {
+			types = append(types, e.eventType)
+		}
+		require.Equal(t, "message_start", types[0])
+		require.Equal(t, "content_block_start", types[1])
+		require.Equal(t, []string{
+			"content_block_stop",
+			"content_block_start", "content_block_delta", "content_block_delta", "content_block_stop",
+			"message_delta", "message_stop",
+		}, types[len(types)-7:])
+
+		require.JSONEq(t, `{"type":"message_start","message":{"id":"req-123","type":"message","role":"assistant","content":[],"model":"anthropic.claude-sonnet-4","stop_reason":null,"stop_sequence":null,"usage":{"input_tokens":0,"output_tokens":0}}}`, events[0].data)
+		require.JSONEq(t, `{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`, events[1].data)
+		require.JSONEq(t, `{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"To"}}`, events[2].data)
+		n := len(events)
+		require.JSONEq(t, `{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"tooluse_QklrEHKjRu6Oc4BQUfy7ZQ","name":"cosine","input":{}}}`, events[n-6].data)
+		require.JSONEq(t, `{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"x\": 7}"}}`, events[n-4].data)
+		require.JSONEq(t, `{"type":"content_block_stop","index":1}`, events[n-3].data)
+		require.JSONEq(t, `{"type":"message_delta","delta":{"stop_reason":"tool_use","stop_sequence":null},"usage":{"input_tokens":386,"output_tokens":75,"cache_read_input_tokens":0,"cache_creation_input_tokens":0}}`, events[n-2].data)
+		require.JSONEq(t, `{"type":"message_stop"}`, events[n-1].data)
+	})
+
+	t.Run("thinking", func(t *testing.T) {
+		tr := newStreamingTranslator(t)
+		buf := bytes.NewBuffer(nil)
+		e := eventstream.NewEncoder()
+		for _, event := range []struct {
+			typ  awsbedrock.ConverseStreamEventType
+			data awsbedrock.ConverseStreamEvent
+		}{
+			{awsbedrock.ConverseStreamEventTypeMessageStart, awsbedrock.ConverseStreamEvent{Role: ptr.To("assistant")}},
+			{awsbedrock.ConverseStreamEventTypeContentBlockDelta, awsbedrock.ConverseStreamEvent{Delta: &awsbedrock.ConverseStreamEventContentBlockDelta{
+				ReasoningContent: &awsbedrock.ReasoningContentBlockDelta{Text: "hmm"},
+			}}},
+			{awsbedrock.ConverseStreamEventTypeContentBlockDelta, awsbedrock.ConverseStreamEvent{Delta: &awsbedrock.ConverseStreamEventContentBlockDelta{
+				ReasoningContent: &awsbedrock.ReasoningContentBlockDelta{Signature: "sig"},
+			}}},
+			{awsbedrock.ConverseStreamEventTypeContentBlockStop, awsbedrock.ConverseStreamEvent{}},
+			{awsbedrock.ConverseStreamEventTypeContentBlockDelta, awsbedrock.ConverseStreamEvent{ContentBlockIndex: 1, Delta: &awsbedrock.ConverseStreamEventContentBlockDelta{
+				ReasoningContent: &awsbedrock.ReasoningContentBlockDelta{RedactedContent: []byte("secret")},
+			}}},
+			{awsbedrock.ConverseStreamEventTypeContentBlockStop, awsbedrock.ConverseStreamEvent{ContentBlockIndex: 1}},
+			{awsbedrock.ConverseStreamEventTypeContentBlockDelta, awsbedrock.ConverseStreamEvent{ContentBlockIndex: 2, Delta: &awsbedrock.ConverseStreamEventContentBlockDelta{Text: ptr.To("hello")}}},
+			{awsbedrock.ConverseStreamEventTypeContentBlockStop, awsbedrock.ConverseStreamEvent{ContentBlockIndex: 2}},
+			{awsbedrock.ConverseStreamEventTypeMessageStop, awsbedrock.ConverseStreamEvent{StopReason: ptr.To(awsbedrock.StopReasonMaxTokens)}},
+		} {
+			// The payload must carry the event type as well, since an empty eventType in the payload
+			// overrides the one decoded from the :event-type header.
+			event.data.EventType = event.typ.String()
+			payload, err := json.Marshal(event.data)
+			require.NoError(t, err)
+			require.NoError(t, e.Encode(buf, eventstream.Message{
+				Headers: eventstream.Headers{{Name: ":event-type", Value: eventstream.StringValue(event.typ.String())}},
+				Payload: payload,
+			}))
+		}
+
+		// The stream ends without the metadata event, so the closing events are emitted at the end of stream.
+		_, body, _, _, err := tr.ResponseBody(nil, buf, true, nil)
+		require.NoError(t, err)
+		events := parseSSEEventsFromBytes(body)
+		var data []string
+		for _, e := range events {
+			data = append(data, e.data)
+		}
+		require.Len(t, data, 12)
+		require.JSONEq(t, `{"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":"","signature":""}}`, data[1])
+		require.JSONEq(t, `{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"hmm"}}`, data[2])
+		require.JSONEq(t, `{"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"sig"}}`, data[3])
+		require.JSONEq(t, `{"type":"content_block_stop","index":0}`, data[4])
+		require.JSONEq(t, `{"type":"content_block_start","index":1,"content_block":{"type":"redacted_thinking","data":"`+base64.StdEncoding.EncodeToString([]byte("secret"))+`"}}`, data[5])
+		require.JSONEq(t, `{"type":"content_block_stop","index":1}`, data[6])
+		require.JSONEq(t, `{"type":"content_block_start","index":2,"content_block":{"type":"text","text":""}}`, data[7])
+		require.JSONEq(t, `{"type":"content_block_delta","index":2,"delta":{"type":"text_delta","text":"hello"}}`, data[8])
+		require.JSONEq(t, `{"type":"content_block_stop","index":2}`, data[9])
+		require.JSONEq(t, `{"type":"message_delta","delta":{"stop_reason":"max_tokens","stop_sequence":null},"usage":{"input_tokens":0,"output_tokens":0,"cache_read_input_tokens":0,"cache_creation_input_tokens":0}}`, data[10])
+		require.JSONEq(t, `{"type":"message_stop"}`, data[11])
+	})
+}
+
+func TestAnthropicToAWSBedrockTranslator_ResponseError(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		headers map[string]string
+		body    string
+		expBody string
+	}{
+		{
+			name: "bedrock exception",
+			headers: map[string]string{
+				statusHeaderName:       "429",
+				contentTypeHeaderName:  jsonContentType,
+				awsErrorTypeHeaderName: "ThrottlingException",
+			},
+			body:    `{"message":"Too many requests"}`,
+			expBody: `{"type":"error","request_id":"","error":{"type":"rate_limit_error","message":"ThrottlingException: Too many requests"}}`,
+		},
+		{
+			name:    "plain text",
+			headers: map[string]string{statusHeaderName: "503"},
+			body:    "upstream connect error",
+			expBody: `{"type":"error","request_id":"","error":{"type":"service_unavailable_error","message":"upstream connect error"}}`,
+		},
+		{
+			name:    "unknown status",
+			headers: map[string]string{statusHeaderName: "500"},
+			body:    "boom",
+			expBody: `{"type":"error","request_id":"","error":{"type":"api_error","message":"boom"}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			headers, body, err := NewAnthropicToAWSBedrockTranslator("").ResponseError(tc.headers, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expBody, string(body))
+			require.Equal(t, jsonContentType, headers[0].Value())
+		})
+	}
+
+	_, _, err := NewAnthropicToAWSBedrockTranslator("").ResponseError(
+		map[string]string{contentTypeHeaderName: jsonContentType}, strings.NewReader("{"))
+	require.ErrorContains(t, err, "failed to unmarshal error body")
+}
+
+func TestAnthropicToAWSBedrockTranslator_RedactAnthropicBody(t *testing.T) {
+	tr := NewAnthropicToAWSBedrockTranslator("").(*anthropicToAWSBedrockTranslator)
+	require.Nil(t, tr.RedactAnthropicBody(nil))
+	resp := &anthropic.MessagesResponse{Content: []anthropic.MessagesContentBlock{{Text: &anthropic.TextBlock{Type: "text", Text: "secret"}}}}
+	redacted := tr.RedactAnthropicBody(resp)
+	require.NotEqual(t, "secret", redacted.Content[0].Text.Text)
+	require.Equal(t, "secret", resp.Content[0].Text.Text)
+}
@@ -0,0 +1,354 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/tidwall/sjson"
+
+	anthropicschema "github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tokenizer"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewCountTokensAnthropicToAnthropicTranslator creates a passthrough translator for the Anthropic
+// /v1/messages/count_tokens endpoint.
+func NewCountTokensAnthropicToAnthropicTranslator(modelNameOverride internalapi.ModelNameOverride) AnthropicCountTokensTranslator {
+	return &anthropicToAnthropicCountTokensTranslator{
+		anthropicToAnthropicTranslator: anthropicToAnthropicTranslator{modelNameOverride: modelNameOverride},
+	}
+}
+
+// anthropicToAnthropicCountTokensTranslator implements [AnthropicCountTokensTranslator] for Anthropic.
+// The embedded messages translator is only used for the error conversion.
+type anthropicToAnthropicCountTokensTranslator struct {
+	anthropicToAnthropicTranslator
+}
+
+// RequestBody implements [AnthropicCountTokensTranslator.RequestBody].
+func (a *anthropicToAnthropicCountTokensTranslator) RequestBody(original []byte, req *anthropicschema.MessagesRequest, forceBodyMutation bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	a.requestModel = cmp.Or(a.modelNameOverride, req.Model)
+	if a.modelNameOverride != "" {
+		newBody, err = sjson.SetBytesOptions(original, "model", a.modelNameOverride, sjsonOptions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to set model name: %w", err)
+		}
+	}
+	if forceBodyMutation && len(newBody) == 0 {
+		newBody = original
+	}
+
+	newHeaders = []internalapi.Header{{pathHeaderName, "/v1/messages/count_tokens"}}
+	if len(newBody) > 0 {
+		newHeaders = append(newHeaders, internalapi.Header{contentLengthHeaderName, strconv.Itoa(len(newBody))})
+	}
+	return
+}
+
+// ResponseHeaders implements [AnthropicCountTokensTranslator.ResponseHeaders].
+func (a *anthropicToAnthropicCountTokensTranslator) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [AnthropicCountTokensTranslator.ResponseBody].
+//
+// Counting the tokens is free of charge, so the token usage is always empty.
+func (a *anthropicToAnthropicCountTokensTranslator) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.CountTokensSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	var resp anthropicschema.CountTokensResponse
+	if err = json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, nil, tokenUsage, a.requestModel, fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(&resp)
+	}
+	return nil, nil, tokenUsage, a.requestModel, nil
+}
+
+// NewCountTokensAnthropicToGCPAnthropicTranslator creates a translator for the Anthropic /v1/messages/count_tokens
+// endpoint to the count-tokens model of Anthropic on GCP Vertex AI, which takes the model in the body.
+// https://cloud.google.com/vertex-ai/generative-ai/docs/partner-models/claude/count-tokens
+func NewCountTokensAnthropicToGCPAnthropicTranslator(modelNameOverride internalapi.ModelNameOverride) AnthropicCountTokensTranslator {
+	return &anthropicToGCPAnthropicCountTokensTranslator{
+		anthropicToAnthropicCountTokensTranslator: anthropicToAnthropicCountTokensTranslator{
+			anthropicToAnthropicTranslator: anthropicToAnthropicTranslator{modelNameOverride: modelNameOverride},
+		},
+	}
+}
+
+// anthropicToGCPAnthropicCountTokensTranslator implements [AnthropicCountTokensTranslator] for GCP Anthropic.
+// The response is in the Anthropic format, so only the request differs from Anthropic.
+type anthropicToGCPAnthropicCountTokensTranslator struct {
+	anthropicToAnthropicCountTokensTranslator
+}
+
+// RequestBody implements [AnthropicCountTokensTranslator.RequestBody].
+func (a *anthropicToGCPAnthropicCountTokensTranslator) RequestBody(original []byte, req *anthropicschema.MessagesRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	a.requestModel = cmp.Or(a.modelNameOverride, req.Model)
+	newBody, err = sjson.SetBytesOptions(original, "model", a.requestModel, sjsonOptions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to set model name: %w", err)
+	}
+	path := buildGCPModelPathSuffix(gcpModelPublisherAnthropic, "count-tokens", "rawPredict")
+	newHeaders = []internalapi.Header{{pathHeaderName, path}, {contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// NewCountTokensAnthropicToAWSAnthropicTranslator creates a translator for the Anthropic /v1/messages/count_tokens
+// endpoint to the CountTokens API of AWS Bedrock with the InvokeModel input.
+// https://docs.aws.amazon.com/bedrock/latest/APIReference/API_runtime_CountTokens.html
+func NewCountTokensAnthropicToAWSAnthropicTranslator(apiVersion string, modelNameOverride internalapi.ModelNameOverride) AnthropicCountTokensTranslator {
+	return &anthropicToAWSAnthropicCountTokensTranslator{
+		anthropicToAnthropicCountTokensTranslator: anthropicToAnthropicCountTokensTranslator{
+			anthropicToAnthropicTranslator: anthropicToAnthropicTranslator{modelNameOverride: modelNameOverride},
+		},
+		apiVersion: apiVersion,
+	}
+}
+
+// anthropicToAWSAnthropicCountTokensTranslator implements [AnthropicCountTokensTranslator] for AWS Anthropic.
+type anthropicToAWSAnthropicCountTokensTranslator struct {
+	anthropicToAnthropicCountTokensTranslator
+	apiVersion string
+}
+
+// RequestBody implements [AnthropicCountTokensTranslator.RequestBody].
+//
+// The InvokeModel body is the same as the one of the messages endpoint, see [NewAnthropicToAWSAnthropicTranslator].
+// The max_tokens is required by InvokeModel but not by count_tokens, so it is set to 1 when missing, which
+// does not change the count.
+func (a *anthropicToAWSAnthropicCountTokensTranslator) RequestBody(original []byte, req *anthropicschema.MessagesRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	a.requestModel = cmp.Or(a.modelNameOverride, req.Model)
+	invokeBody, err := sjson.SetBytesOptions(original, anthropicVersionKey, a.apiVersion, sjsonOptions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to set anthropic_version field: %w", err)
+	}
+	// It is safe to use sjsonOptionsInPlace here since we have already created a new invokeBody above.
+	invokeBody, _ = sjson.DeleteBytesOptions(invokeBody, "model", sjsonOptionsInPlace)
+	invokeBody, _ = sjson.DeleteBytesOptions(invokeBody, "stream", sjsonOptionsInPlace)
+	if req.MaxTokens == 0 {
+		invokeBody, _ = sjson.SetBytesOptions(invokeBody, "max_tokens", 1, sjsonOptionsInPlace)
+	}
+
+	newBody, err = json.Marshal(&awsbedrock.CountTokensRequest{
+		Input: awsbedrock.CountTokensInput{InvokeModel: &awsbedrock.CountTokensInvokeModelInput{Body: invokeBody}},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	path := fmt.Sprintf("/model/%s/count-tokens", url.PathEscape(a.requestModel))
+	newHeaders = []internalapi.Header{{pathHeaderName, path}, {contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// ResponseBody implements [AnthropicCountTokensTranslator.ResponseBody].
+func (a *anthropicToAWSAnthropicCountTokensTranslator) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.CountTokensSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	var awsResp awsbedrock.CountTokensResponse
+	if err = json.NewDecoder(body).Decode(&awsResp); err != nil {
+		return nil, nil, tokenUsage, a.requestModel, fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	resp := &anthropicschema.CountTokensResponse{InputTokens: awsResp.InputTokens}
+	newBody, err = json.Marshal(resp)
+	if err != nil {
+		return nil, nil, tokenUsage, a.requestModel, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(resp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return newHeaders, newBody, tokenUsage, a.requestModel, nil
+}
+
+// NewCountTokensEstimationTranslator creates a translator for the Anthropic /v1/messages/count_tokens endpoint
+// that estimates the count in the gateway with the tokenizer of the model family, see [tokenizer.ForModel]. It is
+// used for the backends without a token counting API, and the request is never forwarded to the backend.
+func NewCountTokensEstimationTranslator(modelNameOverride internalapi.ModelNameOverride) AnthropicCountTokensTranslator {
+	return &countTokensEstimationTranslator{
+		anthropicToAnthropicTranslator: anthropicToAnthropicTranslator{modelNameOverride: modelNameOverride},
+	}
+}
+
+// countTokensEstimationTranslator implements [AnthropicCountTokensTranslator] and [LocalResponder].
+type countTokensEstimationTranslator struct {
+	anthropicToAnthropicTranslator
+	// response is the estimated count of the last request.
+	response []byte
+}
+
+// RequestBody implements [AnthropicCountTokensTranslator.RequestBody].
+func (c *countTokensEstimationTranslator) RequestBody(_ []byte, req *anthropicschema.MessagesRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	c.requestModel = cmp.Or(c.modelNameOverride, req.Model)
+	c.response, err = json.Marshal(&anthropicschema.CountTokensResponse{
+		InputTokens: estimateAnthropicInputTokens(tokenizer.ForModel(c.requestModel), req),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	return nil, nil, nil
+}
+
+// LocalResponse implements [LocalResponder.LocalResponse].
+func (c *countTokensEstimationTranslator) LocalResponse() ([]byte, bool) {
+	return c.response, c.response != nil
+}
+
+// ResponseHeaders implements [AnthropicCountTokensTranslator.ResponseHeaders].
+func (c *countTokensEstimationTranslator) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [AnthropicCountTokensTranslator.ResponseBody].
+//
+// This is never called since the request is answered locally.
+func (c *countTokensEstimationTranslator) ResponseBody(map[string]string, io.Reader, bool, tracingapi.CountTokensSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	return nil, nil, tokenUsage, c.requestModel, errors.New("unexpected response of a locally answered count tokens request")
+}
+
+const (
+	// countTokensMessageOverhead is the number of tokens of the role markers of each message.
+	countTokensMessageOverhead = 3
+	// countTokensToolUseSystemPrompt is the number of tokens of the system prompt that Anthropic adds when tools
+	// are given, which is 346 tokens for the auto and none tool choices of the Claude 4 models.
+	countTokensToolUseSystemPrompt = 346
+	// countTokensPerAttachment is the number of tokens of an image or a page of a PDF document, which is the
+	// maximum of an image resized by Anthropic to fit 1.15 megapixels. The size is not known without decoding,
+	// so the maximum is used to not underestimate the count.
+	countTokensPerAttachment = 1600
+)
+
+// estimateAnthropicInputTokens estimates the number of the input tokens of the messages request.
+func estimateAnthropicInputTokens(t *tokenizer.Tokenizer, req *anthropicschema.MessagesRequest) int {
+	count := 0
+	if system := req.System; system != nil {
+		count += t.CountTokens(system.Text)
+		for i := range system.Texts {
+			count += t.CountTokens(system.Texts[i].Text)
+		}
+	}
+	for i := range req.Messages {
+		msg := &req.Messages[i]
+		count += countTokensMessageOverhead + t.CountTokens(msg.Content.Text)
+		for j := range msg.Content.Array {
+			count += estimateContentBlockTokens(t, &msg.Content.Array[j])
+		}
+	}
+	if len(req.Tools) > 0 {
+		count += countTokensToolUseSystemPrompt
+		for i := range req.Tools {
+			count += countJSONTokens(t, &req.Tools[i])
+		}
+	}
+	return count
+}
+
+// estimateContentBlockTokens estimates the number of tokens of a content block of a message.
+func estimateContentBlockTokens(t *tokenizer.Tokenizer, block *anthropicschema.ContentBlockParam) int {
+	switch {
+	case block.Text != nil:
+		return t.CountTokens(block.Text.Text)
+	case block.Image != nil:
+		return countTokensPerAttachment
+	case block.Document != nil:
+		return estimateDocumentTokens(t, block.Document)
+	case block.SearchResult != nil:
+		return estimateSearchResultTokens(t, block.SearchResult)
+	case block.Thinking != nil:
+		return t.CountTokens(block.Thinking.Thinking)
+	case block.ToolUse != nil:
+		return t.CountTokens(block.ToolUse.Name) + countJSONTokens(t, block.ToolUse.Input)
+	case block.ToolResult != nil:
+		content := block.ToolResult.Content
+		if content == nil {
+			return 0
+		}
+		count := t.CountTokens(content.Text)
+		for i := range content.Array {
+			switch item := &content.Array[i]; {
+			case item.Text != nil:
+				count += t.CountTokens(item.Text.Text)
+			case item.Image != nil:
+				count += countTokensPerAttachment
+			case item.SearchResult != nil:
+				count += estimateSearchResultTokens(t, item.SearchResult)
+			case item.Document != nil:
+				count += estimateDocumentTokens(t, item.Document)
+			}
+		}
+		return count
+	case block.ServerToolUse != nil:
+		return t.CountTokens(block.ServerToolUse.Name) + countJSONTokens(t, block.ServerToolUse.Input)
+	case block.WebSearchToolResult != nil:
+		return countJSONTokens(t, block.WebSearchToolResult)
+	default:
+		// The redacted thinking blocks are encrypted, and do not count as the input tokens.
+		return 0
+	}
+}
+
+// estimateDocumentTokens estimates the number of tokens of a document. The PDF documents are counted as a single
+// page since their number of pages is not known without decoding them.
+func estimateDocumentTokens(t *tokenizer.Tokenizer, doc *anthropicschema.DocumentBlockParam) int {
+	count := t.CountTokens(doc.Title) + t.CountTokens(doc.Context)
+	switch source := &doc.Source; {
+	case source.PlainText != nil:
+		count += t.CountTokens(source.PlainText.Data)
+	case source.ContentBlock != nil:
+		content := &source.ContentBlock.Content
+		count += t.CountTokens(content.Text)
+		for i := range content.Array {
+			if item := &content.Array[i]; item.Text != nil {
+				count += t.CountTokens(item.Text.Text)
+			} else if item.Image != nil {
+				count += countTokensPerAttachment
+			}
+		}
+	default:
+		count += countTokensPerAttachment
+	}
+	return count
+}
+
+// estimateSearchResultTokens estimates the number of tokens of a search result.
+func estimateSearchResultTokens(t *tokenizer.Tokenizer, result *anthropicschema.SearchResultBlockParam) int {
+	count := t.CountTokens(result.Title) + t.CountTokens(result.Source)
+	for i := range result.Content {
+		count += t.CountTokens(result.Content[i].Text)
+	}
+	return count
+}
+
+// countJSONTokens estimates the number of tokens of the JSON encoding of v, which is how the tool definitions and
+// the tool inputs are given to the model.
+func countJSONTokens(t *tokenizer.Tokenizer, v any) int {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return t.CountTokens(string(b))
+}
@@ -0,0 +1,160 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	anthropicschema "github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/tokenizer"
+)
+
+const countTokensRequestBody = `{"model":"claude-sonnet-4-5","messages":[{"role":"user","content":"Hello, world"}]}`
+
+func parseCountTokensRequest(t *testing.T) *anthropicschema.MessagesRequest {
+	var req anthropicschema.MessagesRequest
+	require.NoError(t, json.Unmarshal([]byte(countTokensRequestBody), &req))
+	return &req
+}
+
+func TestAnthropicToAnthropicCountTokensTranslator(t *testing.T) {
+	t.Run("passthrough", func(t *testing.T) {
+		translator := NewCountTokensAnthropicToAnthropicTranslator("")
+		headers, body, err := translator.RequestBody([]byte(countTokensRequestBody), parseCountTokensRequest(t), false)
+		require.NoError(t, err)
+		require.Nil(t, body)
+		require.Equal(t, []internalapi.Header{{pathHeaderName, "/v1/messages/count_tokens"}}, headers)
+
+		headers, body, tokenUsage, responseModel, err := translator.ResponseBody(nil, strings.NewReader(`{"input_tokens":12}`), true, nil)
+		require.NoError(t, err)
+		require.Nil(t, headers)
+		require.Nil(t, body)
+		require.Equal(t, "claude-sonnet-4-5", responseModel)
+		_, ok := tokenUsage.InputTokens()
+		require.False(t, ok)
+	})
+
+	t.Run("override", func(t *testing.T) {
+		translator := NewCountTokensAnthropicToAnthropicTranslator("claude-opus-4-1")
+		headers, body, err := translator.RequestBody([]byte(countTokensRequestBody), parseCountTokensRequest(t), false)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"model":"claude-opus-4-1","messages":[{"role":"user","content":"Hello, world"}]}`, string(body))
+		require.Equal(t, []internalapi.Header{
+			{pathHeaderName, "/v1/messages/count_tokens"},
+			{contentLengthHeaderName, strconv.Itoa(len(body))},
+		}, headers)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		translator := NewCountTokensAnthropicToAnthropicTranslator("")
+		_, _, _, _, err := translator.ResponseBody(nil, strings.NewReader("not-json"), true, nil)
+		require.ErrorContains(t, err, "failed to unmarshal body")
+	})
+}
+
+func TestAnthropicToGCPAnthropicCountTokensTranslator(t *testing.T) {
+	translator := NewCountTokensAnthropicToGCPAnthropicTranslator("claude-sonnet-4-5@20250929")
+	headers, body, err := translator.RequestBody([]byte(countTokensRequestBody), parseCountTokensRequest(t), false)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"model":"claude-sonnet-4-5@20250929","messages":[{"role":"user","content":"Hello, world"}]}`, string(body))
+	require.Equal(t, []internalapi.Header{
+		{pathHeaderName, "publishers/anthropic/models/count-tokens:rawPredict"},
+		{contentLengthHeaderName, strconv.Itoa(len(body))},
+	}, headers)
+
+	_, body, _, responseModel, err := translator.ResponseBody(nil, strings.NewReader(`{"input_tokens":12}`), true, nil)
+	require.NoError(t, err)
+	require.Nil(t, body)
+	require.Equal(t, "claude-sonnet-4-5@20250929", responseModel)
+}
+
+func TestAnthropicToAWSAnthropicCountTokensTranslator(t *testing.T) {
+	translator := NewCountTokensAnthropicToAWSAnthropicTranslator("bedrock-2023-05-31", "us.anthropic.claude-sonnet-4-5-20250929-v1:0")
+	headers, body, err := translator.RequestBody([]byte(countTokensRequestBody), parseCountTokensRequest(t), false)
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{
+		{pathHeaderName, "/model/us.anthropic.claude-sonnet-4-5-20250929-v1:0/count-tokens"},
+		{contentLengthHeaderName, strconv.Itoa(len(body))},
+	}, headers)
+
+	var req awsbedrock.CountTokensRequest
+	require.NoError(t, json.Unmarshal(body, &req))
+	require.NotNil(t, req.Input.InvokeModel)
+	require.JSONEq(t, `{"anthropic_version":"bedrock-2023-05-31","max_tokens":1,"messages":[{"role":"user","content":"Hello, world"}]}`,
+		string(req.Input.InvokeModel.Body))
+
+	headers, body, _, responseModel, err := translator.ResponseBody(nil, strings.NewReader(`{"inputTokens":12}`), true, nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"input_tokens":12}`, string(body))
+	require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(body))}}, headers)
+	require.Equal(t, "us.anthropic.claude-sonnet-4-5-20250929-v1:0", responseModel)
+}
+
+func TestCountTokensEstimationTranslator(t *testing.T) {
+	translator := NewCountTokensEstimationTranslator("gpt-4o")
+	headers, body, err := translator.RequestBody([]byte(countTokensRequestBody), parseCountTokensRequest(t), false)
+	require.NoError(t, err)
+	require.Nil(t, headers)
+	require.Nil(t, body)
+
+	responder, ok := translator.(LocalResponder)
+	require.True(t, ok)
+	resp, ok := responder.LocalResponse()
+	require.True(t, ok)
+	// "Hello, world" is 3 tokens, plus the message overhead.
+	require.JSONEq(t, `{"input_tokens":6}`, string(resp))
+
+	_, _, _, _, err = translator.ResponseBody(nil, strings.NewReader(""), true, nil)
+	require.Error(t, err)
+}
+
+func TestEstimateAnthropicInputTokens(t *testing.T) {
+	tk := tokenizer.ForModel("gpt-4o")
+	for _, tc := range []struct {
+		name string
+		body string
+		exp  int
+	}{
+		{
+			name: "system and text blocks",
+			body: `{"system":"Be brief.","messages":[{"role":"user","content":[{"type":"text","text":"Hi"},{"type":"text","text":"there"}]}]}`,
+			exp:  3 + countTokensMessageOverhead + 2,
+		},
+		{
+			name: "image",
+			body: `{"messages":[{"role":"user","content":[{"type":"image","source":{"type":"url","url":"https://example.com/a.png"}}]}]}`,
+			exp:  countTokensMessageOverhead + countTokensPerAttachment,
+		},
+		{
+			name: "tool result",
+			body: `{"messages":[{"role":"user","content":[{"type":"tool_result","tool_use_id":"t","content":"sunny"}]}]}`,
+			exp:  countTokensMessageOverhead + 1,
+		},
+		{
+			name: "plain text document",
+			body: `{"messages":[{"role":"user","content":[{"type":"document","source":{"type":"text","media_type":"text/plain","data":"Hello"}}]}]}`,
+			exp:  countTokensMessageOverhead + 1,
+		},
+		{
+			name: "tools",
+			body: `{"messages":[],"tools":[{"name":"get_weather","input_schema":{"type":"object"}}]}`,
+			exp:  countTokensToolUseSystemPrompt + tk.CountTokens(`{"type":"","name":"get_weather","input_schema":{"type":"object"}}`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var req anthropicschema.MessagesRequest
+			require.NoError(t, json.Unmarshal([]byte(tc.body), &req))
+			require.Equal(t, tc.exp, estimateAnthropicInputTokens(tk, &req))
+		})
+	}
+}
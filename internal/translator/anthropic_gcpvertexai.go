@@ -0,0 +1,746 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"cmp"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/genai"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewAnthropicToGCPVertexAITranslator implements [Factory] for Anthropic Messages to GCP Vertex AI Gemini translation.
+// Unlike [NewAnthropicToGCPAnthropicTranslator], this targets the Gemini generateContent API, so Gemini models
+// can be served through the Anthropic Messages endpoint:
+// https://cloud.google.com/vertex-ai/generative-ai/docs/model-reference/inference
+func NewAnthropicToGCPVertexAITranslator(modelNameOverride internalapi.ModelNameOverride) AnthropicMessagesTranslator {
+	return &anthropicToGCPVertexAITranslator{modelNameOverride: modelNameOverride}
+}
+
+// anthropicToGCPVertexAITranslator translates Anthropic Messages API requests to GCP Vertex AI Gemini API.
+type anthropicToGCPVertexAITranslator struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+	stream            bool
+	streamDelimiter   []byte
+	bufferedBody      []byte // Buffer for incomplete JSON chunks.
+	streamState       *geminiStreamToAnthropicState
+	// Redaction configuration for debug logging
+	debugLogEnabled bool
+	enableRedaction bool
+	logger          *slog.Logger
+}
+
+// RequestBody implements [AnthropicMessagesTranslator.RequestBody].
+func (a *anthropicToGCPVertexAITranslator) RequestBody(_ []byte, body *anthropic.MessagesRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	a.stream = body.Stream
+	a.requestModel = cmp.Or(a.modelNameOverride, body.Model)
+
+	var pathSuffix string
+	if a.stream {
+		// For streaming requests, use the streamGenerateContent endpoint with SSE format.
+		pathSuffix = buildGCPModelPathSuffix(gcpModelPublisherGoogle, a.requestModel, gcpMethodStreamGenerateContent, "alt=sse")
+		a.streamState = &geminiStreamToAnthropicState{anthropicSSEWriter: anthropicSSEWriter{blockIndex: -1}}
+	} else {
+		pathSuffix = buildGCPModelPathSuffix(gcpModelPublisherGoogle, a.requestModel, gcpMethodGenerateContent)
+	}
+
+	gcpReq, err := anthropicToGeminiGenerateContentRequest(body, a.requestModel)
+	if err != nil {
+		return nil, nil, err
+	}
+	newBody, err = json.Marshal(gcpReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error marshaling Gemini request: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, pathSuffix},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// anthropicToGeminiGenerateContentRequest converts an Anthropic Messages request to a Gemini GenerateContentRequest.
+func anthropicToGeminiGenerateContentRequest(body *anthropic.MessagesRequest, requestModel internalapi.RequestModel) (*gcp.GenerateContentRequest, error) {
+	gcpReq := &gcp.GenerateContentRequest{GenerationConfig: &genai.GenerationConfig{StopSequences: body.StopSequences}}
+
+	gc := gcpReq.GenerationConfig
+	if body.MaxTokens > 0 {
+		gc.MaxOutputTokens = int32(body.MaxTokens)
+	}
+	if body.Temperature != nil {
+		gc.Temperature = ptr.To(float32(*body.Temperature))
+	}
+	if body.TopP != nil {
+		gc.TopP = ptr.To(float32(*body.TopP))
+	}
+	if body.TopK != nil {
+		gc.TopK = ptr.To(float32(*body.TopK))
+	}
+	if t := body.Thinking; t != nil {
+		switch {
+		case t.Enabled != nil:
+			//nolint:gosec // G115: BudgetTokens is bounded by max_tokens which is within int32 range.
+			gc.ThinkingConfig = &genai.ThinkingConfig{IncludeThoughts: true, ThinkingBudget: ptr.To(int32(t.Enabled.BudgetTokens))}
+		case t.Adaptive != nil:
+			// A budget of -1 lets the model decide how much to think.
+			gc.ThinkingConfig = &genai.ThinkingConfig{IncludeThoughts: true, ThinkingBudget: ptr.To(int32(-1))}
+		}
+		// When thinking is disabled, the thinking config is left unset so that no thoughts are returned.
+	}
+
+	if s := body.System; s != nil {
+		var parts []*genai.Part
+		if s.Text != "" {
+			parts = append(parts, genai.NewPartFromText(s.Text))
+		}
+		for i := range s.Texts {
+			parts = append(parts, genai.NewPartFromText(s.Texts[i].Text))
+		}
+		if len(parts) > 0 {
+			gcpReq.SystemInstruction = &genai.Content{Parts: parts}
+		}
+	}
+
+	// Gemini function responses are correlated with the calls by name, so keep track of the tool names by ID.
+	knownToolCalls := make(map[string]string)
+	gcpReq.Contents = make([]genai.Content, 0, len(body.Messages))
+	for i := range body.Messages {
+		content, err := anthropicMessageToGeminiContent(&body.Messages[i], knownToolCalls)
+		if err != nil {
+			return nil, err
+		}
+		gcpReq.Contents = append(gcpReq.Contents, *content)
+	}
+
+	if len(body.Tools) > 0 {
+		tools, err := anthropicToolsToGeminiTools(body.Tools, responseJSONSchemaAvailable(requestModel))
+		if err != nil {
+			return nil, err
+		}
+		gcpReq.Tools = tools
+		gcpReq.ToolConfig = anthropicToolChoiceToGeminiToolConfig(body.ToolChoice)
+	}
+	return gcpReq, nil
+}
+
+// anthropicMessageToGeminiContent converts an Anthropic message to a Gemini content.
+//
+// Gemini attaches the thought signature to the part following the thoughts, such as the first function call,
+// rather than to the thoughts themselves. Therefore, the signature of a thinking block is carried over to the
+// next part of the message.
+func anthropicMessageToGeminiContent(msg *anthropic.MessageParam, knownToolCalls map[string]string) (*genai.Content, error) {
+	var role string
+	switch msg.Role {
+	case anthropic.MessageRoleUser:
+		role = genai.RoleUser
+	case anthropic.MessageRoleAssistant:
+		role = genai.RoleModel
+	default:
+		return nil, fmt.Errorf("%w: unsupported message role %q", internalapi.ErrInvalidRequestBody, msg.Role)
+	}
+	content := &genai.Content{Role: role}
+	if msg.Content.Text != "" {
+		content.Parts = []*genai.Part{genai.NewPartFromText(msg.Content.Text)}
+		return content, nil
+	}
+
+	var pendingSignature []byte
+	content.Parts = make([]*genai.Part, 0, len(msg.Content.Array))
+	for i := range msg.Content.Array {
+		block := &msg.Content.Array[i]
+		var part *genai.Part
+		switch {
+		case block.Text != nil:
+			part = genai.NewPartFromText(block.Text.Text)
+		case block.Image != nil:
+			var err error
+			if part, err = anthropicImageToGeminiPart(&block.Image.Source); err != nil {
+				return nil, err
+			}
+		case block.Document != nil:
+			var err error
+			if part, err = anthropicDocumentToGeminiPart(block.Document); err != nil {
+				return nil, err
+			}
+		case block.Thinking != nil:
+			if sig := block.Thinking.Signature; sig != "" {
+				var err error
+				if pendingSignature, err = base64.StdEncoding.DecodeString(sig); err != nil {
+					return nil, fmt.Errorf("%w: invalid thinking signature", internalapi.ErrInvalidRequestBody)
+				}
+			}
+			if block.Thinking.Thinking == "" {
+				continue
+			}
+			part = &genai.Part{Text: block.Thinking.Thinking, Thought: true}
+		case block.RedactedThinking != nil:
+			// Redacted thinking is specific to Anthropic models and cannot be replayed to Gemini.
+			continue
+		case block.ToolUse != nil:
+			knownToolCalls[block.ToolUse.ID] = block.ToolUse.Name
+			input := block.ToolUse.Input
+			if input == nil {
+				input = map[string]any{}
+			}
+			part = genai.NewPartFromFunctionCall(block.ToolUse.Name, input)
+		case block.ToolResult != nil:
+			name, ok := knownToolCalls[block.ToolResult.ToolUseID]
+			if !ok {
+				return nil, fmt.Errorf("%w: tool_result references unknown tool_use_id %q",
+					internalapi.ErrInvalidRequestBody, block.ToolResult.ToolUseID)
+			}
+			output, err := anthropicToolResultText(block.ToolResult)
+			if err != nil {
+				return nil, err
+			}
+			key := "output"
+			if block.ToolResult.IsError {
+				key = "error"
+			}
+			part = genai.NewPartFromFunctionResponse(name, map[string]any{key: output})
+		default:
+			// Server tools such as web search are executed by Anthropic and have no Gemini equivalent.
+			return nil, fmt.Errorf("%w: unsupported content block in %s message", internalapi.ErrInvalidRequestBody, msg.Role)
+		}
+		if pendingSignature != nil && !part.Thought {
+			part.ThoughtSignature = pendingSignature
+			pendingSignature = nil
+		}
+		content.Parts = append(content.Parts, part)
+	}
+	if pendingSignature != nil && len(content.Parts) > 0 {
+		// The thoughts are the last parts of the message, so the signature is attached to the last of them.
+		content.Parts[len(content.Parts)-1].ThoughtSignature = pendingSignature
+	}
+	return content, nil
+}
+
+// anthropicImageToGeminiPart converts an Anthropic image source to a Gemini part.
+func anthropicImageToGeminiPart(src *anthropic.ImageSource) (*genai.Part, error) {
+	switch {
+	case src.Base64 != nil:
+		data, err := base64.StdEncoding.DecodeString(src.Base64.Data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid base64 image data", internalapi.ErrInvalidRequestBody)
+		}
+		return genai.NewPartFromBytes(data, src.Base64.MediaType), nil
+	case src.URL != nil:
+		// Identify mimeType based in image url.
+		mimeType := mimeTypeImageJPEG // Default to jpeg if unknown.
+		if mt := mime.TypeByExtension(path.Ext(src.URL.URL)); mt != "" {
+			mimeType = mt
+		}
+		return genai.NewPartFromURI(src.URL.URL, mimeType), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported image source", internalapi.ErrInvalidRequestBody)
+	}
+}
+
+// anthropicDocumentToGeminiPart converts an Anthropic document block to a Gemini part.
+func anthropicDocumentToGeminiPart(doc *anthropic.DocumentBlockParam) (*genai.Part, error) {
+	switch {
+	case doc.Source.Base64PDF != nil:
+		data, err := base64.StdEncoding.DecodeString(doc.Source.Base64PDF.Data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid base64 document data", internalapi.ErrInvalidRequestBody)
+		}
+		return genai.NewPartFromBytes(data, "application/pdf"), nil
+	case doc.Source.PlainText != nil:
+		return genai.NewPartFromText(doc.Source.PlainText.Data), nil
+	case doc.Source.URL != nil:
+		return genai.NewPartFromURI(doc.Source.URL.URL, "application/pdf"), nil
+	default:
+		return nil, fmt.Errorf("%w: only base64 PDF, plain text and URL document sources are supported", internalapi.ErrInvalidRequestBody)
+	}
+}
+
+// anthropicToolResultText returns the text content of an Anthropic tool_result block.
+// Gemini function responses are JSON objects, so only text content is supported.
+func anthropicToolResultText(result *anthropic.ToolResultBlockParam) (string, error) {
+	if result.Content == nil {
+		return "", nil
+	}
+	if result.Content.Text != "" {
+		return result.Content.Text, nil
+	}
+	var sb strings.Builder
+	for i := range result.Content.Array {
+		item := &result.Content.Array[i]
+		if item.Text == nil {
+			return "", fmt.Errorf("%w: only text tool_result content is supported", internalapi.ErrInvalidRequestBody)
+		}
+		sb.WriteString(item.Text.Text)
+	}
+	return sb.String(), nil
+}
+
+// anthropicToolsToGeminiTools converts Anthropic tools to Gemini function declarations.
+// Only custom tools are supported since the Anthropic-defined tools are not available on Gemini.
+func anthropicToolsToGeminiTools(tools []anthropic.ToolUnion, parametersJSONSchemaAvailable bool) ([]genai.Tool, error) {
+	functionDecls := make([]*genai.FunctionDeclaration, 0, len(tools))
+	for i := range tools {
+		tool := tools[i].Tool
+		if tool == nil {
+			return nil, fmt.Errorf("%w: only custom tools are supported", internalapi.ErrInvalidRequestBody)
+		}
+		functionDecl := &genai.FunctionDeclaration{Name: tool.Name, Description: tool.Description}
+		schema := map[string]any{"type": cmp.Or(tool.InputSchema.Type, "object")}
+		if len(tool.InputSchema.Properties) > 0 {
+			schema["properties"] = tool.InputSchema.Properties
+		}
+		if len(tool.InputSchema.Required) > 0 {
+			schema["required"] = tool.InputSchema.Required
+		}
+		if parametersJSONSchemaAvailable {
+			functionDecl.ParametersJsonSchema = schema
+		} else if len(tool.InputSchema.Properties) > 0 {
+			var err error
+			if functionDecl.Parameters, err = jsonSchemaToGemini(schema); err != nil {
+				return nil, fmt.Errorf("invalid JSON schema for parameters in tool %s: %w", tool.Name, err)
+			}
+		}
+		functionDecls = append(functionDecls, functionDecl)
+	}
+	return []genai.Tool{{FunctionDeclarations: functionDecls}}, nil
+}
+
+// anthropicToolChoiceToGeminiToolConfig converts the Anthropic tool choice to a Gemini tool config.
+func anthropicToolChoiceToGeminiToolConfig(toolChoice *anthropic.ToolChoice) *genai.ToolConfig {
+	if toolChoice == nil {
+		return nil
+	}
+	var config genai.FunctionCallingConfig
+	switch {
+	case toolChoice.Auto != nil:
+		config.Mode = genai.FunctionCallingConfigModeAuto
+	case toolChoice.Any != nil:
+		config.Mode = genai.FunctionCallingConfigModeAny
+	case toolChoice.Tool != nil:
+		config.Mode = genai.FunctionCallingConfigModeAny
+		config.AllowedFunctionNames = []string{toolChoice.Tool.Name}
+	case toolChoice.None != nil:
+		config.Mode = genai.FunctionCallingConfigModeNone
+	default:
+		return nil
+	}
+	return &genai.ToolConfig{FunctionCallingConfig: &config}
+}
+
+// ResponseHeaders implements [AnthropicMessagesTranslator.ResponseHeaders].
+func (a *anthropicToGCPVertexAITranslator) ResponseHeaders(_ map[string]string) (
+	newHeaders []internalapi.Header, err error,
+) {
+	if a.stream {
+		// For streaming responses, set content-type to text/event-stream to match Anthropic API.
+		newHeaders = []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}
+	}
+	return
+}
+
+// ResponseBody implements [AnthropicMessagesTranslator.ResponseBody].
+func (a *anthropicToGCPVertexAITranslator) ResponseBody(_ map[string]string, body io.Reader, endOfStream bool, span tracingapi.MessageSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel string, err error,
+) {
+	if a.stream {
+		return a.responseBodyStreaming(body, endOfStream)
+	}
+
+	gcpResp := &genai.GenerateContentResponse{}
+	if err = json.NewDecoder(body).Decode(gcpResp); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("error decoding GCP response: %w", err)
+	}
+	responseModel = cmp.Or(gcpResp.ModelVersion, a.requestModel)
+
+	anthropicResp := &anthropic.MessagesResponse{
+		ID:      gcpResp.ResponseID,
+		Type:    "message",
+		Role:    "assistant",
+		Model:   responseModel,
+		Content: make([]anthropic.MessagesContentBlock, 0),
+		Usage:   &anthropic.Usage{},
+	}
+	var (
+		hasToolUse   bool
+		finishReason genai.FinishReason
+	)
+	if len(gcpResp.Candidates) > 0 && gcpResp.Candidates[0] != nil {
+		candidate := gcpResp.Candidates[0]
+		finishReason = candidate.FinishReason
+		if candidate.Content != nil {
+			anthropicResp.Content, hasToolUse = geminiPartsToAnthropicContent(candidate.Content.Parts)
+		}
+	}
+	anthropicResp.StopReason = ptr.To(geminiFinishReasonToAnthropic(finishReason, hasToolUse))
+	if u := gcpResp.UsageMetadata; u != nil {
+		anthropicResp.Usage = geminiUsageToAnthropic(u)
+		tokenUsage = geminiUsageToTokenUsage(u)
+	}
+
+	// Redact and log response when enabled
+	if a.debugLogEnabled && a.enableRedaction && a.logger != nil {
+		redactedResp := a.RedactAnthropicBody(anthropicResp)
+		if jsonBody, marshalErr := json.Marshal(redactedResp); marshalErr == nil {
+			a.logger.Debug("response body processing", slog.Any("response", string(jsonBody)))
+		}
+	}
+
+	if span != nil {
+		span.RecordResponse(anthropicResp)
+	}
+	newBody, err = json.Marshal(anthropicResp)
+	if err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("error marshaling Anthropic response: %w", err)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// responseBodyStreaming converts the Gemini SSE chunks to Anthropic SSE events.
+func (a *anthropicToGCPVertexAITranslator) responseBodyStreaming(body io.Reader, endOfStream bool) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel string, err error,
+) {
+	responseModel = a.requestModel
+	if a.streamState == nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("stream state not initialized")
+	}
+	chunks, err := parseGeminiStreamingChunks(&a.bufferedBody, &a.streamDelimiter, body)
+	if err != nil {
+		return nil, nil, tokenUsage, responseModel, err
+	}
+
+	s := a.streamState
+	newBody = make([]byte, 0)
+	for i := range chunks {
+		if err = s.handleChunk(&chunks[i], a.requestModel, &newBody); err != nil {
+			return nil, nil, tokenUsage, responseModel, err
+		}
+	}
+	if endOfStream {
+		if err = s.emitClosingEvents(&newBody); err != nil {
+			return nil, nil, tokenUsage, responseModel, err
+		}
+	}
+	responseModel = cmp.Or(s.model, a.requestModel)
+	tokenUsage = s.tokenUsage
+	return
+}
+
+// ResponseError implements [AnthropicMessagesTranslator.ResponseError].
+func (a *anthropicToGCPVertexAITranslator) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	var buf []byte
+	if buf, err = io.ReadAll(body); err != nil {
+		return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+	}
+	message := string(buf)
+	var gcpError gcpVertexAIError
+	if json.Unmarshal(buf, &gcpError) == nil && gcpError.Error.Message != "" {
+		message = gcpError.Error.Message
+		if gcpError.Error.Status != "" {
+			message = gcpError.Error.Status + ": " + message
+		}
+	}
+	anthropicError := anthropic.ErrorResponse{
+		Type: "error",
+		Error: anthropic.ErrorResponseMessage{
+			Type:    anthropicErrorTypeForStatus(respHeaders[statusHeaderName]),
+			Message: message,
+		},
+	}
+	newBody, err = json.Marshal(anthropicError)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal error body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{contentTypeHeaderName, jsonContentType},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// SetRedactionConfig implements [AnthropicResponseRedactor.SetRedactionConfig].
+func (a *anthropicToGCPVertexAITranslator) SetRedactionConfig(debugLogEnabled, enableRedaction bool, logger *slog.Logger) {
+	a.debugLogEnabled = debugLogEnabled
+	a.enableRedaction = enableRedaction
+	a.logger = logger
+}
+
+// RedactAnthropicBody implements [AnthropicResponseRedactor.RedactAnthropicBody].
+// Creates a redacted copy of the Anthropic response for safe logging without modifying the original.
+func (a *anthropicToGCPVertexAITranslator) RedactAnthropicBody(resp *anthropic.MessagesResponse) *anthropic.MessagesResponse {
+	if resp == nil {
+		return nil
+	}
+	redacted := *resp
+	if len(resp.Content) > 0 {
+		redacted.Content = make([]anthropic.MessagesContentBlock, len(resp.Content))
+		for i := range resp.Content {
+			redacted.Content[i] = redactAnthropicContent(&resp.Content[i])
+		}
+	}
+	return &redacted
+}
+
+// geminiPartsToAnthropicContent converts the Gemini candidate parts to Anthropic content blocks,
+// and reports whether any of them is a tool use.
+//
+// The thought signature attached to a non-thought part is moved to the preceding thinking block,
+// or to an empty thinking block if there is none, since Anthropic only carries signatures on thinking blocks.
+func geminiPartsToAnthropicContent(parts []*genai.Part) (content []anthropic.MessagesContentBlock, hasToolUse bool) {
+	content = make([]anthropic.MessagesContentBlock, 0, len(parts))
+	for _, part := range parts {
+		if part == nil {
+			continue
+		}
+		if part.Thought {
+			content = append(content, anthropic.MessagesContentBlock{Thinking: &anthropic.ThinkingBlock{
+				Type: "thinking", Thinking: part.Text, Signature: geminiThoughtSignature(part),
+			}})
+			continue
+		}
+		if len(part.ThoughtSignature) > 0 {
+			signature := geminiThoughtSignature(part)
+			if n := len(content); n > 0 && content[n-1].Thinking != nil && content[n-1].Thinking.Signature == "" {
+				content[n-1].Thinking.Signature = signature
+			} else {
+				content = append(content, anthropic.MessagesContentBlock{Thinking: &anthropic.ThinkingBlock{
+					Type: "thinking", Signature: signature,
+				}})
+			}
+		}
+		switch {
+		case part.FunctionCall != nil:
+			hasToolUse = true
+			content = append(content, anthropic.MessagesContentBlock{Tool: &anthropic.ToolUseBlock{
+				Type:  "tool_use",
+				ID:    geminiToolUseID(part.FunctionCall),
+				Name:  part.FunctionCall.Name,
+				Input: geminiFunctionCallArgs(part.FunctionCall),
+			}})
+		case part.Text != "":
+			content = append(content, anthropic.MessagesContentBlock{Text: &anthropic.TextBlock{Type: "text", Text: part.Text}})
+		}
+	}
+	return
+}
+
+// geminiThoughtSignature returns the base64-encoded thought signature of the part, or an empty string if none.
+func geminiThoughtSignature(part *genai.Part) string {
+	if len(part.ThoughtSignature) == 0 {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(part.ThoughtSignature)
+}
+
+// geminiToolUseID returns the ID of the function call, generating one if Gemini did not set it.
+func geminiToolUseID(call *genai.FunctionCall) string {
+	if call.ID != "" {
+		return call.ID
+	}
+	return "toolu_" + uuid.New().String()
+}
+
+// geminiFunctionCallArgs returns the arguments of the function call, which are never nil in Anthropic tool_use.
+func geminiFunctionCallArgs(call *genai.FunctionCall) map[string]any {
+	if call.Args == nil {
+		return map[string]any{}
+	}
+	return call.Args
+}
+
+// geminiFinishReasonToAnthropic converts the Gemini finish reason to the Anthropic stop reason.
+// Gemini finishes with STOP when calling functions, so hasToolUse is used to report tool_use.
+func geminiFinishReasonToAnthropic(reason genai.FinishReason, hasToolUse bool) anthropic.StopReason {
+	switch reason {
+	case genai.FinishReasonMaxTokens:
+		return anthropic.StopReasonMaxTokens
+	case genai.FinishReasonSafety, genai.FinishReasonRecitation, genai.FinishReasonBlocklist,
+		genai.FinishReasonProhibitedContent, genai.FinishReasonSPII:
+		return anthropic.StopReasonRefusal
+	default:
+		if hasToolUse {
+			return anthropic.StopReasonToolUse
+		}
+		return anthropic.StopReasonEndTurn
+	}
+}
+
+// geminiUsageToAnthropic converts the Gemini usage metadata to the Anthropic usage.
+// Gemini includes the cached tokens in the prompt tokens while Anthropic excludes them from the input tokens,
+// and the thoughts tokens are reported as output tokens.
+func geminiUsageToAnthropic(u *genai.GenerateContentResponseUsageMetadata) *anthropic.Usage {
+	return &anthropic.Usage{
+		InputTokens:          float64(u.PromptTokenCount - u.CachedContentTokenCount),
+		OutputTokens:         float64(u.CandidatesTokenCount + u.ThoughtsTokenCount),
+		CacheReadInputTokens: float64(u.CachedContentTokenCount),
+	}
+}
+
+// geminiUsageToTokenUsage converts the Gemini usage metadata to the token usage.
+func geminiUsageToTokenUsage(u *genai.GenerateContentResponseUsageMetadata) metrics.TokenUsage {
+	tokenUsage := metrics.ExtractTokenUsageFromExplicitCaching(
+		int64(u.PromptTokenCount-u.CachedContentTokenCount),
+		int64(u.CandidatesTokenCount+u.ThoughtsTokenCount),
+		ptr.To(int64(u.CachedContentTokenCount)),
+		nil,
+	)
+	tokenUsage.SetReasoningTokens(uint32(u.ThoughtsTokenCount)) //nolint:gosec
+	return tokenUsage
+}
+
+// geminiStreamToAnthropicState tracks the state for converting Gemini stream chunks to Anthropic SSE events.
+//
+// Gemini streams text and thoughts as parts of consecutive chunks, so a part continues the open content block
+// of the same type. Function calls are streamed in full, so each of them becomes a complete tool_use block.
+type geminiStreamToAnthropicState struct {
+	anthropicSSEWriter
+	// nextIndex is the index of the next Anthropic content block.
+	nextIndex int
+	// openBlockType is the type of the open content block, either "text" or "thinking".
+	openBlockType string
+	hasToolUse    bool
+	tokenUsage    metrics.TokenUsage
+}
+
+// handleChunk converts a single Gemini stream chunk to Anthropic SSE events.
+func (s *geminiStreamToAnthropicState) handleChunk(chunk *genai.GenerateContentResponse, requestModel string, out *[]byte) error {
+	if s.closingEmitted {
+		return nil
+	}
+	if !s.messageStarted {
+		s.messageID = chunk.ResponseID
+		s.model = cmp.Or(chunk.ModelVersion, requestModel)
+		if err := s.emitMessageStart(out); err != nil {
+			return err
+		}
+	}
+	// Every chunk carries the cumulative usage, so the last one is the total.
+	if u := chunk.UsageMetadata; u != nil {
+		s.usage = geminiUsageToAnthropic(u)
+		s.tokenUsage = geminiUsageToTokenUsage(u)
+	}
+	if len(chunk.Candidates) == 0 || chunk.Candidates[0] == nil {
+		return nil
+	}
+	candidate := chunk.Candidates[0]
+	if candidate.Content != nil {
+		for _, part := range candidate.Content.Parts {
+			if part == nil {
+				continue
+			}
+			if err := s.handlePart(part, out); err != nil {
+				return err
+			}
+		}
+	}
+	if candidate.FinishReason != "" {
+		s.stopReason = geminiFinishReasonToAnthropic(candidate.FinishReason, s.hasToolUse)
+	}
+	return nil
+}
+
+// handlePart converts a single Gemini part to Anthropic SSE events.
+func (s *geminiStreamToAnthropicState) handlePart(part *genai.Part, out *[]byte) error {
+	if part.Thought {
+		if err := s.continueBlock("thinking", sseThinkingBlock{Type: "thinking", Thinking: "", Signature: ""}, out); err != nil {
+			return err
+		}
+		if part.Text != "" {
+			if err := s.emitDelta(sseContentBlockDeltaThinking{
+				Type: "content_block_delta", Index: s.blockIndex, Delta: sseThinkingDelta{Type: "thinking_delta", Thinking: part.Text},
+			}, out); err != nil {
+				return err
+			}
+		}
+		return s.emitSignature(part, out)
+	}
+
+	if len(part.ThoughtSignature) > 0 {
+		// The signature of the thoughts is attached to the following part, so it is emitted to the open thinking block,
+		// or to an empty thinking block if there is none.
+		if err := s.continueBlock("thinking", sseThinkingBlock{Type: "thinking", Thinking: "", Signature: ""}, out); err != nil {
+			return err
+		}
+		if err := s.emitSignature(part, out); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case part.FunctionCall != nil:
+		s.hasToolUse = true
+		index := s.nextIndex
+		s.nextIndex++
+		s.openBlockType = ""
+		if err := s.startBlock(index, sseToolBlock{
+			Type:  "tool_use",
+			ID:    geminiToolUseID(part.FunctionCall),
+			Name:  part.FunctionCall.Name,
+			Input: map[string]any{},
+		}, out); err != nil {
+			return err
+		}
+		args, err := json.Marshal(geminiFunctionCallArgs(part.FunctionCall))
+		if err != nil {
+			return fmt.Errorf("failed to marshal function call args: %w", err)
+		}
+		if err = s.emitDelta(sseContentBlockDeltaTool{
+			Type: "content_block_delta", Index: index, Delta: sseInputJSONDelta{Type: "input_json_delta", PartialJSON: string(args)},
+		}, out); err != nil {
+			return err
+		}
+		return s.stopBlock(out)
+	case part.Text != "":
+		if err := s.continueBlock("text", sseTextBlock{Type: "text", Text: ""}, out); err != nil {
+			return err
+		}
+		return s.emitDelta(sseContentBlockDeltaText{
+			Type: "content_block_delta", Index: s.blockIndex, Delta: sseTextDelta{Type: "text_delta", Text: part.Text},
+		}, out)
+	}
+	return nil
+}
+
+// continueBlock starts a new content block of the given type unless a block of the same type is already open.
+func (s *geminiStreamToAnthropicState) continueBlock(blockType string, block any, out *[]byte) error {
+	if s.blockIndex >= 0 && s.openBlockType == blockType {
+		return nil
+	}
+	index := s.nextIndex
+	s.nextIndex++
+	s.openBlockType = blockType
+	return s.startBlock(index, block, out)
+}
+
+// emitSignature emits the signature_delta event for the thought signature of the part, if any.
+func (s *geminiStreamToAnthropicState) emitSignature(part *genai.Part, out *[]byte) error {
+	if len(part.ThoughtSignature) == 0 {
+		return nil
+	}
+	return s.emitDelta(sseContentBlockDeltaSignature{
+		Type: "content_block_delta", Index: s.blockIndex, Delta: sseSignatureDelta{Type: "signature_delta", Signature: geminiThoughtSignature(part)},
+	}, out)
+}
@@ -0,0 +1,370 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestAnthropicToGCPVertexAITranslator_RequestBody(t *testing.T) {
+	req := &anthropic.MessagesRequest{
+		Model:         "gemini-2.5-pro",
+		MaxTokens:     2048,
+		Temperature:   ptr.To(0.5),
+		TopK:          ptr.To(10),
+		StopSequences: []string{"STOP"},
+		System:        &anthropic.SystemPrompt{Texts: []anthropic.TextBlockParam{{Type: "text", Text: "be nice"}}},
+		Thinking:      &anthropic.Thinking{Enabled: &anthropic.ThinkingEnabled{Type: "enabled", BudgetTokens: 1024}},
+		Messages: []anthropic.MessageParam{
+			{Role: anthropic.MessageRoleUser, Content: anthropic.MessageContent{Text: "what is cos(7)?"}},
+			{Role: anthropic.MessageRoleAssistant, Content: anthropic.MessageContent{Array: []anthropic.ContentBlockParam{
+				{Thinking: &anthropic.ThinkingBlockParam{Type: "thinking", Thinking: "use the tool", Signature: "c2ln"}},
+				{ToolUse: &anthropic.ToolUseBlockParam{Type: "tool_use", ID: "tool-1", Name: "cosine", Input: map[string]any{"x": 7}}},
+			}}},
+			{Role: anthropic.MessageRoleUser, Content: anthropic.MessageContent{Array: []anthropic.ContentBlockParam{
+				{ToolResult: &anthropic.ToolResultBlockParam{Type: "tool_result", ToolUseID: "tool-1", Content: &anthropic.ToolResultContent{Text: "0.75"}}},
+				{Image: &anthropic.ImageBlockParam{Type: "image", Source: anthropic.ImageSource{Base64: &anthropic.Base64ImageSource{Type: "base64", MediaType: "image/png", Data: "aGk="}}}},
+			}}},
+		},
+		Tools: []anthropic.ToolUnion{{Tool: &anthropic.Tool{
+			Type: "custom", Name: "cosine", Description: "cosine of x",
+			InputSchema: anthropic.ToolInputSchema{Type: "object", Properties: map[string]any{"x": map[string]any{"type": "number"}}, Required: []string{"x"}},
+		}}},
+		ToolChoice: &anthropic.ToolChoice{Tool: &anthropic.ToolChoiceTool{Type: "tool", Name: "cosine"}},
+	}
+
+	t.Run("non-streaming", func(t *testing.T) {
+		tr := NewAnthropicToGCPVertexAITranslator("")
+		headers, body, err := tr.RequestBody(nil, req, false)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{
+			{pathHeaderName, "publishers/google/models/gemini-2.5-pro:generateContent"},
+			{contentLengthHeaderName, strconv.Itoa(len(body))},
+		}, headers)
+
+		var gcpReq gcp.GenerateContentRequest
+		require.NoError(t, json.Unmarshal(body, &gcpReq))
+
+		gc := gcpReq.GenerationConfig
+		require.NotNil(t, gc)
+		require.Equal(t, int32(2048), gc.MaxOutputTokens)
+		require.Equal(t, ptr.To(float32(0.5)), gc.Temperature)
+		require.Equal(t, ptr.To(float32(10)), gc.TopK)
+		require.Equal(t, []string{"STOP"}, gc.StopSequences)
+		require.Equal(t, &genai.ThinkingConfig{IncludeThoughts: true, ThinkingBudget: ptr.To(int32(1024))}, gc.ThinkingConfig)
+
+		require.NotNil(t, gcpReq.SystemInstruction)
+		require.Len(t, gcpReq.SystemInstruction.Parts, 1)
+		require.Equal(t, "be nice", gcpReq.SystemInstruction.Parts[0].Text)
+
+		require.Len(t, gcpReq.Contents, 3)
+		require.Equal(t, genai.RoleUser, gcpReq.Contents[0].Role)
+		require.Equal(t, "what is cos(7)?", gcpReq.Contents[0].Parts[0].Text)
+
+		model := gcpReq.Contents[1]
+		require.Equal(t, genai.RoleModel, model.Role)
+		require.Len(t, model.Parts, 2)
+		require.True(t, model.Parts[0].Thought)
+		require.Equal(t, "use the tool", model.Parts[0].Text)
+		require.Empty(t, model.Parts[0].ThoughtSignature)
+		// The signature is attached to the function call following the thoughts.
+		require.Equal(t, []byte("sig"), model.Parts[1].ThoughtSignature)
+		require.Equal(t, "cosine", model.Parts[1].FunctionCall.Name)
+		require.Equal(t, map[string]any{"x": float64(7)}, model.Parts[1].FunctionCall.Args)
+
+		user := gcpReq.Contents[2]
+		require.Len(t, user.Parts, 2)
+		require.Equal(t, "cosine", user.Parts[0].FunctionResponse.Name)
+		require.Equal(t, map[string]any{"output": "0.75"}, user.Parts[0].FunctionResponse.Response)
+		require.Equal(t, &genai.Blob{Data: []byte("hi"), MIMEType: "image/png"}, user.Parts[1].InlineData)
+
+		require.Len(t, gcpReq.Tools, 1)
+		require.Len(t, gcpReq.Tools[0].FunctionDeclarations, 1)
+		decl := gcpReq.Tools[0].FunctionDeclarations[0]
+		require.Equal(t, "cosine", decl.Name)
+		require.Equal(t, "cosine of x", decl.Description)
+		require.Equal(t, map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"x": map[string]any{"type": "number"}},
+			"required":   []any{"x"},
+		}, decl.ParametersJsonSchema)
+		require.Equal(t, &genai.ToolConfig{FunctionCallingConfig: &genai.FunctionCallingConfig{
+			Mode: genai.FunctionCallingConfigModeAny, AllowedFunctionNames: []string{"cosine"},
+		}}, gcpReq.ToolConfig)
+	})
+
+	t.Run("streaming with model override", func(t *testing.T) {
+		tr := NewAnthropicToGCPVertexAITranslator("gemini-2.0-flash")
+		streamReq := *req
+		streamReq.Stream = true
+		headers, body, err := tr.RequestBody(nil, &streamReq, false)
+		require.NoError(t, err)
+		require.Equal(t, "publishers/google/models/gemini-2.0-flash:streamGenerateContent?alt=sse", headers[0].Value())
+
+		// The model does not support JSON schema parameters, so they are converted to the Gemini schema.
+		var gcpReq gcp.GenerateContentRequest
+		require.NoError(t, json.Unmarshal(body, &gcpReq))
+		decl := gcpReq.Tools[0].FunctionDeclarations[0]
+		require.Nil(t, decl.ParametersJsonSchema)
+		require.NotNil(t, decl.Parameters)
+		require.Equal(t, genai.Type("object"), decl.Parameters.Type)
+		require.Equal(t, []string{"x"}, decl.Parameters.Required)
+	})
+
+	t.Run("tool error result", func(t *testing.T) {
+		_, body, err := NewAnthropicToGCPVertexAITranslator("").RequestBody(nil, &anthropic.MessagesRequest{
+			Model: "gemini-2.5-pro",
+			Messages: []anthropic.MessageParam{
+				{Role: anthropic.MessageRoleAssistant, Content: anthropic.MessageContent{Array: []anthropic.ContentBlockParam{
+					{ToolUse: &anthropic.ToolUseBlockParam{Type: "tool_use", ID: "tool-1", Name: "cosine"}},
+				}}},
+				{Role: anthropic.MessageRoleUser, Content: anthropic.MessageContent{Array: []anthropic.ContentBlockParam{
+					{ToolResult: &anthropic.ToolResultBlockParam{Type: "tool_result", ToolUseID: "tool-1", IsError: true, Content: &anthropic.ToolResultContent{
+						Array: []anthropic.ToolResultContentItem{{Text: &anthropic.TextBlockParam{Type: "text", Text: "bad "}}, {Text: &anthropic.TextBlockParam{Type: "text", Text: "input"}}},
+					}}},
+				}}},
+			},
+		}, false)
+		require.NoError(t, err)
+		var gcpReq gcp.GenerateContentRequest
+		require.NoError(t, json.Unmarshal(body, &gcpReq))
+		require.Equal(t, "cosine", gcpReq.Contents[0].Parts[0].FunctionCall.Name)
+		require.Equal(t, map[string]any{"error": "bad input"}, gcpReq.Contents[1].Parts[0].FunctionResponse.Response)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			name   string
+			block  anthropic.ContentBlockParam
+			tools  []anthropic.ToolUnion
+			expErr string
+		}{
+			{
+				name:   "unknown tool use id",
+				block:  anthropic.ContentBlockParam{ToolResult: &anthropic.ToolResultBlockParam{ToolUseID: "missing"}},
+				expErr: `tool_result references unknown tool_use_id "missing"`,
+			},
+			{
+				name:   "invalid signature",
+				block:  anthropic.ContentBlockParam{Thinking: &anthropic.ThinkingBlockParam{Thinking: "hmm", Signature: "!"}},
+				expErr: "invalid thinking signature",
+			},
+			{
+				name:   "server tool use",
+				block:  anthropic.ContentBlockParam{ServerToolUse: &anthropic.ServerToolUseBlockParam{Name: "web_search"}},
+				expErr: "unsupported content block in user message",
+			},
+			{
+				name:   "non custom tool",
+				block:  anthropic.ContentBlockParam{Text: &anthropic.TextBlockParam{Text: "hi"}},
+				tools:  []anthropic.ToolUnion{{BashTool: &anthropic.BashTool{Name: "bash"}}},
+				expErr: "only custom tools are supported",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				_, _, err := NewAnthropicToGCPVertexAITranslator("").RequestBody(nil, &anthropic.MessagesRequest{
+					Model:    "gemini-2.5-pro",
+					Messages: []anthropic.MessageParam{{Role: anthropic.MessageRoleUser, Content: anthropic.MessageContent{Array: []anthropic.ContentBlockParam{tc.block}}}},
+					Tools:    tc.tools,
+				}, false)
+				require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+				require.ErrorContains(t, err, tc.expErr)
+			})
+		}
+	})
+}
+
+func TestAnthropicToGCPVertexAITranslator_ResponseBody_NonStreaming(t *testing.T) {
+	tr := NewAnthropicToGCPVertexAITranslator("")
+	_, _, err := tr.RequestBody(nil, &anthropic.MessagesRequest{
+		Model:    "gemini-2.5-pro",
+		Messages: []anthropic.MessageParam{{Role: anthropic.MessageRoleUser, Content: anthropic.MessageContent{Text: "hi"}}},
+	}, false)
+	require.NoError(t, err)
+	headers, err := tr.ResponseHeaders(nil)
+	require.NoError(t, err)
+	require.Nil(t, headers)
+
+	body := `{
+		"responseId": "resp-1",
+		"modelVersion": "gemini-2.5-pro-001",
+		"candidates": [{
+			"content": {"role": "model", "parts": [
+				{"text": "hmm", "thought": true},
+				{"functionCall": {"id": "call-1", "name": "cosine", "args": {"x": 7}}, "thoughtSignature": "c2ln"},
+				{"text": "let me check"}
+			]},
+			"finishReason": "STOP"
+		}],
+		"usageMetadata": {"promptTokenCount": 15, "cachedContentTokenCount": 5, "candidatesTokenCount": 20, "thoughtsTokenCount": 3}
+	}`
+	_, newBody, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(body), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, "gemini-2.5-pro-001", responseModel)
+	inputTokens, _ := tokenUsage.InputTokens()
+	cachedTokens, _ := tokenUsage.CachedInputTokens()
+	outputTokens, _ := tokenUsage.OutputTokens()
+	require.Equal(t, uint32(15), inputTokens)
+	require.Equal(t, uint32(5), cachedTokens)
+	require.Equal(t, uint32(23), outputTokens)
+	require.JSONEq(t, `{
+		"id": "resp-1",
+		"type": "message",
+		"role": "assistant",
+		"model": "gemini-2.5-pro-001",
+		"content": [
+			{"type": "thinking", "thinking": "hmm", "signature": "c2ln"},
+			{"type": "tool_use", "id": "call-1", "name": "cosine", "input": {"x": 7}},
+			{"type": "text", "text": "let me check"}
+		],
+		"stop_reason": "tool_use",
+		"usage": {"input_tokens": 10, "output_tokens": 23, "cache_read_input_tokens": 5, "cache_creation_input_tokens": 0}
+	}`, string(newBody))
+
+	t.Run("invalid body", func(t *testing.T) {
+		_, _, _, _, err := tr.ResponseBody(nil, strings.NewReader("{"), true, nil)
+		require.ErrorContains(t, err, "error decoding GCP response")
+	})
+}
+
+func TestGeminiPartsToAnthropicContent(t *testing.T) {
+	content, hasToolUse := geminiPartsToAnthropicContent([]*genai.Part{
+		{Text: "hello", ThoughtSignature: []byte("sig")},
+		{FunctionCall: &genai.FunctionCall{Name: "cosine"}},
+	})
+	require.True(t, hasToolUse)
+	require.Len(t, content, 3)
+	// Without preceding thoughts, the signature is carried by an empty thinking block.
+	require.Equal(t, &anthropic.ThinkingBlock{Type: "thinking", Signature: "c2ln"}, content[0].Thinking)
+	require.Equal(t, "hello", content[1].Text.Text)
+	require.True(t, strings.HasPrefix(content[2].Tool.ID, "toolu_"))
+	require.Equal(t, map[string]any{}, content[2].Tool.Input)
+}
+
+func TestGeminiFinishReasonToAnthropic(t *testing.T) {
+	for _, tc := range []struct {
+		reason     genai.FinishReason
+		hasToolUse bool
+		exp        anthropic.StopReason
+	}{
+		{reason: genai.FinishReasonStop, exp: anthropic.StopReasonEndTurn},
+		{reason: genai.FinishReasonStop, hasToolUse: true, exp: anthropic.StopReasonToolUse},
+		{reason: genai.FinishReasonMaxTokens, hasToolUse: true, exp: anthropic.StopReasonMaxTokens},
+		{reason: genai.FinishReasonSafety, exp: anthropic.StopReasonRefusal},
+		{reason: genai.FinishReasonProhibitedContent, exp: anthropic.StopReasonRefusal},
+		{reason: "", exp: anthropic.StopReasonEndTurn},
+	} {
+		require.Equal(t, tc.exp, geminiFinishReasonToAnthropic(tc.reason, tc.hasToolUse), tc.reason)
+	}
+}
+
+func TestAnthropicToGCPVertexAITranslator_ResponseBody_Streaming(t *testing.T) {
+	tr := NewAnthropicToGCPVertexAITranslator("")
+	_, _, err := tr.RequestBody(nil, &anthropic.MessagesRequest{
+		Model:    "gemini-2.5-pro",
+		Stream:   true,
+		Messages: []anthropic.MessageParam{{Role: anthropic.MessageRoleUser, Content: anthropic.MessageContent{Text: "hi"}}},
+	}, false)
+	require.NoError(t, err)
+	headers, err := tr.ResponseHeaders(nil)
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}, headers)
+
+	stream := []byte(`data: {"responseId":"resp-1","modelVersion":"gemini-2.5-pro","candidates":[{"content":{"role":"model","parts":[{"text":"let me think","thought":true}]}}]}
+
+data: {"responseId":"resp-1","candidates":[{"content":{"role":"model","parts":[{"text":"Hello"}]}}]}
+
+data: {"responseId":"resp-1","candidates":[{"content":{"role":"model","parts":[{"text":" world"}]}}]}
+
+data: {"responseId":"resp-1","candidates":[{"content":{"role":"model","parts":[{"functionCall":{"id":"call-1","name":"cosine","args":{"x":7}},"thoughtSignature":"c2ln"}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":10,"candidatesTokenCount":5,"thoughtsTokenCount":2}}
+
+`)
+	// Feed the stream in small chunks to exercise the buffering of partial chunks.
+	var out []byte
+	for i := 0; i < len(stream); i += 50 {
+		_, body, _, _, err := tr.ResponseBody(nil, bytes.NewReader(stream[i:min(i+50, len(stream))]), false, nil)
+		require.NoError(t, err)
+		require.NotNil(t, body)
+		out = append(out, body...)
+	}
+	_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, bytes.NewReader(nil), true, nil)
+	require.NoError(t, err)
+	out = append(out, body...)
+	require.Equal(t, "gemini-2.5-pro", responseModel)
+	inputTokens, _ := tokenUsage.InputTokens()
+	outputTokens, _ := tokenUsage.OutputTokens()
+	require.Equal(t, uint32(10), inputTokens)
+	require.Equal(t, uint32(7), outputTokens)
+
+	events := parseSSEEventsFromBytes(out)
+	var data []string
+	for _, e := range events {
+		data = append(data, e.data)
+	}
+	expected := []string{
+		`{"type":"message_start","message":{"id":"resp-1","type":"message","role":"assistant","content":[],"model":"gemini-2.5-pro","stop_reason":null,"stop_sequence":null,"usage":{"input_tokens":0,"output_tokens":0}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":"","signature":""}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"let me think"}}`,
+		`{"type":"content_block_stop","index":0}`,
+		`{"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Hello"}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":" world"}}`,
+		`{"type":"content_block_stop","index":1}`,
+		`{"type":"content_block_start","index":2,"content_block":{"type":"thinking","thinking":"","signature":""}}`,
+		`{"type":"content_block_delta","index":2,"delta":{"type":"signature_delta","signature":"c2ln"}}`,
+		`{"type":"content_block_stop","index":2}`,
+		`{"type":"content_block_start","index":3,"content_block":{"type":"tool_use","id":"call-1","name":"cosine","input":{}}}`,
+		`{"type":"content_block_delta","index":3,"delta":{"type":"input_json_delta","partial_json":"{\"x\":7}"}}`,
+		`{"type":"content_block_stop","index":3}`,
+		`{"type":"message_delta","delta":{"stop_reason":"tool_use","stop_sequence":null},"usage":{"input_tokens":10,"output_tokens":7,"cache_read_input_tokens":0,"cache_creation_input_tokens":0}}`,
+		`{"type":"message_stop"}`,
+	}
+	require.Len(t, data, len(expected))
+	for i := range expected {
+		require.JSONEq(t, expected[i], data[i], "event %d", i)
+	}
+}
+
+func TestAnthropicToGCPVertexAITranslator_ResponseError(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		headers map[string]string
+		body    string
+		expBody string
+	}{
+		{
+			name:    "gcp error",
+			headers: map[string]string{statusHeaderName: "429", contentTypeHeaderName: jsonContentType},
+			body:    `{"error":{"code":429,"message":"Quota exceeded","status":"RESOURCE_EXHAUSTED"}}`,
+			expBody: `{"type":"error","request_id":"","error":{"type":"rate_limit_error","message":"RESOURCE_EXHAUSTED: Quota exceeded"}}`,
+		},
+		{
+			name:    "plain text",
+			headers: map[string]string{statusHeaderName: "500"},
+			body:    "boom",
+			expBody: `{"type":"error","request_id":"","error":{"type":"api_error","message":"boom"}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			headers, body, err := NewAnthropicToGCPVertexAITranslator("").ResponseError(tc.headers, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expBody, string(body))
+			require.Equal(t, jsonContentType, headers[0].Value())
+		})
+	}
+}
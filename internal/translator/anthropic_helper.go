@@ -19,8 +19,10 @@ import (
 	"github.com/anthropics/anthropic-sdk-go/shared/constant"
 	openAIconstant "github.com/openai/openai-go/shared/constant"
 	openaisdk "github.com/openai/openai-go/v3"
+	"github.com/tidwall/gjson"
 	"k8s.io/utils/ptr"
 
+	anthropicschema "github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
@@ -32,6 +34,8 @@ import (
 const (
 	anthropicVersionKey   = "anthropic_version"
 	tempNotSupportedError = "temperature %.2f is not supported by Anthropic (must be between 0.0 and 1.0)"
+	// minThinkingBudgetTokens is the smallest extended thinking budget accepted by the Messages API.
+	minThinkingBudgetTokens = 1024
 )
 
 // anthropicInputSchemaKeysToSkip defines the keys from an OpenAI function parameter map
@@ -1253,3 +1257,72 @@ func messageToChatCompletion(anthropicResp *anthropic.Message, responseModel int
 	openAIResp.Choices = append(openAIResp.Choices, choice)
 	return openAIResp, tokenUsage, nil
 }
+
+// reasoningEffortToThinkingBudget maps an OpenAI reasoning effort to an extended thinking budget.
+// A zero budget means extended thinking is not requested.
+func reasoningEffortToThinkingBudget(effort string) (int64, error) {
+	switch effort {
+	case "", "none":
+		return 0, nil
+	case "minimal", "low":
+		return minThinkingBudgetTokens, nil
+	case "medium":
+		return 4096, nil
+	case "high", "xhigh":
+		return 16384, nil
+	default:
+		return 0, fmt.Errorf("%w: unsupported reasoning effort %q", internalapi.ErrInvalidRequestBody, effort)
+	}
+}
+
+// applyThinkingBudget enables extended thinking with the given budget on the Messages request translated from
+// another API. When the client did not limit the output, i.e. maxOutputTokens is zero, the default max_tokens of
+// the request is raised by the budget so that thinking does not eat into the output. Otherwise the budget is
+// clamped below the limit since the Messages API requires budget_tokens to be less than max_tokens, and thinking
+// is left disabled when the clamped budget is below the minimum.
+func applyThinkingBudget(out *anthropicschema.MessagesRequest, budget, maxOutputTokens int64) {
+	if maxOutputTokens == 0 {
+		out.MaxTokens += float64(budget)
+	} else if budget >= maxOutputTokens {
+		budget = maxOutputTokens - 1
+	}
+	if budget >= minThinkingBudgetTokens {
+		out.Thinking = &anthropicschema.Thinking{Enabled: &anthropicschema.ThinkingEnabled{Type: "enabled", BudgetTokens: float64(budget)}}
+	}
+}
+
+// scanAnthropicStreamEvents consumes the complete lines of the Anthropic SSE stream buffered so far, calling
+// onError for the error events and onChunk for the other events. The trailing partial line is kept in buffered
+// for the next call, and ping and unknown events are skipped.
+func scanAnthropicStreamEvents(buffered *[]byte,
+	onChunk func(chunk *anthropicschema.MessagesStreamChunk) error,
+	onError func(errEvent *anthropicschema.ErrorResponse) error,
+) error {
+	for {
+		i := bytes.IndexByte(*buffered, '\n')
+		if i == -1 {
+			return nil
+		}
+		line := (*buffered)[:i]
+		*buffered = (*buffered)[i+1:]
+		if !bytes.HasPrefix(line, sseDataPrefix) {
+			continue
+		}
+		data := bytes.TrimPrefix(line, sseDataPrefix)
+		if gjson.GetBytes(data, "type").String() == "error" {
+			var errEvent anthropicschema.ErrorResponse
+			_ = json.Unmarshal(data, &errEvent)
+			if err := onError(&errEvent); err != nil {
+				return err
+			}
+			continue
+		}
+		var chunk anthropicschema.MessagesStreamChunk
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			continue
+		}
+		if err := onChunk(&chunk); err != nil {
+			return err
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"k8s.io/utils/ptr"
 
+	anthropicschema "github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 )
@@ -1133,3 +1134,71 @@ func TestBuildAnthropicParamsWithReasoningEffort(t *testing.T) {
 		require.Equal(t, anthropic.OutputConfigEffort(""), params.OutputConfig.Effort)
 	})
 }
+
+func TestReasoningEffortToThinkingBudget(t *testing.T) {
+	for effort, exp := range map[string]int64{"": 0, "none": 0, "minimal": 1024, "low": 1024, "medium": 4096, "high": 16384, "xhigh": 16384} {
+		budget, err := reasoningEffortToThinkingBudget(effort)
+		require.NoError(t, err)
+		require.Equal(t, exp, budget, effort)
+	}
+	_, err := reasoningEffortToThinkingBudget("extreme")
+	require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+}
+
+func TestApplyThinkingBudget(t *testing.T) {
+	for _, tc := range []struct {
+		name                    string
+		budget, maxOutputTokens int64
+		expMaxTokens, expBudget float64
+	}{
+		{name: "no max output tokens", budget: 2048, expMaxTokens: 4096 + 2048, expBudget: 2048},
+		{name: "below max output tokens", budget: 2048, maxOutputTokens: 8192, expMaxTokens: 4096, expBudget: 2048},
+		{name: "clamped below max output tokens", budget: 4096, maxOutputTokens: 2048, expMaxTokens: 4096, expBudget: 2047},
+		{name: "clamped below the minimum", budget: 4096, maxOutputTokens: 1024, expMaxTokens: 4096},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &anthropicschema.MessagesRequest{MaxTokens: 4096}
+			applyThinkingBudget(out, tc.budget, tc.maxOutputTokens)
+			require.Equal(t, tc.expMaxTokens, out.MaxTokens)
+			if tc.expBudget == 0 {
+				require.Nil(t, out.Thinking)
+				return
+			}
+			require.Equal(t, &anthropicschema.Thinking{Enabled: &anthropicschema.ThinkingEnabled{Type: "enabled", BudgetTokens: tc.expBudget}}, out.Thinking)
+		})
+	}
+}
+
+func TestScanAnthropicStreamEvents(t *testing.T) {
+	buffered := []byte("event: ping\ndata: {\"type\": \"ping\"}\n\n" +
+		"event: message_stop\ndata: {\"type\": \"message_stop\"}\n\n" +
+		"event: error\ndata: {\"type\": \"error\", \"error\": {\"type\": \"overloaded_error\", \"message\": \"Overloaded\"}}\n\n" +
+		"event: message_stop\ndata: {\"type\": \"message_")
+	var chunks []*anthropicschema.MessagesStreamChunk
+	var errs []*anthropicschema.ErrorResponse
+	err := scanAnthropicStreamEvents(&buffered,
+		func(chunk *anthropicschema.MessagesStreamChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		},
+		func(errEvent *anthropicschema.ErrorResponse) error {
+			errs = append(errs, errEvent)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+	require.NotNil(t, chunks[0].MessageStop)
+	require.Len(t, errs, 1)
+	require.Equal(t, "Overloaded", errs[0].Error.Message)
+	// The partial line is kept for the next call.
+	require.Equal(t, `data: {"type": "message_`, string(buffered))
+
+	// The errors of the callbacks are returned.
+	buffered = []byte("data: {\"type\": \"message_stop\"}\n")
+	err = scanAnthropicStreamEvents(&buffered,
+		func(*anthropicschema.MessagesStreamChunk) error { return fmt.Errorf("chunk error") },
+		func(*anthropicschema.ErrorResponse) error { return nil },
+	)
+	require.EqualError(t, err, "chunk error")
+}
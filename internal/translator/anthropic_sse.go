@@ -0,0 +1,177 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// anthropicSSEWriter emits Anthropic Messages SSE events for the translators converting a non-Anthropic
+// stream to the Anthropic format. It only allows one content block to be open at a time, which matches
+// the Anthropic streaming protocol.
+type anthropicSSEWriter struct {
+	messageStarted bool
+	closingEmitted bool
+	// blockIndex is the index of the currently open content block, or -1 if none is open.
+	blockIndex int
+	messageID  string
+	model      string
+	stopReason anthropic.StopReason
+	// usage is reported in message_delta. Zero usage is reported if nil.
+	usage *anthropic.Usage
+}
+
+// emitMessageStart emits the Anthropic message_start SSE event.
+func (s *anthropicSSEWriter) emitMessageStart(out *[]byte) error {
+	s.messageStarted = true
+	payload := sseMessageStart{
+		Type: "message_start",
+		Message: sseMessageBody{
+			ID:      s.messageID,
+			Type:    "message",
+			Role:    "assistant",
+			Content: []any{},
+			Model:   s.model,
+			// The usage is only known at the end of the stream and is reported in message_delta.
+			Usage: sseMessageUsage{InputTokens: 0, OutputTokens: 0},
+		},
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message_start: %w", err)
+	}
+	appendAnthropicSSEEvent(out, "message_start", data)
+	return nil
+}
+
+// startBlock emits a content_block_start SSE event for the given block, closing the currently open block if any.
+func (s *anthropicSSEWriter) startBlock(index int, block any, out *[]byte) error {
+	if s.blockIndex >= 0 {
+		if err := s.stopBlock(out); err != nil {
+			return err
+		}
+	}
+	s.blockIndex = index
+	data, err := json.Marshal(sseContentBlockStart{Type: "content_block_start", Index: index, ContentBlock: block})
+	if err != nil {
+		return fmt.Errorf("failed to marshal content_block_start: %w", err)
+	}
+	appendAnthropicSSEEvent(out, "content_block_start", data)
+	return nil
+}
+
+// stopBlock emits a content_block_stop SSE event for the currently open block, if any.
+func (s *anthropicSSEWriter) stopBlock(out *[]byte) error {
+	if s.blockIndex < 0 {
+		return nil
+	}
+	data, err := json.Marshal(sseContentBlockStop{Type: "content_block_stop", Index: s.blockIndex})
+	if err != nil {
+		return fmt.Errorf("failed to marshal content_block_stop: %w", err)
+	}
+	appendAnthropicSSEEvent(out, "content_block_stop", data)
+	s.blockIndex = -1
+	return nil
+}
+
+// emitDelta emits a content_block_delta SSE event with the given payload.
+func (s *anthropicSSEWriter) emitDelta(payload any, out *[]byte) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal content_block_delta: %w", err)
+	}
+	appendAnthropicSSEEvent(out, "content_block_delta", data)
+	return nil
+}
+
+// emitClosingEvents emits content_block_stop (if a block is open), message_delta, and message_stop SSE events.
+func (s *anthropicSSEWriter) emitClosingEvents(out *[]byte) error {
+	if s.closingEmitted {
+		return nil
+	}
+	if !s.messageStarted {
+		if err := s.emitMessageStart(out); err != nil {
+			return err
+		}
+	}
+	s.closingEmitted = true
+	if err := s.stopBlock(out); err != nil {
+		return err
+	}
+	usage := s.usage
+	if usage == nil {
+		usage = &anthropic.Usage{}
+	}
+	data, err := json.Marshal(sseMessageDeltaWithUsage{
+		Type:  "message_delta",
+		Delta: sseMessageDeltaBody{StopReason: string(cmp.Or(s.stopReason, anthropic.StopReasonEndTurn)), StopSequence: nil},
+		Usage: *usage,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message_delta: %w", err)
+	}
+	appendAnthropicSSEEvent(out, "message_delta", data)
+	data, err = json.Marshal(sseMessageStop{Type: "message_stop"})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message_stop: %w", err)
+	}
+	appendAnthropicSSEEvent(out, "message_stop", data)
+	return nil
+}
+
+// The following structs complement the ones in openai_helper.go for the blocks and deltas
+// that cannot be produced from OpenAI chunks.
+
+type sseContentBlockStart struct {
+	Type         string `json:"type"`
+	Index        int    `json:"index"`
+	ContentBlock any    `json:"content_block"`
+}
+
+type sseThinkingBlock struct {
+	Type      string `json:"type"`
+	Thinking  string `json:"thinking"`
+	Signature string `json:"signature"`
+}
+
+type sseRedactedThinkingBlock struct {
+	Type string `json:"type"`
+	Data string `json:"data"`
+}
+
+type sseContentBlockDeltaThinking struct {
+	Type  string           `json:"type"`
+	Index int              `json:"index"`
+	Delta sseThinkingDelta `json:"delta"`
+}
+
+type sseThinkingDelta struct {
+	Type     string `json:"type"`
+	Thinking string `json:"thinking"`
+}
+
+type sseContentBlockDeltaSignature struct {
+	Type  string            `json:"type"`
+	Index int               `json:"index"`
+	Delta sseSignatureDelta `json:"delta"`
+}
+
+type sseSignatureDelta struct {
+	Type      string `json:"type"`
+	Signature string `json:"signature"`
+}
+
+// sseMessageDeltaWithUsage is the message_delta event carrying the complete usage, for the backends
+// that only report it at the end of the stream.
+type sseMessageDeltaWithUsage struct {
+	Type  string              `json:"type"`
+	Delta sseMessageDeltaBody `json:"delta"`
+	Usage anthropic.Usage     `json:"usage"`
+}
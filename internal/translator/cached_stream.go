@@ -0,0 +1,173 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"fmt"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// ChatCompletionResponseToStream converts the complete chat completion response body to the server-sent events of
+// the equivalent streaming response. This is used to answer a streaming request with a cached response.
+//
+// Each choice is sent in one chunk followed by the chunk with the finish reasons, the usage chunk if includeUsage
+// is true, and the [DONE] message.
+func ChatCompletionResponseToStream(body []byte, includeUsage bool) ([]byte, error) {
+	var resp openai.ChatCompletionResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chat completion response: %w", err)
+	}
+	newChunk := func(choices []openai.ChatCompletionResponseChunkChoice) *openai.ChatCompletionResponseChunk {
+		return &openai.ChatCompletionResponseChunk{
+			ID:                resp.ID,
+			Choices:           choices,
+			Created:           resp.Created,
+			Model:             resp.Model,
+			ServiceTier:       resp.ServiceTier,
+			SystemFingerprint: resp.SystemFingerprint,
+			Object:            "chat.completion.chunk",
+		}
+	}
+
+	var out []byte
+	finishes := make([]openai.ChatCompletionResponseChunkChoice, 0, len(resp.Choices))
+	for i := range resp.Choices {
+		choice := &resp.Choices[i]
+		delta := &openai.ChatCompletionResponseChunkChoiceDelta{
+			Content:     choice.Message.Content,
+			Role:        choice.Message.Role,
+			Annotations: choice.Message.Annotations,
+		}
+		for j := range choice.Message.ToolCalls {
+			toolCall := &choice.Message.ToolCalls[j]
+			delta.ToolCalls = append(delta.ToolCalls, openai.ChatCompletionChunkChoiceDeltaToolCall{
+				Index:    int64(j),
+				ID:       toolCall.ID,
+				Function: toolCall.Function,
+				Type:     toolCall.Type,
+			})
+		}
+		if err := appendChatCompletionChunk(&out, newChunk([]openai.ChatCompletionResponseChunkChoice{
+			{Index: choice.Index, Delta: delta},
+		})); err != nil {
+			return nil, err
+		}
+		finishes = append(finishes, openai.ChatCompletionResponseChunkChoice{
+			Index: choice.Index, Delta: &openai.ChatCompletionResponseChunkChoiceDelta{}, FinishReason: choice.FinishReason,
+		})
+	}
+	if err := appendChatCompletionChunk(&out, newChunk(finishes)); err != nil {
+		return nil, err
+	}
+	if includeUsage {
+		usageChunk := newChunk([]openai.ChatCompletionResponseChunkChoice{})
+		usageChunk.Usage = &resp.Usage
+		if err := appendChatCompletionChunk(&out, usageChunk); err != nil {
+			return nil, err
+		}
+	}
+	out = append(out, sseDataPrefix...)
+	out = append(out, sseDoneMessage...)
+	out = append(out, '\n', '\n')
+	return out, nil
+}
+
+// appendChatCompletionChunk appends the chunk as a server-sent event to the output buffer.
+func appendChatCompletionChunk(out *[]byte, chunk *openai.ChatCompletionResponseChunk) error {
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return fmt.Errorf("failed to marshal chat completion chunk: %w", err)
+	}
+	*out = append(*out, sseDataPrefix...)
+	*out = append(*out, data...)
+	*out = append(*out, '\n', '\n')
+	return nil
+}
+
+// MessagesResponseToStream converts the complete Anthropic messages response body to the server-sent events of the
+// equivalent streaming response. This is used to answer a streaming request with a cached response.
+//
+// Each content block is sent in full in a single delta.
+func MessagesResponseToStream(body []byte) ([]byte, error) {
+	var resp anthropic.MessagesResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal messages response: %w", err)
+	}
+	s := &anthropicSSEWriter{blockIndex: -1, messageID: resp.ID, model: resp.Model, usage: resp.Usage}
+	if resp.StopReason != nil {
+		s.stopReason = *resp.StopReason
+	}
+	var out []byte
+	if err := s.emitMessageStart(&out); err != nil {
+		return nil, err
+	}
+	for i := range resp.Content {
+		if err := s.emitCachedBlock(i, &resp.Content[i], &out); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.emitClosingEvents(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// emitCachedBlock emits the SSE events of the complete content block at the index.
+func (s *anthropicSSEWriter) emitCachedBlock(index int, block *anthropic.MessagesContentBlock, out *[]byte) error {
+	switch {
+	case block.Text != nil:
+		if err := s.startBlock(index, sseTextBlock{Type: "text", Text: ""}, out); err != nil {
+			return err
+		}
+		return s.emitDelta(sseContentBlockDeltaText{
+			Type: "content_block_delta", Index: index, Delta: sseTextDelta{Type: "text_delta", Text: block.Text.Text},
+		}, out)
+	case block.Tool != nil:
+		return s.emitCachedToolUse(index, block.Tool.Type, block.Tool.ID, block.Tool.Name, block.Tool.Input, out)
+	case block.ServerToolUse != nil:
+		return s.emitCachedToolUse(index, block.ServerToolUse.Type, block.ServerToolUse.ID, block.ServerToolUse.Name, block.ServerToolUse.Input, out)
+	case block.Thinking != nil:
+		if err := s.startBlock(index, sseThinkingBlock{Type: "thinking", Thinking: "", Signature: ""}, out); err != nil {
+			return err
+		}
+		if err := s.emitDelta(sseContentBlockDeltaThinking{
+			Type: "content_block_delta", Index: index, Delta: sseThinkingDelta{Type: "thinking_delta", Thinking: block.Thinking.Thinking},
+		}, out); err != nil {
+			return err
+		}
+		if block.Thinking.Signature == "" {
+			return nil
+		}
+		return s.emitDelta(sseContentBlockDeltaSignature{
+			Type: "content_block_delta", Index: index, Delta: sseSignatureDelta{Type: "signature_delta", Signature: block.Thinking.Signature},
+		}, out)
+	case block.RedactedThinking != nil:
+		return s.startBlock(index, sseRedactedThinkingBlock{Type: "redacted_thinking", Data: block.RedactedThinking.Data}, out)
+	case block.WebSearchToolResult != nil:
+		return s.startBlock(index, block.WebSearchToolResult, out)
+	}
+	return nil
+}
+
+// emitCachedToolUse emits the SSE events of the complete tool use block at the index.
+func (s *anthropicSSEWriter) emitCachedToolUse(index int, blockType, id, name string, input map[string]any, out *[]byte) error {
+	if err := s.startBlock(index, sseToolBlock{Type: blockType, ID: id, Name: name, Input: map[string]any{}}, out); err != nil {
+		return err
+	}
+	if len(input) == 0 {
+		return nil
+	}
+	args, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool use input: %w", err)
+	}
+	return s.emitDelta(sseContentBlockDeltaTool{
+		Type: "content_block_delta", Index: index, Delta: sseInputJSONDelta{Type: "input_json_delta", PartialJSON: string(args)},
+	}, out)
+}
@@ -0,0 +1,104 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatCompletionResponseToStream(t *testing.T) {
+	body := `{"id":"chatcmpl-1","object":"chat.completion","created":1700000000,"model":"gpt-4o","choices":[
+{"index":0,"finish_reason":"tool_calls","message":{"role":"assistant","content":"Let me check.","tool_calls":[
+{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}}]}}],
+"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`
+
+	t.Run("with usage", func(t *testing.T) {
+		out, err := ChatCompletionResponseToStream([]byte(body), true)
+		require.NoError(t, err)
+		events := strings.Split(strings.TrimSuffix(string(out), "\n\n"), "\n\n")
+		require.Len(t, events, 4)
+		require.JSONEq(t, `{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1700000000,"model":"gpt-4o","choices":[
+{"index":0,"delta":{"role":"assistant","content":"Let me check.","tool_calls":[
+{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}}]}}]}`,
+			strings.TrimPrefix(events[0], "data: "))
+		require.JSONEq(t, `{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1700000000,"model":"gpt-4o","choices":[
+{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`, strings.TrimPrefix(events[1], "data: "))
+		require.JSONEq(t, `{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1700000000,"model":"gpt-4o","choices":[],
+"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`, strings.TrimPrefix(events[2], "data: "))
+		require.Equal(t, "data: [DONE]", events[3])
+	})
+
+	t.Run("without usage", func(t *testing.T) {
+		out, err := ChatCompletionResponseToStream([]byte(body), false)
+		require.NoError(t, err)
+		events := strings.Split(strings.TrimSuffix(string(out), "\n\n"), "\n\n")
+		require.Len(t, events, 3)
+		require.NotContains(t, string(out), "usage")
+		require.Equal(t, "data: [DONE]", events[2])
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		_, err := ChatCompletionResponseToStream([]byte(`{`), false)
+		require.ErrorContains(t, err, "failed to unmarshal chat completion response")
+	})
+}
+
+func TestMessagesResponseToStream(t *testing.T) {
+	body := `{"id":"msg_1","type":"message","role":"assistant","model":"claude-sonnet-4-5","stop_reason":"tool_use","content":[
+{"type":"thinking","thinking":"The user wants weather.","signature":"sig"},
+{"type":"text","text":"Let me check."},
+{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{"city":"Paris"}}],
+"usage":{"input_tokens":10,"output_tokens":5,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}}`
+
+	out, err := MessagesResponseToStream([]byte(body))
+	require.NoError(t, err)
+	require.Equal(t, `event: message_start
+data: {"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","content":[],"model":"claude-sonnet-4-5","stop_reason":null,"stop_sequence":null,"usage":{"input_tokens":0,"output_tokens":0}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":"","signature":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"The user wants weather."}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"sig"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: content_block_start
+data: {"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Let me check."}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":1}
+
+event: content_block_start
+data: {"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{}}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"{\"city\":\"Paris\"}"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":2}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"tool_use","stop_sequence":null},"usage":{"cache_creation_input_tokens":0,"cache_read_input_tokens":0,"input_tokens":10,"output_tokens":5}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`, string(out))
+
+	_, err = MessagesResponseToStream([]byte(`{`))
+	require.ErrorContains(t, err, "failed to unmarshal messages response")
+}
@@ -85,22 +85,7 @@ func (t *cohereToCohereTranslatorV2Rerank) ResponseBody(_ map[string]string, bod
 		span.RecordResponse(&resp)
 	}
 
-	// Token accounting: rerank only has input tokens; output tokens do not apply.
-	if resp.Meta != nil && resp.Meta.Tokens != nil {
-		var totalTokens uint32
-		if resp.Meta.Tokens.InputTokens != nil {
-			// Cohere uses float; round down to uint32 like embeddings.
-			input := uint32(*resp.Meta.Tokens.InputTokens) //nolint:gosec
-			tokenUsage.SetInputTokens(input)
-			totalTokens += input
-		}
-		if resp.Meta.Tokens.OutputTokens != nil {
-			output := uint32(*resp.Meta.Tokens.OutputTokens) //nolint:gosec
-			tokenUsage.SetOutputTokens(output)
-			totalTokens += output
-		}
-		tokenUsage.SetTotalTokens(totalTokens)
-	}
+	tokenUsage = rerankTokenUsage(&resp)
 
 	// Cohere rerank responses do not echo model; report the effective request model if known.
 	responseModel = t.requestModel
@@ -117,19 +102,44 @@ func (t *cohereToCohereTranslatorV2Rerank) ResponseError(respHeaders map[string]
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to read error body: %w", err)
 		}
-		message := string(buf)
 		// Wrap as a minimal Cohere v2 error JSON for consistency.
-		cohereErr := cohereschema.RerankV2Error{
-			Message: &message,
-		}
-		newBody, err = json.Marshal(cohereErr)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to marshal error body: %w", err)
-		}
-		newHeaders = append(newHeaders,
-			internalapi.Header{contentTypeHeaderName, jsonContentType},
-			internalapi.Header{contentLengthHeaderName, strconv.Itoa(len(newBody))},
-		)
+		return cohereRerankError(string(buf))
+	}
+	return
+}
+
+// rerankTokenUsage returns the token usage reported in meta.tokens of the Cohere v2 rerank response.
+// Rerank only has input tokens in practice; output tokens do not apply.
+func rerankTokenUsage(resp *cohereschema.RerankV2Response) (tokenUsage metrics.TokenUsage) {
+	if resp.Meta == nil || resp.Meta.Tokens == nil {
+		return
+	}
+	var totalTokens uint32
+	if resp.Meta.Tokens.InputTokens != nil {
+		// Cohere uses float; round down to uint32 like embeddings.
+		input := uint32(*resp.Meta.Tokens.InputTokens) //nolint:gosec
+		tokenUsage.SetInputTokens(input)
+		totalTokens += input
+	}
+	if resp.Meta.Tokens.OutputTokens != nil {
+		output := uint32(*resp.Meta.Tokens.OutputTokens) //nolint:gosec
+		tokenUsage.SetOutputTokens(output)
+		totalTokens += output
+	}
+	tokenUsage.SetTotalTokens(totalTokens)
+	return
+}
+
+// cohereRerankError wraps the error message of the backend into a Cohere v2 error body, so that the clients of
+// the rerank endpoint get the same error format regardless of the backend.
+func cohereRerankError(message string) (newHeaders []internalapi.Header, newBody []byte, err error) {
+	newBody, err = json.Marshal(cohereschema.RerankV2Error{Message: &message})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal error body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{contentTypeHeaderName, jsonContentType},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
 	}
 	return
 }
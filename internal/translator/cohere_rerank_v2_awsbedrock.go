@@ -0,0 +1,133 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
+	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewRerankCohereToAWSBedrockTranslator implements [Factory] for Cohere Rerank v2 to AWS Bedrock Rerank translation.
+func NewRerankCohereToAWSBedrockTranslator(modelNameOverride internalapi.ModelNameOverride) CohereRerankTranslator {
+	return &cohereToAWSBedrockRerankTranslator{modelNameOverride: modelNameOverride}
+}
+
+// cohereToAWSBedrockRerankTranslator translates the Cohere Rerank API v2 to the Rerank API of the Amazon Bedrock
+// Agents runtime.
+// https://docs.aws.amazon.com/bedrock/latest/APIReference/API_agent-runtime_Rerank.html
+//
+// The Rerank API identifies the model by its ARN, which includes the region, so the model name (usually the model
+// name override of the backend) must be the model ARN.
+type cohereToAWSBedrockRerankTranslator struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+}
+
+// RequestBody implements [CohereRerankTranslator.RequestBody].
+func (t *cohereToAWSBedrockRerankTranslator) RequestBody(_ []byte, req *cohereschema.RerankV2Request, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	t.requestModel = cmp.Or(t.modelNameOverride, req.Model)
+	if !strings.HasPrefix(t.requestModel, "arn:") {
+		return nil, nil, fmt.Errorf("%w: AWS Bedrock Rerank requires the model ARN, got %q: set the model name override of the backend to the model ARN",
+			internalapi.ErrInvalidRequestBody, t.requestModel)
+	}
+
+	bedrockReq := awsbedrock.RerankRequest{
+		Queries: []awsbedrock.RerankQuery{{Type: "TEXT", TextQuery: awsbedrock.RerankTextData{Text: req.Query}}},
+		Sources: make([]awsbedrock.RerankSource, 0, len(req.Documents)),
+		RerankingConfiguration: awsbedrock.RerankingConfiguration{
+			Type: "BEDROCK_RERANKING_MODEL",
+			BedrockRerankingConfiguration: awsbedrock.BedrockRerankingConfiguration{
+				NumberOfResults:    req.TopN,
+				ModelConfiguration: awsbedrock.BedrockRerankingModelConfiguration{ModelArn: t.requestModel},
+			},
+		},
+	}
+	for _, doc := range req.Documents {
+		bedrockReq.Sources = append(bedrockReq.Sources, awsbedrock.RerankSource{
+			Type: "INLINE",
+			InlineDocumentSource: awsbedrock.RerankInlineDocumentSource{
+				Type:         "TEXT",
+				TextDocument: awsbedrock.RerankTextData{Text: doc},
+			},
+		})
+	}
+
+	newBody, err = json.Marshal(bedrockReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, "/rerank"},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// ResponseHeaders implements [CohereRerankTranslator.ResponseHeaders].
+func (t *cohereToAWSBedrockRerankTranslator) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [CohereRerankTranslator.ResponseBody].
+// The Rerank API does not report token usage nor model, so the request model is returned as the response model.
+func (t *cohereToAWSBedrockRerankTranslator) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.RerankSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	var bedrockResp awsbedrock.RerankResponse
+	if err = json.NewDecoder(body).Decode(&bedrockResp); err != nil {
+		return nil, nil, tokenUsage, t.requestModel, fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+
+	resp := cohereschema.RerankV2Response{Results: make([]*cohereschema.RerankV2Result, 0, len(bedrockResp.Results))}
+	for _, result := range bedrockResp.Results {
+		resp.Results = append(resp.Results, &cohereschema.RerankV2Result{Index: result.Index, RelevanceScore: result.RelevanceScore})
+	}
+
+	newBody, err = json.Marshal(resp)
+	if err != nil {
+		return nil, nil, tokenUsage, t.requestModel, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(&resp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	responseModel = t.requestModel
+	return
+}
+
+// ResponseError implements [CohereRerankTranslator.ResponseError].
+// Translate AWS Bedrock exceptions to the Cohere error type.
+func (t *cohereToAWSBedrockRerankTranslator) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+	}
+	message := string(buf)
+	if isJSON(respHeaders[contentTypeHeaderName]) {
+		var bedrockErr awsbedrock.BedrockException
+		if json.Unmarshal(buf, &bedrockErr) == nil && bedrockErr.Message != "" {
+			message = bedrockErr.Message
+		}
+	}
+	if errorType := respHeaders[awsErrorTypeHeaderName]; errorType != "" {
+		message = errorType + ": " + message
+	}
+	return cohereRerankError(message)
+}
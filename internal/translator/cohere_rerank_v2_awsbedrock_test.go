@@ -0,0 +1,105 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+const testBedrockRerankModelArn = "arn:aws:bedrock:us-west-2::foundation-model/cohere.rerank-v3-5:0"
+
+func TestCohereToAWSBedrockRerankTranslator_RequestBody(t *testing.T) {
+	t.Run("translates request", func(t *testing.T) {
+		tr := NewRerankCohereToAWSBedrockTranslator(testBedrockRerankModelArn)
+		req := &cohereschema.RerankV2Request{Model: "rerank-v3.5", Query: "reset password", Documents: []string{"doc1", "doc2"}, TopN: ptr.To(1)}
+		headers, body, err := tr.RequestBody(nil, req, false)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{{pathHeaderName, "/rerank"}, {contentLengthHeaderName, strconv.Itoa(len(body))}}, headers)
+		require.JSONEq(t, `{
+"queries":[{"type":"TEXT","textQuery":{"text":"reset password"}}],
+"sources":[
+  {"type":"INLINE","inlineDocumentSource":{"type":"TEXT","textDocument":{"text":"doc1"}}},
+  {"type":"INLINE","inlineDocumentSource":{"type":"TEXT","textDocument":{"text":"doc2"}}}
+],
+"rerankingConfiguration":{"type":"BEDROCK_RERANKING_MODEL","bedrockRerankingConfiguration":{
+  "numberOfResults":1,
+  "modelConfiguration":{"modelArn":"arn:aws:bedrock:us-west-2::foundation-model/cohere.rerank-v3-5:0"}
+}}}`, string(body))
+	})
+
+	t.Run("model is not an ARN", func(t *testing.T) {
+		tr := NewRerankCohereToAWSBedrockTranslator("")
+		_, _, err := tr.RequestBody(nil, &cohereschema.RerankV2Request{Model: "cohere.rerank-v3-5:0"}, false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+		require.ErrorContains(t, err, "model ARN")
+	})
+}
+
+func TestCohereToAWSBedrockRerankTranslator_ResponseBody(t *testing.T) {
+	tr := NewRerankCohereToAWSBedrockTranslator(testBedrockRerankModelArn)
+	_, _, err := tr.RequestBody(nil, &cohereschema.RerankV2Request{Query: "q", Documents: []string{"a", "b"}}, false)
+	require.NoError(t, err)
+
+	t.Run("normalizes to cohere", func(t *testing.T) {
+		span := &mockRerankSpanTranslator{}
+		headers, body, tokenUsage, responseModel, err := tr.ResponseBody(nil,
+			strings.NewReader(`{"results":[{"index":1,"relevanceScore":0.9},{"index":0,"relevanceScore":0.1}]}`), true, span)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"results":[{"index":1,"relevance_score":0.9},{"index":0,"relevance_score":0.1}]}`, string(body))
+		require.Len(t, headers, 1)
+		require.Equal(t, tokenUsageFrom(-1, -1, -1, -1, -1, -1), tokenUsage)
+		require.Equal(t, testBedrockRerankModelArn, responseModel)
+		require.True(t, span.recordCalled)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, _, _, _, err := tr.ResponseBody(nil, strings.NewReader("{"), true, nil)
+		require.ErrorContains(t, err, "failed to unmarshal body")
+	})
+}
+
+func TestCohereToAWSBedrockRerankTranslator_ResponseError(t *testing.T) {
+	tr := NewRerankCohereToAWSBedrockTranslator(testBedrockRerankModelArn)
+	for _, tc := range []struct {
+		name       string
+		headers    map[string]string
+		body       string
+		expMessage string
+	}{
+		{
+			name: "bedrock exception",
+			headers: map[string]string{
+				statusHeaderName: "400", contentTypeHeaderName: jsonContentType, awsErrorTypeHeaderName: "ValidationException",
+			},
+			body:       `{"message":"too many sources"}`,
+			expMessage: "ValidationException: too many sources",
+		},
+		{
+			name:       "non-json error",
+			headers:    map[string]string{statusHeaderName: "503", contentTypeHeaderName: "text/plain"},
+			body:       "Service Unavailable",
+			expMessage: "Service Unavailable",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			headers, body, err := tr.ResponseError(tc.headers, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			require.Len(t, headers, 2)
+			var cohereErr cohereschema.RerankV2Error
+			require.NoError(t, json.Unmarshal(body, &cohereErr))
+			require.Equal(t, tc.expMessage, *cohereErr.Message)
+		})
+	}
+}
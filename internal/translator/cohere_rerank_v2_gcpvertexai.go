@@ -0,0 +1,125 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"strconv"
+
+	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// gcpRankPathSuffix is the path suffix of the rank method of the default ranking config.
+const gcpRankPathSuffix = "rankingConfigs/default_ranking_config:rank"
+
+// NewRerankCohereToGCPVertexAITranslator implements [Factory] for Cohere Rerank v2 to GCP Vertex AI ranking API
+// translation.
+func NewRerankCohereToGCPVertexAITranslator(modelNameOverride internalapi.ModelNameOverride) CohereRerankTranslator {
+	return &cohereToGCPVertexAIRerankTranslator{modelNameOverride: modelNameOverride}
+}
+
+// cohereToGCPVertexAIRerankTranslator translates the Cohere Rerank API v2 to the rank method of the Vertex AI
+// ranking API.
+// https://cloud.google.com/generative-ai-app-builder/docs/ranking
+//
+// The documents are sent as records whose IDs are their indexes in the request, so that the scored records can be
+// mapped back to the indexes of the Cohere response.
+type cohereToGCPVertexAIRerankTranslator struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+}
+
+// RequestBody implements [CohereRerankTranslator.RequestBody].
+func (t *cohereToGCPVertexAIRerankTranslator) RequestBody(_ []byte, req *cohereschema.RerankV2Request, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	t.requestModel = cmp.Or(t.modelNameOverride, req.Model)
+	rankReq := gcp.RankRequest{
+		Model:                         t.requestModel,
+		Query:                         req.Query,
+		Records:                       make([]gcp.RankingRecord, 0, len(req.Documents)),
+		IgnoreRecordDetailsInResponse: true,
+	}
+	if req.TopN != nil {
+		rankReq.TopN = *req.TopN
+	}
+	for i, doc := range req.Documents {
+		rankReq.Records = append(rankReq.Records, gcp.RankingRecord{ID: strconv.Itoa(i), Content: doc})
+	}
+
+	newBody, err = json.Marshal(rankReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, gcpRankPathSuffix},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// ResponseHeaders implements [CohereRerankTranslator.ResponseHeaders].
+func (t *cohereToGCPVertexAIRerankTranslator) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [CohereRerankTranslator.ResponseBody].
+// The ranking API does not report token usage nor model, so the request model is returned as the response model.
+func (t *cohereToGCPVertexAIRerankTranslator) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.RerankSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	var rankResp gcp.RankResponse
+	if err = json.NewDecoder(body).Decode(&rankResp); err != nil {
+		return nil, nil, tokenUsage, t.requestModel, fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+
+	resp := cohereschema.RerankV2Response{Results: make([]*cohereschema.RerankV2Result, 0, len(rankResp.Records))}
+	for _, record := range rankResp.Records {
+		index, err := strconv.Atoi(record.ID)
+		if err != nil {
+			return nil, nil, tokenUsage, t.requestModel, fmt.Errorf("unexpected record id %q: %w", record.ID, err)
+		}
+		resp.Results = append(resp.Results, &cohereschema.RerankV2Result{Index: index, RelevanceScore: record.Score})
+	}
+
+	newBody, err = json.Marshal(resp)
+	if err != nil {
+		return nil, nil, tokenUsage, t.requestModel, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(&resp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	responseModel = t.requestModel
+	return
+}
+
+// ResponseError implements [CohereRerankTranslator.ResponseError].
+// Translate GCP errors to the Cohere error type.
+func (t *cohereToGCPVertexAIRerankTranslator) ResponseError(_ map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+	}
+	message := string(buf)
+	var gcpError gcpVertexAIError
+	if json.Unmarshal(buf, &gcpError) == nil && gcpError.Error.Message != "" {
+		message = gcpError.Error.Message
+		if gcpError.Error.Status != "" {
+			message = gcpError.Error.Status + ": " + message
+		}
+	}
+	return cohereRerankError(message)
+}
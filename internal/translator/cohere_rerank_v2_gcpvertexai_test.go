@@ -0,0 +1,65 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestCohereToGCPVertexAIRerankTranslator_RequestBody(t *testing.T) {
+	tr := NewRerankCohereToGCPVertexAITranslator("semantic-ranker-default-004")
+	req := &cohereschema.RerankV2Request{Model: "rerank-v3.5", Query: "reset password", Documents: []string{"doc1", "doc2"}, TopN: ptr.To(1)}
+	headers, body, err := tr.RequestBody(nil, req, false)
+	require.NoError(t, err)
+	require.Equal(t, pathHeaderName, headers[0].Key())
+	require.Equal(t, "rankingConfigs/default_ranking_config:rank", headers[0].Value())
+	require.JSONEq(t, `{
+"model":"semantic-ranker-default-004",
+"query":"reset password",
+"records":[{"id":"0","content":"doc1"},{"id":"1","content":"doc2"}],
+"topN":1,
+"ignoreRecordDetailsInResponse":true
+}`, string(body))
+}
+
+func TestCohereToGCPVertexAIRerankTranslator_ResponseBody(t *testing.T) {
+	tr := NewRerankCohereToGCPVertexAITranslator("semantic-ranker-default-004")
+	_, _, err := tr.RequestBody(nil, &cohereschema.RerankV2Request{Query: "q", Documents: []string{"a", "b"}}, false)
+	require.NoError(t, err)
+
+	t.Run("normalizes to cohere", func(t *testing.T) {
+		span := &mockRerankSpanTranslator{}
+		_, body, _, responseModel, err := tr.ResponseBody(nil,
+			strings.NewReader(`{"records":[{"id":"1","score":0.9},{"id":"0","score":0.1}]}`), true, span)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"results":[{"index":1,"relevance_score":0.9},{"index":0,"relevance_score":0.1}]}`, string(body))
+		require.Equal(t, "semantic-ranker-default-004", responseModel)
+		require.True(t, span.recordCalled)
+	})
+
+	t.Run("unexpected record id", func(t *testing.T) {
+		_, _, _, _, err := tr.ResponseBody(nil, strings.NewReader(`{"records":[{"id":"x","score":0.9}]}`), true, nil)
+		require.ErrorContains(t, err, `unexpected record id "x"`)
+	})
+}
+
+func TestCohereToGCPVertexAIRerankTranslator_ResponseError(t *testing.T) {
+	tr := NewRerankCohereToGCPVertexAITranslator("")
+	headers, body, err := tr.ResponseError(map[string]string{statusHeaderName: "403"},
+		strings.NewReader(`{"error":{"code":403,"message":"Permission denied","status":"PERMISSION_DENIED"}}`))
+	require.NoError(t, err)
+	require.Len(t, headers, 2)
+	var cohereErr cohereschema.RerankV2Error
+	require.NoError(t, json.Unmarshal(body, &cohereErr))
+	require.Equal(t, "PERMISSION_DENIED: Permission denied", *cohereErr.Message)
+}
@@ -0,0 +1,182 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+
+	"github.com/tidwall/gjson"
+
+	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewRerankCohereToOpenAITranslator implements [Factory] for Cohere Rerank v2 to the OpenAI-compatible rerank
+// endpoint translation.
+func NewRerankCohereToOpenAITranslator(prefix string, modelNameOverride internalapi.ModelNameOverride) CohereRerankTranslator {
+	return &cohereToOpenAIRerankTranslator{modelNameOverride: modelNameOverride, path: path.Join("/", prefix, "rerank")} // e.g., /v1/rerank
+}
+
+// cohereToOpenAIRerankTranslator translates the Cohere Rerank API v2 to the rerank endpoint of the OpenAI-compatible
+// servers, such as the Jina-style /v1/rerank of vLLM and the /rerank of Text Embeddings Inference (TEI).
+//
+// The request has both the "documents" field of vLLM and the "texts" field of TEI since each server ignores the
+// field of the other. The response is either the Jina-style object of vLLM or the array of the scores of TEI.
+type cohereToOpenAIRerankTranslator struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+	// topN is the top_n of the request, which TEI does not support so that the results are truncated here.
+	topN *int
+	// The path of the rerank endpoint to be used for the request. It is prefixed with the OpenAI path prefix.
+	path string
+}
+
+// openAIRerankRequest is the request body of the rerank endpoint of the OpenAI-compatible servers.
+type openAIRerankRequest struct {
+	Model     string   `json:"model,omitempty"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	// Texts is the documents field of the rerank endpoint of TEI.
+	Texts []string `json:"texts"`
+	TopN  *int     `json:"top_n,omitempty"`
+}
+
+// openAIRerankResponse is the Jina-style response body of the rerank endpoint of vLLM.
+type openAIRerankResponse struct {
+	ID      *string              `json:"id,omitempty"`
+	Model   string               `json:"model,omitempty"`
+	Results []openAIRerankResult `json:"results"`
+	Usage   *openAIRerankUsage   `json:"usage,omitempty"`
+}
+
+// openAIRerankResult is a result of [openAIRerankResponse].
+type openAIRerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+// openAIRerankUsage is the usage of [openAIRerankResponse]. Some servers only report the total tokens.
+type openAIRerankUsage struct {
+	PromptTokens int `json:"prompt_tokens,omitempty"`
+	TotalTokens  int `json:"total_tokens,omitempty"`
+}
+
+// teiRerankResult is an element of the response body of the rerank endpoint of TEI.
+type teiRerankResult struct {
+	Index int     `json:"index"`
+	Score float64 `json:"score"`
+}
+
+// RequestBody implements [CohereRerankTranslator.RequestBody].
+func (t *cohereToOpenAIRerankTranslator) RequestBody(_ []byte, req *cohereschema.RerankV2Request, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	t.requestModel = cmp.Or(t.modelNameOverride, req.Model)
+	t.topN = req.TopN
+	newBody, err = json.Marshal(openAIRerankRequest{
+		Model:     t.requestModel,
+		Query:     req.Query,
+		Documents: req.Documents,
+		Texts:     req.Documents,
+		TopN:      req.TopN,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, t.path},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// ResponseHeaders implements [CohereRerankTranslator.ResponseHeaders].
+func (t *cohereToOpenAIRerankTranslator) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [CohereRerankTranslator.ResponseBody].
+// The usage of vLLM is reported as the input tokens, while TEI does not report usage.
+func (t *cohereToOpenAIRerankTranslator) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.RerankSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, tokenUsage, t.requestModel, fmt.Errorf("failed to read body: %w", err)
+	}
+
+	responseModel = t.requestModel
+	var resp cohereschema.RerankV2Response
+	if trimmed := bytes.TrimSpace(buf); len(trimmed) > 0 && trimmed[0] == '[' {
+		var teiResp []teiRerankResult
+		if err = json.Unmarshal(buf, &teiResp); err != nil {
+			return nil, nil, tokenUsage, t.requestModel, fmt.Errorf("failed to unmarshal body: %w", err)
+		}
+		// TEI returns all the texts ordered by the score in descending order.
+		if t.topN != nil && *t.topN >= 0 && *t.topN < len(teiResp) {
+			teiResp = teiResp[:*t.topN]
+		}
+		resp.Results = make([]*cohereschema.RerankV2Result, 0, len(teiResp))
+		for _, result := range teiResp {
+			resp.Results = append(resp.Results, &cohereschema.RerankV2Result{Index: result.Index, RelevanceScore: result.Score})
+		}
+	} else {
+		var openAIResp openAIRerankResponse
+		if err = json.Unmarshal(buf, &openAIResp); err != nil {
+			return nil, nil, tokenUsage, t.requestModel, fmt.Errorf("failed to unmarshal body: %w", err)
+		}
+		resp.ID = openAIResp.ID
+		resp.Results = make([]*cohereschema.RerankV2Result, 0, len(openAIResp.Results))
+		for _, result := range openAIResp.Results {
+			resp.Results = append(resp.Results, &cohereschema.RerankV2Result{Index: result.Index, RelevanceScore: result.RelevanceScore})
+		}
+		if usage := openAIResp.Usage; usage != nil {
+			inputTokens := float64(cmp.Or(usage.PromptTokens, usage.TotalTokens))
+			resp.Meta = &cohereschema.RerankV2Meta{Tokens: &cohereschema.RerankV2Tokens{InputTokens: &inputTokens}}
+		}
+		responseModel = cmp.Or(openAIResp.Model, t.requestModel)
+	}
+
+	newBody, err = json.Marshal(resp)
+	if err != nil {
+		return nil, nil, tokenUsage, t.requestModel, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(&resp)
+	}
+	tokenUsage = rerankTokenUsage(&resp)
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// ResponseError implements [CohereRerankTranslator.ResponseError].
+// The OpenAI-style, vLLM-style and TEI-style error messages are translated to the Cohere error type.
+func (t *cohereToOpenAIRerankTranslator) ResponseError(_ map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+	}
+	message := string(buf)
+	if gjson.ValidBytes(buf) {
+		for _, p := range []string{"message", "error.message", "error"} {
+			if v := gjson.GetBytes(buf, p); v.Type == gjson.String {
+				message = v.String()
+				break
+			}
+		}
+	}
+	return cohereRerankError(message)
+}
@@ -0,0 +1,99 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestCohereToOpenAIRerankTranslator_RequestBody(t *testing.T) {
+	for _, tc := range []struct {
+		prefix  string
+		expPath string
+	}{
+		{prefix: "v1", expPath: "/v1/rerank"},
+		{prefix: "", expPath: "/rerank"},
+	} {
+		t.Run(tc.expPath, func(t *testing.T) {
+			tr := NewRerankCohereToOpenAITranslator(tc.prefix, "BAAI/bge-reranker-v2-m3")
+			req := &cohereschema.RerankV2Request{Model: "rerank-v3.5", Query: "q", Documents: []string{"a", "b"}, TopN: ptr.To(1)}
+			headers, body, err := tr.RequestBody(nil, req, false)
+			require.NoError(t, err)
+			require.Equal(t, tc.expPath, headers[0].Value())
+			require.JSONEq(t, `{"model":"BAAI/bge-reranker-v2-m3","query":"q","documents":["a","b"],"texts":["a","b"],"top_n":1}`, string(body))
+		})
+	}
+}
+
+func TestCohereToOpenAIRerankTranslator_ResponseBody(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		topN             *int
+		body             string
+		expBody          string
+		expInputTokens   int32
+		expResponseModel string
+	}{
+		{
+			name:             "vllm",
+			body:             `{"id":"rerank-1","model":"BAAI/bge-reranker-v2-m3","usage":{"total_tokens":30},"results":[{"index":1,"document":{"text":"b"},"relevance_score":0.9}]}`,
+			expBody:          `{"id":"rerank-1","results":[{"index":1,"relevance_score":0.9}],"meta":{"tokens":{"input_tokens":30}}}`,
+			expInputTokens:   30,
+			expResponseModel: "BAAI/bge-reranker-v2-m3",
+		},
+		{
+			name:             "tei truncated to top_n",
+			topN:             ptr.To(1),
+			body:             `[{"index":1,"score":0.9},{"index":0,"score":0.1}]`,
+			expBody:          `{"results":[{"index":1,"relevance_score":0.9}]}`,
+			expInputTokens:   -1,
+			expResponseModel: "rerank-v3.5",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := NewRerankCohereToOpenAITranslator("v1", "")
+			_, _, err := tr.RequestBody(nil, &cohereschema.RerankV2Request{Model: "rerank-v3.5", Query: "q", Documents: []string{"a", "b"}, TopN: tc.topN}, false)
+			require.NoError(t, err)
+
+			span := &mockRerankSpanTranslator{}
+			_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(tc.body), true, span)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expBody, string(body))
+			require.Equal(t, tc.expResponseModel, responseModel)
+			require.Equal(t, tokenUsageFrom(tc.expInputTokens, -1, -1, -1, tc.expInputTokens, -1), tokenUsage)
+			require.True(t, span.recordCalled)
+		})
+	}
+}
+
+func TestCohereToOpenAIRerankTranslator_ResponseError(t *testing.T) {
+	tr := NewRerankCohereToOpenAITranslator("v1", "")
+	for _, tc := range []struct {
+		name       string
+		body       string
+		expMessage string
+	}{
+		{name: "vllm", body: `{"object":"error","message":"model not found","code":404}`, expMessage: "model not found"},
+		{name: "openai", body: `{"error":{"message":"bad request"}}`, expMessage: "bad request"},
+		{name: "tei", body: `{"error":"batch size too large","error_type":"Validation"}`, expMessage: "batch size too large"},
+		{name: "plain text", body: "Service Unavailable", expMessage: "Service Unavailable"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, body, err := tr.ResponseError(map[string]string{statusHeaderName: "400"}, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			var cohereErr cohereschema.RerankV2Error
+			require.NoError(t, json.Unmarshal(body, &cohereErr))
+			require.Equal(t, tc.expMessage, *cohereErr.Message)
+		})
+	}
+}
@@ -0,0 +1,833 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/protocol/eventstream"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// defaultConverseMaxTokens is used as Anthropic max_tokens when the Converse request does not set
+// inferenceConfig.maxTokens, since the Messages API requires it.
+const defaultConverseMaxTokens = 4096
+
+// NewConverseToAnthropicTranslator implements [AWSBedrockConverseTranslator] for AWS Bedrock Converse
+// to native Anthropic Messages translation.
+func NewConverseToAnthropicTranslator(version string, modelNameOverride internalapi.ModelNameOverride) AWSBedrockConverseTranslator {
+	return &converseToAnthropicMessagesTranslator{inner: NewAnthropicToAnthropicTranslator(version, modelNameOverride)}
+}
+
+// NewConverseToGCPAnthropicTranslator implements [AWSBedrockConverseTranslator] for AWS Bedrock Converse
+// to Anthropic on GCP Vertex AI translation.
+func NewConverseToGCPAnthropicTranslator(version string, modelNameOverride internalapi.ModelNameOverride) AWSBedrockConverseTranslator {
+	return &converseToAnthropicMessagesTranslator{inner: NewAnthropicToGCPAnthropicTranslator(version, modelNameOverride)}
+}
+
+// NewConverseToAWSAnthropicTranslator implements [AWSBedrockConverseTranslator] for AWS Bedrock Converse
+// to Anthropic on AWS Bedrock InvokeModel translation.
+func NewConverseToAWSAnthropicTranslator(version string, modelNameOverride internalapi.ModelNameOverride) AWSBedrockConverseTranslator {
+	return &converseToAnthropicMessagesTranslator{inner: NewAnthropicToAWSAnthropicTranslator(version, modelNameOverride)}
+}
+
+// NewConverseToOpenAITranslator implements [AWSBedrockConverseTranslator] for AWS Bedrock Converse
+// to OpenAI Chat Completions translation.
+func NewConverseToOpenAITranslator(prefix string, modelNameOverride internalapi.ModelNameOverride) AWSBedrockConverseTranslator {
+	return &converseToAnthropicMessagesTranslator{inner: NewAnthropicToChatCompletionOpenAITranslator(prefix, modelNameOverride)}
+}
+
+// NewConverseToGCPVertexAITranslator implements [AWSBedrockConverseTranslator] for AWS Bedrock Converse
+// to GCP Vertex AI Gemini translation.
+func NewConverseToGCPVertexAITranslator(modelNameOverride internalapi.ModelNameOverride) AWSBedrockConverseTranslator {
+	return &converseToAnthropicMessagesTranslator{inner: NewAnthropicToGCPVertexAITranslator(modelNameOverride)}
+}
+
+// converseToAnthropicMessagesTranslator translates the AWS Bedrock Converse API to backends that do not speak it
+// natively. The same as [generateContentToAnthropicMessagesTranslator], the request is first converted to an
+// Anthropic Messages request and the backend specific translation is delegated to an [AnthropicMessagesTranslator].
+// The Anthropic Messages responses produced by the inner translator are then converted back to the Converse format,
+// and the stream is encoded as AWS eventstream messages so that the AWS SDKs can consume it.
+type converseToAnthropicMessagesTranslator struct {
+	inner  AnthropicMessagesTranslator
+	stream bool
+	// startTime is used to report the latency in the metrics of the response, which is required by Converse.
+	startTime time.Time
+	// streamState holds the state of the streamed event conversion.
+	streamState *anthropicToConverseStreamState
+}
+
+// RequestBody implements [AWSBedrockConverseTranslator.RequestBody].
+func (c *converseToAnthropicMessagesTranslator) RequestBody(_ []byte, req *awsbedrock.ConverseInput, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	c.stream = req.Stream
+	c.startTime = time.Now()
+	anthropicReq, err := converseToAnthropicMessagesRequest(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.stream {
+		c.streamState = &anthropicToConverseStreamState{startTime: c.startTime}
+	}
+	original, err := json.Marshal(anthropicReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal Anthropic request: %w", err)
+	}
+	// The body always has to be mutated since the backend does not understand the Converse format.
+	return c.inner.RequestBody(original, anthropicReq, true)
+}
+
+// ResponseHeaders implements [AWSBedrockConverseTranslator.ResponseHeaders].
+func (c *converseToAnthropicMessagesTranslator) ResponseHeaders(headers map[string]string) (newHeaders []internalapi.Header, err error) {
+	newHeaders, err = c.inner.ResponseHeaders(headers)
+	if err != nil {
+		return nil, err
+	}
+	if c.stream {
+		// The inner translator may have set the content type of the Anthropic server-sent events.
+		newHeaders = slicesDeleteHeader(newHeaders, contentTypeHeaderName)
+		newHeaders = append(newHeaders, internalapi.Header{contentTypeHeaderName, awsEventStreamContentType})
+	}
+	return newHeaders, nil
+}
+
+// ResponseBody implements [AWSBedrockConverseTranslator.ResponseBody].
+func (c *converseToAnthropicMessagesTranslator) ResponseBody(respHeaders map[string]string, body io.Reader, endOfStream bool, span tracingapi.ConverseSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel string, err error,
+) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to read body: %w", err)
+	}
+	// The inner translator produces the Anthropic Messages body. A nil body means it is passed through as is.
+	_, anthropicBody, tokenUsage, responseModel, err := c.inner.ResponseBody(respHeaders, bytes.NewReader(raw), endOfStream, nil)
+	if err != nil {
+		return nil, nil, tokenUsage, responseModel, err
+	}
+	if anthropicBody == nil {
+		anthropicBody = raw
+	}
+
+	if c.stream {
+		newBody, err = c.streamState.convert(anthropicBody, endOfStream, span)
+		if err != nil {
+			return nil, nil, tokenUsage, responseModel, err
+		}
+		return nil, newBody, tokenUsage, responseModel, nil
+	}
+
+	anthropicResp := &anthropic.MessagesResponse{}
+	if err = json.Unmarshal(anthropicBody, anthropicResp); err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to unmarshal Anthropic response: %w", err)
+	}
+	resp := anthropicToConverseResponse(anthropicResp, time.Since(c.startTime))
+	if span != nil {
+		span.RecordResponse(resp)
+	}
+	newBody, err = json.Marshal(resp)
+	if err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// ResponseError implements [AWSBedrockConverseTranslator.ResponseError].
+// The inner translator normalizes the backend error to the Anthropic error format, which is then
+// converted to the Converse error format.
+func (c *converseToAnthropicMessagesTranslator) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+	}
+	_, anthropicBody, err := c.inner.ResponseError(respHeaders, bytes.NewReader(raw))
+	if err != nil {
+		return nil, nil, err
+	}
+	if anthropicBody == nil {
+		anthropicBody = raw
+	}
+	message := string(anthropicBody)
+	var anthropicError anthropic.ErrorResponse
+	if json.Unmarshal(anthropicBody, &anthropicError) == nil && anthropicError.Error.Message != "" {
+		message = anthropicError.Error.Message
+	}
+	return converseErrorResponse(respHeaders[statusHeaderName], message)
+}
+
+// slicesDeleteHeader returns the headers without the ones of the given name.
+func slicesDeleteHeader(headers []internalapi.Header, name string) []internalapi.Header {
+	out := headers[:0]
+	for _, h := range headers {
+		if h.Key() != name {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// converseToAnthropicMessagesRequest converts an AWS Bedrock Converse request to an Anthropic Messages request.
+func converseToAnthropicMessagesRequest(req *awsbedrock.ConverseInput) (*anthropic.MessagesRequest, error) {
+	if req.GuardrailConfig != nil {
+		return nil, fmt.Errorf("%w: guardrailConfig is not supported by this backend", internalapi.ErrInvalidRequestBody)
+	}
+	out := &anthropic.MessagesRequest{
+		Model:     req.ModelID,
+		MaxTokens: defaultConverseMaxTokens,
+		Stream:    req.Stream,
+	}
+	var maxTokens int64
+	if config := req.InferenceConfig; config != nil {
+		out.Temperature = config.Temperature
+		out.TopP = config.TopP
+		out.StopSequences = config.StopSequences
+		if config.MaxTokens != nil {
+			maxTokens = *config.MaxTokens
+			out.MaxTokens = float64(maxTokens)
+		}
+	}
+	if err := applyConverseAdditionalModelRequestFields(out, req.AdditionalModelRequestFields, maxTokens); err != nil {
+		return nil, err
+	}
+
+	if len(req.System) > 0 {
+		system := &anthropic.SystemPrompt{}
+		for _, block := range req.System {
+			switch {
+			case block == nil:
+			case block.Text != nil:
+				system.Texts = append(system.Texts, anthropic.TextBlockParam{Type: "text", Text: *block.Text})
+			case block.GuardContent != nil && block.GuardContent.Text != nil && block.GuardContent.Text.Text != nil:
+				// Without a guardrail configuration, the guarded content is the same as a text block.
+				system.Texts = append(system.Texts, anthropic.TextBlockParam{Type: "text", Text: *block.GuardContent.Text.Text})
+			case block.CachePoint != nil:
+				if n := len(system.Texts); n > 0 {
+					system.Texts[n-1].CacheControl = converseCachePointToAnthropic()
+				}
+			}
+		}
+		if len(system.Texts) > 0 {
+			out.System = system
+		}
+	}
+
+	out.Messages = make([]anthropic.MessageParam, 0, len(req.Messages))
+	for i, msg := range req.Messages {
+		if msg == nil {
+			continue
+		}
+		converted, err := converseMessageToAnthropic(i, msg)
+		if err != nil {
+			return nil, err
+		}
+		out.Messages = append(out.Messages, *converted)
+	}
+
+	if req.ToolConfig != nil {
+		var err error
+		if out.Tools, err = converseToolsToAnthropic(req.ToolConfig.Tools); err != nil {
+			return nil, err
+		}
+		out.ToolChoice = converseToolChoiceToAnthropic(req.ToolConfig.ToolChoice)
+	}
+	return out, nil
+}
+
+// applyConverseAdditionalModelRequestFields applies the model specific fields of the Converse request that have
+// an equivalent in the Messages API, which are the same as the fields of the Anthropic models on Bedrock.
+// Other fields have no equivalent and are dropped.
+func applyConverseAdditionalModelRequestFields(out *anthropic.MessagesRequest, fields map[string]any, maxTokens int64) error {
+	if topK, ok := fields["top_k"].(float64); ok {
+		out.TopK = ptr.To(int(topK))
+	}
+	thinking, ok := fields["thinking"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	switch thinking["type"] {
+	case "enabled":
+		budget, _ := thinking["budget_tokens"].(float64)
+		if budget < minThinkingBudgetTokens {
+			return fmt.Errorf("%w: thinking.budget_tokens must be at least %d", internalapi.ErrInvalidRequestBody, minThinkingBudgetTokens)
+		}
+		applyThinkingBudget(out, int64(budget), maxTokens)
+	case "disabled":
+		out.Thinking = &anthropic.Thinking{Disabled: &anthropic.ThinkingDisabled{Type: "disabled"}}
+	case "adaptive":
+		out.Thinking = &anthropic.Thinking{Adaptive: &anthropic.ThinkingAdaptive{Type: "adaptive"}}
+	default:
+		return fmt.Errorf("%w: unsupported thinking type %v", internalapi.ErrInvalidRequestBody, thinking["type"])
+	}
+	return nil
+}
+
+// converseCachePointToAnthropic returns the Anthropic cache control equivalent to a Converse cache point.
+// Converse places the cache point after the cached content while Anthropic marks the last cached block.
+func converseCachePointToAnthropic() *anthropic.CacheControl {
+	return &anthropic.CacheControl{Ephemeral: &anthropic.CacheControlEphemeral{Type: "ephemeral"}}
+}
+
+// converseMessageToAnthropic converts a single Converse message to an Anthropic message.
+func converseMessageToAnthropic(index int, msg *awsbedrock.Message) (*anthropic.MessageParam, error) {
+	var role anthropic.MessageRole
+	switch msg.Role {
+	case awsbedrock.ConversationRoleUser:
+		role = anthropic.MessageRoleUser
+	case awsbedrock.ConversationRoleAssistant:
+		role = anthropic.MessageRoleAssistant
+	default:
+		return nil, fmt.Errorf("%w: unsupported role %q at messages[%d]", internalapi.ErrInvalidRequestBody, msg.Role, index)
+	}
+	blocks := make([]anthropic.ContentBlockParam, 0, len(msg.Content))
+	for j, block := range msg.Content {
+		switch {
+		case block == nil:
+		case block.Text != nil:
+			blocks = append(blocks, anthropicTextBlock(*block.Text))
+		case block.Image != nil:
+			image, err := converseImageToAnthropic(block.Image)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, anthropic.ContentBlockParam{Image: image})
+		case block.Document != nil:
+			document, err := converseDocumentToAnthropic(block.Document)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, anthropic.ContentBlockParam{Document: document})
+		case block.ToolUse != nil:
+			input := block.ToolUse.Input
+			if input == nil {
+				input = map[string]any{}
+			}
+			blocks = append(blocks, anthropic.ContentBlockParam{ToolUse: &anthropic.ToolUseBlockParam{
+				Type: "tool_use", ID: block.ToolUse.ToolUseID, Name: block.ToolUse.Name, Input: input,
+			}})
+		case block.ToolResult != nil:
+			result, err := converseToolResultToAnthropic(block.ToolResult)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, anthropic.ContentBlockParam{ToolResult: result})
+		case block.ReasoningContent != nil:
+			if rt := block.ReasoningContent.ReasoningText; rt != nil {
+				blocks = append(blocks, anthropic.ContentBlockParam{Thinking: &anthropic.ThinkingBlockParam{
+					Type: "thinking", Thinking: rt.Text, Signature: rt.Signature,
+				}})
+			} else if rc := block.ReasoningContent.RedactedContent; len(rc) > 0 {
+				blocks = append(blocks, anthropic.ContentBlockParam{RedactedThinking: &anthropic.RedactedThinkingBlockParam{
+					Type: "redacted_thinking", Data: base64.StdEncoding.EncodeToString(rc),
+				}})
+			}
+		case block.CachePoint != nil:
+			if n := len(blocks); n > 0 {
+				setAnthropicCacheControl(&blocks[n-1], converseCachePointToAnthropic())
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported content block at messages[%d].content[%d]", internalapi.ErrInvalidRequestBody, index, j)
+		}
+	}
+	return &anthropic.MessageParam{Role: role, Content: anthropic.MessageContent{Array: blocks}}, nil
+}
+
+// setAnthropicCacheControl sets the cache control of the content block if the block supports it.
+func setAnthropicCacheControl(block *anthropic.ContentBlockParam, cc *anthropic.CacheControl) {
+	switch {
+	case block.Text != nil:
+		block.Text.CacheControl = cc
+	case block.Image != nil:
+		block.Image.CacheControl = cc
+	case block.Document != nil:
+		block.Document.CacheControl = cc
+	case block.ToolUse != nil:
+		block.ToolUse.CacheControl = cc
+	case block.ToolResult != nil:
+		block.ToolResult.CacheControl = cc
+	}
+}
+
+// converseImageToAnthropic converts a Converse image block to an Anthropic base64 image block.
+func converseImageToAnthropic(image *awsbedrock.ImageBlock) (*anthropic.ImageBlockParam, error) {
+	var mediaType string
+	switch image.Format {
+	case "png":
+		mediaType = mimeTypeImagePNG
+	case "jpeg":
+		mediaType = mimeTypeImageJPEG
+	case "gif":
+		mediaType = mimeTypeImageGIF
+	case "webp":
+		mediaType = mimeTypeImageWEBP
+	default:
+		return nil, fmt.Errorf("%w: unsupported image format %q", internalapi.ErrInvalidRequestBody, image.Format)
+	}
+	return &anthropic.ImageBlockParam{Type: "image", Source: anthropic.ImageSource{
+		Base64: &anthropic.Base64ImageSource{Type: "base64", MediaType: mediaType, Data: base64.StdEncoding.EncodeToString(image.Source.Bytes)},
+	}}, nil
+}
+
+// converseDocumentToAnthropic converts a Converse document block to an Anthropic document block.
+// Only PDF and text documents have an Anthropic equivalent.
+func converseDocumentToAnthropic(document *awsbedrock.DocumentBlock) (*anthropic.DocumentBlockParam, error) {
+	out := &anthropic.DocumentBlockParam{Type: "document", Title: document.Name}
+	switch document.Format {
+	case "pdf":
+		out.Source.Base64PDF = &anthropic.Base64PDFSource{
+			Type: "base64", MediaType: "application/pdf", Data: base64.StdEncoding.EncodeToString(document.Source.Bytes),
+		}
+	case "txt", "md":
+		out.Source.PlainText = &anthropic.PlainTextSource{Type: "text", MediaType: mimeTypeTextPlain, Data: string(document.Source.Bytes)}
+	default:
+		return nil, fmt.Errorf("%w: unsupported document format %q", internalapi.ErrInvalidRequestBody, document.Format)
+	}
+	return out, nil
+}
+
+// converseToolResultToAnthropic converts a Converse tool result block to an Anthropic tool_result block.
+// JSON results are passed as text since Anthropic tool results do not have a JSON content type.
+func converseToolResultToAnthropic(result *awsbedrock.ToolResultBlock) (*anthropic.ToolResultBlockParam, error) {
+	out := &anthropic.ToolResultBlockParam{
+		Type:    "tool_result",
+		IsError: result.Status != nil && *result.Status == "error",
+		Content: &anthropic.ToolResultContent{},
+	}
+	if result.ToolUseID != nil {
+		out.ToolUseID = *result.ToolUseID
+	}
+	for _, item := range result.Content {
+		switch {
+		case item == nil:
+		case item.Text != nil:
+			out.Content.Array = append(out.Content.Array, anthropic.ToolResultContentItem{Text: &anthropic.TextBlockParam{Type: "text", Text: *item.Text}})
+		case item.JSON != nil:
+			out.Content.Array = append(out.Content.Array, anthropic.ToolResultContentItem{Text: &anthropic.TextBlockParam{Type: "text", Text: *item.JSON}})
+		case item.Image != nil:
+			image, err := converseImageToAnthropic(item.Image)
+			if err != nil {
+				return nil, err
+			}
+			out.Content.Array = append(out.Content.Array, anthropic.ToolResultContentItem{Image: image})
+		case item.Document != nil:
+			document, err := converseDocumentToAnthropic(item.Document)
+			if err != nil {
+				return nil, err
+			}
+			out.Content.Array = append(out.Content.Array, anthropic.ToolResultContentItem{Document: document})
+		}
+	}
+	return out, nil
+}
+
+// converseToolsToAnthropic converts the Converse tool specifications to Anthropic custom tools.
+func converseToolsToAnthropic(tools []*awsbedrock.Tool) ([]anthropic.ToolUnion, error) {
+	var out []anthropic.ToolUnion
+	for i, tool := range tools {
+		switch {
+		case tool == nil:
+		case tool.ToolSpec != nil:
+			spec := tool.ToolSpec
+			if spec.Name == nil {
+				return nil, fmt.Errorf("%w: missing name of tool at index %d", internalapi.ErrInvalidRequestBody, i)
+			}
+			converted := &anthropic.Tool{Type: "custom", Name: *spec.Name, InputSchema: anthropic.ToolInputSchema{Type: "object"}}
+			if spec.Description != nil {
+				converted.Description = *spec.Description
+			}
+			if spec.InputSchema != nil {
+				schema, err := converseToolInputSchemaToAnthropic(spec.InputSchema.JSON)
+				if err != nil {
+					return nil, fmt.Errorf("%w: invalid input schema of tool %s: %w", internalapi.ErrInvalidRequestBody, *spec.Name, err)
+				}
+				converted.InputSchema = schema
+			}
+			out = append(out, anthropic.ToolUnion{Tool: converted})
+		case tool.CachePoint != nil:
+			if n := len(out); n > 0 && out[n-1].Tool != nil {
+				out[n-1].Tool.CacheControl = converseCachePointToAnthropic()
+			}
+		}
+	}
+	return out, nil
+}
+
+// converseToolInputSchemaToAnthropic converts the JSON schema of the tool to the Anthropic input schema.
+func converseToolInputSchemaToAnthropic(schema any) (anthropic.ToolInputSchema, error) {
+	out := anthropic.ToolInputSchema{Type: "object"}
+	if schema == nil {
+		return out, nil
+	}
+	marshaled, err := json.Marshal(schema)
+	if err != nil {
+		return out, err
+	}
+	if err = json.Unmarshal(marshaled, &out); err != nil {
+		return out, err
+	}
+	out.Type = "object"
+	return out, nil
+}
+
+// converseToolChoiceToAnthropic converts the Converse tool choice to the Anthropic tool choice.
+func converseToolChoiceToAnthropic(choice *awsbedrock.ToolChoice) *anthropic.ToolChoice {
+	switch {
+	case choice == nil:
+		return nil
+	case choice.Tool != nil && choice.Tool.Name != nil:
+		return &anthropic.ToolChoice{Tool: &anthropic.ToolChoiceTool{Type: "tool", Name: *choice.Tool.Name}}
+	case choice.Any != nil:
+		return &anthropic.ToolChoice{Any: &anthropic.ToolChoiceAny{Type: "any"}}
+	case choice.Auto != nil:
+		return &anthropic.ToolChoice{Auto: &anthropic.ToolChoiceAuto{Type: "auto"}}
+	default:
+		return nil
+	}
+}
+
+// anthropicToConverseResponse converts an Anthropic Messages response to a Converse response.
+func anthropicToConverseResponse(resp *anthropic.MessagesResponse, latency time.Duration) *awsbedrock.ConverseResponse {
+	out := &awsbedrock.ConverseResponse{
+		Metrics: &awsbedrock.ConverseMetrics{LatencyMs: ptr.To(latency.Milliseconds())},
+		Output: &awsbedrock.ConverseOutput{Message: awsbedrock.Message{
+			Role:    awsbedrock.ConversationRoleAssistant,
+			Content: make([]*awsbedrock.ContentBlock, 0, len(resp.Content)),
+		}},
+		StopReason: ptr.To(anthropicStopReasonToConverse(resp.StopReason)),
+		Usage:      &awsbedrock.TokenUsage{},
+	}
+	for i := range resp.Content {
+		if block := anthropicContentBlockToConverse(&resp.Content[i]); block != nil {
+			out.Output.Message.Content = append(out.Output.Message.Content, block)
+		}
+	}
+	if resp.Usage != nil {
+		out.Usage = anthropicUsageToConverse(resp.Usage)
+	}
+	return out
+}
+
+// anthropicContentBlockToConverse converts an Anthropic response content block to a Converse content block.
+// Blocks without a Converse equivalent, such as server tool results, are skipped.
+func anthropicContentBlockToConverse(block *anthropic.MessagesContentBlock) *awsbedrock.ContentBlock {
+	switch {
+	case block.Text != nil:
+		return &awsbedrock.ContentBlock{Text: ptr.To(block.Text.Text)}
+	case block.Thinking != nil:
+		return &awsbedrock.ContentBlock{ReasoningContent: &awsbedrock.ReasoningContentBlock{
+			ReasoningText: &awsbedrock.ReasoningTextBlock{Text: block.Thinking.Thinking, Signature: block.Thinking.Signature},
+		}}
+	case block.RedactedThinking != nil:
+		data, err := base64.StdEncoding.DecodeString(block.RedactedThinking.Data)
+		if err != nil {
+			return nil
+		}
+		return &awsbedrock.ContentBlock{ReasoningContent: &awsbedrock.ReasoningContentBlock{RedactedContent: data}}
+	case block.Tool != nil:
+		input := block.Tool.Input
+		if input == nil {
+			input = map[string]any{}
+		}
+		return &awsbedrock.ContentBlock{ToolUse: &awsbedrock.ToolUseBlock{ToolUseID: block.Tool.ID, Name: block.Tool.Name, Input: input}}
+	default:
+		return nil
+	}
+}
+
+// anthropicStopReasonToConverse converts the Anthropic stop reason to the Converse stop reason.
+func anthropicStopReasonToConverse(reason *anthropic.StopReason) string {
+	if reason == nil {
+		return awsbedrock.StopReasonEndTurn
+	}
+	switch *reason {
+	case anthropic.StopReasonToolUse:
+		return awsbedrock.StopReasonToolUse
+	case anthropic.StopReasonMaxTokens, anthropic.StopReasonModelContextWindowExceeded:
+		return awsbedrock.StopReasonMaxTokens
+	case anthropic.StopReasonStopSequence:
+		return awsbedrock.StopReasonStopSequence
+	case anthropic.StopReasonRefusal:
+		return awsbedrock.StopReasonContentFiltered
+	default:
+		return awsbedrock.StopReasonEndTurn
+	}
+}
+
+// anthropicUsageToConverse converts the Anthropic usage to the Converse token usage.
+// Both exclude the cached tokens from the input tokens, while the Converse total includes them.
+func anthropicUsageToConverse(u *anthropic.Usage) *awsbedrock.TokenUsage {
+	usage := &awsbedrock.TokenUsage{
+		InputTokens:  int64(u.InputTokens),
+		OutputTokens: int64(u.OutputTokens),
+		TotalTokens:  int64(u.InputTokens + u.OutputTokens + u.CacheReadInputTokens + u.CacheCreationInputTokens),
+	}
+	if u.CacheReadInputTokens > 0 {
+		usage.CacheReadInputTokens = ptr.To(int64(u.CacheReadInputTokens))
+	}
+	if u.CacheCreationInputTokens > 0 {
+		usage.CacheWriteInputTokens = ptr.To(int64(u.CacheCreationInputTokens))
+	}
+	return usage
+}
+
+// converseStreamEventPayload is the payload of a ConverseStream event message. The event type is carried by the
+// :event-type header of the message rather than the payload, and the content block index is a pointer so that
+// the first content block is not omitted.
+type converseStreamEventPayload struct {
+	ContentBlockIndex *int                                             `json:"contentBlockIndex,omitempty"`
+	Delta             *awsbedrock.ConverseStreamEventContentBlockDelta `json:"delta,omitempty"`
+	Start             *awsbedrock.ContentBlockStart                    `json:"start,omitempty"`
+	Role              *string                                          `json:"role,omitempty"`
+	StopReason        *string                                          `json:"stopReason,omitempty"`
+	Usage             *awsbedrock.TokenUsage                           `json:"usage,omitempty"`
+	Metrics           *awsbedrock.ConverseMetrics                      `json:"metrics,omitempty"`
+}
+
+// anthropicToConverseStreamState tracks the state for converting Anthropic stream events to ConverseStream events.
+//
+// Anthropic content blocks map one-to-one to Converse content blocks, so the Anthropic index is used as the
+// Converse content block index. Converse only announces tool use blocks with contentBlockStart, so the start of
+// the other blocks is dropped. The stream ends with messageStop followed by the metadata event with the usage.
+type anthropicToConverseStreamState struct {
+	buffered   []byte
+	startTime  time.Time
+	usage      anthropic.Usage
+	stopReason *anthropic.StopReason
+	// blocks holds the indexes of the content blocks with a Converse equivalent.
+	blocks map[int]struct{}
+	done   bool
+}
+
+// convert converts the Anthropic SSE events in the body to ConverseStream eventstream messages.
+func (s *anthropicToConverseStreamState) convert(body []byte, endOfStream bool, span tracingapi.ConverseSpan) ([]byte, error) {
+	s.buffered = append(s.buffered, body...)
+	var out bytes.Buffer
+	// The events following an error are dropped since the exception ends the Converse stream.
+	err := scanAnthropicStreamEvents(&s.buffered,
+		func(chunk *anthropic.MessagesStreamChunk) error {
+			if s.done {
+				return nil
+			}
+			return s.handleChunk(chunk, &out, span)
+		},
+		func(errEvent *anthropic.ErrorResponse) error {
+			if s.done {
+				return nil
+			}
+			s.done = true
+			return writeConverseException(&out, errEvent.Error.Type, errEvent.Error.Message)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if endOfStream && !s.done {
+		// The backend closed the stream without message_stop, e.g. on an upstream timeout.
+		if err := s.complete(&out, span); err != nil {
+			return nil, err
+		}
+	}
+	// Always return a non-nil body so that the Anthropic events are never passed through.
+	return append([]byte{}, out.Bytes()...), nil
+}
+
+// handleChunk converts a single Anthropic stream event.
+func (s *anthropicToConverseStreamState) handleChunk(chunk *anthropic.MessagesStreamChunk, out *bytes.Buffer, span tracingapi.ConverseSpan) error {
+	switch {
+	case chunk.MessageStart != nil:
+		if u := chunk.MessageStart.Usage; u != nil {
+			s.usage = *u
+		}
+		return s.write(&awsbedrock.ConverseStreamEvent{
+			EventType: awsbedrock.ConverseStreamEventTypeMessageStart.String(),
+			Role:      ptr.To(awsbedrock.ConversationRoleAssistant),
+		}, out, span)
+	case chunk.ContentBlockStart != nil:
+		index, block := chunk.ContentBlockStart.Index, &chunk.ContentBlockStart.ContentBlock
+		switch {
+		case block.Tool != nil:
+			s.startBlock(index)
+			return s.write(&awsbedrock.ConverseStreamEvent{
+				EventType:         awsbedrock.ConverseStreamEventTypeContentBlockStart.String(),
+				ContentBlockIndex: index,
+				Start: &awsbedrock.ContentBlockStart{ToolUse: &awsbedrock.ToolUseBlockStart{
+					ToolUseID: block.Tool.ID, Name: block.Tool.Name,
+				}},
+			}, out, span)
+		case block.RedactedThinking != nil:
+			// Anthropic delivers redacted thinking in full with content_block_start, Converse with a delta.
+			s.startBlock(index)
+			data, err := base64.StdEncoding.DecodeString(block.RedactedThinking.Data)
+			if err != nil {
+				return nil
+			}
+			return s.writeDelta(index, &awsbedrock.ConverseStreamEventContentBlockDelta{
+				ReasoningContent: &awsbedrock.ReasoningContentBlockDelta{RedactedContent: data},
+			}, out, span)
+		case block.Text != nil, block.Thinking != nil:
+			s.startBlock(index)
+		}
+	case chunk.ContentBlockDelta != nil:
+		index, delta := chunk.ContentBlockDelta.Index, &chunk.ContentBlockDelta.Delta
+		if _, ok := s.blocks[index]; !ok {
+			return nil
+		}
+		switch {
+		case delta.Text != "":
+			return s.writeDelta(index, &awsbedrock.ConverseStreamEventContentBlockDelta{Text: ptr.To(delta.Text)}, out, span)
+		case delta.Thinking != "":
+			return s.writeDelta(index, &awsbedrock.ConverseStreamEventContentBlockDelta{
+				ReasoningContent: &awsbedrock.ReasoningContentBlockDelta{Text: delta.Thinking},
+			}, out, span)
+		case delta.Signature != "":
+			return s.writeDelta(index, &awsbedrock.ConverseStreamEventContentBlockDelta{
+				ReasoningContent: &awsbedrock.ReasoningContentBlockDelta{Signature: delta.Signature},
+			}, out, span)
+		case delta.PartialJSON != "":
+			return s.writeDelta(index, &awsbedrock.ConverseStreamEventContentBlockDelta{
+				ToolUse: &awsbedrock.ToolUseBlockDelta{Input: delta.PartialJSON},
+			}, out, span)
+		}
+	case chunk.ContentBlockStop != nil:
+		index := chunk.ContentBlockStop.Index
+		if _, ok := s.blocks[index]; !ok {
+			return nil
+		}
+		delete(s.blocks, index)
+		return s.write(&awsbedrock.ConverseStreamEvent{
+			EventType:         awsbedrock.ConverseStreamEventTypeContentBlockStop.String(),
+			ContentBlockIndex: index,
+		}, out, span)
+	case chunk.MessageDelta != nil:
+		// The usage of message_delta is cumulative.
+		u := chunk.MessageDelta.Usage
+		s.usage.OutputTokens = u.OutputTokens
+		if u.InputTokens > 0 {
+			s.usage.InputTokens = u.InputTokens
+			s.usage.CacheReadInputTokens = u.CacheReadInputTokens
+			s.usage.CacheCreationInputTokens = u.CacheCreationInputTokens
+		}
+		if reason := chunk.MessageDelta.Delta.StopReason; reason != "" {
+			s.stopReason = &reason
+		}
+	case chunk.MessageStop != nil:
+		return s.complete(out, span)
+	}
+	return nil
+}
+
+// startBlock records the content block as having a Converse equivalent.
+func (s *anthropicToConverseStreamState) startBlock(index int) {
+	if s.blocks == nil {
+		s.blocks = make(map[int]struct{})
+	}
+	s.blocks[index] = struct{}{}
+}
+
+// complete emits the messageStop event with the stop reason and the metadata event with the usage.
+func (s *anthropicToConverseStreamState) complete(out *bytes.Buffer, span tracingapi.ConverseSpan) error {
+	s.done = true
+	if err := s.write(&awsbedrock.ConverseStreamEvent{
+		EventType:  awsbedrock.ConverseStreamEventTypeMessageStop.String(),
+		StopReason: ptr.To(anthropicStopReasonToConverse(s.stopReason)),
+	}, out, span); err != nil {
+		return err
+	}
+	return s.write(&awsbedrock.ConverseStreamEvent{
+		EventType: awsbedrock.ConverseStreamEventTypeMetadata.String(),
+		Usage:     anthropicUsageToConverse(&s.usage),
+		Metrics:   &awsbedrock.ConverseMetrics{LatencyMs: ptr.To(time.Since(s.startTime).Milliseconds())},
+	}, out, span)
+}
+
+// writeDelta emits a contentBlockDelta event with the given delta.
+func (s *anthropicToConverseStreamState) writeDelta(index int, delta *awsbedrock.ConverseStreamEventContentBlockDelta, out *bytes.Buffer, span tracingapi.ConverseSpan) error {
+	return s.write(&awsbedrock.ConverseStreamEvent{
+		EventType:         awsbedrock.ConverseStreamEventTypeContentBlockDelta.String(),
+		ContentBlockIndex: index,
+		Delta:             delta,
+	}, out, span)
+}
+
+// write writes the event as an eventstream message and records it to the span.
+func (s *anthropicToConverseStreamState) write(event *awsbedrock.ConverseStreamEvent, out *bytes.Buffer, span tracingapi.ConverseSpan) error {
+	payload := converseStreamEventPayload{
+		Delta:      event.Delta,
+		Start:      event.Start,
+		Role:       event.Role,
+		StopReason: event.StopReason,
+		Usage:      event.Usage,
+		Metrics:    event.Metrics,
+	}
+	switch awsbedrock.ConverseStreamEventType(event.EventType) {
+	case awsbedrock.ConverseStreamEventTypeContentBlockStart, awsbedrock.ConverseStreamEventTypeContentBlockDelta,
+		awsbedrock.ConverseStreamEventTypeContentBlockStop:
+		payload.ContentBlockIndex = ptr.To(event.ContentBlockIndex)
+	}
+	marshaled, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stream event: %w", err)
+	}
+	if err = eventstream.NewEncoder().Encode(out, eventstream.Message{
+		Headers: eventstream.Headers{
+			{Name: ":event-type", Value: eventstream.StringValue(event.EventType)},
+			{Name: ":content-type", Value: eventstream.StringValue(jsonContentType)},
+			{Name: ":message-type", Value: eventstream.StringValue("event")},
+		},
+		Payload: marshaled,
+	}); err != nil {
+		return fmt.Errorf("failed to encode stream event: %w", err)
+	}
+	if span != nil {
+		span.RecordResponseChunk(event)
+	}
+	return nil
+}
+
+// writeConverseException writes an exception eventstream message, which the AWS SDKs surface as the stream error.
+// The exception type is the lower camel case name of the Converse exception, e.g. throttlingException.
+func writeConverseException(out *bytes.Buffer, anthropicErrorType, message string) error {
+	var exceptionType string
+	switch anthropicErrorType {
+	case "invalid_request_error":
+		exceptionType = "validationException"
+	case "rate_limit_error":
+		exceptionType = "throttlingException"
+	case "overloaded_error", "service_unavailable_error":
+		exceptionType = "serviceUnavailableException"
+	default:
+		exceptionType = "internalServerException"
+	}
+	marshaled, err := json.Marshal(awsbedrock.BedrockException{Message: message})
+	if err != nil {
+		return fmt.Errorf("failed to marshal stream exception: %w", err)
+	}
+	if err = eventstream.NewEncoder().Encode(out, eventstream.Message{
+		Headers: eventstream.Headers{
+			{Name: ":exception-type", Value: eventstream.StringValue(exceptionType)},
+			{Name: ":content-type", Value: eventstream.StringValue(jsonContentType)},
+			{Name: ":message-type", Value: eventstream.StringValue("exception")},
+		},
+		Payload: marshaled,
+	}); err != nil {
+		return fmt.Errorf("failed to encode stream exception: %w", err)
+	}
+	return nil
+}
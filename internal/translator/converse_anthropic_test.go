@@ -0,0 +1,467 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws/protocol/eventstream"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestConverseToAnthropicMessagesRequest(t *testing.T) {
+	req := &awsbedrock.ConverseInput{
+		ModelID: "claude-sonnet-4-5",
+		Stream:  true,
+		System: []*awsbedrock.SystemContentBlock{
+			{Text: ptr.To("be nice")},
+			{CachePoint: &awsbedrock.CachePointBlock{Type: "default"}},
+		},
+		Messages: []*awsbedrock.Message{
+			{Role: awsbedrock.ConversationRoleUser, Content: []*awsbedrock.ContentBlock{
+				{Text: ptr.To("what is cos(7)?")},
+				{Image: &awsbedrock.ImageBlock{Format: "png", Source: awsbedrock.ImageSource{Bytes: []byte("hello")}}},
+				{Document: &awsbedrock.DocumentBlock{Format: "pdf", Name: "doc", Source: awsbedrock.DocumentSource{Bytes: []byte("pdf")}}},
+				{CachePoint: &awsbedrock.CachePointBlock{Type: "default"}},
+			}},
+			{Role: awsbedrock.ConversationRoleAssistant, Content: []*awsbedrock.ContentBlock{
+				{ReasoningContent: &awsbedrock.ReasoningContentBlock{ReasoningText: &awsbedrock.ReasoningTextBlock{Text: "use the tool", Signature: "sig"}}},
+				{ToolUse: &awsbedrock.ToolUseBlock{ToolUseID: "tooluse_1", Name: "cosine", Input: map[string]any{"x": 7}}},
+			}},
+			{Role: awsbedrock.ConversationRoleUser, Content: []*awsbedrock.ContentBlock{
+				{ToolResult: &awsbedrock.ToolResultBlock{
+					ToolUseID: ptr.To("tooluse_1"),
+					Status:    ptr.To("error"),
+					Content:   []*awsbedrock.ToolResultContentBlock{{Text: ptr.To("0.75")}, {JSON: ptr.To(`{"output":0.75}`)}},
+				}},
+			}},
+		},
+		InferenceConfig: &awsbedrock.InferenceConfiguration{
+			MaxTokens:     ptr.To[int64](8192),
+			Temperature:   ptr.To(0.5),
+			StopSequences: []string{"END"},
+		},
+		AdditionalModelRequestFields: map[string]any{
+			"top_k":    float64(40),
+			"thinking": map[string]any{"type": "enabled", "budget_tokens": float64(2048)},
+			"unknown":  "dropped",
+		},
+		ToolConfig: &awsbedrock.ToolConfiguration{
+			Tools: []*awsbedrock.Tool{
+				{ToolSpec: &awsbedrock.ToolSpecification{
+					Name:        ptr.To("cosine"),
+					Description: ptr.To("computes the cosine"),
+					InputSchema: &awsbedrock.ToolInputSchema{JSON: map[string]any{
+						"type":       "object",
+						"properties": map[string]any{"x": map[string]any{"type": "number"}},
+						"required":   []any{"x"},
+					}},
+				}},
+				{CachePoint: &awsbedrock.CachePointBlock{Type: "default"}},
+			},
+			ToolChoice: &awsbedrock.ToolChoice{Auto: &awsbedrock.AutoToolChoice{}},
+		},
+	}
+	out, err := converseToAnthropicMessagesRequest(req)
+	require.NoError(t, err)
+
+	cacheControl := &anthropic.CacheControl{Ephemeral: &anthropic.CacheControlEphemeral{Type: "ephemeral"}}
+	require.Equal(t, "claude-sonnet-4-5", out.Model)
+	require.True(t, out.Stream)
+	require.Equal(t, &anthropic.SystemPrompt{Texts: []anthropic.TextBlockParam{{Type: "text", Text: "be nice", CacheControl: cacheControl}}}, out.System)
+	require.Equal(t, float64(8192), out.MaxTokens)
+	require.Equal(t, ptr.To(0.5), out.Temperature)
+	require.Equal(t, ptr.To(40), out.TopK)
+	require.Equal(t, []string{"END"}, out.StopSequences)
+	require.Equal(t, &anthropic.Thinking{Enabled: &anthropic.ThinkingEnabled{Type: "enabled", BudgetTokens: 2048}}, out.Thinking)
+	require.Equal(t, &anthropic.ToolChoice{Auto: &anthropic.ToolChoiceAuto{Type: "auto"}}, out.ToolChoice)
+	require.Equal(t, []anthropic.ToolUnion{{Tool: &anthropic.Tool{
+		Type:        "custom",
+		Name:        "cosine",
+		Description: "computes the cosine",
+		InputSchema: anthropic.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]any{"x": map[string]any{"type": "number"}},
+			Required:   []string{"x"},
+		},
+		CacheControl: cacheControl,
+	}}}, out.Tools)
+
+	require.Len(t, out.Messages, 3)
+	require.Equal(t, anthropic.MessageRoleUser, out.Messages[0].Role)
+	require.Equal(t, []anthropic.ContentBlockParam{
+		anthropicTextBlock("what is cos(7)?"),
+		{Image: &anthropic.ImageBlockParam{Type: "image", Source: anthropic.ImageSource{
+			Base64: &anthropic.Base64ImageSource{Type: "base64", MediaType: mimeTypeImagePNG, Data: "aGVsbG8="},
+		}}},
+		{Document: &anthropic.DocumentBlockParam{Type: "document", Title: "doc", CacheControl: cacheControl, Source: anthropic.DocumentSource{
+			Base64PDF: &anthropic.Base64PDFSource{Type: "base64", MediaType: "application/pdf", Data: "cGRm"},
+		}}},
+	}, out.Messages[0].Content.Array)
+	require.Equal(t, anthropic.MessageRoleAssistant, out.Messages[1].Role)
+	require.Equal(t, []anthropic.ContentBlockParam{
+		{Thinking: &anthropic.ThinkingBlockParam{Type: "thinking", Thinking: "use the tool", Signature: "sig"}},
+		{ToolUse: &anthropic.ToolUseBlockParam{Type: "tool_use", ID: "tooluse_1", Name: "cosine", Input: map[string]any{"x": 7}}},
+	}, out.Messages[1].Content.Array)
+	require.Equal(t, []anthropic.ContentBlockParam{
+		{ToolResult: &anthropic.ToolResultBlockParam{
+			Type:      "tool_result",
+			ToolUseID: "tooluse_1",
+			IsError:   true,
+			Content: &anthropic.ToolResultContent{Array: []anthropic.ToolResultContentItem{
+				{Text: &anthropic.TextBlockParam{Type: "text", Text: "0.75"}},
+				{Text: &anthropic.TextBlockParam{Type: "text", Text: `{"output":0.75}`}},
+			}},
+		}},
+	}, out.Messages[2].Content.Array)
+
+	t.Run("guardrail config", func(t *testing.T) {
+		_, err := converseToAnthropicMessagesRequest(&awsbedrock.ConverseInput{GuardrailConfig: &awsbedrock.GuardrailConfiguration{}})
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+	})
+	t.Run("unsupported document format", func(t *testing.T) {
+		_, err := converseToAnthropicMessagesRequest(&awsbedrock.ConverseInput{Messages: []*awsbedrock.Message{
+			{Role: awsbedrock.ConversationRoleUser, Content: []*awsbedrock.ContentBlock{
+				{Document: &awsbedrock.DocumentBlock{Format: "docx", Name: "doc"}},
+			}},
+		}})
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+	})
+}
+
+func TestApplyConverseAdditionalModelRequestFields_Thinking(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		thinking     map[string]any
+		maxTokens    int64
+		expThinking  *anthropic.Thinking
+		expMaxTokens float64
+		expErr       string
+	}{
+		{
+			name:         "enabled without max tokens",
+			thinking:     map[string]any{"type": "enabled", "budget_tokens": float64(2048)},
+			expThinking:  &anthropic.Thinking{Enabled: &anthropic.ThinkingEnabled{Type: "enabled", BudgetTokens: 2048}},
+			expMaxTokens: defaultConverseMaxTokens + 2048,
+		},
+		{
+			name:         "enabled with max tokens",
+			thinking:     map[string]any{"type": "enabled", "budget_tokens": float64(2048)},
+			maxTokens:    8192,
+			expThinking:  &anthropic.Thinking{Enabled: &anthropic.ThinkingEnabled{Type: "enabled", BudgetTokens: 2048}},
+			expMaxTokens: 8192,
+		},
+		{
+			name:         "budget above max tokens",
+			thinking:     map[string]any{"type": "enabled", "budget_tokens": float64(4096)},
+			maxTokens:    2048,
+			expThinking:  &anthropic.Thinking{Enabled: &anthropic.ThinkingEnabled{Type: "enabled", BudgetTokens: 2047}},
+			expMaxTokens: 2048,
+		},
+		{
+			name:         "disabled",
+			thinking:     map[string]any{"type": "disabled"},
+			expThinking:  &anthropic.Thinking{Disabled: &anthropic.ThinkingDisabled{Type: "disabled"}},
+			expMaxTokens: defaultConverseMaxTokens,
+		},
+		{
+			name:     "budget too small",
+			thinking: map[string]any{"type": "enabled", "budget_tokens": float64(100)},
+			expErr:   "thinking.budget_tokens must be at least 1024",
+		},
+		{
+			name:     "unknown type",
+			thinking: map[string]any{"type": "unknown"},
+			expErr:   "unsupported thinking type unknown",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &anthropic.MessagesRequest{MaxTokens: defaultConverseMaxTokens}
+			if tc.maxTokens > 0 {
+				out.MaxTokens = float64(tc.maxTokens)
+			}
+			err := applyConverseAdditionalModelRequestFields(out, map[string]any{"thinking": tc.thinking}, tc.maxTokens)
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+				require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expThinking, out.Thinking)
+			require.Equal(t, tc.expMaxTokens, out.MaxTokens)
+		})
+	}
+}
+
+func TestConverseToolChoiceToAnthropic(t *testing.T) {
+	require.Nil(t, converseToolChoiceToAnthropic(nil))
+	require.Equal(t, &anthropic.ToolChoice{Any: &anthropic.ToolChoiceAny{Type: "any"}},
+		converseToolChoiceToAnthropic(&awsbedrock.ToolChoice{Any: &awsbedrock.AnyToolChoice{}}))
+	require.Equal(t, &anthropic.ToolChoice{Tool: &anthropic.ToolChoiceTool{Type: "tool", Name: "cosine"}},
+		converseToolChoiceToAnthropic(&awsbedrock.ToolChoice{Tool: &awsbedrock.SpecificToolChoice{Name: ptr.To("cosine")}}))
+}
+
+func TestConverseToAnthropicTranslator_RequestBody(t *testing.T) {
+	tr := NewConverseToAnthropicTranslator("", "claude-override")
+	headers, body, err := tr.RequestBody(nil, &awsbedrock.ConverseInput{
+		ModelID:  "claude-sonnet-4-5",
+		Messages: []*awsbedrock.Message{{Role: awsbedrock.ConversationRoleUser, Content: []*awsbedrock.ContentBlock{{Text: ptr.To("hi")}}}},
+	}, false)
+	require.NoError(t, err)
+	require.Equal(t, internalapi.Header{pathHeaderName, "/v1/messages"}, headers[0])
+	var anthropicReq anthropic.MessagesRequest
+	require.NoError(t, json.Unmarshal(body, &anthropicReq))
+	require.Equal(t, "claude-override", anthropicReq.Model)
+	require.Equal(t, float64(defaultConverseMaxTokens), anthropicReq.MaxTokens)
+}
+
+func TestConverseToAnthropicTranslator_ResponseBody_NonStreaming(t *testing.T) {
+	tr := NewConverseToAnthropicTranslator("", "")
+	_, _, err := tr.RequestBody(nil, &awsbedrock.ConverseInput{
+		ModelID:  "claude-sonnet-4-5",
+		Messages: []*awsbedrock.Message{{Role: awsbedrock.ConversationRoleUser, Content: []*awsbedrock.ContentBlock{{Text: ptr.To("what is cos(7)?")}}}},
+	}, false)
+	require.NoError(t, err)
+
+	anthropicResp := `{"id":"msg_01","type":"message","role":"assistant","model":"claude-sonnet-4-5-20250929",
+"content":[{"type":"thinking","thinking":"use the tool","signature":"sig"},{"type":"text","text":"Let me compute."},{"type":"tool_use","id":"toolu_1","name":"cosine","input":{"x":7}}],
+"stop_reason":"tool_use","usage":{"input_tokens":10,"output_tokens":20,"cache_read_input_tokens":5,"cache_creation_input_tokens":3}}`
+	span := &mockConverseSpan{}
+	headers, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(anthropicResp), true, span)
+	require.NoError(t, err)
+	require.Equal(t, "claude-sonnet-4-5-20250929", responseModel)
+	require.Len(t, headers, 1)
+	require.Equal(t, contentLengthHeaderName, headers[0].Key())
+	inputTokens, _ := tokenUsage.InputTokens()
+	require.Equal(t, uint32(18), inputTokens)
+	require.NotNil(t, span.recordedResponse)
+
+	var resp awsbedrock.ConverseResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	require.Equal(t, ptr.To(awsbedrock.StopReasonToolUse), resp.StopReason)
+	require.NotNil(t, resp.Metrics.LatencyMs)
+	require.Equal(t, &awsbedrock.TokenUsage{
+		InputTokens:           10,
+		OutputTokens:          20,
+		TotalTokens:           38,
+		CacheReadInputTokens:  ptr.To[int64](5),
+		CacheWriteInputTokens: ptr.To[int64](3),
+	}, resp.Usage)
+	require.Equal(t, awsbedrock.ConversationRoleAssistant, resp.Output.Message.Role)
+	require.Equal(t, []*awsbedrock.ContentBlock{
+		{ReasoningContent: &awsbedrock.ReasoningContentBlock{ReasoningText: &awsbedrock.ReasoningTextBlock{Text: "use the tool", Signature: "sig"}}},
+		{Text: ptr.To("Let me compute.")},
+		{ToolUse: &awsbedrock.ToolUseBlock{ToolUseID: "toolu_1", Name: "cosine", Input: map[string]any{"x": float64(7)}}},
+	}, resp.Output.Message.Content)
+
+	t.Run("max tokens", func(t *testing.T) {
+		_, body, _, _, err := tr.ResponseBody(nil, strings.NewReader(
+			`{"id":"msg_02","type":"message","role":"assistant","model":"claude","content":[{"type":"text","text":"Hel"}],"stop_reason":"max_tokens","usage":{"input_tokens":1,"output_tokens":1}}`,
+		), true, nil)
+		require.NoError(t, err)
+		var resp awsbedrock.ConverseResponse
+		require.NoError(t, json.Unmarshal(body, &resp))
+		require.Equal(t, ptr.To(awsbedrock.StopReasonMaxTokens), resp.StopReason)
+	})
+}
+
+// decodeConverseStreamMessages decodes the eventstream messages of the ConverseStream response.
+func decodeConverseStreamMessages(t *testing.T, body []byte) []eventstream.Message {
+	var messages []eventstream.Message
+	decoder := eventstream.NewDecoder()
+	r := bytes.NewReader(body)
+	for r.Len() > 0 {
+		msg, err := decoder.Decode(r, nil)
+		require.NoError(t, err)
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+func TestConverseToAnthropicTranslator_ResponseBody_Streaming(t *testing.T) {
+	tr := NewConverseToAnthropicTranslator("", "")
+	_, _, err := tr.RequestBody(nil, &awsbedrock.ConverseInput{
+		ModelID:  "claude-sonnet-4-5",
+		Stream:   true,
+		Messages: []*awsbedrock.Message{{Role: awsbedrock.ConversationRoleUser, Content: []*awsbedrock.ContentBlock{{Text: ptr.To("what is cos(7)?")}}}},
+	}, false)
+	require.NoError(t, err)
+	headers, err := tr.ResponseHeaders(nil)
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{{contentTypeHeaderName, awsEventStreamContentType}}, headers)
+
+	stream := []byte(`event: message_start
+data: {"type":"message_start","message":{"id":"msg_01","type":"message","role":"assistant","content":[],"model":"claude-sonnet-4-5","usage":{"input_tokens":10,"output_tokens":1}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"use the tool"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"sig"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: ping
+data: {"type":"ping"}
+
+event: content_block_start
+data: {"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Hello"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":1}
+
+event: content_block_start
+data: {"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"toolu_1","name":"cosine","input":{}}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"{\"x\":7}"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":2}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":15}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`)
+	span := &mockConverseSpan{}
+	// Feed the stream in small chunks to exercise the buffering of partial lines.
+	var out []byte
+	for i := 0; i < len(stream); i += 50 {
+		_, body, _, _, err := tr.ResponseBody(nil, bytes.NewReader(stream[i:min(i+50, len(stream))]), false, span)
+		require.NoError(t, err)
+		out = append(out, body...)
+	}
+	_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, bytes.NewReader(nil), true, span)
+	require.NoError(t, err)
+	out = append(out, body...)
+	require.Equal(t, "claude-sonnet-4-5", responseModel)
+	outputTokens, _ := tokenUsage.OutputTokens()
+	require.Equal(t, uint32(15), outputTokens)
+
+	messages := decodeConverseStreamMessages(t, out)
+	var eventTypes, payloads []string
+	for _, msg := range messages {
+		require.Equal(t, "event", msg.Headers.Get(":message-type").String())
+		eventTypes = append(eventTypes, msg.Headers.Get(":event-type").String())
+		payloads = append(payloads, string(msg.Payload))
+	}
+	require.Equal(t, []string{
+		"messageStart",
+		"contentBlockDelta", "contentBlockDelta", "contentBlockStop",
+		"contentBlockDelta", "contentBlockStop",
+		"contentBlockStart", "contentBlockDelta", "contentBlockStop",
+		"messageStop", "metadata",
+	}, eventTypes)
+	require.JSONEq(t, `{"role":"assistant"}`, payloads[0])
+	require.JSONEq(t, `{"contentBlockIndex":0,"delta":{"reasoningContent":{"text":"use the tool"}}}`, payloads[1])
+	require.JSONEq(t, `{"contentBlockIndex":0,"delta":{"reasoningContent":{"signature":"sig"}}}`, payloads[2])
+	require.JSONEq(t, `{"contentBlockIndex":0}`, payloads[3])
+	require.JSONEq(t, `{"contentBlockIndex":1,"delta":{"text":"Hello"}}`, payloads[4])
+	require.JSONEq(t, `{"contentBlockIndex":2,"start":{"toolUse":{"toolUseId":"toolu_1","name":"cosine"}}}`, payloads[6])
+	require.JSONEq(t, `{"contentBlockIndex":2,"delta":{"toolUse":{"input":"{\"x\":7}"}}}`, payloads[7])
+	require.JSONEq(t, `{"stopReason":"tool_use"}`, payloads[9])
+
+	var metadata converseStreamEventPayload
+	require.NoError(t, json.Unmarshal(messages[10].Payload, &metadata))
+	require.Equal(t, &awsbedrock.TokenUsage{InputTokens: 10, OutputTokens: 15, TotalTokens: 25}, metadata.Usage)
+	require.NotNil(t, metadata.Metrics.LatencyMs)
+
+	require.Len(t, span.recordedChunks, len(messages))
+	require.Equal(t, "metadata", span.recordedChunks[10].EventType)
+
+	t.Run("error event", func(t *testing.T) {
+		tr := NewConverseToAnthropicTranslator("", "")
+		_, _, err := tr.RequestBody(nil, &awsbedrock.ConverseInput{
+			Stream:   true,
+			Messages: []*awsbedrock.Message{{Role: awsbedrock.ConversationRoleUser, Content: []*awsbedrock.ContentBlock{{Text: ptr.To("hi")}}}},
+		}, false)
+		require.NoError(t, err)
+		_, body, _, _, err := tr.ResponseBody(nil, strings.NewReader(`event: message_start
+data: {"type":"message_start","message":{"id":"msg_01","type":"message","role":"assistant","content":[],"model":"claude","usage":{"input_tokens":1,"output_tokens":0}}}
+
+event: error
+data: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}
+
+`), true, nil)
+		require.NoError(t, err)
+		messages := decodeConverseStreamMessages(t, body)
+		require.Len(t, messages, 2)
+		require.Equal(t, "exception", messages[1].Headers.Get(":message-type").String())
+		require.Equal(t, "serviceUnavailableException", messages[1].Headers.Get(":exception-type").String())
+		require.JSONEq(t, `{"message":"Overloaded"}`, string(messages[1].Payload))
+	})
+
+	t.Run("missing message_stop", func(t *testing.T) {
+		tr := NewConverseToAnthropicTranslator("", "")
+		_, _, err := tr.RequestBody(nil, &awsbedrock.ConverseInput{
+			Stream:   true,
+			Messages: []*awsbedrock.Message{{Role: awsbedrock.ConversationRoleUser, Content: []*awsbedrock.ContentBlock{{Text: ptr.To("hi")}}}},
+		}, false)
+		require.NoError(t, err)
+		_, body, _, _, err := tr.ResponseBody(nil, strings.NewReader(`event: message_start
+data: {"type":"message_start","message":{"id":"msg_01","type":"message","role":"assistant","content":[],"model":"claude","usage":{"input_tokens":1,"output_tokens":0}}}
+
+`), true, nil)
+		require.NoError(t, err)
+		messages := decodeConverseStreamMessages(t, body)
+		require.Len(t, messages, 3)
+		require.Equal(t, "messageStop", messages[1].Headers.Get(":event-type").String())
+		require.Equal(t, "metadata", messages[2].Headers.Get(":event-type").String())
+	})
+}
+
+func TestConverseToAnthropicTranslator_ResponseError(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		headers map[string]string
+		body    string
+		expType string
+		expMsg  string
+	}{
+		{
+			name:    "anthropic error",
+			headers: map[string]string{statusHeaderName: "429", contentTypeHeaderName: jsonContentType},
+			body:    `{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`,
+			expType: "ThrottlingException",
+			expMsg:  "slow down",
+		},
+		{
+			name:    "non json error",
+			headers: map[string]string{statusHeaderName: "503"},
+			body:    "upstream connect error",
+			expType: "ServiceUnavailableException",
+			expMsg:  "upstream connect error",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := NewConverseToAnthropicTranslator("", "")
+			headers, body, err := tr.ResponseError(tc.headers, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			var exception awsbedrock.BedrockException
+			require.NoError(t, json.Unmarshal(body, &exception))
+			require.Equal(t, tc.expMsg, exception.Message)
+			require.Equal(t, internalapi.Header{awsErrorTypeHeaderName, tc.expType}, headers[1])
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewConverseToAWSBedrockTranslator implements [Factory] for AWS Bedrock Converse to AWS Bedrock translation.
+// The request and response bodies are passed through as is.
+func NewConverseToAWSBedrockTranslator(modelNameOverride internalapi.ModelNameOverride) AWSBedrockConverseTranslator {
+	return &converseToAWSBedrockTranslator{modelNameOverride: modelNameOverride}
+}
+
+// converseToAWSBedrockTranslator passes the Converse and ConverseStream requests through to AWS Bedrock.
+// Only the model in the path is rewritten when overridden. The token usage is read from the usage of the response,
+// or from the metadata event at the end of the stream.
+type converseToAWSBedrockTranslator struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+	stream            bool
+	bufferedBody      []byte
+	events            []awsbedrock.ConverseStreamEvent
+	// streamingTokenUsage is taken from the metadata event.
+	streamingTokenUsage metrics.TokenUsage
+}
+
+// RequestBody implements [AWSBedrockConverseTranslator.RequestBody].
+func (c *converseToAWSBedrockTranslator) RequestBody(original []byte, req *awsbedrock.ConverseInput, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	c.stream = req.Stream
+	c.requestModel = cmp.Or(c.modelNameOverride, req.ModelID)
+
+	pathTemplate := "/model/%s/converse"
+	if c.stream {
+		pathTemplate = "/model/%s/converse-stream"
+	}
+	// The body is always set since the AWS request signing hashes the payload and only sees the mutated body.
+	newBody = original
+	newHeaders = []internalapi.Header{
+		// URL encode the model name for the path to handle ARNs with special characters.
+		{pathHeaderName, fmt.Sprintf(pathTemplate, url.PathEscape(c.requestModel))},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// ResponseHeaders implements [AWSBedrockConverseTranslator.ResponseHeaders].
+func (c *converseToAWSBedrockTranslator) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [AWSBedrockConverseTranslator.ResponseBody].
+// The Converse response does not contain the model, so the request model is returned as the response model.
+func (c *converseToAWSBedrockTranslator) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.ConverseSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	responseModel = c.requestModel
+	if c.stream {
+		var buf []byte
+		if buf, err = io.ReadAll(body); err != nil {
+			return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to read body: %w", err)
+		}
+		c.bufferedBody = append(c.bufferedBody, buf...)
+		c.bufferedBody, c.events = decodeConverseStreamEvents(c.bufferedBody, c.events)
+		for i := range c.events {
+			event := c.events[i]
+			if u := event.Usage; u != nil {
+				c.streamingTokenUsage = metrics.ExtractTokenUsageFromExplicitCaching(u.InputTokens, u.OutputTokens,
+					u.CacheReadInputTokens, u.CacheWriteInputTokens)
+			}
+			if span != nil {
+				// The events are reused by the next decoding, so the span records a copy.
+				span.RecordResponseChunk(&event)
+			}
+		}
+		return nil, nil, c.streamingTokenUsage, responseModel, nil
+	}
+
+	resp := &awsbedrock.ConverseResponse{}
+	if err = json.NewDecoder(body).Decode(resp); err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	if u := resp.Usage; u != nil {
+		tokenUsage = metrics.ExtractTokenUsageFromExplicitCaching(u.InputTokens, u.OutputTokens,
+			u.CacheReadInputTokens, u.CacheWriteInputTokens)
+	}
+	if span != nil {
+		span.RecordResponse(resp)
+	}
+	return nil, nil, tokenUsage, responseModel, nil
+}
+
+// ResponseError implements [AWSBedrockConverseTranslator.ResponseError].
+// AWS Bedrock already returns the Converse error format, so only non-JSON errors are converted.
+func (c *converseToAWSBedrockTranslator) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	if v, ok := respHeaders[contentTypeHeaderName]; ok && strings.Contains(v, jsonContentType) {
+		return nil, nil, nil
+	}
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+	}
+	return converseErrorResponse(respHeaders[statusHeaderName], string(bytes.TrimSpace(buf)))
+}
+
+// converseErrorResponse builds the AWS Bedrock error response for the given HTTP status code and message.
+// The exception name is carried by the x-amzn-errortype header, which the AWS SDKs use to build the typed error.
+// https://docs.aws.amazon.com/bedrock/latest/APIReference/API_runtime_Converse.html#API_runtime_Converse_Errors
+func converseErrorResponse(statusCode, message string) (newHeaders []internalapi.Header, newBody []byte, err error) {
+	newBody, err = json.Marshal(awsbedrock.BedrockException{Message: message})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal error body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{contentTypeHeaderName, jsonContentType},
+		{awsErrorTypeHeaderName, converseExceptionForStatus(statusCode)},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// converseExceptionForStatus returns the AWS Bedrock exception name corresponding to the HTTP status code.
+func converseExceptionForStatus(statusCode string) string {
+	switch statusCode {
+	case "400":
+		return "ValidationException"
+	case "403":
+		return "AccessDeniedException"
+	case "404":
+		return "ResourceNotFoundException"
+	case "408":
+		return "ModelTimeoutException"
+	case "429":
+		return "ThrottlingException"
+	case "503":
+		return "ServiceUnavailableException"
+	default:
+		return "InternalServerException"
+	}
+}
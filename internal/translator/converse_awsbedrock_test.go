@@ -0,0 +1,184 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws/protocol/eventstream"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+// mockConverseSpan implements [tracingapi.ConverseSpan] for testing.
+type mockConverseSpan struct {
+	recordedResponse *awsbedrock.ConverseResponse
+	recordedChunks   []*awsbedrock.ConverseStreamEvent
+}
+
+func (m *mockConverseSpan) RecordResponseChunk(event *awsbedrock.ConverseStreamEvent) {
+	m.recordedChunks = append(m.recordedChunks, event)
+}
+
+func (m *mockConverseSpan) RecordResponse(resp *awsbedrock.ConverseResponse) {
+	m.recordedResponse = resp
+}
+func (m *mockConverseSpan) EndSpanOnError(int, []byte) {}
+func (m *mockConverseSpan) EndSpan()                   {}
+
+func TestConverseToAWSBedrockTranslator_RequestBody(t *testing.T) {
+	original := []byte(`{"messages":[{"role":"user","content":[{"text":"hi"}]}]}`)
+	for _, tc := range []struct {
+		name              string
+		req               awsbedrock.ConverseInput
+		modelNameOverride string
+		expPath           string
+	}{
+		{
+			name:    "converse",
+			req:     awsbedrock.ConverseInput{ModelID: "anthropic.claude-sonnet-4-5"},
+			expPath: "/model/anthropic.claude-sonnet-4-5/converse",
+		},
+		{
+			name:    "converse-stream",
+			req:     awsbedrock.ConverseInput{ModelID: "anthropic.claude-sonnet-4-5", Stream: true},
+			expPath: "/model/anthropic.claude-sonnet-4-5/converse-stream",
+		},
+		{
+			name:              "model name override with ARN",
+			req:               awsbedrock.ConverseInput{ModelID: "anthropic.claude-sonnet-4-5"},
+			modelNameOverride: "arn:aws:bedrock:us-east-1:123456789012:inference-profile/us.anthropic.claude-sonnet-4-5",
+			expPath:           "/model/arn:aws:bedrock:us-east-1:123456789012:inference-profile%2Fus.anthropic.claude-sonnet-4-5/converse",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := NewConverseToAWSBedrockTranslator(tc.modelNameOverride)
+			headers, body, err := tr.RequestBody(original, &tc.req, false)
+			require.NoError(t, err)
+			require.Equal(t, original, body)
+			require.Equal(t, []internalapi.Header{
+				{pathHeaderName, tc.expPath},
+				{contentLengthHeaderName, strconv.Itoa(len(original))},
+			}, headers)
+		})
+	}
+}
+
+func TestConverseToAWSBedrockTranslator_ResponseBody(t *testing.T) {
+	t.Run("non-streaming", func(t *testing.T) {
+		tr := NewConverseToAWSBedrockTranslator("override")
+		_, _, err := tr.RequestBody(nil, &awsbedrock.ConverseInput{ModelID: "anthropic.claude-sonnet-4-5"}, false)
+		require.NoError(t, err)
+
+		span := &mockConverseSpan{}
+		headers, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(`{
+"output":{"message":{"role":"assistant","content":[{"text":"Hello!"}]}},
+"stopReason":"end_turn",
+"usage":{"inputTokens":10,"outputTokens":5,"totalTokens":19,"cacheReadInputTokens":4}}`), true, span)
+		require.NoError(t, err)
+		require.Nil(t, headers)
+		require.Nil(t, body)
+		require.Equal(t, "override", responseModel)
+		inputTokens, _ := tokenUsage.InputTokens()
+		require.Equal(t, uint32(14), inputTokens)
+		cachedTokens, _ := tokenUsage.CachedInputTokens()
+		require.Equal(t, uint32(4), cachedTokens)
+		outputTokens, _ := tokenUsage.OutputTokens()
+		require.Equal(t, uint32(5), outputTokens)
+		require.Equal(t, "Hello!", *span.recordedResponse.Output.Message.Content[0].Text)
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		tr := NewConverseToAWSBedrockTranslator("")
+		_, _, err := tr.RequestBody(nil, &awsbedrock.ConverseInput{ModelID: "anthropic.claude-sonnet-4-5", Stream: true}, false)
+		require.NoError(t, err)
+
+		buf := bytes.NewBuffer(nil)
+		e := eventstream.NewEncoder()
+		for _, event := range []struct {
+			typ     awsbedrock.ConverseStreamEventType
+			payload string
+		}{
+			{awsbedrock.ConverseStreamEventTypeMessageStart, `{"role":"assistant"}`},
+			{awsbedrock.ConverseStreamEventTypeContentBlockDelta, `{"contentBlockIndex":0,"delta":{"text":"Hello"}}`},
+			{awsbedrock.ConverseStreamEventTypeContentBlockStop, `{"contentBlockIndex":0}`},
+			{awsbedrock.ConverseStreamEventTypeMessageStop, `{"stopReason":"end_turn"}`},
+			{awsbedrock.ConverseStreamEventTypeMetadata, `{"usage":{"inputTokens":10,"outputTokens":5,"totalTokens":15},"metrics":{"latencyMs":100}}`},
+		} {
+			require.NoError(t, e.Encode(buf, eventstream.Message{
+				Headers: eventstream.Headers{{Name: ":event-type", Value: eventstream.StringValue(event.typ.String())}},
+				Payload: []byte(event.payload),
+			}))
+		}
+		stream := buf.Bytes()
+
+		span := &mockConverseSpan{}
+		// Feed the stream in small chunks to exercise the buffering of partial messages.
+		for i := 0; i < len(stream); i += 30 {
+			_, body, _, _, err := tr.ResponseBody(nil, bytes.NewReader(stream[i:min(i+30, len(stream))]), false, span)
+			require.NoError(t, err)
+			require.Nil(t, body)
+		}
+		_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, bytes.NewReader(nil), true, span)
+		require.NoError(t, err)
+		require.Nil(t, body)
+		require.Equal(t, "anthropic.claude-sonnet-4-5", responseModel)
+		inputTokens, _ := tokenUsage.InputTokens()
+		require.Equal(t, uint32(10), inputTokens)
+		outputTokens, _ := tokenUsage.OutputTokens()
+		require.Equal(t, uint32(5), outputTokens)
+
+		require.Len(t, span.recordedChunks, 5)
+		require.Equal(t, "messageStart", span.recordedChunks[0].EventType)
+		require.Equal(t, ptr.To("Hello"), span.recordedChunks[1].Delta.Text)
+		require.Equal(t, ptr.To(awsbedrock.StopReasonEndTurn), span.recordedChunks[3].StopReason)
+		require.Equal(t, ptr.To[int64](100), span.recordedChunks[4].Metrics.LatencyMs)
+	})
+}
+
+func TestConverseToAWSBedrockTranslator_ResponseError(t *testing.T) {
+	tr := NewConverseToAWSBedrockTranslator("")
+
+	t.Run("json error passthrough", func(t *testing.T) {
+		headers, body, err := tr.ResponseError(map[string]string{statusHeaderName: "400", contentTypeHeaderName: jsonContentType},
+			strings.NewReader(`{"message":"bad request"}`))
+		require.NoError(t, err)
+		require.Nil(t, headers)
+		require.Nil(t, body)
+	})
+
+	for _, tc := range []struct {
+		status, expType string
+	}{
+		{"400", "ValidationException"},
+		{"403", "AccessDeniedException"},
+		{"404", "ResourceNotFoundException"},
+		{"408", "ModelTimeoutException"},
+		{"429", "ThrottlingException"},
+		{"503", "ServiceUnavailableException"},
+		{"500", "InternalServerException"},
+	} {
+		t.Run(tc.status, func(t *testing.T) {
+			headers, body, err := tr.ResponseError(map[string]string{statusHeaderName: tc.status}, strings.NewReader("upstream error\n"))
+			require.NoError(t, err)
+			var exception awsbedrock.BedrockException
+			require.NoError(t, json.Unmarshal(body, &exception))
+			require.Equal(t, "upstream error", exception.Message)
+			require.Equal(t, []internalapi.Header{
+				{contentTypeHeaderName, jsonContentType},
+				{awsErrorTypeHeaderName, tc.expType},
+				{contentLengthHeaderName, "28"},
+			}, headers)
+		})
+	}
+}
@@ -1009,3 +1009,16 @@ func buildGCPModelPathSuffix(publisher, model, gcpMethod string, queryParams ...
 	}
 	return pathSuffix
 }
+
+// buildGoogleAIStudioModelPath constructs the Gemini Developer API path of the model method with optional queryParams
+// where each string is in the form of "%s=%s". Unlike Vertex AI, the path is absolute since it is not scoped to a
+// project and location. The model may be given with or without the "models/" prefix.
+// https://ai.google.dev/api/generate-content
+func buildGoogleAIStudioModelPath(model, method string, queryParams ...string) string {
+	path := fmt.Sprintf("/v1beta/models/%s:%s", strings.TrimPrefix(model, "models/"), method)
+
+	if len(queryParams) > 0 {
+		path += "?" + strings.Join(queryParams, "&")
+	}
+	return path
+}
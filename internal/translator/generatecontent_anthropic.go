@@ -0,0 +1,700 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genai"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// defaultGenerateContentMaxTokens is used as Anthropic max_tokens when the generateContent request does not set
+// generationConfig.maxOutputTokens, since the Messages API requires it.
+const defaultGenerateContentMaxTokens = 4096
+
+// NewGenerateContentToAnthropicTranslator implements [GeminiGenerateContentTranslator] for Gemini generateContent
+// to native Anthropic Messages translation.
+func NewGenerateContentToAnthropicTranslator(version string, modelNameOverride internalapi.ModelNameOverride) GeminiGenerateContentTranslator {
+	return &generateContentToAnthropicMessagesTranslator{inner: NewAnthropicToAnthropicTranslator(version, modelNameOverride)}
+}
+
+// NewGenerateContentToOpenAITranslator implements [GeminiGenerateContentTranslator] for Gemini generateContent
+// to OpenAI Chat Completions translation.
+func NewGenerateContentToOpenAITranslator(prefix string, modelNameOverride internalapi.ModelNameOverride) GeminiGenerateContentTranslator {
+	return &generateContentToAnthropicMessagesTranslator{inner: NewAnthropicToChatCompletionOpenAITranslator(prefix, modelNameOverride)}
+}
+
+// NewGenerateContentToAWSBedrockTranslator implements [GeminiGenerateContentTranslator] for Gemini generateContent
+// to AWS Bedrock Converse translation.
+func NewGenerateContentToAWSBedrockTranslator(modelNameOverride internalapi.ModelNameOverride) GeminiGenerateContentTranslator {
+	return &generateContentToAnthropicMessagesTranslator{inner: NewAnthropicToAWSBedrockTranslator(modelNameOverride)}
+}
+
+// generateContentToAnthropicMessagesTranslator translates the Gemini generateContent API to backends that do not
+// speak it natively. The same as [responsesToAnthropicMessagesTranslator], the request is first converted to an
+// Anthropic Messages request and the backend specific translation is delegated to an [AnthropicMessagesTranslator].
+// The Anthropic Messages responses produced by the inner translator are then converted back to the Gemini format.
+type generateContentToAnthropicMessagesTranslator struct {
+	inner  AnthropicMessagesTranslator
+	stream bool
+	// streamState holds the state of the streamed chunk conversion.
+	streamState *anthropicToGeminiStreamState
+}
+
+// RequestBody implements [GeminiGenerateContentTranslator.RequestBody].
+func (g *generateContentToAnthropicMessagesTranslator) RequestBody(_ []byte, req *gcp.GenerateContentRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	g.stream = req.Stream
+	anthropicReq, err := generateContentToAnthropicMessagesRequest(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if g.stream {
+		g.streamState = &anthropicToGeminiStreamState{model: req.Model}
+	}
+	original, err := json.Marshal(anthropicReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal Anthropic request: %w", err)
+	}
+	// The body always has to be mutated since the backend does not understand the Gemini format.
+	return g.inner.RequestBody(original, anthropicReq, true)
+}
+
+// ResponseHeaders implements [GeminiGenerateContentTranslator.ResponseHeaders].
+func (g *generateContentToAnthropicMessagesTranslator) ResponseHeaders(headers map[string]string) (newHeaders []internalapi.Header, err error) {
+	newHeaders, err = g.inner.ResponseHeaders(headers)
+	if err != nil {
+		return nil, err
+	}
+	if g.stream && !hasHeader(newHeaders, contentTypeHeaderName) {
+		newHeaders = append(newHeaders, internalapi.Header{contentTypeHeaderName, eventStreamContentType})
+	}
+	return newHeaders, nil
+}
+
+// ResponseBody implements [GeminiGenerateContentTranslator.ResponseBody].
+func (g *generateContentToAnthropicMessagesTranslator) ResponseBody(respHeaders map[string]string, body io.Reader, endOfStream bool, span tracingapi.GenerateContentSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel string, err error,
+) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to read body: %w", err)
+	}
+	// The inner translator produces the Anthropic Messages body. A nil body means it is passed through as is.
+	_, anthropicBody, tokenUsage, responseModel, err := g.inner.ResponseBody(respHeaders, bytes.NewReader(raw), endOfStream, nil)
+	if err != nil {
+		return nil, nil, tokenUsage, responseModel, err
+	}
+	if anthropicBody == nil {
+		anthropicBody = raw
+	}
+
+	if g.stream {
+		newBody, err = g.streamState.convert(anthropicBody, endOfStream, responseModel, span)
+		if err != nil {
+			return nil, nil, tokenUsage, responseModel, err
+		}
+		return nil, newBody, tokenUsage, responseModel, nil
+	}
+
+	anthropicResp := &anthropic.MessagesResponse{}
+	if err = json.Unmarshal(anthropicBody, anthropicResp); err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to unmarshal Anthropic response: %w", err)
+	}
+	resp := anthropicToGeminiResponse(anthropicResp, cmp.Or(responseModel, anthropicResp.Model))
+	if span != nil {
+		span.RecordResponse(resp)
+	}
+	newBody, err = json.Marshal(resp)
+	if err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// ResponseError implements [GeminiGenerateContentTranslator.ResponseError].
+// The inner translator normalizes the backend error to the Anthropic error format, which is then
+// converted to the Gemini error format.
+func (g *generateContentToAnthropicMessagesTranslator) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+	}
+	_, anthropicBody, err := g.inner.ResponseError(respHeaders, bytes.NewReader(raw))
+	if err != nil {
+		return nil, nil, err
+	}
+	if anthropicBody == nil {
+		anthropicBody = raw
+	}
+	message := string(anthropicBody)
+	var anthropicError anthropic.ErrorResponse
+	if json.Unmarshal(anthropicBody, &anthropicError) == nil && anthropicError.Error.Message != "" {
+		message = anthropicError.Error.Message
+	}
+	return geminiErrorResponse(respHeaders[statusHeaderName], message)
+}
+
+// generateContentToAnthropicMessagesRequest converts a Gemini generateContent request to an Anthropic Messages
+// request.
+func generateContentToAnthropicMessagesRequest(req *gcp.GenerateContentRequest) (*anthropic.MessagesRequest, error) {
+	out := &anthropic.MessagesRequest{
+		Model:     req.Model,
+		MaxTokens: defaultGenerateContentMaxTokens,
+		Stream:    req.Stream,
+	}
+	if req.SystemInstruction != nil {
+		var texts []string
+		for _, part := range req.SystemInstruction.Parts {
+			if part != nil && part.Text != "" {
+				texts = append(texts, part.Text)
+			}
+		}
+		if len(texts) > 0 {
+			out.System = &anthropic.SystemPrompt{Text: strings.Join(texts, "\n")}
+		}
+	}
+
+	// Gemini does not require the IDs of the function calls, so the IDs are generated when missing and
+	// the function responses without ID are matched with the pending calls of the same name in order.
+	var generatedIDs int
+	pendingIDs := map[string][]string{}
+	for i := range req.Contents {
+		content := &req.Contents[i]
+		role := anthropic.MessageRoleUser
+		if content.Role == genai.RoleModel {
+			role = anthropic.MessageRoleAssistant
+		}
+		blocks := make([]anthropic.ContentBlockParam, 0, len(content.Parts))
+		for j, part := range content.Parts {
+			if part == nil {
+				continue
+			}
+			switch {
+			case part.FunctionCall != nil:
+				id := part.FunctionCall.ID
+				if id == "" {
+					generatedIDs++
+					id = "toolu_" + strconv.Itoa(generatedIDs)
+				}
+				pendingIDs[part.FunctionCall.Name] = append(pendingIDs[part.FunctionCall.Name], id)
+				input := part.FunctionCall.Args
+				if input == nil {
+					input = map[string]any{}
+				}
+				blocks = append(blocks, anthropic.ContentBlockParam{ToolUse: &anthropic.ToolUseBlockParam{
+					Type: "tool_use", ID: id, Name: part.FunctionCall.Name, Input: input,
+				}})
+			case part.FunctionResponse != nil:
+				fr := part.FunctionResponse
+				id := fr.ID
+				if pending := pendingIDs[fr.Name]; id == "" && len(pending) > 0 {
+					id, pendingIDs[fr.Name] = pending[0], pending[1:]
+				}
+				if id == "" {
+					return nil, fmt.Errorf("%w: function response %q at contents[%d].parts[%d] does not match any function call", internalapi.ErrInvalidRequestBody, fr.Name, i, j)
+				}
+				result, err := geminiFunctionResponseToAnthropic(id, fr.Response)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, result)
+			case part.Thought:
+				// Only signed thoughts can be replayed to the backend, others are dropped.
+				if len(part.ThoughtSignature) > 0 {
+					blocks = append(blocks, anthropic.ContentBlockParam{Thinking: &anthropic.ThinkingBlockParam{
+						Type: "thinking", Thinking: part.Text, Signature: base64.StdEncoding.EncodeToString(part.ThoughtSignature),
+					}})
+				}
+			case part.InlineData != nil:
+				block, err := geminiInlineDataToAnthropic(part.InlineData)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, block)
+			case part.FileData != nil:
+				block, err := geminiFileDataToAnthropic(part.FileData)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, block)
+			case part.Text != "":
+				blocks = append(blocks, anthropicTextBlock(part.Text))
+			default:
+				return nil, fmt.Errorf("%w: unsupported part at contents[%d].parts[%d]", internalapi.ErrInvalidRequestBody, i, j)
+			}
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+		// The Messages API expects alternating roles, so consecutive contents of the same role are merged.
+		if n := len(out.Messages); n > 0 && out.Messages[n-1].Role == role {
+			out.Messages[n-1].Content.Array = append(out.Messages[n-1].Content.Array, blocks...)
+			continue
+		}
+		out.Messages = append(out.Messages, anthropic.MessageParam{Role: role, Content: anthropic.MessageContent{Array: blocks}})
+	}
+
+	var err error
+	if out.Tools, err = geminiToolsToAnthropic(req.Tools); err != nil {
+		return nil, err
+	}
+	if req.ToolConfig != nil {
+		out.ToolChoice = geminiFunctionCallingConfigToAnthropic(req.ToolConfig.FunctionCallingConfig)
+	}
+	if err = applyGeminiGenerationConfig(out, req.GenerationConfig); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// applyGeminiGenerationConfig applies the Gemini generation config to the Anthropic request.
+func applyGeminiGenerationConfig(out *anthropic.MessagesRequest, config *genai.GenerationConfig) error {
+	if config == nil {
+		return nil
+	}
+	if config.CandidateCount > 1 {
+		return fmt.Errorf("%w: candidateCount greater than 1 is not supported by this backend", internalapi.ErrInvalidRequestBody)
+	}
+	if config.ResponseSchema != nil || config.ResponseJsonSchema != nil {
+		return fmt.Errorf("%w: responseSchema is not supported by this backend", internalapi.ErrInvalidRequestBody)
+	}
+	if config.Temperature != nil {
+		out.Temperature = ptr.To(float64(*config.Temperature))
+	}
+	if config.TopP != nil {
+		out.TopP = ptr.To(float64(*config.TopP))
+	}
+	if config.TopK != nil {
+		out.TopK = ptr.To(int(*config.TopK))
+	}
+	out.StopSequences = config.StopSequences
+
+	maxOutputTokens := int64(config.MaxOutputTokens)
+	if tc := config.ThinkingConfig; tc != nil && tc.ThinkingBudget != nil {
+		switch budget := int64(*tc.ThinkingBudget); {
+		case budget == 0:
+			out.Thinking = &anthropic.Thinking{Disabled: &anthropic.ThinkingDisabled{Type: "disabled"}}
+		default:
+			// A negative budget asks for dynamic thinking, for which the smallest budget is used.
+			applyThinkingBudget(out, max(budget, minThinkingBudgetTokens), maxOutputTokens)
+		}
+	}
+	if maxOutputTokens > 0 {
+		out.MaxTokens = float64(maxOutputTokens)
+	}
+	return nil
+}
+
+// geminiFunctionResponseToAnthropic converts the response of a Gemini function response to an Anthropic tool
+// result block. By convention, the output of the function is under the "output" key and the error under the
+// "error" key. Any other response is passed as JSON.
+func geminiFunctionResponseToAnthropic(id string, response map[string]any) (anthropic.ContentBlockParam, error) {
+	result := &anthropic.ToolResultBlockParam{Type: "tool_result", ToolUseID: id}
+	value, isError := response["error"]
+	if !isError {
+		value = response["output"]
+	}
+	if s, ok := value.(string); ok && len(response) == 1 {
+		result.Content = &anthropic.ToolResultContent{Text: s}
+	} else {
+		if value == nil || len(response) != 1 {
+			value = response
+		}
+		marshaled, err := json.Marshal(value)
+		if err != nil {
+			return anthropic.ContentBlockParam{}, fmt.Errorf("%w: failed to marshal function response for %s: %w", internalapi.ErrInvalidRequestBody, id, err)
+		}
+		result.Content = &anthropic.ToolResultContent{Text: string(marshaled)}
+	}
+	result.IsError = isError
+	return anthropic.ContentBlockParam{ToolResult: result}, nil
+}
+
+// geminiInlineDataToAnthropic converts Gemini inline data to an Anthropic image or document block.
+func geminiInlineDataToAnthropic(blob *genai.Blob) (anthropic.ContentBlockParam, error) {
+	data := base64.StdEncoding.EncodeToString(blob.Data)
+	switch {
+	case strings.HasPrefix(blob.MIMEType, "image/"):
+		return anthropic.ContentBlockParam{Image: &anthropic.ImageBlockParam{Type: "image", Source: anthropic.ImageSource{
+			Base64: &anthropic.Base64ImageSource{Type: "base64", MediaType: blob.MIMEType, Data: data},
+		}}}, nil
+	case blob.MIMEType == "application/pdf":
+		return anthropic.ContentBlockParam{Document: &anthropic.DocumentBlockParam{Type: "document", Source: anthropic.DocumentSource{
+			Base64PDF: &anthropic.Base64PDFSource{Type: "base64", MediaType: blob.MIMEType, Data: data},
+		}}}, nil
+	case blob.MIMEType == "text/plain":
+		return anthropic.ContentBlockParam{Document: &anthropic.DocumentBlockParam{Type: "document", Source: anthropic.DocumentSource{
+			PlainText: &anthropic.PlainTextSource{Type: "text", MediaType: blob.MIMEType, Data: string(blob.Data)},
+		}}}, nil
+	default:
+		return anthropic.ContentBlockParam{}, fmt.Errorf("%w: unsupported inline data MIME type %q", internalapi.ErrInvalidRequestBody, blob.MIMEType)
+	}
+}
+
+// geminiFileDataToAnthropic converts Gemini file data to an Anthropic image or document block referenced by URL.
+func geminiFileDataToAnthropic(file *genai.FileData) (anthropic.ContentBlockParam, error) {
+	if !strings.HasPrefix(file.FileURI, "https://") && !strings.HasPrefix(file.FileURI, "http://") {
+		return anthropic.ContentBlockParam{}, fmt.Errorf("%w: only HTTP(S) file URIs are supported by this backend, got %q", internalapi.ErrInvalidRequestBody, file.FileURI)
+	}
+	switch {
+	case file.MIMEType == "application/pdf":
+		return anthropic.ContentBlockParam{Document: &anthropic.DocumentBlockParam{Type: "document", Source: anthropic.DocumentSource{
+			URL: &anthropic.URLPDFSource{Type: "url", URL: file.FileURI},
+		}}}, nil
+	case file.MIMEType == "" || strings.HasPrefix(file.MIMEType, "image/"):
+		return anthropic.ContentBlockParam{Image: &anthropic.ImageBlockParam{Type: "image", Source: anthropic.ImageSource{
+			URL: &anthropic.URLImageSource{Type: "url", URL: file.FileURI},
+		}}}, nil
+	default:
+		return anthropic.ContentBlockParam{}, fmt.Errorf("%w: unsupported file data MIME type %q", internalapi.ErrInvalidRequestBody, file.MIMEType)
+	}
+}
+
+// geminiToolsToAnthropic converts the Gemini function declarations to Anthropic custom tools.
+func geminiToolsToAnthropic(tools []genai.Tool) ([]anthropic.ToolUnion, error) {
+	var out []anthropic.ToolUnion
+	for i := range tools {
+		tool := &tools[i]
+		if len(tool.FunctionDeclarations) == 0 {
+			return nil, fmt.Errorf("%w: only function declarations are supported by this backend, got unsupported tool at index %d", internalapi.ErrInvalidRequestBody, i)
+		}
+		for _, fn := range tool.FunctionDeclarations {
+			parameters, err := geminiFunctionParameters(fn)
+			if err != nil {
+				return nil, err
+			}
+			schema := anthropic.ToolInputSchema{Type: "object"}
+			if props, ok := parameters["properties"].(map[string]any); ok {
+				schema.Properties = props
+			}
+			if required, ok := parameters["required"].([]any); ok {
+				for _, r := range required {
+					if s, ok := r.(string); ok {
+						schema.Required = append(schema.Required, s)
+					}
+				}
+			}
+			out = append(out, anthropic.ToolUnion{Tool: &anthropic.Tool{
+				Type: "custom", Name: fn.Name, Description: fn.Description, InputSchema: schema,
+			}})
+		}
+	}
+	return out, nil
+}
+
+// geminiFunctionParameters returns the parameters of the function declaration as a JSON schema object.
+// The OpenAPI schema of parametersJsonSchema is used as is, while the genai.Schema of parameters has the
+// types in upper case, which are lowered to match the JSON schema.
+func geminiFunctionParameters(fn *genai.FunctionDeclaration) (map[string]any, error) {
+	var source any
+	switch {
+	case fn.ParametersJsonSchema != nil:
+		source = fn.ParametersJsonSchema
+	case fn.Parameters != nil:
+		source = fn.Parameters
+	default:
+		return nil, nil
+	}
+	marshaled, err := json.Marshal(source)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to marshal parameters of function %s: %w", internalapi.ErrInvalidRequestBody, fn.Name, err)
+	}
+	var parameters map[string]any
+	if err = json.Unmarshal(marshaled, &parameters); err != nil {
+		return nil, fmt.Errorf("%w: invalid parameters of function %s: %w", internalapi.ErrInvalidRequestBody, fn.Name, err)
+	}
+	if fn.ParametersJsonSchema == nil {
+		lowerSchemaTypes(parameters)
+	}
+	return parameters, nil
+}
+
+// lowerSchemaTypes recursively lowers the "type" values of the schema.
+func lowerSchemaTypes(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if s, ok := value.(string); ok && key == "type" {
+				v[key] = strings.ToLower(s)
+				continue
+			}
+			lowerSchemaTypes(value)
+		}
+	case []any:
+		for _, item := range v {
+			lowerSchemaTypes(item)
+		}
+	}
+}
+
+// geminiFunctionCallingConfigToAnthropic converts the Gemini function calling config to the Anthropic tool choice.
+func geminiFunctionCallingConfigToAnthropic(config *genai.FunctionCallingConfig) *anthropic.ToolChoice {
+	if config == nil {
+		return nil
+	}
+	switch config.Mode {
+	case genai.FunctionCallingConfigModeAny:
+		if len(config.AllowedFunctionNames) == 1 {
+			return &anthropic.ToolChoice{Tool: &anthropic.ToolChoiceTool{Type: "tool", Name: config.AllowedFunctionNames[0]}}
+		}
+		return &anthropic.ToolChoice{Any: &anthropic.ToolChoiceAny{Type: "any"}}
+	case genai.FunctionCallingConfigModeNone:
+		return &anthropic.ToolChoice{None: &anthropic.ToolChoiceNone{Type: "none"}}
+	case genai.FunctionCallingConfigModeAuto, genai.FunctionCallingConfigModeValidated:
+		return &anthropic.ToolChoice{Auto: &anthropic.ToolChoiceAuto{Type: "auto"}}
+	default:
+		return nil
+	}
+}
+
+// anthropicToGeminiResponse converts an Anthropic Messages response to a Gemini generateContent response.
+func anthropicToGeminiResponse(resp *anthropic.MessagesResponse, model string) *genai.GenerateContentResponse {
+	content := &genai.Content{Role: genai.RoleModel}
+	for _, block := range resp.Content {
+		if part := anthropicContentBlockToGeminiPart(&block); part != nil {
+			content.Parts = append(content.Parts, part)
+		}
+	}
+	out := &genai.GenerateContentResponse{
+		ResponseID:   resp.ID,
+		ModelVersion: model,
+		Candidates: []*genai.Candidate{{
+			Content:      content,
+			FinishReason: anthropicStopReasonToGemini(resp.StopReason),
+		}},
+	}
+	if resp.Usage != nil {
+		out.UsageMetadata = anthropicUsageToGemini(resp.Usage)
+	}
+	return out
+}
+
+// anthropicContentBlockToGeminiPart converts an Anthropic response content block to a Gemini part.
+// Blocks without a Gemini equivalent, such as server tool results, are skipped.
+func anthropicContentBlockToGeminiPart(block *anthropic.MessagesContentBlock) *genai.Part {
+	switch {
+	case block.Text != nil:
+		return &genai.Part{Text: block.Text.Text}
+	case block.Thinking != nil:
+		part := &genai.Part{Text: block.Thinking.Thinking, Thought: true}
+		if signature, err := base64.StdEncoding.DecodeString(block.Thinking.Signature); err == nil {
+			part.ThoughtSignature = signature
+		}
+		return part
+	case block.Tool != nil:
+		return &genai.Part{FunctionCall: &genai.FunctionCall{ID: block.Tool.ID, Name: block.Tool.Name, Args: block.Tool.Input}}
+	default:
+		return nil
+	}
+}
+
+// anthropicStopReasonToGemini converts the Anthropic stop reason to the Gemini finish reason.
+func anthropicStopReasonToGemini(reason *anthropic.StopReason) genai.FinishReason {
+	if reason == nil {
+		return genai.FinishReasonStop
+	}
+	switch *reason {
+	case anthropic.StopReasonMaxTokens, anthropic.StopReasonModelContextWindowExceeded:
+		return genai.FinishReasonMaxTokens
+	case anthropic.StopReasonRefusal:
+		return genai.FinishReasonSafety
+	default:
+		return genai.FinishReasonStop
+	}
+}
+
+// anthropicUsageToGemini converts the Anthropic usage to the Gemini usage metadata.
+// Anthropic excludes the cached tokens from the input tokens while Gemini includes them in the prompt tokens.
+func anthropicUsageToGemini(u *anthropic.Usage) *genai.GenerateContentResponseUsageMetadata {
+	prompt := int32(u.InputTokens + u.CacheReadInputTokens + u.CacheCreationInputTokens)
+	candidates := int32(u.OutputTokens)
+	return &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:        prompt,
+		CachedContentTokenCount: int32(u.CacheReadInputTokens),
+		CandidatesTokenCount:    candidates,
+		TotalTokenCount:         prompt + candidates,
+	}
+}
+
+// anthropicToGeminiStreamState tracks the state for converting Anthropic stream events to Gemini stream chunks.
+//
+// Text and thinking deltas are emitted as they arrive. The input of a tool use is streamed as partial JSON,
+// so the function call is emitted once its content block stops. The last chunk carries the finish reason and
+// the usage metadata.
+type anthropicToGeminiStreamState struct {
+	buffered   []byte
+	model      string
+	responseID string
+	usage      anthropic.Usage
+	stopReason *anthropic.StopReason
+	// toolUses holds the tool uses being streamed by content block index.
+	toolUses map[int]*anthropicStreamedToolUse
+	done     bool
+}
+
+// anthropicStreamedToolUse is a tool use whose input is being streamed.
+type anthropicStreamedToolUse struct {
+	id, name string
+	input    strings.Builder
+}
+
+// convert converts the Anthropic SSE events in the body to Gemini SSE chunks.
+func (s *anthropicToGeminiStreamState) convert(body []byte, endOfStream bool, responseModel string, span tracingapi.GenerateContentSpan) ([]byte, error) {
+	s.model = cmp.Or(responseModel, s.model)
+	s.buffered = append(s.buffered, body...)
+	var out []byte
+	err := scanAnthropicStreamEvents(&s.buffered,
+		func(chunk *anthropic.MessagesStreamChunk) error {
+			return s.handleChunk(chunk, &out, span)
+		},
+		func(errEvent *anthropic.ErrorResponse) error {
+			_, errBody, err := geminiErrorResponse("500", errEvent.Error.Message)
+			if err != nil {
+				return err
+			}
+			out = append(out, sseDataPrefix...)
+			out = append(out, errBody...)
+			out = append(out, '\n', '\n')
+			s.done = true
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if endOfStream && !s.done {
+		// The backend closed the stream without message_stop, e.g. on an upstream timeout.
+		if err := s.complete(&out, span); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// handleChunk converts a single Anthropic stream event.
+func (s *anthropicToGeminiStreamState) handleChunk(chunk *anthropic.MessagesStreamChunk, out *[]byte, span tracingapi.GenerateContentSpan) error {
+	switch {
+	case chunk.MessageStart != nil:
+		s.responseID = chunk.MessageStart.ID
+		s.model = cmp.Or(s.model, chunk.MessageStart.Model)
+		if u := chunk.MessageStart.Usage; u != nil {
+			s.usage = *u
+		}
+	case chunk.ContentBlockStart != nil:
+		if tool := chunk.ContentBlockStart.ContentBlock.Tool; tool != nil {
+			if s.toolUses == nil {
+				s.toolUses = make(map[int]*anthropicStreamedToolUse)
+			}
+			s.toolUses[chunk.ContentBlockStart.Index] = &anthropicStreamedToolUse{id: tool.ID, name: tool.Name}
+		}
+	case chunk.ContentBlockDelta != nil:
+		delta := &chunk.ContentBlockDelta.Delta
+		switch {
+		case delta.Text != "":
+			return s.emit(&genai.Part{Text: delta.Text}, out, span)
+		case delta.Thinking != "":
+			return s.emit(&genai.Part{Text: delta.Thinking, Thought: true}, out, span)
+		case delta.Signature != "":
+			signature, err := base64.StdEncoding.DecodeString(delta.Signature)
+			if err != nil {
+				return nil
+			}
+			return s.emit(&genai.Part{Thought: true, ThoughtSignature: signature}, out, span)
+		case delta.PartialJSON != "":
+			if tool, ok := s.toolUses[chunk.ContentBlockDelta.Index]; ok {
+				tool.input.WriteString(delta.PartialJSON)
+			}
+		}
+	case chunk.ContentBlockStop != nil:
+		tool, ok := s.toolUses[chunk.ContentBlockStop.Index]
+		if !ok {
+			return nil
+		}
+		delete(s.toolUses, chunk.ContentBlockStop.Index)
+		args := map[string]any{}
+		if tool.input.Len() > 0 {
+			if err := json.Unmarshal([]byte(tool.input.String()), &args); err != nil {
+				return fmt.Errorf("failed to unmarshal tool input of %s: %w", tool.name, err)
+			}
+		}
+		return s.emit(&genai.Part{FunctionCall: &genai.FunctionCall{ID: tool.id, Name: tool.name, Args: args}}, out, span)
+	case chunk.MessageDelta != nil:
+		// The usage of message_delta is cumulative.
+		u := chunk.MessageDelta.Usage
+		s.usage.OutputTokens = u.OutputTokens
+		if u.InputTokens > 0 {
+			s.usage.InputTokens = u.InputTokens
+			s.usage.CacheReadInputTokens = u.CacheReadInputTokens
+			s.usage.CacheCreationInputTokens = u.CacheCreationInputTokens
+		}
+		if reason := chunk.MessageDelta.Delta.StopReason; reason != "" {
+			s.stopReason = &reason
+		}
+	case chunk.MessageStop != nil:
+		return s.complete(out, span)
+	}
+	return nil
+}
+
+// complete emits the last chunk with the finish reason and the usage metadata.
+func (s *anthropicToGeminiStreamState) complete(out *[]byte, span tracingapi.GenerateContentSpan) error {
+	s.done = true
+	resp := s.chunk(nil, anthropicStopReasonToGemini(s.stopReason))
+	resp.UsageMetadata = anthropicUsageToGemini(&s.usage)
+	return s.write(resp, out, span)
+}
+
+// emit emits a chunk with the given part.
+func (s *anthropicToGeminiStreamState) emit(part *genai.Part, out *[]byte, span tracingapi.GenerateContentSpan) error {
+	return s.write(s.chunk(part, ""), out, span)
+}
+
+// chunk returns a Gemini stream chunk with the given part, if any.
+func (s *anthropicToGeminiStreamState) chunk(part *genai.Part, finishReason genai.FinishReason) *genai.GenerateContentResponse {
+	content := &genai.Content{Role: genai.RoleModel}
+	if part != nil {
+		content.Parts = []*genai.Part{part}
+	}
+	return &genai.GenerateContentResponse{
+		ResponseID:   s.responseID,
+		ModelVersion: s.model,
+		Candidates:   []*genai.Candidate{{Content: content, FinishReason: finishReason}},
+	}
+}
+
+// write writes the chunk as an SSE event and records it to the span.
+func (s *anthropicToGeminiStreamState) write(resp *genai.GenerateContentResponse, out *[]byte, span tracingapi.GenerateContentSpan) error {
+	marshaled, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stream chunk: %w", err)
+	}
+	*out = append(*out, sseDataPrefix...)
+	*out = append(*out, marshaled...)
+	*out = append(*out, '\n', '\n')
+	if span != nil {
+		span.RecordResponseChunk(resp)
+	}
+	return nil
+}
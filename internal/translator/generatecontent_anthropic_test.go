@@ -0,0 +1,455 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestGenerateContentToAnthropicMessagesRequest(t *testing.T) {
+	req := &gcp.GenerateContentRequest{
+		Model:             "claude-sonnet-4-5",
+		Stream:            true,
+		SystemInstruction: &genai.Content{Parts: []*genai.Part{{Text: "be nice"}, {Text: "answer briefly"}}},
+		Contents: []genai.Content{
+			{Role: genai.RoleUser, Parts: []*genai.Part{
+				{Text: "what is cos(7)?"},
+				{InlineData: &genai.Blob{MIMEType: "image/png", Data: []byte("hello")}},
+			}},
+			{Role: genai.RoleUser, Parts: []*genai.Part{
+				{FileData: &genai.FileData{MIMEType: "application/pdf", FileURI: "https://example.com/doc.pdf"}},
+			}},
+			{Role: genai.RoleModel, Parts: []*genai.Part{
+				{Text: "use the tool", Thought: true, ThoughtSignature: []byte("sig")},
+				{Text: "unsigned thought", Thought: true},
+				{FunctionCall: &genai.FunctionCall{Name: "cosine", Args: map[string]any{"x": 7}}},
+			}},
+			{Role: genai.RoleUser, Parts: []*genai.Part{
+				{FunctionResponse: &genai.FunctionResponse{Name: "cosine", Response: map[string]any{"output": "0.75"}}},
+			}},
+		},
+		Tools: []genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{{
+			Name:        "cosine",
+			Description: "computes the cosine",
+			Parameters: &genai.Schema{
+				Type:       genai.TypeObject,
+				Properties: map[string]*genai.Schema{"x": {Type: genai.TypeNumber}},
+				Required:   []string{"x"},
+			},
+		}}}},
+		ToolConfig: &genai.ToolConfig{FunctionCallingConfig: &genai.FunctionCallingConfig{Mode: genai.FunctionCallingConfigModeAuto}},
+		GenerationConfig: &genai.GenerationConfig{
+			Temperature:     ptr.To(float32(0.5)),
+			TopK:            ptr.To(float32(40)),
+			StopSequences:   []string{"END"},
+			MaxOutputTokens: 8192,
+			ThinkingConfig:  &genai.ThinkingConfig{ThinkingBudget: ptr.To(int32(2048))},
+		},
+	}
+	out, err := generateContentToAnthropicMessagesRequest(req)
+	require.NoError(t, err)
+
+	require.Equal(t, "claude-sonnet-4-5", out.Model)
+	require.True(t, out.Stream)
+	require.Equal(t, "be nice\nanswer briefly", out.System.Text)
+	require.Equal(t, float64(8192), out.MaxTokens)
+	require.Equal(t, ptr.To(0.5), out.Temperature)
+	require.Equal(t, ptr.To(40), out.TopK)
+	require.Equal(t, []string{"END"}, out.StopSequences)
+	require.Equal(t, &anthropic.Thinking{Enabled: &anthropic.ThinkingEnabled{Type: "enabled", BudgetTokens: 2048}}, out.Thinking)
+	require.Equal(t, &anthropic.ToolChoice{Auto: &anthropic.ToolChoiceAuto{Type: "auto"}}, out.ToolChoice)
+	require.Equal(t, []anthropic.ToolUnion{{Tool: &anthropic.Tool{
+		Type:        "custom",
+		Name:        "cosine",
+		Description: "computes the cosine",
+		InputSchema: anthropic.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]any{"x": map[string]any{"type": "number"}},
+			Required:   []string{"x"},
+		},
+	}}}, out.Tools)
+
+	// The consecutive user contents are merged into a single message.
+	require.Len(t, out.Messages, 3)
+	require.Equal(t, anthropic.MessageRoleUser, out.Messages[0].Role)
+	require.Equal(t, []anthropic.ContentBlockParam{
+		anthropicTextBlock("what is cos(7)?"),
+		{Image: &anthropic.ImageBlockParam{Type: "image", Source: anthropic.ImageSource{
+			Base64: &anthropic.Base64ImageSource{Type: "base64", MediaType: "image/png", Data: "aGVsbG8="},
+		}}},
+		{Document: &anthropic.DocumentBlockParam{Type: "document", Source: anthropic.DocumentSource{
+			URL: &anthropic.URLPDFSource{Type: "url", URL: "https://example.com/doc.pdf"},
+		}}},
+	}, out.Messages[0].Content.Array)
+
+	require.Equal(t, anthropic.MessageRoleAssistant, out.Messages[1].Role)
+	require.Equal(t, []anthropic.ContentBlockParam{
+		{Thinking: &anthropic.ThinkingBlockParam{Type: "thinking", Thinking: "use the tool", Signature: "c2ln"}},
+		{ToolUse: &anthropic.ToolUseBlockParam{Type: "tool_use", ID: "toolu_1", Name: "cosine", Input: map[string]any{"x": 7}}},
+	}, out.Messages[1].Content.Array)
+
+	require.Equal(t, anthropic.MessageRoleUser, out.Messages[2].Role)
+	require.Equal(t, []anthropic.ContentBlockParam{
+		{ToolResult: &anthropic.ToolResultBlockParam{Type: "tool_result", ToolUseID: "toolu_1", Content: &anthropic.ToolResultContent{Text: "0.75"}}},
+	}, out.Messages[2].Content.Array)
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			name   string
+			req    gcp.GenerateContentRequest
+			expErr string
+		}{
+			{
+				name: "unmatched function response",
+				req: gcp.GenerateContentRequest{Contents: []genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{
+					{FunctionResponse: &genai.FunctionResponse{Name: "cosine", Response: map[string]any{"output": "1"}}},
+				}}}},
+				expErr: `function response "cosine" at contents[0].parts[0] does not match any function call`,
+			},
+			{
+				name: "unsupported inline data",
+				req: gcp.GenerateContentRequest{Contents: []genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{
+					{InlineData: &genai.Blob{MIMEType: "audio/wav", Data: []byte("a")}},
+				}}}},
+				expErr: `unsupported inline data MIME type "audio/wav"`,
+			},
+			{
+				name: "gcs file uri",
+				req: gcp.GenerateContentRequest{Contents: []genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{
+					{FileData: &genai.FileData{MIMEType: "application/pdf", FileURI: "gs://bucket/doc.pdf"}},
+				}}}},
+				expErr: "only HTTP(S) file URIs are supported",
+			},
+			{
+				name: "unsupported tool",
+				req: gcp.GenerateContentRequest{
+					Contents: []genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{{Text: "hi"}}}},
+					Tools:    []genai.Tool{{GoogleSearch: &genai.GoogleSearch{}}},
+				},
+				expErr: "only function declarations are supported",
+			},
+			{
+				name: "candidate count",
+				req: gcp.GenerateContentRequest{
+					Contents:         []genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{{Text: "hi"}}}},
+					GenerationConfig: &genai.GenerationConfig{CandidateCount: 2},
+				},
+				expErr: "candidateCount greater than 1 is not supported",
+			},
+			{
+				name: "response schema",
+				req: gcp.GenerateContentRequest{
+					Contents:         []genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{{Text: "hi"}}}},
+					GenerationConfig: &genai.GenerationConfig{ResponseSchema: &genai.Schema{Type: genai.TypeObject}},
+				},
+				expErr: "responseSchema is not supported",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := generateContentToAnthropicMessagesRequest(&tc.req)
+				require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+				require.ErrorContains(t, err, tc.expErr)
+			})
+		}
+	})
+}
+
+func TestApplyGeminiGenerationConfig_Thinking(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		config       genai.GenerationConfig
+		expMaxTokens float64
+		expThinking  *anthropic.Thinking
+		expTopP      *float64
+	}{
+		{
+			name:         "disabled",
+			config:       genai.GenerationConfig{ThinkingConfig: &genai.ThinkingConfig{ThinkingBudget: ptr.To(int32(0))}},
+			expMaxTokens: defaultGenerateContentMaxTokens,
+			expThinking:  &anthropic.Thinking{Disabled: &anthropic.ThinkingDisabled{Type: "disabled"}},
+		},
+		{
+			name:         "dynamic without max output tokens",
+			config:       genai.GenerationConfig{ThinkingConfig: &genai.ThinkingConfig{ThinkingBudget: ptr.To(int32(-1))}},
+			expMaxTokens: defaultGenerateContentMaxTokens + minThinkingBudgetTokens,
+			expThinking:  &anthropic.Thinking{Enabled: &anthropic.ThinkingEnabled{Type: "enabled", BudgetTokens: minThinkingBudgetTokens}},
+		},
+		{
+			name:         "budget capped by max output tokens",
+			config:       genai.GenerationConfig{MaxOutputTokens: 2000, ThinkingConfig: &genai.ThinkingConfig{ThinkingBudget: ptr.To(int32(4000))}},
+			expMaxTokens: 2000,
+			expThinking:  &anthropic.Thinking{Enabled: &anthropic.ThinkingEnabled{Type: "enabled", BudgetTokens: 1999}},
+		},
+		{
+			name:         "max output tokens too small for thinking",
+			config:       genai.GenerationConfig{MaxOutputTokens: 500, TopP: ptr.To(float32(0.5)), ThinkingConfig: &genai.ThinkingConfig{ThinkingBudget: ptr.To(int32(4000))}},
+			expMaxTokens: 500,
+			expTopP:      ptr.To(0.5),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &anthropic.MessagesRequest{MaxTokens: defaultGenerateContentMaxTokens}
+			require.NoError(t, applyGeminiGenerationConfig(out, &tc.config))
+			require.Equal(t, tc.expMaxTokens, out.MaxTokens)
+			require.Equal(t, tc.expThinking, out.Thinking)
+			require.Equal(t, tc.expTopP, out.TopP)
+		})
+	}
+}
+
+func TestGeminiFunctionResponseToAnthropic(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		response   map[string]any
+		expText    string
+		expIsError bool
+	}{
+		{name: "output string", response: map[string]any{"output": "sunny"}, expText: "sunny"},
+		{name: "output object", response: map[string]any{"output": map[string]any{"temp": 20}}, expText: `{"temp":20}`},
+		{name: "error string", response: map[string]any{"error": "not found"}, expText: "not found", expIsError: true},
+		{name: "arbitrary object", response: map[string]any{"temp": 20, "unit": "C"}, expText: `{"temp":20,"unit":"C"}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			block, err := geminiFunctionResponseToAnthropic("toolu_1", tc.response)
+			require.NoError(t, err)
+			require.Equal(t, "toolu_1", block.ToolResult.ToolUseID)
+			require.Equal(t, tc.expText, block.ToolResult.Content.Text)
+			require.Equal(t, tc.expIsError, block.ToolResult.IsError)
+		})
+	}
+}
+
+func TestGeminiFunctionCallingConfigToAnthropic(t *testing.T) {
+	require.Nil(t, geminiFunctionCallingConfigToAnthropic(nil))
+	require.Equal(t, &anthropic.ToolChoice{Any: &anthropic.ToolChoiceAny{Type: "any"}},
+		geminiFunctionCallingConfigToAnthropic(&genai.FunctionCallingConfig{Mode: genai.FunctionCallingConfigModeAny}))
+	require.Equal(t, &anthropic.ToolChoice{Tool: &anthropic.ToolChoiceTool{Type: "tool", Name: "cosine"}},
+		geminiFunctionCallingConfigToAnthropic(&genai.FunctionCallingConfig{Mode: genai.FunctionCallingConfigModeAny, AllowedFunctionNames: []string{"cosine"}}))
+	require.Equal(t, &anthropic.ToolChoice{None: &anthropic.ToolChoiceNone{Type: "none"}},
+		geminiFunctionCallingConfigToAnthropic(&genai.FunctionCallingConfig{Mode: genai.FunctionCallingConfigModeNone}))
+}
+
+func TestGenerateContentToAnthropicTranslator_RequestBody(t *testing.T) {
+	tr := NewGenerateContentToAnthropicTranslator("", "claude-override")
+	headers, body, err := tr.RequestBody(nil, &gcp.GenerateContentRequest{
+		Model:    "claude-sonnet-4-5",
+		Contents: []genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{{Text: "hi"}}}},
+	}, false)
+	require.NoError(t, err)
+	require.Equal(t, internalapi.Header{pathHeaderName, "/v1/messages"}, headers[0])
+	var anthropicReq anthropic.MessagesRequest
+	require.NoError(t, json.Unmarshal(body, &anthropicReq))
+	require.Equal(t, "claude-override", anthropicReq.Model)
+	require.Equal(t, float64(defaultGenerateContentMaxTokens), anthropicReq.MaxTokens)
+}
+
+func TestGenerateContentToAnthropicTranslator_ResponseBody_NonStreaming(t *testing.T) {
+	tr := NewGenerateContentToAnthropicTranslator("", "")
+	_, _, err := tr.RequestBody(nil, &gcp.GenerateContentRequest{
+		Model:    "claude-sonnet-4-5",
+		Contents: []genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{{Text: "what is cos(7)?"}}}},
+	}, false)
+	require.NoError(t, err)
+
+	anthropicResp := `{"id":"msg_01","type":"message","role":"assistant","model":"claude-sonnet-4-5-20250929",
+"content":[{"type":"thinking","thinking":"use the tool","signature":"c2ln"},{"type":"text","text":"Let me compute."},{"type":"tool_use","id":"toolu_1","name":"cosine","input":{"x":7}}],
+"stop_reason":"tool_use","usage":{"input_tokens":10,"output_tokens":20,"cache_read_input_tokens":5,"cache_creation_input_tokens":3}}`
+	span := &mockGenerateContentSpan{}
+	headers, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(anthropicResp), true, span)
+	require.NoError(t, err)
+	require.Equal(t, "claude-sonnet-4-5-20250929", responseModel)
+	require.Len(t, headers, 1)
+	require.Equal(t, contentLengthHeaderName, headers[0].Key())
+	inputTokens, _ := tokenUsage.InputTokens()
+	require.Equal(t, uint32(18), inputTokens)
+	require.NotNil(t, span.recordedResponse)
+
+	var resp genai.GenerateContentResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	require.Equal(t, "msg_01", resp.ResponseID)
+	require.Equal(t, "claude-sonnet-4-5-20250929", resp.ModelVersion)
+	require.Equal(t, &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:        18,
+		CachedContentTokenCount: 5,
+		CandidatesTokenCount:    20,
+		TotalTokenCount:         38,
+	}, resp.UsageMetadata)
+	require.Len(t, resp.Candidates, 1)
+	require.Equal(t, genai.FinishReasonStop, resp.Candidates[0].FinishReason)
+	require.Equal(t, []*genai.Part{
+		{Text: "use the tool", Thought: true, ThoughtSignature: []byte("sig")},
+		{Text: "Let me compute."},
+		{FunctionCall: &genai.FunctionCall{ID: "toolu_1", Name: "cosine", Args: map[string]any{"x": float64(7)}}},
+	}, resp.Candidates[0].Content.Parts)
+
+	t.Run("max tokens", func(t *testing.T) {
+		_, body, _, _, err := tr.ResponseBody(nil, strings.NewReader(
+			`{"id":"msg_02","type":"message","role":"assistant","model":"claude","content":[{"type":"text","text":"Hel"}],"stop_reason":"max_tokens","usage":{"input_tokens":1,"output_tokens":1}}`,
+		), true, nil)
+		require.NoError(t, err)
+		var resp genai.GenerateContentResponse
+		require.NoError(t, json.Unmarshal(body, &resp))
+		require.Equal(t, genai.FinishReasonMaxTokens, resp.Candidates[0].FinishReason)
+	})
+}
+
+func TestGenerateContentToAnthropicTranslator_ResponseBody_Streaming(t *testing.T) {
+	tr := NewGenerateContentToAnthropicTranslator("", "")
+	_, _, err := tr.RequestBody(nil, &gcp.GenerateContentRequest{
+		Model:    "claude-sonnet-4-5",
+		Stream:   true,
+		Contents: []genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{{Text: "what is cos(7)?"}}}},
+	}, false)
+	require.NoError(t, err)
+	headers, err := tr.ResponseHeaders(nil)
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}, headers)
+
+	stream := []byte(`event: message_start
+data: {"type":"message_start","message":{"id":"msg_01","type":"message","role":"assistant","content":[],"model":"claude-sonnet-4-5","usage":{"input_tokens":10,"output_tokens":1}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"use the tool"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"c2ln"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: ping
+data: {"type":"ping"}
+
+event: content_block_start
+data: {"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Hello"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":1}
+
+event: content_block_start
+data: {"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"toolu_1","name":"cosine","input":{}}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"{\"x\":"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"7}"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":2}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":15}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`)
+	span := &mockGenerateContentSpan{}
+	// Feed the stream in small chunks to exercise the buffering of partial lines.
+	var out []byte
+	for i := 0; i < len(stream); i += 50 {
+		_, body, _, _, err := tr.ResponseBody(nil, bytes.NewReader(stream[i:min(i+50, len(stream))]), false, span)
+		require.NoError(t, err)
+		out = append(out, body...)
+	}
+	_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, bytes.NewReader(nil), true, span)
+	require.NoError(t, err)
+	out = append(out, body...)
+	require.Equal(t, "claude-sonnet-4-5", responseModel)
+	outputTokens, _ := tokenUsage.OutputTokens()
+	require.Equal(t, uint32(15), outputTokens)
+
+	var chunks []genai.GenerateContentResponse
+	for _, event := range strings.Split(strings.TrimSpace(string(out)), "\n\n") {
+		require.True(t, strings.HasPrefix(event, "data: "), event)
+		var chunk genai.GenerateContentResponse
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(event, "data: ")), &chunk))
+		require.Equal(t, "msg_01", chunk.ResponseID)
+		require.Equal(t, "claude-sonnet-4-5", chunk.ModelVersion)
+		chunks = append(chunks, chunk)
+	}
+	require.Len(t, chunks, 5)
+	require.Len(t, span.recordedChunks, 5)
+	require.Equal(t, &genai.Part{Text: "use the tool", Thought: true}, chunks[0].Candidates[0].Content.Parts[0])
+	require.Equal(t, &genai.Part{Thought: true, ThoughtSignature: []byte("sig")}, chunks[1].Candidates[0].Content.Parts[0])
+	require.Equal(t, &genai.Part{Text: "Hello"}, chunks[2].Candidates[0].Content.Parts[0])
+	require.Equal(t, &genai.Part{FunctionCall: &genai.FunctionCall{ID: "toolu_1", Name: "cosine", Args: map[string]any{"x": float64(7)}}},
+		chunks[3].Candidates[0].Content.Parts[0])
+	require.Empty(t, chunks[3].Candidates[0].FinishReason)
+	require.Equal(t, genai.FinishReasonStop, chunks[4].Candidates[0].FinishReason)
+	require.Equal(t, &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:     10,
+		CandidatesTokenCount: 15,
+		TotalTokenCount:      25,
+	}, chunks[4].UsageMetadata)
+
+	t.Run("error event", func(t *testing.T) {
+		tr := NewGenerateContentToAnthropicTranslator("", "")
+		_, _, err := tr.RequestBody(nil, &gcp.GenerateContentRequest{
+			Stream:   true,
+			Contents: []genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{{Text: "hi"}}}},
+		}, false)
+		require.NoError(t, err)
+		_, body, _, _, err := tr.ResponseBody(nil, strings.NewReader(`event: message_start
+data: {"type":"message_start","message":{"id":"msg_01","type":"message","role":"assistant","content":[],"model":"claude","usage":{"input_tokens":1,"output_tokens":0}}}
+
+event: error
+data: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}
+
+`), true, nil)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"error":{"code":500,"message":"Overloaded","status":"INTERNAL","details":null}}`,
+			strings.TrimPrefix(strings.TrimSpace(string(body)), "data: "))
+	})
+}
+
+func TestGenerateContentToAnthropicTranslator_ResponseError(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		headers  map[string]string
+		body     string
+		expected string
+	}{
+		{
+			name:     "anthropic error",
+			headers:  map[string]string{statusHeaderName: "429", contentTypeHeaderName: jsonContentType},
+			body:     `{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`,
+			expected: `{"error":{"code":429,"message":"slow down","status":"RESOURCE_EXHAUSTED","details":null}}`,
+		},
+		{
+			name:     "non json error",
+			headers:  map[string]string{statusHeaderName: "503"},
+			body:     "upstream connect error",
+			expected: `{"error":{"code":503,"message":"upstream connect error","status":"UNAVAILABLE","details":null}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := NewGenerateContentToAnthropicTranslator("", "")
+			headers, body, err := tr.ResponseError(tc.headers, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expected, string(body))
+			require.Equal(t, internalapi.Header{contentTypeHeaderName, jsonContentType}, headers[0])
+		})
+	}
+}
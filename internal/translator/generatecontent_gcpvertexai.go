@@ -0,0 +1,179 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewGenerateContentToGCPVertexAITranslator implements [Factory] for Gemini generateContent to GCP Vertex AI
+// translation. The request and response bodies are passed through as is.
+func NewGenerateContentToGCPVertexAITranslator(modelNameOverride internalapi.ModelNameOverride) GeminiGenerateContentTranslator {
+	return &generateContentToGCPVertexAITranslator{modelNameOverride: modelNameOverride}
+}
+
+// NewGenerateContentToGoogleAIStudioTranslator implements [Factory] for Gemini generateContent to Gemini Developer API
+// translation. The request and response bodies are passed through as is.
+func NewGenerateContentToGoogleAIStudioTranslator(modelNameOverride internalapi.ModelNameOverride) GeminiGenerateContentTranslator {
+	return &generateContentToGCPVertexAITranslator{modelNameOverride: modelNameOverride, googleAIStudio: true}
+}
+
+// generateContentToGCPVertexAITranslator passes the Gemini API generateContent and streamGenerateContent
+// requests through to the same methods of the Gemini models on Vertex AI. Only the path is rewritten, since
+// Vertex AI addresses the models under the publisher. The token usage is read from the usageMetadata.
+type generateContentToGCPVertexAITranslator struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+	stream            bool
+	streamDelimiter   []byte
+	bufferedBody      []byte // Buffer for incomplete JSON chunks.
+	// streamingResponseModel and streamingTokenUsage are taken from the latest chunks that carry them.
+	streamingResponseModel internalapi.ResponseModel
+	streamingTokenUsage    metrics.TokenUsage
+	// googleAIStudio is true when the backend is the Gemini Developer API instead of Vertex AI.
+	googleAIStudio bool
+}
+
+// RequestBody implements [GeminiGenerateContentTranslator.RequestBody].
+func (g *generateContentToGCPVertexAITranslator) RequestBody(original []byte, req *gcp.GenerateContentRequest, forceBodyMutation bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	g.stream = req.Stream
+	g.requestModel = cmp.Or(g.modelNameOverride, req.Model)
+
+	var path string
+	switch {
+	case g.googleAIStudio && g.stream:
+		path = buildGoogleAIStudioModelPath(g.requestModel, gcpMethodStreamGenerateContent, "alt=sse")
+	case g.googleAIStudio:
+		path = buildGoogleAIStudioModelPath(g.requestModel, gcpMethodGenerateContent)
+	case g.stream:
+		path = buildGCPModelPathSuffix(gcpModelPublisherGoogle, g.requestModel, gcpMethodStreamGenerateContent, "alt=sse")
+	default:
+		path = buildGCPModelPathSuffix(gcpModelPublisherGoogle, g.requestModel, gcpMethodGenerateContent)
+	}
+	newHeaders = []internalapi.Header{{pathHeaderName, path}}
+	if forceBodyMutation {
+		newBody = original
+		newHeaders = append(newHeaders, internalapi.Header{contentLengthHeaderName, strconv.Itoa(len(newBody))})
+	}
+	return
+}
+
+// ResponseHeaders implements [GeminiGenerateContentTranslator.ResponseHeaders].
+func (g *generateContentToGCPVertexAITranslator) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [GeminiGenerateContentTranslator.ResponseBody].
+func (g *generateContentToGCPVertexAITranslator) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.GenerateContentSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	if g.stream {
+		var chunks []genai.GenerateContentResponse
+		chunks, err = parseGeminiStreamingChunks(&g.bufferedBody, &g.streamDelimiter, body)
+		if err != nil {
+			return nil, nil, tokenUsage, g.requestModel, err
+		}
+		for i := range chunks {
+			chunk := &chunks[i]
+			if chunk.ModelVersion != "" {
+				g.streamingResponseModel = chunk.ModelVersion
+			}
+			// Each chunk carries the cumulative usage, so the last one wins.
+			if chunk.UsageMetadata != nil {
+				g.streamingTokenUsage = geminiUsageToTokenUsage(chunk.UsageMetadata)
+			}
+			if span != nil {
+				span.RecordResponseChunk(chunk)
+			}
+		}
+		return nil, nil, g.streamingTokenUsage, cmp.Or(g.streamingResponseModel, g.requestModel), nil
+	}
+
+	resp := &genai.GenerateContentResponse{}
+	if err = json.NewDecoder(body).Decode(resp); err != nil {
+		return nil, nil, tokenUsage, g.requestModel, fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	if resp.UsageMetadata != nil {
+		tokenUsage = geminiUsageToTokenUsage(resp.UsageMetadata)
+	}
+	if span != nil {
+		span.RecordResponse(resp)
+	}
+	return nil, nil, tokenUsage, cmp.Or(resp.ModelVersion, g.requestModel), nil
+}
+
+// ResponseError implements [GeminiGenerateContentTranslator.ResponseError].
+// Vertex AI already returns the Gemini error format, so only non-JSON errors are converted.
+func (g *generateContentToGCPVertexAITranslator) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	if v, ok := respHeaders[contentTypeHeaderName]; ok && strings.Contains(v, jsonContentType) {
+		return nil, nil, nil
+	}
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+	}
+	return geminiErrorResponse(respHeaders[statusHeaderName], string(bytes.TrimSpace(buf)))
+}
+
+// geminiErrorResponse builds the Gemini API error response for the given HTTP status code and message.
+// https://ai.google.dev/gemini-api/docs/troubleshooting#error-codes
+func geminiErrorResponse(statusCode, message string) (newHeaders []internalapi.Header, newBody []byte, err error) {
+	code, _ := strconv.Atoi(statusCode)
+	var gcpError gcpVertexAIError
+	gcpError.Error.Code = code
+	gcpError.Error.Message = message
+	gcpError.Error.Status = geminiErrorStatusForCode(code)
+	newBody, err = json.Marshal(gcpError)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal error body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{contentTypeHeaderName, jsonContentType},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// geminiErrorStatusForCode returns the canonical status of the Google APIs corresponding to the HTTP status code.
+func geminiErrorStatusForCode(code int) string {
+	switch code {
+	case 400:
+		return "INVALID_ARGUMENT"
+	case 401:
+		return "UNAUTHENTICATED"
+	case 403:
+		return "PERMISSION_DENIED"
+	case 404:
+		return "NOT_FOUND"
+	case 429:
+		return "RESOURCE_EXHAUSTED"
+	case 499:
+		return "CANCELLED"
+	case 503:
+		return "UNAVAILABLE"
+	case 504:
+		return "DEADLINE_EXCEEDED"
+	default:
+		return "INTERNAL"
+	}
+}
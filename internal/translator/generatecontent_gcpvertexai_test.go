@@ -0,0 +1,196 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+// mockGenerateContentSpan implements [tracingapi.GenerateContentSpan] for testing.
+type mockGenerateContentSpan struct {
+	recordedResponse *genai.GenerateContentResponse
+	recordedChunks   []*genai.GenerateContentResponse
+}
+
+func (m *mockGenerateContentSpan) RecordResponseChunk(resp *genai.GenerateContentResponse) {
+	m.recordedChunks = append(m.recordedChunks, resp)
+}
+
+func (m *mockGenerateContentSpan) RecordResponse(resp *genai.GenerateContentResponse) {
+	m.recordedResponse = resp
+}
+func (m *mockGenerateContentSpan) EndSpanOnError(int, []byte) {}
+func (m *mockGenerateContentSpan) EndSpan()                   {}
+
+func TestGenerateContentToGCPVertexAITranslator_RequestBody(t *testing.T) {
+	original := []byte(`{"contents":[{"role":"user","parts":[{"text":"hi"}]}]}`)
+	for _, tc := range []struct {
+		name              string
+		req               gcp.GenerateContentRequest
+		modelNameOverride string
+		forceBodyMutation bool
+		expHeaders        []internalapi.Header
+		expBody           []byte
+	}{
+		{
+			name:       "generateContent",
+			req:        gcp.GenerateContentRequest{Model: "gemini-2.5-flash"},
+			expHeaders: []internalapi.Header{{pathHeaderName, "publishers/google/models/gemini-2.5-flash:generateContent"}},
+		},
+		{
+			name:       "streamGenerateContent",
+			req:        gcp.GenerateContentRequest{Model: "gemini-2.5-flash", Stream: true},
+			expHeaders: []internalapi.Header{{pathHeaderName, "publishers/google/models/gemini-2.5-flash:streamGenerateContent?alt=sse"}},
+		},
+		{
+			name:              "model override",
+			req:               gcp.GenerateContentRequest{Model: "gemini-2.5-flash"},
+			modelNameOverride: "gemini-2.5-pro",
+			expHeaders:        []internalapi.Header{{pathHeaderName, "publishers/google/models/gemini-2.5-pro:generateContent"}},
+		},
+		{
+			name:              "force body mutation",
+			req:               gcp.GenerateContentRequest{Model: "gemini-2.5-flash"},
+			forceBodyMutation: true,
+			expHeaders: []internalapi.Header{
+				{pathHeaderName, "publishers/google/models/gemini-2.5-flash:generateContent"},
+				{contentLengthHeaderName, "54"},
+			},
+			expBody: original,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := NewGenerateContentToGCPVertexAITranslator(tc.modelNameOverride)
+			headers, body, err := tr.RequestBody(original, &tc.req, tc.forceBodyMutation)
+			require.NoError(t, err)
+			require.Equal(t, tc.expHeaders, headers)
+			require.Equal(t, tc.expBody, body)
+		})
+	}
+}
+
+func TestGenerateContentToGoogleAIStudioTranslator_RequestBody(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		req               gcp.GenerateContentRequest
+		modelNameOverride string
+		expPath           string
+	}{
+		{
+			name:    "generateContent",
+			req:     gcp.GenerateContentRequest{Model: "gemini-2.5-flash"},
+			expPath: "/v1beta/models/gemini-2.5-flash:generateContent",
+		},
+		{
+			name:    "streamGenerateContent",
+			req:     gcp.GenerateContentRequest{Model: "gemini-2.5-flash", Stream: true},
+			expPath: "/v1beta/models/gemini-2.5-flash:streamGenerateContent?alt=sse",
+		},
+		{
+			name:              "model override with resource name",
+			req:               gcp.GenerateContentRequest{Model: "gemini-2.5-flash"},
+			modelNameOverride: "models/gemini-2.5-pro",
+			expPath:           "/v1beta/models/gemini-2.5-pro:generateContent",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := NewGenerateContentToGoogleAIStudioTranslator(tc.modelNameOverride)
+			headers, body, err := tr.RequestBody(nil, &tc.req, false)
+			require.NoError(t, err)
+			require.Equal(t, []internalapi.Header{{pathHeaderName, tc.expPath}}, headers)
+			require.Nil(t, body)
+		})
+	}
+}
+
+func TestGenerateContentToGCPVertexAITranslator_ResponseBody(t *testing.T) {
+	t.Run("non-streaming", func(t *testing.T) {
+		tr := NewGenerateContentToGCPVertexAITranslator("")
+		_, _, err := tr.RequestBody(nil, &gcp.GenerateContentRequest{Model: "gemini-2.5-flash"}, false)
+		require.NoError(t, err)
+
+		span := &mockGenerateContentSpan{}
+		headers, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(`{
+"candidates":[{"content":{"role":"model","parts":[{"text":"Hello!"}]},"finishReason":"STOP"}],
+"usageMetadata":{"promptTokenCount":10,"cachedContentTokenCount":4,"candidatesTokenCount":5,"thoughtsTokenCount":3,"totalTokenCount":18},
+"modelVersion":"gemini-2.5-flash-001"}`), true, span)
+		require.NoError(t, err)
+		require.Nil(t, headers)
+		require.Nil(t, body)
+		require.Equal(t, "gemini-2.5-flash-001", responseModel)
+		inputTokens, _ := tokenUsage.InputTokens()
+		require.Equal(t, uint32(10), inputTokens)
+		cachedTokens, _ := tokenUsage.CachedInputTokens()
+		require.Equal(t, uint32(4), cachedTokens)
+		outputTokens, _ := tokenUsage.OutputTokens()
+		require.Equal(t, uint32(8), outputTokens)
+		require.NotNil(t, span.recordedResponse)
+		require.Equal(t, "Hello!", span.recordedResponse.Candidates[0].Content.Parts[0].Text)
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		tr := NewGenerateContentToGCPVertexAITranslator("")
+		_, _, err := tr.RequestBody(nil, &gcp.GenerateContentRequest{Model: "gemini-2.5-flash", Stream: true}, false)
+		require.NoError(t, err)
+
+		stream := []byte(`data: {"candidates":[{"content":{"role":"model","parts":[{"text":"Hel"}]}}],"usageMetadata":{"promptTokenCount":10,"candidatesTokenCount":1,"totalTokenCount":11},"modelVersion":"gemini-2.5-flash-001"}
+
+data: {"candidates":[{"content":{"role":"model","parts":[{"text":"lo!"}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":10,"candidatesTokenCount":2,"totalTokenCount":12},"modelVersion":"gemini-2.5-flash-001"}
+
+`)
+		span := &mockGenerateContentSpan{}
+		// Feed the stream in two pieces to exercise the buffering of partial chunks.
+		headers, body, _, _, err := tr.ResponseBody(nil, bytes.NewReader(stream[:100]), false, span)
+		require.NoError(t, err)
+		require.Nil(t, headers)
+		require.Nil(t, body)
+		_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, bytes.NewReader(stream[100:]), true, span)
+		require.NoError(t, err)
+		require.Nil(t, body)
+		require.Equal(t, "gemini-2.5-flash-001", responseModel)
+		inputTokens, _ := tokenUsage.InputTokens()
+		require.Equal(t, uint32(10), inputTokens)
+		outputTokens, _ := tokenUsage.OutputTokens()
+		require.Equal(t, uint32(2), outputTokens)
+		require.Len(t, span.recordedChunks, 2)
+		require.Equal(t, "lo!", span.recordedChunks[1].Candidates[0].Content.Parts[0].Text)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		tr := NewGenerateContentToGCPVertexAITranslator("")
+		_, _, err := tr.RequestBody(nil, &gcp.GenerateContentRequest{Model: "gemini-2.5-flash"}, false)
+		require.NoError(t, err)
+		_, _, _, _, err = tr.ResponseBody(nil, strings.NewReader("not-json"), true, nil)
+		require.ErrorContains(t, err, "failed to unmarshal body")
+	})
+}
+
+func TestGenerateContentToGCPVertexAITranslator_ResponseError(t *testing.T) {
+	t.Run("json error is passed through", func(t *testing.T) {
+		tr := NewGenerateContentToGCPVertexAITranslator("")
+		headers, body, err := tr.ResponseError(map[string]string{statusHeaderName: "400", contentTypeHeaderName: jsonContentType},
+			strings.NewReader(`{"error":{"code":400,"message":"bad","status":"INVALID_ARGUMENT"}}`))
+		require.NoError(t, err)
+		require.Nil(t, headers)
+		require.Nil(t, body)
+	})
+
+	t.Run("non json error", func(t *testing.T) {
+		tr := NewGenerateContentToGCPVertexAITranslator("")
+		headers, body, err := tr.ResponseError(map[string]string{statusHeaderName: "503"}, strings.NewReader("upstream connect error\n"))
+		require.NoError(t, err)
+		require.JSONEq(t, `{"error":{"code":503,"message":"upstream connect error","status":"UNAVAILABLE","details":null}}`, string(body))
+		require.Equal(t, internalapi.Header{contentTypeHeaderName, jsonContentType}, headers[0])
+	})
+}
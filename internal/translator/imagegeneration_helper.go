@@ -0,0 +1,78 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+// imageResponseFormatB64JSON is the response_format of the image generation request to return base64 images.
+const imageResponseFormatB64JSON = "b64_json"
+
+// parseImageGenerationSize parses the size of the image generation request in the form of "WIDTHxHEIGHT".
+// ok is false when the size is not specified or "auto", in which case the backend default is used.
+func parseImageGenerationSize(size string) (width, height int, ok bool, err error) {
+	if size == "" || size == "auto" {
+		return 0, 0, false, nil
+	}
+	w, h, found := strings.Cut(size, "x")
+	if found {
+		width, err = strconv.Atoi(w)
+		if err == nil {
+			height, err = strconv.Atoi(h)
+		}
+	}
+	if !found || err != nil || width <= 0 || height <= 0 {
+		return 0, 0, false, fmt.Errorf("%w: invalid size %q", internalapi.ErrInvalidRequestBody, size)
+	}
+	return width, height, true, nil
+}
+
+// closestAspectRatio returns the aspect ratio in the form of "W:H" among the supported ones that is the closest
+// to the given width and height, since the providers other than OpenAI take the aspect ratio instead of the size.
+func closestAspectRatio(width, height int, supported []string) string {
+	target := math.Log(float64(width) / float64(height))
+	var closest string
+	minDiff := math.Inf(1)
+	for _, ratio := range supported {
+		w, h, _ := strings.Cut(ratio, ":")
+		rw, _ := strconv.ParseFloat(w, 64)
+		rh, _ := strconv.ParseFloat(h, 64)
+		if diff := math.Abs(math.Log(rw/rh) - target); diff < minDiff {
+			closest, minDiff = ratio, diff
+		}
+	}
+	return closest
+}
+
+// imageOutputFormatMimeType returns the MIME type of the output_format of the image generation request.
+func imageOutputFormatMimeType(outputFormat string) string {
+	switch outputFormat {
+	case "jpeg", "jpg":
+		return "image/jpeg"
+	case "webp":
+		return "image/webp"
+	default:
+		return "image/png"
+	}
+}
+
+// imageGenerationResponseData returns the OpenAI image data for the base64 encoded image returned by the backend.
+//
+// The backends other than OpenAI only return the image bytes, so for the "url" response format, which is the
+// default of OpenAI, the image is returned as a data URL.
+func imageGenerationResponseData(b64, mimeType, responseFormat string) openai.ImageGenerationResponseData {
+	if responseFormat == imageResponseFormatB64JSON {
+		return openai.ImageGenerationResponseData{B64JSON: b64}
+	}
+	return openai.ImageGenerationResponseData{URL: "data:" + mimeType + ";base64," + b64}
+}
@@ -0,0 +1,69 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+func TestParseImageGenerationSize(t *testing.T) {
+	for _, tc := range []struct {
+		size          string
+		width, height int
+		ok            bool
+		expErr        bool
+	}{
+		{size: ""},
+		{size: "auto"},
+		{size: "1024x1024", width: 1024, height: 1024, ok: true},
+		{size: "1792x1024", width: 1792, height: 1024, ok: true},
+		{size: "axb", expErr: true},
+		{size: "0x1024", expErr: true},
+		{size: "1024", expErr: true},
+	} {
+		t.Run(tc.size, func(t *testing.T) {
+			width, height, ok, err := parseImageGenerationSize(tc.size)
+			if tc.expErr {
+				require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.width, width)
+			require.Equal(t, tc.height, height)
+		})
+	}
+}
+
+func TestClosestAspectRatio(t *testing.T) {
+	for _, tc := range []struct {
+		width, height int
+		exp           string
+	}{
+		{width: 1024, height: 1024, exp: "1:1"},
+		{width: 1792, height: 1024, exp: "16:9"},
+		{width: 1024, height: 1792, exp: "9:16"},
+		{width: 1536, height: 1024, exp: "4:3"},
+		{width: 1024, height: 1536, exp: "3:4"},
+	} {
+		require.Equal(t, tc.exp, closestAspectRatio(tc.width, tc.height, imagenAspectRatios))
+	}
+	require.Equal(t, "3:2", closestAspectRatio(1536, 1024, stabilityAspectRatios))
+}
+
+func TestImageGenerationResponseData(t *testing.T) {
+	require.Equal(t, openai.ImageGenerationResponseData{B64JSON: "aGVsbG8="},
+		imageGenerationResponseData("aGVsbG8=", "image/png", "b64_json"))
+	require.Equal(t, openai.ImageGenerationResponseData{URL: "data:image/jpeg;base64,aGVsbG8="},
+		imageGenerationResponseData("aGVsbG8=", "image/jpeg", "url"))
+	require.Equal(t, openai.ImageGenerationResponseData{URL: "data:image/png;base64,aGVsbG8="},
+		imageGenerationResponseData("aGVsbG8=", "image/png", ""))
+}
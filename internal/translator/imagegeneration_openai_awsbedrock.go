@@ -0,0 +1,153 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// stabilityAspectRatios is the list of the aspect ratios supported by the Stability AI image models.
+var stabilityAspectRatios = []string{"1:1", "2:3", "3:2", "4:5", "5:4", "9:16", "16:9", "9:21", "21:9"}
+
+// NewImageGenerationOpenAIToAWSBedrockTranslator implements [Factory] for OpenAI to AWS Bedrock image generation
+// translation.
+func NewImageGenerationOpenAIToAWSBedrockTranslator(modelNameOverride internalapi.ModelNameOverride) OpenAIImageGenerationTranslator {
+	return &openAIToAWSBedrockImageGenerationTranslator{modelNameOverride: modelNameOverride}
+}
+
+// openAIToAWSBedrockImageGenerationTranslator translates the OpenAI Images API to AWS Bedrock InvokeModel requests.
+//
+// The Stability AI models, whose IDs contain "stability.", use their own request schema. The other models such
+// as Amazon Titan Image Generator and Amazon Nova Canvas use the Titan TEXT_IMAGE request schema.
+type openAIToAWSBedrockImageGenerationTranslator struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+	// stability is true when the request model is a Stability AI model.
+	stability bool
+	// responseFormat is the response_format of the request, either url or b64_json.
+	responseFormat string
+	// outputFormat is the output_format of the request, which is also returned in the response.
+	outputFormat string
+}
+
+// RequestBody implements [OpenAIImageGenerationTranslator.RequestBody].
+func (o *openAIToAWSBedrockImageGenerationTranslator) RequestBody(_ []byte, req *openai.ImageGenerationRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.requestModel = cmp.Or(o.modelNameOverride, req.Model)
+	o.stability = strings.Contains(o.requestModel, "stability.")
+	o.responseFormat = req.ResponseFormat
+	o.outputFormat = req.OutputFormat
+
+	width, height, hasSize, err := parseImageGenerationSize(req.Size)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.stability {
+		if req.N > 1 {
+			return nil, nil, fmt.Errorf("%w: Stability AI models on AWS Bedrock generate a single image (got n=%d)",
+				internalapi.ErrInvalidRequestBody, req.N)
+		}
+		stabilityReq := awsbedrock.StabilityImageGenerationRequest{Prompt: req.Prompt, OutputFormat: req.OutputFormat}
+		if hasSize {
+			stabilityReq.AspectRatio = closestAspectRatio(width, height, stabilityAspectRatios)
+		}
+		newBody, err = json.Marshal(stabilityReq)
+	} else {
+		if req.OutputFormat != "" && req.OutputFormat != "png" {
+			return nil, nil, fmt.Errorf("%w: unsupported output_format %q for AWS Bedrock image model %s",
+				internalapi.ErrInvalidRequestBody, req.OutputFormat, o.requestModel)
+		}
+		config := &awsbedrock.TitanImageGenerationConfig{NumberOfImages: cmp.Or(req.N, 1), Width: width, Height: height}
+		// The hd and high qualities of OpenAI are mapped to the premium quality.
+		if req.Quality == "hd" || req.Quality == "high" {
+			config.Quality = "premium"
+		}
+		newBody, err = json.Marshal(awsbedrock.TitanImageGenerationRequest{
+			TaskType:              "TEXT_IMAGE",
+			TextToImageParams:     awsbedrock.TitanTextToImageParams{Text: req.Prompt},
+			ImageGenerationConfig: config,
+		})
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, fmt.Sprintf("/model/%s/invoke", url.PathEscape(o.requestModel))},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// ResponseHeaders implements [OpenAIImageGenerationTranslator.ResponseHeaders].
+func (o *openAIToAWSBedrockImageGenerationTranslator) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIImageGenerationTranslator.ResponseBody].
+// The InvokeModel response does not contain usage nor model, so the request model is returned as the response model.
+func (o *openAIToAWSBedrockImageGenerationTranslator) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.ImageGenerationSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	var images []string
+	if o.stability {
+		var stabilityResp awsbedrock.StabilityImageGenerationResponse
+		if err = json.NewDecoder(body).Decode(&stabilityResp); err != nil {
+			return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal Stability AI image response: %w", err)
+		}
+		images = stabilityResp.Images
+	} else {
+		var titanResp awsbedrock.TitanImageGenerationResponse
+		if err = json.NewDecoder(body).Decode(&titanResp); err != nil {
+			return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal Titan image response: %w", err)
+		}
+		images = titanResp.Images
+	}
+
+	openaiResp := openai.ImageGenerationResponse{
+		Created:      time.Now().Unix(),
+		Data:         make([]openai.ImageGenerationResponseData, 0, len(images)),
+		OutputFormat: o.outputFormat,
+	}
+	mimeType := imageOutputFormatMimeType(o.outputFormat)
+	for _, image := range images {
+		openaiResp.Data = append(openaiResp.Data, imageGenerationResponseData(image, mimeType, o.responseFormat))
+	}
+
+	newBody, err = json.Marshal(openaiResp)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal OpenAI image response: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(&openaiResp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	tokenUsage.SetImageCount(uint32(len(openaiResp.Data))) //nolint:gosec
+	responseModel = o.requestModel
+	return
+}
+
+// ResponseError implements [OpenAIImageGenerationTranslator.ResponseError].
+// Translate AWS Bedrock exceptions to OpenAI error type.
+func (o *openAIToAWSBedrockImageGenerationTranslator) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return convertAWSBedrockErrorToOpenAI(respHeaders, body)
+}
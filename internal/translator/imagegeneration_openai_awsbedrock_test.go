@@ -0,0 +1,125 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestOpenAIToAWSBedrockImageTranslator_RequestBody_Titan(t *testing.T) {
+	tr := NewImageGenerationOpenAIToAWSBedrockTranslator("")
+	req := &openai.ImageGenerationRequest{
+		Model: "amazon.titan-image-generator-v2:0", Prompt: "a cat", N: 2, Size: "1024x768", Quality: "hd",
+	}
+	hm, bm, err := tr.RequestBody(nil, req, false)
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{
+		{pathHeaderName, "/model/amazon.titan-image-generator-v2:0/invoke"},
+		{contentLengthHeaderName, strconv.Itoa(len(bm))},
+	}, hm)
+
+	var got awsbedrock.TitanImageGenerationRequest
+	require.NoError(t, json.Unmarshal(bm, &got))
+	require.Equal(t, awsbedrock.TitanImageGenerationRequest{
+		TaskType:          "TEXT_IMAGE",
+		TextToImageParams: awsbedrock.TitanTextToImageParams{Text: "a cat"},
+		ImageGenerationConfig: &awsbedrock.TitanImageGenerationConfig{
+			NumberOfImages: 2, Quality: "premium", Width: 1024, Height: 768,
+		},
+	}, got)
+
+	t.Run("unsupported output format", func(t *testing.T) {
+		_, _, err := tr.RequestBody(nil, &openai.ImageGenerationRequest{Model: "amazon.nova-canvas-v1:0", OutputFormat: "webp"}, false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+	})
+}
+
+func TestOpenAIToAWSBedrockImageTranslator_RequestBody_Stability(t *testing.T) {
+	tr := NewImageGenerationOpenAIToAWSBedrockTranslator("stability.stable-image-core-v1:1")
+	req := &openai.ImageGenerationRequest{Model: "dall-e-3", Prompt: "a cat", Size: "1792x1024", OutputFormat: "jpeg"}
+	hm, bm, err := tr.RequestBody(nil, req, false)
+	require.NoError(t, err)
+	require.Equal(t, "/model/stability.stable-image-core-v1:1/invoke", hm[0].Value())
+
+	var got awsbedrock.StabilityImageGenerationRequest
+	require.NoError(t, json.Unmarshal(bm, &got))
+	require.Equal(t, awsbedrock.StabilityImageGenerationRequest{Prompt: "a cat", AspectRatio: "16:9", OutputFormat: "jpeg"}, got)
+
+	t.Run("multiple images", func(t *testing.T) {
+		_, _, err := tr.RequestBody(nil, &openai.ImageGenerationRequest{Prompt: "a cat", N: 2}, false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+		require.ErrorContains(t, err, "generate a single image (got n=2)")
+	})
+}
+
+func TestOpenAIToAWSBedrockImageTranslator_ResponseBody(t *testing.T) {
+	t.Run("titan", func(t *testing.T) {
+		tr := NewImageGenerationOpenAIToAWSBedrockTranslator("")
+		_, _, err := tr.RequestBody(nil, &openai.ImageGenerationRequest{Model: "amazon.titan-image-generator-v2:0", ResponseFormat: "b64_json"}, false)
+		require.NoError(t, err)
+
+		buf, _ := json.Marshal(awsbedrock.TitanImageGenerationResponse{Images: []string{"aW1hZ2Ux", "aW1hZ2Uy"}})
+		span := &mockImageGenerationSpan{}
+		hm, bm, usage, responseModel, err := tr.ResponseBody(nil, bytes.NewReader(buf), true, span)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(bm))}}, hm)
+		require.Equal(t, "amazon.titan-image-generator-v2:0", responseModel)
+		imageCount, ok := usage.ImageCount()
+		require.True(t, ok)
+		require.Equal(t, uint32(2), imageCount)
+
+		var got openai.ImageGenerationResponse
+		require.NoError(t, json.Unmarshal(bm, &got))
+		require.Equal(t, []openai.ImageGenerationResponseData{{B64JSON: "aW1hZ2Ux"}, {B64JSON: "aW1hZ2Uy"}}, got.Data)
+		require.NotNil(t, span.recordedResponse)
+	})
+
+	t.Run("stability", func(t *testing.T) {
+		tr := NewImageGenerationOpenAIToAWSBedrockTranslator("stability.sd3-5-large-v1:0")
+		_, _, err := tr.RequestBody(nil, &openai.ImageGenerationRequest{Prompt: "a cat", OutputFormat: "webp"}, false)
+		require.NoError(t, err)
+
+		buf := []byte(`{"seeds":[42],"finish_reasons":[null],"images":["aW1hZ2Ux"]}`)
+		_, bm, _, _, err := tr.ResponseBody(nil, bytes.NewReader(buf), true, nil)
+		require.NoError(t, err)
+
+		var got openai.ImageGenerationResponse
+		require.NoError(t, json.Unmarshal(bm, &got))
+		require.Equal(t, "webp", got.OutputFormat)
+		require.Equal(t, []openai.ImageGenerationResponseData{{URL: "data:image/webp;base64,aW1hZ2Ux"}}, got.Data)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		tr := NewImageGenerationOpenAIToAWSBedrockTranslator("")
+		_, _, _, _, err := tr.ResponseBody(nil, bytes.NewReader([]byte("not json")), true, nil)
+		require.ErrorContains(t, err, "failed to unmarshal Titan image response")
+	})
+}
+
+func TestOpenAIToAWSBedrockImageTranslator_ResponseError(t *testing.T) {
+	tr := NewImageGenerationOpenAIToAWSBedrockTranslator("")
+	headers := map[string]string{
+		statusHeaderName:       "400",
+		contentTypeHeaderName:  "application/json",
+		awsErrorTypeHeaderName: "ValidationException",
+	}
+	_, bm, err := tr.ResponseError(headers, bytes.NewReader([]byte(`{"message":"Invalid prompt"}`)))
+	require.NoError(t, err)
+
+	var got openai.Error
+	require.NoError(t, json.Unmarshal(bm, &got))
+	require.Equal(t, "ValidationException", got.Error.Type)
+	require.Equal(t, "Invalid prompt", got.Error.Message)
+}
@@ -0,0 +1,45 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"fmt"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+// NewImageGenerationOpenAIToAzureOpenAITranslator implements [Factory] for OpenAI to Azure OpenAI translation
+// for image generation.
+func NewImageGenerationOpenAIToAzureOpenAITranslator(apiVersion string, modelNameOverride internalapi.ModelNameOverride) OpenAIImageGenerationTranslator {
+	return &openAIToAzureOpenAIImageGenerationTranslator{
+		apiVersion: apiVersion,
+		openAIToOpenAIImageGenerationTranslator: openAIToOpenAIImageGenerationTranslator{
+			modelNameOverride: modelNameOverride,
+		},
+	}
+}
+
+// openAIToAzureOpenAIImageGenerationTranslator implements [OpenAIImageGenerationTranslator] for /images/generations.
+type openAIToAzureOpenAIImageGenerationTranslator struct {
+	apiVersion string
+	openAIToOpenAIImageGenerationTranslator
+}
+
+// RequestBody implements [OpenAIImageGenerationTranslator.RequestBody].
+func (o *openAIToAzureOpenAIImageGenerationTranslator) RequestBody(original []byte, req *openai.ImageGenerationRequest, forceBodyMutation bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	newHeaders, newBody, err = o.openAIToOpenAIImageGenerationTranslator.RequestBody(original, req, forceBodyMutation)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Azure OpenAI uses a {deployment-id} in the path instead of the model in the body. Assume deployment_id is
+	// same as model name.
+	pathTemplate := "/openai/deployments/%s/images/generations?api-version=%s"
+	newHeaders[0] = internalapi.Header{pathHeaderName, fmt.Sprintf(pathTemplate, o.requestModel, o.apiVersion)}
+	return
+}
@@ -0,0 +1,46 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestOpenAIToAzureOpenAIImageTranslator_RequestBody(t *testing.T) {
+	t.Run("no override", func(t *testing.T) {
+		tr := NewImageGenerationOpenAIToAzureOpenAITranslator("2025-04-01-preview", "")
+		req := &openai.ImageGenerationRequest{Model: "dall-e-3", Prompt: "a cat"}
+		original, _ := json.Marshal(req)
+
+		hm, bm, err := tr.RequestBody(original, req, false)
+		require.NoError(t, err)
+		require.Nil(t, bm)
+		require.Len(t, hm, 1)
+		require.Equal(t, pathHeaderName, hm[0].Key())
+		require.Equal(t, "/openai/deployments/dall-e-3/images/generations?api-version=2025-04-01-preview", hm[0].Value())
+	})
+
+	t.Run("model override", func(t *testing.T) {
+		tr := NewImageGenerationOpenAIToAzureOpenAITranslator("2025-04-01-preview", "gpt-image-1")
+		req := &openai.ImageGenerationRequest{Model: "dall-e-3", Prompt: "a cat"}
+		original, _ := json.Marshal(req)
+
+		hm, bm, err := tr.RequestBody(original, req, false)
+		require.NoError(t, err)
+		require.Len(t, hm, 2)
+		require.Equal(t, "/openai/deployments/gpt-image-1/images/generations?api-version=2025-04-01-preview", hm[0].Value())
+		require.Equal(t, contentLengthHeaderName, hm[1].Key())
+
+		var got openai.ImageGenerationRequest
+		require.NoError(t, json.Unmarshal(bm, &got))
+		require.Equal(t, "gpt-image-1", got.Model)
+	})
+}
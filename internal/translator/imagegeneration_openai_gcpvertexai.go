@@ -0,0 +1,141 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// imagenAspectRatios is the list of the aspect ratios supported by Imagen.
+var imagenAspectRatios = []string{"1:1", "3:4", "4:3", "9:16", "16:9"}
+
+// NewImageGenerationOpenAIToGCPVertexAITranslator implements [Factory] for OpenAI to GCP Vertex AI Imagen
+// translation for image generation.
+func NewImageGenerationOpenAIToGCPVertexAITranslator(modelNameOverride internalapi.ModelNameOverride) OpenAIImageGenerationTranslator {
+	return &openAIToGCPVertexAIImageGenerationTranslator{modelNameOverride: modelNameOverride}
+}
+
+// openAIToGCPVertexAIImageGenerationTranslator translates the OpenAI Images API to the predict method of the
+// Vertex AI Imagen models.
+// https://cloud.google.com/vertex-ai/generative-ai/docs/model-reference/imagen-api
+type openAIToGCPVertexAIImageGenerationTranslator struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+	// responseFormat is the response_format of the request, either url or b64_json.
+	responseFormat string
+	// outputFormat is the output_format of the request, which is also returned in the response.
+	outputFormat string
+}
+
+// RequestBody implements [OpenAIImageGenerationTranslator.RequestBody].
+func (o *openAIToGCPVertexAIImageGenerationTranslator) RequestBody(_ []byte, req *openai.ImageGenerationRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.requestModel = cmp.Or(o.modelNameOverride, req.Model)
+	o.responseFormat = req.ResponseFormat
+	o.outputFormat = req.OutputFormat
+
+	params := gcp.ImagenParameters{SampleCount: cmp.Or(req.N, 1)}
+	width, height, ok, err := parseImageGenerationSize(req.Size)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ok {
+		params.AspectRatio = closestAspectRatio(width, height, imagenAspectRatios)
+		if max(width, height) > 1024 {
+			params.SampleImageSize = "2K"
+		}
+	}
+	// The hd and high qualities of OpenAI are mapped to the higher resolution.
+	if req.Quality == "hd" || req.Quality == "high" {
+		params.SampleImageSize = "2K"
+	}
+	switch req.OutputFormat {
+	case "":
+	case "png", "jpeg":
+		params.OutputOptions = &gcp.ImagenOutputOptions{MimeType: imageOutputFormatMimeType(req.OutputFormat)}
+		if req.OutputCompression != nil && req.OutputFormat == "jpeg" {
+			params.OutputOptions.CompressionQuality = req.OutputCompression
+		}
+	default:
+		return nil, nil, fmt.Errorf("%w: unsupported output_format %q for Imagen", internalapi.ErrInvalidRequestBody, req.OutputFormat)
+	}
+
+	newBody, err = json.Marshal(gcp.ImagenPredictRequest{
+		Instances:  []gcp.ImagenInstance{{Prompt: req.Prompt}},
+		Parameters: params,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal Imagen request: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, buildGCPModelPathSuffix(gcpModelPublisherGoogle, o.requestModel, gcpMethodPredict)},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// ResponseHeaders implements [OpenAIImageGenerationTranslator.ResponseHeaders].
+func (o *openAIToGCPVertexAIImageGenerationTranslator) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIImageGenerationTranslator.ResponseBody].
+// The Imagen response does not contain usage nor model, so the request model is returned as the response model.
+func (o *openAIToGCPVertexAIImageGenerationTranslator) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.ImageGenerationSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	var imagenResp gcp.ImagenPredictResponse
+	if err = json.NewDecoder(body).Decode(&imagenResp); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal Imagen response: %w", err)
+	}
+
+	openaiResp := openai.ImageGenerationResponse{
+		Created:      time.Now().Unix(),
+		Data:         make([]openai.ImageGenerationResponseData, 0, len(imagenResp.Predictions)),
+		OutputFormat: o.outputFormat,
+	}
+	for _, prediction := range imagenResp.Predictions {
+		// The images filtered out by the responsible AI filters have no bytes.
+		if prediction.BytesBase64Encoded == "" {
+			continue
+		}
+		mimeType := cmp.Or(prediction.MimeType, imageOutputFormatMimeType(o.outputFormat))
+		data := imageGenerationResponseData(prediction.BytesBase64Encoded, mimeType, o.responseFormat)
+		data.RevisedPrompt = prediction.Prompt
+		openaiResp.Data = append(openaiResp.Data, data)
+	}
+
+	newBody, err = json.Marshal(openaiResp)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal OpenAI image response: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(&openaiResp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	tokenUsage.SetImageCount(uint32(len(openaiResp.Data))) //nolint:gosec
+	responseModel = o.requestModel
+	return
+}
+
+// ResponseError implements [OpenAIImageGenerationTranslator.ResponseError].
+func (o *openAIToGCPVertexAIImageGenerationTranslator) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return convertGCPVertexAIErrorToOpenAI(respHeaders, body)
+}
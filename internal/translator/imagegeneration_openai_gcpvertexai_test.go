@@ -0,0 +1,161 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestOpenAIToGCPVertexAIImageTranslator_RequestBody(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		override string
+		req      *openai.ImageGenerationRequest
+		expPath  string
+		expBody  gcp.ImagenPredictRequest
+	}{
+		{
+			name:    "defaults",
+			req:     &openai.ImageGenerationRequest{Model: "imagen-4.0-generate-001", Prompt: "a cat"},
+			expPath: "publishers/google/models/imagen-4.0-generate-001:predict",
+			expBody: gcp.ImagenPredictRequest{
+				Instances:  []gcp.ImagenInstance{{Prompt: "a cat"}},
+				Parameters: gcp.ImagenParameters{SampleCount: 1},
+			},
+		},
+		{
+			name:     "all parameters",
+			override: "imagen-4.0-ultra-generate-001",
+			req: &openai.ImageGenerationRequest{
+				Model: "dall-e-3", Prompt: "a cat", N: 3, Size: "1792x1024", Quality: "standard",
+				OutputFormat: "jpeg", OutputCompression: ptr.To(80),
+			},
+			expPath: "publishers/google/models/imagen-4.0-ultra-generate-001:predict",
+			expBody: gcp.ImagenPredictRequest{
+				Instances: []gcp.ImagenInstance{{Prompt: "a cat"}},
+				Parameters: gcp.ImagenParameters{
+					SampleCount: 3, AspectRatio: "16:9", SampleImageSize: "2K",
+					OutputOptions: &gcp.ImagenOutputOptions{MimeType: "image/jpeg", CompressionQuality: ptr.To(80)},
+				},
+			},
+		},
+		{
+			name:    "hd quality",
+			req:     &openai.ImageGenerationRequest{Model: "imagen", Prompt: "a cat", Size: "1024x1024", Quality: "hd"},
+			expPath: "publishers/google/models/imagen:predict",
+			expBody: gcp.ImagenPredictRequest{
+				Instances:  []gcp.ImagenInstance{{Prompt: "a cat"}},
+				Parameters: gcp.ImagenParameters{SampleCount: 1, AspectRatio: "1:1", SampleImageSize: "2K"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := NewImageGenerationOpenAIToGCPVertexAITranslator(tc.override)
+			hm, bm, err := tr.RequestBody(nil, tc.req, false)
+			require.NoError(t, err)
+			require.Equal(t, []internalapi.Header{
+				{pathHeaderName, tc.expPath},
+				{contentLengthHeaderName, strconv.Itoa(len(bm))},
+			}, hm)
+
+			var got gcp.ImagenPredictRequest
+			require.NoError(t, json.Unmarshal(bm, &got))
+			require.Equal(t, tc.expBody, got)
+		})
+	}
+
+	t.Run("invalid size", func(t *testing.T) {
+		tr := NewImageGenerationOpenAIToGCPVertexAITranslator("")
+		_, _, err := tr.RequestBody(nil, &openai.ImageGenerationRequest{Prompt: "a cat", Size: "big"}, false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+	})
+
+	t.Run("unsupported output format", func(t *testing.T) {
+		tr := NewImageGenerationOpenAIToGCPVertexAITranslator("")
+		_, _, err := tr.RequestBody(nil, &openai.ImageGenerationRequest{Prompt: "a cat", OutputFormat: "webp"}, false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+		require.ErrorContains(t, err, `unsupported output_format "webp" for Imagen`)
+	})
+}
+
+func TestOpenAIToGCPVertexAIImageTranslator_ResponseBody(t *testing.T) {
+	imagenResp := gcp.ImagenPredictResponse{Predictions: []gcp.ImagenPrediction{
+		{BytesBase64Encoded: "aW1hZ2Ux", MimeType: "image/png", Prompt: "an enhanced cat"},
+		{RaiFilteredReason: "filtered"},
+		{BytesBase64Encoded: "aW1hZ2Uy"},
+	}}
+	buf, _ := json.Marshal(imagenResp)
+
+	t.Run("b64_json", func(t *testing.T) {
+		tr := NewImageGenerationOpenAIToGCPVertexAITranslator("")
+		_, _, err := tr.RequestBody(nil, &openai.ImageGenerationRequest{Model: "imagen", Prompt: "a cat", ResponseFormat: "b64_json"}, false)
+		require.NoError(t, err)
+
+		span := &mockImageGenerationSpan{}
+		hm, bm, usage, responseModel, err := tr.ResponseBody(nil, bytes.NewReader(buf), true, span)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(bm))}}, hm)
+		require.Equal(t, "imagen", responseModel)
+		// The filtered image is not counted.
+		expUsage := tokenUsageFrom(-1, -1, -1, -1, -1, -1)
+		expUsage.SetImageCount(2)
+		require.Equal(t, expUsage, usage)
+
+		var got openai.ImageGenerationResponse
+		require.NoError(t, json.Unmarshal(bm, &got))
+		require.NotZero(t, got.Created)
+		require.Equal(t, []openai.ImageGenerationResponseData{
+			{B64JSON: "aW1hZ2Ux", RevisedPrompt: "an enhanced cat"},
+			{B64JSON: "aW1hZ2Uy"},
+		}, got.Data)
+		require.Equal(t, &got, span.recordedResponse)
+	})
+
+	t.Run("url", func(t *testing.T) {
+		tr := NewImageGenerationOpenAIToGCPVertexAITranslator("")
+		_, _, err := tr.RequestBody(nil, &openai.ImageGenerationRequest{Model: "imagen", Prompt: "a cat", OutputFormat: "jpeg"}, false)
+		require.NoError(t, err)
+
+		_, bm, _, _, err := tr.ResponseBody(nil, bytes.NewReader(buf), true, nil)
+		require.NoError(t, err)
+		var got openai.ImageGenerationResponse
+		require.NoError(t, json.Unmarshal(bm, &got))
+		require.Equal(t, "jpeg", got.OutputFormat)
+		require.Equal(t, []openai.ImageGenerationResponseData{
+			{URL: "data:image/png;base64,aW1hZ2Ux", RevisedPrompt: "an enhanced cat"},
+			{URL: "data:image/jpeg;base64,aW1hZ2Uy"},
+		}, got.Data)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		tr := NewImageGenerationOpenAIToGCPVertexAITranslator("")
+		_, _, _, _, err := tr.ResponseBody(nil, bytes.NewReader([]byte("not json")), true, nil)
+		require.ErrorContains(t, err, "failed to unmarshal Imagen response")
+	})
+}
+
+func TestOpenAIToGCPVertexAIImageTranslator_ResponseError(t *testing.T) {
+	tr := NewImageGenerationOpenAIToGCPVertexAITranslator("")
+	headers := map[string]string{statusHeaderName: "400", contentTypeHeaderName: "application/json"}
+	body := `{"error":{"code":400,"message":"Invalid prompt","status":"INVALID_ARGUMENT"}}`
+	_, bm, err := tr.ResponseError(headers, bytes.NewReader([]byte(body)))
+	require.NoError(t, err)
+
+	var got openai.Error
+	require.NoError(t, json.Unmarshal(bm, &got))
+	require.Equal(t, "INVALID_ARGUMENT", got.Error.Type)
+	require.Equal(t, "Invalid prompt", got.Error.Message)
+}
@@ -90,6 +90,7 @@ func (o *openAIToOpenAIImageGenerationTranslator) ResponseBody(_ map[string]stri
 		tokenUsage.SetOutputTokens(uint32(resp.Usage.OutputTokens)) //nolint:gosec
 		tokenUsage.SetTotalTokens(uint32(resp.Usage.TotalTokens))   //nolint:gosec
 	}
+	tokenUsage.SetImageCount(uint32(len(resp.Data))) //nolint:gosec
 
 	// There is no response model field, so use the request one.
 	responseModel = o.requestModel
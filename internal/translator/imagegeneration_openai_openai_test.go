@@ -79,7 +79,9 @@ func TestOpenAIToOpenAIImageTranslator_ResponseBody_OK(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, hm)
 	require.Nil(t, bm)
-	require.Equal(t, tokenUsageFrom(-1, -1, -1, -1, -1, -1), usage)
+	expUsage := tokenUsageFrom(-1, -1, -1, -1, -1, -1)
+	expUsage.SetImageCount(0)
+	require.Equal(t, expUsage, usage)
 	require.Empty(t, responseModel)
 }
 
@@ -173,9 +175,12 @@ func TestOpenAIToOpenAIImageTranslator_ResponseBody_Usage(t *testing.T) {
 			InputTokens:  40,
 			OutputTokens: 60,
 		},
+		Data: []openai.ImageGenerationResponseData{{URL: "https://example.com/1.png"}, {URL: "https://example.com/2.png"}},
 	}
 	buf, _ := json.Marshal(resp)
 	_, _, usage, _, err := tr.ResponseBody(map[string]string{}, bytes.NewReader(buf), true, nil)
 	require.NoError(t, err)
-	require.Equal(t, tokenUsageFrom(40, -1, -1, 60, 100, -1), usage)
+	expUsage := tokenUsageFrom(40, -1, -1, 60, 100, -1)
+	expUsage.SetImageCount(2)
+	require.Equal(t, expUsage, usage)
 }
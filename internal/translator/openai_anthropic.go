@@ -0,0 +1,89 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"cmp"
+	"io"
+	"strconv"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/tidwall/sjson"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+const (
+	// anthropicDefaultVersion is the default value of the anthropic-version header of the Anthropic API.
+	// https://docs.claude.com/en/api/versioning
+	anthropicDefaultVersion = "2023-06-01"
+	// anthropicVersionHeaderName is the header to specify the version of the Anthropic API.
+	anthropicVersionHeaderName = "anthropic-version"
+	// anthropicBackendError is the error type used when the Anthropic API returns a non-JSON error.
+	anthropicBackendError = "AnthropicBackendError"
+)
+
+// NewChatCompletionOpenAIToAnthropicTranslator implements [Factory] for OpenAI to Anthropic translation.
+// This translator converts OpenAI ChatCompletion API requests to the native Anthropic Messages API.
+func NewChatCompletionOpenAIToAnthropicTranslator(apiVersion string, modelNameOverride internalapi.ModelNameOverride) OpenAIChatCompletionTranslator {
+	return &openAIToAnthropicTranslatorV1ChatCompletion{
+		openAIToGCPAnthropicTranslatorV1ChatCompletion: openAIToGCPAnthropicTranslatorV1ChatCompletion{
+			apiVersion:        apiVersion,
+			modelNameOverride: modelNameOverride,
+		},
+	}
+}
+
+// openAIToAnthropicTranslatorV1ChatCompletion translates OpenAI Chat Completions API to the Anthropic Messages API:
+// https://docs.claude.com/en/api/messages
+//
+// The responses of the Anthropic API are the same as those of Claude on Vertex AI, so the response translation is
+// shared with the GCP Anthropic translator. The request differs in that the model is in the body, the API version
+// is the anthropic-version header and the path is always /v1/messages.
+type openAIToAnthropicTranslatorV1ChatCompletion struct {
+	openAIToGCPAnthropicTranslatorV1ChatCompletion
+}
+
+// RequestBody implements [OpenAIChatCompletionTranslator.RequestBody] for Anthropic.
+func (o *openAIToAnthropicTranslatorV1ChatCompletion) RequestBody(_ []byte, openAIReq *openai.ChatCompletionRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	params, err := buildAnthropicParams(openAIReq, "Anthropic", o.modelNameOverride)
+	if err != nil {
+		return
+	}
+
+	o.requestModel = cmp.Or(o.modelNameOverride, openAIReq.Model)
+	params.Model = anthropic.Model(o.requestModel)
+
+	newBody, err = json.Marshal(params)
+	if err != nil {
+		return
+	}
+	if openAIReq.Stream {
+		newBody, err = sjson.SetBytesOptions(newBody, "stream", true, sjsonOptions)
+		if err != nil {
+			return
+		}
+		o.streamParser = newAnthropicStreamParser(o.requestModel)
+	}
+
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, "/v1/messages"},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+		{anthropicVersionHeaderName, cmp.Or(o.apiVersion, anthropicDefaultVersion)},
+	}
+	return
+}
+
+// ResponseError implements [OpenAIChatCompletionTranslator.ResponseError].
+func (o *openAIToAnthropicTranslatorV1ChatCompletion) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return convertAnthropicErrorToOpenAI(respHeaders, body, anthropicBackendError)
+}
@@ -0,0 +1,188 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/shared/constant"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestOpenAIToAnthropicTranslatorV1ChatCompletion_RequestBody(t *testing.T) {
+	openAIReq := &openai.ChatCompletionRequest{
+		Model:     claudeTestModel,
+		MaxTokens: ptr.To(int64(1024)),
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			{OfSystem: &openai.ChatCompletionSystemMessageParam{Content: openai.ContentUnion{Value: "You are a helpful assistant."}, Role: openai.ChatMessageRoleSystem}},
+			{OfUser: &openai.ChatCompletionUserMessageParam{
+				Content: openai.StringOrUserRoleContentUnion{
+					Value: []openai.ChatCompletionContentPartUserUnionParam{
+						{OfText: &openai.ChatCompletionContentPartTextParam{Text: "What is in this image?"}},
+						{OfImageURL: &openai.ChatCompletionContentPartImageParam{
+							ImageURL: openai.ChatCompletionContentPartImageImageURLParam{URL: "data:image/jpeg;base64,dGVzdA=="},
+						}},
+					},
+				},
+				Role: openai.ChatMessageRoleUser,
+			}},
+		},
+		Tools: []openai.Tool{{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:       "get_weather",
+				Parameters: map[string]any{"type": "object", "properties": map[string]any{"location": map[string]any{"type": "string"}}},
+			},
+		}},
+		Thinking: &openai.ThinkingUnion{OfEnabled: &openai.ThinkingEnabled{BudgetTokens: 100, Type: "enabled"}},
+	}
+
+	t.Run("non-streaming", func(t *testing.T) {
+		translator := NewChatCompletionOpenAIToAnthropicTranslator("", "")
+		hm, body, err := translator.RequestBody(nil, openAIReq, false)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{
+			{pathHeaderName, "/v1/messages"},
+			{contentLengthHeaderName, strconv.Itoa(len(body))},
+			{anthropicVersionHeaderName, anthropicDefaultVersion},
+		}, hm)
+
+		require.Equal(t, claudeTestModel, gjson.GetBytes(body, "model").String())
+		require.False(t, gjson.GetBytes(body, "stream").Exists())
+		require.False(t, gjson.GetBytes(body, anthropicVersionKey).Exists())
+		require.Equal(t, "You are a helpful assistant.", gjson.GetBytes(body, "system.0.text").String())
+		require.Equal(t, "image", gjson.GetBytes(body, "messages.0.content.1.type").String())
+		require.Equal(t, "get_weather", gjson.GetBytes(body, "tools.0.name").String())
+		require.Equal(t, "enabled", gjson.GetBytes(body, "thinking.type").String())
+		require.Equal(t, int64(100), gjson.GetBytes(body, "thinking.budget_tokens").Int())
+	})
+
+	t.Run("streaming with model name override and version", func(t *testing.T) {
+		streamReq := *openAIReq
+		streamReq.Stream = true
+		translator := NewChatCompletionOpenAIToAnthropicTranslator("2023-01-01", "claude-sonnet-4-5")
+		hm, body, err := translator.RequestBody(nil, &streamReq, false)
+		require.NoError(t, err)
+		require.Equal(t, internalapi.Header{anthropicVersionHeaderName, "2023-01-01"}, hm[2])
+		require.Equal(t, "claude-sonnet-4-5", gjson.GetBytes(body, "model").String())
+		require.True(t, gjson.GetBytes(body, "stream").Bool())
+
+		headers, err := translator.ResponseHeaders(nil)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}, headers)
+	})
+}
+
+func TestOpenAIToAnthropicTranslatorV1ChatCompletion_ResponseBody(t *testing.T) {
+	translator := NewChatCompletionOpenAIToAnthropicTranslator("", "")
+	_, _, err := translator.RequestBody(nil, &openai.ChatCompletionRequest{Model: claudeTestModel, MaxTokens: ptr.To(int64(100))}, false)
+	require.NoError(t, err)
+
+	body, err := json.Marshal(anthropic.Message{
+		ID:         "msg_01XYZ",
+		Model:      "claude-3-opus-20240229",
+		Role:       constant.Assistant(anthropic.MessageParamRoleAssistant),
+		Content:    []anthropic.ContentBlockUnion{{Type: "text", Text: "Hello!"}},
+		StopReason: anthropic.StopReasonEndTurn,
+		Usage:      anthropic.Usage{InputTokens: 10, OutputTokens: 5, CacheReadInputTokens: 4, CacheCreationInputTokens: 2},
+	})
+	require.NoError(t, err)
+
+	hm, newBody, tokenUsage, responseModel, err := translator.ResponseBody(nil, bytes.NewReader(body), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}, hm)
+	require.Equal(t, "claude-3-opus-20240229", responseModel)
+	require.Equal(t, tokenUsageFrom(16, 4, 2, 5, 21, -1), tokenUsage)
+
+	var resp openai.ChatCompletionResponse
+	require.NoError(t, json.Unmarshal(newBody, &resp))
+	require.Equal(t, "Hello!", *resp.Choices[0].Message.Content)
+	require.Equal(t, openai.ChatCompletionChoicesFinishReasonStop, resp.Choices[0].FinishReason)
+}
+
+func TestOpenAIToAnthropicTranslatorV1ChatCompletion_ResponseBody_Streaming(t *testing.T) {
+	sseStream := `event: message_start
+data: {"type": "message_start", "message": {"id": "msg_1", "type": "message", "role": "assistant", "content": [], "model": "claude-3-opus-20240229", "stop_reason": null, "stop_sequence": null, "usage": {"input_tokens": 10, "output_tokens": 1}}}
+
+event: content_block_start
+data: {"type": "content_block_start", "index": 0, "content_block": {"type": "text", "text": ""}}
+
+event: content_block_delta
+data: {"type": "content_block_delta", "index": 0, "delta": {"type": "text_delta", "text": "Hello"}}
+
+event: content_block_stop
+data: {"type": "content_block_stop", "index": 0}
+
+event: message_delta
+data: {"type": "message_delta", "delta": {"stop_reason": "end_turn", "stop_sequence":null}, "usage": {"output_tokens": 5}}
+
+event: message_stop
+data: {"type": "message_stop"}
+
+`
+	translator := NewChatCompletionOpenAIToAnthropicTranslator("", "")
+	_, _, err := translator.RequestBody(nil, &openai.ChatCompletionRequest{Model: claudeTestModel, Stream: true, MaxTokens: ptr.To(int64(100))}, false)
+	require.NoError(t, err)
+
+	_, body, tokenUsage, responseModel, err := translator.ResponseBody(nil, strings.NewReader(sseStream), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, claudeTestModel, responseModel)
+	require.Contains(t, string(body), `"content":"Hello"`)
+	require.Contains(t, string(body), "data: [DONE]")
+	inputTokens, ok := tokenUsage.InputTokens()
+	require.True(t, ok)
+	require.Equal(t, uint32(10), inputTokens)
+	outputTokens, ok := tokenUsage.OutputTokens()
+	require.True(t, ok)
+	require.Equal(t, uint32(5), outputTokens)
+}
+
+func TestOpenAIToAnthropicTranslatorV1ChatCompletion_ResponseError(t *testing.T) {
+	translator := NewChatCompletionOpenAIToAnthropicTranslator("", "")
+	for _, tc := range []struct {
+		name       string
+		headers    map[string]string
+		body       string
+		expType    string
+		expMessage string
+	}{
+		{
+			name:       "anthropic error",
+			headers:    map[string]string{statusHeaderName: "429", contentTypeHeaderName: jsonContentType},
+			body:       `{"type":"error","error":{"type":"rate_limit_error","message":"Number of requests has exceeded your rate limit"}}`,
+			expType:    "rate_limit_error",
+			expMessage: "Number of requests has exceeded your rate limit",
+		},
+		{
+			name:       "non-json error",
+			headers:    map[string]string{statusHeaderName: "503", contentTypeHeaderName: "text/plain"},
+			body:       "Service Unavailable",
+			expType:    anthropicBackendError,
+			expMessage: "Service Unavailable",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			hm, body, err := translator.ResponseError(tc.headers, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			require.Len(t, hm, 2)
+			var openAIErr openai.Error
+			require.NoError(t, json.Unmarshal(body, &openAIErr))
+			require.Equal(t, tc.expType, openAIErr.Error.Type)
+			require.Equal(t, tc.expMessage, openAIErr.Error.Message)
+			require.Equal(t, tc.headers[statusHeaderName], *openAIErr.Error.Code)
+		})
+	}
+}
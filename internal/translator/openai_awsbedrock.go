@@ -827,17 +827,23 @@ func (o *openAIToAWSBedrockTranslatorV1ChatCompletion) ResponseBody(_ map[string
 // extractAmazonEventStreamEvents extracts [awsbedrock.ConverseStreamEvent] from the buffered body.
 // The extracted events are stored in the processor's events field.
 func (o *openAIToAWSBedrockTranslatorV1ChatCompletion) extractAmazonEventStreamEvents() {
+	o.bufferedBody, o.events = decodeConverseStreamEvents(o.bufferedBody, o.events)
+}
+
+// decodeConverseStreamEvents decodes the complete [awsbedrock.ConverseStreamEvent] messages in the buffered
+// AWS eventstream body into events, reusing its backing array. It returns the undecoded remainder of the buffer,
+// which is a partial message to be completed by the next chunk, and the decoded events.
+func decodeConverseStreamEvents(buffered []byte, events []awsbedrock.ConverseStreamEvent) ([]byte, []awsbedrock.ConverseStreamEvent) {
 	// TODO: Maybe reuse the reader and decoder.
-	r := bytes.NewReader(o.bufferedBody)
+	r := bytes.NewReader(buffered)
 	dec := eventstream.NewDecoder()
-	clear(o.events)
-	o.events = o.events[:0]
+	clear(events)
+	events = events[:0]
 	var lastRead int64
 	for {
 		msg, err := dec.Decode(r, nil)
 		if err != nil {
-			o.bufferedBody = o.bufferedBody[lastRead:]
-			return
+			return buffered[lastRead:], events
 		}
 		var event awsbedrock.ConverseStreamEvent
 		eventType := msg.Headers.Get(":event-type")
@@ -845,7 +851,7 @@ func (o *openAIToAWSBedrockTranslatorV1ChatCompletion) extractAmazonEventStreamE
 			event.EventType = eventType.String()
 		}
 		if err := json.Unmarshal(msg.Payload, &event); err == nil {
-			o.events = append(o.events, event)
+			events = append(events, event)
 		}
 		lastRead = r.Size() - int64(r.Len())
 	}
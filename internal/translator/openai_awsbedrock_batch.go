@@ -0,0 +1,229 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewBatchesOpenAIToAWSBedrockTranslator implements [OpenAIBatchesTranslator] for OpenAI to AWS Bedrock batch
+// inference translation for /v1/batches.
+func NewBatchesOpenAIToAWSBedrockTranslator(modelNameOverride internalapi.ModelNameOverride) OpenAIBatchesTranslator {
+	return &openAIToAWSBedrockTranslatorV1Batches{modelNameOverride: modelNameOverride}
+}
+
+// openAIToAWSBedrockTranslatorV1Batches translates the OpenAI Batch API to the AWS Bedrock batch inference jobs:
+// https://docs.aws.amazon.com/bedrock/latest/userguide/batch-inference.html
+//
+// The input of the batch must already be in S3 in the format of AWS Bedrock, so the input file ID is the S3 URI of
+// the input. The allowed input location, the output location and the service role of the job are taken from the
+// batch configuration of the backend.
+//
+// The batch inference jobs are served by the AWS Bedrock control plane, bedrock.<region>.amazonaws.com, rather than
+// the runtime endpoint of the other APIs, so the backend must point to the control plane.
+type openAIToAWSBedrockTranslatorV1Batches struct {
+	modelNameOverride internalapi.ModelNameOverride
+	// config is the batch configuration of the backend. Nil if the backend has none.
+	config *filterapi.BackendBatch
+	// originalModel is the original model of the request, which the batch IDs are scoped to.
+	originalModel internalapi.OriginalModel
+	// requestModel is the model of the request after the override, which is used as the model of the job.
+	requestModel internalapi.RequestModel
+	// req is the request, which is used to build the batch of the creation and cancellation responses.
+	req *openai.BatchRequest
+}
+
+// SetBatchConfig implements [BatchConfigurer.SetBatchConfig].
+func (o *openAIToAWSBedrockTranslatorV1Batches) SetBatchConfig(config *filterapi.BackendBatch) {
+	o.config = config
+}
+
+// RequestBody implements [OpenAIBatchesTranslator.RequestBody].
+func (o *openAIToAWSBedrockTranslatorV1Batches) RequestBody(_ []byte, req *openai.BatchRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.originalModel, o.requestModel, o.req = req.Model, cmp.Or(o.modelNameOverride, req.Model), req
+	switch req.Operation {
+	case openai.BatchOperationCreate:
+		var job *awsbedrock.CreateModelInvocationJobInput
+		if job, err = o.openAIBatchToModelInvocationJob(req); err != nil {
+			return nil, nil, err
+		}
+		if newBody, err = json.Marshal(job); err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+		}
+		newHeaders = []internalapi.Header{
+			{pathHeaderName, "/model-invocation-job"},
+			{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+		}
+	case openai.BatchOperationRetrieve:
+		newHeaders = []internalapi.Header{{pathHeaderName, "/model-invocation-job/" + url.PathEscape(req.BatchID)}}
+	case openai.BatchOperationCancel:
+		newHeaders = []internalapi.Header{{pathHeaderName, "/model-invocation-job/" + url.PathEscape(req.BatchID) + "/stop"}}
+	}
+	return
+}
+
+// openAIBatchToModelInvocationJob converts the OpenAI batch creation request to the AWS Bedrock batch inference job.
+func (o *openAIToAWSBedrockTranslatorV1Batches) openAIBatchToModelInvocationJob(req *openai.BatchRequest) (*awsbedrock.CreateModelInvocationJobInput, error) {
+	if err := validateStorageBatchRequest(req, o.config, "AWS Bedrock"); err != nil {
+		return nil, err
+	}
+	if o.config.RoleARN == "" {
+		return nil, errors.New("the batch configuration of the AWS Bedrock backend has no role ARN")
+	}
+	job := &awsbedrock.CreateModelInvocationJobInput{
+		JobName: "batch-" + uuid.New().String(),
+		RoleArn: o.config.RoleARN,
+		ModelID: o.requestModel,
+		InputDataConfig: awsbedrock.ModelInvocationJobInputDataConfig{
+			S3InputDataConfig: awsbedrock.ModelInvocationJobS3InputDataConfig{S3URI: req.InputFileID, S3InputFormat: "JSONL"},
+		},
+		OutputDataConfig: awsbedrock.ModelInvocationJobOutputDataConfig{
+			S3OutputDataConfig: awsbedrock.ModelInvocationJobS3OutputDataConfig{S3URI: o.config.OutputURI},
+		},
+	}
+	if window, err := time.ParseDuration(req.CompletionWindow); err == nil && window >= time.Hour {
+		job.TimeoutDurationInHours = ptr.To(int(window.Hours()))
+	}
+	return job, nil
+}
+
+// ResponseHeaders implements [OpenAIBatchesTranslator.ResponseHeaders].
+func (o *openAIToAWSBedrockTranslatorV1Batches) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIBatchesTranslator.ResponseBody].
+//
+// The token usage of the batch is not supported since the batch inference jobs do not report it, so the token usage
+// is always empty.
+func (o *openAIToAWSBedrockTranslatorV1Batches) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.BatchesSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	responseModel = o.requestModel
+	var batch *openai.Batch
+	switch o.req.Operation {
+	case openai.BatchOperationCreate:
+		var created awsbedrock.CreateModelInvocationJobOutput
+		if err = json.NewDecoder(body).Decode(&created); err != nil {
+			return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to unmarshal body: %w", err)
+		}
+		batch = &openai.Batch{
+			ID:               internalapi.EncodeModelScopedID(openai.BatchIDPrefix, o.originalModel, created.JobArn),
+			Object:           "batch",
+			Endpoint:         o.req.Endpoint,
+			Model:            o.requestModel,
+			InputFileID:      o.req.InputFileID,
+			CompletionWindow: o.req.CompletionWindow,
+			Status:           openai.BatchStatusValidating,
+			CreatedAt:        time.Now().Unix(),
+			Metadata:         o.req.Metadata,
+		}
+	case openai.BatchOperationCancel:
+		// The response of StopModelInvocationJob is empty.
+		batch = &openai.Batch{
+			ID:     internalapi.EncodeModelScopedID(openai.BatchIDPrefix, o.originalModel, o.req.BatchID),
+			Object: "batch",
+			Model:  o.requestModel,
+			Status: openai.BatchStatusCancelling,
+		}
+	default:
+		var job awsbedrock.GetModelInvocationJobOutput
+		if err = json.NewDecoder(body).Decode(&job); err != nil {
+			return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to unmarshal body: %w", err)
+		}
+		batch = o.modelInvocationJobToOpenAIBatch(&job)
+	}
+
+	newBody, err = json.Marshal(batch)
+	if err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(batch)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// modelInvocationJobToOpenAIBatch converts the AWS Bedrock batch inference job to the OpenAI batch.
+// The output file ID is the S3 URI of the output location of the job.
+func (o *openAIToAWSBedrockTranslatorV1Batches) modelInvocationJobToOpenAIBatch(job *awsbedrock.GetModelInvocationJobOutput) *openai.Batch {
+	batch := &openai.Batch{
+		ID:          internalapi.EncodeModelScopedID(openai.BatchIDPrefix, o.originalModel, job.JobArn),
+		Object:      "batch",
+		Model:       cmp.Or(job.ModelID, o.requestModel),
+		InputFileID: job.InputDataConfig.S3InputDataConfig.S3URI,
+		Status:      modelInvocationJobStatusToOpenAI(job.Status),
+	}
+	if job.SubmitTime != nil {
+		batch.CreatedAt = job.SubmitTime.Unix()
+	}
+	if job.JobExpirationTime != nil {
+		batch.ExpiresAt = ptr.To(job.JobExpirationTime.Unix())
+	}
+	switch batch.Status {
+	case openai.BatchStatusCompleted:
+		batch.OutputFileID = job.OutputDataConfig.S3OutputDataConfig.S3URI
+		if job.EndTime != nil {
+			batch.CompletedAt = ptr.To(job.EndTime.Unix())
+		}
+	case openai.BatchStatusFailed:
+		batch.Errors = &openai.BatchErrors{Object: "list", Data: []openai.BatchError{{Code: string(job.Status), Message: job.Message}}}
+		if job.EndTime != nil {
+			batch.FailedAt = ptr.To(job.EndTime.Unix())
+		}
+	case openai.BatchStatusCancelled:
+		if job.EndTime != nil {
+			batch.CancelledAt = ptr.To(job.EndTime.Unix())
+		}
+	}
+	return batch
+}
+
+// modelInvocationJobStatusToOpenAI converts the status of the AWS Bedrock batch inference job to the OpenAI batch status.
+func modelInvocationJobStatusToOpenAI(status awsbedrock.ModelInvocationJobStatus) openai.BatchStatus {
+	switch status {
+	case awsbedrock.ModelInvocationJobStatusInProgress:
+		return openai.BatchStatusInProgress
+	case awsbedrock.ModelInvocationJobStatusCompleted, awsbedrock.ModelInvocationJobStatusPartiallyCompleted:
+		return openai.BatchStatusCompleted
+	case awsbedrock.ModelInvocationJobStatusFailed:
+		return openai.BatchStatusFailed
+	case awsbedrock.ModelInvocationJobStatusStopping:
+		return openai.BatchStatusCancelling
+	case awsbedrock.ModelInvocationJobStatusStopped:
+		return openai.BatchStatusCancelled
+	case awsbedrock.ModelInvocationJobStatusExpired:
+		return openai.BatchStatusExpired
+	default:
+		// Submitted, Validating and Scheduled.
+		return openai.BatchStatusValidating
+	}
+}
+
+// ResponseError implements [OpenAIBatchesTranslator.ResponseError].
+func (o *openAIToAWSBedrockTranslatorV1Batches) ResponseError(respHeaders map[string]string, body io.Reader) ([]internalapi.Header, []byte, error) {
+	return convertAWSBedrockErrorToOpenAI(respHeaders, body)
+}
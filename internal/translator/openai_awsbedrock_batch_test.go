@@ -0,0 +1,277 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+const testBedrockJobArn = "arn:aws:bedrock:us-east-1:123456789012:model-invocation-job/abc123"
+
+var testBedrockBatchConfig = &filterapi.BackendBatch{
+	InputURIPrefix: "s3://bucket/input/",
+	OutputURI:      "s3://bucket/output/",
+	RoleARN:        "arn:aws:iam::123456789012:role/batch",
+}
+
+// newTestBedrockBatchesTranslator returns the AWS Bedrock batch translator with the batch configuration set.
+func newTestBedrockBatchesTranslator(modelNameOverride string, config *filterapi.BackendBatch) OpenAIBatchesTranslator {
+	translator := NewBatchesOpenAIToAWSBedrockTranslator(modelNameOverride)
+	translator.(BatchConfigurer).SetBatchConfig(config)
+	return translator
+}
+
+func TestOpenAIToAWSBedrockTranslatorV1Batches_RequestBody(t *testing.T) {
+	t.Run("create", func(t *testing.T) {
+		req := &openai.BatchRequest{
+			InputFileID:      "s3://bucket/input/batch.jsonl",
+			Endpoint:         "/v1/chat/completions",
+			CompletionWindow: "24h",
+			Metadata:         map[string]string{internalapi.BatchMetadataModel: "claude-3-haiku"},
+			Model:            "claude-3-haiku",
+			Operation:        openai.BatchOperationCreate,
+		}
+		translator := newTestBedrockBatchesTranslator("anthropic.claude-3-haiku-20240307-v1:0", testBedrockBatchConfig)
+		headers, body, err := translator.RequestBody(nil, req, false)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{
+			{pathHeaderName, "/model-invocation-job"},
+			{contentLengthHeaderName, strconv.Itoa(len(body))},
+		}, headers)
+
+		var job awsbedrock.CreateModelInvocationJobInput
+		require.NoError(t, json.Unmarshal(body, &job))
+		require.True(t, strings.HasPrefix(job.JobName, "batch-"))
+		job.JobName = ""
+		require.Equal(t, awsbedrock.CreateModelInvocationJobInput{
+			RoleArn: "arn:aws:iam::123456789012:role/batch",
+			ModelID: "anthropic.claude-3-haiku-20240307-v1:0",
+			InputDataConfig: awsbedrock.ModelInvocationJobInputDataConfig{
+				S3InputDataConfig: awsbedrock.ModelInvocationJobS3InputDataConfig{S3URI: "s3://bucket/input/batch.jsonl", S3InputFormat: "JSONL"},
+			},
+			OutputDataConfig: awsbedrock.ModelInvocationJobOutputDataConfig{
+				S3OutputDataConfig: awsbedrock.ModelInvocationJobS3OutputDataConfig{S3URI: "s3://bucket/output/"},
+			},
+			TimeoutDurationInHours: ptr.To(24),
+		}, job)
+	})
+
+	for _, tc := range []struct {
+		name     string
+		config   *filterapi.BackendBatch
+		req      *openai.BatchRequest
+		expError string
+	}{
+		{
+			name: "no batch config",
+			req: &openai.BatchRequest{
+				InputFileID: "s3://bucket/input/batch.jsonl",
+				Operation:   openai.BatchOperationCreate,
+			},
+			expError: "batches are not configured on the AWS Bedrock backend",
+		},
+		{
+			name:   "input file not under the input prefix",
+			config: testBedrockBatchConfig,
+			req: &openai.BatchRequest{
+				InputFileID: "s3://other-bucket/input.jsonl",
+				Operation:   openai.BatchOperationCreate,
+			},
+			expError: "input_file_id must be the URI of the batch input under s3://bucket/input/",
+		},
+		{
+			name:   "output URI in metadata",
+			config: testBedrockBatchConfig,
+			req: &openai.BatchRequest{
+				InputFileID: "s3://bucket/input/batch.jsonl",
+				Metadata:    map[string]string{internalapi.BatchMetadataOutputURI: "s3://attacker/output/"},
+				Operation:   openai.BatchOperationCreate,
+			},
+			expError: "metadata.output_uri is not accepted",
+		},
+		{
+			name:   "role ARN in metadata",
+			config: testBedrockBatchConfig,
+			req: &openai.BatchRequest{
+				InputFileID: "s3://bucket/input/batch.jsonl",
+				Metadata:    map[string]string{internalapi.BatchMetadataRoleARN: "arn:aws:iam::123456789012:role/admin"},
+				Operation:   openai.BatchOperationCreate,
+			},
+			expError: "metadata.role_arn is not accepted",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := newTestBedrockBatchesTranslator("", tc.config).RequestBody(nil, tc.req, false)
+			require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+			require.ErrorContains(t, err, tc.expError)
+		})
+	}
+
+	t.Run("no role ARN in batch config", func(t *testing.T) {
+		config := &filterapi.BackendBatch{InputURIPrefix: "s3://bucket/input/", OutputURI: "s3://bucket/output/"}
+		_, _, err := newTestBedrockBatchesTranslator("", config).RequestBody(nil,
+			&openai.BatchRequest{InputFileID: "s3://bucket/input/batch.jsonl", Operation: openai.BatchOperationCreate}, false)
+		require.EqualError(t, err, "the batch configuration of the AWS Bedrock backend has no role ARN")
+	})
+
+	t.Run("retrieve", func(t *testing.T) {
+		headers, body, err := NewBatchesOpenAIToAWSBedrockTranslator("").RequestBody(nil,
+			&openai.BatchRequest{Operation: openai.BatchOperationRetrieve, BatchID: testBedrockJobArn}, false)
+		require.NoError(t, err)
+		require.Nil(t, body)
+		require.Equal(t, []internalapi.Header{
+			{pathHeaderName, "/model-invocation-job/arn:aws:bedrock:us-east-1:123456789012:model-invocation-job%2Fabc123"},
+		}, headers)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		headers, _, err := NewBatchesOpenAIToAWSBedrockTranslator("").RequestBody(nil,
+			&openai.BatchRequest{Operation: openai.BatchOperationCancel, BatchID: testBedrockJobArn}, false)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{
+			{pathHeaderName, "/model-invocation-job/arn:aws:bedrock:us-east-1:123456789012:model-invocation-job%2Fabc123/stop"},
+		}, headers)
+	})
+}
+
+func TestOpenAIToAWSBedrockTranslatorV1Batches_ResponseBody(t *testing.T) {
+	expID := internalapi.EncodeModelScopedID(openai.BatchIDPrefix, "claude-3-haiku", testBedrockJobArn)
+
+	t.Run("create", func(t *testing.T) {
+		req := &openai.BatchRequest{
+			InputFileID:      "s3://bucket/input/batch.jsonl",
+			Endpoint:         "/v1/chat/completions",
+			CompletionWindow: "24h",
+			Metadata:         map[string]string{internalapi.BatchMetadataModel: "claude-3-haiku"},
+			Model:            "claude-3-haiku",
+			Operation:        openai.BatchOperationCreate,
+		}
+		translator := newTestBedrockBatchesTranslator("", testBedrockBatchConfig)
+		_, _, err := translator.RequestBody(nil, req, false)
+		require.NoError(t, err)
+
+		headers, body, _, responseModel, err := translator.ResponseBody(nil, strings.NewReader(`{"jobArn":"`+testBedrockJobArn+`"}`), true, nil)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(body))}}, headers)
+		require.Equal(t, "claude-3-haiku", responseModel)
+
+		var batch openai.Batch
+		require.NoError(t, json.Unmarshal(body, &batch))
+		require.Equal(t, expID, batch.ID)
+		require.Equal(t, openai.BatchStatusValidating, batch.Status)
+		require.Equal(t, "s3://bucket/input/batch.jsonl", batch.InputFileID)
+		require.Equal(t, req.Metadata, batch.Metadata)
+		require.NotZero(t, batch.CreatedAt)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		translator := NewBatchesOpenAIToAWSBedrockTranslator("")
+		_, _, err := translator.RequestBody(nil,
+			&openai.BatchRequest{Model: "claude-3-haiku", Operation: openai.BatchOperationCancel, BatchID: testBedrockJobArn}, false)
+		require.NoError(t, err)
+
+		_, body, _, _, err := translator.ResponseBody(nil, strings.NewReader(""), true, nil)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"id":"`+expID+`","object":"batch","endpoint":"","model":"claude-3-haiku","input_file_id":"",`+
+			`"completion_window":"","status":"cancelling","created_at":0}`, string(body))
+	})
+
+	for _, tc := range []struct {
+		name     string
+		job      string
+		expBatch openai.Batch
+	}{
+		{
+			name: "completed",
+			job: `{"jobArn":"` + testBedrockJobArn + `","modelId":"anthropic.claude-3-haiku-20240307-v1:0","status":"Completed",` +
+				`"inputDataConfig":{"s3InputDataConfig":{"s3Uri":"s3://bucket/input.jsonl"}},` +
+				`"outputDataConfig":{"s3OutputDataConfig":{"s3Uri":"s3://bucket/output/"}},` +
+				`"submitTime":"2025-01-01T00:00:00Z","endTime":"2025-01-01T01:00:00Z","jobExpirationTime":"2025-01-02T00:00:00Z"}`,
+			expBatch: openai.Batch{
+				ID:           expID,
+				Object:       "batch",
+				Model:        "anthropic.claude-3-haiku-20240307-v1:0",
+				InputFileID:  "s3://bucket/input.jsonl",
+				OutputFileID: "s3://bucket/output/",
+				Status:       openai.BatchStatusCompleted,
+				CreatedAt:    1735689600,
+				ExpiresAt:    ptr.To[int64](1735776000),
+				CompletedAt:  ptr.To[int64](1735693200),
+			},
+		},
+		{
+			name: "failed",
+			job: `{"jobArn":"` + testBedrockJobArn + `","modelId":"anthropic.claude-3-haiku-20240307-v1:0","status":"Failed",` +
+				`"message":"access denied","inputDataConfig":{"s3InputDataConfig":{"s3Uri":"s3://bucket/input.jsonl"}},` +
+				`"outputDataConfig":{"s3OutputDataConfig":{"s3Uri":"s3://bucket/output/"}},"endTime":"2025-01-01T01:00:00Z"}`,
+			expBatch: openai.Batch{
+				ID:          expID,
+				Object:      "batch",
+				Model:       "anthropic.claude-3-haiku-20240307-v1:0",
+				InputFileID: "s3://bucket/input.jsonl",
+				Status:      openai.BatchStatusFailed,
+				Errors:      &openai.BatchErrors{Object: "list", Data: []openai.BatchError{{Code: "Failed", Message: "access denied"}}},
+				FailedAt:    ptr.To[int64](1735693200),
+			},
+		},
+		{
+			name: "scheduled",
+			job: `{"jobArn":"` + testBedrockJobArn + `","status":"Scheduled",` +
+				`"inputDataConfig":{"s3InputDataConfig":{"s3Uri":"s3://bucket/input.jsonl"}},` +
+				`"outputDataConfig":{"s3OutputDataConfig":{"s3Uri":"s3://bucket/output/"}}}`,
+			expBatch: openai.Batch{
+				ID:          expID,
+				Object:      "batch",
+				Model:       "claude-3-haiku",
+				InputFileID: "s3://bucket/input.jsonl",
+				Status:      openai.BatchStatusValidating,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			translator := NewBatchesOpenAIToAWSBedrockTranslator("")
+			_, _, err := translator.RequestBody(nil,
+				&openai.BatchRequest{Model: "claude-3-haiku", Operation: openai.BatchOperationRetrieve, BatchID: testBedrockJobArn}, false)
+			require.NoError(t, err)
+
+			_, body, tokenUsage, _, err := translator.ResponseBody(nil, strings.NewReader(tc.job), true, nil)
+			require.NoError(t, err)
+			var batch openai.Batch
+			require.NoError(t, json.Unmarshal(body, &batch))
+			require.Equal(t, tc.expBatch, batch)
+			_, ok := tokenUsage.InputTokens()
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestModelInvocationJobStatusToOpenAI(t *testing.T) {
+	for status, exp := range map[awsbedrock.ModelInvocationJobStatus]openai.BatchStatus{
+		awsbedrock.ModelInvocationJobStatusSubmitted:          openai.BatchStatusValidating,
+		awsbedrock.ModelInvocationJobStatusValidating:         openai.BatchStatusValidating,
+		awsbedrock.ModelInvocationJobStatusScheduled:          openai.BatchStatusValidating,
+		awsbedrock.ModelInvocationJobStatusInProgress:         openai.BatchStatusInProgress,
+		awsbedrock.ModelInvocationJobStatusCompleted:          openai.BatchStatusCompleted,
+		awsbedrock.ModelInvocationJobStatusPartiallyCompleted: openai.BatchStatusCompleted,
+		awsbedrock.ModelInvocationJobStatusFailed:             openai.BatchStatusFailed,
+		awsbedrock.ModelInvocationJobStatusStopping:           openai.BatchStatusCancelling,
+		awsbedrock.ModelInvocationJobStatusStopped:            openai.BatchStatusCancelled,
+		awsbedrock.ModelInvocationJobStatusExpired:            openai.BatchStatusExpired,
+	} {
+		require.Equal(t, exp, modelInvocationJobStatusToOpenAI(status), status)
+	}
+}
@@ -6,6 +6,7 @@
 package translator
 
 import (
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/url"
@@ -26,9 +27,18 @@ func NewEmbeddingOpenAIToAWSBedrockTranslator(modelNameOverride internalapi.Mode
 }
 
 // openAIToAWSBedrockTranslatorV1Embedding translates OpenAI embedding requests to AWS Bedrock InvokeModel requests.
+// The Titan Multimodal Embeddings model embeds the text and the image of the input together into a single embedding.
 type openAIToAWSBedrockTranslatorV1Embedding struct {
 	modelNameOverride internalapi.ModelNameOverride
 	requestModel      internalapi.RequestModel
+	// base64Encoding is true when the embedding must be returned base64 encoded to the client.
+	base64Encoding bool
+}
+
+// isTitanMultimodalEmbeddingModel returns true when the model is the Titan Multimodal Embeddings model,
+// e.g. amazon.titan-embed-image-v1 or its inference profile.
+func isTitanMultimodalEmbeddingModel(model internalapi.RequestModel) bool {
+	return strings.Contains(model, "titan-embed-image")
 }
 
 // RequestBody implements [OpenAIEmbeddingTranslator.RequestBody].
@@ -41,23 +51,35 @@ func (o *openAIToAWSBedrockTranslatorV1Embedding) RequestBody(_ []byte, req *ope
 	}
 	o.requestModel = model
 
-	var inputText string
-	switch v := req.Input.Value.(type) {
-	case string:
-		inputText = v
-	case []string:
-		if len(v) != 1 {
-			return nil, nil, fmt.Errorf("%w: AWS Bedrock Titan does not support batch embeddings (got %d inputs)",
-				internalapi.ErrInvalidRequestBody, len(v))
-		}
-		inputText = v[0]
-	default:
-		return nil, nil, fmt.Errorf("%w: unsupported input type %T", internalapi.ErrInvalidRequestBody, req.Input.Value)
-	}
+	o.base64Encoding = isBase64EncodingFormat(req)
 
-	bedrockReq := awsbedrock.TitanEmbeddingRequest{
-		InputText:  inputText,
-		Dimensions: req.Dimensions,
+	inputs, err := embeddingInputs(req.Input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", internalapi.ErrInvalidRequestBody, err)
+	}
+	if len(inputs) != 1 {
+		return nil, nil, fmt.Errorf("%w: AWS Bedrock Titan does not support batch embeddings (got %d inputs)",
+			internalapi.ErrInvalidRequestBody, len(inputs))
+	}
+	input := inputs[0]
+
+	bedrockReq := awsbedrock.TitanEmbeddingRequest{InputText: input.text}
+	if isTitanMultimodalEmbeddingModel(model) {
+		if input.imageURL != "" {
+			var image []byte
+			if _, image, err = parseDataURI(input.imageURL); err != nil {
+				return nil, nil, fmt.Errorf("%w: image must be a base64 encoded data URL: %w", internalapi.ErrInvalidRequestBody, err)
+			}
+			bedrockReq.InputImage = base64.StdEncoding.EncodeToString(image)
+		}
+		if req.Dimensions != nil {
+			bedrockReq.EmbeddingConfig = &awsbedrock.TitanEmbeddingConfig{OutputEmbeddingLength: *req.Dimensions}
+		}
+	} else {
+		if input.imageURL != "" {
+			return nil, nil, fmt.Errorf("%w: image inputs are only supported by the Titan Multimodal Embeddings model", internalapi.ErrInvalidRequestBody)
+		}
+		bedrockReq.Dimensions = req.Dimensions
 	}
 
 	mutatedBody, err = json.Marshal(bedrockReq)
@@ -98,7 +120,7 @@ func (o *openAIToAWSBedrockTranslatorV1Embedding) ResponseBody(_ map[string]stri
 			{
 				Object:    "embedding",
 				Index:     0,
-				Embedding: openai.EmbeddingUnion{Value: titanResp.Embedding},
+				Embedding: embeddingUnion(titanResp.Embedding, o.base64Encoding),
 			},
 		},
 		Usage: openai.EmbeddingUsage{
@@ -131,6 +153,15 @@ func (o *openAIToAWSBedrockTranslatorV1Embedding) ResponseBody(_ map[string]stri
 func (o *openAIToAWSBedrockTranslatorV1Embedding) ResponseError(
 	respHeaders map[string]string,
 	body io.Reader,
+) ([]internalapi.Header, []byte, error) {
+	return convertAWSBedrockErrorToOpenAI(respHeaders, body)
+}
+
+// convertAWSBedrockErrorToOpenAI converts AWS Bedrock InvokeModel error responses to OpenAI error format.
+// This is a shared function used by both embedding and image generation translators.
+func convertAWSBedrockErrorToOpenAI(
+	respHeaders map[string]string,
+	body io.Reader,
 ) ([]internalapi.Header, []byte, error) {
 	statusCode := respHeaders[statusHeaderName]
 	contentType := respHeaders[contentTypeHeaderName]
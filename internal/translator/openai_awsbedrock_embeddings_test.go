@@ -115,6 +115,40 @@ func TestEmbeddingOpenAIToAWSBedrockTranslator_RequestBody(t *testing.T) {
 			wantPath:         "/model/amazon.titan-embed-text-v1:2/invoke",
 			wantBodyContains: []string{`"inputText":"test"`},
 		},
+		{
+			name: "multimodal model - text and image embedded together",
+			input: openai.EmbeddingRequest{
+				Model: "amazon.titan-embed-image-v1",
+				Input: openai.EmbeddingRequestInput{Value: openai.EmbeddingInputItem{Content: openai.EmbeddingContent{Value: []openai.EmbeddingContentPart{
+					{Type: openai.EmbeddingContentPartTypeText, Text: "a cat"},
+					{Type: openai.EmbeddingContentPartTypeImageURL, ImageURL: &openai.ChatCompletionContentPartImageImageURLParam{URL: "data:image/png;base64,AAEC"}},
+				}}}},
+				Dimensions: &[]int{384}[0],
+			},
+			wantPath:            "/model/amazon.titan-embed-image-v1/invoke",
+			wantBodyContains:    []string{`"inputText":"a cat"`, `"inputImage":"AAEC"`, `"embeddingConfig":{"outputEmbeddingLength":384}`},
+			wantBodyNotContains: []string{`"dimensions"`},
+		},
+		{
+			name: "multimodal model - image must be a data URL",
+			input: openai.EmbeddingRequest{
+				Model: "amazon.titan-embed-image-v1",
+				Input: openai.EmbeddingRequestInput{Value: openai.EmbeddingInputItem{Content: openai.EmbeddingContent{Value: []openai.EmbeddingContentPart{
+					{Type: openai.EmbeddingContentPartTypeImageURL, ImageURL: &openai.ChatCompletionContentPartImageImageURLParam{URL: "https://example.com/cat.png"}},
+				}}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "text model - image rejected",
+			input: openai.EmbeddingRequest{
+				Model: "amazon.titan-embed-text-v2:0",
+				Input: openai.EmbeddingRequestInput{Value: openai.EmbeddingInputItem{Content: openai.EmbeddingContent{Value: []openai.EmbeddingContentPart{
+					{Type: openai.EmbeddingContentPartTypeImageURL, ImageURL: &openai.ChatCompletionContentPartImageImageURLParam{URL: "data:image/png;base64,AAEC"}},
+				}}}},
+			},
+			wantErr: true,
+		},
 		{
 			name: "model with spaces is path-escaped",
 			input: openai.EmbeddingRequest{
@@ -542,3 +576,20 @@ func TestEmbeddingOpenAIToAWSBedrockTranslator_ResponseError(t *testing.T) {
 		})
 	}
 }
+
+func TestEmbeddingOpenAIToAWSBedrockTranslator_ResponseBody_Base64(t *testing.T) {
+	translator := NewEmbeddingOpenAIToAWSBedrockTranslator("")
+	encodingFormat := "base64"
+	req := &openai.EmbeddingRequest{
+		Model:          "amazon.titan-embed-text-v2:0",
+		Input:          openai.EmbeddingRequestInput{Value: "a"},
+		EncodingFormat: &encodingFormat,
+	}
+	_, _, err := translator.RequestBody(nil, req, false)
+	require.NoError(t, err)
+
+	_, body, _, _, err := translator.ResponseBody(nil, strings.NewReader(`{"embedding":[1.0,-2.5],"inputTextTokenCount":1}`), true, nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"object":"list","data":[{"object":"embedding","index":0,"embedding":"AACAPwAAIMA="}],
+"model":"amazon.titan-embed-text-v2:0","usage":{"prompt_tokens":1,"total_tokens":1}}`, string(body))
+}
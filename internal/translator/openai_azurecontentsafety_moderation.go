@@ -0,0 +1,148 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/azure"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+const (
+	// azureContentSafetyDefaultAPIVersion is the api-version used when the backend does not specify one.
+	azureContentSafetyDefaultAPIVersion = "2024-09-01"
+	// azureContentSafetyFlaggedSeverity is the severity at or above which a category is flagged. It matches the
+	// "medium" threshold of the Azure OpenAI default content filter.
+	azureContentSafetyFlaggedSeverity = 4
+)
+
+// azureContentSafetyCategories maps the Azure AI Content Safety harm categories to the OpenAI moderation categories.
+var azureContentSafetyCategories = map[azure.ContentSafetyCategory]string{
+	azure.ContentSafetyCategoryHate:     openai.ModerationCategoryHate,
+	azure.ContentSafetyCategorySelfHarm: openai.ModerationCategorySelfHarm,
+	azure.ContentSafetyCategorySexual:   openai.ModerationCategorySexual,
+	azure.ContentSafetyCategoryViolence: openai.ModerationCategoryViolence,
+}
+
+// NewModerationOpenAIToAzureContentSafetyTranslator implements [OpenAIModerationTranslator] for OpenAI to
+// Azure AI Content Safety translation for /v1/moderations.
+//
+// Only a single text input is supported, and only the hate, self-harm, sexual and violence categories are returned.
+func NewModerationOpenAIToAzureContentSafetyTranslator(apiVersion string, modelNameOverride internalapi.ModelNameOverride) OpenAIModerationTranslator {
+	return &openAIToAzureContentSafetyTranslatorV1Moderation{
+		path:              "/contentsafety/text:analyze?api-version=" + cmp.Or(apiVersion, azureContentSafetyDefaultAPIVersion),
+		modelNameOverride: modelNameOverride,
+	}
+}
+
+// openAIToAzureContentSafetyTranslatorV1Moderation translates the OpenAI Moderations API to the text:analyze API
+// of Azure AI Content Safety:
+// https://learn.microsoft.com/en-us/rest/api/contentsafety/text-operations/analyze-text
+type openAIToAzureContentSafetyTranslatorV1Moderation struct {
+	path              string
+	modelNameOverride internalapi.ModelNameOverride
+	// requestModel is reported as the response model since Content Safety does not have models.
+	requestModel internalapi.RequestModel
+}
+
+// RequestBody implements [OpenAIModerationTranslator.RequestBody].
+func (o *openAIToAzureContentSafetyTranslatorV1Moderation) RequestBody(_ []byte, req *openai.ModerationRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.requestModel = cmp.Or(o.modelNameOverride, req.Model)
+	texts, err := moderationInputTexts(&req.Input)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(texts) != 1 {
+		return nil, nil, fmt.Errorf("%w: Azure AI Content Safety supports a single text input, got %d",
+			internalapi.ErrInvalidRequestBody, len(texts))
+	}
+	newBody, err = json.Marshal(&azure.AnalyzeTextRequest{
+		Text: texts[0],
+		Categories: []azure.ContentSafetyCategory{
+			azure.ContentSafetyCategoryHate,
+			azure.ContentSafetyCategorySelfHarm,
+			azure.ContentSafetyCategorySexual,
+			azure.ContentSafetyCategoryViolence,
+		},
+		OutputType: azure.ContentSafetyOutputTypeFourSeverityLevels,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, o.path},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// ResponseHeaders implements [OpenAIModerationTranslator.ResponseHeaders].
+func (o *openAIToAzureContentSafetyTranslatorV1Moderation) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIModerationTranslator.ResponseBody].
+//
+// The severity is mapped to the score by dividing it by the maximum severity. A blocklist match flags the input
+// without flagging any category.
+func (o *openAIToAzureContentSafetyTranslatorV1Moderation) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.ModerationsSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	var analysis azure.AnalyzeTextResponse
+	if err = json.NewDecoder(body).Decode(&analysis); err != nil {
+		return nil, nil, tokenUsage, o.requestModel, fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+
+	result := openai.ModerationResult{
+		Flagged:        len(analysis.BlocklistsMatch) > 0,
+		Categories:     make(map[string]bool, len(analysis.CategoriesAnalysis)),
+		CategoryScores: make(map[string]float64, len(analysis.CategoriesAnalysis)),
+	}
+	for _, c := range analysis.CategoriesAnalysis {
+		category, ok := azureContentSafetyCategories[c.Category]
+		if !ok {
+			continue
+		}
+		flagged := c.Severity >= azureContentSafetyFlaggedSeverity
+		result.CategoryScores[category] = float64(c.Severity) / azure.ContentSafetyMaxSeverity
+		result.Categories[category] = flagged
+		result.Flagged = result.Flagged || flagged
+	}
+	resp := &openai.ModerationResponse{
+		ID:      "modr-" + uuid.NewString(),
+		Model:   o.requestModel,
+		Results: []openai.ModerationResult{result},
+	}
+	newBody, err = json.Marshal(resp)
+	if err != nil {
+		return nil, nil, tokenUsage, o.requestModel, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(resp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return newHeaders, newBody, tokenUsage, o.requestModel, nil
+}
+
+// ResponseError implements [OpenAIModerationTranslator.ResponseError].
+//
+// Azure AI Content Safety returns the errors in the {"error":{"code":...,"message":...}} format, which is compatible
+// with OpenAI.
+func (o *openAIToAzureContentSafetyTranslatorV1Moderation) ResponseError(respHeaders map[string]string, body io.Reader) ([]internalapi.Header, []byte, error) {
+	return convertErrorOpenAIToOpenAIError(respHeaders, body)
+}
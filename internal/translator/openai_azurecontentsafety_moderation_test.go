@@ -0,0 +1,105 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestOpenAIToAzureContentSafetyTranslatorV1Moderation_RequestBody(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		apiVersion string
+		expPath    string
+	}{
+		{name: "default api version", expPath: "/contentsafety/text:analyze?api-version=2024-09-01"},
+		{name: "api version", apiVersion: "2023-10-01", expPath: "/contentsafety/text:analyze?api-version=2023-10-01"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			translator := NewModerationOpenAIToAzureContentSafetyTranslator(tc.apiVersion, "")
+			headers, body, err := translator.RequestBody(nil, &openai.ModerationRequest{Input: openai.ModerationInput{Value: "hello"}}, false)
+			require.NoError(t, err)
+			require.Equal(t, []internalapi.Header{
+				{pathHeaderName, tc.expPath},
+				{contentLengthHeaderName, strconv.Itoa(len(body))},
+			}, headers)
+			require.JSONEq(t, `{"text":"hello","categories":["Hate","SelfHarm","Sexual","Violence"],"outputType":"FourSeverityLevels"}`, string(body))
+		})
+	}
+
+	t.Run("multiple inputs", func(t *testing.T) {
+		_, _, err := NewModerationOpenAIToAzureContentSafetyTranslator("", "").RequestBody(nil,
+			&openai.ModerationRequest{Input: openai.ModerationInput{Value: []string{"a", "b"}}}, false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+		require.ErrorContains(t, err, "Azure AI Content Safety supports a single text input, got 2")
+	})
+}
+
+func TestOpenAIToAzureContentSafetyTranslatorV1Moderation_ResponseBody(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		analysis   string
+		expFlagged bool
+		expScores  map[string]float64
+		expFlags   map[string]bool
+	}{
+		{
+			name:       "safe",
+			analysis:   `{"blocklistsMatch":[],"categoriesAnalysis":[{"category":"Hate","severity":0},{"category":"SelfHarm","severity":0},{"category":"Sexual","severity":0},{"category":"Violence","severity":2}]}`,
+			expFlagged: false,
+			expScores:  map[string]float64{"hate": 0, "self-harm": 0, "sexual": 0, "violence": 2.0 / 6},
+			expFlags:   map[string]bool{"hate": false, "self-harm": false, "sexual": false, "violence": false},
+		},
+		{
+			name:       "violence",
+			analysis:   `{"categoriesAnalysis":[{"category":"Hate","severity":2},{"category":"Violence","severity":4}]}`,
+			expFlagged: true,
+			expScores:  map[string]float64{"hate": 2.0 / 6, "violence": 4.0 / 6},
+			expFlags:   map[string]bool{"hate": false, "violence": true},
+		},
+		{
+			name:       "blocklist",
+			analysis:   `{"blocklistsMatch":[{"blocklistName":"b","blocklistItemId":"1","blocklistItemText":"x"}],"categoriesAnalysis":[{"category":"Hate","severity":0}]}`,
+			expFlagged: true,
+			expScores:  map[string]float64{"hate": 0},
+			expFlags:   map[string]bool{"hate": false},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			translator := NewModerationOpenAIToAzureContentSafetyTranslator("", "content-safety")
+			_, _, err := translator.RequestBody(nil, &openai.ModerationRequest{Model: "omni-moderation-latest", Input: openai.ModerationInput{Value: "hello"}}, false)
+			require.NoError(t, err)
+
+			headers, body, _, responseModel, err := translator.ResponseBody(nil, strings.NewReader(tc.analysis), true, nil)
+			require.NoError(t, err)
+			require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(body))}}, headers)
+			require.Equal(t, "content-safety", responseModel)
+
+			var resp openai.ModerationResponse
+			require.NoError(t, json.Unmarshal(body, &resp))
+			require.True(t, strings.HasPrefix(resp.ID, "modr-"))
+			require.Equal(t, "content-safety", resp.Model)
+			require.Len(t, resp.Results, 1)
+			require.Equal(t, tc.expFlagged, resp.Results[0].Flagged)
+			require.Equal(t, tc.expFlags, resp.Results[0].Categories)
+			require.InDeltaMapValues(t, tc.expScores, resp.Results[0].CategoryScores, 1e-9)
+		})
+	}
+
+	t.Run("invalid body", func(t *testing.T) {
+		translator := NewModerationOpenAIToAzureContentSafetyTranslator("", "")
+		_, _, _, _, err := translator.ResponseBody(nil, strings.NewReader("not-json"), true, nil)
+		require.ErrorContains(t, err, "failed to unmarshal body")
+	})
+}
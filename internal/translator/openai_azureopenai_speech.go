@@ -0,0 +1,99 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewSpeechOpenAIToAzureOpenAITranslator implements [OpenAISpeechTranslator] for OpenAI to Azure OpenAI
+// translation for /v1/audio/speech.
+func NewSpeechOpenAIToAzureOpenAITranslator(apiVersion string, modelNameOverride internalapi.ModelNameOverride) OpenAISpeechTranslator {
+	return &openAIToAzureOpenAITranslatorV1Speech{
+		apiVersion: apiVersion,
+		openAIToOpenAITranslatorV1Speech: openAIToOpenAITranslatorV1Speech{
+			modelNameOverride: modelNameOverride,
+		},
+	}
+}
+
+// openAIToAzureOpenAITranslatorV1Speech implements [OpenAISpeechTranslator] for the speech synthesis of the
+// Azure OpenAI deployments. The body is the same as OpenAI, so only the path differs.
+//
+// The token usage is taken from the speech.audio.done event of the SSE stream. The binary audio response carries
+// no usage, so the number of characters of the input is reported as the input tokens instead, which is how the
+// tts-1 and tts-1-hd models are priced.
+type openAIToAzureOpenAITranslatorV1Speech struct {
+	apiVersion string
+	// inputCharacters is the number of characters of the input text.
+	inputCharacters int
+	openAIToOpenAITranslatorV1Speech
+}
+
+// RequestBody implements [OpenAISpeechTranslator.RequestBody].
+func (o *openAIToAzureOpenAITranslatorV1Speech) RequestBody(original []byte, req *openai.SpeechRequest, forceBodyMutation bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	newHeaders, newBody, err = o.openAIToOpenAITranslatorV1Speech.RequestBody(original, req, forceBodyMutation)
+	if err != nil {
+		return nil, nil, err
+	}
+	o.inputCharacters = utf8.RuneCountInString(req.Input)
+	// Azure OpenAI uses a {deployment-id} in the path instead of the model in the body. Assume deployment_id is
+	// same as model name.
+	pathTemplate := "/openai/deployments/%s/audio/speech?api-version=%s"
+	newHeaders[0] = internalapi.Header{pathHeaderName, fmt.Sprintf(pathTemplate, o.requestModel, o.apiVersion)}
+	return
+}
+
+// ResponseBody implements [OpenAISpeechTranslator.ResponseBody].
+func (o *openAIToAzureOpenAITranslatorV1Speech) ResponseBody(respHeaders map[string]string, body io.Reader, endOfStream bool, span tracingapi.SpeechSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to read speech response: %w", err)
+	}
+	newHeaders, newBody, tokenUsage, responseModel, err = o.openAIToOpenAITranslatorV1Speech.ResponseBody(respHeaders, bytes.NewReader(data), endOfStream, span)
+	if err != nil {
+		return
+	}
+	if !o.stream {
+		tokenUsage.SetInputTokens(uint32(o.inputCharacters)) //nolint:gosec
+		return
+	}
+	if usage := speechUsageFromSSE(data); usage != nil {
+		tokenUsage = metrics.ExtractTokenUsageFromExplicitCaching(int64(usage.InputTokens), int64(usage.OutputTokens), nil, nil)
+	}
+	return
+}
+
+// speechUsageFromSSE returns the usage of the speech.audio.done event in the SSE chunks if any.
+func speechUsageFromSSE(chunks []byte) *openai.SpeechUsage {
+	for line := range bytes.SplitSeq(chunks, []byte("\n")) {
+		data, ok := bytes.CutPrefix(line, sseDataPrefix)
+		if !ok {
+			continue
+		}
+		var chunk openai.SpeechStreamChunk
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			continue
+		}
+		if chunk.Type == openai.SpeechStreamEventTypeAudioDone && chunk.Usage != nil {
+			return chunk.Usage
+		}
+	}
+	return nil
+}
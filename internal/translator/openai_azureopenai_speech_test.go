@@ -0,0 +1,80 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+func TestOpenAIToAzureOpenAISpeechTranslator_RequestBody(t *testing.T) {
+	tr := NewSpeechOpenAIToAzureOpenAITranslator("2025-03-01-preview", "")
+	req := &openai.SpeechRequest{Model: "gpt-4o-mini-tts", Input: "Hello", Voice: "alloy"}
+	headers, body, err := tr.RequestBody([]byte(`{"model":"gpt-4o-mini-tts","input":"Hello","voice":"alloy"}`), req, false)
+	require.NoError(t, err)
+	require.Nil(t, body)
+	require.Equal(t, []internalapi.Header{
+		{pathHeaderName, "/openai/deployments/gpt-4o-mini-tts/audio/speech?api-version=2025-03-01-preview"},
+	}, headers)
+
+	t.Run("model override", func(t *testing.T) {
+		tr := NewSpeechOpenAIToAzureOpenAITranslator("2025-03-01-preview", "tts-hd")
+		headers, body, err := tr.RequestBody([]byte(`{"model":"tts-1","input":"Hello","voice":"alloy"}`), req, false)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"model":"tts-hd","input":"Hello","voice":"alloy"}`, string(body))
+		require.Equal(t, "/openai/deployments/tts-hd/audio/speech?api-version=2025-03-01-preview", headers[0].Value())
+	})
+}
+
+func TestOpenAIToAzureOpenAISpeechTranslator_ResponseBody(t *testing.T) {
+	t.Run("binary reports characters", func(t *testing.T) {
+		tr := NewSpeechOpenAIToAzureOpenAITranslator("2025-03-01-preview", "")
+		req := &openai.SpeechRequest{Model: "tts-1", Input: "Héllo, world", Voice: "alloy"}
+		_, _, err := tr.RequestBody(nil, req, false)
+		require.NoError(t, err)
+
+		mockSpan := &mockSpeechSpan{}
+		headers, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader("mp3-bytes"), true, mockSpan)
+		require.NoError(t, err)
+		require.Nil(t, headers)
+		require.Nil(t, body)
+		require.Equal(t, tokenUsageFrom(12, -1, -1, -1, -1, -1), tokenUsage)
+		require.Equal(t, "tts-1", responseModel)
+		require.Equal(t, []byte("mp3-bytes"), *mockSpan.recordedResponse)
+	})
+
+	t.Run("sse reports usage of the done event", func(t *testing.T) {
+		tr := NewSpeechOpenAIToAzureOpenAITranslator("2025-03-01-preview", "")
+		req := &openai.SpeechRequest{Model: "gpt-4o-mini-tts", Input: "Hello", Voice: "alloy", StreamFormat: ptr.To(openai.StreamFormatSSE)}
+		_, _, err := tr.RequestBody(nil, req, false)
+		require.NoError(t, err)
+
+		_, _, tokenUsage, _, err := tr.ResponseBody(nil, strings.NewReader("data: {\"type\":\"speech.audio.delta\",\"audio\":\"AAAA\"}\n\n"), false, nil)
+		require.NoError(t, err)
+		require.Equal(t, tokenUsageFrom(-1, -1, -1, -1, -1, -1), tokenUsage)
+
+		mockSpan := &mockSpeechSpan{}
+		_, _, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(
+			"data: {\"type\":\"speech.audio.done\",\"usage\":{\"input_tokens\":5,\"output_tokens\":40,\"total_tokens\":45}}\n\n"), true, mockSpan)
+		require.NoError(t, err)
+		require.Equal(t, tokenUsageFrom(5, -1, -1, 40, 45, -1), tokenUsage)
+		require.Equal(t, "gpt-4o-mini-tts", responseModel)
+		require.Len(t, mockSpan.recordedChunks, 1)
+		require.Equal(t, openai.SpeechStreamEventTypeAudioDone, mockSpan.recordedChunks[0].Type)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		tr := NewSpeechOpenAIToAzureOpenAITranslator("2025-03-01-preview", "")
+		_, _, _, _, err := tr.ResponseBody(nil, &errorReader{}, true, nil)
+		require.ErrorContains(t, err, "failed to read speech response")
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"fmt"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+// NewAudioTranscriptionOpenAIToAzureOpenAITranslator implements [OpenAITranscriptionTranslator] for OpenAI to
+// Azure OpenAI translation for /v1/audio/transcriptions.
+func NewAudioTranscriptionOpenAIToAzureOpenAITranslator(apiVersion string, modelNameOverride internalapi.ModelNameOverride) OpenAITranscriptionTranslator {
+	return &openAIToAzureOpenAITranslatorV1Transcription{
+		apiVersion: apiVersion,
+		endpoint:   "transcriptions",
+		openAIToOpenAITranslatorV1Transcription: openAIToOpenAITranslatorV1Transcription{
+			modelNameOverride: modelNameOverride,
+		},
+	}
+}
+
+// NewAudioTranslationOpenAIToAzureOpenAITranslator implements [OpenAITranscriptionTranslator] for OpenAI to
+// Azure OpenAI translation for /v1/audio/translations.
+func NewAudioTranslationOpenAIToAzureOpenAITranslator(apiVersion string, modelNameOverride internalapi.ModelNameOverride) OpenAITranscriptionTranslator {
+	return &openAIToAzureOpenAITranslatorV1Transcription{
+		apiVersion: apiVersion,
+		endpoint:   "translations",
+		openAIToOpenAITranslatorV1Transcription: openAIToOpenAITranslatorV1Transcription{
+			modelNameOverride: modelNameOverride,
+		},
+	}
+}
+
+// openAIToAzureOpenAITranslatorV1Transcription implements [OpenAITranscriptionTranslator] for /audio/transcriptions
+// and /audio/translations.
+type openAIToAzureOpenAITranslatorV1Transcription struct {
+	apiVersion string
+	// endpoint is either "transcriptions" or "translations".
+	endpoint string
+	openAIToOpenAITranslatorV1Transcription
+}
+
+// RequestBody implements [OpenAITranscriptionTranslator.RequestBody].
+func (o *openAIToAzureOpenAITranslatorV1Transcription) RequestBody(original []byte, req *openai.TranscriptionRequest, forceBodyMutation bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	newHeaders, newBody, err = o.openAIToOpenAITranslatorV1Transcription.RequestBody(original, req, forceBodyMutation)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Azure OpenAI uses a {deployment-id} in the path instead of the model in the form. Assume deployment_id is
+	// same as model name.
+	pathTemplate := "/openai/deployments/%s/audio/%s?api-version=%s"
+	newHeaders[0] = internalapi.Header{pathHeaderName, fmt.Sprintf(pathTemplate, o.requestModel, o.endpoint, o.apiVersion)}
+	return
+}
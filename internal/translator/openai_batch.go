@@ -0,0 +1,378 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/sjson"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/multipartform"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewFilesOpenAIToOpenAITranslator implements [OpenAIFilesTranslator] for OpenAI to OpenAI translation for /v1/files.
+func NewFilesOpenAIToOpenAITranslator(prefix string, modelNameOverride internalapi.ModelNameOverride) OpenAIFilesTranslator {
+	return &openAIToOpenAITranslatorV1Files{modelNameOverride: modelNameOverride, path: path.Join("/", prefix, "files")}
+}
+
+// NewFilesOpenAIToAzureOpenAITranslator implements [OpenAIFilesTranslator] for OpenAI to Azure OpenAI translation
+// for /v1/files. Unlike the inference endpoints, the files are not scoped to a deployment.
+func NewFilesOpenAIToAzureOpenAITranslator(apiVersion string, modelNameOverride internalapi.ModelNameOverride) OpenAIFilesTranslator {
+	return &openAIToOpenAITranslatorV1Files{modelNameOverride: modelNameOverride, path: "/openai/files", query: "?api-version=" + apiVersion}
+}
+
+// openAIToOpenAITranslatorV1Files is a passthrough translator for the OpenAI Files API:
+// https://platform.openai.com/docs/api-reference/files
+//
+// The IDs of the files in the responses are replaced with the model scoped IDs, so that the subsequent requests on
+// the files are routed to the same backend. The model of the requests in the uploaded batch input file is replaced
+// when the model name override is set.
+type openAIToOpenAITranslatorV1Files struct {
+	modelNameOverride internalapi.ModelNameOverride
+	// The path of the files endpoint to be used for the request. It is prefixed with the OpenAI path prefix.
+	path string
+	// query is the query string appended to the path, e.g. the api-version of Azure OpenAI.
+	query string
+	// originalModel is the original model of the request, which the file IDs are scoped to.
+	originalModel internalapi.OriginalModel
+	// requestModel is the model of the request after the override, which is used as the response model.
+	requestModel internalapi.RequestModel
+	// operation is the operation of the request.
+	operation openai.FileOperation
+}
+
+// RequestBody implements [OpenAIFilesTranslator.RequestBody].
+func (o *openAIToOpenAITranslatorV1Files) RequestBody(original []byte, req *openai.FileRequest, forceBodyMutation bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.originalModel, o.requestModel, o.operation = req.Model, cmp.Or(o.modelNameOverride, req.Model), req.Operation
+	p := o.path
+	if req.FileID != "" {
+		p += "/" + url.PathEscape(req.FileID)
+	}
+	if req.Operation == openai.FileOperationContent {
+		p += "/content"
+	}
+	newHeaders = []internalapi.Header{{pathHeaderName, p + o.query}}
+
+	if req.Operation == openai.FileOperationUpload && o.modelNameOverride != "" {
+		newBody, err = rewriteBatchInputModel(original, o.modelNameOverride)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to set model: %w", err)
+		}
+	}
+	if forceBodyMutation && len(newBody) == 0 {
+		newBody = original
+	}
+	if len(newBody) > 0 {
+		newHeaders = append(newHeaders, internalapi.Header{contentLengthHeaderName, strconv.Itoa(len(newBody))})
+	}
+	return
+}
+
+// rewriteBatchInputModel returns the multipart/form-data body of the file upload with the model of all the requests
+// in the uploaded JSONL file replaced. The form is written with the same boundary, so the content-type is unchanged.
+func rewriteBatchInputModel(original []byte, model string) ([]byte, error) {
+	form, err := multipartform.Parse(original, "")
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err = w.SetBoundary(form.Boundary()); err != nil {
+		return nil, err
+	}
+	for _, part := range form.Parts() {
+		header := textproto.MIMEHeader{}
+		disposition := fmt.Sprintf("form-data; name=%q", part.Name)
+		content := part.Content()
+		if part.IsFile() {
+			disposition += fmt.Sprintf("; filename=%q", part.FileName)
+			if content, err = setBatchInputModel(content, model); err != nil {
+				return nil, err
+			}
+		}
+		header.Set("Content-Disposition", disposition)
+		if part.ContentType != "" {
+			header.Set("Content-Type", part.ContentType)
+		}
+		var pw io.Writer
+		if pw, err = w.CreatePart(header); err != nil {
+			return nil, err
+		}
+		if _, err = pw.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// setBatchInputModel returns a copy of the JSONL batch input with the model of every request replaced.
+func setBatchInputModel(jsonl []byte, model string) ([]byte, error) {
+	lines := bytes.Split(jsonl, []byte("\n"))
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		newLine, err := sjson.SetBytesOptions(line, "body.model", model, sjsonOptions)
+		if err != nil {
+			return nil, fmt.Errorf("invalid batch input line %d: %w", i+1, err)
+		}
+		lines[i] = newLine
+	}
+	return bytes.Join(lines, []byte("\n")), nil
+}
+
+// ResponseHeaders implements [OpenAIFilesTranslator.ResponseHeaders].
+func (o *openAIToOpenAITranslatorV1Files) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIFilesTranslator.ResponseBody].
+//
+// The content of a file is passed through as is. Otherwise, the response is a file object or the result of the
+// deletion, whose ID is replaced with the model scoped ID.
+func (o *openAIToOpenAITranslatorV1Files) ResponseBody(_ map[string]string, body io.Reader, _ bool, _ tracingapi.FilesSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	responseModel = o.requestModel
+	if o.operation == openai.FileOperationContent {
+		return nil, nil, tokenUsage, responseModel, nil
+	}
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to read body: %w", err)
+	}
+	var file openai.FileObject
+	if err = json.Unmarshal(raw, &file); err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	newBody, err = sjson.SetBytesOptions(raw, "id", internalapi.EncodeModelScopedID(openai.FileIDPrefix, o.originalModel, file.ID), sjsonOptions)
+	if err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to set id: %w", err)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// ResponseError implements [OpenAIFilesTranslator.ResponseError].
+func (o *openAIToOpenAITranslatorV1Files) ResponseError(respHeaders map[string]string, body io.Reader) ([]internalapi.Header, []byte, error) {
+	return convertErrorOpenAIToOpenAIError(respHeaders, body)
+}
+
+// NewFilesStorageTranslator implements [OpenAIFilesTranslator] for the providers whose batch jobs read the input from
+// their own storage, e.g. S3 for AWS Bedrock and Cloud Storage for GCP Vertex AI, which have no files API.
+// Every request is rejected with a message telling the client to upload the batch input to the storage instead.
+func NewFilesStorageTranslator(provider, storage string) OpenAIFilesTranslator {
+	return &storageFilesTranslator{provider: provider, storage: storage}
+}
+
+// storageFilesTranslator is the [OpenAIFilesTranslator] returned by [NewFilesStorageTranslator].
+type storageFilesTranslator struct {
+	// provider is the name of the provider used in the error message, e.g. "AWS Bedrock".
+	provider string
+	// storage is the name of the storage of the provider used in the error message, e.g. "S3".
+	storage string
+}
+
+// RequestBody implements [OpenAIFilesTranslator.RequestBody].
+func (s *storageFilesTranslator) RequestBody([]byte, *openai.FileRequest, bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return nil, nil, fmt.Errorf("%w: the files API is not supported for %s, upload the batch input to %s under the "+
+		"input URI prefix of the backend and set its URI as the input_file_id of the batch",
+		internalapi.ErrInvalidRequestBody, s.provider, s.storage)
+}
+
+// ResponseHeaders implements [OpenAIFilesTranslator.ResponseHeaders].
+func (s *storageFilesTranslator) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIFilesTranslator.ResponseBody].
+func (s *storageFilesTranslator) ResponseBody(map[string]string, io.Reader, bool, tracingapi.FilesSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	return nil, nil, tokenUsage, responseModel, nil
+}
+
+// ResponseError implements [OpenAIFilesTranslator.ResponseError].
+func (s *storageFilesTranslator) ResponseError(respHeaders map[string]string, body io.Reader) ([]internalapi.Header, []byte, error) {
+	return convertErrorOpenAIToOpenAIError(respHeaders, body)
+}
+
+// NewBatchesOpenAIToOpenAITranslator implements [OpenAIBatchesTranslator] for OpenAI to OpenAI translation for /v1/batches.
+func NewBatchesOpenAIToOpenAITranslator(prefix string, modelNameOverride internalapi.ModelNameOverride) OpenAIBatchesTranslator {
+	return &openAIToOpenAITranslatorV1Batches{modelNameOverride: modelNameOverride, path: path.Join("/", prefix, "batches")}
+}
+
+// NewBatchesOpenAIToAzureOpenAITranslator implements [OpenAIBatchesTranslator] for OpenAI to Azure OpenAI translation
+// for /v1/batches. Unlike the inference endpoints, the batches are not scoped to a deployment.
+func NewBatchesOpenAIToAzureOpenAITranslator(apiVersion string, modelNameOverride internalapi.ModelNameOverride) OpenAIBatchesTranslator {
+	return &openAIToOpenAITranslatorV1Batches{
+		modelNameOverride: modelNameOverride, path: "/openai/batches", query: "?api-version=" + apiVersion,
+	}
+}
+
+// openAIToOpenAITranslatorV1Batches is a passthrough translator for the OpenAI Batch API:
+// https://platform.openai.com/docs/api-reference/batch
+//
+// The IDs of the batch and its files in the responses are replaced with the model scoped IDs, so that the subsequent
+// requests on them are routed to the same backend. The token usage of a completed batch is reported by every
+// retrieval along with its [UsageDeduplicator.UsageKey], so that it is only recorded once.
+type openAIToOpenAITranslatorV1Batches struct {
+	modelNameOverride internalapi.ModelNameOverride
+	// The path of the batches endpoint to be used for the request. It is prefixed with the OpenAI path prefix.
+	path string
+	// query is the query string appended to the path, e.g. the api-version of Azure OpenAI.
+	query string
+	// originalModel is the original model of the request, which the batch and file IDs are scoped to.
+	originalModel internalapi.OriginalModel
+	// requestModel is the model of the request after the override, which is used as the response model.
+	requestModel internalapi.RequestModel
+	// usageKey is the key of the token usage reported by the last response, or empty if there is none.
+	usageKey string
+}
+
+// RequestBody implements [OpenAIBatchesTranslator.RequestBody].
+func (o *openAIToOpenAITranslatorV1Batches) RequestBody(original []byte, req *openai.BatchRequest, forceBodyMutation bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.originalModel, o.requestModel = req.Model, cmp.Or(o.modelNameOverride, req.Model)
+	p := o.path
+	switch req.Operation {
+	case openai.BatchOperationCreate:
+		// The input file ID in the request is the model scoped ID, so replace it with the one of the backend.
+		newBody, err = sjson.SetBytesOptions(original, "input_file_id", req.InputFileID, sjsonOptions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to set input_file_id: %w", err)
+		}
+	case openai.BatchOperationRetrieve:
+		p += "/" + url.PathEscape(req.BatchID)
+	case openai.BatchOperationCancel:
+		p += "/" + url.PathEscape(req.BatchID) + "/cancel"
+	}
+	newHeaders = []internalapi.Header{{pathHeaderName, p + o.query}}
+	if forceBodyMutation && len(newBody) == 0 {
+		newBody = original
+	}
+	if len(newBody) > 0 {
+		newHeaders = append(newHeaders, internalapi.Header{contentLengthHeaderName, strconv.Itoa(len(newBody))})
+	}
+	return
+}
+
+// ResponseHeaders implements [OpenAIBatchesTranslator.ResponseHeaders].
+func (o *openAIToOpenAITranslatorV1Batches) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIBatchesTranslator.ResponseBody].
+func (o *openAIToOpenAITranslatorV1Batches) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.BatchesSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	responseModel = o.requestModel
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to read body: %w", err)
+	}
+	var batch openai.Batch
+	if err = json.Unmarshal(raw, &batch); err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	if batch.Model != "" {
+		responseModel = batch.Model
+	}
+	o.usageKey = ""
+	if batch.Status == openai.BatchStatusCompleted && batch.Usage != nil {
+		tokenUsage = batchTokenUsage(batch.Usage)
+		o.usageKey = internalapi.EncodeModelScopedID(openai.BatchIDPrefix, o.originalModel, batch.ID)
+	}
+
+	newBody = raw
+	for _, id := range []struct {
+		key, prefix, value string
+	}{
+		{"id", openai.BatchIDPrefix, batch.ID},
+		{"input_file_id", openai.FileIDPrefix, batch.InputFileID},
+		{"output_file_id", openai.FileIDPrefix, batch.OutputFileID},
+		{"error_file_id", openai.FileIDPrefix, batch.ErrorFileID},
+	} {
+		if id.value == "" {
+			continue
+		}
+		newBody, err = sjson.SetBytesOptions(newBody, id.key, internalapi.EncodeModelScopedID(id.prefix, o.originalModel, id.value), sjsonOptions)
+		if err != nil {
+			return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to set %s: %w", id.key, err)
+		}
+	}
+	if span != nil {
+		span.RecordResponse(&batch)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// UsageKey implements [UsageDeduplicator.UsageKey].
+func (o *openAIToOpenAITranslatorV1Batches) UsageKey() string {
+	return o.usageKey
+}
+
+// batchTokenUsage returns the token usage of a completed batch.
+func batchTokenUsage(usage *openai.BatchUsage) (tokenUsage metrics.TokenUsage) {
+	tokenUsage.SetInputTokens(uint32(usage.InputTokens))   //nolint:gosec
+	tokenUsage.SetOutputTokens(uint32(usage.OutputTokens)) //nolint:gosec
+	tokenUsage.SetTotalTokens(uint32(usage.TotalTokens))   //nolint:gosec
+	if usage.InputTokensDetails != nil {
+		tokenUsage.SetCachedInputTokens(uint32(usage.InputTokensDetails.CachedTokens)) //nolint:gosec
+	}
+	if usage.OutputTokensDetails != nil {
+		tokenUsage.SetReasoningTokens(uint32(usage.OutputTokensDetails.ReasoningTokens)) //nolint:gosec
+	}
+	return
+}
+
+// validateStorageBatchRequest validates the creation of a batch on a provider that keeps the input and the output of
+// the batches in its own storage, whose locations are taken from the batch configuration of the backend rather than
+// from the request.
+func validateStorageBatchRequest(req *openai.BatchRequest, config *filterapi.BackendBatch, provider string) error {
+	if config == nil {
+		return fmt.Errorf("%w: batches are not configured on the %s backend", internalapi.ErrInvalidRequestBody, provider)
+	}
+	for _, key := range []string{internalapi.BatchMetadataOutputURI, internalapi.BatchMetadataRoleARN} {
+		if _, ok := req.Metadata[key]; ok {
+			return fmt.Errorf("%w: metadata.%s is not accepted, it is configured on the backend", internalapi.ErrInvalidRequestBody, key)
+		}
+	}
+	if !strings.HasPrefix(req.InputFileID, config.InputURIPrefix) {
+		return fmt.Errorf("%w: input_file_id must be the URI of the batch input under %s for %s",
+			internalapi.ErrInvalidRequestBody, config.InputURIPrefix, provider)
+	}
+	return nil
+}
+
+// ResponseError implements [OpenAIBatchesTranslator.ResponseError].
+func (o *openAIToOpenAITranslatorV1Batches) ResponseError(respHeaders map[string]string, body io.Reader) ([]internalapi.Header, []byte, error) {
+	return convertErrorOpenAIToOpenAIError(respHeaders, body)
+}
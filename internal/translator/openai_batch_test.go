@@ -0,0 +1,241 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/multipartform"
+)
+
+func TestOpenAIToOpenAITranslatorV1Files_RequestBody(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		translator OpenAIFilesTranslator
+		req        *openai.FileRequest
+		expPath    string
+	}{
+		{
+			name:       "upload",
+			translator: NewFilesOpenAIToOpenAITranslator("v1", ""),
+			req:        &openai.FileRequest{Model: "gpt-4o-mini", Operation: openai.FileOperationUpload},
+			expPath:    "/v1/files",
+		},
+		{
+			name:       "retrieve",
+			translator: NewFilesOpenAIToOpenAITranslator("v1", ""),
+			req:        &openai.FileRequest{Model: "gpt-4o-mini", Operation: openai.FileOperationRetrieve, FileID: "file-abc123"},
+			expPath:    "/v1/files/file-abc123",
+		},
+		{
+			name:       "content",
+			translator: NewFilesOpenAIToOpenAITranslator("v1", ""),
+			req:        &openai.FileRequest{Model: "gpt-4o-mini", Operation: openai.FileOperationContent, FileID: "file-abc123"},
+			expPath:    "/v1/files/file-abc123/content",
+		},
+		{
+			name:       "azure delete",
+			translator: NewFilesOpenAIToAzureOpenAITranslator("2025-03-01-preview", ""),
+			req:        &openai.FileRequest{Model: "gpt-4o-mini", Operation: openai.FileOperationDelete, FileID: "file-abc123"},
+			expPath:    "/openai/files/file-abc123?api-version=2025-03-01-preview",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			headers, body, err := tc.translator.RequestBody(nil, tc.req, false)
+			require.NoError(t, err)
+			require.Nil(t, body)
+			require.Equal(t, []internalapi.Header{{pathHeaderName, tc.expPath}}, headers)
+		})
+	}
+}
+
+func TestOpenAIToOpenAITranslatorV1Files_RequestBody_ModelNameOverride(t *testing.T) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	require.NoError(t, w.WriteField("purpose", "batch"))
+	fw, err := w.CreateFormFile("file", "batch.jsonl")
+	require.NoError(t, err)
+	_, err = fw.Write([]byte(`{"custom_id":"1","method":"POST","url":"/v1/chat/completions","body":{"model":"gpt-4o-mini"}}` + "\n" +
+		`{"custom_id":"2","method":"POST","url":"/v1/chat/completions","body":{"model":"gpt-4o-mini"}}` + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	translator := NewFilesOpenAIToOpenAITranslator("v1", "gpt-4o-mini-2024-07-18")
+	headers, body, err := translator.RequestBody(buf.Bytes(), &openai.FileRequest{Model: "gpt-4o-mini", Operation: openai.FileOperationUpload}, false)
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{
+		{pathHeaderName, "/v1/files"},
+		{contentLengthHeaderName, strconv.Itoa(len(body))},
+	}, headers)
+
+	// The body is written with the same boundary, so it can be parsed with the original content-type.
+	form, err := multipartform.Parse(body, w.Boundary())
+	require.NoError(t, err)
+	purpose, _ := form.Value("purpose")
+	require.Equal(t, "batch", purpose)
+	file := form.Part("file")
+	require.NotNil(t, file)
+	require.Equal(t, "batch.jsonl", file.FileName)
+	require.Equal(t, `{"custom_id":"1","method":"POST","url":"/v1/chat/completions","body":{"model":"gpt-4o-mini-2024-07-18"}}`+"\n"+
+		`{"custom_id":"2","method":"POST","url":"/v1/chat/completions","body":{"model":"gpt-4o-mini-2024-07-18"}}`+"\n", string(file.Content()))
+}
+
+func TestOpenAIToOpenAITranslatorV1Files_ResponseBody(t *testing.T) {
+	t.Run("file object", func(t *testing.T) {
+		translator := NewFilesOpenAIToOpenAITranslator("v1", "")
+		_, _, err := translator.RequestBody(nil, &openai.FileRequest{Model: "gpt-4o-mini", Operation: openai.FileOperationUpload}, false)
+		require.NoError(t, err)
+
+		headers, body, tokenUsage, responseModel, err := translator.ResponseBody(nil,
+			strings.NewReader(`{"id":"file-abc123","object":"file","bytes":120,"filename":"batch.jsonl","purpose":"batch"}`), true, nil)
+		require.NoError(t, err)
+		expID := internalapi.EncodeModelScopedID(openai.FileIDPrefix, "gpt-4o-mini", "file-abc123")
+		require.JSONEq(t, `{"id":"`+expID+`","object":"file","bytes":120,"filename":"batch.jsonl","purpose":"batch"}`, string(body))
+		require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(body))}}, headers)
+		require.Equal(t, metrics.TokenUsage{}, tokenUsage)
+		require.Equal(t, "gpt-4o-mini", responseModel)
+	})
+
+	t.Run("content", func(t *testing.T) {
+		translator := NewFilesOpenAIToOpenAITranslator("v1", "")
+		_, _, err := translator.RequestBody(nil, &openai.FileRequest{Model: "gpt-4o-mini", Operation: openai.FileOperationContent, FileID: "file-abc123"}, false)
+		require.NoError(t, err)
+
+		headers, body, _, _, err := translator.ResponseBody(nil, strings.NewReader(`{"custom_id":"1"}`), true, nil)
+		require.NoError(t, err)
+		require.Nil(t, headers)
+		require.Nil(t, body)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		translator := NewFilesOpenAIToOpenAITranslator("v1", "")
+		_, _, err := translator.RequestBody(nil, &openai.FileRequest{Model: "gpt-4o-mini", Operation: openai.FileOperationRetrieve, FileID: "file-abc123"}, false)
+		require.NoError(t, err)
+
+		_, _, _, _, err = translator.ResponseBody(nil, strings.NewReader("not-json"), true, nil)
+		require.ErrorContains(t, err, "failed to unmarshal body")
+	})
+}
+
+func TestStorageFilesTranslator_RequestBody(t *testing.T) {
+	translator := NewFilesStorageTranslator("AWS Bedrock", "S3")
+	_, _, err := translator.RequestBody(nil, &openai.FileRequest{Model: "gpt-4o-mini", Operation: openai.FileOperationUpload}, false)
+	require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+	require.ErrorContains(t, err, "the files API is not supported for AWS Bedrock, upload the batch input to S3 under the "+
+		"input URI prefix of the backend and set its URI as the input_file_id of the batch")
+}
+
+func TestOpenAIToOpenAITranslatorV1Batches_RequestBody(t *testing.T) {
+	t.Run("create", func(t *testing.T) {
+		scopedID := internalapi.EncodeModelScopedID(openai.FileIDPrefix, "gpt-4o-mini", "file-abc123")
+		original := []byte(`{"input_file_id":"` + scopedID + `","endpoint":"/v1/chat/completions","completion_window":"24h"}`)
+		req := &openai.BatchRequest{InputFileID: "file-abc123", Model: "gpt-4o-mini", Operation: openai.BatchOperationCreate}
+
+		headers, body, err := NewBatchesOpenAIToOpenAITranslator("v1", "").RequestBody(original, req, false)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"input_file_id":"file-abc123","endpoint":"/v1/chat/completions","completion_window":"24h"}`, string(body))
+		require.Equal(t, []internalapi.Header{
+			{pathHeaderName, "/v1/batches"},
+			{contentLengthHeaderName, strconv.Itoa(len(body))},
+		}, headers)
+	})
+
+	for _, tc := range []struct {
+		name       string
+		translator OpenAIBatchesTranslator
+		req        *openai.BatchRequest
+		expPath    string
+	}{
+		{
+			name:       "retrieve",
+			translator: NewBatchesOpenAIToOpenAITranslator("v1", ""),
+			req:        &openai.BatchRequest{Model: "gpt-4o-mini", Operation: openai.BatchOperationRetrieve, BatchID: "batch_abc123"},
+			expPath:    "/v1/batches/batch_abc123",
+		},
+		{
+			name:       "azure cancel",
+			translator: NewBatchesOpenAIToAzureOpenAITranslator("2025-03-01-preview", ""),
+			req:        &openai.BatchRequest{Model: "gpt-4o-mini", Operation: openai.BatchOperationCancel, BatchID: "batch_abc123"},
+			expPath:    "/openai/batches/batch_abc123/cancel?api-version=2025-03-01-preview",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			headers, body, err := tc.translator.RequestBody(nil, tc.req, false)
+			require.NoError(t, err)
+			require.Nil(t, body)
+			require.Equal(t, []internalapi.Header{{pathHeaderName, tc.expPath}}, headers)
+		})
+	}
+}
+
+func TestOpenAIToOpenAITranslatorV1Batches_ResponseBody(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		body             string
+		expBody          string
+		expTokenUsage    metrics.TokenUsage
+		expUsageKey      string
+		expResponseModel internalapi.ResponseModel
+	}{
+		{
+			name: "in progress",
+			body: `{"id":"batch_abc123","object":"batch","endpoint":"/v1/chat/completions","input_file_id":"file-abc123",` +
+				`"completion_window":"24h","status":"in_progress","created_at":1711471533}`,
+			expBody: `{"id":"` + internalapi.EncodeModelScopedID(openai.BatchIDPrefix, "gpt-4o-mini", "batch_abc123") + `","object":"batch",` +
+				`"endpoint":"/v1/chat/completions","input_file_id":"` + internalapi.EncodeModelScopedID(openai.FileIDPrefix, "gpt-4o-mini", "file-abc123") + `",` +
+				`"completion_window":"24h","status":"in_progress","created_at":1711471533}`,
+			expResponseModel: "gpt-4o-mini",
+		},
+		{
+			name: "completed",
+			body: `{"id":"batch_abc123","object":"batch","model":"gpt-4o-mini-2024-07-18","input_file_id":"file-abc123",` +
+				`"output_file_id":"file-out","error_file_id":"file-err","status":"completed",` +
+				`"usage":{"input_tokens":100,"input_tokens_details":{"cached_tokens":10},"output_tokens":50,` +
+				`"output_tokens_details":{"reasoning_tokens":5},"total_tokens":150}}`,
+			expBody: `{"id":"` + internalapi.EncodeModelScopedID(openai.BatchIDPrefix, "gpt-4o-mini", "batch_abc123") + `","object":"batch",` +
+				`"model":"gpt-4o-mini-2024-07-18","input_file_id":"` + internalapi.EncodeModelScopedID(openai.FileIDPrefix, "gpt-4o-mini", "file-abc123") + `",` +
+				`"output_file_id":"` + internalapi.EncodeModelScopedID(openai.FileIDPrefix, "gpt-4o-mini", "file-out") + `",` +
+				`"error_file_id":"` + internalapi.EncodeModelScopedID(openai.FileIDPrefix, "gpt-4o-mini", "file-err") + `","status":"completed",` +
+				`"usage":{"input_tokens":100,"input_tokens_details":{"cached_tokens":10},"output_tokens":50,` +
+				`"output_tokens_details":{"reasoning_tokens":5},"total_tokens":150}}`,
+			expTokenUsage:    tokenUsageFrom(100, 10, -1, 50, 150, 5),
+			expUsageKey:      internalapi.EncodeModelScopedID(openai.BatchIDPrefix, "gpt-4o-mini", "batch_abc123"),
+			expResponseModel: "gpt-4o-mini-2024-07-18",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			translator := NewBatchesOpenAIToOpenAITranslator("v1", "")
+			_, _, err := translator.RequestBody(nil, &openai.BatchRequest{Model: "gpt-4o-mini", Operation: openai.BatchOperationRetrieve, BatchID: "batch_abc123"}, false)
+			require.NoError(t, err)
+
+			headers, body, tokenUsage, responseModel, err := translator.ResponseBody(nil, strings.NewReader(tc.body), true, nil)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expBody, string(body))
+			require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(body))}}, headers)
+			require.Equal(t, tc.expTokenUsage, tokenUsage)
+			require.Equal(t, tc.expUsageKey, translator.(UsageDeduplicator).UsageKey())
+			require.Equal(t, tc.expResponseModel, responseModel)
+		})
+	}
+
+	t.Run("invalid body", func(t *testing.T) {
+		translator := NewBatchesOpenAIToOpenAITranslator("v1", "")
+		_, _, err := translator.RequestBody(nil, &openai.BatchRequest{Model: "gpt-4o-mini", Operation: openai.BatchOperationRetrieve, BatchID: "batch_abc123"}, false)
+		require.NoError(t, err)
+
+		_, _, _, _, err = translator.ResponseBody(nil, strings.NewReader("not-json"), true, nil)
+		require.ErrorContains(t, err, "failed to unmarshal body")
+	})
+}
@@ -0,0 +1,650 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/utils/ptr"
+
+	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewChatCompletionOpenAIToCohereTranslator implements [Factory] for OpenAI to Cohere Chat v2 translation.
+func NewChatCompletionOpenAIToCohereTranslator(apiVersion string, modelNameOverride internalapi.ModelNameOverride) OpenAIChatCompletionTranslator {
+	return &openAIToCohereTranslatorV1ChatCompletion{modelNameOverride: modelNameOverride, path: path.Join("/", apiVersion, "chat")} // e.g., /v2/chat
+}
+
+// openAIToCohereTranslatorV1ChatCompletion translates OpenAI Chat Completions API requests to Cohere Chat API v2:
+// https://docs.cohere.com/reference/chat
+type openAIToCohereTranslatorV1ChatCompletion struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+	// The path of the chat endpoint to be used for the request. It is prefixed with the API path prefix.
+	path         string
+	stream       bool
+	bufferedBody []byte
+	responseID   string
+	// toolIndex is the OpenAI index of the tool call being streamed, which is incremented at each tool-call-end event.
+	toolIndex int64
+}
+
+// RequestBody implements [OpenAIChatCompletionTranslator.RequestBody].
+func (o *openAIToCohereTranslatorV1ChatCompletion) RequestBody(_ []byte, openAIReq *openai.ChatCompletionRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.stream = openAIReq.Stream
+	o.requestModel = openAIReq.Model
+	if o.modelNameOverride != "" {
+		// Use modelName override if set.
+		o.requestModel = o.modelNameOverride
+	}
+
+	cohereReq := &cohereschema.ChatV2Request{
+		Model:            o.requestModel,
+		Stream:           openAIReq.Stream,
+		MaxTokens:        cmp.Or(openAIReq.MaxCompletionTokens, openAIReq.MaxTokens),
+		Temperature:      openAIReq.Temperature,
+		P:                openAIReq.TopP,
+		Seed:             openAIReq.Seed,
+		FrequencyPenalty: openAIReq.FrequencyPenalty,
+		PresencePenalty:  openAIReq.PresencePenalty,
+		Logprobs:         openAIReq.LogProbs,
+	}
+	if openAIReq.Stop.OfString.Valid() {
+		cohereReq.StopSequences = []string{openAIReq.Stop.OfString.String()}
+	} else if openAIReq.Stop.OfStringArray != nil {
+		cohereReq.StopSequences = openAIReq.Stop.OfStringArray
+	}
+	if openAIReq.Thinking != nil {
+		if openAIReq.Thinking.OfEnabled != nil {
+			cohereReq.Thinking = &cohereschema.ChatV2Thinking{Type: "enabled", TokenBudget: &openAIReq.Thinking.OfEnabled.BudgetTokens}
+		} else if openAIReq.Thinking.OfDisabled != nil {
+			cohereReq.Thinking = &cohereschema.ChatV2Thinking{Type: "disabled"}
+		}
+	}
+	if rf := openAIReq.ResponseFormat; rf != nil {
+		switch {
+		case rf.OfJSONObject != nil:
+			cohereReq.ResponseFormat = &cohereschema.ChatV2ResponseFormat{Type: "json_object"}
+		case rf.OfJSONSchema != nil:
+			cohereReq.ResponseFormat = &cohereschema.ChatV2ResponseFormat{Type: "json_object"}
+			if len(rf.OfJSONSchema.JSONSchema.Schema) > 0 {
+				cohereReq.ResponseFormat.JSONSchema = rf.OfJSONSchema.JSONSchema.Schema
+			}
+		}
+	}
+
+	if cohereReq.Messages, err = openAIMessagesToCohereMessages(openAIReq.Messages); err != nil {
+		return nil, nil, err
+	}
+	if err = openAIToolsToCohereTools(openAIReq, cohereReq); err != nil {
+		return nil, nil, err
+	}
+
+	newBody, err = json.Marshal(cohereReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, o.path},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// openAIToolsToCohereTools converts the OpenAI tools and tool choice to the Cohere ones.
+// Cohere cannot force a specific tool, so a named tool choice is translated to "REQUIRED" with only the named tool.
+func openAIToolsToCohereTools(openAIReq *openai.ChatCompletionRequest, cohereReq *cohereschema.ChatV2Request) error {
+	var namedTool string
+	if openAIReq.ToolChoice != nil {
+		switch toolChoice := openAIReq.ToolChoice.Value.(type) {
+		case string:
+			switch toolChoice {
+			case "auto":
+			case "none":
+				cohereReq.ToolChoice = cohereschema.ChatV2ToolChoiceNone
+			case "required":
+				cohereReq.ToolChoice = cohereschema.ChatV2ToolChoiceRequired
+			default:
+				return fmt.Errorf("%w: unsupported tool_choice %q", internalapi.ErrInvalidRequestBody, toolChoice)
+			}
+		case openai.ChatCompletionNamedToolChoice:
+			namedTool = toolChoice.Function.Name
+			cohereReq.ToolChoice = cohereschema.ChatV2ToolChoiceRequired
+		default:
+			return fmt.Errorf("%w: tool_choice type not supported", internalapi.ErrInvalidRequestBody)
+		}
+	}
+
+	for i := range openAIReq.Tools {
+		tool := &openAIReq.Tools[i]
+		if tool.Function == nil {
+			return fmt.Errorf("%w: unsupported tool type %q", internalapi.ErrInvalidRequestBody, tool.Type)
+		}
+		if namedTool != "" && tool.Function.Name != namedTool {
+			continue
+		}
+		cohereReq.Tools = append(cohereReq.Tools, cohereschema.ChatV2Tool{
+			Type: "function",
+			Function: cohereschema.ChatV2Function{
+				Name:        tool.Function.Name,
+				Description: tool.Function.Description,
+				Parameters:  tool.Function.Parameters,
+			},
+		})
+		if tool.Function.Strict {
+			cohereReq.StrictTools = &tool.Function.Strict
+		}
+	}
+	if namedTool != "" && len(cohereReq.Tools) == 0 {
+		return fmt.Errorf("%w: tool_choice function %q not found in tools", internalapi.ErrInvalidRequestBody, namedTool)
+	}
+	return nil
+}
+
+// openAIMessagesToCohereMessages converts the OpenAI chat messages to the Cohere ones.
+// The developer messages are sent as system messages.
+func openAIMessagesToCohereMessages(messages []openai.ChatCompletionMessageParamUnion) ([]cohereschema.ChatV2Message, error) {
+	cohereMessages := make([]cohereschema.ChatV2Message, 0, len(messages))
+	for i := range messages {
+		msg := &messages[i]
+		switch {
+		case msg.OfSystem != nil:
+			content, err := openAITextContentToCohereContent(msg.OfSystem.Content.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: unexpected content type for system message", internalapi.ErrInvalidRequestBody)
+			}
+			cohereMessages = append(cohereMessages, cohereschema.ChatV2Message{Role: cohereschema.ChatV2RoleSystem, Content: content})
+		case msg.OfDeveloper != nil:
+			content, err := openAITextContentToCohereContent(msg.OfDeveloper.Content.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: unexpected content type for developer message", internalapi.ErrInvalidRequestBody)
+			}
+			cohereMessages = append(cohereMessages, cohereschema.ChatV2Message{Role: cohereschema.ChatV2RoleSystem, Content: content})
+		case msg.OfUser != nil:
+			content, err := openAIUserContentToCohereContent(msg.OfUser)
+			if err != nil {
+				return nil, err
+			}
+			cohereMessages = append(cohereMessages, cohereschema.ChatV2Message{Role: cohereschema.ChatV2RoleUser, Content: content})
+		case msg.OfAssistant != nil:
+			cohereMessages = append(cohereMessages, openAIAssistantMessageToCohereMessage(msg.OfAssistant))
+		case msg.OfTool != nil:
+			content, err := openAITextContentToCohereContent(msg.OfTool.Content.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: message 'content' must be a string or an array", internalapi.ErrInvalidRequestBody)
+			}
+			cohereMessages = append(cohereMessages, cohereschema.ChatV2Message{
+				Role: cohereschema.ChatV2RoleTool, Content: content, ToolCallID: msg.OfTool.ToolCallID,
+			})
+		default:
+			return nil, fmt.Errorf("%w: unexpected role: %s", internalapi.ErrInvalidRequestBody, msg.ExtractMessgaeRole())
+		}
+	}
+	return cohereMessages, nil
+}
+
+// openAITextContentToCohereContent converts the content of the system, developer and tool messages, which is
+// either a string or a list of text parts.
+func openAITextContentToCohereContent(value any) ([]cohereschema.ChatV2Content, error) {
+	switch v := value.(type) {
+	case string:
+		return []cohereschema.ChatV2Content{{Type: cohereschema.ChatV2ContentTypeText, Text: v}}, nil
+	case []openai.ChatCompletionContentPartTextParam:
+		content := make([]cohereschema.ChatV2Content, 0, len(v))
+		for i := range v {
+			content = append(content, cohereschema.ChatV2Content{Type: cohereschema.ChatV2ContentTypeText, Text: v[i].Text})
+		}
+		return content, nil
+	default:
+		return nil, fmt.Errorf("unexpected content type %T", value)
+	}
+}
+
+// openAIUserContentToCohereContent converts the content of a user message. Only text and image parts are supported.
+func openAIUserContentToCohereContent(msg *openai.ChatCompletionUserMessageParam) ([]cohereschema.ChatV2Content, error) {
+	switch v := msg.Content.Value.(type) {
+	case string:
+		return []cohereschema.ChatV2Content{{Type: cohereschema.ChatV2ContentTypeText, Text: v}}, nil
+	case []openai.ChatCompletionContentPartUserUnionParam:
+		content := make([]cohereschema.ChatV2Content, 0, len(v))
+		for i := range v {
+			part := &v[i]
+			switch {
+			case part.OfText != nil:
+				content = append(content, cohereschema.ChatV2Content{Type: cohereschema.ChatV2ContentTypeText, Text: part.OfText.Text})
+			case part.OfImageURL != nil:
+				content = append(content, cohereschema.ChatV2Content{
+					Type: cohereschema.ChatV2ContentTypeImageURL,
+					ImageURL: &cohereschema.ChatV2ImageURL{
+						URL:    part.OfImageURL.ImageURL.URL,
+						Detail: string(part.OfImageURL.ImageURL.Detail),
+					},
+				})
+			default:
+				return nil, fmt.Errorf("%w: only text and image_url content parts are supported for user messages", internalapi.ErrInvalidRequestBody)
+			}
+		}
+		return content, nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected content type for user message", internalapi.ErrInvalidRequestBody)
+	}
+}
+
+// openAIAssistantMessageToCohereMessage converts an assistant message, including its tool calls.
+// The refusals are sent as text and the thinking parts as thinking content.
+func openAIAssistantMessageToCohereMessage(msg *openai.ChatCompletionAssistantMessageParam) cohereschema.ChatV2Message {
+	cohereMessage := cohereschema.ChatV2Message{Role: cohereschema.ChatV2RoleAssistant}
+
+	var contentParts []openai.ChatCompletionAssistantMessageParamContent
+	if v, ok := msg.Content.Value.(string); ok && len(v) > 0 {
+		contentParts = append(contentParts, openai.ChatCompletionAssistantMessageParamContent{Type: openai.ChatCompletionAssistantMessageParamContentTypeText, Text: &v})
+	} else if singleContent, ok := msg.Content.Value.(openai.ChatCompletionAssistantMessageParamContent); ok {
+		contentParts = append(contentParts, singleContent)
+	} else if sliceContent, ok := msg.Content.Value.([]openai.ChatCompletionAssistantMessageParamContent); ok {
+		contentParts = sliceContent
+	}
+	for _, part := range contentParts {
+		switch part.Type {
+		case openai.ChatCompletionAssistantMessageParamContentTypeText:
+			if part.Text != nil {
+				cohereMessage.Content = append(cohereMessage.Content, cohereschema.ChatV2Content{Type: cohereschema.ChatV2ContentTypeText, Text: *part.Text})
+			}
+		case openai.ChatCompletionAssistantMessageParamContentTypeThinking:
+			if part.Text != nil {
+				cohereMessage.Content = append(cohereMessage.Content, cohereschema.ChatV2Content{Type: cohereschema.ChatV2ContentTypeThinking, Thinking: *part.Text})
+			}
+		case openai.ChatCompletionAssistantMessageParamContentTypeRefusal:
+			if part.Refusal != nil {
+				cohereMessage.Content = append(cohereMessage.Content, cohereschema.ChatV2Content{Type: cohereschema.ChatV2ContentTypeText, Text: *part.Refusal})
+			}
+		}
+	}
+
+	for i := range msg.ToolCalls {
+		toolCall := &msg.ToolCalls[i]
+		var id string
+		if toolCall.ID != nil {
+			id = *toolCall.ID
+		}
+		cohereMessage.ToolCalls = append(cohereMessage.ToolCalls, cohereschema.ChatV2ToolCall{
+			ID:   id,
+			Type: "function",
+			Function: cohereschema.ChatV2ToolCallFunction{
+				Name:      toolCall.Function.Name,
+				Arguments: toolCall.Function.Arguments,
+			},
+		})
+	}
+	return cohereMessage
+}
+
+// ResponseHeaders implements [OpenAIChatCompletionTranslator.ResponseHeaders].
+func (o *openAIToCohereTranslatorV1ChatCompletion) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIChatCompletionTranslator.ResponseBody].
+// Cohere does not return the model in the response, so the request model is returned as the response model.
+func (o *openAIToCohereTranslatorV1ChatCompletion) ResponseBody(_ map[string]string, body io.Reader, endOfStream bool, span tracingapi.ChatCompletionSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	responseModel = o.requestModel
+	if o.stream {
+		var buf []byte
+		buf, err = io.ReadAll(body)
+		if err != nil {
+			return nil, nil, metrics.TokenUsage{}, "", fmt.Errorf("failed to read body: %w", err)
+		}
+		o.bufferedBody = append(o.bufferedBody, buf...)
+
+		newBody = make([]byte, 0)
+		for _, event := range o.extractCohereStreamEvents() {
+			if event.Type == cohereschema.ChatV2StreamEventTypeMessageEnd && event.Delta != nil && event.Delta.Usage != nil {
+				tokenUsage = cohereChatTokenUsage(event.Delta.Usage)
+			}
+			chunk, ok := o.convertEvent(&event)
+			if !ok {
+				continue
+			}
+			if err = serializeOpenAIChatCompletionChunk(chunk, &newBody); err != nil {
+				return nil, nil, metrics.TokenUsage{}, "", fmt.Errorf("failed to marshal chunk: %w", err)
+			}
+			if span != nil {
+				span.RecordResponseChunk(chunk)
+			}
+		}
+		if endOfStream {
+			newBody = append(newBody, sseDoneFullLine...)
+		}
+		return
+	}
+
+	var cohereResp cohereschema.ChatV2Response
+	if err = json.NewDecoder(body).Decode(&cohereResp); err != nil {
+		return nil, nil, metrics.TokenUsage{}, "", fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+
+	openAIResp := &openai.ChatCompletionResponse{
+		ID:      cohereResp.ID,
+		Model:   o.requestModel,
+		Object:  "chat.completion",
+		Created: openai.JSONUNIXTime(time.Now()),
+	}
+	if cohereResp.Usage != nil {
+		tokenUsage = cohereChatTokenUsage(cohereResp.Usage)
+		openAIResp.Usage = cohereChatUsageToOpenAIUsage(cohereResp.Usage)
+	}
+
+	// Cohere Chat API does not support multiple choices, so there is only one choice.
+	choice := openai.ChatCompletionResponseChoice{
+		Index:        0,
+		Message:      openai.ChatCompletionResponseChoiceMessage{Role: openai.ChatMessageRoleAssistant},
+		FinishReason: cohereFinishReasonToOpenAI(cohereResp.FinishReason),
+	}
+	var text, reasoning strings.Builder
+	reasoning.WriteString(cohereResp.Message.ToolPlan)
+	for i := range cohereResp.Message.Content {
+		switch c := &cohereResp.Message.Content[i]; c.Type {
+		case cohereschema.ChatV2ContentTypeText:
+			text.WriteString(c.Text)
+		case cohereschema.ChatV2ContentTypeThinking:
+			reasoning.WriteString(c.Thinking)
+		}
+	}
+	if text.Len() > 0 || len(cohereResp.Message.ToolCalls) == 0 {
+		choice.Message.Content = ptr.To(text.String())
+	}
+	if reasoning.Len() > 0 {
+		choice.Message.ReasoningContent = &openai.ReasoningContentUnion{Value: reasoning.String()}
+	}
+	for i := range cohereResp.Message.ToolCalls {
+		choice.Message.ToolCalls = append(choice.Message.ToolCalls, cohereToolCallToOpenAI(&cohereResp.Message.ToolCalls[i]))
+	}
+	if annotations := cohereCitationsToOpenAIAnnotations(cohereResp.Message.Citations); len(annotations) > 0 {
+		choice.Message.Annotations = &annotations
+	}
+	openAIResp.Choices = []openai.ChatCompletionResponseChoice{choice}
+
+	newBody, err = json.Marshal(openAIResp)
+	if err != nil {
+		return nil, nil, metrics.TokenUsage{}, "", fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(openAIResp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// extractCohereStreamEvents extracts the complete server-sent events from the buffered body, keeping the incomplete
+// trailing event in the buffer for the next call.
+func (o *openAIToCohereTranslatorV1ChatCompletion) extractCohereStreamEvents() []cohereschema.ChatV2StreamEvent {
+	var events []cohereschema.ChatV2StreamEvent
+	for {
+		idx := bytes.Index(o.bufferedBody, []byte("\n\n"))
+		if idx < 0 {
+			return events
+		}
+		rawEvent := o.bufferedBody[:idx]
+		o.bufferedBody = o.bufferedBody[idx+2:]
+		for _, line := range bytes.Split(rawEvent, []byte("\n")) {
+			data, ok := bytes.CutPrefix(bytes.TrimSpace(line), sseDataPrefix)
+			if !ok {
+				continue
+			}
+			var event cohereschema.ChatV2StreamEvent
+			// Ignore the unparsable events to maintain the stream continuity.
+			if err := json.Unmarshal(data, &event); err == nil {
+				events = append(events, event)
+			}
+		}
+	}
+}
+
+// convertEvent converts a [cohereschema.ChatV2StreamEvent] to an [openai.ChatCompletionResponseChunk].
+// It returns false when the event has no OpenAI equivalent.
+func (o *openAIToCohereTranslatorV1ChatCompletion) convertEvent(event *cohereschema.ChatV2StreamEvent) (*openai.ChatCompletionResponseChunk, bool) {
+	chunk := &openai.ChatCompletionResponseChunk{
+		Object: "chat.completion.chunk", Model: o.requestModel, ID: o.responseID,
+		Created: openai.JSONUNIXTime(time.Now()),
+		Choices: []openai.ChatCompletionResponseChunkChoice{},
+	}
+	delta := &openai.ChatCompletionResponseChunkChoiceDelta{}
+	var msg *cohereschema.ChatV2StreamDeltaMessage
+	if event.Delta != nil {
+		msg = event.Delta.Message
+	}
+
+	switch event.Type {
+	case cohereschema.ChatV2StreamEventTypeMessageStart:
+		o.responseID = event.ID
+		chunk.ID = event.ID
+		delta.Role = openai.ChatMessageRoleAssistant
+		delta.Content = &emptyString
+	case cohereschema.ChatV2StreamEventTypeContentDelta:
+		if msg == nil || msg.Content == nil {
+			return chunk, false
+		}
+		switch msg.Content.Type {
+		case cohereschema.ChatV2ContentTypeThinking:
+			delta.ReasoningContent = &openai.StreamReasoningContent{Text: msg.Content.Thinking}
+		default:
+			delta.Content = &msg.Content.Text
+		}
+	case cohereschema.ChatV2StreamEventTypeToolPlanDelta:
+		if msg == nil {
+			return chunk, false
+		}
+		delta.ReasoningContent = &openai.StreamReasoningContent{Text: msg.ToolPlan}
+	case cohereschema.ChatV2StreamEventTypeToolCallStart:
+		if msg == nil || msg.ToolCalls == nil {
+			return chunk, false
+		}
+		delta.ToolCalls = []openai.ChatCompletionChunkChoiceDeltaToolCall{{
+			Index: o.toolIndex,
+			ID:    &msg.ToolCalls.ID,
+			Type:  openai.ChatCompletionMessageToolCallTypeFunction,
+			Function: openai.ChatCompletionMessageToolCallFunctionParam{
+				Name:      msg.ToolCalls.Function.Name,
+				Arguments: msg.ToolCalls.Function.Arguments,
+			},
+		}}
+	case cohereschema.ChatV2StreamEventTypeToolCallDelta:
+		if msg == nil || msg.ToolCalls == nil {
+			return chunk, false
+		}
+		delta.ToolCalls = []openai.ChatCompletionChunkChoiceDeltaToolCall{{
+			Index: o.toolIndex,
+			Type:  openai.ChatCompletionMessageToolCallTypeFunction,
+			Function: openai.ChatCompletionMessageToolCallFunctionParam{
+				Arguments: msg.ToolCalls.Function.Arguments,
+			},
+		}}
+	case cohereschema.ChatV2StreamEventTypeToolCallEnd:
+		o.toolIndex++
+		return chunk, false
+	case cohereschema.ChatV2StreamEventTypeCitationStart:
+		if msg == nil || msg.Citations == nil {
+			return chunk, false
+		}
+		annotations := cohereCitationsToOpenAIAnnotations([]cohereschema.ChatV2Citation{*msg.Citations})
+		if len(annotations) == 0 {
+			return chunk, false
+		}
+		delta.Annotations = &annotations
+	case cohereschema.ChatV2StreamEventTypeMessageEnd:
+		if event.Delta == nil {
+			return chunk, false
+		}
+		chunk.Choices = append(chunk.Choices, openai.ChatCompletionResponseChunkChoice{
+			Index:        0,
+			Delta:        &openai.ChatCompletionResponseChunkChoiceDelta{Content: &emptyString},
+			FinishReason: cohereFinishReasonToOpenAI(event.Delta.FinishReason),
+		})
+		if event.Delta.Usage != nil {
+			usage := cohereChatUsageToOpenAIUsage(event.Delta.Usage)
+			chunk.Usage = &usage
+		}
+		return chunk, true
+	default:
+		return chunk, false
+	}
+	chunk.Choices = append(chunk.Choices, openai.ChatCompletionResponseChunkChoice{Index: 0, Delta: delta})
+	return chunk, true
+}
+
+// ResponseError implements [OpenAIChatCompletionTranslator.ResponseError].
+// Translate Cohere errors to OpenAI error type. Cohere returns the errors as a JSON object with a message field.
+func (o *openAIToCohereTranslatorV1ChatCompletion) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return convertCohereErrorToOpenAI(respHeaders, body)
+}
+
+// convertCohereErrorToOpenAI converts a Cohere error response to the OpenAI error format.
+// The non-JSON error bodies, e.g. on connection failures, are used as the error message.
+func convertCohereErrorToOpenAI(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	statusCode := respHeaders[statusHeaderName]
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+	}
+	message := string(buf)
+	if v, ok := respHeaders[contentTypeHeaderName]; ok && strings.Contains(v, jsonContentType) {
+		var cohereError cohereschema.RerankV2Error
+		if err = json.Unmarshal(buf, &cohereError); err == nil && cohereError.Message != nil {
+			message = *cohereError.Message
+		}
+	}
+	openaiError := openai.Error{
+		Type: "error",
+		Error: openai.ErrorType{
+			Type:    cohereBackendError,
+			Message: message,
+			Code:    &statusCode,
+		},
+	}
+	newBody, err = json.Marshal(openaiError)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal error body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{contentTypeHeaderName, jsonContentType},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// cohereFinishReasonToOpenAI converts the Cohere finish reason to the OpenAI one.
+func cohereFinishReasonToOpenAI(reason cohereschema.ChatV2FinishReason) openai.ChatCompletionChoicesFinishReason {
+	switch reason {
+	case cohereschema.ChatV2FinishReasonMaxTokens:
+		return openai.ChatCompletionChoicesFinishReasonLength
+	case cohereschema.ChatV2FinishReasonToolCall:
+		return openai.ChatCompletionChoicesFinishReasonToolCalls
+	case cohereschema.ChatV2FinishReasonError, cohereschema.ChatV2FinishReasonTimeout:
+		return openai.ChatCompletionChoicesFinishReasonError
+	default:
+		return openai.ChatCompletionChoicesFinishReasonStop
+	}
+}
+
+// cohereToolCallToOpenAI converts a Cohere tool call to the OpenAI one.
+func cohereToolCallToOpenAI(toolCall *cohereschema.ChatV2ToolCall) openai.ChatCompletionMessageToolCallParam {
+	return openai.ChatCompletionMessageToolCallParam{
+		ID:   &toolCall.ID,
+		Type: openai.ChatCompletionMessageToolCallTypeFunction,
+		Function: openai.ChatCompletionMessageToolCallFunctionParam{
+			Name:      toolCall.Function.Name,
+			Arguments: toolCall.Function.Arguments,
+		},
+	}
+}
+
+// cohereCitationsToOpenAIAnnotations converts the Cohere citations to the OpenAI URL citation annotations, one per
+// source. The URL is the "url" field of the cited document, or the source ID when the document has none, and the
+// title is the "title" field of the document, or the cited text.
+func cohereCitationsToOpenAIAnnotations(citations []cohereschema.ChatV2Citation) []openai.Annotation {
+	var annotations []openai.Annotation
+	for i := range citations {
+		citation := &citations[i]
+		for j := range citation.Sources {
+			source := &citation.Sources[j]
+			url, _ := source.Document["url"].(string)
+			title, _ := source.Document["title"].(string)
+			annotations = append(annotations, openai.Annotation{
+				Type: "url_citation",
+				URLCitation: &openai.URLCitation{
+					StartIndex: citation.Start,
+					EndIndex:   citation.End,
+					URL:        cmp.Or(url, source.ID),
+					Title:      cmp.Or(title, citation.Text),
+				},
+			})
+		}
+	}
+	return annotations
+}
+
+// cohereChatTokenUsage returns the token usage of the Cohere chat response. The token counts are taken from the
+// tokens and fall back to the billed units.
+func cohereChatTokenUsage(usage *cohereschema.ChatV2Usage) (tokenUsage metrics.TokenUsage) {
+	var input, output *float64
+	if usage.BilledUnits != nil {
+		input, output = usage.BilledUnits.InputTokens, usage.BilledUnits.OutputTokens
+	}
+	if usage.Tokens != nil {
+		input, output = cmp.Or(usage.Tokens.InputTokens, input), cmp.Or(usage.Tokens.OutputTokens, output)
+	}
+	var totalTokens uint32
+	if input != nil {
+		// Cohere uses float; round down to uint32 like rerank.
+		tokenUsage.SetInputTokens(uint32(*input)) //nolint:gosec
+		totalTokens += uint32(*input)             //nolint:gosec
+	}
+	if output != nil {
+		tokenUsage.SetOutputTokens(uint32(*output)) //nolint:gosec
+		totalTokens += uint32(*output)              //nolint:gosec
+	}
+	tokenUsage.SetTotalTokens(totalTokens)
+	if usage.CachedTokens != nil {
+		tokenUsage.SetCachedInputTokens(uint32(*usage.CachedTokens)) //nolint:gosec
+	}
+	return
+}
+
+// cohereChatUsageToOpenAIUsage converts the usage of the Cohere chat response to the OpenAI one.
+func cohereChatUsageToOpenAIUsage(usage *cohereschema.ChatV2Usage) openai.Usage {
+	tokenUsage := cohereChatTokenUsage(usage)
+	inputTokens, _ := tokenUsage.InputTokens()
+	outputTokens, _ := tokenUsage.OutputTokens()
+	totalTokens, _ := tokenUsage.TotalTokens()
+	openAIUsage := openai.Usage{
+		PromptTokens:     int(inputTokens),
+		CompletionTokens: int(outputTokens),
+		TotalTokens:      int(totalTokens),
+	}
+	if cachedTokens, ok := tokenUsage.CachedInputTokens(); ok {
+		openAIUsage.PromptTokensDetails = &openai.PromptTokensDetails{CachedTokens: int(cachedTokens)}
+	}
+	return openAIUsage
+}
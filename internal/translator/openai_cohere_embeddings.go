@@ -0,0 +1,217 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strconv"
+
+	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	tracing "github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewEmbeddingOpenAIToCohereTranslator implements [Factory] for OpenAI to Cohere Embed v2 translation.
+func NewEmbeddingOpenAIToCohereTranslator(apiVersion string, modelNameOverride internalapi.ModelNameOverride) OpenAIEmbeddingTranslator {
+	return &openAIToCohereTranslatorV1Embedding{modelNameOverride: modelNameOverride, path: path.Join("/", apiVersion, "embed")} // e.g., /v2/embed
+}
+
+// openAIToCohereTranslatorV1Embedding translates OpenAI Embeddings API requests to Cohere Embed API v2:
+// https://docs.cohere.com/reference/embed
+type openAIToCohereTranslatorV1Embedding struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+	// The path of the embed endpoint to be used for the request. It is prefixed with the API path prefix.
+	path string
+	// embeddingType is the Cohere embedding type requested from the backend.
+	embeddingType cohereschema.EmbedV2EmbeddingType
+	// base64Encoding is true when the float embeddings must be returned base64 encoded to the client.
+	base64Encoding bool
+}
+
+// RequestBody implements [OpenAIEmbeddingTranslator.RequestBody].
+func (o *openAIToCohereTranslatorV1Embedding) RequestBody(_ []byte, req *openai.EmbeddingRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.requestModel = req.Model
+	if o.modelNameOverride != "" {
+		// Use modelName override if set.
+		o.requestModel = o.modelNameOverride
+	}
+
+	cohereReq, err := o.openAIEmbeddingToCohereEmbed(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	newBody, err = json.Marshal(cohereReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, o.path},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// openAIEmbeddingToCohereEmbed converts an OpenAI EmbeddingRequest to a Cohere EmbedV2Request.
+// The inputs are flattened the same way as for Gemini. Cohere has a single input type per request, which is taken
+// from the input_type vendor field, or derived from the global or the first input item task type.
+// When any input has an image, all the inputs are sent as the multimodal inputs of the embed v4 models.
+func (o *openAIToCohereTranslatorV1Embedding) openAIEmbeddingToCohereEmbed(req *openai.EmbeddingRequest) (*cohereschema.EmbedV2Request, error) {
+	inputs, err := embeddingInputs(req.Input)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", internalapi.ErrInvalidRequestBody, err)
+	}
+
+	cohereReq := &cohereschema.EmbedV2Request{
+		Model:           o.requestModel,
+		OutputDimension: req.Dimensions,
+	}
+	var taskType openai.EmbeddingTaskType
+	multimodal := slices.ContainsFunc(inputs, func(in embeddingInput) bool { return in.imageURL != "" })
+	for _, in := range inputs {
+		if taskType == "" {
+			taskType = in.taskType
+		}
+		if !multimodal {
+			cohereReq.Texts = append(cohereReq.Texts, in.text)
+			continue
+		}
+		var content []cohereschema.EmbedV2Content
+		if in.text != "" {
+			content = append(content, cohereschema.EmbedV2Content{Type: "text", Text: in.text})
+		}
+		if in.imageURL != "" {
+			content = append(content, cohereschema.EmbedV2Content{Type: "image_url", ImageURL: &cohereschema.EmbedV2ContentImage{URL: in.imageURL}})
+		}
+		cohereReq.Inputs = append(cohereReq.Inputs, cohereschema.EmbedV2Input{Content: content})
+	}
+	if req.GCPVertexAIEmbeddingVendorFields != nil && req.TaskType != "" {
+		taskType = req.TaskType
+	}
+	cohereReq.InputType = embeddingTaskTypeToCohereInputType(taskType)
+
+	o.embeddingType = cohereschema.EmbedV2EmbeddingTypeFloat
+	if v := req.CohereEmbeddingVendorFields; v != nil {
+		if v.InputType != "" {
+			cohereReq.InputType = cohereschema.EmbedV2InputType(v.InputType)
+		}
+		if v.EmbeddingType != "" {
+			o.embeddingType = cohereschema.EmbedV2EmbeddingType(v.EmbeddingType)
+		}
+		cohereReq.Truncate = v.Truncate
+	}
+	switch o.embeddingType {
+	case cohereschema.EmbedV2EmbeddingTypeFloat, cohereschema.EmbedV2EmbeddingTypeInt8, cohereschema.EmbedV2EmbeddingTypeUint8,
+		cohereschema.EmbedV2EmbeddingTypeBinary, cohereschema.EmbedV2EmbeddingTypeUbinary:
+	default:
+		return nil, fmt.Errorf("%w: unsupported embedding_type %q", internalapi.ErrInvalidRequestBody, o.embeddingType)
+	}
+	cohereReq.EmbeddingTypes = []cohereschema.EmbedV2EmbeddingType{o.embeddingType}
+
+	if isBase64EncodingFormat(req) {
+		// Cohere base64 embeddings are not compatible with OpenAI ones, so the float embeddings are encoded locally.
+		if o.embeddingType != cohereschema.EmbedV2EmbeddingTypeFloat {
+			return nil, fmt.Errorf("%w: base64 encoding_format is only supported with the float embedding_type", internalapi.ErrInvalidRequestBody)
+		}
+		o.base64Encoding = true
+	}
+	return cohereReq, nil
+}
+
+// embeddingTaskTypeToCohereInputType converts the Gemini task type of the embedding inputs to the Cohere input type.
+// The task types without a Cohere equivalent default to "search_document".
+func embeddingTaskTypeToCohereInputType(taskType openai.EmbeddingTaskType) cohereschema.EmbedV2InputType {
+	switch taskType {
+	case openai.EmbeddingTaskTypeRetrievalQuery, openai.EmbeddingTaskTypeQuestionAnswering, openai.EmbeddingTaskTypeCodeRetrievalQuery:
+		return cohereschema.EmbedV2InputTypeSearchQuery
+	case openai.EmbeddingTaskTypeClassification:
+		return cohereschema.EmbedV2InputTypeClassification
+	case openai.EmbeddingTaskTypeClustering:
+		return cohereschema.EmbedV2InputTypeClustering
+	default:
+		return cohereschema.EmbedV2InputTypeSearchDocument
+	}
+}
+
+// ResponseHeaders implements [OpenAIEmbeddingTranslator.ResponseHeaders].
+func (o *openAIToCohereTranslatorV1Embedding) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIEmbeddingTranslator.ResponseBody].
+// Cohere does not return the model in the response, so the request model is returned as the response model.
+func (o *openAIToCohereTranslatorV1Embedding) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracing.EmbeddingsSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	var cohereResp cohereschema.EmbedV2Response
+	if err = json.NewDecoder(body).Decode(&cohereResp); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+
+	var embeddings [][]float64
+	switch o.embeddingType {
+	case cohereschema.EmbedV2EmbeddingTypeInt8:
+		embeddings = cohereResp.Embeddings.Int8
+	case cohereschema.EmbedV2EmbeddingTypeUint8:
+		embeddings = cohereResp.Embeddings.Uint8
+	case cohereschema.EmbedV2EmbeddingTypeBinary:
+		embeddings = cohereResp.Embeddings.Binary
+	case cohereschema.EmbedV2EmbeddingTypeUbinary:
+		embeddings = cohereResp.Embeddings.Ubinary
+	default:
+		embeddings = cohereResp.Embeddings.Float
+	}
+
+	openaiResp := openai.EmbeddingResponse{
+		Object: "list",
+		Model:  o.requestModel,
+		Data:   make([]openai.Embedding, 0, len(embeddings)),
+	}
+	for i, embedding := range embeddings {
+		openaiResp.Data = append(openaiResp.Data, openai.Embedding{Object: "embedding", Index: i, Embedding: embeddingUnion(embedding, o.base64Encoding)})
+	}
+
+	if meta := cohereResp.Meta; meta != nil {
+		var inputTokens *float64
+		if meta.BilledUnits != nil {
+			inputTokens = meta.BilledUnits.InputTokens
+		}
+		if meta.Tokens != nil && meta.Tokens.InputTokens != nil {
+			inputTokens = meta.Tokens.InputTokens
+		}
+		if inputTokens != nil {
+			// Cohere uses float; round down to uint32 like rerank.
+			tokenUsage.SetInputTokens(uint32(*inputTokens)) //nolint:gosec
+			tokenUsage.SetTotalTokens(uint32(*inputTokens)) //nolint:gosec
+			openaiResp.Usage = openai.EmbeddingUsage{PromptTokens: int(*inputTokens), TotalTokens: int(*inputTokens)}
+		}
+	}
+
+	newBody, err = json.Marshal(openaiResp)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(&openaiResp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return newHeaders, newBody, tokenUsage, openaiResp.Model, nil
+}
+
+// ResponseError implements [OpenAIEmbeddingTranslator.ResponseError].
+func (o *openAIToCohereTranslatorV1Embedding) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return convertCohereErrorToOpenAI(respHeaders, body)
+}
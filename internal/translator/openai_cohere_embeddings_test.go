@@ -0,0 +1,124 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestOpenAIToCohereTranslatorV1Embedding_RequestBody(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		req               string
+		modelNameOverride string
+		expBody           string
+	}{
+		{
+			name:    "string input",
+			req:     `{"model":"embed-v4.0","input":"hello"}`,
+			expBody: `{"model":"embed-v4.0","input_type":"search_document","texts":["hello"],"embedding_types":["float"]}`,
+		},
+		{
+			name:              "task type and dimensions",
+			req:               `{"model":"embed-v4.0","input":[{"content":["a","b"],"task_type":"RETRIEVAL_QUERY"}],"dimensions":512}`,
+			modelNameOverride: "embed-english-v3.0",
+			expBody:           `{"model":"embed-english-v3.0","input_type":"search_query","texts":["a","b"],"embedding_types":["float"],"output_dimension":512}`,
+		},
+		{
+			name:    "vendor fields",
+			req:     `{"model":"embed-v4.0","input":["a"],"task_type":"CLUSTERING","input_type":"classification","embedding_type":"int8","truncate":"END"}`,
+			expBody: `{"model":"embed-v4.0","input_type":"classification","texts":["a"],"embedding_types":["int8"],"truncate":"END"}`,
+		},
+		{
+			name: "multimodal inputs",
+			req: `{"model":"embed-v4.0","input":[{"content":"a"},{"content":[{"type":"text","text":"b"},
+{"type":"image_url","image_url":{"url":"data:image/png;base64,AAAA"}}]}]}`,
+			expBody: `{"model":"embed-v4.0","input_type":"search_document","inputs":[{"content":[{"type":"text","text":"a"}]},
+{"content":[{"type":"text","text":"b"},{"type":"image_url","image_url":{"url":"data:image/png;base64,AAAA"}}]}],"embedding_types":["float"]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var req openai.EmbeddingRequest
+			require.NoError(t, json.Unmarshal([]byte(tc.req), &req))
+			tr := NewEmbeddingOpenAIToCohereTranslator("v2", tc.modelNameOverride)
+			headers, body, err := tr.RequestBody(nil, &req, false)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expBody, string(body))
+			require.Equal(t, []internalapi.Header{
+				{pathHeaderName, "/v2/embed"},
+				{contentLengthHeaderName, strconv.Itoa(len(body))},
+			}, headers)
+		})
+	}
+
+	for _, tc := range []struct {
+		name   string
+		req    string
+		expErr string
+	}{
+		{name: "unsupported input", req: `{"model":"m","input":[1,2]}`, expErr: "unsupported input type"},
+		{name: "unsupported embedding type", req: `{"model":"m","input":"a","embedding_type":"base64"}`, expErr: `unsupported embedding_type "base64"`},
+		{name: "base64 with int8", req: `{"model":"m","input":"a","embedding_type":"int8","encoding_format":"base64"}`, expErr: "only supported with the float embedding_type"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var req openai.EmbeddingRequest
+			require.NoError(t, json.Unmarshal([]byte(tc.req), &req))
+			_, _, err := NewEmbeddingOpenAIToCohereTranslator("v2", "").RequestBody(nil, &req, false)
+			require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestOpenAIToCohereTranslatorV1Embedding_ResponseBody(t *testing.T) {
+	const body = `{"id":"e1","embeddings":{"float":[[1.0,-2.5]],"int8":[[1,-2]]},"texts":["a"],"meta":{"billed_units":{"input_tokens":2},"tokens":{"input_tokens":3}}}`
+	for _, tc := range []struct {
+		name    string
+		req     string
+		expBody string
+	}{
+		{
+			name:    "float",
+			req:     `{"model":"embed-v4.0","input":"a"}`,
+			expBody: `{"object":"list","data":[{"object":"embedding","index":0,"embedding":[1,-2.5]}],"model":"embed-v4.0","usage":{"prompt_tokens":3,"total_tokens":3}}`,
+		},
+		{
+			name:    "int8",
+			req:     `{"model":"embed-v4.0","input":"a","embedding_type":"int8"}`,
+			expBody: `{"object":"list","data":[{"object":"embedding","index":0,"embedding":[1,-2]}],"model":"embed-v4.0","usage":{"prompt_tokens":3,"total_tokens":3}}`,
+		},
+		{
+			name:    "base64",
+			req:     `{"model":"embed-v4.0","input":"a","encoding_format":"base64"}`,
+			expBody: `{"object":"list","data":[{"object":"embedding","index":0,"embedding":"AACAPwAAIMA="}],"model":"embed-v4.0","usage":{"prompt_tokens":3,"total_tokens":3}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var req openai.EmbeddingRequest
+			require.NoError(t, json.Unmarshal([]byte(tc.req), &req))
+			tr := NewEmbeddingOpenAIToCohereTranslator("v2", "")
+			_, _, err := tr.RequestBody(nil, &req, false)
+			require.NoError(t, err)
+
+			headers, newBody, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(body), true, nil)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expBody, string(newBody))
+			require.Equal(t, "embed-v4.0", responseModel)
+			require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}, headers)
+			in, ok := tokenUsage.InputTokens()
+			require.True(t, ok)
+			require.Equal(t, uint32(3), in)
+		})
+	}
+}
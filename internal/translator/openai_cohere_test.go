@@ -0,0 +1,238 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestOpenAIToCohereTranslatorV1ChatCompletion_RequestBody(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		req               string
+		modelNameOverride string
+		expBody           string
+	}{
+		{
+			name: "messages and parameters",
+			req: `{"model":"command-a-03-2025","messages":[
+{"role":"system","content":"be brief"},
+{"role":"developer","content":[{"type":"text","text":"be nice"}]},
+{"role":"user","content":[{"type":"text","text":"what is this?"},{"type":"image_url","image_url":{"url":"https://example.com/a.png","detail":"low"}}]},
+{"role":"assistant","content":"a cat"}],
+"max_tokens":100,"temperature":0.5,"top_p":0.9,"stop":["END"],"seed":42,"frequency_penalty":0.1,"presence_penalty":0.2,"stream":true}`,
+			expBody: `{"model":"command-a-03-2025","messages":[
+{"role":"system","content":[{"type":"text","text":"be brief"}]},
+{"role":"system","content":[{"type":"text","text":"be nice"}]},
+{"role":"user","content":[{"type":"text","text":"what is this?"},{"type":"image_url","image_url":{"url":"https://example.com/a.png","detail":"low"}}]},
+{"role":"assistant","content":[{"type":"text","text":"a cat"}]}],
+"stream":true,"max_tokens":100,"stop_sequences":["END"],"temperature":0.5,"seed":42,"frequency_penalty":0.1,"presence_penalty":0.2,"p":0.9}`,
+		},
+		{
+			name: "tools and tool calls",
+			req: `{"model":"command-a-03-2025","messages":[
+{"role":"user","content":"weather?"},
+{"role":"assistant","tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}}]},
+{"role":"tool","tool_call_id":"call_1","content":"sunny"}],
+"tools":[{"type":"function","function":{"name":"get_weather","description":"weather","parameters":{"type":"object"}}},
+{"type":"function","function":{"name":"get_time","parameters":{"type":"object"}}}],
+"tool_choice":{"type":"function","function":{"name":"get_weather"}}}`,
+			modelNameOverride: "command-r-plus",
+			expBody: `{"model":"command-r-plus","messages":[
+{"role":"user","content":[{"type":"text","text":"weather?"}]},
+{"role":"assistant","tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}}]},
+{"role":"tool","content":[{"type":"text","text":"sunny"}],"tool_call_id":"call_1"}],
+"tools":[{"type":"function","function":{"name":"get_weather","description":"weather","parameters":{"type":"object"}}}],
+"tool_choice":"REQUIRED"}`,
+		},
+		{
+			name: "response format and thinking",
+			req: `{"model":"command-a-reasoning-08-2025","messages":[{"role":"user","content":"hi"}],
+"tools":[{"type":"function","function":{"name":"f"}}],"tool_choice":"none",
+"response_format":{"type":"json_schema","json_schema":{"name":"s","schema":{"type":"object"}}},
+"thinking":{"type":"enabled","budget_tokens":512}}`,
+			expBody: `{"model":"command-a-reasoning-08-2025","messages":[{"role":"user","content":[{"type":"text","text":"hi"}]}],
+"tools":[{"type":"function","function":{"name":"f"}}],"tool_choice":"NONE",
+"response_format":{"type":"json_object","json_schema":{"type":"object"}},
+"thinking":{"type":"enabled","token_budget":512}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var req openai.ChatCompletionRequest
+			require.NoError(t, json.Unmarshal([]byte(tc.req), &req))
+			tr := NewChatCompletionOpenAIToCohereTranslator("v2", tc.modelNameOverride)
+			headers, body, err := tr.RequestBody(nil, &req, false)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expBody, string(body))
+			require.Equal(t, []internalapi.Header{
+				{pathHeaderName, "/v2/chat"},
+				{contentLengthHeaderName, strconv.Itoa(len(body))},
+			}, headers)
+		})
+	}
+
+	for _, tc := range []struct {
+		name   string
+		req    string
+		expErr string
+	}{
+		{
+			name:   "unsupported content part",
+			req:    `{"model":"m","messages":[{"role":"user","content":[{"type":"input_audio","input_audio":{"data":"abc","format":"wav"}}]}]}`,
+			expErr: "only text and image_url content parts are supported",
+		},
+		{
+			name:   "unknown named tool",
+			req:    `{"model":"m","messages":[{"role":"user","content":"hi"}],"tools":[{"type":"function","function":{"name":"f"}}],"tool_choice":{"type":"function","function":{"name":"g"}}}`,
+			expErr: `tool_choice function "g" not found in tools`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var req openai.ChatCompletionRequest
+			require.NoError(t, json.Unmarshal([]byte(tc.req), &req))
+			_, _, err := NewChatCompletionOpenAIToCohereTranslator("v2", "").RequestBody(nil, &req, false)
+			require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestOpenAIToCohereTranslatorV1ChatCompletion_ResponseBody(t *testing.T) {
+	tr := NewChatCompletionOpenAIToCohereTranslator("v2", "")
+	_, _, err := tr.RequestBody(nil, &openai.ChatCompletionRequest{Model: "command-a-03-2025"}, false)
+	require.NoError(t, err)
+
+	const body = `{"id":"resp-1","finish_reason":"COMPLETE","message":{"role":"assistant",
+"content":[{"type":"thinking","thinking":"let me see"},{"type":"text","text":"Paris is sunny."}],
+"tool_plan":"I will check. ",
+"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{}"}}],
+"citations":[{"start":0,"end":5,"text":"Paris","sources":[{"type":"document","id":"doc_0","document":{"url":"https://example.com","title":"Weather"}},{"type":"tool","id":"call_1:0","tool_output":{"weather":"sunny"}}]}]},
+"usage":{"billed_units":{"input_tokens":5,"output_tokens":3},"tokens":{"input_tokens":10,"output_tokens":7},"cached_tokens":4}}`
+	headers, newBody, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(body), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, "command-a-03-2025", responseModel)
+	require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}, headers)
+
+	var resp openai.ChatCompletionResponse
+	require.NoError(t, json.Unmarshal(newBody, &resp))
+	require.Equal(t, "resp-1", resp.ID)
+	require.Equal(t, openai.Usage{PromptTokens: 10, CompletionTokens: 7, TotalTokens: 17, PromptTokensDetails: &openai.PromptTokensDetails{CachedTokens: 4}}, resp.Usage)
+	require.Len(t, resp.Choices, 1)
+	choice := resp.Choices[0]
+	require.Equal(t, openai.ChatCompletionChoicesFinishReasonStop, choice.FinishReason)
+	require.Equal(t, "Paris is sunny.", *choice.Message.Content)
+	require.Equal(t, "I will check. let me see", choice.Message.ReasoningContent.Value)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	require.Equal(t, "get_weather", choice.Message.ToolCalls[0].Function.Name)
+	require.Equal(t, []openai.Annotation{
+		{Type: "url_citation", URLCitation: &openai.URLCitation{StartIndex: 0, EndIndex: 5, URL: "https://example.com", Title: "Weather"}},
+		{Type: "url_citation", URLCitation: &openai.URLCitation{StartIndex: 0, EndIndex: 5, URL: "call_1:0", Title: "Paris"}},
+	}, *choice.Message.Annotations)
+
+	in, _ := tokenUsage.InputTokens()
+	out, _ := tokenUsage.OutputTokens()
+	cached, _ := tokenUsage.CachedInputTokens()
+	require.Equal(t, [3]uint32{10, 7, 4}, [3]uint32{in, out, cached})
+}
+
+func TestOpenAIToCohereTranslatorV1ChatCompletion_ResponseBody_Streaming(t *testing.T) {
+	tr := NewChatCompletionOpenAIToCohereTranslator("v2", "")
+	_, _, err := tr.RequestBody(nil, &openai.ChatCompletionRequest{Model: "command-a-03-2025", Stream: true}, false)
+	require.NoError(t, err)
+
+	const stream = `event: message-start
+data: {"id":"resp-1","type":"message-start","delta":{"message":{"role":"assistant"}}}
+
+event: content-delta
+data: {"type":"content-delta","index":0,"delta":{"message":{"content":{"text":"Hello"}}}}
+
+event: tool-call-start
+data: {"type":"tool-call-start","index":0,"delta":{"message":{"tool_calls":{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}}}}
+
+event: tool-call-delta
+data: {"type":"tool-call-delta","index":0,"delta":{"message":{"tool_calls":{"function":{"arguments":"{}"}}}}}
+
+event: tool-call-end
+data: {"type":"tool-call-end","index":0}
+
+event: citation-start
+data: {"type":"citation-start","index":0,"delta":{"message":{"citations":{"start":0,"end":5,"text":"Hello","sources":[{"type":"document","id":"doc_0","document":{"url":"https://example.com"}}]}}}}
+
+event: message-end
+data: {"type":"message-end","delta":{"finish_reason":"TOOL_CALL","usage":{"tokens":{"input_tokens":3,"output_tokens":2}}}}
+
+`
+	// Split the stream in the middle of an event to check that the incomplete events are buffered.
+	split := strings.Index(stream, "tool-call-delta")
+	_, first, _, _, err := tr.ResponseBody(nil, strings.NewReader(stream[:split]), false, nil)
+	require.NoError(t, err)
+	_, second, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(stream[split:]), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, "command-a-03-2025", responseModel)
+
+	all := append(first, second...) //nolint:gocritic
+	require.True(t, bytes.HasSuffix(all, sseDoneFullLine))
+	var chunks []openai.ChatCompletionResponseChunk
+	for _, line := range bytes.Split(bytes.TrimSuffix(all, sseDoneFullLine), []byte("\n\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var chunk openai.ChatCompletionResponseChunk
+		require.NoError(t, json.Unmarshal(bytes.TrimPrefix(line, sseDataPrefix), &chunk))
+		chunks = append(chunks, chunk)
+	}
+	require.Len(t, chunks, 6)
+	for _, chunk := range chunks {
+		require.Equal(t, "resp-1", chunk.ID)
+	}
+	require.Equal(t, openai.ChatMessageRoleAssistant, chunks[0].Choices[0].Delta.Role)
+	require.Equal(t, "Hello", *chunks[1].Choices[0].Delta.Content)
+	require.Equal(t, "call_1", *chunks[2].Choices[0].Delta.ToolCalls[0].ID)
+	require.Equal(t, "get_weather", chunks[2].Choices[0].Delta.ToolCalls[0].Function.Name)
+	require.Equal(t, "{}", chunks[3].Choices[0].Delta.ToolCalls[0].Function.Arguments)
+	require.Equal(t, "https://example.com", (*chunks[4].Choices[0].Delta.Annotations)[0].URLCitation.URL)
+	require.Equal(t, openai.ChatCompletionChoicesFinishReasonToolCalls, chunks[5].Choices[0].FinishReason)
+	require.Equal(t, &openai.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5}, chunks[5].Usage)
+
+	total, _ := tokenUsage.TotalTokens()
+	require.Equal(t, uint32(5), total)
+}
+
+func TestOpenAIToCohereTranslatorV1ChatCompletion_ResponseError(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		contentType string
+		body        string
+		expMessage  string
+	}{
+		{name: "json", contentType: "application/json", body: `{"id":"e1","message":"invalid model"}`, expMessage: "invalid model"},
+		{name: "text", contentType: "text/plain", body: "upstream connect error", expMessage: "upstream connect error"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := NewChatCompletionOpenAIToCohereTranslator("v2", "")
+			headers, body, err := tr.ResponseError(map[string]string{statusHeaderName: "400", contentTypeHeaderName: tc.contentType}, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			require.Equal(t, []internalapi.Header{
+				{contentTypeHeaderName, jsonContentType},
+				{contentLengthHeaderName, strconv.Itoa(len(body))},
+			}, headers)
+			var openAIError openai.Error
+			require.NoError(t, json.Unmarshal(body, &openAIError))
+			require.Equal(t, cohereBackendError, openAIError.Error.Type)
+			require.Equal(t, tc.expMessage, openAIError.Error.Message)
+			require.Equal(t, "400", *openAIError.Error.Code)
+		})
+	}
+}
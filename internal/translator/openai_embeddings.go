@@ -6,8 +6,11 @@
 package translator
 
 import (
+	"encoding/base64"
+	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"path"
 	"strconv"
 
@@ -91,3 +94,27 @@ func (o *openAIToOpenAITranslatorV1Embedding) ResponseBody(_ map[string]string,
 func (o *openAIToOpenAITranslatorV1Embedding) ResponseError(respHeaders map[string]string, body io.Reader) ([]internalapi.Header, []byte, error) {
 	return convertErrorOpenAIToOpenAIError(respHeaders, body)
 }
+
+// isBase64EncodingFormat returns true when the request asks for the base64 encoded embeddings.
+func isBase64EncodingFormat(req *openai.EmbeddingRequest) bool {
+	return req.EncodingFormat != nil && *req.EncodingFormat == "base64"
+}
+
+// embeddingUnion returns the embedding in the float or the base64 encoding format. The backends other than OpenAI
+// return the embeddings as floats, so the base64 encoding is done by the translators.
+func embeddingUnion(embedding []float64, base64Encoding bool) openai.EmbeddingUnion {
+	if base64Encoding {
+		return openai.EmbeddingUnion{Value: encodeFloat32EmbeddingBase64(embedding)}
+	}
+	return openai.EmbeddingUnion{Value: embedding}
+}
+
+// encodeFloat32EmbeddingBase64 encodes the embedding the same way as OpenAI does for the base64 encoding format,
+// i.e. as little-endian float32 values.
+func encodeFloat32EmbeddingBase64(embedding []float64) string {
+	buf := make([]byte, 4*len(embedding))
+	for i, v := range embedding {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(float32(v)))
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
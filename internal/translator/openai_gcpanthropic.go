@@ -102,6 +102,14 @@ func (o *openAIToGCPAnthropicTranslatorV1ChatCompletion) RequestBody(_ []byte, o
 // ResponseError implements [OpenAIChatCompletionTranslator.ResponseError].
 func (o *openAIToGCPAnthropicTranslatorV1ChatCompletion) ResponseError(respHeaders map[string]string, body io.Reader) (
 	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return convertAnthropicErrorToOpenAI(respHeaders, body, gcpBackendError)
+}
+
+// convertAnthropicErrorToOpenAI translates an Anthropic error response to the OpenAI error type.
+// backendErrorType is used as the error type when the body is not JSON.
+func convertAnthropicErrorToOpenAI(respHeaders map[string]string, body io.Reader, backendErrorType string) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
 ) {
 	statusCode := respHeaders[statusHeaderName]
 	var openaiError openai.Error
@@ -109,16 +117,16 @@ func (o *openAIToGCPAnthropicTranslatorV1ChatCompletion) ResponseError(respHeade
 
 	// Check for a JSON content type to decide how to parse the error.
 	if v, ok := respHeaders[contentTypeHeaderName]; ok && strings.Contains(v, jsonContentType) {
-		var gcpError anthropic.ErrorResponse
-		if decodeErr = json.NewDecoder(body).Decode(&gcpError); decodeErr != nil {
+		var anthropicError anthropic.ErrorResponse
+		if decodeErr = json.NewDecoder(body).Decode(&anthropicError); decodeErr != nil {
 			// If we expect JSON but fail to decode, it's an internal translator error.
 			return nil, nil, fmt.Errorf("failed to unmarshal JSON error body: %w", decodeErr)
 		}
 		openaiError = openai.Error{
 			Type: "error",
 			Error: openai.ErrorType{
-				Type:    gcpError.Error.Type,
-				Message: gcpError.Error.Message,
+				Type:    anthropicError.Error.Type,
+				Message: anthropicError.Error.Message,
 				Code:    &statusCode,
 			},
 		}
@@ -132,7 +140,7 @@ func (o *openAIToGCPAnthropicTranslatorV1ChatCompletion) ResponseError(respHeade
 		openaiError = openai.Error{
 			Type: "error",
 			Error: openai.ErrorType{
-				Type:    gcpBackendError,
+				Type:    backendErrorType,
 				Message: string(buf),
 				Code:    &statusCode,
 			},
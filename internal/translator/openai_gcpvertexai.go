@@ -71,6 +71,13 @@ func NewChatCompletionOpenAIToGCPVertexAITranslator(modelNameOverride internalap
 	return &openAIToGCPVertexAITranslatorV1ChatCompletion{modelNameOverride: modelNameOverride, toolCallIndex: int64(0)}
 }
 
+// NewChatCompletionOpenAIToGoogleAIStudioTranslator implements [Factory] for OpenAI to Gemini Developer API translation.
+// The Gemini Developer API of Google AI Studio shares the request and response bodies with Vertex AI, so this
+// reuses the Vertex AI translation and only differs in the request path.
+func NewChatCompletionOpenAIToGoogleAIStudioTranslator(modelNameOverride internalapi.ModelNameOverride) OpenAIChatCompletionTranslator {
+	return &openAIToGCPVertexAITranslatorV1ChatCompletion{modelNameOverride: modelNameOverride, toolCallIndex: int64(0), googleAIStudio: true}
+}
+
 // openAIToGCPVertexAITranslatorV1ChatCompletion translates OpenAI Chat Completions API to GCP Vertex AI Gemini API.
 // Note: This uses the Gemini native API directly, not Vertex AI's OpenAI-compatible API:
 // https://cloud.google.com/vertex-ai/generative-ai/docs/model-reference/inference
@@ -82,6 +89,8 @@ type openAIToGCPVertexAITranslatorV1ChatCompletion struct {
 	bufferedBody      []byte // Buffer for incomplete JSON chunks.
 	requestModel      internalapi.RequestModel
 	toolCallIndex     int64
+	// googleAIStudio is true when the backend is the Gemini Developer API instead of Vertex AI.
+	googleAIStudio bool
 	// Redaction configuration for debug logging
 	debugLogEnabled bool
 	enableRedaction bool
@@ -104,10 +113,15 @@ func (o *openAIToGCPVertexAITranslatorV1ChatCompletion) RequestBody(_ []byte, op
 
 	// Choose the correct endpoint based on streaming.
 	var path string
-	if o.stream {
+	switch {
+	case o.googleAIStudio && o.stream:
+		path = buildGoogleAIStudioModelPath(o.requestModel, gcpMethodStreamGenerateContent, "alt=sse")
+	case o.googleAIStudio:
+		path = buildGoogleAIStudioModelPath(o.requestModel, gcpMethodGenerateContent)
+	case o.stream:
 		// For streaming requests, use the streamGenerateContent endpoint with SSE format.
 		path = buildGCPModelPathSuffix(gcpModelPublisherGoogle, o.requestModel, gcpMethodStreamGenerateContent, "alt=sse")
-	} else {
+	default:
 		path = buildGCPModelPathSuffix(gcpModelPublisherGoogle, o.requestModel, gcpMethodGenerateContent)
 	}
 	gcpReq, err := o.openAIMessageToGeminiMessage(openAIReq, o.requestModel)
@@ -284,10 +298,17 @@ func (o *openAIToGCPVertexAITranslatorV1ChatCompletion) handleStreamingResponse(
 
 // parseGCPStreamingChunks parses the buffered body to extract complete JSON chunks.
 func (o *openAIToGCPVertexAITranslatorV1ChatCompletion) parseGCPStreamingChunks(body io.Reader) ([]genai.GenerateContentResponse, error) {
+	return parseGeminiStreamingChunks(&o.bufferedBody, &o.streamDelimiter, body)
+}
+
+// parseGeminiStreamingChunks parses the buffered body and the new input to extract complete JSON chunks.
+// An incomplete trailing chunk is kept in bufferedBody for the next call, and the detected SSE delimiter
+// is stored in streamDelimiter.
+func parseGeminiStreamingChunks(bufferedBody, streamDelimiter *[]byte, body io.Reader) ([]genai.GenerateContentResponse, error) {
 	var chunks []genai.GenerateContentResponse
 
 	// Read all data from buffered body and new input into memory.
-	bodyReader := io.MultiReader(bytes.NewReader(o.bufferedBody), body)
+	bodyReader := io.MultiReader(bytes.NewReader(*bufferedBody), body)
 	allData, err := io.ReadAll(bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read streaming body: %w", err)
@@ -299,14 +320,14 @@ func (o *openAIToGCPVertexAITranslatorV1ChatCompletion) parseGCPStreamingChunks(
 	}
 
 	// Detect which SSE delimiter is being used and store it for future streaming chunks.
-	if o.streamDelimiter == nil {
-		o.streamDelimiter = detectSSEDelimiter(allData)
+	if *streamDelimiter == nil {
+		*streamDelimiter = detectSSEDelimiter(allData)
 	}
 
 	// Split by the detected delimiter.
 	var parts [][]byte
-	if o.streamDelimiter != nil {
-		parts = bytes.Split(allData, o.streamDelimiter)
+	if *streamDelimiter != nil {
+		parts = bytes.Split(allData, *streamDelimiter)
 	} else {
 		parts = [][]byte{allData}
 	}
@@ -325,10 +346,10 @@ func (o *openAIToGCPVertexAITranslatorV1ChatCompletion) parseGCPStreamingChunks(
 		var chunk genai.GenerateContentResponse
 		if err := json.Unmarshal(line, &chunk); err == nil {
 			chunks = append(chunks, chunk)
-			o.bufferedBody = nil
+			*bufferedBody = nil
 		} else {
 			// Failed to parse, buffer it for the next call.
-			o.bufferedBody = line
+			*bufferedBody = line
 		}
 		// Ignore parse errors for individual chunks to maintain stream continuity.
 	}
@@ -0,0 +1,226 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewBatchesOpenAIToGCPVertexAITranslator implements [OpenAIBatchesTranslator] for OpenAI to GCP Vertex AI batch
+// prediction translation for /v1/batches.
+func NewBatchesOpenAIToGCPVertexAITranslator(modelNameOverride internalapi.ModelNameOverride) OpenAIBatchesTranslator {
+	return &openAIToGCPVertexAITranslatorV1Batches{modelNameOverride: modelNameOverride}
+}
+
+// openAIToGCPVertexAITranslatorV1Batches translates the OpenAI Batch API to the GCP Vertex AI batch prediction jobs:
+// https://cloud.google.com/vertex-ai/generative-ai/docs/multimodal/batch-prediction-gemini
+//
+// The input of the batch must already be in Cloud Storage in the format of Vertex AI, so the input file ID is the
+// Cloud Storage URI of the input. The allowed input location and the output location of the job are taken from the
+// batch configuration of the backend.
+type openAIToGCPVertexAITranslatorV1Batches struct {
+	modelNameOverride internalapi.ModelNameOverride
+	// config is the batch configuration of the backend. Nil if the backend has none.
+	config *filterapi.BackendBatch
+	// originalModel is the original model of the request, which the batch IDs are scoped to.
+	originalModel internalapi.OriginalModel
+	// requestModel is the model of the request after the override, which is used as the model of the job.
+	requestModel internalapi.RequestModel
+	// req is the request, which is used to build the batch of the cancellation response.
+	req *openai.BatchRequest
+}
+
+// SetBatchConfig implements [BatchConfigurer.SetBatchConfig].
+func (o *openAIToGCPVertexAITranslatorV1Batches) SetBatchConfig(config *filterapi.BackendBatch) {
+	o.config = config
+}
+
+// RequestBody implements [OpenAIBatchesTranslator.RequestBody].
+func (o *openAIToGCPVertexAITranslatorV1Batches) RequestBody(_ []byte, req *openai.BatchRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.originalModel, o.requestModel, o.req = req.Model, cmp.Or(o.modelNameOverride, req.Model), req
+	switch req.Operation {
+	case openai.BatchOperationCreate:
+		var job *gcp.BatchPredictionJob
+		if job, err = o.openAIBatchToBatchPredictionJob(req); err != nil {
+			return nil, nil, err
+		}
+		if newBody, err = json.Marshal(job); err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal body: %w", err)
+		}
+		newHeaders = []internalapi.Header{
+			{pathHeaderName, "batchPredictionJobs"},
+			{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+		}
+	case openai.BatchOperationRetrieve:
+		newHeaders = []internalapi.Header{{pathHeaderName, "batchPredictionJobs/" + url.PathEscape(req.BatchID)}}
+	case openai.BatchOperationCancel:
+		newHeaders = []internalapi.Header{{pathHeaderName, "batchPredictionJobs/" + url.PathEscape(req.BatchID) + ":cancel"}}
+	}
+	return
+}
+
+// openAIBatchToBatchPredictionJob converts the OpenAI batch creation request to the GCP Vertex AI batch prediction job.
+func (o *openAIToGCPVertexAITranslatorV1Batches) openAIBatchToBatchPredictionJob(req *openai.BatchRequest) (*gcp.BatchPredictionJob, error) {
+	if err := validateStorageBatchRequest(req, o.config, "GCP Vertex AI"); err != nil {
+		return nil, err
+	}
+	model := o.requestModel
+	if !strings.Contains(model, "/") {
+		// The model is either the name of a Google model or the full resource name of a model.
+		model = "publishers/google/models/" + model
+	}
+	return &gcp.BatchPredictionJob{
+		DisplayName: "batch-" + uuid.New().String(),
+		Model:       model,
+		InputConfig: gcp.BatchPredictionJobInputConfig{
+			InstancesFormat: "jsonl",
+			GCSSource:       &gcp.GCSSource{URIs: []string{req.InputFileID}},
+		},
+		OutputConfig: gcp.BatchPredictionJobOutputConfig{
+			PredictionsFormat: "jsonl",
+			GCSDestination:    &gcp.GCSDestination{OutputURIPrefix: o.config.OutputURI},
+		},
+	}, nil
+}
+
+// ResponseHeaders implements [OpenAIBatchesTranslator.ResponseHeaders].
+func (o *openAIToGCPVertexAITranslatorV1Batches) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIBatchesTranslator.ResponseBody].
+//
+// The token usage of the batch is not supported since the batch prediction jobs do not report it, so the token usage
+// is always empty.
+func (o *openAIToGCPVertexAITranslatorV1Batches) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.BatchesSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	responseModel = o.requestModel
+	var batch *openai.Batch
+	if o.req.Operation == openai.BatchOperationCancel {
+		// The response of the cancellation is empty.
+		batch = &openai.Batch{
+			ID:     internalapi.EncodeModelScopedID(openai.BatchIDPrefix, o.originalModel, o.req.BatchID),
+			Object: "batch",
+			Model:  o.requestModel,
+			Status: openai.BatchStatusCancelling,
+		}
+	} else {
+		var job gcp.BatchPredictionJob
+		if err = json.NewDecoder(body).Decode(&job); err != nil {
+			return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to unmarshal body: %w", err)
+		}
+		batch = o.batchPredictionJobToOpenAIBatch(&job)
+		if o.req.Operation == openai.BatchOperationCreate {
+			batch.Endpoint, batch.CompletionWindow, batch.Metadata = o.req.Endpoint, o.req.CompletionWindow, o.req.Metadata
+		}
+	}
+
+	newBody, err = json.Marshal(batch)
+	if err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to marshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(batch)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// batchPredictionJobToOpenAIBatch converts the GCP Vertex AI batch prediction job to the OpenAI batch.
+// The ID of the batch is the ID of the job, i.e. the last segment of its resource name. The output file ID is the
+// Cloud Storage URI of the output directory of the job.
+func (o *openAIToGCPVertexAITranslatorV1Batches) batchPredictionJobToOpenAIBatch(job *gcp.BatchPredictionJob) *openai.Batch {
+	batch := &openai.Batch{
+		ID:     internalapi.EncodeModelScopedID(openai.BatchIDPrefix, o.originalModel, path.Base(job.Name)),
+		Object: "batch",
+		Model:  o.requestModel,
+		Status: jobStateToOpenAI(job.State),
+	}
+	if job.Model != "" {
+		batch.Model = path.Base(job.Model)
+	}
+	if source := job.InputConfig.GCSSource; source != nil && len(source.URIs) > 0 {
+		batch.InputFileID = source.URIs[0]
+	}
+	if job.CreateTime != nil {
+		batch.CreatedAt = job.CreateTime.Unix()
+	}
+	if job.StartTime != nil {
+		batch.InProgressAt = ptr.To(job.StartTime.Unix())
+	}
+	if stats := job.CompletionStats; stats != nil {
+		batch.RequestCounts = &openai.BatchRequestCounts{
+			Total:     int(stats.SuccessfulCount + stats.FailedCount + stats.IncompleteCount),
+			Completed: int(stats.SuccessfulCount),
+			Failed:    int(stats.FailedCount),
+		}
+	}
+	var endTime *int64
+	if job.EndTime != nil {
+		endTime = ptr.To(job.EndTime.Unix())
+	}
+	switch batch.Status {
+	case openai.BatchStatusCompleted:
+		if job.OutputInfo != nil {
+			batch.OutputFileID = job.OutputInfo.GCSOutputDirectory
+		}
+		batch.CompletedAt = endTime
+	case openai.BatchStatusFailed:
+		if job.Error != nil {
+			batch.Errors = &openai.BatchErrors{Object: "list", Data: []openai.BatchError{{Code: strconv.Itoa(job.Error.Code), Message: job.Error.Message}}}
+		}
+		batch.FailedAt = endTime
+	case openai.BatchStatusCancelled:
+		batch.CancelledAt = endTime
+	}
+	return batch
+}
+
+// jobStateToOpenAI converts the state of the GCP Vertex AI batch prediction job to the OpenAI batch status.
+func jobStateToOpenAI(state gcp.JobState) openai.BatchStatus {
+	switch state {
+	case gcp.JobStateRunning, gcp.JobStateUpdating, gcp.JobStatePaused:
+		return openai.BatchStatusInProgress
+	case gcp.JobStateSucceeded, gcp.JobStatePartiallySucceeded:
+		return openai.BatchStatusCompleted
+	case gcp.JobStateFailed:
+		return openai.BatchStatusFailed
+	case gcp.JobStateCancelling:
+		return openai.BatchStatusCancelling
+	case gcp.JobStateCancelled:
+		return openai.BatchStatusCancelled
+	case gcp.JobStateExpired:
+		return openai.BatchStatusExpired
+	default:
+		// Queued and pending.
+		return openai.BatchStatusValidating
+	}
+}
+
+// ResponseError implements [OpenAIBatchesTranslator.ResponseError].
+func (o *openAIToGCPVertexAITranslatorV1Batches) ResponseError(respHeaders map[string]string, body io.Reader) ([]internalapi.Header, []byte, error) {
+	return convertGCPVertexAIErrorToOpenAI(respHeaders, body)
+}
@@ -0,0 +1,257 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+var testVertexAIBatchConfig = &filterapi.BackendBatch{InputURIPrefix: "gs://bucket/", OutputURI: "gs://bucket/output"}
+
+// newTestVertexAIBatchesTranslator returns the GCP Vertex AI batch translator with the batch configuration set.
+func newTestVertexAIBatchesTranslator(modelNameOverride string, config *filterapi.BackendBatch) OpenAIBatchesTranslator {
+	translator := NewBatchesOpenAIToGCPVertexAITranslator(modelNameOverride)
+	translator.(BatchConfigurer).SetBatchConfig(config)
+	return translator
+}
+
+func TestOpenAIToGCPVertexAITranslatorV1Batches_RequestBody(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		model    string
+		override string
+		expModel string
+	}{
+		{name: "google model", model: "gemini-2.0-flash-001", expModel: "publishers/google/models/gemini-2.0-flash-001"},
+		{name: "override", model: "gemini", override: "gemini-2.0-flash-001", expModel: "publishers/google/models/gemini-2.0-flash-001"},
+		{name: "resource name", model: "publishers/anthropic/models/claude-3-haiku", expModel: "publishers/anthropic/models/claude-3-haiku"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &openai.BatchRequest{
+				InputFileID: "gs://bucket/input.jsonl",
+				Model:       tc.model,
+				Operation:   openai.BatchOperationCreate,
+			}
+			headers, body, err := newTestVertexAIBatchesTranslator(tc.override, testVertexAIBatchConfig).RequestBody(nil, req, false)
+			require.NoError(t, err)
+			require.Equal(t, []internalapi.Header{
+				{pathHeaderName, "batchPredictionJobs"},
+				{contentLengthHeaderName, strconv.Itoa(len(body))},
+			}, headers)
+
+			var job gcp.BatchPredictionJob
+			require.NoError(t, json.Unmarshal(body, &job))
+			require.True(t, strings.HasPrefix(job.DisplayName, "batch-"))
+			job.DisplayName = ""
+			require.Equal(t, gcp.BatchPredictionJob{
+				Model: tc.expModel,
+				InputConfig: gcp.BatchPredictionJobInputConfig{
+					InstancesFormat: "jsonl",
+					GCSSource:       &gcp.GCSSource{URIs: []string{"gs://bucket/input.jsonl"}},
+				},
+				OutputConfig: gcp.BatchPredictionJobOutputConfig{
+					PredictionsFormat: "jsonl",
+					GCSDestination:    &gcp.GCSDestination{OutputURIPrefix: "gs://bucket/output"},
+				},
+			}, job)
+		})
+	}
+
+	for _, tc := range []struct {
+		name     string
+		config   *filterapi.BackendBatch
+		req      *openai.BatchRequest
+		expError string
+	}{
+		{
+			name:     "no batch config",
+			req:      &openai.BatchRequest{InputFileID: "gs://bucket/input.jsonl", Operation: openai.BatchOperationCreate},
+			expError: "batches are not configured on the GCP Vertex AI backend",
+		},
+		{
+			name:     "input file not under the input prefix",
+			config:   testVertexAIBatchConfig,
+			req:      &openai.BatchRequest{InputFileID: "file-abc123", Operation: openai.BatchOperationCreate},
+			expError: "input_file_id must be the URI of the batch input under gs://bucket/",
+		},
+		{
+			name:   "output URI in metadata",
+			config: testVertexAIBatchConfig,
+			req: &openai.BatchRequest{
+				InputFileID: "gs://bucket/input.jsonl",
+				Metadata:    map[string]string{internalapi.BatchMetadataOutputURI: "gs://attacker/output"},
+				Operation:   openai.BatchOperationCreate,
+			},
+			expError: "metadata.output_uri is not accepted",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := newTestVertexAIBatchesTranslator("", tc.config).RequestBody(nil, tc.req, false)
+			require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+			require.ErrorContains(t, err, tc.expError)
+		})
+	}
+
+	t.Run("retrieve", func(t *testing.T) {
+		headers, body, err := NewBatchesOpenAIToGCPVertexAITranslator("").RequestBody(nil,
+			&openai.BatchRequest{Operation: openai.BatchOperationRetrieve, BatchID: "123"}, false)
+		require.NoError(t, err)
+		require.Nil(t, body)
+		require.Equal(t, []internalapi.Header{{pathHeaderName, "batchPredictionJobs/123"}}, headers)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		headers, _, err := NewBatchesOpenAIToGCPVertexAITranslator("").RequestBody(nil,
+			&openai.BatchRequest{Operation: openai.BatchOperationCancel, BatchID: "123"}, false)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{{pathHeaderName, "batchPredictionJobs/123:cancel"}}, headers)
+	})
+}
+
+func TestOpenAIToGCPVertexAITranslatorV1Batches_ResponseBody(t *testing.T) {
+	expID := internalapi.EncodeModelScopedID(openai.BatchIDPrefix, "gemini", "123")
+
+	t.Run("create", func(t *testing.T) {
+		req := &openai.BatchRequest{
+			InputFileID:      "gs://bucket/input.jsonl",
+			Endpoint:         "/v1/chat/completions",
+			CompletionWindow: "24h",
+			Metadata:         map[string]string{internalapi.BatchMetadataModel: "gemini"},
+			Model:            "gemini",
+			Operation:        openai.BatchOperationCreate,
+		}
+		translator := newTestVertexAIBatchesTranslator("gemini-2.0-flash-001", testVertexAIBatchConfig)
+		_, _, err := translator.RequestBody(nil, req, false)
+		require.NoError(t, err)
+
+		job := `{"name":"projects/p/locations/us-central1/batchPredictionJobs/123","displayName":"batch-1",` +
+			`"model":"publishers/google/models/gemini-2.0-flash-001","state":"JOB_STATE_PENDING",` +
+			`"inputConfig":{"instancesFormat":"jsonl","gcsSource":{"uris":["gs://bucket/input.jsonl"]}},` +
+			`"outputConfig":{"predictionsFormat":"jsonl","gcsDestination":{"outputUriPrefix":"gs://bucket/output"}},` +
+			`"createTime":"2025-01-01T00:00:00Z"}`
+		headers, body, _, responseModel, err := translator.ResponseBody(nil, strings.NewReader(job), true, nil)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(body))}}, headers)
+		require.Equal(t, "gemini-2.0-flash-001", responseModel)
+
+		var batch openai.Batch
+		require.NoError(t, json.Unmarshal(body, &batch))
+		require.Equal(t, openai.Batch{
+			ID:               expID,
+			Object:           "batch",
+			Endpoint:         "/v1/chat/completions",
+			Model:            "gemini-2.0-flash-001",
+			InputFileID:      "gs://bucket/input.jsonl",
+			CompletionWindow: "24h",
+			Status:           openai.BatchStatusValidating,
+			CreatedAt:        1735689600,
+			Metadata:         req.Metadata,
+		}, batch)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		translator := NewBatchesOpenAIToGCPVertexAITranslator("")
+		_, _, err := translator.RequestBody(nil, &openai.BatchRequest{Model: "gemini", Operation: openai.BatchOperationCancel, BatchID: "123"}, false)
+		require.NoError(t, err)
+
+		_, body, _, _, err := translator.ResponseBody(nil, strings.NewReader("{}"), true, nil)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"id":"`+expID+`","object":"batch","endpoint":"","model":"gemini","input_file_id":"",`+
+			`"completion_window":"","status":"cancelling","created_at":0}`, string(body))
+	})
+
+	for _, tc := range []struct {
+		name     string
+		job      string
+		expBatch openai.Batch
+	}{
+		{
+			name: "succeeded",
+			job: `{"name":"projects/p/locations/us-central1/batchPredictionJobs/123","model":"publishers/google/models/gemini-2.0-flash-001",` +
+				`"state":"JOB_STATE_SUCCEEDED","inputConfig":{"gcsSource":{"uris":["gs://bucket/input.jsonl"]}},` +
+				`"outputInfo":{"gcsOutputDirectory":"gs://bucket/output/prediction-123"},` +
+				`"completionStats":{"successfulCount":"8","failedCount":"2"},` +
+				`"createTime":"2025-01-01T00:00:00Z","startTime":"2025-01-01T00:10:00Z","endTime":"2025-01-01T01:00:00Z"}`,
+			expBatch: openai.Batch{
+				ID:            expID,
+				Object:        "batch",
+				Model:         "gemini-2.0-flash-001",
+				InputFileID:   "gs://bucket/input.jsonl",
+				OutputFileID:  "gs://bucket/output/prediction-123",
+				Status:        openai.BatchStatusCompleted,
+				CreatedAt:     1735689600,
+				InProgressAt:  ptr.To[int64](1735690200),
+				CompletedAt:   ptr.To[int64](1735693200),
+				RequestCounts: &openai.BatchRequestCounts{Total: 10, Completed: 8, Failed: 2},
+			},
+		},
+		{
+			name: "failed",
+			job: `{"name":"projects/p/locations/us-central1/batchPredictionJobs/123","state":"JOB_STATE_FAILED",` +
+				`"error":{"code":3,"message":"invalid input"},"endTime":"2025-01-01T01:00:00Z"}`,
+			expBatch: openai.Batch{
+				ID:       expID,
+				Object:   "batch",
+				Model:    "gemini",
+				Status:   openai.BatchStatusFailed,
+				Errors:   &openai.BatchErrors{Object: "list", Data: []openai.BatchError{{Code: "3", Message: "invalid input"}}},
+				FailedAt: ptr.To[int64](1735693200),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			translator := NewBatchesOpenAIToGCPVertexAITranslator("")
+			_, _, err := translator.RequestBody(nil, &openai.BatchRequest{Model: "gemini", Operation: openai.BatchOperationRetrieve, BatchID: "123"}, false)
+			require.NoError(t, err)
+
+			_, body, tokenUsage, _, err := translator.ResponseBody(nil, strings.NewReader(tc.job), true, nil)
+			require.NoError(t, err)
+			var batch openai.Batch
+			require.NoError(t, json.Unmarshal(body, &batch))
+			require.Equal(t, tc.expBatch, batch)
+			_, ok := tokenUsage.InputTokens()
+			require.False(t, ok)
+		})
+	}
+
+	t.Run("invalid body", func(t *testing.T) {
+		translator := NewBatchesOpenAIToGCPVertexAITranslator("")
+		_, _, err := translator.RequestBody(nil, &openai.BatchRequest{Model: "gemini", Operation: openai.BatchOperationRetrieve, BatchID: "123"}, false)
+		require.NoError(t, err)
+
+		_, _, _, _, err = translator.ResponseBody(nil, strings.NewReader("not-json"), true, nil)
+		require.ErrorContains(t, err, "failed to unmarshal body")
+	})
+}
+
+func TestJobStateToOpenAI(t *testing.T) {
+	for state, exp := range map[gcp.JobState]openai.BatchStatus{
+		gcp.JobStateQueued:             openai.BatchStatusValidating,
+		gcp.JobStatePending:            openai.BatchStatusValidating,
+		gcp.JobStateRunning:            openai.BatchStatusInProgress,
+		gcp.JobStateUpdating:           openai.BatchStatusInProgress,
+		gcp.JobStatePaused:             openai.BatchStatusInProgress,
+		gcp.JobStateSucceeded:          openai.BatchStatusCompleted,
+		gcp.JobStatePartiallySucceeded: openai.BatchStatusCompleted,
+		gcp.JobStateFailed:             openai.BatchStatusFailed,
+		gcp.JobStateCancelling:         openai.BatchStatusCancelling,
+		gcp.JobStateCancelled:          openai.BatchStatusCancelled,
+		gcp.JobStateExpired:            openai.BatchStatusExpired,
+	} {
+		require.Equal(t, exp, jobStateToOpenAI(state), state)
+	}
+}
@@ -6,9 +6,11 @@
 package translator
 
 import (
+	"encoding/base64"
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 
 	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
@@ -34,75 +36,148 @@ func NewEmbeddingOpenAIToGCPVertexAITranslator(requestModel internalapi.RequestM
 // openAIToGCPVertexAITranslatorV1Embedding translates OpenAI Embeddings API to GCP Vertex AI Gemini Embeddings API.
 // Note: This uses the Gemini native API (predict endpoint), not Vertex AI's OpenAI-compatible API:
 // https://cloud.google.com/vertex-ai/generative-ai/docs/model-reference/text-embeddings-api
+//
+// The multimodalembedding models take images as well, and use a different request and response format:
+// https://cloud.google.com/vertex-ai/generative-ai/docs/model-reference/multimodal-embeddings-api
 type openAIToGCPVertexAITranslatorV1Embedding struct {
 	requestModel      internalapi.RequestModel
 	modelNameOverride internalapi.ModelNameOverride
+	// multimodal is true when the model is one of the multimodalembedding models.
+	multimodal bool
+	// base64Encoding is true when the embeddings must be returned base64 encoded to the client.
+	base64Encoding bool
 }
 
-// createInstancesFromEmbeddingInputItem converts an EmbeddingInputItem to GCP Instance(s).
-// This handles the mapping of OpenAI's extended embedding input format to GCP's instance format,
-// including task_type and title metadata for optimized embedding generation.
-// When content is an array of strings, each string becomes a separate instance with the same task_type.
-func createInstancesFromEmbeddingInputItem(item openai.EmbeddingInputItem, instances []*gcp.Instance) []*gcp.Instance {
-	switch v := item.Content.Value.(type) {
-	case string:
-		instance := &gcp.Instance{Content: v}
-		if item.TaskType != "" {
-			instance.TaskType = item.TaskType
-		}
-		// Title is only valid with task_type=RETRIEVAL_DOCUMENT.
-		// See: https://cloud.google.com/vertex-ai/generative-ai/docs/embeddings/task-types
-		if item.TaskType == openai.EmbeddingTaskTypeRetrievalDocument && item.Title != "" {
-			instance.Title = item.Title
-		}
-		instances = append(instances, instance)
-	case []string:
-		// Multiple strings with the same task_type - each becomes a separate instance
-		for _, text := range v {
-			instance := &gcp.Instance{Content: text}
-			if item.TaskType != "" {
-				instance.TaskType = item.TaskType
-			}
-			// Title is only valid with task_type=RETRIEVAL_DOCUMENT.
-			if item.TaskType == openai.EmbeddingTaskTypeRetrievalDocument && item.Title != "" {
-				instance.Title = item.Title
+// isGCPMultimodalEmbeddingModel returns true when the model is one of the Vertex AI multimodalembedding models,
+// e.g. multimodalembedding@001.
+func isGCPMultimodalEmbeddingModel(model internalapi.RequestModel) bool {
+	return strings.HasPrefix(model, "multimodalembedding")
+}
+
+// openAIEmbeddingToMultimodalEmbedding converts an OpenAI EmbeddingRequest to a request of the multimodalembedding
+// models. Each input becomes an instance with either a text or an image, so that there is one embedding per input.
+// The images are either base64 encoded data URLs or Cloud Storage URIs.
+func openAIEmbeddingToMultimodalEmbedding(openAIReq *openai.EmbeddingRequest) (*gcp.MultimodalEmbeddingPredictRequest, error) {
+	inputs, err := embeddingInputs(openAIReq.Input)
+	if err != nil {
+		return nil, err
+	}
+	req := &gcp.MultimodalEmbeddingPredictRequest{Instances: make([]*gcp.MultimodalEmbeddingInstance, 0, len(inputs))}
+	for _, in := range inputs {
+		instance := &gcp.MultimodalEmbeddingInstance{Text: in.text}
+		switch {
+		case in.imageURL == "":
+		case in.text != "":
+			return nil, fmt.Errorf("text and image must be separate inputs for the multimodal embedding models")
+		case strings.HasPrefix(in.imageURL, "gs://"):
+			instance.Image = &gcp.MultimodalEmbeddingImage{GcsURI: in.imageURL}
+		default:
+			mimeType, data, err := parseDataURI(in.imageURL)
+			if err != nil {
+				return nil, fmt.Errorf("image must be a data URL or a Cloud Storage URI: %w", err)
 			}
-			instances = append(instances, instance)
+			instance.Image = &gcp.MultimodalEmbeddingImage{BytesBase64Encoded: base64.StdEncoding.EncodeToString(data), MimeType: mimeType}
 		}
+		req.Instances = append(req.Instances, instance)
+	}
+	if openAIReq.Dimensions != nil && *openAIReq.Dimensions > 0 {
+		req.Parameters = &gcp.MultimodalEmbeddingParameters{Dimension: *openAIReq.Dimensions}
 	}
-	return instances
+	return req, nil
 }
 
-// setInstances converts OpenAI embedding input to GCP instances.
+// embeddingInput is a single input of the embedding request, which is embedded into a single embedding.
+type embeddingInput struct {
+	text string
+	// imageURL is the URL of the image of the multimodal input, either a data URL or a URL of the provider storage.
+	imageURL string
+	taskType openai.EmbeddingTaskType
+	title    string
+}
+
+// embeddingInputs flattens the OpenAI embedding input into the inputs to embed.
 // It handles multiple input formats: string, []string, EmbeddingInputItem, []EmbeddingInputItem.
-// Each input element is converted to a separate GCP Instance for batch embedding generation.
-func setInstances(input openai.EmbeddingRequestInput, instances []*gcp.Instance) ([]*gcp.Instance, error) {
+// When the content of an EmbeddingInputItem is an array of strings, each string becomes a separate input with the
+// same task_type. When the content is an array of content parts, the text parts are joined with a newline and
+// at most one image is allowed.
+func embeddingInputs(input openai.EmbeddingRequestInput) ([]embeddingInput, error) {
 	switch v := input.Value.(type) {
 	case string:
-		instances = append(instances, &gcp.Instance{Content: v})
-		return instances, nil
+		return []embeddingInput{{text: v}}, nil
 	case []string:
-		// Array of strings: create a separate instance for each string.
+		inputs := make([]embeddingInput, 0, len(v))
 		for _, text := range v {
-			instances = append(instances, &gcp.Instance{Content: text})
+			inputs = append(inputs, embeddingInput{text: text})
 		}
-		return instances, nil
+		return inputs, nil
 	case openai.EmbeddingInputItem:
-		// Single EmbeddingInputItem with enhanced metadata.
-		// Content can be string or []string.
-		instances = createInstancesFromEmbeddingInputItem(v, instances)
-		return instances, nil
+		return appendEmbeddingInputItem(nil, v)
 	case []openai.EmbeddingInputItem:
-		// Array of EmbeddingInputItem objects with metadata support.
+		var inputs []embeddingInput
 		for _, item := range v {
-			instances = createInstancesFromEmbeddingInputItem(item, instances)
+			var err error
+			if inputs, err = appendEmbeddingInputItem(inputs, item); err != nil {
+				return nil, err
+			}
 		}
-		return instances, nil
+		return inputs, nil
 	default:
 		return nil, fmt.Errorf("unsupported input type for embedding: %T (supported: string, []string, EmbeddingInputItem, []EmbeddingInputItem)", v)
 	}
 }
 
+// appendEmbeddingInputItem appends the inputs of the EmbeddingInputItem, keeping its task_type and title.
+func appendEmbeddingInputItem(inputs []embeddingInput, item openai.EmbeddingInputItem) ([]embeddingInput, error) {
+	switch v := item.Content.Value.(type) {
+	case string:
+		inputs = append(inputs, embeddingInput{text: v, taskType: item.TaskType, title: item.Title})
+	case []string:
+		for _, text := range v {
+			inputs = append(inputs, embeddingInput{text: text, taskType: item.TaskType, title: item.Title})
+		}
+	case []openai.EmbeddingContentPart:
+		in := embeddingInput{taskType: item.TaskType, title: item.Title}
+		var texts []string
+		for _, part := range v {
+			switch part.Type {
+			case openai.EmbeddingContentPartTypeText:
+				texts = append(texts, part.Text)
+			case openai.EmbeddingContentPartTypeImageURL:
+				if in.imageURL != "" {
+					return nil, fmt.Errorf("only one image is supported per embedding input")
+				}
+				in.imageURL = part.ImageURL.URL
+			}
+		}
+		in.text = strings.Join(texts, "\n")
+		inputs = append(inputs, in)
+	}
+	return inputs, nil
+}
+
+// setInstances converts OpenAI embedding input to GCP instances of the text embedding models.
+// Each input element is converted to a separate GCP Instance for batch embedding generation, including task_type
+// and title metadata for optimized embedding generation.
+func setInstances(input openai.EmbeddingRequestInput, instances []*gcp.Instance) ([]*gcp.Instance, error) {
+	inputs, err := embeddingInputs(input)
+	if err != nil {
+		return nil, err
+	}
+	for _, in := range inputs {
+		if in.imageURL != "" {
+			return nil, fmt.Errorf("image inputs are only supported by the multimodal embedding models")
+		}
+		instance := &gcp.Instance{Content: in.text, TaskType: in.taskType}
+		// Title is only valid with task_type=RETRIEVAL_DOCUMENT.
+		// See: https://cloud.google.com/vertex-ai/generative-ai/docs/embeddings/task-types
+		if in.taskType == openai.EmbeddingTaskTypeRetrievalDocument {
+			instance.Title = in.title
+		}
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
 // openAIEmbeddingToGeminiMessage converts an OpenAI EmbeddingRequest to a GCP PredictRequest.
 func openAIEmbeddingToGeminiMessage(openAIReq *openai.EmbeddingRequest) (*gcp.PredictRequest, error) {
 	// Convert OpenAI EmbeddingRequest's input to Gemini instances.
@@ -160,11 +235,17 @@ func (o *openAIToGCPVertexAITranslatorV1Embedding) RequestBody(_ []byte, req *op
 	// https://docs.cloud.google.com/vertex-ai/generative-ai/docs/model-reference/text-embeddings-api#curl
 	path := buildGCPModelPathSuffix(gcpModelPublisherGoogle, o.requestModel, gcpMethodPredict)
 
-	var gcpReq *gcp.PredictRequest
+	o.base64Encoding = isBase64EncodingFormat(req)
+	o.multimodal = isGCPMultimodalEmbeddingModel(o.requestModel)
 
-	gcpReq, err = openAIEmbeddingToGeminiMessage(req)
+	var gcpReq any
+	if o.multimodal {
+		gcpReq, err = openAIEmbeddingToMultimodalEmbedding(req)
+	} else {
+		gcpReq, err = openAIEmbeddingToGeminiMessage(req)
+	}
 	if err != nil {
-		return nil, nil, fmt.Errorf("error converting EmbeddingRequest: %w", err)
+		return nil, nil, fmt.Errorf("%w: error converting EmbeddingRequest: %w", internalapi.ErrInvalidRequestBody, err)
 	}
 
 	newBody, err = json.Marshal(gcpReq)
@@ -196,6 +277,9 @@ func (o *openAIToGCPVertexAITranslatorV1Embedding) ResponseBody(_ map[string]str
 	if err != nil {
 		return nil, nil, tokenUsage, "", fmt.Errorf("failed to read gemini embedding response body: %w", err)
 	}
+	if o.multimodal {
+		return o.multimodalResponseBody(respBody, span)
+	}
 
 	// Unmarshal as GCP PredictResponse.
 	var gcpResp gcp.PredictResponse
@@ -229,7 +313,7 @@ func (o *openAIToGCPVertexAITranslatorV1Embedding) ResponseBody(_ map[string]str
 				openaiResp.Data[i] = openai.Embedding{
 					Object:    "embedding",
 					Index:     i,
-					Embedding: openai.EmbeddingUnion{Value: float64Values},
+					Embedding: embeddingUnion(float64Values, o.base64Encoding),
 				}
 
 				// Extract token count from statistics if available.
@@ -273,6 +357,47 @@ func (o *openAIToGCPVertexAITranslatorV1Embedding) ResponseBody(_ map[string]str
 	return
 }
 
+// multimodalResponseBody converts the response of the multimodalembedding models. Each prediction has either the
+// text or the image embedding of the corresponding instance. The response carries no token usage.
+func (o *openAIToGCPVertexAITranslatorV1Embedding) multimodalResponseBody(respBody []byte, span tracing.EmbeddingsSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	var gcpResp gcp.MultimodalEmbeddingPredictResponse
+	if err = json.Unmarshal(respBody, &gcpResp); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	openaiResp := openai.EmbeddingResponse{
+		Object: "list",
+		Model:  o.requestModel,
+		Data:   make([]openai.Embedding, 0, len(gcpResp.Predictions)),
+	}
+	for i, prediction := range gcpResp.Predictions {
+		if prediction == nil {
+			continue
+		}
+		embedding := prediction.TextEmbedding
+		if embedding == nil {
+			embedding = prediction.ImageEmbedding
+		}
+		openaiResp.Data = append(openaiResp.Data, openai.Embedding{
+			Object:    "embedding",
+			Index:     i,
+			Embedding: embeddingUnion(embedding, o.base64Encoding),
+		})
+	}
+
+	newBody, err = json.Marshal(openaiResp)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal OpenAI response: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(&openaiResp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return newHeaders, newBody, tokenUsage, openaiResp.Model, nil
+}
+
 // ResponseError implements [OpenAIEmbeddingTranslator.ResponseError].
 // Translate GCP Vertex AI exceptions to OpenAI error type.
 // GCP error responses typically contain JSON with error details or plain text error messages.
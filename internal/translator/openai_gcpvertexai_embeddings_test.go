@@ -213,6 +213,50 @@ func TestOpenAIToGCPVertexAITranslatorV1Embedding_RequestBody(t *testing.T) {
 				`"parameters"`,
 			},
 		},
+		{
+			name: "multimodal model with text and image inputs",
+			input: openai.EmbeddingRequest{
+				Model: "multimodalembedding@001",
+				Input: openai.EmbeddingRequestInput{Value: []openai.EmbeddingInputItem{
+					{Content: openai.EmbeddingContent{Value: "a cat"}},
+					{Content: openai.EmbeddingContent{Value: []openai.EmbeddingContentPart{
+						{Type: openai.EmbeddingContentPartTypeImageURL, ImageURL: &openai.ChatCompletionContentPartImageImageURLParam{URL: "data:image/png;base64,AAEC"}},
+					}}},
+					{Content: openai.EmbeddingContent{Value: []openai.EmbeddingContentPart{
+						{Type: openai.EmbeddingContentPartTypeImageURL, ImageURL: &openai.ChatCompletionContentPartImageImageURLParam{URL: "gs://bucket/cat.png"}},
+					}}},
+				}},
+				Dimensions: &[]int{512}[0],
+			},
+			wantPath: "publishers/google/models/multimodalembedding@001:predict",
+			wantBodyContains: []string{
+				`{"text":"a cat"}`,
+				`{"image":{"bytesBase64Encoded":"AAEC","mimeType":"image/png"}}`,
+				`{"image":{"gcsUri":"gs://bucket/cat.png"}}`,
+				`"parameters":{"dimension":512}`,
+			},
+		},
+		{
+			name: "multimodal model rejects text and image in one input",
+			input: openai.EmbeddingRequest{
+				Model: "multimodalembedding@001",
+				Input: openai.EmbeddingRequestInput{Value: openai.EmbeddingInputItem{Content: openai.EmbeddingContent{Value: []openai.EmbeddingContentPart{
+					{Type: openai.EmbeddingContentPartTypeText, Text: "a cat"},
+					{Type: openai.EmbeddingContentPartTypeImageURL, ImageURL: &openai.ChatCompletionContentPartImageImageURLParam{URL: "gs://bucket/cat.png"}},
+				}}}},
+			},
+			wantError: true,
+		},
+		{
+			name: "text model rejects image input",
+			input: openai.EmbeddingRequest{
+				Model: "text-embedding-004",
+				Input: openai.EmbeddingRequestInput{Value: openai.EmbeddingInputItem{Content: openai.EmbeddingContent{Value: []openai.EmbeddingContentPart{
+					{Type: openai.EmbeddingContentPartTypeImageURL, ImageURL: &openai.ChatCompletionContentPartImageImageURLParam{URL: "gs://bucket/cat.png"}},
+				}}}},
+			},
+			wantError: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -676,3 +720,40 @@ func TestResponseModel_GCPVertexAIEmbeddings(t *testing.T) {
 	_, ok = tokenUsage.OutputTokens()
 	require.False(t, ok) // Output tokens not available for embeddings
 }
+
+func TestOpenAIToGCPVertexAITranslatorV1Embedding_MultimodalResponseBody(t *testing.T) {
+	translator := NewEmbeddingOpenAIToGCPVertexAITranslator("multimodalembedding@001", "")
+	req := &openai.EmbeddingRequest{
+		Model: "multimodalembedding@001",
+		Input: openai.EmbeddingRequestInput{Value: []string{"a", "b"}},
+	}
+	_, _, err := translator.RequestBody(nil, req, false)
+	require.NoError(t, err)
+
+	_, body, tokenUsage, responseModel, err := translator.ResponseBody(nil,
+		strings.NewReader(`{"predictions":[{"textEmbedding":[0.5,-1]},{"imageEmbedding":[0.25]}]}`), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, "multimodalembedding@001", responseModel)
+	require.JSONEq(t, `{"object":"list","model":"multimodalembedding@001","usage":{"prompt_tokens":0,"total_tokens":0},"data":[
+{"object":"embedding","index":0,"embedding":[0.5,-1]},
+{"object":"embedding","index":1,"embedding":[0.25]}]}`, string(body))
+	_, ok := tokenUsage.InputTokens()
+	require.False(t, ok)
+}
+
+func TestOpenAIToGCPVertexAITranslatorV1Embedding_Base64EncodingFormat(t *testing.T) {
+	translator := NewEmbeddingOpenAIToGCPVertexAITranslator("text-embedding-004", "")
+	encodingFormat := "base64"
+	req := &openai.EmbeddingRequest{
+		Model:          "text-embedding-004",
+		Input:          openai.EmbeddingRequestInput{Value: "a"},
+		EncodingFormat: &encodingFormat,
+	}
+	_, _, err := translator.RequestBody(nil, req, false)
+	require.NoError(t, err)
+
+	_, body, _, _, err := translator.ResponseBody(nil,
+		strings.NewReader(`{"predictions":[{"embeddings":{"values":[1.0,-2.5],"statistics":{"token_count":1}}}]}`), true, nil)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `"embedding":"AACAPwAAIMA="`)
+}
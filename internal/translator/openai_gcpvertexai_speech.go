@@ -0,0 +1,308 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"strconv"
+
+	"google.golang.org/genai"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// geminiSpeechSampleRate is the sample rate of the 16-bit mono PCM audio generated by the Gemini TTS models,
+// used when the MIME type of the audio does not carry the rate.
+const geminiSpeechSampleRate = 24000
+
+// geminiSpeechVoices maps the OpenAI voices to the prebuilt voices of the Gemini TTS models with a similar tone.
+// Other voices are passed through as is, so that the Gemini voices can be requested directly.
+// https://ai.google.dev/gemini-api/docs/speech-generation#voices
+var geminiSpeechVoices = map[string]string{
+	openai.SpeechVoiceAlloy:   "Zephyr",
+	openai.SpeechVoiceAsh:     "Charon",
+	openai.SpeechVoiceBallad:  "Enceladus",
+	openai.SpeechVoiceCoral:   "Aoede",
+	openai.SpeechVoiceEcho:    "Puck",
+	openai.SpeechVoiceFable:   "Fenrir",
+	openai.SpeechVoiceOnyx:    "Orus",
+	openai.SpeechVoiceNova:    "Leda",
+	openai.SpeechVoiceSage:    "Sulafat",
+	openai.SpeechVoiceShimmer: "Despina",
+	openai.SpeechVoiceVerse:   "Iapetus",
+	openai.SpeechVoiceMarin:   "Achernar",
+	openai.SpeechVoiceCedar:   "Algenib",
+}
+
+// NewSpeechOpenAIToGCPVertexAITranslator implements [OpenAISpeechTranslator] for OpenAI to GCP Vertex AI
+// translation for /v1/audio/speech.
+func NewSpeechOpenAIToGCPVertexAITranslator(modelNameOverride internalapi.ModelNameOverride) OpenAISpeechTranslator {
+	return &openAIToGCPVertexAITranslatorV1Speech{modelNameOverride: modelNameOverride}
+}
+
+// NewSpeechOpenAIToGoogleAIStudioTranslator implements [OpenAISpeechTranslator] for OpenAI to Gemini Developer
+// API translation for /v1/audio/speech.
+func NewSpeechOpenAIToGoogleAIStudioTranslator(modelNameOverride internalapi.ModelNameOverride) OpenAISpeechTranslator {
+	return &openAIToGCPVertexAITranslatorV1Speech{modelNameOverride: modelNameOverride, googleAIStudio: true}
+}
+
+// openAIToGCPVertexAITranslatorV1Speech translates the OpenAI Speech API to the generateContent method of the
+// Gemini TTS models with the AUDIO response modality:
+// https://ai.google.dev/gemini-api/docs/speech-generation
+//
+// Gemini only generates 24kHz 16-bit mono PCM audio, which is the same as the pcm format of OpenAI, so only the
+// pcm and wav formats are supported. The SSE stream format is translated from streamGenerateContent.
+type openAIToGCPVertexAITranslatorV1Speech struct {
+	modelNameOverride internalapi.ModelNameOverride
+	requestModel      internalapi.RequestModel
+	// googleAIStudio is true when the backend is the Gemini Developer API instead of Vertex AI.
+	googleAIStudio bool
+	// responseFormat is either wav or pcm.
+	responseFormat string
+	stream         bool
+	// streamDelimiter and bufferedBody are used to parse the streamGenerateContent chunks.
+	streamDelimiter []byte
+	bufferedBody    []byte
+	// wavHeaderSent is true once the WAV header has been sent in the SSE stream.
+	wavHeaderSent bool
+	// streamingUsage and streamingResponseModel are taken from the latest chunks that carry them.
+	streamingUsage         *genai.GenerateContentResponseUsageMetadata
+	streamingResponseModel internalapi.ResponseModel
+}
+
+// RequestBody implements [OpenAISpeechTranslator.RequestBody].
+func (o *openAIToGCPVertexAITranslatorV1Speech) RequestBody(_ []byte, req *openai.SpeechRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.requestModel = cmp.Or(o.modelNameOverride, req.Model)
+	o.stream = req.StreamFormat != nil && *req.StreamFormat == openai.StreamFormatSSE
+
+	// The default format of OpenAI is mp3, which Gemini cannot generate, so wav is the default instead.
+	o.responseFormat = openai.AudioFormatWAV
+	if req.ResponseFormat != nil {
+		switch *req.ResponseFormat {
+		case openai.AudioFormatWAV, openai.AudioFormatPCM:
+			o.responseFormat = *req.ResponseFormat
+		default:
+			return nil, nil, fmt.Errorf("%w: unsupported response_format %q for Gemini TTS, only wav and pcm are supported",
+				internalapi.ErrInvalidRequestBody, *req.ResponseFormat)
+		}
+	}
+	if req.Speed != nil && *req.Speed != 1 {
+		return nil, nil, fmt.Errorf("%w: speed is not supported for Gemini TTS, use instructions instead", internalapi.ErrInvalidRequestBody)
+	}
+
+	// Gemini TTS is steered with the natural language in the prompt, so the instructions precede the input.
+	text := req.Input
+	if req.Instructions != nil && *req.Instructions != "" {
+		text = *req.Instructions + "\n\n" + req.Input
+	}
+	gcpReq := gcp.GenerateContentRequest{
+		Contents: []genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{{Text: text}}}},
+		GenerationConfig: &genai.GenerationConfig{
+			ResponseModalities: []genai.Modality{genai.ModalityAudio},
+			SpeechConfig: &genai.SpeechConfig{
+				VoiceConfig: &genai.VoiceConfig{
+					PrebuiltVoiceConfig: &genai.PrebuiltVoiceConfig{VoiceName: cmp.Or(geminiSpeechVoices[req.Voice], req.Voice)},
+				},
+			},
+		},
+	}
+	newBody, err = json.Marshal(gcpReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal Gemini TTS request: %w", err)
+	}
+
+	var path string
+	switch {
+	case o.googleAIStudio && o.stream:
+		path = buildGoogleAIStudioModelPath(o.requestModel, gcpMethodStreamGenerateContent, "alt=sse")
+	case o.googleAIStudio:
+		path = buildGoogleAIStudioModelPath(o.requestModel, gcpMethodGenerateContent)
+	case o.stream:
+		path = buildGCPModelPathSuffix(gcpModelPublisherGoogle, o.requestModel, gcpMethodStreamGenerateContent, "alt=sse")
+	default:
+		path = buildGCPModelPathSuffix(gcpModelPublisherGoogle, o.requestModel, gcpMethodGenerateContent)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, path},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// ResponseHeaders implements [OpenAISpeechTranslator.ResponseHeaders].
+func (o *openAIToGCPVertexAITranslatorV1Speech) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	if o.stream {
+		return []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}, nil
+	}
+	return []internalapi.Header{{contentTypeHeaderName, "audio/" + o.responseFormat}}, nil
+}
+
+// ResponseBody implements [OpenAISpeechTranslator.ResponseBody].
+// The token usage is read from the usageMetadata, where the output tokens are the audio tokens.
+func (o *openAIToGCPVertexAITranslatorV1Speech) ResponseBody(_ map[string]string, body io.Reader, endOfStream bool, span tracingapi.SpeechSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	if o.stream {
+		return o.handleStreamingResponse(body, endOfStream, span)
+	}
+
+	resp := &genai.GenerateContentResponse{}
+	if err = json.NewDecoder(body).Decode(resp); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	pcm, sampleRate := geminiSpeechAudio(resp)
+	if len(pcm) == 0 {
+		return nil, nil, tokenUsage, "", fmt.Errorf("no audio in the Gemini TTS response")
+	}
+	if o.responseFormat == openai.AudioFormatWAV {
+		newBody = append(wavHeader(uint32(len(pcm)), sampleRate), pcm...) //nolint:gosec
+	} else {
+		newBody = pcm
+	}
+	if resp.UsageMetadata != nil {
+		tokenUsage = geminiUsageToTokenUsage(resp.UsageMetadata)
+	}
+	if span != nil {
+		span.RecordResponse(&newBody)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return newHeaders, newBody, tokenUsage, cmp.Or(resp.ModelVersion, o.requestModel), nil
+}
+
+// handleStreamingResponse converts the streamGenerateContent chunks to the speech.audio.delta events, followed by
+// the speech.audio.done event with the usage at the end of the stream.
+func (o *openAIToGCPVertexAITranslatorV1Speech) handleStreamingResponse(body io.Reader, endOfStream bool, span tracingapi.SpeechSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	chunks, err := parseGeminiStreamingChunks(&o.bufferedBody, &o.streamDelimiter, body)
+	if err != nil {
+		return nil, nil, tokenUsage, "", err
+	}
+
+	var buf bytes.Buffer
+	for i := range chunks {
+		chunk := &chunks[i]
+		if chunk.ModelVersion != "" {
+			o.streamingResponseModel = chunk.ModelVersion
+		}
+		// Each chunk carries the cumulative usage, so the last one wins.
+		if chunk.UsageMetadata != nil {
+			o.streamingUsage = chunk.UsageMetadata
+		}
+		pcm, sampleRate := geminiSpeechAudio(chunk)
+		if len(pcm) == 0 {
+			continue
+		}
+		if o.responseFormat == openai.AudioFormatWAV && !o.wavHeaderSent {
+			// The length is unknown when streaming, so the header has the maximum sizes as other encoders do.
+			pcm = append(wavHeader(math.MaxUint32-36, sampleRate), pcm...)
+			o.wavHeaderSent = true
+		}
+		if err = o.writeEvent(&buf, &openai.SpeechStreamChunk{
+			Type:  openai.SpeechStreamEventTypeAudioDelta,
+			Audio: base64.StdEncoding.EncodeToString(pcm),
+		}, span); err != nil {
+			return nil, nil, tokenUsage, "", err
+		}
+	}
+
+	if endOfStream {
+		done := &openai.SpeechStreamChunk{Type: openai.SpeechStreamEventTypeAudioDone, Usage: &openai.SpeechUsage{}}
+		if u := o.streamingUsage; u != nil {
+			done.Usage.InputTokens = int(u.PromptTokenCount)
+			done.Usage.OutputTokens = int(u.CandidatesTokenCount)
+			done.Usage.TotalTokens = int(u.TotalTokenCount)
+		}
+		if err = o.writeEvent(&buf, done, span); err != nil {
+			return nil, nil, tokenUsage, "", err
+		}
+	}
+	if o.streamingUsage != nil {
+		tokenUsage = geminiUsageToTokenUsage(o.streamingUsage)
+	}
+	return nil, buf.Bytes(), tokenUsage, cmp.Or(o.streamingResponseModel, o.requestModel), nil
+}
+
+// writeEvent writes the SSE event of the chunk to the buffer and records it to the span.
+func (o *openAIToGCPVertexAITranslatorV1Speech) writeEvent(buf *bytes.Buffer, chunk *openai.SpeechStreamChunk, span tracingapi.SpeechSpan) error {
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return fmt.Errorf("failed to marshal speech stream chunk: %w", err)
+	}
+	buf.Write(sseDataPrefix)
+	buf.Write(data)
+	buf.WriteString("\n\n")
+	if span != nil {
+		span.RecordResponseChunk(chunk)
+	}
+	return nil
+}
+
+// ResponseError implements [OpenAISpeechTranslator.ResponseError].
+func (o *openAIToGCPVertexAITranslatorV1Speech) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return convertGCPVertexAIErrorToOpenAI(respHeaders, body)
+}
+
+// geminiSpeechAudio returns the PCM audio of the first candidate and its sample rate.
+func geminiSpeechAudio(resp *genai.GenerateContentResponse) (pcm []byte, sampleRate uint32) {
+	sampleRate = geminiSpeechSampleRate
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+		return nil, sampleRate
+	}
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if part == nil || part.InlineData == nil {
+			continue
+		}
+		// The MIME type is in the form of audio/L16;codec=pcm;rate=24000.
+		if _, params, err := mime.ParseMediaType(part.InlineData.MIMEType); err == nil {
+			if rate, err := strconv.ParseUint(params["rate"], 10, 32); err == nil {
+				sampleRate = uint32(rate)
+			}
+		}
+		pcm = append(pcm, part.InlineData.Data...)
+	}
+	return pcm, sampleRate
+}
+
+// wavHeader returns the 44-byte header of the WAV file for 16-bit mono PCM audio of the given size.
+// http://soundfile.sapp.org/doc/WaveFormat/
+func wavHeader(dataSize, sampleRate uint32) []byte {
+	const (
+		channels      = 1
+		bitsPerSample = 16
+		blockAlign    = channels * bitsPerSample / 8
+	)
+	header := make([]byte, 0, 44)
+	header = append(header, "RIFF"...)
+	header = binary.LittleEndian.AppendUint32(header, 36+dataSize)
+	header = append(header, "WAVEfmt "...)
+	header = binary.LittleEndian.AppendUint32(header, 16) // Size of the fmt chunk.
+	header = binary.LittleEndian.AppendUint16(header, 1)  // PCM.
+	header = binary.LittleEndian.AppendUint16(header, channels)
+	header = binary.LittleEndian.AppendUint32(header, sampleRate)
+	header = binary.LittleEndian.AppendUint32(header, sampleRate*blockAlign)
+	header = binary.LittleEndian.AppendUint16(header, blockAlign)
+	header = binary.LittleEndian.AppendUint16(header, bitsPerSample)
+	header = append(header, "data"...)
+	header = binary.LittleEndian.AppendUint32(header, dataSize)
+	return header
+}
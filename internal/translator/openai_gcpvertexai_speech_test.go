@@ -0,0 +1,186 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+const geminiSpeechResponse = `{
+  "candidates": [{"content": {"role": "model", "parts": [{"inlineData": {"mimeType": "audio/L16;codec=pcm;rate=24000", "data": "AAEC"}}]}}],
+  "usageMetadata": {"promptTokenCount": 5, "candidatesTokenCount": 40, "totalTokenCount": 45},
+  "modelVersion": "gemini-2.5-flash-preview-tts"
+}`
+
+func TestOpenAIToGCPVertexAISpeechTranslator_RequestBody(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		translator OpenAISpeechTranslator
+		req        *openai.SpeechRequest
+		expPath    string
+		expBody    string
+	}{
+		{
+			name:       "vertex ai",
+			translator: NewSpeechOpenAIToGCPVertexAITranslator(""),
+			req:        &openai.SpeechRequest{Model: "gemini-2.5-flash-preview-tts", Input: "Hello", Voice: "alloy"},
+			expPath:    "publishers/google/models/gemini-2.5-flash-preview-tts:generateContent",
+			expBody: `{"contents":[{"role":"user","parts":[{"text":"Hello"}]}],"tools":null,"generation_config":{"responseModalities":["AUDIO"],
+"speechConfig":{"voiceConfig":{"prebuiltVoiceConfig":{"voiceName":"Zephyr"}}}}}`,
+		},
+		{
+			name:       "vertex ai streaming with instructions and override",
+			translator: NewSpeechOpenAIToGCPVertexAITranslator("gemini-2.5-pro-preview-tts"),
+			req: &openai.SpeechRequest{
+				Model: "tts-1", Input: "Hello", Voice: "Kore", Instructions: ptr.To("Say cheerfully"),
+				StreamFormat: ptr.To(openai.StreamFormatSSE), ResponseFormat: ptr.To(openai.AudioFormatPCM), Speed: ptr.To(1.0),
+			},
+			expPath: "publishers/google/models/gemini-2.5-pro-preview-tts:streamGenerateContent?alt=sse",
+			expBody: `{"contents":[{"role":"user","parts":[{"text":"Say cheerfully\n\nHello"}]}],"tools":null,"generation_config":{"responseModalities":["AUDIO"],
+"speechConfig":{"voiceConfig":{"prebuiltVoiceConfig":{"voiceName":"Kore"}}}}}`,
+		},
+		{
+			name:       "google ai studio",
+			translator: NewSpeechOpenAIToGoogleAIStudioTranslator(""),
+			req:        &openai.SpeechRequest{Model: "gemini-2.5-flash-preview-tts", Input: "Hello", Voice: "onyx", ResponseFormat: ptr.To(openai.AudioFormatWAV)},
+			expPath:    "/v1beta/models/gemini-2.5-flash-preview-tts:generateContent",
+			expBody: `{"contents":[{"role":"user","parts":[{"text":"Hello"}]}],"tools":null,"generation_config":{"responseModalities":["AUDIO"],
+"speechConfig":{"voiceConfig":{"prebuiltVoiceConfig":{"voiceName":"Orus"}}}}}`,
+		},
+		{
+			name:       "google ai studio streaming",
+			translator: NewSpeechOpenAIToGoogleAIStudioTranslator(""),
+			req:        &openai.SpeechRequest{Model: "gemini-2.5-flash-preview-tts", Input: "Hello", Voice: "nova", StreamFormat: ptr.To(openai.StreamFormatSSE)},
+			expPath:    "/v1beta/models/gemini-2.5-flash-preview-tts:streamGenerateContent?alt=sse",
+			expBody: `{"contents":[{"role":"user","parts":[{"text":"Hello"}]}],"tools":null,"generation_config":{"responseModalities":["AUDIO"],
+"speechConfig":{"voiceConfig":{"prebuiltVoiceConfig":{"voiceName":"Leda"}}}}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			headers, body, err := tc.translator.RequestBody(nil, tc.req, false)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expBody, string(body))
+			require.Equal(t, []internalapi.Header{
+				{pathHeaderName, tc.expPath},
+				{contentLengthHeaderName, strconv.Itoa(len(body))},
+			}, headers)
+		})
+	}
+
+	t.Run("unsupported format", func(t *testing.T) {
+		tr := NewSpeechOpenAIToGCPVertexAITranslator("")
+		_, _, err := tr.RequestBody(nil, &openai.SpeechRequest{Model: "m", Input: "Hello", ResponseFormat: ptr.To(openai.AudioFormatMP3)}, false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+		require.ErrorContains(t, err, `unsupported response_format "mp3"`)
+	})
+
+	t.Run("unsupported speed", func(t *testing.T) {
+		tr := NewSpeechOpenAIToGCPVertexAITranslator("")
+		_, _, err := tr.RequestBody(nil, &openai.SpeechRequest{Model: "m", Input: "Hello", Speed: ptr.To(1.5)}, false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+	})
+}
+
+func TestOpenAIToGCPVertexAISpeechTranslator_ResponseBody(t *testing.T) {
+	pcm := []byte{0x00, 0x01, 0x02}
+
+	t.Run("wav", func(t *testing.T) {
+		tr := NewSpeechOpenAIToGCPVertexAITranslator("")
+		_, _, err := tr.RequestBody(nil, &openai.SpeechRequest{Model: "gemini-2.5-flash-preview-tts", Input: "Hello"}, false)
+		require.NoError(t, err)
+
+		headers, err := tr.ResponseHeaders(nil)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{{contentTypeHeaderName, "audio/wav"}}, headers)
+
+		mockSpan := &mockSpeechSpan{}
+		headers, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(geminiSpeechResponse), true, mockSpan)
+		require.NoError(t, err)
+		require.Len(t, body, 44+len(pcm))
+		require.Equal(t, "RIFF", string(body[0:4]))
+		require.Equal(t, "WAVE", string(body[8:12]))
+		require.Equal(t, uint32(24000), binary.LittleEndian.Uint32(body[24:28]))
+		require.Equal(t, uint32(len(pcm)), binary.LittleEndian.Uint32(body[40:44]))
+		require.Equal(t, pcm, body[44:])
+		require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(body))}}, headers)
+		require.Equal(t, tokenUsageFrom(5, 0, -1, 40, 45, 0), tokenUsage)
+		require.Equal(t, "gemini-2.5-flash-preview-tts", responseModel)
+		require.Equal(t, body, *mockSpan.recordedResponse)
+	})
+
+	t.Run("pcm", func(t *testing.T) {
+		tr := NewSpeechOpenAIToGoogleAIStudioTranslator("")
+		_, _, err := tr.RequestBody(nil, &openai.SpeechRequest{Model: "gemini-2.5-flash-preview-tts", Input: "Hello", ResponseFormat: ptr.To(openai.AudioFormatPCM)}, false)
+		require.NoError(t, err)
+
+		headers, err := tr.ResponseHeaders(nil)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{{contentTypeHeaderName, "audio/pcm"}}, headers)
+
+		_, body, _, _, err := tr.ResponseBody(nil, strings.NewReader(geminiSpeechResponse), true, nil)
+		require.NoError(t, err)
+		require.Equal(t, pcm, body)
+	})
+
+	t.Run("no audio", func(t *testing.T) {
+		tr := NewSpeechOpenAIToGCPVertexAITranslator("")
+		_, _, err := tr.RequestBody(nil, &openai.SpeechRequest{Model: "gemini-2.5-flash-preview-tts", Input: "Hello"}, false)
+		require.NoError(t, err)
+		_, _, _, _, err = tr.ResponseBody(nil, strings.NewReader(`{"candidates":[{"finishReason":"OTHER"}]}`), true, nil)
+		require.ErrorContains(t, err, "no audio in the Gemini TTS response")
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		tr := NewSpeechOpenAIToGCPVertexAITranslator("")
+		_, _, err := tr.RequestBody(nil, &openai.SpeechRequest{
+			Model: "gemini-2.5-flash-preview-tts", Input: "Hello", StreamFormat: ptr.To(openai.StreamFormatSSE),
+		}, false)
+		require.NoError(t, err)
+
+		headers, err := tr.ResponseHeaders(nil)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}, headers)
+
+		mockSpan := &mockSpeechSpan{}
+		chunk := `data: {"candidates":[{"content":{"role":"model","parts":[{"inlineData":{"mimeType":"audio/L16;codec=pcm;rate=24000","data":"AAEC"}}]}}]}` + "\n\n"
+		_, body, tokenUsage, _, err := tr.ResponseBody(nil, strings.NewReader(chunk+chunk), false, mockSpan)
+		require.NoError(t, err)
+		require.Equal(t, tokenUsageFrom(-1, -1, -1, -1, -1, -1), tokenUsage)
+		events := strings.Split(strings.TrimSuffix(string(body), "\n\n"), "\n\n")
+		require.Len(t, events, 2)
+		// Only the first delta carries the WAV header.
+		expHeader := wavHeader(0xFFFFFFFF-36, 24000)
+		require.Equal(t, `data: {"type":"speech.audio.delta","audio":"`+base64.StdEncoding.EncodeToString(append(expHeader, pcm...))+`"}`, events[0])
+		require.Equal(t, `data: {"type":"speech.audio.delta","audio":"AAEC"}`, events[1])
+
+		last := `data: {"usageMetadata":{"promptTokenCount":5,"candidatesTokenCount":40,"totalTokenCount":45},"modelVersion":"gemini-2.5-flash-preview-tts"}` + "\n\n"
+		_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(last), true, mockSpan)
+		require.NoError(t, err)
+		require.Equal(t, `data: {"type":"speech.audio.done","usage":{"input_tokens":5,"output_tokens":40,"total_tokens":45}}`+"\n\n", string(body))
+		require.Equal(t, tokenUsageFrom(5, 0, -1, 40, 45, 0), tokenUsage)
+		require.Equal(t, "gemini-2.5-flash-preview-tts", responseModel)
+		require.Len(t, mockSpan.recordedChunks, 3)
+	})
+}
+
+func TestOpenAIToGCPVertexAISpeechTranslator_ResponseError(t *testing.T) {
+	tr := NewSpeechOpenAIToGCPVertexAITranslator("")
+	headers, body, err := tr.ResponseError(map[string]string{statusHeaderName: "400", contentTypeHeaderName: jsonContentType},
+		strings.NewReader(`{"error":{"code":400,"message":"invalid voice","status":"INVALID_ARGUMENT"}}`))
+	require.NoError(t, err)
+	require.NotNil(t, headers)
+	require.Contains(t, string(body), "invalid voice")
+}
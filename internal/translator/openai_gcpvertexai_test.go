@@ -8,6 +8,7 @@ package translator
 import (
 	"bytes"
 	"slices"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -812,6 +813,51 @@ func TestOpenAIToGCPVertexAITranslatorV1ChatCompletion_RequestBody(t *testing.T)
 	}
 }
 
+func TestOpenAIToGoogleAIStudioTranslatorV1ChatCompletion_RequestBody(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		stream            bool
+		modelNameOverride string
+		expPath           string
+	}{
+		{
+			name:    "generateContent",
+			expPath: "/v1beta/models/gemini-2.5-flash:generateContent",
+		},
+		{
+			name:    "streamGenerateContent",
+			stream:  true,
+			expPath: "/v1beta/models/gemini-2.5-flash:streamGenerateContent?alt=sse",
+		},
+		{
+			name:              "model override",
+			modelNameOverride: "gemini-2.5-pro",
+			expPath:           "/v1beta/models/gemini-2.5-pro:generateContent",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := NewChatCompletionOpenAIToGoogleAIStudioTranslator(tc.modelNameOverride)
+			headers, body, err := tr.RequestBody(nil, &openai.ChatCompletionRequest{
+				Model:  "gemini-2.5-flash",
+				Stream: tc.stream,
+				Messages: []openai.ChatCompletionMessageParamUnion{
+					{OfUser: &openai.ChatCompletionUserMessageParam{
+						Role:    openai.ChatMessageRoleUser,
+						Content: openai.StringOrUserRoleContentUnion{Value: "Tell me a joke."},
+					}},
+				},
+			}, false)
+			require.NoError(t, err)
+			require.Equal(t, []internalapi.Header{
+				{pathHeaderName, tc.expPath},
+				{contentLengthHeaderName, strconv.Itoa(len(body))},
+			}, headers)
+			// The body is the same as for Vertex AI.
+			require.JSONEq(t, `{"contents":[{"parts":[{"text":"Tell me a joke."}],"role":"user"}],"tools":null,"generation_config":{}}`, string(body))
+		})
+	}
+}
+
 func TestOpenAIToGCPVertexAITranslatorV1ChatCompletion_ResponseHeaders(t *testing.T) {
 	tests := []struct {
 		name      string
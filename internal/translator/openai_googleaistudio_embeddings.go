@@ -0,0 +1,152 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	tracing "github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+const (
+	googleAIStudioMethodBatchEmbedContents = "batchEmbedContents"
+)
+
+// NewEmbeddingOpenAIToGoogleAIStudioTranslator implements [Factory] for OpenAI to Gemini Developer API translation
+// for embeddings.
+func NewEmbeddingOpenAIToGoogleAIStudioTranslator(requestModel internalapi.RequestModel, modelNameOverride internalapi.ModelNameOverride) OpenAIEmbeddingTranslator {
+	return &openAIToGoogleAIStudioTranslatorV1Embedding{
+		requestModel:      requestModel,
+		modelNameOverride: modelNameOverride,
+	}
+}
+
+// openAIToGoogleAIStudioTranslatorV1Embedding translates OpenAI Embeddings API to the batchEmbedContents method
+// of the Gemini Developer API of Google AI Studio:
+// https://ai.google.dev/api/embeddings#method:-models.batchembedcontents
+type openAIToGoogleAIStudioTranslatorV1Embedding struct {
+	requestModel      internalapi.RequestModel
+	modelNameOverride internalapi.ModelNameOverride
+	// base64Encoding is true when the embeddings must be returned base64 encoded to the client.
+	base64Encoding bool
+}
+
+// openAIEmbeddingToBatchEmbedContents converts an OpenAI EmbeddingRequest to a Gemini BatchEmbedContentsRequest.
+// The inputs are converted the same way as for Vertex AI, and each instance becomes a separate request.
+func openAIEmbeddingToBatchEmbedContents(openAIReq *openai.EmbeddingRequest, requestModel internalapi.RequestModel) (*gcp.BatchEmbedContentsRequest, error) {
+	predictReq, err := openAIEmbeddingToGeminiMessage(openAIReq)
+	if err != nil {
+		return nil, err
+	}
+
+	model := "models/" + strings.TrimPrefix(requestModel, "models/")
+	batchReq := &gcp.BatchEmbedContentsRequest{Requests: make([]*gcp.EmbedContentRequest, len(predictReq.Instances))}
+	for i, instance := range predictReq.Instances {
+		batchReq.Requests[i] = &gcp.EmbedContentRequest{
+			Model:                model,
+			Content:              &genai.Content{Parts: []*genai.Part{{Text: instance.Content}}},
+			TaskType:             instance.TaskType,
+			Title:                instance.Title,
+			OutputDimensionality: predictReq.Parameters.OutputDimensionality,
+		}
+	}
+	return batchReq, nil
+}
+
+// RequestBody implements [OpenAIEmbeddingTranslator.RequestBody] for the Gemini Developer API.
+func (o *openAIToGoogleAIStudioTranslatorV1Embedding) RequestBody(_ []byte, req *openai.EmbeddingRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.requestModel = req.Model
+	if o.modelNameOverride != "" {
+		// Use modelName override if set.
+		o.requestModel = o.modelNameOverride
+	}
+
+	o.base64Encoding = isBase64EncodingFormat(req)
+
+	batchReq, err := openAIEmbeddingToBatchEmbedContents(req, o.requestModel)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error converting EmbeddingRequest: %w", err)
+	}
+
+	newBody, err = json.Marshal(batchReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error marshaling Gemini request: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{pathHeaderName, buildGoogleAIStudioModelPath(o.requestModel, googleAIStudioMethodBatchEmbedContents)},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// ResponseHeaders implements [OpenAIEmbeddingTranslator.ResponseHeaders].
+func (o *openAIToGoogleAIStudioTranslatorV1Embedding) ResponseHeaders(_ map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIEmbeddingTranslator.ResponseBody] for the Gemini Developer API.
+// The batchEmbedContents response contains neither the model nor the token usage, so the request model is
+// returned as the response model and the token usage is left unset.
+func (o *openAIToGoogleAIStudioTranslatorV1Embedding) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracing.EmbeddingsSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	var batchResp gcp.BatchEmbedContentsResponse
+	if err = json.NewDecoder(body).Decode(&batchResp); err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	openaiResp := openai.EmbeddingResponse{
+		Object: "list",
+		Model:  o.requestModel,
+		Data:   make([]openai.Embedding, 0, len(batchResp.Embeddings)),
+	}
+	for i, embedding := range batchResp.Embeddings {
+		if embedding == nil {
+			continue
+		}
+		// Convert float32 slice to float64 slice for OpenAI format.
+		float64Values := make([]float64, len(embedding.Values))
+		for j, v := range embedding.Values {
+			float64Values[j] = float64(v)
+		}
+		openaiResp.Data = append(openaiResp.Data, openai.Embedding{
+			Object:    "embedding",
+			Index:     i,
+			Embedding: embeddingUnion(float64Values, o.base64Encoding),
+		})
+	}
+
+	newBody, err = json.Marshal(openaiResp)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to marshal OpenAI response: %w", err)
+	}
+
+	if span != nil {
+		span.RecordResponse(&openaiResp)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return newHeaders, newBody, tokenUsage, openaiResp.Model, nil
+}
+
+// ResponseError implements [OpenAIEmbeddingTranslator.ResponseError].
+// The Gemini Developer API returns the same error format as Vertex AI.
+func (o *openAIToGoogleAIStudioTranslatorV1Embedding) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	return convertGCPVertexAIErrorToOpenAI(respHeaders, body)
+}
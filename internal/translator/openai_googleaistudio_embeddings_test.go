@@ -0,0 +1,121 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+)
+
+func TestOpenAIToGoogleAIStudioTranslatorV1Embedding_RequestBody(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		req               openai.EmbeddingRequest
+		modelNameOverride string
+		expPath           string
+		expBody           string
+	}{
+		{
+			name:    "string input",
+			req:     openai.EmbeddingRequest{Model: "gemini-embedding-001", Input: openai.EmbeddingRequestInput{Value: "hello"}},
+			expPath: "/v1beta/models/gemini-embedding-001:batchEmbedContents",
+			expBody: `{"requests":[{"model":"models/gemini-embedding-001","content":{"parts":[{"text":"hello"}]}}]}`,
+		},
+		{
+			name: "array input with dimensions",
+			req: openai.EmbeddingRequest{
+				Model:      "gemini-embedding-001",
+				Input:      openai.EmbeddingRequestInput{Value: []string{"a", "b"}},
+				Dimensions: ptr.To(768),
+			},
+			expPath: "/v1beta/models/gemini-embedding-001:batchEmbedContents",
+			expBody: `{"requests":[
+{"model":"models/gemini-embedding-001","content":{"parts":[{"text":"a"}]},"outputDimensionality":768},
+{"model":"models/gemini-embedding-001","content":{"parts":[{"text":"b"}]},"outputDimensionality":768}]}`,
+		},
+		{
+			name: "input item with task type and title",
+			req: openai.EmbeddingRequest{
+				Model: "gemini-embedding-001",
+				Input: openai.EmbeddingRequestInput{Value: openai.EmbeddingInputItem{
+					Content:  openai.EmbeddingContent{Value: "doc"},
+					TaskType: openai.EmbeddingTaskTypeRetrievalDocument,
+					Title:    "title",
+				}},
+			},
+			modelNameOverride: "models/text-embedding-004",
+			expPath:           "/v1beta/models/text-embedding-004:batchEmbedContents",
+			expBody:           `{"requests":[{"model":"models/text-embedding-004","content":{"parts":[{"text":"doc"}]},"taskType":"RETRIEVAL_DOCUMENT","title":"title"}]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := NewEmbeddingOpenAIToGoogleAIStudioTranslator(tc.req.Model, tc.modelNameOverride)
+			headers, body, err := tr.RequestBody(nil, &tc.req, false)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expBody, string(body))
+			require.Equal(t, []internalapi.Header{
+				{pathHeaderName, tc.expPath},
+				{contentLengthHeaderName, strconv.Itoa(len(body))},
+			}, headers)
+		})
+	}
+
+	t.Run("unsupported input", func(t *testing.T) {
+		tr := NewEmbeddingOpenAIToGoogleAIStudioTranslator("", "")
+		_, _, err := tr.RequestBody(nil, &openai.EmbeddingRequest{Input: openai.EmbeddingRequestInput{Value: []int64{1}}}, false)
+		require.ErrorContains(t, err, "unsupported input type")
+	})
+}
+
+func TestOpenAIToGoogleAIStudioTranslatorV1Embedding_ResponseBody(t *testing.T) {
+	tr := NewEmbeddingOpenAIToGoogleAIStudioTranslator("", "")
+	_, _, err := tr.RequestBody(nil, &openai.EmbeddingRequest{Model: "gemini-embedding-001", Input: openai.EmbeddingRequestInput{Value: []string{"a", "b"}}}, false)
+	require.NoError(t, err)
+
+	span := &mockEmbeddingsSpan{}
+	headers, body, tokenUsage, responseModel, err := tr.ResponseBody(nil,
+		strings.NewReader(`{"embeddings":[{"values":[0.5,-1]},{"values":[0.25]}]}`), true, span)
+	require.NoError(t, err)
+	require.Equal(t, "gemini-embedding-001", responseModel)
+	require.JSONEq(t, `{"object":"list","model":"gemini-embedding-001","usage":{"prompt_tokens":0,"total_tokens":0},"data":[
+{"object":"embedding","index":0,"embedding":[0.5,-1]},
+{"object":"embedding","index":1,"embedding":[0.25]}]}`, string(body))
+	require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(body))}}, headers)
+	_, ok := tokenUsage.InputTokens()
+	require.False(t, ok)
+	require.NotNil(t, span.recordedResponse)
+
+	t.Run("base64 encoding format", func(t *testing.T) {
+		tr := NewEmbeddingOpenAIToGoogleAIStudioTranslator("", "")
+		encodingFormat := "base64"
+		_, _, err := tr.RequestBody(nil, &openai.EmbeddingRequest{Model: "gemini-embedding-001", Input: openai.EmbeddingRequestInput{Value: "a"}, EncodingFormat: &encodingFormat}, false)
+		require.NoError(t, err)
+		_, body, _, _, err := tr.ResponseBody(nil, strings.NewReader(`{"embeddings":[{"values":[1,-2.5]}]}`), true, nil)
+		require.NoError(t, err)
+		require.Contains(t, string(body), `"embedding":"AACAPwAAIMA="`)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		_, _, _, _, err := tr.ResponseBody(nil, strings.NewReader("{"), true, nil)
+		require.ErrorContains(t, err, "failed to unmarshal response")
+	})
+}
+
+func TestOpenAIToGoogleAIStudioTranslatorV1Embedding_ResponseError(t *testing.T) {
+	tr := NewEmbeddingOpenAIToGoogleAIStudioTranslator("", "")
+	headers, body, err := tr.ResponseError(map[string]string{statusHeaderName: "400", contentTypeHeaderName: jsonContentType},
+		strings.NewReader(`{"error":{"code":400,"message":"API key not valid.","status":"INVALID_ARGUMENT"}}`))
+	require.NoError(t, err)
+	require.NotEmpty(t, headers)
+	require.Contains(t, string(body), "API key not valid.")
+}
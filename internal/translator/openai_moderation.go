@@ -0,0 +1,137 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/sjson"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewModerationOpenAIToOpenAITranslator implements [OpenAIModerationTranslator] for OpenAI to OpenAI translation
+// for /v1/moderations.
+//
+// The request is passed through when the model is an OpenAI moderation model. Otherwise, the backend is assumed to
+// be a chat model, and the request is delegated to the classifier, see [NewModerationToChatCompletionTranslator].
+func NewModerationOpenAIToOpenAITranslator(prefix string, modelNameOverride internalapi.ModelNameOverride, classifier OpenAIModerationTranslator) OpenAIModerationTranslator {
+	return &openAIToOpenAITranslatorV1Moderation{
+		modelNameOverride: modelNameOverride,
+		path:              path.Join("/", prefix, "moderations"),
+		classifier:        classifier,
+	}
+}
+
+// openAIToOpenAITranslatorV1Moderation is a passthrough translator for the OpenAI Moderations API:
+// https://platform.openai.com/docs/api-reference/moderations
+type openAIToOpenAITranslatorV1Moderation struct {
+	modelNameOverride internalapi.ModelNameOverride
+	// The path of the moderations endpoint to be used for the request. It is prefixed with the OpenAI path prefix.
+	path string
+	// classifier is the translator used when the model is not an OpenAI moderation model.
+	classifier OpenAIModerationTranslator
+	// useClassifier is true when the request is delegated to the classifier.
+	useClassifier bool
+	// requestModel is the model of the request after the override, which is used as the fallback response model.
+	requestModel internalapi.RequestModel
+}
+
+// RequestBody implements [OpenAIModerationTranslator.RequestBody].
+func (o *openAIToOpenAITranslatorV1Moderation) RequestBody(original []byte, req *openai.ModerationRequest, forceBodyMutation bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	o.requestModel = cmp.Or(o.modelNameOverride, req.Model, openai.ModerationModelOmniLatest)
+	if !openai.IsModerationModel(o.requestModel) {
+		o.useClassifier = true
+		return o.classifier.RequestBody(original, req, forceBodyMutation)
+	}
+
+	if o.modelNameOverride != "" {
+		newBody, err = sjson.SetBytesOptions(original, "model", o.modelNameOverride, sjsonOptions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to set model name: %w", err)
+		}
+	}
+	newHeaders = []internalapi.Header{{pathHeaderName, o.path}}
+	if forceBodyMutation && len(newBody) == 0 {
+		newBody = original
+	}
+	if len(newBody) > 0 {
+		newHeaders = append(newHeaders, internalapi.Header{contentLengthHeaderName, strconv.Itoa(len(newBody))})
+	}
+	return
+}
+
+// ResponseHeaders implements [OpenAIModerationTranslator.ResponseHeaders].
+func (o *openAIToOpenAITranslatorV1Moderation) ResponseHeaders(headers map[string]string) (newHeaders []internalapi.Header, err error) {
+	if o.useClassifier {
+		return o.classifier.ResponseHeaders(headers)
+	}
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAIModerationTranslator.ResponseBody].
+//
+// The moderation models are free of charge, so the token usage is always empty.
+func (o *openAIToOpenAITranslatorV1Moderation) ResponseBody(respHeaders map[string]string, body io.Reader, endOfStream bool, span tracingapi.ModerationsSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	if o.useClassifier {
+		return o.classifier.ResponseBody(respHeaders, body, endOfStream, span)
+	}
+	var resp openai.ModerationResponse
+	if err = json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, nil, tokenUsage, o.requestModel, fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	if span != nil {
+		span.RecordResponse(&resp)
+	}
+	return nil, nil, tokenUsage, cmp.Or(resp.Model, o.requestModel), nil
+}
+
+// ResponseError implements [OpenAIModerationTranslator.ResponseError].
+func (o *openAIToOpenAITranslatorV1Moderation) ResponseError(respHeaders map[string]string, body io.Reader) ([]internalapi.Header, []byte, error) {
+	if o.useClassifier {
+		return o.classifier.ResponseError(respHeaders, body)
+	}
+	return convertErrorOpenAIToOpenAIError(respHeaders, body)
+}
+
+// moderationInputTexts returns the texts of the moderation input, one per moderation result. The text parts of
+// a multi-modal input are a single input, so they are joined. The image inputs are only supported by the OpenAI
+// moderation models.
+func moderationInputTexts(input *openai.ModerationInput) ([]string, error) {
+	switch v := input.Value.(type) {
+	case string:
+		return []string{v}, nil
+	case []string:
+		if len(v) == 0 {
+			return nil, fmt.Errorf("%w: input must not be empty", internalapi.ErrInvalidRequestBody)
+		}
+		return v, nil
+	case []openai.ModerationMultiModalInput:
+		texts := make([]string, 0, len(v))
+		for _, part := range v {
+			if part.Type != "text" {
+				return nil, fmt.Errorf("%w: %s input is only supported by the OpenAI moderation models", internalapi.ErrInvalidRequestBody, part.Type)
+			}
+			texts = append(texts, part.Text)
+		}
+		return []string{strings.Join(texts, "\n")}, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported input type %T", internalapi.ErrInvalidRequestBody, v)
+	}
+}
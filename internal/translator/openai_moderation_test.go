@@ -0,0 +1,205 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestOpenAIToOpenAITranslatorV1Moderation_RequestBody(t *testing.T) {
+	original := []byte(`{"model":"omni-moderation-latest","input":"hello"}`)
+	for _, tc := range []struct {
+		name      string
+		override  string
+		force     bool
+		expHeader []internalapi.Header
+		expBody   []byte
+	}{
+		{
+			name:      "passthrough",
+			expHeader: []internalapi.Header{{pathHeaderName, "/v1/moderations"}},
+		},
+		{
+			name:      "force",
+			force:     true,
+			expHeader: []internalapi.Header{{pathHeaderName, "/v1/moderations"}, {contentLengthHeaderName, strconv.Itoa(len(original))}},
+			expBody:   original,
+		},
+		{
+			name:     "override",
+			override: "text-moderation-stable",
+			expHeader: []internalapi.Header{
+				{pathHeaderName, "/v1/moderations"},
+				{contentLengthHeaderName, "50"},
+			},
+			expBody: []byte(`{"model":"text-moderation-stable","input":"hello"}`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var req openai.ModerationRequest
+			require.NoError(t, json.Unmarshal(original, &req))
+			translator := NewModerationOpenAIToOpenAITranslator("v1", tc.override, nil)
+			headers, body, err := translator.RequestBody(original, &req, tc.force)
+			require.NoError(t, err)
+			require.Equal(t, tc.expHeader, headers)
+			require.Equal(t, tc.expBody, body)
+		})
+	}
+
+	t.Run("chat model uses classifier", func(t *testing.T) {
+		req := &openai.ModerationRequest{Model: "gpt-4o-mini", Input: openai.ModerationInput{Value: "hello"}}
+		translator := NewModerationOpenAIToOpenAITranslator("v1", "",
+			NewModerationToChatCompletionTranslator(NewChatCompletionOpenAIToOpenAITranslator("v1", "")))
+		headers, body, err := translator.RequestBody(nil, req, false)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{
+			{pathHeaderName, "/v1/chat/completions"},
+			{contentLengthHeaderName, strconv.Itoa(len(body))},
+		}, headers)
+
+		var chatReq openai.ChatCompletionRequest
+		require.NoError(t, json.Unmarshal(body, &chatReq))
+		require.Equal(t, "gpt-4o-mini", chatReq.Model)
+		require.Len(t, chatReq.Messages, 2)
+		require.Equal(t, `["hello"]`, chatReq.Messages[1].OfUser.Content.Value)
+	})
+}
+
+func TestOpenAIToOpenAITranslatorV1Moderation_ResponseBody(t *testing.T) {
+	t.Run("passthrough", func(t *testing.T) {
+		translator := NewModerationOpenAIToOpenAITranslator("v1", "", nil)
+		_, _, err := translator.RequestBody(nil, &openai.ModerationRequest{Input: openai.ModerationInput{Value: "hello"}}, false)
+		require.NoError(t, err)
+
+		resp := `{"id":"modr-123","model":"omni-moderation-2024-09-26","results":[{"flagged":false,"categories":{"hate":false},"category_scores":{"hate":0.01}}]}`
+		headers, body, tokenUsage, responseModel, err := translator.ResponseBody(nil, strings.NewReader(resp), true, nil)
+		require.NoError(t, err)
+		require.Nil(t, headers)
+		require.Nil(t, body)
+		require.Equal(t, "omni-moderation-2024-09-26", responseModel)
+		_, ok := tokenUsage.InputTokens()
+		require.False(t, ok)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		translator := NewModerationOpenAIToOpenAITranslator("v1", "", nil)
+		_, _, err := translator.RequestBody(nil, &openai.ModerationRequest{Input: openai.ModerationInput{Value: "hello"}}, false)
+		require.NoError(t, err)
+		_, _, _, responseModel, err := translator.ResponseBody(nil, strings.NewReader("not-json"), true, nil)
+		require.ErrorContains(t, err, "failed to unmarshal body")
+		require.Equal(t, openai.ModerationModelOmniLatest, responseModel)
+	})
+}
+
+func TestModerationToChatCompletionTranslator(t *testing.T) {
+	newTranslator := func(t *testing.T, inputs any) OpenAIModerationTranslator {
+		translator := NewModerationToChatCompletionTranslator(NewChatCompletionOpenAIToOpenAITranslator("v1", "gpt-4o-mini"))
+		_, _, err := translator.RequestBody(nil, &openai.ModerationRequest{Model: "classifier", Input: openai.ModerationInput{Value: inputs}}, false)
+		require.NoError(t, err)
+		return translator
+	}
+	chatResponse := func(content string) string {
+		b, err := json.Marshal(&openai.ChatCompletionResponse{
+			ID:    "chatcmpl-123",
+			Model: "gpt-4o-mini-2024-07-18",
+			Choices: []openai.ChatCompletionResponseChoice{{
+				Message: openai.ChatCompletionResponseChoiceMessage{Role: "assistant", Content: &content},
+			}},
+			Usage: openai.Usage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120},
+		})
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	t.Run("scores", func(t *testing.T) {
+		translator := newTranslator(t, []string{"hello", "I will hurt you"})
+		content := "```json\n" + `{"results":[{"hate":0.1},{"violence":0.9,"harassment/threatening":1.5,"unknown":1}]}` + "\n```"
+		headers, body, tokenUsage, responseModel, err := translator.ResponseBody(nil, strings.NewReader(chatResponse(content)), true, nil)
+		require.NoError(t, err)
+		require.Equal(t, []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(body))}}, headers)
+		require.Equal(t, "gpt-4o-mini-2024-07-18", responseModel)
+		require.Equal(t, tokenUsageFrom(100, -1, -1, 20, 120, -1), tokenUsage)
+
+		var resp openai.ModerationResponse
+		require.NoError(t, json.Unmarshal(body, &resp))
+		require.Equal(t, "modr-chatcmpl-123", resp.ID)
+		require.Equal(t, "gpt-4o-mini-2024-07-18", resp.Model)
+		require.Len(t, resp.Results, 2)
+
+		require.False(t, resp.Results[0].Flagged)
+		require.Len(t, resp.Results[0].CategoryScores, len(openai.ModerationCategories))
+		require.InDelta(t, 0.1, resp.Results[0].CategoryScores[openai.ModerationCategoryHate], 1e-9)
+
+		require.True(t, resp.Results[1].Flagged)
+		require.True(t, resp.Results[1].Categories[openai.ModerationCategoryViolence])
+		require.True(t, resp.Results[1].Categories[openai.ModerationCategoryHarassmentThreatening])
+		require.False(t, resp.Results[1].Categories[openai.ModerationCategoryHate])
+		require.InDelta(t, 1.0, resp.Results[1].CategoryScores[openai.ModerationCategoryHarassmentThreatening], 1e-9)
+		require.NotContains(t, resp.Results[1].CategoryScores, "unknown")
+	})
+
+	t.Run("result count mismatch", func(t *testing.T) {
+		translator := newTranslator(t, []string{"a", "b"})
+		_, _, _, _, err := translator.ResponseBody(nil, strings.NewReader(chatResponse(`{"results":[{}]}`)), true, nil)
+		require.ErrorContains(t, err, "classifier replied 1 results for 2 inputs")
+	})
+
+	t.Run("no JSON in reply", func(t *testing.T) {
+		translator := newTranslator(t, "a")
+		_, _, _, _, err := translator.ResponseBody(nil, strings.NewReader(chatResponse("I cannot help with that.")), true, nil)
+		require.ErrorContains(t, err, "classifier reply does not contain a JSON object")
+	})
+
+	t.Run("image input", func(t *testing.T) {
+		translator := NewModerationToChatCompletionTranslator(NewChatCompletionOpenAIToOpenAITranslator("v1", ""))
+		_, _, err := translator.RequestBody(nil, &openai.ModerationRequest{Input: openai.ModerationInput{Value: []openai.ModerationMultiModalInput{
+			{Type: "image_url", ImageURL: &openai.ModerationImageURL{URL: "https://example.com/image.png"}},
+		}}}, false)
+		require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+	})
+}
+
+func TestModerationInputTexts(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		input  any
+		exp    []string
+		expErr string
+	}{
+		{name: "string", input: "a", exp: []string{"a"}},
+		{name: "strings", input: []string{"a", "b"}, exp: []string{"a", "b"}},
+		{name: "empty strings", input: []string{}, expErr: "input must not be empty"},
+		{
+			name:  "multi-modal text",
+			input: []openai.ModerationMultiModalInput{{Type: "text", Text: "a"}, {Type: "text", Text: "b"}},
+			exp:   []string{"a\nb"},
+		},
+		{
+			name:   "multi-modal image",
+			input:  []openai.ModerationMultiModalInput{{Type: "image_url", ImageURL: &openai.ModerationImageURL{URL: "https://example.com"}}},
+			expErr: "image_url input is only supported by the OpenAI moderation models",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			texts, err := moderationInputTexts(&openai.ModerationInput{Value: tc.input})
+			if tc.expErr != "" {
+				require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+				require.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.exp, texts)
+		})
+	}
+}
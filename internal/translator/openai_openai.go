@@ -43,6 +43,9 @@ type openAIToOpenAITranslatorV1ChatCompletion struct {
 	streamingResponseModel internalapi.ResponseModel
 	stream                 bool
 	buffered               []byte
+
+	// streamingToolCalls is the number of tool calls seen so far in the streaming response.
+	streamingToolCalls uint32
 	// The path of the chat completions endpoint to be used for the request. It is prefixed with the OpenAI path prefix.
 	path string
 	// Redaction configuration for debug logging
@@ -166,6 +169,19 @@ func (o *openAIToOpenAITranslatorV1ChatCompletion) ResponseBody(_ map[string]str
 	if resp.Usage.CompletionTokensDetails != nil {
 		tokenUsage.SetReasoningTokens(uint32(resp.Usage.CompletionTokensDetails.ReasoningTokens)) //nolint:gosec
 	}
+	var toolCalls, audios uint32
+	for i := range resp.Choices {
+		toolCalls += uint32(len(resp.Choices[i].Message.ToolCalls)) //nolint:gosec
+		if resp.Choices[i].Message.Audio != nil {
+			audios++
+		}
+	}
+	if toolCalls > 0 {
+		tokenUsage.SetToolCallCount(toolCalls)
+	}
+	if audios > 0 {
+		tokenUsage.SetAudioCount(audios)
+	}
 	// Fallback to request model for test or non-compliant OpenAI backends
 	responseModel = cmp.Or(resp.Model, o.requestModel)
 	if span != nil {
@@ -198,6 +214,20 @@ func (o *openAIToOpenAITranslatorV1ChatCompletion) extractUsageFromBufferEvent(s
 			// Store the response model for future batches
 			o.streamingResponseModel = event.Model
 		}
+		for i := range event.Choices {
+			if event.Choices[i].Delta == nil {
+				continue
+			}
+			for _, tc := range event.Choices[i].Delta.ToolCalls {
+				// Only the first delta of each tool call carries the ID.
+				if tc.ID != nil && *tc.ID != "" {
+					o.streamingToolCalls++
+				}
+			}
+		}
+		if o.streamingToolCalls > 0 {
+			tokenUsage.SetToolCallCount(o.streamingToolCalls)
+		}
 		if usage := event.Usage; usage != nil {
 			tokenUsage.SetInputTokens(uint32(usage.PromptTokens))      //nolint:gosec
 			tokenUsage.SetOutputTokens(uint32(usage.CompletionTokens)) //nolint:gosec
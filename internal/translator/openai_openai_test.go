@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -334,6 +335,27 @@ data: [DONE]
 		}
 	})
 
+	t.Run("streaming tool calls", func(t *testing.T) {
+		o := &openAIToOpenAITranslatorV1ChatCompletion{stream: true}
+		_, _, tokenUsage, _, err := o.ResponseBody(nil, strings.NewReader(`data: {"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"a","arguments":""}}]}}]}
+
+data: {"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{}"}}]}}]}
+
+`), false, nil)
+		require.NoError(t, err)
+		toolCalls, ok := tokenUsage.ToolCallCount()
+		require.True(t, ok)
+		require.Equal(t, uint32(1), toolCalls)
+
+		_, _, tokenUsage, _, err = o.ResponseBody(nil, strings.NewReader(`data: {"choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_2","type":"function","function":{"name":"b","arguments":"{}"}}]}}]}
+
+`), true, nil)
+		require.NoError(t, err)
+		toolCalls, ok = tokenUsage.ToolCallCount()
+		require.True(t, ok)
+		require.Equal(t, uint32(2), toolCalls)
+	})
+
 	t.Run("streaming read error", func(t *testing.T) {
 		o := &openAIToOpenAITranslatorV1ChatCompletion{stream: true}
 		pr, pw := io.Pipe()
@@ -378,6 +400,25 @@ data: [DONE]
 			require.Equal(t, &resp, s.Resp)
 		})
 	})
+	t.Run("valid body with tool calls and audio", func(t *testing.T) {
+		var resp openai.ChatCompletionResponse
+		resp.Choices = []openai.ChatCompletionResponseChoice{
+			{Message: openai.ChatCompletionResponseChoiceMessage{ToolCalls: []openai.ChatCompletionMessageToolCallParam{
+				{ID: ptr.To("call_1"), Type: openai.ChatCompletionMessageToolCallTypeFunction},
+				{ID: ptr.To("call_2"), Type: openai.ChatCompletionMessageToolCallTypeFunction},
+			}}},
+			{Message: openai.ChatCompletionResponseChoiceMessage{Audio: &openai.ChatCompletionResponseChoiceMessageAudio{ID: "audio_1"}}},
+		}
+		body, err := json.Marshal(resp)
+		require.NoError(t, err)
+		o := &openAIToOpenAITranslatorV1ChatCompletion{}
+		_, _, usedToken, _, err := o.ResponseBody(nil, bytes.NewBuffer(body), false, nil)
+		require.NoError(t, err)
+		expUsage := tokenUsageFrom(0, -1, -1, 0, 0, -1)
+		expUsage.SetToolCallCount(2)
+		expUsage.SetAudioCount(1)
+		require.Equal(t, expUsage, usedToken)
+	})
 	t.Run("valid body with reasoning tokens", func(t *testing.T) {
 		s := &testotel.MockSpan{}
 		var resp openai.ChatCompletionResponse
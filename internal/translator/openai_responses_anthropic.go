@@ -0,0 +1,995 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+const (
+	// defaultResponsesMaxTokens is used as Anthropic max_tokens when the Responses request does not set
+	// max_output_tokens, since the Messages API requires it.
+	defaultResponsesMaxTokens = 4096
+)
+
+// NewResponsesToAnthropicTranslator implements [OpenAIResponsesTranslator] for OpenAI Responses to
+// native Anthropic Messages translation.
+func NewResponsesToAnthropicTranslator(version string, modelNameOverride internalapi.ModelNameOverride) OpenAIResponsesTranslator {
+	return &responsesToAnthropicMessagesTranslator{inner: NewAnthropicToAnthropicTranslator(version, modelNameOverride)}
+}
+
+// NewResponsesToAWSBedrockTranslator implements [OpenAIResponsesTranslator] for OpenAI Responses to
+// AWS Bedrock Converse translation.
+func NewResponsesToAWSBedrockTranslator(modelNameOverride internalapi.ModelNameOverride) OpenAIResponsesTranslator {
+	return &responsesToAnthropicMessagesTranslator{inner: NewAnthropicToAWSBedrockTranslator(modelNameOverride)}
+}
+
+// NewResponsesToGCPVertexAITranslator implements [OpenAIResponsesTranslator] for OpenAI Responses to
+// GCP Vertex AI Gemini generateContent translation.
+func NewResponsesToGCPVertexAITranslator(modelNameOverride internalapi.ModelNameOverride) OpenAIResponsesTranslator {
+	return &responsesToAnthropicMessagesTranslator{inner: NewAnthropicToGCPVertexAITranslator(modelNameOverride)}
+}
+
+// responsesToAnthropicMessagesTranslator translates the OpenAI Responses API to backends that do not speak it
+// natively. The Responses request is first converted to an Anthropic Messages request, and the backend specific
+// translation is delegated to an [AnthropicMessagesTranslator]. The Anthropic Messages responses produced by the
+// inner translator are then converted back to the Responses format, including the streamed response.* events.
+type responsesToAnthropicMessagesTranslator struct {
+	inner AnthropicMessagesTranslator
+	// req is the original Responses request, used to echo request parameters back in the response object.
+	req    *openai.ResponseRequest
+	stream bool
+	// streamState holds the state of the streamed response.* event conversion.
+	streamState *anthropicToResponsesStreamState
+}
+
+// RequestBody implements [OpenAIResponsesTranslator.RequestBody].
+func (r *responsesToAnthropicMessagesTranslator) RequestBody(_ []byte, req *openai.ResponseRequest, _ bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	r.req = req
+	r.stream = req.Stream
+	anthropicReq, err := responsesToAnthropicMessagesRequest(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.stream {
+		r.streamState = &anthropicToResponsesStreamState{req: req, createdAt: time.Now()}
+	}
+	original, err := json.Marshal(anthropicReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal Anthropic request: %w", err)
+	}
+	// The body always has to be mutated since the backend does not understand the Responses format.
+	return r.inner.RequestBody(original, anthropicReq, true)
+}
+
+// ResponseHeaders implements [OpenAIResponsesTranslator.ResponseHeaders].
+func (r *responsesToAnthropicMessagesTranslator) ResponseHeaders(headers map[string]string) (newHeaders []internalapi.Header, err error) {
+	newHeaders, err = r.inner.ResponseHeaders(headers)
+	if err != nil {
+		return nil, err
+	}
+	if r.stream && !hasHeader(newHeaders, contentTypeHeaderName) {
+		newHeaders = append(newHeaders, internalapi.Header{contentTypeHeaderName, eventStreamContentType})
+	}
+	return newHeaders, nil
+}
+
+// ResponseBody implements [OpenAIResponsesTranslator.ResponseBody].
+func (r *responsesToAnthropicMessagesTranslator) ResponseBody(respHeaders map[string]string, body io.Reader, endOfStream bool, span tracingapi.ResponsesSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel string, err error,
+) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, tokenUsage, "", fmt.Errorf("failed to read body: %w", err)
+	}
+	// The inner translator produces the Anthropic Messages body. A nil body means it is passed through as is.
+	_, anthropicBody, tokenUsage, responseModel, err := r.inner.ResponseBody(respHeaders, bytes.NewReader(raw), endOfStream, nil)
+	if err != nil {
+		return nil, nil, tokenUsage, responseModel, err
+	}
+	if anthropicBody == nil {
+		anthropicBody = raw
+	}
+
+	if r.stream {
+		newBody, err = r.streamState.convert(anthropicBody, endOfStream, span)
+		if err != nil {
+			return nil, nil, tokenUsage, responseModel, err
+		}
+		return nil, newBody, tokenUsage, responseModel, nil
+	}
+
+	anthropicResp := &anthropic.MessagesResponse{}
+	if err = json.Unmarshal(anthropicBody, anthropicResp); err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to unmarshal Anthropic response: %w", err)
+	}
+	resp := anthropicToResponsesResponse(anthropicResp, r.req, time.Now())
+	resp.Model = cmp.Or(responseModel, resp.Model)
+	if span != nil {
+		span.RecordResponse(resp)
+	}
+	newBody, err = json.Marshal(resp)
+	if err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	newHeaders = []internalapi.Header{{contentLengthHeaderName, strconv.Itoa(len(newBody))}}
+	return
+}
+
+// ResponseError implements [OpenAIResponsesTranslator.ResponseError].
+// The inner translator normalizes the backend error to the Anthropic error format, which is then
+// converted to the OpenAI error format.
+func (r *responsesToAnthropicMessagesTranslator) ResponseError(respHeaders map[string]string, body io.Reader) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read error body: %w", err)
+	}
+	_, anthropicBody, err := r.inner.ResponseError(respHeaders, bytes.NewReader(raw))
+	if err != nil {
+		return nil, nil, err
+	}
+	if anthropicBody == nil {
+		anthropicBody = raw
+	}
+
+	statusCode := respHeaders[statusHeaderName]
+	openaiError := openai.Error{
+		Type: "error",
+		Error: openai.ErrorType{
+			Type:    openAIBackendError,
+			Message: string(anthropicBody),
+			Code:    &statusCode,
+		},
+	}
+	var anthropicError anthropic.ErrorResponse
+	if json.Unmarshal(anthropicBody, &anthropicError) == nil && anthropicError.Error.Type != "" {
+		openaiError.Error.Type = anthropicError.Error.Type
+		openaiError.Error.Message = anthropicError.Error.Message
+	}
+	newBody, err = json.Marshal(openaiError)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal error body: %w", err)
+	}
+	newHeaders = []internalapi.Header{
+		{contentTypeHeaderName, jsonContentType},
+		{contentLengthHeaderName, strconv.Itoa(len(newBody))},
+	}
+	return
+}
+
+// hasHeader reports whether the given header key is present in the headers.
+func hasHeader(headers []internalapi.Header, key string) bool {
+	for _, h := range headers {
+		if strings.EqualFold(h.Key(), key) {
+			return true
+		}
+	}
+	return false
+}
+
+// responsesToAnthropicMessagesRequest converts an OpenAI Responses request to an Anthropic Messages request.
+func responsesToAnthropicMessagesRequest(req *openai.ResponseRequest) (*anthropic.MessagesRequest, error) {
+	if req.PreviousResponseID != "" {
+		return nil, fmt.Errorf("%w: previous_response_id is not supported by this backend", internalapi.ErrInvalidRequestBody)
+	}
+
+	var system []string
+	if req.Instructions != "" {
+		system = append(system, req.Instructions)
+	}
+	var messages []anthropic.MessageParam
+	appendBlocks := func(role anthropic.MessageRole, blocks ...anthropic.ContentBlockParam) {
+		if len(blocks) == 0 {
+			return
+		}
+		// The Messages API expects alternating roles, so consecutive items of the same role are merged.
+		if n := len(messages); n > 0 && messages[n-1].Role == role {
+			messages[n-1].Content.Array = append(messages[n-1].Content.Array, blocks...)
+			return
+		}
+		messages = append(messages, anthropic.MessageParam{Role: role, Content: anthropic.MessageContent{Array: blocks}})
+	}
+
+	if s := req.Input.OfString; s != nil {
+		appendBlocks(anthropic.MessageRoleUser, anthropicTextBlock(*s))
+	}
+	for i := range req.Input.OfInputItemList {
+		item := &req.Input.OfInputItemList[i]
+		switch {
+		case item.OfMessage != nil:
+			msg := item.OfMessage
+			var blocks []anthropic.ContentBlockParam
+			if msg.Content.OfString != nil {
+				blocks = []anthropic.ContentBlockParam{anthropicTextBlock(*msg.Content.OfString)}
+			} else {
+				var err error
+				if blocks, err = responsesInputContentToAnthropic(msg.Content.OfInputItemContentList); err != nil {
+					return nil, err
+				}
+			}
+			switch msg.Role {
+			case "system", "developer":
+				system = append(system, anthropicBlocksText(blocks))
+			case "assistant":
+				appendBlocks(anthropic.MessageRoleAssistant, blocks...)
+			default:
+				appendBlocks(anthropic.MessageRoleUser, blocks...)
+			}
+		case item.OfInputMessage != nil:
+			blocks, err := responsesInputContentToAnthropic(item.OfInputMessage.Content)
+			if err != nil {
+				return nil, err
+			}
+			switch item.OfInputMessage.Role {
+			case "system", "developer":
+				system = append(system, anthropicBlocksText(blocks))
+			default:
+				appendBlocks(anthropic.MessageRoleUser, blocks...)
+			}
+		case item.OfOutputMessage != nil:
+			content := item.OfOutputMessage.Content
+			if content.OfString != nil {
+				appendBlocks(anthropic.MessageRoleAssistant, anthropicTextBlock(*content.OfString))
+			}
+			for _, part := range content.OfContentArray {
+				switch {
+				case part.OfOutputText != nil:
+					appendBlocks(anthropic.MessageRoleAssistant, anthropicTextBlock(part.OfOutputText.Text))
+				case part.OfRefusal != nil:
+					appendBlocks(anthropic.MessageRoleAssistant, anthropicTextBlock(part.OfRefusal.Refusal))
+				}
+			}
+		case item.OfFunctionCall != nil:
+			call := item.OfFunctionCall
+			input := map[string]any{}
+			if call.Arguments != "" {
+				if err := json.Unmarshal([]byte(call.Arguments), &input); err != nil {
+					return nil, fmt.Errorf("%w: invalid arguments for function call %s: %w", internalapi.ErrInvalidRequestBody, call.CallID, err)
+				}
+			}
+			appendBlocks(anthropic.MessageRoleAssistant, anthropic.ContentBlockParam{ToolUse: &anthropic.ToolUseBlockParam{
+				Type: "tool_use", ID: call.CallID, Name: call.Name, Input: input,
+			}})
+		case item.OfFunctionCallOutput != nil:
+			result, err := responsesFunctionCallOutputToAnthropic(item.OfFunctionCallOutput)
+			if err != nil {
+				return nil, err
+			}
+			appendBlocks(anthropic.MessageRoleUser, result)
+		case item.OfReasoning != nil:
+			// Only signed reasoning can be replayed to the backend, so reasoning items produced by other
+			// providers (without encrypted content) are dropped.
+			reasoning := item.OfReasoning
+			if reasoning.EncryptedContent == "" {
+				continue
+			}
+			var texts []string
+			for _, c := range reasoning.Content {
+				texts = append(texts, c.Text)
+			}
+			if len(texts) == 0 {
+				for _, s := range reasoning.Summary {
+					texts = append(texts, s.Text)
+				}
+			}
+			appendBlocks(anthropic.MessageRoleAssistant, anthropic.ContentBlockParam{Thinking: &anthropic.ThinkingBlockParam{
+				Type: "thinking", Thinking: strings.Join(texts, "\n"), Signature: reasoning.EncryptedContent,
+			}})
+		default:
+			return nil, fmt.Errorf("%w: unsupported input item at index %d", internalapi.ErrInvalidRequestBody, i)
+		}
+	}
+
+	out := &anthropic.MessagesRequest{
+		Model:       req.Model,
+		Messages:    messages,
+		MaxTokens:   defaultResponsesMaxTokens,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		Stream:      req.Stream,
+	}
+	if len(system) > 0 {
+		out.System = &anthropic.SystemPrompt{Text: strings.Join(system, "\n\n")}
+	}
+
+	var err error
+	if out.Tools, err = responsesToolsToAnthropic(req.Tools); err != nil {
+		return nil, err
+	}
+	if out.ToolChoice, err = responsesToolChoiceToAnthropic(&req.ToolChoice, req.ParallelToolCalls); err != nil {
+		return nil, err
+	}
+
+	budget, err := reasoningEffortToThinkingBudget(req.Reasoning.Effort)
+	if err != nil {
+		return nil, err
+	}
+	var maxOutputTokens int64
+	if req.MaxOutputTokens != nil {
+		maxOutputTokens = *req.MaxOutputTokens
+	}
+	switch {
+	case req.Reasoning.Effort == "none":
+		out.Thinking = &anthropic.Thinking{Disabled: &anthropic.ThinkingDisabled{Type: "disabled"}}
+	case budget > 0:
+		applyThinkingBudget(out, budget, maxOutputTokens)
+	}
+	if req.MaxOutputTokens != nil {
+		out.MaxTokens = float64(*req.MaxOutputTokens)
+	}
+	return out, nil
+}
+
+// anthropicTextBlock returns an Anthropic text content block.
+func anthropicTextBlock(text string) anthropic.ContentBlockParam {
+	return anthropic.ContentBlockParam{Text: &anthropic.TextBlockParam{Type: "text", Text: text}}
+}
+
+// anthropicBlocksText concatenates the text of the given content blocks, ignoring non-text blocks.
+func anthropicBlocksText(blocks []anthropic.ContentBlockParam) string {
+	var texts []string
+	for _, b := range blocks {
+		if b.Text != nil {
+			texts = append(texts, b.Text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// responsesInputContentToAnthropic converts Responses input content parts to Anthropic content blocks.
+func responsesInputContentToAnthropic(parts []openai.ResponseInputContentUnionParam) ([]anthropic.ContentBlockParam, error) {
+	blocks := make([]anthropic.ContentBlockParam, 0, len(parts))
+	for i := range parts {
+		part := &parts[i]
+		switch {
+		case part.OfInputText != nil:
+			blocks = append(blocks, anthropicTextBlock(part.OfInputText.Text))
+		case part.OfInputImage != nil:
+			block, err := responsesImageURLToAnthropic(part.OfInputImage.ImageURL)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, block)
+		case part.OfInputFile != nil:
+			block, err := responsesInputFileToAnthropic(part.OfInputFile)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+// responsesImageURLToAnthropic converts a Responses input image URL, either a data URI or a remote URL,
+// to an Anthropic image block. Images referenced by file_id cannot be resolved by the backend.
+func responsesImageURLToAnthropic(imageURL string) (anthropic.ContentBlockParam, error) {
+	if imageURL == "" {
+		return anthropic.ContentBlockParam{}, fmt.Errorf("%w: input_image requires image_url, file_id is not supported by this backend", internalapi.ErrInvalidRequestBody)
+	}
+	var source anthropic.ImageSource
+	if strings.HasPrefix(imageURL, "data:") {
+		contentType, data, err := parseDataURI(imageURL)
+		if err != nil {
+			return anthropic.ContentBlockParam{}, fmt.Errorf("%w: failed to parse image data URI: %w", internalapi.ErrInvalidRequestBody, err)
+		}
+		source.Base64 = &anthropic.Base64ImageSource{Type: "base64", MediaType: contentType, Data: base64.StdEncoding.EncodeToString(data)}
+	} else {
+		source.URL = &anthropic.URLImageSource{Type: "url", URL: imageURL}
+	}
+	return anthropic.ContentBlockParam{Image: &anthropic.ImageBlockParam{Type: "image", Source: source}}, nil
+}
+
+// responsesInputFileToAnthropic converts a Responses input file to an Anthropic document block.
+// Only PDF files passed inline or by URL are supported.
+func responsesInputFileToAnthropic(file *openai.ResponseInputFileParam) (anthropic.ContentBlockParam, error) {
+	var source anthropic.DocumentSource
+	switch {
+	case file.FileData != "":
+		contentType, data, err := parseDataURI(file.FileData)
+		if err != nil {
+			return anthropic.ContentBlockParam{}, fmt.Errorf("%w: failed to parse file data URI: %w", internalapi.ErrInvalidRequestBody, err)
+		}
+		if contentType != "application/pdf" {
+			return anthropic.ContentBlockParam{}, fmt.Errorf("%w: unsupported input_file content type %q", internalapi.ErrInvalidRequestBody, contentType)
+		}
+		source.Base64PDF = &anthropic.Base64PDFSource{Type: "base64", MediaType: contentType, Data: base64.StdEncoding.EncodeToString(data)}
+	case file.FileURL != "":
+		source.URL = &anthropic.URLPDFSource{Type: "url", URL: file.FileURL}
+	default:
+		return anthropic.ContentBlockParam{}, fmt.Errorf("%w: input_file requires file_data or file_url, file_id is not supported by this backend", internalapi.ErrInvalidRequestBody)
+	}
+	return anthropic.ContentBlockParam{Document: &anthropic.DocumentBlockParam{Type: "document", Source: source, Title: file.Filename}}, nil
+}
+
+// responsesFunctionCallOutputToAnthropic converts a function_call_output item to an Anthropic tool_result block.
+func responsesFunctionCallOutputToAnthropic(output *openai.ResponseInputItemFunctionCallOutputParam) (anthropic.ContentBlockParam, error) {
+	content := &anthropic.ToolResultContent{}
+	if output.Output.OfString != nil {
+		content.Text = *output.Output.OfString
+	}
+	for _, item := range output.Output.OfResponseFunctionCallOutputItemArray {
+		switch {
+		case item.OfInputText != nil:
+			content.Array = append(content.Array, anthropic.ToolResultContentItem{
+				Text: &anthropic.TextBlockParam{Type: "text", Text: item.OfInputText.Text},
+			})
+		case item.OfInputImage != nil:
+			block, err := responsesImageURLToAnthropic(item.OfInputImage.ImageURL)
+			if err != nil {
+				return anthropic.ContentBlockParam{}, err
+			}
+			content.Array = append(content.Array, anthropic.ToolResultContentItem{Image: block.Image})
+		default:
+			return anthropic.ContentBlockParam{}, fmt.Errorf("%w: unsupported function_call_output content for call %s", internalapi.ErrInvalidRequestBody, output.CallID)
+		}
+	}
+	if content.Text == "" && len(content.Array) == 0 {
+		content = nil
+	}
+	return anthropic.ContentBlockParam{ToolResult: &anthropic.ToolResultBlockParam{
+		Type: "tool_result", ToolUseID: output.CallID, Content: content,
+	}}, nil
+}
+
+// responsesToolsToAnthropic converts Responses function tools to Anthropic custom tools.
+// Hosted tools such as web_search or file_search are executed by OpenAI and cannot be translated.
+func responsesToolsToAnthropic(tools []openai.ResponseToolUnion) ([]anthropic.ToolUnion, error) {
+	if len(tools) == 0 {
+		return nil, nil
+	}
+	out := make([]anthropic.ToolUnion, 0, len(tools))
+	for i := range tools {
+		fn := tools[i].OfFunction
+		if fn == nil {
+			return nil, fmt.Errorf("%w: only function tools are supported by this backend, got unsupported tool at index %d", internalapi.ErrInvalidRequestBody, i)
+		}
+		schema := anthropic.ToolInputSchema{Type: "object"}
+		if props, ok := fn.Parameters["properties"].(map[string]any); ok {
+			schema.Properties = props
+		}
+		if required, ok := fn.Parameters["required"].([]any); ok {
+			for _, r := range required {
+				if s, ok := r.(string); ok {
+					schema.Required = append(schema.Required, s)
+				}
+			}
+		}
+		out = append(out, anthropic.ToolUnion{Tool: &anthropic.Tool{
+			Type: "custom", Name: fn.Name, Description: fn.Description, InputSchema: schema,
+		}})
+	}
+	return out, nil
+}
+
+// responsesToolChoiceToAnthropic converts the Responses tool_choice to the Anthropic tool choice.
+func responsesToolChoiceToAnthropic(choice *openai.ResponseToolChoiceUnion, parallelToolCalls *bool) (*anthropic.ToolChoice, error) {
+	var disableParallel *bool
+	if parallelToolCalls != nil && !*parallelToolCalls {
+		disableParallel = ptr.To(true)
+	}
+	switch {
+	case choice.OfToolChoiceMode != nil:
+		switch *choice.OfToolChoiceMode {
+		case "auto":
+			return &anthropic.ToolChoice{Auto: &anthropic.ToolChoiceAuto{Type: "auto", DisableParallelToolUse: disableParallel}}, nil
+		case "required":
+			return &anthropic.ToolChoice{Any: &anthropic.ToolChoiceAny{Type: "any", DisableParallelToolUse: disableParallel}}, nil
+		case "none":
+			return &anthropic.ToolChoice{None: &anthropic.ToolChoiceNone{Type: "none"}}, nil
+		default:
+			return nil, fmt.Errorf("%w: unsupported tool_choice %q", internalapi.ErrInvalidRequestBody, *choice.OfToolChoiceMode)
+		}
+	case choice.OfFunctionTool != nil:
+		return &anthropic.ToolChoice{Tool: &anthropic.ToolChoiceTool{
+			Type: "tool", Name: choice.OfFunctionTool.Name, DisableParallelToolUse: disableParallel,
+		}}, nil
+	case choice.OfAllowedTools != nil, choice.OfHostedTool != nil, choice.OfMcpTool != nil,
+		choice.OfCustomTool != nil, choice.OfApplyPatchTool != nil, choice.OfShellTool != nil:
+		return nil, fmt.Errorf("%w: only auto, required, none and function tool_choice are supported by this backend", internalapi.ErrInvalidRequestBody)
+	}
+	if disableParallel != nil {
+		return &anthropic.ToolChoice{Auto: &anthropic.ToolChoiceAuto{Type: "auto", DisableParallelToolUse: disableParallel}}, nil
+	}
+	return nil, nil
+}
+
+// responsesIDSuffix derives the suffix used for the Responses object and item IDs from the Anthropic message ID.
+func responsesIDSuffix(messageID string) string {
+	if messageID == "" {
+		return uuid.NewString()
+	}
+	return strings.TrimPrefix(messageID, "msg_")
+}
+
+// responsesItemID returns a stable output item ID for the given item kind and output index.
+func responsesItemID(prefix, suffix string, outputIndex int) string {
+	return prefix + "_" + suffix + "_" + strconv.Itoa(outputIndex)
+}
+
+// newResponsesObject returns a Responses object echoing the request parameters.
+func newResponsesObject(id, model string, req *openai.ResponseRequest, createdAt time.Time) *openai.Response {
+	resp := &openai.Response{
+		ID:                 id,
+		CreatedAt:          openai.JSONUNIXTime(createdAt),
+		Object:             "response",
+		Model:              model,
+		Metadata:           req.Metadata,
+		ParallelToolCalls:  req.ParallelToolCalls,
+		Temperature:        ptr.Deref(req.Temperature, 1),
+		TopP:               ptr.Deref(req.TopP, 1),
+		ToolChoice:         req.ToolChoice,
+		Tools:              req.Tools,
+		MaxOutputTokens:    req.MaxOutputTokens,
+		PreviousResponseID: req.PreviousResponseID,
+		Reasoning:          req.Reasoning,
+		Store:              req.Store,
+		Text:               openai.ResponseTextConfig{Format: req.Text.Format, Verbosity: req.Text.Verbosity},
+	}
+	if resp.Text.Format == (openai.ResponseFormatTextConfigUnionParam{}) {
+		resp.Text.Format.OfText = &openai.ResponseFormatTextParam{Type: "text"}
+	}
+	if req.Instructions != "" {
+		resp.Instructions.OfString = ptr.To(req.Instructions)
+	}
+	if resp.ToolChoice == (openai.ResponseToolChoiceUnion{}) {
+		resp.ToolChoice.OfToolChoiceMode = ptr.To("auto")
+	}
+	return resp
+}
+
+// anthropicUsageToResponsesUsage converts the Anthropic usage to the Responses usage. Unlike Anthropic,
+// the Responses input token count includes the cached tokens.
+func anthropicUsageToResponsesUsage(u *anthropic.Usage) *openai.ResponseUsage {
+	if u == nil {
+		return &openai.ResponseUsage{}
+	}
+	input := int64(u.InputTokens + u.CacheReadInputTokens + u.CacheCreationInputTokens)
+	output := int64(u.OutputTokens)
+	return &openai.ResponseUsage{
+		InputTokens: input,
+		InputTokensDetails: openai.ResponseUsageInputTokensDetails{
+			CachedTokens:        int64(u.CacheReadInputTokens),
+			CacheCreationTokens: int64(u.CacheCreationInputTokens),
+		},
+		OutputTokens: output,
+		TotalTokens:  input + output,
+	}
+}
+
+// setResponsesStatus sets the status of the Responses object from the Anthropic stop reason.
+func setResponsesStatus(resp *openai.Response, stopReason anthropic.StopReason) {
+	switch stopReason {
+	case anthropic.StopReasonMaxTokens, anthropic.StopReasonModelContextWindowExceeded:
+		resp.Status = "incomplete"
+		resp.IncompleteDetails.Reason = "max_output_tokens"
+	case anthropic.StopReasonRefusal:
+		resp.Status = "incomplete"
+		resp.IncompleteDetails.Reason = "content_filter"
+	default:
+		resp.Status = "completed"
+	}
+}
+
+// anthropicToResponsesResponse converts a non-streaming Anthropic Messages response to a Responses object.
+func anthropicToResponsesResponse(anthropicResp *anthropic.MessagesResponse, req *openai.ResponseRequest, createdAt time.Time) *openai.Response {
+	suffix := responsesIDSuffix(anthropicResp.ID)
+	resp := newResponsesObject("resp_"+suffix, cmp.Or(anthropicResp.Model, req.Model), req, createdAt)
+	for i := range anthropicResp.Content {
+		block := &anthropicResp.Content[i]
+		outputIndex := len(resp.Output)
+		switch {
+		case block.Text != nil:
+			resp.Output = append(resp.Output, openai.ResponseOutputItemUnion{
+				OfOutputMessage: responsesOutputMessage(responsesItemID("msg", suffix, outputIndex), block.Text.Text),
+			})
+		case block.Tool != nil:
+			input := block.Tool.Input
+			if input == nil {
+				input = map[string]any{}
+			}
+			args, _ := json.Marshal(input)
+			resp.Output = append(resp.Output, openai.ResponseOutputItemUnion{OfFunctionCall: &openai.ResponseFunctionToolCall{
+				ID:        responsesItemID("fc", suffix, outputIndex),
+				CallID:    block.Tool.ID,
+				Name:      block.Tool.Name,
+				Arguments: string(args),
+				Status:    "completed",
+				Type:      "function_call",
+			}})
+		case block.Thinking != nil:
+			resp.Output = append(resp.Output, openai.ResponseOutputItemUnion{
+				OfReasoning: responsesReasoningItem(responsesItemID("rs", suffix, outputIndex), block.Thinking.Thinking, block.Thinking.Signature),
+			})
+		}
+	}
+	setResponsesStatus(resp, ptr.Deref(anthropicResp.StopReason, anthropic.StopReasonEndTurn))
+	resp.CompletedAt = ptr.To(openai.JSONUNIXTime(time.Now()))
+	resp.Usage = anthropicUsageToResponsesUsage(anthropicResp.Usage)
+	return resp
+}
+
+// responsesOutputMessage returns a completed assistant output message with a single output_text part.
+func responsesOutputMessage(id, text string) *openai.ResponseOutputMessage {
+	return &openai.ResponseOutputMessage{
+		ID:     id,
+		Role:   "assistant",
+		Type:   "message",
+		Status: "completed",
+		Content: openai.ResponseOutputMessageContentUnion{OfContentArray: []openai.ResponseOutputMessageContentArrayUnion{
+			{OfOutputText: &openai.ResponseOutputTextParam{Type: "output_text", Text: text, Annotations: []openai.ResponseOutputTextAnnotationUnionParam{}}},
+		}},
+	}
+}
+
+// responsesReasoningItem returns a completed reasoning item. The thinking signature is carried in
+// encrypted_content so that the reasoning can be replayed to the backend in the following turns.
+func responsesReasoningItem(id, text, signature string) *openai.ResponseReasoningItem {
+	return &openai.ResponseReasoningItem{
+		ID:               id,
+		Type:             "reasoning",
+		Status:           "completed",
+		Summary:          []openai.ResponseReasoningItemSummaryParam{},
+		Content:          []openai.ResponseReasoningItemContentParam{{Type: "reasoning_text", Text: text}},
+		EncryptedContent: signature,
+	}
+}
+
+// anthropicToResponsesStreamState converts the Anthropic Messages SSE events to the Responses response.* events.
+type anthropicToResponsesStreamState struct {
+	req       *openai.ResponseRequest
+	createdAt time.Time
+	// buffered holds the incomplete SSE line carried over from the previous chunk.
+	buffered       []byte
+	suffix         string
+	model          string
+	sequenceNumber int64
+	// blocks maps the Anthropic content block index to the Responses output item being streamed.
+	blocks     map[int]*responsesStreamItem
+	output     []openai.ResponseOutputItemUnion
+	usage      anthropic.Usage
+	stopReason anthropic.StopReason
+	started    bool
+	done       bool
+}
+
+// responsesStreamItem is an output item whose content is being streamed.
+type responsesStreamItem struct {
+	outputIndex int
+	item        openai.ResponseOutputItemUnion
+	text        strings.Builder
+	signature   string
+}
+
+// convert converts the Anthropic SSE bytes to Responses SSE events.
+func (s *anthropicToResponsesStreamState) convert(body []byte, endOfStream bool, span tracingapi.ResponsesSpan) ([]byte, error) {
+	s.buffered = append(s.buffered, body...)
+	var out []byte
+	err := scanAnthropicStreamEvents(&s.buffered,
+		func(chunk *anthropic.MessagesStreamChunk) error {
+			return s.handleChunk(chunk, &out, span)
+		},
+		func(errEvent *anthropic.ErrorResponse) error {
+			return s.fail(errEvent.Error.Type, errEvent.Error.Message, &out, span)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if endOfStream && s.started && !s.done {
+		// The backend closed the stream without message_stop, e.g. on an upstream timeout.
+		if err := s.complete(&out, span); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// handleChunk converts a single Anthropic stream event.
+func (s *anthropicToResponsesStreamState) handleChunk(chunk *anthropic.MessagesStreamChunk, out *[]byte, span tracingapi.ResponsesSpan) error {
+	switch {
+	case chunk.MessageStart != nil:
+		s.started = true
+		s.suffix = responsesIDSuffix(chunk.MessageStart.ID)
+		s.model = cmp.Or(chunk.MessageStart.Model, s.req.Model)
+		s.blocks = make(map[int]*responsesStreamItem)
+		if u := chunk.MessageStart.Usage; u != nil {
+			s.usage = *u
+		}
+		resp := s.response("in_progress")
+		if err := s.emit(openai.ResponseStreamEventUnion{OfResponseCreated: &openai.ResponseCreatedEvent{
+			Type: "response.created", Response: *resp,
+		}}, out, span); err != nil {
+			return err
+		}
+		return s.emit(openai.ResponseStreamEventUnion{OfResponseInProgress: &openai.ResponseInProgressEvent{
+			Type: "response.in_progress", Response: *resp,
+		}}, out, span)
+	case chunk.ContentBlockStart != nil:
+		return s.startItem(chunk.ContentBlockStart, out, span)
+	case chunk.ContentBlockDelta != nil:
+		return s.delta(chunk.ContentBlockDelta, out, span)
+	case chunk.ContentBlockStop != nil:
+		return s.stopItem(chunk.ContentBlockStop.Index, out, span)
+	case chunk.MessageDelta != nil:
+		if chunk.MessageDelta.Delta.StopReason != "" {
+			s.stopReason = chunk.MessageDelta.Delta.StopReason
+		}
+		u := chunk.MessageDelta.Usage
+		s.usage.OutputTokens = u.OutputTokens
+		// Input token counts are only present here when the backend reports them at the end of the stream.
+		if u.InputTokens > 0 {
+			s.usage.InputTokens = u.InputTokens
+		}
+		if u.CacheReadInputTokens > 0 {
+			s.usage.CacheReadInputTokens = u.CacheReadInputTokens
+		}
+		if u.CacheCreationInputTokens > 0 {
+			s.usage.CacheCreationInputTokens = u.CacheCreationInputTokens
+		}
+	case chunk.MessageStop != nil:
+		return s.complete(out, span)
+	}
+	return nil
+}
+
+// startItem emits the output_item.added, and for text and reasoning the content_part.added, events.
+func (s *anthropicToResponsesStreamState) startItem(start *anthropic.MessagesStreamChunkContentBlockStart, out *[]byte, span tracingapi.ResponsesSpan) error {
+	outputIndex := len(s.output) + len(s.blocks)
+	item := &responsesStreamItem{outputIndex: outputIndex}
+	block := start.ContentBlock
+	switch {
+	case block.Text != nil:
+		item.item.OfOutputMessage = &openai.ResponseOutputMessage{
+			ID:      responsesItemID("msg", s.suffix, outputIndex),
+			Role:    "assistant",
+			Type:    "message",
+			Status:  "in_progress",
+			Content: openai.ResponseOutputMessageContentUnion{OfContentArray: []openai.ResponseOutputMessageContentArrayUnion{}},
+		}
+	case block.Thinking != nil:
+		item.item.OfReasoning = &openai.ResponseReasoningItem{
+			ID:      responsesItemID("rs", s.suffix, outputIndex),
+			Type:    "reasoning",
+			Status:  "in_progress",
+			Summary: []openai.ResponseReasoningItemSummaryParam{},
+		}
+	case block.Tool != nil:
+		item.item.OfFunctionCall = &openai.ResponseFunctionToolCall{
+			ID:     responsesItemID("fc", s.suffix, outputIndex),
+			CallID: block.Tool.ID,
+			Name:   block.Tool.Name,
+			Status: "in_progress",
+			Type:   "function_call",
+		}
+	default:
+		// Redacted thinking and server tool blocks have no Responses equivalent.
+		return nil
+	}
+	s.blocks[start.Index] = item
+	if err := s.emit(openai.ResponseStreamEventUnion{OfResponseOutputItemAdded: &openai.ResponseOutputItemAddedEvent{
+		Type: "response.output_item.added", OutputIndex: int64(outputIndex), Item: item.item,
+	}}, out, span); err != nil {
+		return err
+	}
+	added := &openai.ResponseContentPartAddedEvent{
+		Type: "response.content_part.added", OutputIndex: int64(outputIndex),
+	}
+	switch {
+	case item.item.OfOutputMessage != nil:
+		added.ItemID = item.item.OfOutputMessage.ID
+		added.Part.OfResponseOutputText = &openai.ResponseOutputTextParam{Type: "output_text", Annotations: []openai.ResponseOutputTextAnnotationUnionParam{}}
+	case item.item.OfReasoning != nil:
+		added.ItemID = item.item.OfReasoning.ID
+		added.Part.OfResponsContentPartAddedEventPartReasoningText = &openai.ResponseContentPartAddedEventPartReasoningText{Type: "reasoning_text"}
+	default:
+		return nil
+	}
+	return s.emit(openai.ResponseStreamEventUnion{OfResponseContentPartAdded: added}, out, span)
+}
+
+// delta emits the text, reasoning or function call argument delta events.
+func (s *anthropicToResponsesStreamState) delta(delta *anthropic.MessagesStreamChunkContentBlockDelta, out *[]byte, span tracingapi.ResponsesSpan) error {
+	item, ok := s.blocks[delta.Index]
+	if !ok {
+		return nil
+	}
+	outputIndex := int64(item.outputIndex)
+	switch {
+	case item.item.OfOutputMessage != nil && delta.Delta.Text != "":
+		item.text.WriteString(delta.Delta.Text)
+		return s.emit(openai.ResponseStreamEventUnion{OfResponseTextDelta: &openai.ResponseTextDeltaEvent{
+			Type: "response.output_text.delta", ItemID: item.item.OfOutputMessage.ID, OutputIndex: outputIndex, Delta: delta.Delta.Text,
+			Logprobs: []openai.ResponseTextDeltaEventLogprob{},
+		}}, out, span)
+	case item.item.OfReasoning != nil && delta.Delta.Thinking != "":
+		item.text.WriteString(delta.Delta.Thinking)
+		return s.emit(openai.ResponseStreamEventUnion{OfResponseReasoningTextDelta: &openai.ResponseReasoningTextDeltaEvent{
+			Type: "response.reasoning_text.delta", ItemID: item.item.OfReasoning.ID, OutputIndex: outputIndex, Delta: delta.Delta.Thinking,
+		}}, out, span)
+	case item.item.OfReasoning != nil && delta.Delta.Signature != "":
+		item.signature += delta.Delta.Signature
+	case item.item.OfFunctionCall != nil && delta.Delta.PartialJSON != "":
+		item.text.WriteString(delta.Delta.PartialJSON)
+		return s.emit(openai.ResponseStreamEventUnion{OfResponseFunctionCallArgumentsDelta: &openai.ResponseFunctionCallArgumentsDeltaEvent{
+			Type: "response.function_call_arguments.delta", ItemID: item.item.OfFunctionCall.ID, OutputIndex: outputIndex, Delta: delta.Delta.PartialJSON,
+		}}, out, span)
+	}
+	return nil
+}
+
+// stopItem emits the done events of the output item and records it in the response output.
+func (s *anthropicToResponsesStreamState) stopItem(index int, out *[]byte, span tracingapi.ResponsesSpan) error {
+	item, ok := s.blocks[index]
+	if !ok {
+		return nil
+	}
+	delete(s.blocks, index)
+	outputIndex := int64(item.outputIndex)
+	text := item.text.String()
+	var done openai.ResponseOutputItemUnion
+	switch {
+	case item.item.OfOutputMessage != nil:
+		done.OfOutputMessage = responsesOutputMessage(item.item.OfOutputMessage.ID, text)
+		part := done.OfOutputMessage.Content.OfContentArray[0].OfOutputText
+		if err := s.emit(openai.ResponseStreamEventUnion{OfResponseTextDone: &openai.ResponseTextDoneEvent{
+			Type: "response.output_text.done", ItemID: done.OfOutputMessage.ID, OutputIndex: outputIndex, Text: text,
+			Logprobs: []openai.ResponseTextDoneEventLogprob{},
+		}}, out, span); err != nil {
+			return err
+		}
+		if err := s.emit(openai.ResponseStreamEventUnion{OfResponseContentPartDone: &openai.ResponseContentPartDoneEvent{
+			Type: "response.content_part.done", ItemID: done.OfOutputMessage.ID, OutputIndex: outputIndex,
+			Part: openai.ResponseContentPartDoneEventPartUnion{OfResponseOutputText: part},
+		}}, out, span); err != nil {
+			return err
+		}
+	case item.item.OfReasoning != nil:
+		done.OfReasoning = responsesReasoningItem(item.item.OfReasoning.ID, text, item.signature)
+		if err := s.emit(openai.ResponseStreamEventUnion{OfResponseReasoningTextDone: &openai.ResponseReasoningTextDoneEvent{
+			Type: "response.reasoning_text.done", ItemID: done.OfReasoning.ID, OutputIndex: outputIndex, Text: text,
+		}}, out, span); err != nil {
+			return err
+		}
+		if err := s.emit(openai.ResponseStreamEventUnion{OfResponseContentPartDone: &openai.ResponseContentPartDoneEvent{
+			Type: "response.content_part.done", ItemID: done.OfReasoning.ID, OutputIndex: outputIndex,
+			Part: openai.ResponseContentPartDoneEventPartUnion{
+				OfResponsContentPartDoneEventPartReasoningText: &openai.ResponseContentPartDoneEventPartReasoningText{Type: "reasoning_text", Text: text},
+			},
+		}}, out, span); err != nil {
+			return err
+		}
+	case item.item.OfFunctionCall != nil:
+		call := *item.item.OfFunctionCall
+		call.Arguments = cmp.Or(text, "{}")
+		call.Status = "completed"
+		done.OfFunctionCall = &call
+		if err := s.emit(openai.ResponseStreamEventUnion{OfResponseFunctionCallArgumentsDone: &openai.ResponseFunctionCallArgumentsDoneEvent{
+			Type: "response.function_call_arguments.done", ItemID: call.ID, OutputIndex: outputIndex, Name: call.Name, Arguments: call.Arguments,
+		}}, out, span); err != nil {
+			return err
+		}
+	}
+	s.output = append(s.output, done)
+	return s.emit(openai.ResponseStreamEventUnion{OfResponseOutputItemDone: &openai.ResponseOutputItemDoneEvent{
+		Type: "response.output_item.done", OutputIndex: outputIndex, Item: done,
+	}}, out, span)
+}
+
+// complete emits the terminal response.completed or response.incomplete event.
+func (s *anthropicToResponsesStreamState) complete(out *[]byte, span tracingapi.ResponsesSpan) error {
+	s.done = true
+	resp := s.response("")
+	setResponsesStatus(resp, cmp.Or(s.stopReason, anthropic.StopReasonEndTurn))
+	resp.CompletedAt = ptr.To(openai.JSONUNIXTime(time.Now()))
+	resp.Usage = anthropicUsageToResponsesUsage(&s.usage)
+	if resp.Status == "incomplete" {
+		return s.emit(openai.ResponseStreamEventUnion{OfResponseIncomplete: &openai.ResponseIncompleteEvent{
+			Type: "response.incomplete", Response: *resp,
+		}}, out, span)
+	}
+	return s.emit(openai.ResponseStreamEventUnion{OfResponseCompleted: &openai.ResponseCompletedEvent{
+		Type: "response.completed", Response: *resp,
+	}}, out, span)
+}
+
+// fail emits the terminal response.failed event for an error reported in the middle of the stream.
+func (s *anthropicToResponsesStreamState) fail(code, message string, out *[]byte, span tracingapi.ResponsesSpan) error {
+	s.done = true
+	resp := s.response("failed")
+	resp.Error = openai.ResponseError{Code: cmp.Or(code, "server_error"), Message: message}
+	resp.Usage = anthropicUsageToResponsesUsage(&s.usage)
+	return s.emit(openai.ResponseStreamEventUnion{OfResponseFailed: &openai.ResponseFailedEvent{
+		Type: "response.failed", Response: *resp,
+	}}, out, span)
+}
+
+// response returns the Responses object for the stream with the output items completed so far.
+func (s *anthropicToResponsesStreamState) response(status string) *openai.Response {
+	suffix := cmp.Or(s.suffix, responsesIDSuffix(""))
+	s.suffix = suffix
+	resp := newResponsesObject("resp_"+suffix, cmp.Or(s.model, s.req.Model), s.req, s.createdAt)
+	resp.Status = status
+	resp.Output = append([]openai.ResponseOutputItemUnion{}, s.output...)
+	return resp
+}
+
+// emit assigns the sequence number to the event, records it on the span, and appends it as an SSE event.
+func (s *anthropicToResponsesStreamState) emit(event openai.ResponseStreamEventUnion, out *[]byte, span tracingapi.ResponsesSpan) error {
+	setResponsesEventSequenceNumber(&event, s.sequenceNumber)
+	s.sequenceNumber++
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", event.GetEventType(), err)
+	}
+	if span != nil {
+		span.RecordResponseChunk(&event)
+	}
+	*out = append(*out, "event: "...)
+	*out = append(*out, event.GetEventType()...)
+	*out = append(*out, '\n')
+	*out = append(*out, sseDataPrefix...)
+	*out = append(*out, data...)
+	*out = append(*out, '\n', '\n')
+	return nil
+}
+
+// setResponsesEventSequenceNumber sets the sequence number on the events emitted by [anthropicToResponsesStreamState].
+func setResponsesEventSequenceNumber(event *openai.ResponseStreamEventUnion, n int64) {
+	switch {
+	case event.OfResponseCreated != nil:
+		event.OfResponseCreated.SequenceNumber = n
+	case event.OfResponseInProgress != nil:
+		event.OfResponseInProgress.SequenceNumber = n
+	case event.OfResponseCompleted != nil:
+		event.OfResponseCompleted.SequenceNumber = n
+	case event.OfResponseIncomplete != nil:
+		event.OfResponseIncomplete.SequenceNumber = n
+	case event.OfResponseFailed != nil:
+		event.OfResponseFailed.SequenceNumber = n
+	case event.OfResponseOutputItemAdded != nil:
+		event.OfResponseOutputItemAdded.SequenceNumber = n
+	case event.OfResponseOutputItemDone != nil:
+		event.OfResponseOutputItemDone.SequenceNumber = n
+	case event.OfResponseContentPartAdded != nil:
+		event.OfResponseContentPartAdded.SequenceNumber = n
+	case event.OfResponseContentPartDone != nil:
+		event.OfResponseContentPartDone.SequenceNumber = n
+	case event.OfResponseTextDelta != nil:
+		event.OfResponseTextDelta.SequenceNumber = n
+	case event.OfResponseTextDone != nil:
+		event.OfResponseTextDone.SequenceNumber = n
+	case event.OfResponseReasoningTextDelta != nil:
+		event.OfResponseReasoningTextDelta.SequenceNumber = n
+	case event.OfResponseReasoningTextDone != nil:
+		event.OfResponseReasoningTextDone.SequenceNumber = n
+	case event.OfResponseFunctionCallArgumentsDelta != nil:
+		event.OfResponseFunctionCallArgumentsDelta.SequenceNumber = n
+	case event.OfResponseFunctionCallArgumentsDone != nil:
+		event.OfResponseFunctionCallArgumentsDone.SequenceNumber = n
+	}
+}
@@ -0,0 +1,410 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+)
+
+func TestResponsesToAnthropicMessagesRequest(t *testing.T) {
+	req := &openai.ResponseRequest{
+		Model:             "claude-sonnet-4-5",
+		Instructions:      "be nice",
+		MaxOutputTokens:   ptr.To(int64(8192)),
+		Temperature:       ptr.To(0.5),
+		ParallelToolCalls: ptr.To(false),
+		Stream:            true,
+		Reasoning:         openai.ReasoningParam{Effort: "medium"},
+		Input: openai.ResponseNewParamsInputUnion{OfInputItemList: []openai.ResponseInputItemUnionParam{
+			{OfMessage: &openai.EasyInputMessageParam{Role: "developer", Content: openai.EasyInputMessageContentUnionParam{OfString: ptr.To("answer briefly")}}},
+			{OfMessage: &openai.EasyInputMessageParam{Role: "user", Content: openai.EasyInputMessageContentUnionParam{
+				OfInputItemContentList: []openai.ResponseInputContentUnionParam{
+					{OfInputText: &openai.ResponseInputTextParam{Type: "input_text", Text: "what is cos(7)?"}},
+					{OfInputImage: &openai.ResponseInputImageParam{Type: "input_image", ImageURL: "data:image/png;base64,aGVsbG8="}},
+				},
+			}}},
+			{OfReasoning: &openai.ResponseReasoningItem{
+				ID: "rs_1", Type: "reasoning", EncryptedContent: "c2ln",
+				Summary: []openai.ResponseReasoningItemSummaryParam{{Type: "summary_text", Text: "use the tool"}},
+			}},
+			{OfReasoning: &openai.ResponseReasoningItem{ID: "rs_2", Type: "reasoning", Content: []openai.ResponseReasoningItemContentParam{{Text: "unsigned"}}}},
+			{OfFunctionCall: &openai.ResponseFunctionToolCall{Type: "function_call", CallID: "call-1", Name: "cosine", Arguments: `{"x":7}`}},
+			{OfFunctionCallOutput: &openai.ResponseInputItemFunctionCallOutputParam{
+				Type: "function_call_output", CallID: "call-1",
+				Output: openai.ResponseInputItemFunctionCallOutputOutputUnionParam{OfString: ptr.To("0.75")},
+			}},
+			{OfOutputMessage: &openai.ResponseOutputMessage{Type: "message", Role: "assistant", Content: openai.ResponseOutputMessageContentUnion{
+				OfContentArray: []openai.ResponseOutputMessageContentArrayUnion{{OfOutputText: &openai.ResponseOutputTextParam{Type: "output_text", Text: "0.75"}}},
+			}}},
+			{OfMessage: &openai.EasyInputMessageParam{Role: "user", Content: openai.EasyInputMessageContentUnionParam{OfString: ptr.To("thanks")}}},
+		}},
+		Tools: []openai.ResponseToolUnion{{OfFunction: &openai.FunctionToolParam{
+			Type: "function", Name: "cosine", Description: "computes cosine",
+			Parameters: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"x": map[string]any{"type": "number"}},
+				"required":   []any{"x"},
+			},
+		}}},
+		ToolChoice: openai.ResponseToolChoiceUnion{OfToolChoiceMode: ptr.To("required")},
+	}
+
+	out, err := responsesToAnthropicMessagesRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, &anthropic.MessagesRequest{
+		Model:       "claude-sonnet-4-5",
+		MaxTokens:   8192,
+		Temperature: ptr.To(0.5),
+		Stream:      true,
+		System:      &anthropic.SystemPrompt{Text: "be nice\n\nanswer briefly"},
+		Thinking:    &anthropic.Thinking{Enabled: &anthropic.ThinkingEnabled{Type: "enabled", BudgetTokens: 4096}},
+		Tools: []anthropic.ToolUnion{{Tool: &anthropic.Tool{
+			Type: "custom", Name: "cosine", Description: "computes cosine",
+			InputSchema: anthropic.ToolInputSchema{
+				Type:       "object",
+				Properties: map[string]any{"x": map[string]any{"type": "number"}},
+				Required:   []string{"x"},
+			},
+		}}},
+		ToolChoice: &anthropic.ToolChoice{Any: &anthropic.ToolChoiceAny{Type: "any", DisableParallelToolUse: ptr.To(true)}},
+		Messages: []anthropic.MessageParam{
+			{Role: anthropic.MessageRoleUser, Content: anthropic.MessageContent{Array: []anthropic.ContentBlockParam{
+				anthropicTextBlock("what is cos(7)?"),
+				{Image: &anthropic.ImageBlockParam{Type: "image", Source: anthropic.ImageSource{
+					Base64: &anthropic.Base64ImageSource{Type: "base64", MediaType: "image/png", Data: "aGVsbG8="},
+				}}},
+			}}},
+			{Role: anthropic.MessageRoleAssistant, Content: anthropic.MessageContent{Array: []anthropic.ContentBlockParam{
+				{Thinking: &anthropic.ThinkingBlockParam{Type: "thinking", Thinking: "use the tool", Signature: "c2ln"}},
+				{ToolUse: &anthropic.ToolUseBlockParam{Type: "tool_use", ID: "call-1", Name: "cosine", Input: map[string]any{"x": float64(7)}}},
+			}}},
+			{Role: anthropic.MessageRoleUser, Content: anthropic.MessageContent{Array: []anthropic.ContentBlockParam{
+				{ToolResult: &anthropic.ToolResultBlockParam{Type: "tool_result", ToolUseID: "call-1", Content: &anthropic.ToolResultContent{Text: "0.75"}}},
+			}}},
+			{Role: anthropic.MessageRoleAssistant, Content: anthropic.MessageContent{Array: []anthropic.ContentBlockParam{anthropicTextBlock("0.75")}}},
+			{Role: anthropic.MessageRoleUser, Content: anthropic.MessageContent{Array: []anthropic.ContentBlockParam{anthropicTextBlock("thanks")}}},
+		},
+	}, out)
+
+	t.Run("defaults", func(t *testing.T) {
+		out, err := responsesToAnthropicMessagesRequest(&openai.ResponseRequest{
+			Model:     "claude-sonnet-4-5",
+			Input:     openai.ResponseNewParamsInputUnion{OfString: ptr.To("hi")},
+			Reasoning: openai.ReasoningParam{Effort: "high"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, float64(defaultResponsesMaxTokens+16384), out.MaxTokens)
+		require.Equal(t, float64(16384), out.Thinking.Enabled.BudgetTokens)
+		require.Nil(t, out.ToolChoice)
+		require.Equal(t, []anthropic.MessageParam{
+			{Role: anthropic.MessageRoleUser, Content: anthropic.MessageContent{Array: []anthropic.ContentBlockParam{anthropicTextBlock("hi")}}},
+		}, out.Messages)
+	})
+
+	t.Run("thinking budget exceeds max output tokens", func(t *testing.T) {
+		out, err := responsesToAnthropicMessagesRequest(&openai.ResponseRequest{
+			Input:           openai.ResponseNewParamsInputUnion{OfString: ptr.To("hi")},
+			MaxOutputTokens: ptr.To(int64(512)),
+			Reasoning:       openai.ReasoningParam{Effort: "low"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, float64(512), out.MaxTokens)
+		require.Nil(t, out.Thinking)
+	})
+
+	for _, tc := range []struct {
+		name   string
+		req    *openai.ResponseRequest
+		errMsg string
+	}{
+		{
+			name:   "previous response id",
+			req:    &openai.ResponseRequest{PreviousResponseID: "resp_1"},
+			errMsg: "previous_response_id is not supported",
+		},
+		{
+			name:   "hosted tool",
+			req:    &openai.ResponseRequest{Tools: []openai.ResponseToolUnion{{OfWebSearch: &openai.WebSearchToolParam{}}}},
+			errMsg: "only function tools are supported",
+		},
+		{
+			name:   "invalid reasoning effort",
+			req:    &openai.ResponseRequest{Reasoning: openai.ReasoningParam{Effort: "extreme"}},
+			errMsg: "unsupported reasoning effort",
+		},
+		{
+			name: "invalid function call arguments",
+			req: &openai.ResponseRequest{Input: openai.ResponseNewParamsInputUnion{OfInputItemList: []openai.ResponseInputItemUnionParam{
+				{OfFunctionCall: &openai.ResponseFunctionToolCall{CallID: "call-1", Name: "cosine", Arguments: "{"}},
+			}}},
+			errMsg: "invalid arguments for function call call-1",
+		},
+		{
+			name: "image file id",
+			req: &openai.ResponseRequest{Input: openai.ResponseNewParamsInputUnion{OfInputItemList: []openai.ResponseInputItemUnionParam{
+				{OfInputMessage: &openai.ResponseInputItemMessageParam{Role: "user", Content: []openai.ResponseInputContentUnionParam{
+					{OfInputImage: &openai.ResponseInputImageParam{FileID: "file-1"}},
+				}}},
+			}}},
+			errMsg: "file_id is not supported",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := responsesToAnthropicMessagesRequest(tc.req)
+			require.ErrorIs(t, err, internalapi.ErrInvalidRequestBody)
+			require.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestResponsesToAnthropicTranslator_RequestBody(t *testing.T) {
+	tr := NewResponsesToAnthropicTranslator("", "claude-override")
+	headers, body, err := tr.RequestBody(nil, &openai.ResponseRequest{
+		Model: "claude-sonnet-4-5",
+		Input: openai.ResponseNewParamsInputUnion{OfString: ptr.To("hi")},
+	}, false)
+	require.NoError(t, err)
+	require.Equal(t, internalapi.Header{pathHeaderName, "/v1/messages"}, headers[0])
+	var anthropicReq anthropic.MessagesRequest
+	require.NoError(t, json.Unmarshal(body, &anthropicReq))
+	require.Equal(t, "claude-override", anthropicReq.Model)
+	require.Equal(t, float64(defaultResponsesMaxTokens), anthropicReq.MaxTokens)
+}
+
+func TestResponsesToAnthropicTranslator_ResponseBody_NonStreaming(t *testing.T) {
+	tr := NewResponsesToAnthropicTranslator("", "")
+	_, _, err := tr.RequestBody(nil, &openai.ResponseRequest{
+		Model:        "claude-sonnet-4-5",
+		Instructions: "be nice",
+		Input:        openai.ResponseNewParamsInputUnion{OfString: ptr.To("what is cos(7)?")},
+	}, false)
+	require.NoError(t, err)
+
+	anthropicResp := `{"id":"msg_01","type":"message","role":"assistant","model":"claude-sonnet-4-5-20250929",
+"content":[{"type":"thinking","thinking":"use the tool","signature":"c2ln"},{"type":"text","text":"Let me compute."},{"type":"tool_use","id":"toolu_1","name":"cosine","input":{"x":7}}],
+"stop_reason":"tool_use","usage":{"input_tokens":10,"output_tokens":20,"cache_read_input_tokens":5,"cache_creation_input_tokens":3}}`
+	headers, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, strings.NewReader(anthropicResp), true, nil)
+	require.NoError(t, err)
+	require.Equal(t, "claude-sonnet-4-5-20250929", responseModel)
+	require.Len(t, headers, 1)
+	require.Equal(t, contentLengthHeaderName, headers[0].Key())
+	inputTokens, _ := tokenUsage.InputTokens()
+	require.Equal(t, uint32(18), inputTokens)
+
+	var resp openai.Response
+	require.NoError(t, json.Unmarshal(body, &resp))
+	require.Equal(t, "resp_01", resp.ID)
+	require.Equal(t, "response", resp.Object)
+	require.Equal(t, "completed", resp.Status)
+	require.Equal(t, "claude-sonnet-4-5-20250929", resp.Model)
+	require.Equal(t, "be nice", *resp.Instructions.OfString)
+	require.Equal(t, &openai.ResponseUsage{
+		InputTokens:         18,
+		InputTokensDetails:  openai.ResponseUsageInputTokensDetails{CachedTokens: 5, CacheCreationTokens: 3},
+		OutputTokens:        20,
+		OutputTokensDetails: openai.ResponseUsageOutputTokensDetails{},
+		TotalTokens:         38,
+	}, resp.Usage)
+
+	require.Len(t, resp.Output, 3)
+	require.Equal(t, "rs_01_0", resp.Output[0].OfReasoning.ID)
+	require.Equal(t, "c2ln", resp.Output[0].OfReasoning.EncryptedContent)
+	require.Equal(t, "use the tool", resp.Output[0].OfReasoning.Content[0].Text)
+	require.Equal(t, "msg_01_1", resp.Output[1].OfOutputMessage.ID)
+	require.Equal(t, "Let me compute.", resp.Output[1].OfOutputMessage.Content.OfContentArray[0].OfOutputText.Text)
+	require.Equal(t, &openai.ResponseFunctionToolCall{
+		ID: "fc_01_2", CallID: "toolu_1", Name: "cosine", Arguments: `{"x":7}`, Status: "completed", Type: "function_call",
+	}, resp.Output[2].OfFunctionCall)
+
+	t.Run("max tokens", func(t *testing.T) {
+		_, body, _, _, err := tr.ResponseBody(nil, strings.NewReader(
+			`{"id":"msg_02","type":"message","role":"assistant","model":"claude","content":[{"type":"text","text":"Hel"}],"stop_reason":"max_tokens","usage":{"input_tokens":1,"output_tokens":1}}`,
+		), true, nil)
+		require.NoError(t, err)
+		var resp openai.Response
+		require.NoError(t, json.Unmarshal(body, &resp))
+		require.Equal(t, "incomplete", resp.Status)
+		require.Equal(t, "max_output_tokens", resp.IncompleteDetails.Reason)
+	})
+}
+
+func TestResponsesToAnthropicTranslator_ResponseBody_Streaming(t *testing.T) {
+	tr := NewResponsesToAnthropicTranslator("", "")
+	_, _, err := tr.RequestBody(nil, &openai.ResponseRequest{
+		Model:  "claude-sonnet-4-5",
+		Stream: true,
+		Input:  openai.ResponseNewParamsInputUnion{OfString: ptr.To("what is cos(7)?")},
+	}, false)
+	require.NoError(t, err)
+	headers, err := tr.ResponseHeaders(nil)
+	require.NoError(t, err)
+	require.Equal(t, []internalapi.Header{{contentTypeHeaderName, eventStreamContentType}}, headers)
+
+	stream := []byte(`event: message_start
+data: {"type":"message_start","message":{"id":"msg_01","type":"message","role":"assistant","content":[],"model":"claude-sonnet-4-5","usage":{"input_tokens":10,"output_tokens":1}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"use the tool"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"c2ln"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: ping
+data: {"type":"ping"}
+
+event: content_block_start
+data: {"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Hello"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":1}
+
+event: content_block_start
+data: {"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"toolu_1","name":"cosine","input":{}}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"{\"x\":7}"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":2}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":15}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`)
+	// Feed the stream in small chunks to exercise the buffering of partial lines.
+	var out []byte
+	for i := 0; i < len(stream); i += 50 {
+		_, body, _, _, err := tr.ResponseBody(nil, bytes.NewReader(stream[i:min(i+50, len(stream))]), false, nil)
+		require.NoError(t, err)
+		out = append(out, body...)
+	}
+	_, body, tokenUsage, responseModel, err := tr.ResponseBody(nil, bytes.NewReader(nil), true, nil)
+	require.NoError(t, err)
+	out = append(out, body...)
+	require.Equal(t, "claude-sonnet-4-5", responseModel)
+	outputTokens, _ := tokenUsage.OutputTokens()
+	require.Equal(t, uint32(15), outputTokens)
+
+	events := parseSSEEventsFromBytes(out)
+	var types []string
+	for i, e := range events {
+		var event openai.ResponseStreamEventUnion
+		require.NoError(t, json.Unmarshal([]byte(e.data), &event), e.data)
+		require.Equal(t, e.eventType, event.GetEventType())
+		require.Equal(t, int64(i), gjson.Get(e.data, "sequence_number").Int())
+		types = append(types, e.eventType)
+	}
+	require.Equal(t, []string{
+		"response.created",
+		"response.in_progress",
+		"response.output_item.added",
+		"response.content_part.added",
+		"response.reasoning_text.delta",
+		"response.reasoning_text.done",
+		"response.content_part.done",
+		"response.output_item.done",
+		"response.output_item.added",
+		"response.content_part.added",
+		"response.output_text.delta",
+		"response.output_text.done",
+		"response.content_part.done",
+		"response.output_item.done",
+		"response.output_item.added",
+		"response.function_call_arguments.delta",
+		"response.function_call_arguments.done",
+		"response.output_item.done",
+		"response.completed",
+	}, types)
+
+	require.JSONEq(t, `{"type":"response.output_text.delta","item_id":"msg_01_1","output_index":1,"content_index":0,"delta":"Hello","logprobs":[],"sequence_number":10}`, events[10].data)
+	require.JSONEq(t, `{"type":"response.function_call_arguments.done","item_id":"fc_01_2","output_index":2,"name":"cosine","arguments":"{\"x\":7}","sequence_number":16}`, events[16].data)
+
+	var completed openai.ResponseStreamEventUnion
+	require.NoError(t, json.Unmarshal([]byte(events[len(events)-1].data), &completed))
+	resp := completed.OfResponseCompleted.Response
+	require.Equal(t, "resp_01", resp.ID)
+	require.Equal(t, "completed", resp.Status)
+	require.Len(t, resp.Output, 3)
+	require.Equal(t, "c2ln", resp.Output[0].OfReasoning.EncryptedContent)
+	require.Equal(t, "Hello", resp.Output[1].OfOutputMessage.Content.OfContentArray[0].OfOutputText.Text)
+	require.Equal(t, `{"x":7}`, resp.Output[2].OfFunctionCall.Arguments)
+	require.Equal(t, int64(10), resp.Usage.InputTokens)
+	require.Equal(t, int64(15), resp.Usage.OutputTokens)
+	require.Equal(t, int64(25), resp.Usage.TotalTokens)
+
+	t.Run("error event", func(t *testing.T) {
+		tr := NewResponsesToAnthropicTranslator("", "")
+		_, _, err := tr.RequestBody(nil, &openai.ResponseRequest{Stream: true, Input: openai.ResponseNewParamsInputUnion{OfString: ptr.To("hi")}}, false)
+		require.NoError(t, err)
+		_, body, _, _, err := tr.ResponseBody(nil, strings.NewReader(`event: message_start
+data: {"type":"message_start","message":{"id":"msg_01","type":"message","role":"assistant","content":[],"model":"claude","usage":{"input_tokens":1,"output_tokens":0}}}
+
+event: error
+data: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}
+
+`), true, nil)
+		require.NoError(t, err)
+		events := parseSSEEventsFromBytes(body)
+		require.Len(t, events, 3)
+		require.Equal(t, "response.failed", events[2].eventType)
+		var failed openai.ResponseStreamEventUnion
+		require.NoError(t, json.Unmarshal([]byte(events[2].data), &failed))
+		require.Equal(t, openai.ResponseError{Code: "overloaded_error", Message: "Overloaded"}, failed.OfResponseFailed.Response.Error)
+	})
+}
+
+func TestResponsesToAnthropicTranslator_ResponseError(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		headers  map[string]string
+		body     string
+		expected string
+	}{
+		{
+			name:     "anthropic error",
+			headers:  map[string]string{statusHeaderName: "429", contentTypeHeaderName: jsonContentType},
+			body:     `{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`,
+			expected: `{"type":"error","error":{"type":"rate_limit_error","message":"slow down","code":"429"}}`,
+		},
+		{
+			name:     "non json error",
+			headers:  map[string]string{statusHeaderName: "503"},
+			body:     "upstream connect error",
+			expected: `{"type":"error","error":{"type":"service_unavailable_error","message":"upstream connect error","code":"503"}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := NewResponsesToAnthropicTranslator("", "")
+			headers, body, err := tr.ResponseError(tc.headers, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expected, string(body))
+			require.Equal(t, internalapi.Header{contentTypeHeaderName, jsonContentType}, headers[0])
+		})
+	}
+}
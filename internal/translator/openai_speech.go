@@ -81,17 +81,22 @@ func (o *openAIToOpenAITranslatorV1Speech) ResponseHeaders(_ map[string]string)
 }
 
 // ResponseBody implements [OpenAISpeechTranslator.ResponseBody].
-// Speech responses are either binary audio or SSE streaming chunks.
-func (o *openAIToOpenAITranslatorV1Speech) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.SpeechSpan) (
+// Speech responses are either binary audio or SSE streaming chunks. Each response is one generated audio output,
+// which is reported as the audio count at the end of the stream so that it can be priced.
+func (o *openAIToOpenAITranslatorV1Speech) ResponseBody(_ map[string]string, body io.Reader, endOfStream bool, span tracingapi.SpeechSpan) (
 	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
 ) {
 	if o.stream {
 		// Handle SSE streaming response
-		return o.handleStreamingResponse(body, span)
+		newHeaders, newBody, tokenUsage, responseModel, err = o.handleStreamingResponse(body, span)
+	} else {
+		// Handle binary audio response - just pass through the audio bytes
+		newHeaders, newBody, tokenUsage, responseModel, err = o.handleBinaryResponse(body, span)
 	}
-
-	// Handle binary audio response - just pass through the audio bytes
-	return o.handleBinaryResponse(body, span)
+	if err == nil && endOfStream {
+		tokenUsage.SetAudioCount(1)
+	}
+	return
 }
 
 // handleStreamingResponse handles SSE streaming responses from the Speech API.
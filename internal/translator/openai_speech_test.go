@@ -135,7 +135,10 @@ func TestOpenAIToOpenAISpeechTranslator_ResponseBody_BinaryAudio(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, hm)
 	require.Nil(t, bm)
-	require.Equal(t, tokenUsageFrom(-1, -1, -1, -1, -1, -1), usage)
+	// Each response is one generated audio output.
+	expUsage := tokenUsageFrom(-1, -1, -1, -1, -1, -1)
+	expUsage.SetAudioCount(1)
+	require.Equal(t, expUsage, usage)
 	require.Equal(t, "tts-1", respModel)
 }
 
@@ -164,7 +167,10 @@ data: [DONE]
 	require.NoError(t, err)
 	require.Nil(t, hm)
 	require.Nil(t, bm)
-	require.Equal(t, tokenUsageFrom(-1, -1, -1, -1, -1, -1), usage)
+	// Each response is one generated audio output.
+	expUsage := tokenUsageFrom(-1, -1, -1, -1, -1, -1)
+	expUsage.SetAudioCount(1)
+	require.Equal(t, expUsage, usage)
 	require.Equal(t, "gpt-4o-mini-tts", respModel)
 }
 
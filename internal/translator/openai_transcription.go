@@ -0,0 +1,184 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/internalapi"
+	"github.com/envoyproxy/ai-gateway/internal/json"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+	"github.com/envoyproxy/ai-gateway/internal/multipartform"
+	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
+)
+
+// NewAudioTranscriptionOpenAIToOpenAITranslator implements [OpenAITranscriptionTranslator] for OpenAI to OpenAI
+// translation for /v1/audio/transcriptions.
+func NewAudioTranscriptionOpenAIToOpenAITranslator(prefix string, modelNameOverride internalapi.ModelNameOverride) OpenAITranscriptionTranslator {
+	return &openAIToOpenAITranslatorV1Transcription{
+		modelNameOverride: modelNameOverride,
+		path:              path.Join("/", prefix, "audio", "transcriptions"),
+	}
+}
+
+// NewAudioTranslationOpenAIToOpenAITranslator implements [OpenAITranscriptionTranslator] for OpenAI to OpenAI
+// translation for /v1/audio/translations.
+func NewAudioTranslationOpenAIToOpenAITranslator(prefix string, modelNameOverride internalapi.ModelNameOverride) OpenAITranscriptionTranslator {
+	return &openAIToOpenAITranslatorV1Transcription{
+		modelNameOverride: modelNameOverride,
+		path:              path.Join("/", prefix, "audio", "translations"),
+	}
+}
+
+// openAIToOpenAITranslatorV1Transcription is a passthrough translator for the OpenAI speech-to-text endpoints.
+// May apply model overrides but otherwise preserves the OpenAI format:
+// https://platform.openai.com/docs/api-reference/audio/createTranscription
+type openAIToOpenAITranslatorV1Transcription struct {
+	modelNameOverride internalapi.ModelNameOverride
+	// The path of the endpoint to be used for the request. It is prefixed with the OpenAI path prefix.
+	path string
+	// stream indicates whether the request is for SSE streaming.
+	stream bool
+	// jsonResponse indicates whether the response is in the json or verbose_json format, i.e. it can be parsed.
+	jsonResponse bool
+	// requestModel stores the model from the request to use in the response.
+	requestModel internalapi.RequestModel
+	// buffered is the incomplete line of the SSE stream.
+	buffered []byte
+	// streamingTokenUsage is the usage reported by the stream so far.
+	streamingTokenUsage metrics.TokenUsage
+}
+
+// RequestBody implements [OpenAITranscriptionTranslator.RequestBody].
+func (o *openAIToOpenAITranslatorV1Transcription) RequestBody(original []byte, req *openai.TranscriptionRequest, forceBodyMutation bool) (
+	newHeaders []internalapi.Header, newBody []byte, err error,
+) {
+	// Store the request model to use as response model.
+	o.requestModel = req.Model
+	if o.modelNameOverride != "" {
+		// If modelNameOverride is set, we override the model form field to be used for the request.
+		newBody, err = multipartform.SetField(original, "model", o.modelNameOverride)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to set model: %w", err)
+		}
+		o.requestModel = o.modelNameOverride
+	}
+
+	o.stream = req.Stream
+	o.jsonResponse = req.ResponseFormat == nil || *req.ResponseFormat == "json" || *req.ResponseFormat == "verbose_json"
+
+	// Always set the path header to the endpoint so that the request is routed correctly.
+	newHeaders = []internalapi.Header{{pathHeaderName, o.path}}
+
+	if forceBodyMutation && len(newBody) == 0 {
+		newBody = original
+	}
+
+	if len(newBody) > 0 {
+		newHeaders = append(newHeaders, internalapi.Header{contentLengthHeaderName, strconv.Itoa(len(newBody))})
+	}
+	return
+}
+
+// ResponseHeaders implements [OpenAITranscriptionTranslator.ResponseHeaders].
+func (o *openAIToOpenAITranslatorV1Transcription) ResponseHeaders(map[string]string) (newHeaders []internalapi.Header, err error) {
+	return nil, nil
+}
+
+// ResponseBody implements [OpenAITranscriptionTranslator.ResponseBody].
+//
+// The response body is passed through as is. The usage is either the audio duration or the number of tokens
+// depending on the model, and is reported in the final event for streaming responses.
+func (o *openAIToOpenAITranslatorV1Transcription) ResponseBody(_ map[string]string, body io.Reader, _ bool, span tracingapi.TranscriptionSpan) (
+	newHeaders []internalapi.Header, newBody []byte, tokenUsage metrics.TokenUsage, responseModel internalapi.ResponseModel, err error,
+) {
+	responseModel = o.requestModel
+	if o.stream {
+		var buf []byte
+		buf, err = io.ReadAll(body)
+		if err != nil {
+			return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to read body: %w", err)
+		}
+		o.buffered = append(o.buffered, buf...)
+		o.extractUsageFromBufferEvent(span)
+		return nil, nil, o.streamingTokenUsage, responseModel, nil
+	}
+
+	if !o.jsonResponse {
+		// The text, srt and vtt formats carry no usage.
+		return nil, nil, tokenUsage, responseModel, nil
+	}
+	var resp openai.TranscriptionResponse
+	if err = json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, nil, tokenUsage, responseModel, fmt.Errorf("failed to unmarshal body: %w", err)
+	}
+	tokenUsage = transcriptionTokenUsage(resp.Usage, resp.Duration)
+	if span != nil {
+		span.RecordResponse(&resp)
+	}
+	return nil, nil, tokenUsage, responseModel, nil
+}
+
+// extractUsageFromBufferEvent extracts the usage from the complete lines of the buffered SSE stream.
+func (o *openAIToOpenAITranslatorV1Transcription) extractUsageFromBufferEvent(span tracingapi.TranscriptionSpan) {
+	for {
+		i := bytes.IndexByte(o.buffered, '\n')
+		if i == -1 {
+			return
+		}
+		line := bytes.TrimSpace(o.buffered[:i])
+		o.buffered = o.buffered[i+1:]
+		data, ok := bytes.CutPrefix(line, bytes.TrimSpace(sseDataPrefix))
+		if !ok {
+			continue
+		}
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 || bytes.Equal(data, sseDoneMessage) {
+			continue
+		}
+		var event openai.TranscriptionStreamEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			continue
+		}
+		if span != nil {
+			span.RecordResponseChunk(&event)
+		}
+		if event.Type == openai.TranscriptionStreamEventTypeDone && event.Usage != nil {
+			o.streamingTokenUsage = transcriptionTokenUsage(event.Usage, nil)
+		}
+	}
+}
+
+// transcriptionTokenUsage converts the usage of a transcription to the token usage. The audio duration is rounded
+// up to the whole second, and taken from the top level duration of the verbose_json format when the usage is
+// not based on the duration.
+func transcriptionTokenUsage(usage *openai.TranscriptionUsage, duration *float64) (tokenUsage metrics.TokenUsage) {
+	if usage != nil {
+		switch usage.Type {
+		case openai.TranscriptionUsageTypeTokens:
+			tokenUsage.SetInputTokens(uint32(usage.InputTokens))   //nolint:gosec
+			tokenUsage.SetOutputTokens(uint32(usage.OutputTokens)) //nolint:gosec
+			tokenUsage.SetTotalTokens(uint32(usage.TotalTokens))   //nolint:gosec
+		case openai.TranscriptionUsageTypeDuration:
+			duration = &usage.Seconds
+		}
+	}
+	if duration != nil {
+		tokenUsage.SetAudioDurationSeconds(uint32(math.Ceil(*duration))) //nolint:gosec
+	}
+	return
+}
+
+// ResponseError implements [OpenAITranscriptionTranslator.ResponseError].
+func (o *openAIToOpenAITranslatorV1Transcription) ResponseError(respHeaders map[string]string, body io.Reader) ([]internalapi.Header, []byte, error) {
+	return convertErrorOpenAIToOpenAIError(respHeaders, body)
+}
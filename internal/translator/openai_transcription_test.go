@@ -0,0 +1,254 @@
+// Copyright Envoy AI Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/metrics"
+)
+
+// transcriptionForm returns a multipart/form-data body with the given fields and an audio file.
+func transcriptionForm(t *testing.T, fields ...string) []byte {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for i := 0; i < len(fields); i += 2 {
+		require.NoError(t, w.WriteField(fields[i], fields[i+1]))
+	}
+	fw, err := w.CreateFormFile("file", "speech.mp3")
+	require.NoError(t, err)
+	_, err = fw.Write([]byte("\r\n--not-a-boundary\r\n\r\naudio"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// formFields returns the form fields and the file content of the multipart/form-data body.
+func formFields(t *testing.T, body []byte) map[string]string {
+	boundary, _, _ := bytes.Cut(body[2:], []byte("\r\n"))
+	r := multipart.NewReader(bytes.NewReader(body), string(boundary))
+	fields := map[string]string{}
+	for {
+		part, err := r.NextPart()
+		if errors.Is(err, io.EOF) {
+			return fields
+		}
+		require.NoError(t, err)
+		value, err := io.ReadAll(part)
+		require.NoError(t, err)
+		fields[part.FormName()] = string(value)
+	}
+}
+
+func TestOpenAIToOpenAITranscriptionTranslator_RequestBody(t *testing.T) {
+	t.Run("transcriptions path", func(t *testing.T) {
+		tr := NewAudioTranscriptionOpenAIToOpenAITranslator("v1", "")
+		original := transcriptionForm(t, "model", "whisper-1")
+		hm, bm, err := tr.RequestBody(original, &openai.TranscriptionRequest{Model: "whisper-1"}, false)
+		require.NoError(t, err)
+		require.Nil(t, bm)
+		require.Len(t, hm, 1)
+		require.Equal(t, pathHeaderName, hm[0].Key())
+		require.Equal(t, "/v1/audio/transcriptions", hm[0].Value())
+	})
+	t.Run("translations path with force mutation", func(t *testing.T) {
+		tr := NewAudioTranslationOpenAIToOpenAITranslator("v1", "")
+		original := transcriptionForm(t, "model", "whisper-1")
+		hm, bm, err := tr.RequestBody(original, &openai.TranscriptionRequest{Model: "whisper-1"}, true)
+		require.NoError(t, err)
+		require.Equal(t, original, bm)
+		require.Len(t, hm, 2)
+		require.Equal(t, "/v1/audio/translations", hm[0].Value())
+		require.Equal(t, contentLengthHeaderName, hm[1].Key())
+	})
+	t.Run("model override", func(t *testing.T) {
+		tr := NewAudioTranscriptionOpenAIToOpenAITranslator("v1", "gpt-4o-transcribe")
+		original := transcriptionForm(t, "model", "whisper-1", "language", "en")
+		hm, bm, err := tr.RequestBody(original, &openai.TranscriptionRequest{Model: "whisper-1"}, false)
+		require.NoError(t, err)
+		require.Len(t, hm, 2)
+		require.Equal(t, contentLengthHeaderName, hm[1].Key())
+		require.Equal(t, map[string]string{
+			"model":    "gpt-4o-transcribe",
+			"language": "en",
+			"file":     "\r\n--not-a-boundary\r\n\r\naudio",
+		}, formFields(t, bm))
+		// The original body must not be modified so that the translation is idempotent on retries.
+		require.Equal(t, "whisper-1", formFields(t, original)["model"])
+	})
+	t.Run("model override without model field", func(t *testing.T) {
+		tr := NewAudioTranscriptionOpenAIToOpenAITranslator("v1", "gpt-4o-transcribe")
+		_, bm, err := tr.RequestBody(transcriptionForm(t), &openai.TranscriptionRequest{}, false)
+		require.NoError(t, err)
+		// The model field is appended when the form does not have one.
+		require.Equal(t, "gpt-4o-transcribe", formFields(t, bm)["model"])
+	})
+	t.Run("model override with invalid body", func(t *testing.T) {
+		tr := NewAudioTranscriptionOpenAIToOpenAITranslator("v1", "gpt-4o-transcribe")
+		_, _, err := tr.RequestBody([]byte(`{"model":"whisper-1"}`), &openai.TranscriptionRequest{}, false)
+		require.ErrorContains(t, err, "failed to set model: missing multipart boundary")
+	})
+}
+
+func TestOpenAIToAzureOpenAITranscriptionTranslator_RequestBody(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		tr           OpenAITranscriptionTranslator
+		expectedPath string
+	}{
+		{
+			name:         "transcriptions",
+			tr:           NewAudioTranscriptionOpenAIToAzureOpenAITranslator("2025-03-01-preview", ""),
+			expectedPath: "/openai/deployments/whisper/audio/transcriptions?api-version=2025-03-01-preview",
+		},
+		{
+			name:         "translations",
+			tr:           NewAudioTranslationOpenAIToAzureOpenAITranslator("2025-03-01-preview", ""),
+			expectedPath: "/openai/deployments/whisper/audio/translations?api-version=2025-03-01-preview",
+		},
+		{
+			name:         "model override",
+			tr:           NewAudioTranscriptionOpenAIToAzureOpenAITranslator("2025-03-01-preview", "whisper-deployment"),
+			expectedPath: "/openai/deployments/whisper-deployment/audio/transcriptions?api-version=2025-03-01-preview",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			hm, _, err := tc.tr.RequestBody(transcriptionForm(t, "model", "whisper"), &openai.TranscriptionRequest{Model: "whisper"}, false)
+			require.NoError(t, err)
+			require.Equal(t, pathHeaderName, hm[0].Key())
+			require.Equal(t, tc.expectedPath, hm[0].Value())
+		})
+	}
+}
+
+func TestOpenAIToOpenAITranscriptionTranslator_ResponseBody(t *testing.T) {
+	withAudioDuration := func(usage metrics.TokenUsage, seconds uint32) metrics.TokenUsage {
+		usage.SetAudioDurationSeconds(seconds)
+		return usage
+	}
+	for _, tc := range []struct {
+		name          string
+		format        *string
+		body          string
+		expectedUsage metrics.TokenUsage
+	}{
+		{
+			name:          "duration usage",
+			body:          `{"text":"hello","usage":{"type":"duration","seconds":61.2}}`,
+			expectedUsage: withAudioDuration(metrics.TokenUsage{}, 62),
+		},
+		{
+			name:          "tokens usage",
+			body:          `{"text":"hello","usage":{"type":"tokens","input_tokens":14,"output_tokens":45,"total_tokens":59}}`,
+			expectedUsage: tokenUsageFrom(14, -1, -1, 45, 59, -1),
+		},
+		{
+			name:          "verbose json",
+			format:        ptr.To("verbose_json"),
+			body:          `{"task":"transcribe","language":"english","duration":8.47,"text":"hello","segments":[]}`,
+			expectedUsage: withAudioDuration(metrics.TokenUsage{}, 9),
+		},
+		{
+			name:          "text format",
+			format:        ptr.To("text"),
+			body:          "hello\n",
+			expectedUsage: metrics.TokenUsage{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := NewAudioTranscriptionOpenAIToOpenAITranslator("v1", "")
+			req := &openai.TranscriptionRequest{Model: "whisper-1", ResponseFormat: tc.format}
+			_, _, err := tr.RequestBody(transcriptionForm(t, "model", "whisper-1"), req, false)
+			require.NoError(t, err)
+
+			hm, bm, usage, respModel, err := tr.ResponseBody(nil, strings.NewReader(tc.body), true, nil)
+			require.NoError(t, err)
+			require.Nil(t, hm)
+			require.Nil(t, bm)
+			require.Equal(t, tc.expectedUsage, usage)
+			require.Equal(t, "whisper-1", respModel)
+		})
+	}
+
+	t.Run("invalid json", func(t *testing.T) {
+		tr := NewAudioTranscriptionOpenAIToOpenAITranslator("v1", "")
+		_, _, err := tr.RequestBody(transcriptionForm(t, "model", "whisper-1"), &openai.TranscriptionRequest{Model: "whisper-1"}, false)
+		require.NoError(t, err)
+		_, _, _, _, err = tr.ResponseBody(nil, strings.NewReader("{"), true, nil)
+		require.ErrorContains(t, err, "failed to unmarshal body")
+	})
+
+	t.Run("records span", func(t *testing.T) {
+		span := &mockTranscriptionSpan{}
+		tr := NewAudioTranscriptionOpenAIToOpenAITranslator("v1", "")
+		_, _, err := tr.RequestBody(transcriptionForm(t, "model", "whisper-1"), &openai.TranscriptionRequest{Model: "whisper-1"}, false)
+		require.NoError(t, err)
+		_, _, _, _, err = tr.ResponseBody(nil, strings.NewReader(`{"text":"hello"}`), true, span)
+		require.NoError(t, err)
+		require.Equal(t, &openai.TranscriptionResponse{Text: "hello"}, span.recordedResponse)
+	})
+}
+
+func TestOpenAIToOpenAITranscriptionTranslator_ResponseBody_Streaming(t *testing.T) {
+	span := &mockTranscriptionSpan{}
+	tr := NewAudioTranscriptionOpenAIToOpenAITranslator("v1", "gpt-4o-transcribe")
+	req := &openai.TranscriptionRequest{Model: "gpt-4o-mini-transcribe", Stream: true}
+	_, _, err := tr.RequestBody(transcriptionForm(t, "model", "gpt-4o-mini-transcribe", "stream", "true"), req, false)
+	require.NoError(t, err)
+
+	stream := "data: {\"type\":\"transcript.text.delta\",\"delta\":\"I\"}\n\n" +
+		"data: {\"type\":\"transcript.text.delta\",\"delta\":\" see\"}\n\n" +
+		"data: {\"type\":\"transcript.text.done\",\"text\":\"I see\",\"usage\":{\"type\":\"tokens\",\"input_tokens\":14,\"output_tokens\":2,\"total_tokens\":16}}\n\n"
+	// Split the stream in the middle of the final event to ensure the incomplete line is buffered.
+	split := len(stream) - 20
+
+	hm, bm, usage, respModel, err := tr.ResponseBody(nil, strings.NewReader(stream[:split]), false, span)
+	require.NoError(t, err)
+	require.Nil(t, hm)
+	require.Nil(t, bm)
+	require.Equal(t, metrics.TokenUsage{}, usage)
+	require.Equal(t, "gpt-4o-transcribe", respModel)
+	require.Len(t, span.recordedChunks, 2)
+
+	_, _, usage, _, err = tr.ResponseBody(nil, strings.NewReader(stream[split:]), true, span)
+	require.NoError(t, err)
+	require.Equal(t, tokenUsageFrom(14, -1, -1, 2, 16, -1), usage)
+	require.Len(t, span.recordedChunks, 3)
+	require.Equal(t, "I see", span.recordedChunks[2].Text)
+}
+
+func TestOpenAIToOpenAITranscriptionTranslator_ResponseError(t *testing.T) {
+	tr := NewAudioTranscriptionOpenAIToOpenAITranslator("v1", "")
+	headers := map[string]string{contentTypeHeaderName: "text/plain", statusHeaderName: "503"}
+	hm, bm, err := tr.ResponseError(headers, strings.NewReader("service unavailable"))
+	require.NoError(t, err)
+	require.NotNil(t, hm)
+	require.Contains(t, string(bm), "service unavailable")
+}
+
+type mockTranscriptionSpan struct {
+	recordedResponse *openai.TranscriptionResponse
+	recordedChunks   []*openai.TranscriptionStreamEvent
+}
+
+func (m *mockTranscriptionSpan) RecordResponse(resp *openai.TranscriptionResponse) {
+	m.recordedResponse = resp
+}
+
+func (m *mockTranscriptionSpan) RecordResponseChunk(chunk *openai.TranscriptionStreamEvent) {
+	m.recordedChunks = append(m.recordedChunks, chunk)
+}
+func (m *mockTranscriptionSpan) EndSpanOnError(int, []byte) {}
+func (m *mockTranscriptionSpan) EndSpan()                   {}
@@ -12,23 +12,28 @@ import (
 	"github.com/tidwall/sjson"
 
 	anthropicschema "github.com/envoyproxy/ai-gateway/internal/apischema/anthropic"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/awsbedrock"
 	cohereschema "github.com/envoyproxy/ai-gateway/internal/apischema/cohere"
+	"github.com/envoyproxy/ai-gateway/internal/apischema/gcp"
 	"github.com/envoyproxy/ai-gateway/internal/apischema/openai"
+	"github.com/envoyproxy/ai-gateway/internal/filterapi"
 	"github.com/envoyproxy/ai-gateway/internal/internalapi"
 	"github.com/envoyproxy/ai-gateway/internal/metrics"
 	"github.com/envoyproxy/ai-gateway/internal/tracing/tracingapi"
 )
 
 const (
-	pathHeaderName          = ":path"
-	statusHeaderName        = ":status"
-	contentTypeHeaderName   = "content-type"
-	contentLengthHeaderName = "content-length"
-	awsErrorTypeHeaderName  = "x-amzn-errortype"
-	jsonContentType         = "application/json"
-	eventStreamContentType  = "text/event-stream"
-	openAIBackendError      = "OpenAIBackendError"
-	awsBedrockBackendError  = "AWSBedrockBackendError"
+	pathHeaderName            = ":path"
+	statusHeaderName          = ":status"
+	contentTypeHeaderName     = "content-type"
+	contentLengthHeaderName   = "content-length"
+	awsErrorTypeHeaderName    = "x-amzn-errortype"
+	jsonContentType           = "application/json"
+	eventStreamContentType    = "text/event-stream"
+	awsEventStreamContentType = "application/vnd.amazon.eventstream"
+	openAIBackendError        = "OpenAIBackendError"
+	awsBedrockBackendError    = "AWSBedrockBackendError"
+	cohereBackendError        = "CohereBackendError"
 )
 
 // Translator translates the request and response messages between the client
@@ -97,6 +102,30 @@ type AnthropicResponseRedactor interface {
 	RedactAnthropicBody(resp *anthropicschema.MessagesResponse) *anthropicschema.MessagesResponse
 }
 
+// LocalResponder is an optional interface that translators can implement to answer the request in the gateway
+// instead of forwarding it to the backend, e.g. the token counting for the backends without a token counting API.
+type LocalResponder interface {
+	// LocalResponse returns the response body to send to the client with the 200 status, and true when the
+	// request given to [Translator.RequestBody] is answered locally.
+	LocalResponse() (body []byte, ok bool)
+}
+
+// UsageDeduplicator is an optional interface that the translators implement when the same token usage is reported
+// by several responses, e.g. every retrieval of a completed batch. The usage is only recorded by the first response
+// reporting its key across all the external processors sharing the quota store.
+type UsageDeduplicator interface {
+	// UsageKey returns the key identifying the token usage reported by the last [Translator.ResponseBody],
+	// or an empty string if the usage is to be recorded as is.
+	UsageKey() string
+}
+
+// BatchConfigurer is an optional interface that the batch translators implement when the batch jobs of the provider
+// read the input from and write the output to the storage of the provider, whose locations are configured on the backend.
+type BatchConfigurer interface {
+	// SetBatchConfig sets the batch configuration of the backend, which is nil if the backend has none.
+	SetBatchConfig(config *filterapi.BackendBatch)
+}
+
 type (
 	// OpenAIChatCompletionTranslator translates the OpenAI's /chat/completions endpoint.
 	OpenAIChatCompletionTranslator = Translator[openai.ChatCompletionRequest, tracingapi.ChatCompletionSpan]
@@ -114,6 +143,20 @@ type (
 	OpenAIResponsesTranslator = Translator[openai.ResponseRequest, tracingapi.ResponsesSpan]
 	// OpenAISpeechTranslator translates the OpenAI's /v1/audio/speech endpoint.
 	OpenAISpeechTranslator = Translator[openai.SpeechRequest, tracingapi.SpeechSpan]
+	// OpenAITranscriptionTranslator translates the OpenAI's /v1/audio/transcriptions and /v1/audio/translations endpoints.
+	OpenAITranscriptionTranslator = Translator[openai.TranscriptionRequest, tracingapi.TranscriptionSpan]
+	// GeminiGenerateContentTranslator translates the Gemini's generateContent and streamGenerateContent endpoints.
+	GeminiGenerateContentTranslator = Translator[gcp.GenerateContentRequest, tracingapi.GenerateContentSpan]
+	// AWSBedrockConverseTranslator translates the AWS Bedrock's /model/{modelId}/converse and /model/{modelId}/converse-stream endpoints.
+	AWSBedrockConverseTranslator = Translator[awsbedrock.ConverseInput, tracingapi.ConverseSpan]
+	// OpenAIFilesTranslator translates the OpenAI's /v1/files endpoints.
+	OpenAIFilesTranslator = Translator[openai.FileRequest, tracingapi.FilesSpan]
+	// OpenAIBatchesTranslator translates the OpenAI's /v1/batches endpoints.
+	OpenAIBatchesTranslator = Translator[openai.BatchRequest, tracingapi.BatchesSpan]
+	// OpenAIModerationTranslator translates the OpenAI's /v1/moderations endpoint.
+	OpenAIModerationTranslator = Translator[openai.ModerationRequest, tracingapi.ModerationsSpan]
+	// AnthropicCountTokensTranslator translates the Anthropic's /v1/messages/count_tokens endpoint.
+	AnthropicCountTokensTranslator = Translator[anthropicschema.MessagesRequest, tracingapi.CountTokensSpan]
 )
 
 var (
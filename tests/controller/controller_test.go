@@ -757,7 +757,8 @@ func TestSecretController(t *testing.T) {
 
 	bspCh := internaltesting.NewControllerEventChan[*aigv1b1.BackendSecurityPolicy]()
 	mcpRouteCh := internaltesting.NewControllerEventChan[*aigv1b1.MCPRoute]()
-	sc := controller.NewSecretController(mgr.GetClient(), k, defaultLogger(), bspCh.Ch, mcpRouteCh.Ch)
+	gatewayConfigCh := internaltesting.NewControllerEventChan[*aigv1b1.GatewayConfig]()
+	sc := controller.NewSecretController(mgr.GetClient(), k, defaultLogger(), bspCh.Ch, mcpRouteCh.Ch, gatewayConfigCh.Ch)
 	const secretName, secretNamespace = "mysecret", "default"
 
 	err = ctrl.NewControllerManagedBy(mgr).For(&corev1.Secret{}).Complete(sc)
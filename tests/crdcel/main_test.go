@@ -57,6 +57,16 @@ func TestAIGatewayRoutes(t *testing.T) {
 			name:   "too_many_rules.yaml",
 			expErr: "spec.rules: Too many: 16: must have at most 15 items",
 		},
+		{name: "pii_policy.yaml"},
+		{
+			name:   "pii_policy_nothing_to_detect.yaml",
+			expErr: "spec.piiPolicy: Invalid value: \"object\": at least one entity or custom pattern is required",
+		},
+		{name: "guardrail.yaml"},
+		{
+			name:   "guardrail_webhook_and_llm.yaml",
+			expErr: "spec.guardrail: Invalid value: \"object\": exactly one of webhook or llm must be set",
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			data, err := testdata.ReadFile(path.Join("testdata/aigatewayroutes", tc.name))
@@ -184,6 +194,7 @@ func TestBackendSecurityPolicies(t *testing.T) {
 		{name: "aws_oidc.yaml"},
 		{name: "gcp_oidc.yaml"},
 		{name: "anthropic-apikey.yaml"},
+		{name: "google-apikey.yaml"},
 		{name: "targetrefs_basic.yaml"},
 		{name: "targetrefs_multiple.yaml"},
 		{name: "targetrefs_inferencepool.yaml"},